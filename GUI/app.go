@@ -15,6 +15,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"sync"
@@ -22,6 +23,7 @@ import (
 	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 
 	"stt/internal/appcore"
+	"stt/internal/applog"
 	"stt/internal/config"
 )
 
@@ -57,6 +59,7 @@ type App struct {
 	minimal         bool
 	rounded         bool
 	trayMinimalSync func(bool)
+	stopLog         func()
 }
 
 func NewApp() *App {
@@ -73,12 +76,23 @@ func (a *App) startup(ctx context.Context) {
 		return
 	}
 	a.configPath = path
+	// The cache, log and history live beside the config unless CACHE_DIR
+	// says otherwise, never in the directory the GUI was started from.
+	config.SetDataDir(filepath.Dir(path))
 
 	cfg, err := ensureConfig(path)
 	if err != nil {
 		a.emitError("Config load failed", err)
 		return
 	}
+	if err := config.LoadDotEnv(config.DefaultEnvFile()); err != nil && !os.IsNotExist(err) {
+		a.emitError("Env file load failed", err)
+		return
+	}
+	if err := config.ApplyEnv(&cfg); err != nil {
+		a.emitError("Env override failed", err)
+		return
+	}
 
 	rt, err := appcore.NewRuntime(cfg)
 	if err != nil {
@@ -92,9 +106,22 @@ func (a *App) startup(ctx context.Context) {
 	})
 	a.runtime = rt
 
+	runCfg := rt.Config()
+	if logPath := config.LogPath(&runCfg); logPath != "" {
+		if stop, err := applog.Start(logPath, config.LogRotation(&runCfg)); err != nil {
+			log.Printf("log file open failed: %v", err)
+		} else {
+			a.stopLog = stop
+		}
+	}
+
 	if err := rt.StartHotkeys(); err != nil {
 		a.emitError("Hotkey registration failed", err)
 	}
+	go rt.CheckFFmpeg(ctx)
+	if cfg.StartupCheck {
+		go rt.CheckEndpoint(ctx)
+	}
 	go startTray(a)
 	wailsruntime.EventsEmit(ctx, "runtime:state", rt.Snapshot())
 	wailsruntime.EventsEmit(ctx, "window:minimal", a.GetWindowState())
@@ -110,6 +137,9 @@ func (a *App) shutdown(ctx context.Context) {
 		rt.Stop()
 	}
 	stopTray()
+	if a.stopLog != nil {
+		a.stopLog()
+	}
 }
 
 // GetState returns the current runtime state.
@@ -185,6 +215,11 @@ func (a *App) Cancel() appcore.Event {
 	return rt.Snapshot()
 }
 
+// InputDevices lists the microphones INPUT_DEVICE can select.
+func (a *App) InputDevices() ([]string, error) {
+	return appcore.InputDevices()
+}
+
 // LoadConfig reads the editable ASR config JSON.
 func (a *App) LoadConfig() (ConfigPayload, error) {
 	if a.configPath == "" {
@@ -218,7 +253,13 @@ func (a *App) SaveConfig(raw string) (appcore.Event, error) {
 	if err := config.Validate(&cfg); err != nil {
 		return rt.Snapshot(), err
 	}
-	out, err := json.MarshalIndent(cfg, "", "  ")
+	onDisk := cfg
+	if config.HasEncryptedSecrets(a.configPath) {
+		if err := config.EncryptSecrets(&onDisk); err != nil {
+			return rt.Snapshot(), err
+		}
+	}
+	out, err := json.MarshalIndent(onDisk, "", "  ")
 	if err != nil {
 		return rt.Snapshot(), err
 	}
@@ -228,6 +269,9 @@ func (a *App) SaveConfig(raw string) (appcore.Event, error) {
 	if err := os.WriteFile(a.configPath, out, 0600); err != nil {
 		return rt.Snapshot(), err
 	}
+	if err := config.ApplyEnv(&cfg); err != nil {
+		return rt.Snapshot(), err
+	}
 	if err := rt.Reload(cfg); err != nil {
 		return rt.Snapshot(), err
 	}
@@ -315,11 +359,11 @@ func (a *App) quit() {
 }
 
 func defaultConfigPath() (string, error) {
-	base, err := os.UserConfigDir()
-	if err != nil {
-		return "", err
+	dir := config.UserDir()
+	if dir == "" {
+		return "", fmt.Errorf("no per-user config directory")
 	}
-	return filepath.Join(base, "stt", "config.json"), nil
+	return filepath.Join(dir, "config.json"), nil
 }
 
 func ensureConfig(path string) (config.Config, error) {
@@ -340,6 +384,9 @@ func ensureConfig(path string) (config.Config, error) {
 	if err := os.WriteFile(path, raw, 0600); err != nil {
 		return config.Config{}, err
 	}
+	if err := config.SaveTemplate(config.TemplatePath(path)); err != nil {
+		log.Printf("config template: %v", err)
+	}
 	return cfg, nil
 }
 
@@ -15,6 +15,7 @@ import {
   GetState,
   GetUIStyle,
   GetWindowState,
+  InputDevices,
   LoadConfig,
   RequestQuit,
   SaveConfig,
@@ -63,6 +64,7 @@ const translations = {
     quit: "Quit",
     currentState: "Current state: {state}.",
     additionalConfig: "Additional config",
+    systemDefaultDevice: "System default",
     tabs: {
       Display: "Display",
       API: "API",
@@ -80,28 +82,88 @@ const translations = {
       TOKEN: "Token",
       MODEL: "Model",
       LANGUAGE: "Language",
+      PROVIDER: "Provider",
+      PROFILE: "Profile name",
+      MACHINE_ID: "Machine ID",
       PROMPT: "Prompt",
       TEXT_PATH: "Text path",
+      POST_COMMAND: "Post-transcription command",
       ExtraConfig: "Extra config",
       CHANNELS: "Channels",
+      INPUT_DEVICE: "Input device",
+      SOURCE: "Source (microphone/loopback)",
+      MAX_RECORD_SECONDS: "Max recording length (s)",
+      RECORD_SEGMENT_SECONDS: "Segment length (s)",
+      LOCK_ACTION: "On lock (none/pause/stop/cancel)",
       SAMPLING_RATE: "Sampling rate",
       SAMPLING_RATE_DEPTH: "Sample depth",
       BIT_RATE: "Bit rate",
+      AUDIO_TRACK: "Audio track",
       CODECS: "Codec",
       CONTAINER: "Container",
       REQUEST_TIMEOUT: "Request timeout",
       MAX_RETRY: "Max retry",
       RETRY_BASE_DELAY: "Retry delay",
+      MAX_CONCURRENT_UPLOADS: "Max concurrent uploads",
+      REQUESTS_PER_MINUTE: "Requests per minute",
+      REQUESTS_BURST: "Request burst",
       ENABLE_HTTP2: "HTTP/2",
       VERIFY_SSL: "Verify SSL",
+      CA_CERT_FILE: "CA certificate file",
+      STARTUP_CHECK: "Startup endpoint check",
+      UPDATE_CHECK: "Check for updates at startup",
+      WATCHDOG: "Restart automatically after a crash",
+      ONBOARDING: "First-run guide",
+      HTTP_API: "Local HTTP API address",
+      HTTP_API_TOKEN: "Local HTTP API token",
+      GRPC_API: "Local gRPC API address",
+      GRPC_API_TOKEN: "Local gRPC API token",
       START_KEY: "Start key",
       PAUSE_KEY: "Pause key",
       CANCEL_KEY: "Cancel key",
+      MIDI_INPUT: "MIDI input device",
+      MIDI_MAP: "MIDI trigger map",
+      SCHEDULE: "Scheduled transcription jobs",
+      CONVERT_WORKERS: "Parallel conversions",
+      CLIPBOARD_WATCH: "Offer to transcribe copied audio files",
       HOTKEY_HOOK: "Low-level hook",
+      PTT: "Push-to-talk (hold the start hotkey)",
       CACHE_DIR: "Cache dir",
       KEEP_CACHE: "Keep cache",
+      KEEP_WAV: "Keep WAV recordings",
+      KEEP_CONVERTED: "Keep converted audio",
+      CACHE_LAYOUT: "Cache folder layout",
+      CACHE_NAME: "Cache file name template",
+      CACHE_MAX_AGE_DAYS: "Keep cache for (days)",
+      CACHE_MAX_SIZE_MB: "Max cache size (MB)",
+      CACHE_PURGE_INTERVAL: "Cache purge interval (min)",
+      CACHE_ARCHIVE_DAYS: "Archive cache after (days)",
+      HISTORY: "History database",
+      CACHE_ENCRYPTION: "Cache encryption (dpapi/passphrase)",
+      CACHE_PASSPHRASE: "Cache passphrase",
+      RETRY_QUEUE: "Retry failed uploads",
+      QUEUE_RETRY_INTERVAL: "Queue retry interval (s)",
+      OFFLINE_FIRST: "Offline-first recording",
       NOTIFICATION: "Notification",
       REQUEST_FAILED_NOTIFICATION: "Request failed placeholder",
+      PROGRESS_NOTIFICATION: "Progress notification for long files",
+      TIMING_NOTIFICATION: "Show step timings in the paste notification",
+      QUIET_MODE: "Quiet during full-screen and presentations",
+      LOG_FILE: "Log file",
+      LOG_MAX_SIZE_MB: "Log max size (MB)",
+      LOG_ROTATE_DAYS: "Log rotation (days)",
+      LOG_MAX_BACKUPS: "Log backups kept",
+      TRAY: "Tray icon (CLI record mode)",
+      TRAY_THEME: "Tray icon theme (auto/light/dark)",
+      VU_METER: "Level meter while recording",
+      RECORDING_TIMER: "Recording timer notification",
+      SOUND_MUTE: "Mute sound cues",
+      SOUND_START: "Sound: recording started",
+      SOUND_STOP: "Sound: recording stopped",
+      SOUND_PASTE_SUCCESS: "Sound: text pasted",
+      SOUND_UPLOAD_FAILED: "Sound: upload failed",
+      SOUND_ERROR: "Sound: other failures",
+      UI_LANG: "Notification language (zh/en)",
       FFMPEG_DEBUG: "FFmpeg debug",
       RECORD_DEBUG: "Record debug",
       HOTKEY_DEBUG: "Hotkey debug",
@@ -111,6 +173,7 @@ const translations = {
       TOKEN: "Bearer Authentication Token. Other authentication types are not supported yet.",
       PROMPT: "Maps to the request field named prompt. If an API uses another name, configure it in Extra config.",
       TEXT_PATH: "Dot-separated JSON path used to read the transcription text from the API response. Example: results[0].alternatives[0].transcript. Use text for OpenAI-compatible APIs.",
+      POST_COMMAND: "转写后运行的命令",
       ExtraConfig: "Additional JSON request fields to send with the API request. Example: {\"enable_lid\":true,\"enable_itn\":true}. These fields are merged into the root request fields. Setting a built-in field to null, such as {\"prompt\":null}, removes it. Removable built-in fields: model, language, prompt.",
       NOTIFICATION: "Windows system notification. Not recommended."
     },
@@ -138,6 +201,7 @@ const translations = {
     quit: "退出",
     currentState: "当前状态：{state}。",
     additionalConfig: "其他配置",
+    systemDefaultDevice: "系统默认",
     tabs: {
       Display: "显示",
       API: "API",
@@ -155,28 +219,88 @@ const translations = {
       TOKEN: "令牌",
       MODEL: "模型",
       LANGUAGE: "语言",
+      PROVIDER: "服务商",
+      PROFILE: "配置名称",
+      MACHINE_ID: "本机标识",
       PROMPT: "提示词",
       TEXT_PATH: "文本路径",
+      POST_COMMAND: "Befehl nach der Transkription",
       ExtraConfig: "额外配置",
       CHANNELS: "声道数",
+      INPUT_DEVICE: "录音设备",
+      SOURCE: "录音来源（microphone/loopback）",
+      MAX_RECORD_SECONDS: "最长录音时长（秒）",
+      RECORD_SEGMENT_SECONDS: "分段时长（秒）",
+      LOCK_ACTION: "锁屏时（none/pause/stop/cancel）",
       SAMPLING_RATE: "采样率",
       SAMPLING_RATE_DEPTH: "采样位深",
       BIT_RATE: "比特率",
+      AUDIO_TRACK: "音轨",
       CODECS: "编码",
       CONTAINER: "容器",
       REQUEST_TIMEOUT: "请求超时",
       MAX_RETRY: "最大重试次数",
       RETRY_BASE_DELAY: "重试延迟",
+      MAX_CONCURRENT_UPLOADS: "最大同时上传数",
+      REQUESTS_PER_MINUTE: "每分钟请求数",
+      REQUESTS_BURST: "请求突发数",
       ENABLE_HTTP2: "HTTP/2",
       VERIFY_SSL: "验证 SSL",
+      CA_CERT_FILE: "CA 证书文件",
+      STARTUP_CHECK: "启动时检查端点",
+      UPDATE_CHECK: "启动时检查更新",
+      WATCHDOG: "崩溃后自动重启",
+      ONBOARDING: "首次启动引导",
+      HTTP_API: "本地 HTTP 接口地址",
+      HTTP_API_TOKEN: "本地 HTTP 接口令牌",
+      GRPC_API: "本地 gRPC 接口地址",
+      GRPC_API_TOKEN: "本地 gRPC 接口令牌",
       START_KEY: "开始快捷键",
       PAUSE_KEY: "暂停快捷键",
       CANCEL_KEY: "取消快捷键",
+      MIDI_INPUT: "MIDI 输入设备",
+      MIDI_MAP: "MIDI 触发映射",
+      SCHEDULE: "定时转写任务",
+      CONVERT_WORKERS: "并行转码数",
+      CLIPBOARD_WATCH: "转写剪贴板中复制的音频",
       HOTKEY_HOOK: "低级键盘钩子",
+      PTT: "按住说话（按住开始热键录音）",
       CACHE_DIR: "缓存目录",
       KEEP_CACHE: "保留缓存",
+      KEEP_WAV: "保留原始 WAV",
+      KEEP_CONVERTED: "保留转码后的音频",
+      CACHE_LAYOUT: "缓存子目录布局",
+      CACHE_NAME: "缓存文件命名模板",
+      CACHE_MAX_AGE_DAYS: "缓存保留天数",
+      CACHE_MAX_SIZE_MB: "缓存大小上限（MB）",
+      CACHE_PURGE_INTERVAL: "缓存清理间隔（分钟）",
+      CACHE_ARCHIVE_DAYS: "缓存归档天数",
+      HISTORY: "历史记录数据库",
+      CACHE_ENCRYPTION: "缓存加密（dpapi/passphrase）",
+      CACHE_PASSPHRASE: "缓存加密口令",
+      RETRY_QUEUE: "失败上传重试队列",
+      QUEUE_RETRY_INTERVAL: "队列重试间隔（秒）",
+      OFFLINE_FIRST: "离线优先录音",
       NOTIFICATION: "通知",
       REQUEST_FAILED_NOTIFICATION: "请求失败占位提示",
+      PROGRESS_NOTIFICATION: "长文件进度通知",
+      TIMING_NOTIFICATION: "粘贴通知显示各步骤耗时",
+      QUIET_MODE: "全屏/演示时静默",
+      LOG_FILE: "日志文件",
+      LOG_MAX_SIZE_MB: "日志大小上限（MB）",
+      LOG_ROTATE_DAYS: "日志轮转天数",
+      LOG_MAX_BACKUPS: "保留旧日志数",
+      TRAY: "托盘图标（命令行录音模式）",
+      TRAY_THEME: "托盘图标配色（auto/light/dark）",
+      VU_METER: "录音时显示电平窗口",
+      RECORDING_TIMER: "录音计时通知",
+      SOUND_MUTE: "关闭提示音",
+      SOUND_START: "提示音：开始录音",
+      SOUND_STOP: "提示音：停止录音",
+      SOUND_PASTE_SUCCESS: "提示音：粘贴成功",
+      SOUND_UPLOAD_FAILED: "提示音：上传失败",
+      SOUND_ERROR: "提示音：其他失败",
+      UI_LANG: "通知语言 (zh/en)",
       FFMPEG_DEBUG: "FFmpeg 调试",
       RECORD_DEBUG: "录音调试",
       HOTKEY_DEBUG: "快捷键调试",
@@ -186,6 +310,7 @@ const translations = {
       TOKEN: "Bearer Authentication Token。暂不支持其他验证类型。",
       PROMPT: "对应请求字段名 prompt。如果某些 API 使用其他字段名，请在额外配置中配置。",
       TEXT_PATH: "用于从 API 响应 JSON 中读取转写文本的点分路径。示例：results[0].alternatives[0].transcript。OpenAI 兼容接口使用 text 即可。",
+      POST_COMMAND: "文字起こし後に実行するコマンド",
       ExtraConfig: "随 API 请求一起发送的额外 JSON 请求字段配置。示例：{\"enable_lid\":true,\"enable_itn\":true}。这里的字段会合并进根请求字段。将内置字段设为 null，例如 {\"prompt\":null}，等于删除该字段。支持删除的内置字段：model、language、prompt。",
       NOTIFICATION: "Windows 系统通知。不建议开启。"
     },
@@ -213,6 +338,7 @@ const translations = {
     quit: "Beenden",
     currentState: "Aktueller Status: {state}.",
     additionalConfig: "Zusätzliche Konfiguration",
+    systemDefaultDevice: "Systemstandard",
     tabs: {
       Display: "Anzeige",
       API: "API",
@@ -230,28 +356,88 @@ const translations = {
       TOKEN: "Token",
       MODEL: "Modell",
       LANGUAGE: "Sprache",
+      PROVIDER: "Anbieter",
+      PROFILE: "Profilname",
+      MACHINE_ID: "Rechner-ID",
       PROMPT: "Prompt",
       TEXT_PATH: "Textpfad",
+      POST_COMMAND: "Commande après transcription",
       ExtraConfig: "Zusatzkonfiguration",
       CHANNELS: "Kanäle",
+      INPUT_DEVICE: "Eingabegerät",
+      SOURCE: "Quelle (microphone/loopback)",
+      MAX_RECORD_SECONDS: "Maximale Aufnahmedauer (s)",
+      RECORD_SEGMENT_SECONDS: "Segmentlänge (s)",
+      LOCK_ACTION: "Beim Sperren (none/pause/stop/cancel)",
       SAMPLING_RATE: "Abtastrate",
       SAMPLING_RATE_DEPTH: "Abtasttiefe",
       BIT_RATE: "Bitrate",
+      AUDIO_TRACK: "Audiospur",
       CODECS: "Codec",
       CONTAINER: "Container",
       REQUEST_TIMEOUT: "Anfrage-Timeout",
       MAX_RETRY: "Max. Wiederholungen",
       RETRY_BASE_DELAY: "Wiederholungsverzögerung",
+      MAX_CONCURRENT_UPLOADS: "Max. gleichzeitige Uploads",
+      REQUESTS_PER_MINUTE: "Anfragen pro Minute",
+      REQUESTS_BURST: "Anfrage-Burst",
       ENABLE_HTTP2: "HTTP/2",
       VERIFY_SSL: "SSL prüfen",
+      CA_CERT_FILE: "CA-Zertifikatsdatei",
+      STARTUP_CHECK: "Endpunkt beim Start prüfen",
+      UPDATE_CHECK: "Beim Start nach Updates suchen",
+      WATCHDOG: "Nach Absturz automatisch neu starten",
+      ONBOARDING: "Einführung beim ersten Start",
+      HTTP_API: "Lokale HTTP-API-Adresse",
+      HTTP_API_TOKEN: "Token der lokalen HTTP-API",
+      GRPC_API: "Lokale gRPC-API-Adresse",
+      GRPC_API_TOKEN: "Token der lokalen gRPC-API",
       START_KEY: "Starttaste",
       PAUSE_KEY: "Pausentaste",
       CANCEL_KEY: "Abbruchtaste",
+      MIDI_INPUT: "MIDI-Eingabegerät",
+      MIDI_MAP: "MIDI-Zuordnung",
+      SCHEDULE: "Geplante Transkriptionen",
+      CONVERT_WORKERS: "Parallele Konvertierungen",
+      CLIPBOARD_WATCH: "Kopierte Audiodateien zum Transkribieren anbieten",
       HOTKEY_HOOK: "Low-Level-Hook",
+      PTT: "Push-to-Talk (Starttaste gedrückt halten)",
       CACHE_DIR: "Cache-Verzeichnis",
       KEEP_CACHE: "Cache behalten",
+      KEEP_WAV: "WAV-Aufnahmen behalten",
+      KEEP_CONVERTED: "Konvertiertes Audio behalten",
+      CACHE_LAYOUT: "Cache-Ordnerstruktur",
+      CACHE_NAME: "Vorlage für Cache-Dateinamen",
+      CACHE_MAX_AGE_DAYS: "Cache aufbewahren (Tage)",
+      CACHE_MAX_SIZE_MB: "Maximale Cache-Größe (MB)",
+      CACHE_PURGE_INTERVAL: "Bereinigungsintervall (min)",
+      CACHE_ARCHIVE_DAYS: "Cache archivieren nach (Tagen)",
+      HISTORY: "Verlaufsdatenbank",
+      CACHE_ENCRYPTION: "Cache-Verschlüsselung (dpapi/passphrase)",
+      CACHE_PASSPHRASE: "Cache-Passphrase",
+      RETRY_QUEUE: "Fehlgeschlagene Uploads wiederholen",
+      QUEUE_RETRY_INTERVAL: "Wiederholungsintervall (s)",
+      OFFLINE_FIRST: "Offline-first-Aufnahme",
       NOTIFICATION: "Benachrichtigung",
       REQUEST_FAILED_NOTIFICATION: "Platzhalter bei Anfragefehler",
+      PROGRESS_NOTIFICATION: "Fortschrittsbenachrichtigung für lange Dateien",
+      TIMING_NOTIFICATION: "Schrittdauer in der Einfüge-Benachrichtigung",
+      QUIET_MODE: "Ruhig bei Vollbild und Präsentationen",
+      LOG_FILE: "Logdatei",
+      LOG_MAX_SIZE_MB: "Max. Loggröße (MB)",
+      LOG_ROTATE_DAYS: "Log-Rotation (Tage)",
+      LOG_MAX_BACKUPS: "Aufbewahrte alte Logs",
+      TRAY: "Tray-Symbol (CLI-Aufnahmemodus)",
+      TRAY_THEME: "Tray-Symbol-Design (auto/light/dark)",
+      VU_METER: "Pegelanzeige während der Aufnahme",
+      RECORDING_TIMER: "Benachrichtigung mit Aufnahmezeit",
+      SOUND_MUTE: "Hinweistöne stummschalten",
+      SOUND_START: "Ton: Aufnahme gestartet",
+      SOUND_STOP: "Ton: Aufnahme beendet",
+      SOUND_PASTE_SUCCESS: "Ton: Text eingefügt",
+      SOUND_UPLOAD_FAILED: "Ton: Upload fehlgeschlagen",
+      SOUND_ERROR: "Ton: sonstige Fehler",
+      UI_LANG: "Benachrichtigungssprache (zh/en)",
       FFMPEG_DEBUG: "FFmpeg-Debug",
       RECORD_DEBUG: "Aufnahme-Debug",
       HOTKEY_DEBUG: "Hotkey-Debug",
@@ -288,6 +474,7 @@ const translations = {
     quit: "終了",
     currentState: "現在の状態: {state}。",
     additionalConfig: "追加設定",
+    systemDefaultDevice: "システムの既定",
     tabs: {
       Display: "表示",
       API: "API",
@@ -305,28 +492,87 @@ const translations = {
       TOKEN: "トークン",
       MODEL: "モデル",
       LANGUAGE: "言語",
+      PROVIDER: "プロバイダー",
+      PROFILE: "プロファイル名",
+      MACHINE_ID: "マシン ID",
       PROMPT: "プロンプト",
       TEXT_PATH: "テキストパス",
       ExtraConfig: "追加設定",
       CHANNELS: "チャンネル",
+      INPUT_DEVICE: "入力デバイス",
+      SOURCE: "録音ソース（microphone/loopback）",
+      MAX_RECORD_SECONDS: "最大録音時間（秒）",
+      RECORD_SEGMENT_SECONDS: "セグメント長（秒）",
+      LOCK_ACTION: "ロック時（none/pause/stop/cancel）",
       SAMPLING_RATE: "サンプリングレート",
       SAMPLING_RATE_DEPTH: "サンプル深度",
       BIT_RATE: "ビットレート",
+      AUDIO_TRACK: "音声トラック",
       CODECS: "コーデック",
       CONTAINER: "コンテナ",
       REQUEST_TIMEOUT: "リクエストタイムアウト",
       MAX_RETRY: "最大リトライ回数",
       RETRY_BASE_DELAY: "リトライ間隔",
+      MAX_CONCURRENT_UPLOADS: "最大同時アップロード数",
+      REQUESTS_PER_MINUTE: "1 分あたりのリクエスト数",
+      REQUESTS_BURST: "リクエストのバースト数",
       ENABLE_HTTP2: "HTTP/2",
       VERIFY_SSL: "SSL を検証",
+      CA_CERT_FILE: "CA 証明書ファイル",
+      STARTUP_CHECK: "起動時にエンドポイントを確認",
+      UPDATE_CHECK: "起動時に更新を確認",
+      WATCHDOG: "クラッシュ時に自動再起動",
+      ONBOARDING: "初回起動ガイド",
+      HTTP_API: "ローカル HTTP API アドレス",
+      HTTP_API_TOKEN: "ローカル HTTP API トークン",
+      GRPC_API: "ローカル gRPC API アドレス",
+      GRPC_API_TOKEN: "ローカル gRPC API トークン",
       START_KEY: "開始キー",
       PAUSE_KEY: "一時停止キー",
       CANCEL_KEY: "キャンセルキー",
+      MIDI_INPUT: "MIDI 入力デバイス",
+      MIDI_MAP: "MIDI トリガー割り当て",
+      SCHEDULE: "定期文字起こしジョブ",
+      CONVERT_WORKERS: "並列変換数",
+      CLIPBOARD_WATCH: "コピーした音声ファイルの文字起こしを提案",
       HOTKEY_HOOK: "低レベルフック",
+      PTT: "プッシュ・トゥ・トーク（開始キーを押している間録音）",
       CACHE_DIR: "キャッシュディレクトリ",
       KEEP_CACHE: "キャッシュを保持",
+      KEEP_WAV: "WAV 録音を保持",
+      KEEP_CONVERTED: "変換後の音声を保持",
+      CACHE_LAYOUT: "キャッシュのフォルダー構成",
+      CACHE_NAME: "キャッシュのファイル名テンプレート",
+      CACHE_MAX_AGE_DAYS: "キャッシュ保持日数",
+      CACHE_MAX_SIZE_MB: "キャッシュ上限（MB）",
+      CACHE_PURGE_INTERVAL: "キャッシュ削除間隔（分）",
+      CACHE_ARCHIVE_DAYS: "キャッシュをアーカイブ（日）",
+      HISTORY: "履歴データベース",
+      CACHE_ENCRYPTION: "キャッシュ暗号化（dpapi/passphrase）",
+      CACHE_PASSPHRASE: "キャッシュのパスフレーズ",
+      RETRY_QUEUE: "失敗したアップロードを再試行",
+      QUEUE_RETRY_INTERVAL: "再試行間隔（秒）",
+      OFFLINE_FIRST: "オフライン優先録音",
       NOTIFICATION: "通知",
       REQUEST_FAILED_NOTIFICATION: "リクエスト失敗プレースホルダー",
+      PROGRESS_NOTIFICATION: "長いファイルの進捗通知",
+      TIMING_NOTIFICATION: "貼り付け通知に各ステップの所要時間を表示",
+      QUIET_MODE: "全画面・プレゼン中は通知しない",
+      LOG_FILE: "ログファイル",
+      LOG_MAX_SIZE_MB: "ログの最大サイズ（MB）",
+      LOG_ROTATE_DAYS: "ログのローテーション（日）",
+      LOG_MAX_BACKUPS: "保持する旧ログ数",
+      TRAY: "トレイアイコン（CLI 録音モード）",
+      TRAY_THEME: "トレイアイコンの配色（auto/light/dark）",
+      VU_METER: "録音中にレベルメーターを表示",
+      RECORDING_TIMER: "録音タイマー通知",
+      SOUND_MUTE: "効果音をミュート",
+      SOUND_START: "効果音：録音開始",
+      SOUND_STOP: "効果音：録音停止",
+      SOUND_PASTE_SUCCESS: "効果音：貼り付け成功",
+      SOUND_UPLOAD_FAILED: "効果音：アップロード失敗",
+      SOUND_ERROR: "効果音：その他の失敗",
+      UI_LANG: "通知の言語 (zh/en)",
       FFMPEG_DEBUG: "FFmpeg デバッグ",
       RECORD_DEBUG: "録音デバッグ",
       HOTKEY_DEBUG: "ホットキーデバッグ",
@@ -363,6 +609,7 @@ const translations = {
     quit: "Quitter",
     currentState: "État actuel : {state}.",
     additionalConfig: "Configuration supplémentaire",
+    systemDefaultDevice: "Par défaut du système",
     tabs: {
       Display: "Affichage",
       API: "API",
@@ -380,28 +627,87 @@ const translations = {
       TOKEN: "Jeton",
       MODEL: "Modèle",
       LANGUAGE: "Langue",
+      PROVIDER: "Fournisseur",
+      PROFILE: "Nom du profil",
+      MACHINE_ID: "Identifiant de la machine",
       PROMPT: "Invite",
       TEXT_PATH: "Chemin du texte",
       ExtraConfig: "Configuration supplémentaire",
       CHANNELS: "Canaux",
+      INPUT_DEVICE: "Périphérique d'entrée",
+      SOURCE: "Source (microphone/loopback)",
+      MAX_RECORD_SECONDS: "Durée max. d'enregistrement (s)",
+      RECORD_SEGMENT_SECONDS: "Durée des segments (s)",
+      LOCK_ACTION: "Au verrouillage (none/pause/stop/cancel)",
       SAMPLING_RATE: "Fréquence d'échantillonnage",
       SAMPLING_RATE_DEPTH: "Profondeur d'échantillonnage",
       BIT_RATE: "Débit binaire",
+      AUDIO_TRACK: "Piste audio",
       CODECS: "Codec",
       CONTAINER: "Conteneur",
       REQUEST_TIMEOUT: "Délai de requête",
       MAX_RETRY: "Nombre max. de tentatives",
       RETRY_BASE_DELAY: "Délai de nouvelle tentative",
+      MAX_CONCURRENT_UPLOADS: "Envois simultanés max.",
+      REQUESTS_PER_MINUTE: "Requêtes par minute",
+      REQUESTS_BURST: "Rafale de requêtes",
       ENABLE_HTTP2: "HTTP/2",
       VERIFY_SSL: "Vérifier SSL",
+      CA_CERT_FILE: "Fichier de certificat CA",
+      STARTUP_CHECK: "Vérifier le point d'accès au démarrage",
+      UPDATE_CHECK: "Rechercher les mises à jour au démarrage",
+      WATCHDOG: "Redémarrer automatiquement après un plantage",
+      ONBOARDING: "Guide au premier démarrage",
+      HTTP_API: "Adresse de l'API HTTP locale",
+      HTTP_API_TOKEN: "Jeton de l'API HTTP locale",
+      GRPC_API: "Adresse de l'API gRPC locale",
+      GRPC_API_TOKEN: "Jeton de l'API gRPC locale",
       START_KEY: "Touche de démarrage",
       PAUSE_KEY: "Touche de pause",
       CANCEL_KEY: "Touche d'annulation",
+      MIDI_INPUT: "Périphérique d'entrée MIDI",
+      MIDI_MAP: "Correspondance MIDI",
+      SCHEDULE: "Transcriptions planifiées",
+      CONVERT_WORKERS: "Conversions parallèles",
+      CLIPBOARD_WATCH: "Proposer de transcrire les fichiers audio copiés",
       HOTKEY_HOOK: "Hook bas niveau",
+      PTT: "Appuyer pour parler (maintenir la touche de démarrage)",
       CACHE_DIR: "Dossier du cache",
       KEEP_CACHE: "Conserver le cache",
+      KEEP_WAV: "Conserver les WAV",
+      KEEP_CONVERTED: "Conserver l'audio converti",
+      CACHE_LAYOUT: "Organisation des dossiers du cache",
+      CACHE_NAME: "Modèle de nom des fichiers du cache",
+      CACHE_MAX_AGE_DAYS: "Conserver le cache (jours)",
+      CACHE_MAX_SIZE_MB: "Taille max. du cache (Mo)",
+      CACHE_PURGE_INTERVAL: "Intervalle de purge (min)",
+      CACHE_ARCHIVE_DAYS: "Archiver le cache après (jours)",
+      HISTORY: "Base d'historique",
+      CACHE_ENCRYPTION: "Chiffrement du cache (dpapi/passphrase)",
+      CACHE_PASSPHRASE: "Phrase secrète du cache",
+      RETRY_QUEUE: "Réessayer les envois échoués",
+      QUEUE_RETRY_INTERVAL: "Intervalle de réessai (s)",
+      OFFLINE_FIRST: "Enregistrement hors ligne d'abord",
       NOTIFICATION: "Notification",
       REQUEST_FAILED_NOTIFICATION: "Espace réservé en cas d'échec",
+      PROGRESS_NOTIFICATION: "Notification de progression (fichiers longs)",
+      TIMING_NOTIFICATION: "Durée des étapes dans la notification de collage",
+      QUIET_MODE: "Silencieux en plein écran et en présentation",
+      LOG_FILE: "Fichier journal",
+      LOG_MAX_SIZE_MB: "Taille max. du journal (Mo)",
+      LOG_ROTATE_DAYS: "Rotation du journal (jours)",
+      LOG_MAX_BACKUPS: "Anciens journaux conservés",
+      TRAY: "Icône de zone de notification (mode CLI)",
+      TRAY_THEME: "Thème de l'icône (auto/light/dark)",
+      VU_METER: "Vumètre pendant l'enregistrement",
+      RECORDING_TIMER: "Notification de durée d'enregistrement",
+      SOUND_MUTE: "Couper les sons",
+      SOUND_START: "Son : début d'enregistrement",
+      SOUND_STOP: "Son : fin d'enregistrement",
+      SOUND_PASTE_SUCCESS: "Son : texte collé",
+      SOUND_UPLOAD_FAILED: "Son : échec de l'envoi",
+      SOUND_ERROR: "Son : autres échecs",
+      UI_LANG: "Langue des notifications (zh/en)",
       FFMPEG_DEBUG: "Débogage FFmpeg",
       RECORD_DEBUG: "Débogage de l'enregistrement",
       HOTKEY_DEBUG: "Débogage des raccourcis",
@@ -430,27 +736,27 @@ const groups = [
   },
   {
     name: "API",
-    fields: ["API_ENDPOINT", "TOKEN", "MODEL", "LANGUAGE", "PROMPT", "TEXT_PATH", "ExtraConfig"]
+    fields: ["API_ENDPOINT", "TOKEN", "MODEL", "LANGUAGE", "PROVIDER", "PROFILE", "MACHINE_ID", "PROMPT", "TEXT_PATH", "POST_COMMAND", "ExtraConfig"]
   },
   {
     name: "Audio",
-    fields: ["CHANNELS", "SAMPLING_RATE", "SAMPLING_RATE_DEPTH", "BIT_RATE", "CODECS", "CONTAINER"]
+    fields: ["CHANNELS", "INPUT_DEVICE", "SOURCE", "MAX_RECORD_SECONDS", "RECORD_SEGMENT_SECONDS", "LOCK_ACTION", "SAMPLING_RATE", "SAMPLING_RATE_DEPTH", "BIT_RATE", "AUDIO_TRACK", "CODECS", "CONTAINER"]
   },
   {
     name: "Network",
-    fields: ["REQUEST_TIMEOUT", "MAX_RETRY", "RETRY_BASE_DELAY", "ENABLE_HTTP2", "VERIFY_SSL"]
+    fields: ["REQUEST_TIMEOUT", "MAX_RETRY", "RETRY_BASE_DELAY", "MAX_CONCURRENT_UPLOADS", "REQUESTS_PER_MINUTE", "REQUESTS_BURST", "ENABLE_HTTP2", "VERIFY_SSL", "CA_CERT_FILE", "STARTUP_CHECK", "UPDATE_CHECK", "WATCHDOG", "ONBOARDING", "HTTP_API", "HTTP_API_TOKEN", "GRPC_API", "GRPC_API_TOKEN"]
   },
   {
     name: "Hotkeys",
-    fields: ["START_KEY", "PAUSE_KEY", "CANCEL_KEY", "HOTKEY_HOOK"]
+    fields: ["START_KEY", "PAUSE_KEY", "CANCEL_KEY", "MIDI_INPUT", "MIDI_MAP", "SCHEDULE", "CONVERT_WORKERS", "CLIPBOARD_WATCH", "HOTKEY_HOOK", "PTT"]
   },
   {
     name: "Cache",
-    fields: ["CACHE_DIR", "KEEP_CACHE"]
+    fields: ["CACHE_DIR", "KEEP_CACHE", "KEEP_WAV", "KEEP_CONVERTED", "CACHE_LAYOUT", "CACHE_NAME", "CACHE_MAX_AGE_DAYS", "CACHE_MAX_SIZE_MB", "CACHE_PURGE_INTERVAL", "CACHE_ARCHIVE_DAYS", "HISTORY", "CACHE_ENCRYPTION", "CACHE_PASSPHRASE", "RETRY_QUEUE", "QUEUE_RETRY_INTERVAL", "OFFLINE_FIRST"]
   },
   {
     name: "Notifications",
-    fields: ["NOTIFICATION", "REQUEST_FAILED_NOTIFICATION"]
+    fields: ["NOTIFICATION", "REQUEST_FAILED_NOTIFICATION", "PROGRESS_NOTIFICATION", "TIMING_NOTIFICATION", "QUIET_MODE", "LOG_FILE", "LOG_MAX_SIZE_MB", "LOG_ROTATE_DAYS", "LOG_MAX_BACKUPS", "TRAY", "TRAY_THEME", "VU_METER", "RECORDING_TIMER", "SOUND_MUTE", "SOUND_START", "SOUND_STOP", "SOUND_PASTE_SUCCESS", "SOUND_UPLOAD_FAILED", "SOUND_ERROR", "UI_LANG"]
   },
   {
     name: "Debug",
@@ -477,28 +783,88 @@ const fieldMeta = {
   TOKEN: { type: "password" },
   MODEL: { type: "text" },
   LANGUAGE: { type: "text" },
+  PROVIDER: { type: "text" },
+  PROFILE: { type: "text" },
+  MACHINE_ID: { type: "text" },
   PROMPT: { type: "textarea" },
   TEXT_PATH: { type: "text" },
+  POST_COMMAND: { type: "text" },
   ExtraConfig: { type: "textarea" },
   CHANNELS: { type: "number" },
+  INPUT_DEVICE: { type: "device" },
+  SOURCE: { type: "text" },
+  MAX_RECORD_SECONDS: { type: "number" },
+  RECORD_SEGMENT_SECONDS: { type: "number" },
+  LOCK_ACTION: { type: "text" },
   SAMPLING_RATE: { type: "number" },
   SAMPLING_RATE_DEPTH: { type: "number" },
   BIT_RATE: { type: "number" },
+  AUDIO_TRACK: { type: "number" },
   CODECS: { type: "text" },
   CONTAINER: { type: "text" },
   REQUEST_TIMEOUT: { type: "number" },
   MAX_RETRY: { type: "number" },
   RETRY_BASE_DELAY: { type: "number", step: "0.1" },
+  MAX_CONCURRENT_UPLOADS: { type: "number" },
+  REQUESTS_PER_MINUTE: { type: "number" },
+  REQUESTS_BURST: { type: "number" },
   ENABLE_HTTP2: { type: "checkbox" },
   VERIFY_SSL: { type: "checkbox" },
+  CA_CERT_FILE: { type: "text" },
+  STARTUP_CHECK: { type: "checkbox" },
+  UPDATE_CHECK: { type: "checkbox" },
+  WATCHDOG: { type: "checkbox" },
+  ONBOARDING: { type: "checkbox" },
+  HTTP_API: { type: "text" },
+  HTTP_API_TOKEN: { type: "password" },
+  GRPC_API: { type: "text" },
+  GRPC_API_TOKEN: { type: "password" },
   START_KEY: { type: "text" },
   PAUSE_KEY: { type: "text" },
   CANCEL_KEY: { type: "text" },
+  MIDI_INPUT: { type: "text" },
+  MIDI_MAP: { type: "text" },
+  SCHEDULE: { type: "text" },
+  CONVERT_WORKERS: { type: "number" },
+  CLIPBOARD_WATCH: { type: "checkbox" },
   HOTKEY_HOOK: { type: "checkbox" },
+  PTT: { type: "checkbox" },
   CACHE_DIR: { type: "text" },
   KEEP_CACHE: { type: "checkbox" },
+  KEEP_WAV: { type: "checkbox" },
+  KEEP_CONVERTED: { type: "checkbox" },
+  CACHE_LAYOUT: { type: "text" },
+  CACHE_NAME: { type: "text" },
+  CACHE_MAX_AGE_DAYS: { type: "number" },
+  CACHE_MAX_SIZE_MB: { type: "number" },
+  CACHE_PURGE_INTERVAL: { type: "number" },
+  CACHE_ARCHIVE_DAYS: { type: "number" },
+  HISTORY: { type: "checkbox" },
+  CACHE_ENCRYPTION: { type: "text" },
+  CACHE_PASSPHRASE: { type: "password" },
+  RETRY_QUEUE: { type: "checkbox" },
+  QUEUE_RETRY_INTERVAL: { type: "number" },
+  OFFLINE_FIRST: { type: "checkbox" },
   NOTIFICATION: { type: "checkbox" },
   REQUEST_FAILED_NOTIFICATION: { type: "checkbox" },
+  PROGRESS_NOTIFICATION: { type: "checkbox" },
+  TIMING_NOTIFICATION: { type: "checkbox" },
+  QUIET_MODE: { type: "checkbox" },
+  LOG_FILE: { type: "text" },
+  LOG_MAX_SIZE_MB: { type: "number" },
+  LOG_ROTATE_DAYS: { type: "number" },
+  LOG_MAX_BACKUPS: { type: "number" },
+  TRAY: { type: "checkbox" },
+  TRAY_THEME: { type: "text" },
+  VU_METER: { type: "checkbox" },
+  RECORDING_TIMER: { type: "checkbox" },
+  SOUND_MUTE: { type: "checkbox" },
+  SOUND_START: { type: "text" },
+  SOUND_STOP: { type: "text" },
+  SOUND_PASTE_SUCCESS: { type: "text" },
+  SOUND_UPLOAD_FAILED: { type: "text" },
+  SOUND_ERROR: { type: "text" },
+  UI_LANG: { type: "text" },
   FFMPEG_DEBUG: { type: "checkbox" },
   RECORD_DEBUG: { type: "checkbox" },
   HOTKEY_DEBUG: { type: "checkbox" },
@@ -507,6 +873,7 @@ const fieldMeta = {
 
 const state = {
   runtime: { state: "Idle", message: "Idle" },
+  inputDevices: [],
   ui: { rounded: false },
   window: { minimal: false },
   drag: {
@@ -861,6 +1228,16 @@ function renderConfigField(key) {
   if (meta.type === "textarea") {
     input = document.createElement("textarea");
     input.rows = key === "ExtraConfig" ? 6 : 3;
+  } else if (meta.type === "device") {
+    input = document.createElement("select");
+    const current = state.config[key] ?? "";
+    const names = state.inputDevices.includes(current) || current === "" ? state.inputDevices : [current, ...state.inputDevices];
+    for (const [value, text] of [["", t("systemDefaultDevice")], ...names.map((name) => [name, name])]) {
+      const option = document.createElement("option");
+      option.value = value;
+      option.textContent = text;
+      input.appendChild(option);
+    }
   } else {
     input = document.createElement("input");
     input.type = meta.type;
@@ -872,6 +1249,8 @@ function renderConfigField(key) {
   input.dataset.key = key;
   if (meta.type === "checkbox") {
     input.checked = Boolean(state.config[key]);
+  } else if (state.config[key] !== null && typeof state.config[key] === "object") {
+    input.value = JSON.stringify(state.config[key], null, 2);
   } else {
     input.value = state.config[key] ?? "";
   }
@@ -936,6 +1315,7 @@ async function openSettings() {
   el.saveStatus.textContent = "";
   const payload = await LoadConfig();
   state.config = payload.data || {};
+  state.inputDevices = await InputDevices().catch(() => []) || [];
   el.configPath.textContent = payload.path || "";
   state.activeGroup = "Display";
   renderTabs();
@@ -0,0 +1,95 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"stt/internal/i18n"
+	"stt/internal/jumplist"
+)
+
+// autostartUsage lists the `stt autostart` commands.
+const autostartUsage = "usage: stt autostart <enable|disable|status> [-config path]"
+
+// runAutostartCommand handles `stt autostart`, which adds or removes a
+// shortcut in the Startup folder that starts record mode with the config at
+// -config when the user logs on, and returns the process exit code.
+func runAutostartCommand(args []string) int {
+	fs := flag.NewFlagSet("autostart", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config JSON")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(os.Stderr, i18n.T(autostartUsage))
+		return 2
+	}
+	link, err := autostartLink()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[autostart] %v\n", err)
+		return 1
+	}
+
+	switch positional[0] {
+	case "enable":
+		path, err := filepath.Abs(commandConfigPath(*configPath))
+		if err == nil {
+			_, err = os.Stat(path)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[autostart] %s\n", i18n.Sprintf("failed to load config '%s': %v", path, err))
+			return 1
+		}
+		// The config's directory is the working directory, which holds the
+		// temporary files when CACHE_DIR is not set. The console starts
+		// minimized, since the tray icon is the interface.
+		err = jumplist.Save(link, filepath.Dir(path), jumplist.Task{Title: "STT", Args: `-config "` + path + `"`, Minimized: true})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[autostart] %s\n", i18n.Sprintf("failed to create '%s': %v", link, err))
+			return 1
+		}
+		fmt.Printf("[autostart] %s\n", i18n.Sprintf("STT starts with %s when you log on (%s)", path, link))
+	case "disable":
+		if err := os.Remove(link); err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "[autostart] %s\n", i18n.Sprintf("failed to remove '%s': %v", link, err))
+			return 1
+		}
+		fmt.Printf("[autostart] %s\n", i18n.T("STT no longer starts when you log on"))
+	case "status":
+		if _, err := os.Stat(link); err != nil {
+			fmt.Println(i18n.T("autostart is off"))
+			return 0
+		}
+		fmt.Println(i18n.Sprintf("autostart is on: %s", link))
+	default:
+		fmt.Fprintln(os.Stderr, i18n.T(autostartUsage))
+		return 2
+	}
+	return 0
+}
+
+// autostartLink returns the path of the shortcut in the user's Startup
+// folder.
+func autostartLink() (string, error) {
+	appData := os.Getenv("APPDATA")
+	if appData == "" {
+		return "", errors.New("APPDATA is not set")
+	}
+	return filepath.Join(appData, "Microsoft", "Windows", "Start Menu", "Programs", "Startup", "STT.lnk"), nil
+}
@@ -0,0 +1,364 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"stt/internal/cachecrypt"
+	"stt/internal/cacheindex"
+	"stt/internal/config"
+	"stt/internal/history"
+	"stt/internal/i18n"
+)
+
+const cacheUsage = "usage: stt cache <decrypt <file.enc>...|stats|prune|archive> [-config path] [-out dir] [-older-than days] [-max-size MB] [-dry-run] [-json]"
+
+// runCacheCommand handles `stt cache <decrypt|stats|prune|archive>` and returns the
+// process exit code.
+func runCacheCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, i18n.T(cacheUsage))
+		return 2
+	}
+	switch args[0] {
+	case "decrypt":
+		return runCacheDecrypt(args[1:])
+	case "stats":
+		return runCacheStats(args[1:])
+	case "prune":
+		return runCachePrune(args[1:])
+	case "archive":
+		return runCacheArchive(args[1:])
+	}
+	fmt.Fprintln(os.Stderr, i18n.T(cacheUsage))
+	return 2
+}
+
+func runCacheDecrypt(args []string) int {
+	fs := flag.NewFlagSet("cache decrypt", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config JSON")
+	outDir := fs.String("out", "", "directory for decrypted files (default: next to each input)")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	files, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(os.Stderr, i18n.T(cacheUsage))
+		return 2
+	}
+	cfg, err := loadCommandConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[cache] %v\n", err)
+		return 1
+	}
+
+	ciphers := map[string]*cachecrypt.Cipher{}
+	status := 0
+	for _, file := range files {
+		dir := cacheKeyDir(filepath.Dir(file))
+		c, ok := ciphers[dir]
+		if !ok {
+			if c, err = cachecrypt.Load(dir, "", cfg.CachePassphrase); err != nil {
+				fmt.Fprintf(os.Stderr, "[cache] %s\n", i18n.Sprintf("failed to load cache key for '%s': %v", file, err))
+				status = 1
+				continue
+			}
+			ciphers[dir] = c
+		}
+		data, err := c.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[cache] %s\n", i18n.Sprintf("failed to decrypt '%s': %v", file, err))
+			status = 1
+			continue
+		}
+		out := strings.TrimSuffix(file, cachecrypt.Ext)
+		if out == file {
+			out += ".dec"
+		}
+		if *outDir != "" {
+			out = filepath.Join(*outDir, filepath.Base(out))
+		}
+		if err := os.WriteFile(out, data, 0600); err != nil {
+			fmt.Fprintf(os.Stderr, "[cache] %v\n", err)
+			status = 1
+			continue
+		}
+		fmt.Printf("[cache] %s\n", i18n.Sprintf("decrypted %s -> %s", file, out))
+	}
+	return status
+}
+
+// cacheKeyDir returns the nearest directory at or above dir that holds a
+// cache key, so files kept in CACHE_LAYOUT subdirectories find the key in
+// CACHE_DIR. It returns dir when there is none.
+func cacheKeyDir(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	for d := abs; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, cachecrypt.KeyFileName)); err == nil {
+			return d
+		}
+		if filepath.Dir(d) == d {
+			return dir
+		}
+	}
+}
+
+// cacheStatsJSON is the -json form of `stt cache stats`.
+type cacheStatsJSON struct {
+	CacheDir   string                       `json:"cache_dir"`
+	Entries    int                          `json:"entries"`
+	Size       int64                        `json:"size_bytes"`
+	Oldest     *cacheEntryJSON              `json:"oldest,omitempty"`
+	Newest     *cacheEntryJSON              `json:"newest,omitempty"`
+	ByProvider map[string]cacheProviderJSON `json:"providers"`
+}
+
+type cacheEntryJSON struct {
+	Path      string    `json:"path"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type cacheProviderJSON struct {
+	Entries int   `json:"entries"`
+	Size    int64 `json:"size_bytes"`
+}
+
+func runCacheStats(args []string) int {
+	fs := flag.NewFlagSet("cache stats", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config JSON")
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	cfg, entries, ok := scanCommandCache(*configPath)
+	if !ok {
+		return 1
+	}
+	s := cacheindex.Summarize(entries)
+
+	if *asJSON {
+		out := cacheStatsJSON{CacheDir: cfg.CacheDir, Entries: s.Entries, Size: s.Size, ByProvider: map[string]cacheProviderJSON{}}
+		if s.Oldest != nil {
+			out.Oldest = &cacheEntryJSON{Path: filepath.Join(s.Oldest.Dir, s.Oldest.Name), CreatedAt: s.Oldest.CreatedAt}
+			out.Newest = &cacheEntryJSON{Path: filepath.Join(s.Newest.Dir, s.Newest.Name), CreatedAt: s.Newest.CreatedAt}
+		}
+		for name, p := range s.ByProvider {
+			out.ByProvider[name] = cacheProviderJSON{Entries: p.Entries, Size: p.Size}
+		}
+		if err := writeJSON(os.Stdout, out); err != nil {
+			fmt.Fprintf(os.Stderr, "[cache] %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Println(i18n.Sprintf("Cache dir:  %s", cfg.CacheDir))
+	fmt.Println(i18n.Sprintf("Entries:    %d", s.Entries))
+	fmt.Println(i18n.Sprintf("Total size: %s", formatSize(s.Size)))
+	if s.Oldest != nil {
+		fmt.Println(i18n.Sprintf("Oldest:     %s  %s", s.Oldest.CreatedAt.Local().Format("2006-01-02 15:04:05"), cacheRel(cfg.CacheDir, *s.Oldest)))
+		fmt.Println(i18n.Sprintf("Newest:     %s  %s", s.Newest.CreatedAt.Local().Format("2006-01-02 15:04:05"), cacheRel(cfg.CacheDir, *s.Newest)))
+	}
+	if len(s.ByProvider) == 0 {
+		return 0
+	}
+	names := make([]string, 0, len(s.ByProvider))
+	for name := range s.ByProvider {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Println()
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tENTRIES\tSIZE")
+	for _, name := range names {
+		p := s.ByProvider[name]
+		if name == "" {
+			name = "(unknown)"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", name, p.Entries, formatSize(p.Size))
+	}
+	_ = tw.Flush()
+	return 0
+}
+
+func runCachePrune(args []string) int {
+	fs := flag.NewFlagSet("cache prune", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config JSON")
+	olderThan := fs.Int("older-than", 0, "remove entries older than this many days (default: CACHE_MAX_AGE_DAYS)")
+	maxSize := fs.Int("max-size", 0, "remove the oldest entries until the cache fits in this many MB (default: CACHE_MAX_SIZE_MB)")
+	dryRun := fs.Bool("dry-run", false, "list what would be removed without deleting")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *olderThan < 0 || *maxSize < 0 {
+		fmt.Fprintln(os.Stderr, i18n.T(cacheUsage))
+		return 2
+	}
+	cfg, entries, ok := scanCommandCache(*configPath)
+	if !ok {
+		return 1
+	}
+	if *olderThan == 0 && *maxSize == 0 {
+		*olderThan, *maxSize = cfg.CacheMaxAgeDays, cfg.CacheMaxSizeMB
+	}
+	policy := cacheindex.Policy{
+		MaxAge:  time.Duration(*olderThan) * 24 * time.Hour,
+		MaxSize: int64(*maxSize) << 20,
+	}
+	if policy == (cacheindex.Policy{}) {
+		fmt.Fprintf(os.Stderr, "[cache] %s\n", i18n.T("nothing to prune; pass -older-than or -max-size, or set CACHE_MAX_AGE_DAYS or CACHE_MAX_SIZE_MB"))
+		return 2
+	}
+
+	victims := cacheindex.Select(entries, policy, time.Now())
+	var size int64
+	for _, e := range victims {
+		size += e.Size
+		if *dryRun {
+			fmt.Printf("%s  %s  %s\n", e.CreatedAt.Local().Format("2006-01-02 15:04:05"), formatSize(e.Size), cacheRel(cfg.CacheDir, e))
+		}
+	}
+	if *dryRun {
+		fmt.Printf("[cache] %s\n", i18n.Sprintf("would remove %d entries (%s)", len(victims), formatSize(size)))
+		return 0
+	}
+	freed, err := cacheindex.Remove(cfg.CacheDir, victims)
+	fmt.Printf("[cache] %s\n", i18n.Sprintf("removed %d entries (%s)", len(victims), formatSize(freed)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[cache] %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func runCacheArchive(args []string) int {
+	fs := flag.NewFlagSet("cache archive", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config JSON")
+	olderThan := fs.Int("older-than", 0, "archive entries older than this many days (default: CACHE_ARCHIVE_DAYS)")
+	dryRun := fs.Bool("dry-run", false, "list what would be archived without moving anything")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *olderThan < 0 {
+		fmt.Fprintln(os.Stderr, i18n.T(cacheUsage))
+		return 2
+	}
+	cfg, entries, ok := scanCommandCache(*configPath)
+	if !ok {
+		return 1
+	}
+	if *olderThan == 0 {
+		*olderThan = cfg.CacheArchiveDays
+	}
+	if *olderThan == 0 {
+		fmt.Fprintf(os.Stderr, "[cache] %s\n", i18n.T("nothing to archive; pass -older-than or set CACHE_ARCHIVE_DAYS"))
+		return 2
+	}
+
+	victims := cacheindex.Select(entries, cacheindex.Policy{MaxAge: time.Duration(*olderThan) * 24 * time.Hour}, time.Now())
+	var size int64
+	for _, e := range victims {
+		size += e.Size
+		if *dryRun {
+			fmt.Printf("%s  %s  %s\n", e.CreatedAt.Local().Format("2006-01-02 15:04:05"), formatSize(e.Size), cacheRel(cfg.CacheDir, e))
+		}
+	}
+	if *dryRun {
+		fmt.Printf("[cache] %s\n", i18n.Sprintf("would archive %d entries (%s)", len(victims), formatSize(size)))
+		return 0
+	}
+	moved, err := cacheindex.Archive(cfg.CacheDir, victims)
+	relocateCacheHistory(cfg.CacheDir, moved)
+	fmt.Printf("[cache] %s\n", i18n.Sprintf("archived %d entries (%s)", len(victims), formatSize(size)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[cache] %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// relocateCacheHistory points history rows at the archived copies of their
+// audio. A cache dir without a history database is left alone.
+func relocateCacheHistory(cacheDir string, moved map[string]string) {
+	path := history.Path(cacheDir)
+	if _, err := os.Stat(path); err != nil || len(moved) == 0 {
+		return
+	}
+	store, err := history.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[history] %s\n", i18n.Sprintf("failed to open history database '%s': %v", path, err))
+		return
+	}
+	defer store.Close()
+	for src, dst := range moved {
+		if err := store.RelocateAudio(src, dst); err != nil {
+			fmt.Fprintf(os.Stderr, "[history] %v\n", err)
+		}
+	}
+}
+
+// scanCommandCache loads the config at configPath and scans its CACHE_DIR,
+// reporting failures on stderr.
+func scanCommandCache(configPath string) (config.Config, []cacheindex.Entry, bool) {
+	cfg, err := loadCommandConfig(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[cache] %v\n", err)
+		return cfg, nil, false
+	}
+	if cfg.CacheDir == "" {
+		fmt.Fprintf(os.Stderr, "[cache] %s\n", i18n.T("CACHE_DIR is not set"))
+		return cfg, nil, false
+	}
+	entries, err := cacheindex.Scan(cfg.CacheDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[cache] %s\n", i18n.Sprintf("failed to scan cache dir '%s': %v", cfg.CacheDir, err))
+		return cfg, nil, false
+	}
+	return cfg, entries, true
+}
+
+// cacheRel returns the entry's base path relative to the cache dir.
+func cacheRel(cacheDir string, e cacheindex.Entry) string {
+	path := filepath.Join(e.Dir, e.Name)
+	if rel, err := filepath.Rel(cacheDir, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
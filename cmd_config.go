@@ -0,0 +1,57 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"stt/internal/config"
+	"stt/internal/i18n"
+)
+
+// runConfigCommand handles `stt config <encrypt|decrypt> [-config path]` and
+// returns the process exit code.
+func runConfigCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("usage: stt config <encrypt|decrypt> [-config path]"))
+		return 2
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("config "+action, flag.ContinueOnError)
+	path := fs.String("config", "", "path to config JSON")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	*path = commandConfigPath(*path)
+
+	switch action {
+	case "encrypt":
+		if err := config.EncryptFile(*path); err != nil {
+			fmt.Fprintf(os.Stderr, "[config] %s\n", i18n.Sprintf("failed to encrypt '%s': %v", *path, err))
+			return 1
+		}
+		fmt.Printf("[config] %s\n", i18n.Sprintf("TOKEN, API_ENDPOINT and CACHE_PASSPHRASE in %s are now encrypted for the current Windows user", *path))
+	case "decrypt":
+		if err := config.DecryptFile(*path); err != nil {
+			fmt.Fprintf(os.Stderr, "[config] %s\n", i18n.Sprintf("failed to decrypt '%s': %v", *path, err))
+			return 1
+		}
+		fmt.Printf("[config] %s\n", i18n.Sprintf("TOKEN, API_ENDPOINT and CACHE_PASSPHRASE in %s are now stored in plain text", *path))
+	default:
+		fmt.Fprintln(os.Stderr, i18n.T("usage: stt config <encrypt|decrypt> [-config path]"))
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"stt/internal/i18n"
+	"stt/internal/metrics"
+	"stt/internal/tray"
+)
+
+// ctlUsage lists the `stt ctl` commands.
+const ctlUsage = "usage: stt ctl <start|stop|toggle|pause|resume|cancel|status [-json]|stats [-json]|transcribe <file> [-config path]>"
+
+// runCtlCommand handles `stt ctl`, which controls the running record-mode
+// instance from scripts and AutoHotkey, and returns the process exit code:
+// 0 on success, 1 when there is no instance or it refused the request and 2
+// for usage errors.
+func runCtlCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, i18n.T(ctlUsage))
+		return 2
+	}
+	switch args[0] {
+	case "status":
+		return runStatusCommand(args[1:])
+	case "stats":
+		return runStatsCommand(args[1:])
+	case "transcribe":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, i18n.T(ctlUsage))
+			return 2
+		}
+		return runTranscribeCommand(args[1:])
+	case "start", "stop", "toggle", "pause", "resume", "cancel":
+	default:
+		fmt.Fprintln(os.Stderr, i18n.T(ctlUsage))
+		return 2
+	}
+
+	fs := flag.NewFlagSet("ctl", flag.ContinueOnError)
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, i18n.T(ctlUsage))
+		return 2
+	}
+	if _, err := tray.Request(args[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "[ctl] %s\n", i18n.Sprintf("%s failed: %v", args[0], err))
+		return 1
+	}
+	return 0
+}
+
+// runStatsCommand handles `stt ctl stats`, which prints the metrics of the
+// running record-mode instance, as JSON with -json.
+func runStatsCommand(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the metrics as JSON")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	body, err := tray.Request("stats")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[stats] %s\n", i18n.Sprintf("no running STT instance: %v", err))
+		return 1
+	}
+	if *asJSON {
+		fmt.Println(body)
+		return 0
+	}
+	var s metrics.Snapshot
+	if err := json.Unmarshal([]byte(body), &s); err != nil {
+		fmt.Fprintf(os.Stderr, "[stats] %s\n", i18n.Sprintf("unexpected reply: %v", err))
+		return 1
+	}
+	printStats(s)
+	return 0
+}
+
+// printStats prints s for people, in minutes of audio and seconds of
+// latency.
+func printStats(s metrics.Snapshot) {
+	byStatus := map[string]int64{}
+	for _, counts := range s.Transcriptions {
+		for status, n := range counts {
+			byStatus[status] += n
+		}
+	}
+	avg := 0.0
+	if s.Latency.Count > 0 {
+		avg = s.Latency.SumSeconds / float64(s.Latency.Count)
+	}
+	fmt.Println(i18n.Sprintf("Since: %s", s.Since.Local().Format(time.DateTime)))
+	fmt.Println(i18n.Sprintf("Recordings: %d (%.1f min)", s.Recordings, s.RecordedSeconds/60))
+	fmt.Println(i18n.Sprintf("Transcriptions: %d ok, %d empty, %d failed", byStatus["ok"], byStatus["empty"], byStatus["failed"]))
+	fmt.Println(i18n.Sprintf("Transcribed audio: %.1f min", s.AudioSeconds/60))
+	fmt.Println(i18n.Sprintf("Uploads: %d ok, %d failed, %d retries", s.Uploads, s.UploadFailures, s.Retries))
+	fmt.Println(i18n.Sprintf("Average upload latency: %.2fs", avg))
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"stt/internal/app"
+	"stt/internal/i18n"
+	"stt/internal/midi"
+)
+
+// runDevicesCommand handles `stt devices`, listing the names INPUT_DEVICE
+// accepts (or with -midi those MIDI_INPUT accepts), and returns the process
+// exit code.
+func runDevicesCommand(args []string) int {
+	fs := flag.NewFlagSet("devices", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print JSON")
+	midiInputs := fs.Bool("midi", false, "list MIDI input devices")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	var names []string
+	var err error
+	if *midiInputs {
+		if names, err = midi.Devices(); err != nil {
+			fmt.Fprintf(os.Stderr, "[devices] %s\n", i18n.Sprintf("failed to list MIDI devices: %v", err))
+			return 1
+		}
+	} else if names, err = app.InputDevices(); err != nil {
+		fmt.Fprintf(os.Stderr, "[devices] %s\n", i18n.Sprintf("failed to list input devices: %v", err))
+		return 1
+	}
+	if *asJSON {
+		if names == nil {
+			names = []string{}
+		}
+		if err := writeJSON(os.Stdout, names); err != nil {
+			fmt.Fprintf(os.Stderr, "[devices] %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	return 0
+}
+
+// listDevices handles -list-devices, describing each capture device with the
+// index INPUT_DEVICE accepts, and returns the process exit code.
+func listDevices(asJSON bool) int {
+	devices, err := app.InputDeviceDetails()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[devices] %s\n", i18n.Sprintf("failed to list input devices: %v", err))
+		return 1
+	}
+	if asJSON {
+		if devices == nil {
+			devices = []app.DeviceInfo{}
+		}
+		if err := writeJSON(os.Stdout, devices); err != nil {
+			fmt.Fprintf(os.Stderr, "[devices] %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	if len(devices) == 0 {
+		fmt.Println(i18n.T("No input devices found."))
+		return 0
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "INDEX\tNAME\tHOST API\tCHANNELS\tSAMPLE RATES")
+	for _, d := range devices {
+		index := strconv.Itoa(d.Index)
+		if d.Default {
+			index += "*"
+		}
+		rates := make([]string, len(d.SampleRates))
+		for i, r := range d.SampleRates {
+			rates[i] = strconv.Itoa(r)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", index, d.Name, d.HostAPI, d.Channels, strings.Join(rates, ","))
+	}
+	_ = tw.Flush()
+	fmt.Println(i18n.T("* system default. Set INPUT_DEVICE to the index or the name."))
+	return 0
+}
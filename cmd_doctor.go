@@ -0,0 +1,53 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"stt/internal/app"
+	"stt/internal/i18n"
+)
+
+// runDoctorCommand handles `stt doctor`, which checks the config, ffmpeg
+// and the ASR endpoint before the first recording needs them, and returns
+// the process exit code: 1 when a check failed.
+func runDoctorCommand(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config JSON")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	cfg, err := loadCommandConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[doctor] %v\n", err)
+		return 1
+	}
+	failed := false
+	for _, c := range app.Doctor(context.Background(), cfg) {
+		if c.Err != nil {
+			failed = true
+			fmt.Printf("[doctor] %s: %s\n", i18n.T(c.Name), i18n.Sprintf("FAILED: %v", c.Err))
+			continue
+		}
+		fmt.Printf("[doctor] %s: %s\n", i18n.T(c.Name), c.Detail)
+	}
+	if failed {
+		return 1
+	}
+	fmt.Println("[doctor] " + i18n.T("all checks passed"))
+	return 0
+}
@@ -0,0 +1,341 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"stt/internal/cachecrypt"
+	"stt/internal/config"
+	"stt/internal/history"
+	"stt/internal/i18n"
+)
+
+const historyUsage = "usage: stt history <list|search <query>|show <id>|tui [query]> [-config path] [-db path] [-since date] [-until date] [-limit n] [-json]"
+
+// runHistoryCommand handles `stt history <list|search|show|tui>` and returns
+// the process exit code.
+func runHistoryCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, i18n.T(historyUsage))
+		return 2
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("history "+action, flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config JSON")
+	dbPath := fs.String("db", "", "path to history database (default: <CACHE_DIR>/history.db)")
+	since := fs.String("since", "", "only entries at or after this date (YYYY-MM-DD or RFC 3339)")
+	until := fs.String("until", "", "only entries before the end of this date (YYYY-MM-DD or RFC 3339)")
+	limit := fs.Int("limit", 20, "maximum number of entries (0 = no limit)")
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	positional, err := parseInterspersed(fs, args[1:])
+	if err != nil {
+		return 2
+	}
+
+	var q history.Query
+	q.Limit = *limit
+	if q.Since, err = parseHistoryDate(*since, false); err != nil {
+		fmt.Fprintf(os.Stderr, "[history] %s\n", i18n.Sprintf("invalid -since '%s': %v", *since, err))
+		return 2
+	}
+	if q.Until, err = parseHistoryDate(*until, true); err != nil {
+		fmt.Fprintf(os.Stderr, "[history] %s\n", i18n.Sprintf("invalid -until '%s': %v", *until, err))
+		return 2
+	}
+
+	var id int64
+	switch action {
+	case "list":
+		if len(positional) != 0 {
+			fmt.Fprintln(os.Stderr, i18n.T(historyUsage))
+			return 2
+		}
+	case "search":
+		if len(positional) == 0 {
+			fmt.Fprintln(os.Stderr, i18n.T(historyUsage))
+			return 2
+		}
+		q.Text = strings.Join(positional, " ")
+	case "tui":
+		q.Text = strings.Join(positional, " ")
+	case "show":
+		if len(positional) != 1 {
+			fmt.Fprintln(os.Stderr, i18n.T(historyUsage))
+			return 2
+		}
+		if id, err = strconv.ParseInt(positional[0], 10, 64); err != nil {
+			fmt.Fprintf(os.Stderr, "[history] %s\n", i18n.Sprintf("invalid entry id '%s'", positional[0]))
+			return 2
+		}
+	default:
+		fmt.Fprintln(os.Stderr, i18n.T(historyUsage))
+		return 2
+	}
+
+	cfg, err := loadCommandConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[history] %v\n", err)
+		return 1
+	}
+	store, c, ok := openCommandHistory(cfg, *dbPath)
+	if !ok {
+		return 1
+	}
+	defer store.Close()
+
+	if action == "tui" {
+		return runHistoryTUI(cfg, store, c, q)
+	}
+	if action == "show" {
+		e, err := store.Get(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[history] %s\n", i18n.Sprintf("entry %d not found: %v", id, err))
+			return 1
+		}
+		if *asJSON {
+			err = writeJSON(os.Stdout, toHistoryJSON(e, true))
+		} else {
+			writeHistoryDetail(os.Stdout, e)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[history] %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	entries, err := store.Search(q)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[history] %s\n", i18n.Sprintf("history query failed: %v", err))
+		return 1
+	}
+	if *asJSON {
+		out := make([]historyJSON, 0, len(entries))
+		for _, e := range entries {
+			out = append(out, toHistoryJSON(e, false))
+		}
+		if err := writeJSON(os.Stdout, out); err != nil {
+			fmt.Fprintf(os.Stderr, "[history] %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	writeHistoryTable(os.Stdout, entries)
+	return 0
+}
+
+// openCommandHistory opens the history database at dbPath, or the one in
+// CACHE_DIR, and loads the cache key stored next to it if there is one.
+// Failures are reported on stderr.
+func openCommandHistory(cfg config.Config, dbPath string) (*history.Store, *cachecrypt.Cipher, bool) {
+	path := dbPath
+	if path == "" {
+		if cfg.CacheDir == "" {
+			fmt.Fprintf(os.Stderr, "[history] %s\n", i18n.T("CACHE_DIR is not set; pass -db to point at a history database"))
+			return nil, nil, false
+		}
+		path = history.Path(cfg.CacheDir)
+	}
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintf(os.Stderr, "[history] %s\n", i18n.Sprintf("history database '%s' not found: %v", path, err))
+		return nil, nil, false
+	}
+	store, err := history.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[history] %s\n", i18n.Sprintf("failed to open history database '%s': %v", path, err))
+		return nil, nil, false
+	}
+	var c *cachecrypt.Cipher
+	if _, err := os.Stat(filepath.Join(filepath.Dir(path), cachecrypt.KeyFileName)); err == nil {
+		if c, err = cachecrypt.Load(filepath.Dir(path), "", cfg.CachePassphrase); err != nil {
+			fmt.Fprintf(os.Stderr, "[history] %s\n", i18n.Sprintf("encrypted entries cannot be read: %v", err))
+		} else {
+			store.SetCipher(c)
+		}
+	}
+	return store, c, true
+}
+
+// parseInterspersed parses args with fs, allowing flags after positional
+// arguments (e.g. `search hello -json`).
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// commandConfigPath returns the -config value, or the config file
+// config.SelectDefault picks when it was not given.
+func commandConfigPath(configPath string) string {
+	if configPath == "" {
+		return config.SelectDefault(false)
+	}
+	return configPath
+}
+
+// loadCommandConfig loads the default .env and configPath when they exist and
+// applies environment overrides, for subcommands that only need a few
+// settings. An empty configPath means the default config file.
+func loadCommandConfig(configPath string) (config.Config, error) {
+	configPath = commandConfigPath(configPath)
+	cfg := config.DefaultConfig()
+	if err := config.LoadDotEnv(config.DefaultEnvFile()); err != nil && !os.IsNotExist(err) {
+		return cfg, errors.New(i18n.Sprintf("failed to load env file '%s': %v", config.DefaultEnvFile(), err))
+	}
+	if _, err := os.Stat(configPath); err == nil {
+		if cfg, err = config.Load(configPath); err != nil {
+			return cfg, errors.New(i18n.Sprintf("failed to load config '%s': %v", configPath, err))
+		}
+	}
+	if err := config.ApplyEnv(&cfg); err != nil {
+		return cfg, errors.New(i18n.Sprintf("invalid environment override: %v", err))
+	}
+	return cfg, nil
+}
+
+// parseHistoryDate parses a -since/-until value in local time. A bare date
+// used as an upper bound covers the whole day.
+func parseHistoryDate(s string, endOfDay bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+type historyJSON struct {
+	ID         int64           `json:"id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Source     string          `json:"source"`
+	DurationMS int64           `json:"duration_ms"`
+	Provider   string          `json:"provider"`
+	Model      string          `json:"model"`
+	Language   string          `json:"language"`
+	Machine    string          `json:"machine,omitempty"`
+	Text       string          `json:"text"`
+	LatencyMS  int64           `json:"latency_ms"`
+	StopMS     int64           `json:"stop_ms"`
+	ConvertMS  int64           `json:"convert_ms"`
+	PasteMS    int64           `json:"paste_ms"`
+	AudioPath  string          `json:"audio_path"`
+	Status     string          `json:"status"`
+	Error      string          `json:"error,omitempty"`
+	Response   json.RawMessage `json:"response,omitempty"`
+}
+
+func toHistoryJSON(e history.Entry, withResponse bool) historyJSON {
+	out := historyJSON{
+		ID:         e.ID,
+		CreatedAt:  e.CreatedAt,
+		Source:     e.Source,
+		DurationMS: e.Duration.Milliseconds(),
+		Provider:   e.Provider,
+		Model:      e.Model,
+		Language:   e.Language,
+		Machine:    e.Machine,
+		Text:       e.Text,
+		LatencyMS:  e.Latency.Milliseconds(),
+		StopMS:     e.StopTime.Milliseconds(),
+		ConvertMS:  e.ConvertTime.Milliseconds(),
+		PasteMS:    e.PasteTime.Milliseconds(),
+		AudioPath:  e.AudioPath,
+		Status:     e.Status,
+		Error:      e.Error,
+	}
+	if withResponse && len(e.Response) > 0 {
+		if json.Valid(e.Response) {
+			out.Response = e.Response
+		} else {
+			out.Response, _ = json.Marshal(string(e.Response))
+		}
+	}
+	return out
+}
+
+func writeHistoryTable(w io.Writer, entries []history.Entry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTIME\tDURATION\tPROVIDER\tSTATUS\tTEXT")
+	for _, e := range entries {
+		text := e.Text
+		if text == "" {
+			text = e.Error
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%.1fs\t%s\t%s\t%s\n", e.ID, e.CreatedAt.Format("2006-01-02 15:04:05"),
+			e.Duration.Seconds(), e.Provider, e.Status, truncateRunes(strings.Join(strings.Fields(text), " "), 60))
+	}
+	_ = tw.Flush()
+}
+
+func writeHistoryDetail(w io.Writer, e history.Entry) {
+	fmt.Fprintln(w, i18n.Sprintf("ID:        %d", e.ID))
+	fmt.Fprintln(w, i18n.Sprintf("Time:      %s", e.CreatedAt.Format("2006-01-02 15:04:05")))
+	fmt.Fprintln(w, i18n.Sprintf("Source:    %s", e.Source))
+	fmt.Fprintln(w, i18n.Sprintf("Duration:  %.1fs", e.Duration.Seconds()))
+	fmt.Fprintln(w, i18n.Sprintf("Provider:  %s", e.Provider))
+	fmt.Fprintln(w, i18n.Sprintf("Model:     %s", e.Model))
+	fmt.Fprintln(w, i18n.Sprintf("Language:  %s", e.Language))
+	if e.Machine != "" {
+		fmt.Fprintln(w, i18n.Sprintf("Machine:   %s", e.Machine))
+	}
+	fmt.Fprintln(w, i18n.Sprintf("Latency:   %v", e.Latency))
+	if e.StopTime > 0 || e.ConvertTime > 0 || e.PasteTime > 0 {
+		fmt.Fprintln(w, i18n.Sprintf("Steps:     stop %v, convert %v, upload %v, paste %v", e.StopTime, e.ConvertTime, e.Latency, e.PasteTime))
+	}
+	fmt.Fprintln(w, i18n.Sprintf("Audio:     %s", e.AudioPath))
+	fmt.Fprintln(w, i18n.Sprintf("Status:    %s", e.Status))
+	if e.Error != "" {
+		fmt.Fprintln(w, i18n.Sprintf("Error:     %s", e.Error))
+	}
+	fmt.Fprintf(w, "\n%s\n", e.Text)
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
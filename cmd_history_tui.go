@@ -0,0 +1,280 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"stt/internal/app"
+	"stt/internal/cachecrypt"
+	"stt/internal/clipboard"
+	"stt/internal/config"
+	"stt/internal/history"
+	"stt/internal/i18n"
+	"stt/internal/player"
+)
+
+const tuiRule = "────────────────────────────────────────────────────────────────────────"
+
+// historyTUI is the transcript browser behind `stt history tui`. It redraws
+// the screen with ANSI escapes and reads one command per line, so it works in
+// any console without switching the terminal to raw mode.
+type historyTUI struct {
+	cfg     config.Config
+	store   *history.Store
+	cipher  *cachecrypt.Cipher
+	query   history.Query
+	in      *bufio.Scanner
+	out     io.Writer
+	entries []history.Entry
+	sel     int
+	status  string
+	temps   []string // decrypted copies handed to the audio player
+}
+
+func runHistoryTUI(cfg config.Config, store *history.Store, c *cachecrypt.Cipher, q history.Query) int {
+	enableANSI()
+	t := &historyTUI{
+		cfg:    cfg,
+		store:  store,
+		cipher: c,
+		query:  q,
+		in:     bufio.NewScanner(os.Stdin),
+		out:    os.Stdout,
+	}
+	defer t.cleanup()
+	if err := t.reload(); err != nil {
+		fmt.Fprintf(os.Stderr, "[history] %s\n", i18n.Sprintf("history query failed: %v", err))
+		return 1
+	}
+	for {
+		t.render()
+		if !t.in.Scan() || !t.handle(strings.TrimSpace(t.in.Text())) {
+			fmt.Fprintln(t.out)
+			return 0
+		}
+	}
+}
+
+func (t *historyTUI) reload() error {
+	entries, err := t.store.Search(t.query)
+	if err != nil {
+		return err
+	}
+	t.entries = entries
+	if t.sel >= len(entries) {
+		t.sel = max(len(entries)-1, 0)
+	}
+	return nil
+}
+
+func (t *historyTUI) current() (history.Entry, bool) {
+	if t.sel < 0 || t.sel >= len(t.entries) {
+		return history.Entry{}, false
+	}
+	return t.entries[t.sel], true
+}
+
+func (t *historyTUI) render() {
+	w := t.out
+	fmt.Fprint(w, "\x1b[H\x1b[2J")
+	title := i18n.T("STT history")
+	if t.query.Text != "" {
+		title += "  /" + t.query.Text
+	}
+	fmt.Fprintf(w, "\x1b[1m%s\x1b[0m\n\n", title)
+	if len(t.entries) == 0 {
+		fmt.Fprintln(w, i18n.T("No transcripts found."))
+	}
+	for i, e := range t.entries {
+		line := fmt.Sprintf("%3d  %s  %-6s  %s", i+1, e.CreatedAt.Format("01-02 15:04"), e.Status,
+			truncateRunes(strings.Join(strings.Fields(entryText(e)), " "), 60))
+		if i == t.sel {
+			fmt.Fprintf(w, "\x1b[7m> %s\x1b[0m\n", line)
+		} else {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+	if e, ok := t.current(); ok {
+		fmt.Fprintln(w, tuiRule)
+		fmt.Fprintf(w, "#%d  %s  %s  %s  %.1fs\n\n", e.ID, e.Source, e.Provider, e.Model, e.Duration.Seconds())
+		fmt.Fprintln(w, previewText(entryText(e), 72, 8))
+	}
+	fmt.Fprintln(w, tuiRule)
+	if t.status != "" {
+		fmt.Fprintln(w, t.status)
+		t.status = ""
+	}
+	fmt.Fprintf(w, "%s\n> ", i18n.T("[Enter/j] down  [k] up  [number] select  [c] copy  [p] play  [r] re-transcribe  [/text] search  [g] refresh  [q] quit"))
+}
+
+// handle runs one command line and reports whether the browser stays open.
+func (t *historyTUI) handle(cmd string) bool {
+	switch {
+	case cmd == "q" || cmd == "quit":
+		return false
+	case cmd == "" || cmd == "j":
+		t.sel = min(t.sel+1, max(len(t.entries)-1, 0))
+	case cmd == "k":
+		t.sel = max(t.sel-1, 0)
+	case cmd == "c":
+		t.copy()
+	case cmd == "p":
+		t.play()
+	case cmd == "r":
+		t.retranscribe()
+	case cmd == "g":
+		t.refresh()
+	case strings.HasPrefix(cmd, "/"):
+		t.query.Text = strings.TrimSpace(cmd[1:])
+		t.sel = 0
+		t.refresh()
+	default:
+		n, err := strconv.Atoi(cmd)
+		if err != nil || n < 1 || n > len(t.entries) {
+			t.status = i18n.Sprintf("unknown command '%s'", cmd)
+			break
+		}
+		t.sel = n - 1
+	}
+	return true
+}
+
+func (t *historyTUI) refresh() {
+	if err := t.reload(); err != nil {
+		t.status = i18n.Sprintf("history query failed: %v", err)
+	}
+}
+
+func (t *historyTUI) copy() {
+	e, ok := t.current()
+	switch {
+	case !ok || e.Text == "":
+		t.status = i18n.T("This entry has no text")
+	case e.Text == history.EncryptedText:
+		t.status = i18n.T("This entry is encrypted and no cache key is loaded")
+	default:
+		if err := clipboard.CopyText(e.Text); err != nil {
+			t.status = i18n.Sprintf("Copy failed: %v", err)
+		} else {
+			t.status = i18n.T("Copied to clipboard")
+		}
+	}
+}
+
+func (t *historyTUI) play() {
+	e, ok := t.current()
+	if !ok || e.AudioPath == "" {
+		t.status = i18n.T("This entry has no cached audio")
+		return
+	}
+	path := e.AudioPath
+	if _, err := os.Stat(path); err != nil {
+		t.status = i18n.Sprintf("Audio file '%s' is missing", path)
+		return
+	}
+	if strings.HasSuffix(path, cachecrypt.Ext) {
+		plain, err := t.decrypt(path)
+		if err != nil {
+			t.status = i18n.Sprintf("Playback failed: %v", err)
+			return
+		}
+		path = plain
+	}
+	if err := player.Play(path); err != nil {
+		t.status = i18n.Sprintf("Playback failed: %v", err)
+		return
+	}
+	t.status = i18n.Sprintf("Playing %s", filepath.Base(e.AudioPath))
+}
+
+// decrypt writes a plaintext copy of an encrypted recording for the player.
+// Copies are removed when the browser exits.
+func (t *historyTUI) decrypt(path string) (string, error) {
+	if t.cipher == nil {
+		return "", fmt.Errorf("'%s' is encrypted and no cache key is loaded", path)
+	}
+	data, err := t.cipher.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp("", "stt-play-*"+filepath.Ext(strings.TrimSuffix(path, cachecrypt.Ext)))
+	if err != nil {
+		return "", err
+	}
+	t.temps = append(t.temps, f.Name())
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return f.Name(), err
+}
+
+func (t *historyTUI) retranscribe() {
+	e, ok := t.current()
+	if !ok || e.AudioPath == "" {
+		t.status = i18n.T("This entry has no cached audio")
+		return
+	}
+	cfg := t.cfg
+	if err := config.Validate(&cfg); err != nil {
+		t.status = i18n.Sprintf("invalid config: %v", err)
+		return
+	}
+	fmt.Fprintln(t.out, i18n.T("Re-transcribing..."))
+	text, err := app.Retranscribe(cfg, t.store, t.cipher, e.AudioPath)
+	if err != nil {
+		t.status = i18n.Sprintf("Re-transcription failed: %v", err)
+		t.refresh()
+		return
+	}
+	t.sel = 0
+	t.refresh()
+	t.status = i18n.Sprintf("Re-transcribed: %s", truncateRunes(strings.Join(strings.Fields(text), " "), 60))
+}
+
+func (t *historyTUI) cleanup() {
+	for _, p := range t.temps {
+		_ = os.Remove(p)
+	}
+}
+
+// entryText returns the transcript of e, or its error when there is none.
+func entryText(e history.Entry) string {
+	if e.Text == "" {
+		return e.Error
+	}
+	return e.Text
+}
+
+// previewText wraps s at width runes and keeps at most lines lines.
+func previewText(s string, width, lines int) string {
+	var out []string
+	for _, para := range strings.Split(s, "\n") {
+		r := []rune(para)
+		for len(r) > width {
+			out = append(out, string(r[:width]))
+			r = r[width:]
+		}
+		out = append(out, string(r))
+	}
+	if len(out) > lines {
+		out = append(out[:lines-1], "…")
+	}
+	return strings.Join(out, "\n")
+}
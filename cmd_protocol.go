@@ -0,0 +1,136 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"stt/internal/i18n"
+	"stt/internal/notify"
+	"stt/internal/shellmenu"
+	"stt/internal/tray"
+	"stt/internal/urlscheme"
+)
+
+// protocolUsage lists the `stt protocol` commands.
+const protocolUsage = "usage: stt protocol <install|uninstall|status> [-config path]"
+
+// runProtocolCommand handles `stt protocol`, which registers or removes the
+// stt:// URI scheme for the current user, and returns the process exit code.
+func runProtocolCommand(args []string) int {
+	fs := flag.NewFlagSet("protocol", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config JSON")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(os.Stderr, i18n.T(protocolUsage))
+		return 2
+	}
+
+	switch positional[0] {
+	case "install":
+		exe, err := os.Executable()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[protocol] %v\n", err)
+			return 1
+		}
+		cmdArgs := []string{"open"}
+		if *configPath != "" {
+			path, err := filepath.Abs(*configPath)
+			if err == nil {
+				_, err = os.Stat(path)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[protocol] %s\n", i18n.Sprintf("failed to load config '%s': %v", path, err))
+				return 1
+			}
+			cmdArgs = append(cmdArgs, "-config", path)
+		}
+		if err := urlscheme.Install(shellmenu.Command(exe, cmdArgs...), exe); err != nil {
+			fmt.Fprintf(os.Stderr, "[protocol] %s\n", i18n.Sprintf("failed to register stt:// links: %v", err))
+			return 1
+		}
+		fmt.Printf("[protocol] %s\n", i18n.T("stt:// links now open STT"))
+	case "uninstall":
+		if err := urlscheme.Uninstall(); err != nil {
+			fmt.Fprintf(os.Stderr, "[protocol] %s\n", i18n.Sprintf("failed to unregister stt:// links: %v", err))
+			return 1
+		}
+		fmt.Printf("[protocol] %s\n", i18n.T("stt:// links no longer open STT"))
+	case "status":
+		command, err := urlscheme.Installed()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[protocol] %v\n", err)
+			return 1
+		}
+		if command == "" {
+			fmt.Println(i18n.T("stt:// links are not registered"))
+			return 0
+		}
+		fmt.Println(i18n.Sprintf("stt:// links are registered: %s", command))
+	default:
+		fmt.Fprintln(os.Stderr, i18n.T(protocolUsage))
+		return 2
+	}
+	return 0
+}
+
+// runOpenCommand handles `stt open <link>`, which Windows runs for stt://
+// links, and returns the process exit code. Since a link usually comes from
+// a browser without a console, failures are also shown as notifications.
+func runOpenCommand(args []string) int {
+	fs := flag.NewFlagSet("open", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config JSON")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(os.Stderr, i18n.T("usage: stt open <stt://action>"))
+		return 2
+	}
+	fail := func(msg string) int {
+		fmt.Fprintf(os.Stderr, "[open] %s\n", msg)
+		notify.Notify("STT", msg)
+		return 1
+	}
+
+	req, err := urlscheme.Parse(positional[0])
+	if err != nil {
+		return fail(err.Error())
+	}
+	if req.Action == "transcribe" {
+		// Any web page can offer a link, so only media files are sent to
+		// the ASR endpoint.
+		if !slices.Contains(shellmenu.Exts, strings.ToLower(filepath.Ext(req.Path))) {
+			return fail(i18n.Sprintf("not an audio or video file: %s", req.Path))
+		}
+		transcribeArgs := []string{req.Path}
+		if *configPath != "" {
+			transcribeArgs = append(transcribeArgs, "-config", *configPath)
+		}
+		return runTranscribeCommand(transcribeArgs)
+	}
+	if _, err := tray.Request(req.Action); err != nil {
+		return fail(i18n.Sprintf("%s failed: %v", req.Action, err))
+	}
+	return 0
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"stt/internal/app"
+	"stt/internal/config"
+	"stt/internal/i18n"
+	"stt/internal/queue"
+)
+
+const queueUsage = "usage: stt queue <list|flush> [-config path]"
+
+// runQueueCommand handles `stt queue <list|flush>` and returns the process
+// exit code.
+func runQueueCommand(args []string) int {
+	if len(args) == 0 || (args[0] != "list" && args[0] != "flush") {
+		fmt.Fprintln(os.Stderr, i18n.T(queueUsage))
+		return 2
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("queue "+action, flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config JSON")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	cfg, err := loadCommandConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[queue] %v\n", err)
+		return 1
+	}
+	if cfg.CacheDir == "" {
+		fmt.Fprintf(os.Stderr, "[queue] %s\n", i18n.T("CACHE_DIR is not set; the retry queue lives in the cache dir"))
+		return 1
+	}
+
+	if action == "list" {
+		q, err := queue.Open(queue.Path(cfg.CacheDir))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[queue] %v\n", err)
+			return 1
+		}
+		items, err := q.List()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[queue] %v\n", err)
+			return 1
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tTIME\tDURATION\tATTEMPTS\tLAST ERROR")
+		for _, it := range items {
+			fmt.Fprintf(tw, "%s\t%s\t%.1fs\t%d\t%s\n", it.ID, it.CreatedAt.Format("2006-01-02 15:04:05"),
+				it.Duration.Seconds(), it.Attempts, truncateRunes(it.LastError, 60))
+		}
+		_ = tw.Flush()
+		return 0
+	}
+
+	if err := config.Validate(&cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "[queue] %s\n", i18n.Sprintf("invalid config: %v", err))
+		return 1
+	}
+	config.InitCacheDir(&cfg)
+	res, err := app.FlushQueue(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[queue] %v\n", err)
+		return 1
+	}
+	fmt.Printf("[queue] %s\n", i18n.Sprintf("%d transcribed, %d still pending", res.Done, res.Remaining))
+	if res.Remaining > 0 {
+		return 1
+	}
+	return 0
+}
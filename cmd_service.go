@@ -0,0 +1,114 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"stt/internal/app"
+	"stt/internal/applog"
+	"stt/internal/config"
+	"stt/internal/i18n"
+	"stt/internal/service"
+)
+
+// serviceUsage lists the `stt service` commands.
+const serviceUsage = "usage: stt service <install|uninstall|run> [-config path]"
+
+// runServiceCommand handles `stt service`. install registers and starts the
+// Windows service for the config at -config and uninstall removes it; both
+// need an elevated prompt. run is what the service control manager starts.
+func runServiceCommand(args []string) int {
+	fs := flag.NewFlagSet("service", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config JSON")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(os.Stderr, i18n.T(serviceUsage))
+		return 2
+	}
+	path, err := filepath.Abs(commandConfigPath(*configPath))
+	if err == nil {
+		var exe string
+		if exe, err = os.Executable(); err == nil {
+			switch positional[0] {
+			case "install":
+				err = installService(exe, path)
+			case "uninstall":
+				if err = service.Uninstall(); err == nil {
+					fmt.Printf("[service] %s\n", i18n.Sprintf("service %s removed", service.Name))
+				}
+			case "run":
+				err = runService(exe, path)
+			default:
+				fmt.Fprintln(os.Stderr, i18n.T(serviceUsage))
+				return 2
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[service] %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func installService(exe, configPath string) error {
+	if _, err := os.Stat(configPath); err != nil {
+		return errors.New(i18n.Sprintf("failed to load config '%s': %v", configPath, err))
+	}
+	if err := service.Install(exe, []string{"service", "run", "-config", configPath}); err != nil {
+		return errors.New(i18n.Sprintf("failed to install service %s: %v", service.Name, err))
+	}
+	fmt.Printf("[service] %s\n", i18n.Sprintf("service %s installed and started; it starts the agent with %s", service.Name, configPath))
+	return nil
+}
+
+// runService runs as the service: the queue engine here, and the record-mode
+// agent with the same config in every logged-on session.
+func runService(exe, configPath string) error {
+	if !service.IsService() {
+		return errors.New(i18n.T("stt service run is started by Windows; use stt service install"))
+	}
+	cfg, err := loadCommandConfig(configPath)
+	if err == nil {
+		if verr := config.Validate(&cfg); verr != nil {
+			err = errors.New(i18n.Sprintf("invalid config: %v", verr))
+		}
+	}
+	config.InitCacheDir(&cfg)
+	// The agents write LOG_FILE, so the service keeps a log of its own.
+	if logPath := config.LogPath(&cfg); logPath != "" {
+		ext := filepath.Ext(logPath)
+		if stop, lerr := applog.Start(strings.TrimSuffix(logPath, ext)+"-service"+ext, config.LogRotation(&cfg)); lerr == nil {
+			defer stop()
+		}
+	}
+	// The config is loaded before the service starts, but a failure is
+	// reported once it runs, so Windows logs it rather than a start timeout.
+	engine := func(stop <-chan struct{}) error {
+		if err != nil {
+			return err
+		}
+		return app.RunService(cfg, stop)
+	}
+	// The service restarts crashed agents itself, so they skip WATCHDOG.
+	return service.Run(engine, []string{exe, "-config", configPath, "-watchdog", "false"}, filepath.Dir(configPath))
+}
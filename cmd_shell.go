@@ -0,0 +1,92 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"stt/internal/i18n"
+	"stt/internal/shellmenu"
+)
+
+// shellUsage lists the `stt shell-integration` commands.
+const shellUsage = "usage: stt shell-integration <install|uninstall|status> [-config path]"
+
+// runShellCommand handles `stt shell-integration`, which adds or removes the
+// Explorer context-menu entry that runs `stt transcribe` on audio and video
+// files, and returns the process exit code.
+func runShellCommand(args []string) int {
+	fs := flag.NewFlagSet("shell-integration", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config JSON")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(os.Stderr, i18n.T(shellUsage))
+		return 2
+	}
+
+	switch positional[0] {
+	case "install":
+		exe, err := os.Executable()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[shell] %v\n", err)
+			return 1
+		}
+		// Without a running instance, transcribe falls back to file mode,
+		// which needs the config by an absolute path since Explorer starts
+		// it in the file's folder.
+		cmdArgs := []string{"transcribe"}
+		if *configPath != "" {
+			path, err := filepath.Abs(*configPath)
+			if err == nil {
+				_, err = os.Stat(path)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[shell] %s\n", i18n.Sprintf("failed to load config '%s': %v", path, err))
+				return 1
+			}
+			cmdArgs = append(cmdArgs, "-config", path)
+		}
+		if err := shellmenu.Install(i18n.T("Transcribe with STT"), shellmenu.Command(exe, cmdArgs...), exe); err != nil {
+			fmt.Fprintf(os.Stderr, "[shell] %s\n", i18n.Sprintf("failed to install shell integration: %v", err))
+			return 1
+		}
+		fmt.Printf("[shell] %s\n", i18n.T("\"Transcribe with STT\" added to the context menu of audio and video files"))
+	case "uninstall":
+		if err := shellmenu.Uninstall(); err != nil {
+			fmt.Fprintf(os.Stderr, "[shell] %s\n", i18n.Sprintf("failed to remove shell integration: %v", err))
+			return 1
+		}
+		fmt.Printf("[shell] %s\n", i18n.T("\"Transcribe with STT\" removed from the context menu"))
+	case "status":
+		command, err := shellmenu.Installed()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[shell] %v\n", err)
+			return 1
+		}
+		if command == "" {
+			fmt.Println(i18n.T("shell integration is off"))
+			return 0
+		}
+		fmt.Println(i18n.Sprintf("shell integration is on: %s", command))
+	default:
+		fmt.Fprintln(os.Stderr, i18n.T(shellUsage))
+		return 2
+	}
+	return 0
+}
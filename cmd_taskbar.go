@@ -0,0 +1,172 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"stt/internal/app"
+	"stt/internal/config"
+	"stt/internal/filedialog"
+	"stt/internal/i18n"
+	"stt/internal/jumplist"
+	"stt/internal/notify"
+	"stt/internal/tray"
+)
+
+// audioPatterns are the audio and video files the Transcribe file dialog
+// lists.
+var audioPatterns = []string{"*.wav", "*.mp3", "*.m4a", "*.aac", "*.flac", "*.ogg", "*.opus", "*.wma", "*.mp4", "*.webm", "*.mkv", "*.mov", "*.avi", "*.wmv", "*.m4v"}
+
+// registerJumpList adds the record-mode tasks to the jump list of the
+// taskbar button. They run in the current directory, with -config when the
+// config was given as a path.
+func registerJumpList(configPath string) {
+	configArg := ""
+	if configPath != "" {
+		if abs, err := filepath.Abs(configPath); err == nil {
+			configArg = ` -config "` + abs + `"`
+		}
+	}
+	err := jumplist.Set([]jumplist.Task{
+		{Title: i18n.T("Start/stop recording"), Args: "toggle", Minimized: true},
+		{Title: i18n.T("Transcribe file…"), Args: "transcribe" + configArg},
+		{Title: i18n.T("Open history"), Args: "history tui" + configArg},
+	})
+	if err != nil {
+		fmt.Printf("[jumplist] %v\n", err)
+	}
+}
+
+// runToggleCommand handles `stt toggle`, which starts or stops recording in
+// the running record-mode instance, and returns the process exit code.
+func runToggleCommand(args []string) int {
+	fs := flag.NewFlagSet("toggle", flag.ContinueOnError)
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if err := tray.Send("toggle"); err != nil {
+		// Launched from the jump list, the console closes right away, so
+		// the notification is what the user sees.
+		msg := i18n.Sprintf("no running STT instance to toggle: %v", err)
+		fmt.Fprintf(os.Stderr, "[toggle] %s\n", msg)
+		notify.Notify("STT", msg)
+		return 1
+	}
+	return 0
+}
+
+// runStatusCommand handles `stt status`, which prints the state of the
+// running record-mode instance as its tray tooltip shows it: the state,
+// recording time, profile and last transcription latency. With -json it
+// prints them as a JSON object instead.
+func runStatusCommand(args []string) int {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the status as JSON")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	query := tray.Query
+	if *asJSON {
+		query = func() (string, error) { return tray.Request("status") }
+	}
+	text, err := query()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[status] %s\n", i18n.Sprintf("no running STT instance: %v", err))
+		return 1
+	}
+	fmt.Println(text)
+	return 0
+}
+
+// runTranscribeCommand handles `stt transcribe [file]`: it transcribes file,
+// or one picked in a dialog when it is omitted, writes the text next to it
+// and returns the process exit code. A file given on the command line goes
+// to the running record-mode instance when there is one; that hand-off is
+// fire-and-forget, so the exit code only says whether the instance took the
+// file, and the instance reports the result in its log and a notification.
+func runTranscribeCommand(args []string) int {
+	fs := flag.NewFlagSet("transcribe", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config JSON")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) > 1 {
+		fmt.Fprintln(os.Stderr, i18n.T("usage: stt transcribe [file] [-config path]"))
+		return 2
+	}
+	fail := func(msg string) int {
+		fmt.Fprintf(os.Stderr, "[transcribe] %s\n", msg)
+		notify.Notify("STT", msg)
+		return 1
+	}
+
+	// A running record-mode instance transcribes with the config it has
+	// loaded, so the Explorer context menu does not load a second one.
+	if len(positional) == 1 {
+		if abs, err := filepath.Abs(positional[0]); err == nil {
+			_, err := tray.Request("transcribe " + abs)
+			if err == nil {
+				fmt.Printf("[transcribe] %s\n", i18n.Sprintf("sent %s to the running instance, which reports the result in a notification", abs))
+				return 0
+			}
+			if errors.As(err, new(tray.ReplyError)) {
+				return fail(i18n.Sprintf("the running instance could not transcribe %s: %v", abs, err))
+			}
+		}
+	}
+
+	cfg, err := loadCommandConfig(*configPath)
+	if err != nil {
+		return fail(err.Error())
+	}
+	if err := config.Validate(&cfg); err != nil {
+		return fail(i18n.Sprintf("invalid config: %v", err))
+	}
+	config.InitCacheDir(&cfg)
+
+	var path string
+	if len(positional) == 1 {
+		path = positional[0]
+	} else {
+		path, err = filedialog.Open(i18n.T("Transcribe file…"), []filedialog.Filter{
+			{Name: i18n.T("Audio files"), Patterns: audioPatterns},
+			{Name: i18n.T("All files"), Patterns: []string{"*.*"}},
+		})
+		if err != nil {
+			return fail(err.Error())
+		}
+		if path == "" {
+			return 0
+		}
+	}
+
+	out := strings.TrimSuffix(path, filepath.Ext(path)) + ".txt"
+	if err := app.RunFileMode(cfg, path, out); err != nil {
+		return fail(i18n.Sprintf("file mode failed: %v", err))
+	}
+	msg := i18n.Sprintf("transcript written to %s", out)
+	fmt.Printf("[transcribe] %s\n", msg)
+	if cfg.Notification {
+		notify.Notify("STT", msg)
+	}
+	return 0
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"stt/internal/i18n"
+	"stt/internal/update"
+)
+
+// updateUsage lists the `stt update` flags.
+const updateUsage = "usage: stt update [-check] [-force]"
+
+// runUpdateCommand handles `stt update`, which replaces this executable with
+// the latest published release after checking its SHA-256, and returns the
+// process exit code.
+func runUpdateCommand(args []string) int {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	checkOnly := fs.Bool("check", false, "only report whether an update is available")
+	force := fs.Bool("force", false, "install even when up to date or when this build's version is unknown")
+	fs.String("ui-lang", "", "UI language (zh/en)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, i18n.T(updateUsage))
+		return 2
+	}
+
+	ctx := context.Background()
+	client := &http.Client{Timeout: 5 * time.Minute}
+	latest, err := update.Latest(ctx, client)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[update] %s\n", i18n.Sprintf("failed to check for updates: %v", err))
+		return 1
+	}
+	current := update.Current()
+	switch {
+	case current == latest && !*force:
+		fmt.Printf("[update] %s\n", i18n.Sprintf("STT is up to date (%s)", shortRevision(latest)))
+		return 0
+	case *checkOnly:
+		if current == "" {
+			fmt.Printf("[update] %s\n", i18n.Sprintf("this build's version is unknown; the latest release is %s", shortRevision(latest)))
+		} else {
+			fmt.Printf("[update] %s\n", i18n.Sprintf("update available: %s -> %s", shortRevision(current), shortRevision(latest)))
+		}
+		return 0
+	case current == "" && !*force:
+		fmt.Fprintf(os.Stderr, "[update] %s\n", i18n.Sprintf("this build's version is unknown; run stt update -force to install %s anyway", shortRevision(latest)))
+		return 1
+	}
+
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[update] %v\n", err)
+		return 1
+	}
+	dir, err := os.MkdirTemp("", "stt-update-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[update] %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	fmt.Printf("[update] %s\n", i18n.Sprintf("downloading %s...", update.CLIAsset))
+	archive, err := update.Download(ctx, client, update.CLIAsset, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[update] %s\n", i18n.Sprintf("download failed: %v", err))
+		return 1
+	}
+	if err := update.Apply(archive, update.CLIMember, exe); err != nil {
+		fmt.Fprintf(os.Stderr, "[update] %s\n", i18n.Sprintf("failed to replace '%s': %v", exe, err))
+		return 1
+	}
+	fmt.Printf("[update] %s\n", i18n.Sprintf("updated to %s; restart STT to use it", shortRevision(latest)))
+	return 0
+}
+
+// shortRevision abbreviates a commit hash the way git does.
+func shortRevision(rev string) string {
+	if len(rev) > 7 {
+		return rev[:7]
+	}
+	return rev
+}
@@ -0,0 +1,202 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Command dll builds stt.dll, which lets other Windows programs (C#,
+// AutoHotkey, ...) record and transcribe with the STT engine directly:
+//
+//	go build -buildmode=c-shared -o stt.dll ./dll
+//
+// The build also writes stt.h with the exported functions. Strings go in
+// and out as UTF-8; every string the DLL returns must be released with
+// STTFree. Functions returning int give 0 on success and -1 on failure,
+// with the reason in STTLastError.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"stt/internal/appcore"
+	"stt/internal/config"
+)
+
+var (
+	mu sync.Mutex
+	rt *appcore.Runtime
+
+	errMu   sync.Mutex
+	lastErr string
+)
+
+func main() {}
+
+// STTOpen loads the config at configPath, or the default config file when
+// it is NULL or empty, and starts the engine. Hotkeys, the tray and pasting
+// stay off; the host drives recording through the other functions.
+//
+//export STTOpen
+func STTOpen(configPath *C.char) C.int {
+	mu.Lock()
+	defer mu.Unlock()
+	if rt != nil {
+		return fail(errors.New("already open"))
+	}
+	path := ""
+	if configPath != nil {
+		path = C.GoString(configPath)
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return fail(err)
+	}
+	r, err := appcore.NewRuntime(cfg)
+	if err != nil {
+		return fail(err)
+	}
+	r.SetPaste(false)
+	rt = r
+	return 0
+}
+
+// STTClose stops the engine, canceling an active recording.
+//
+//export STTClose
+func STTClose() {
+	mu.Lock()
+	r := rt
+	rt = nil
+	mu.Unlock()
+	if r != nil {
+		r.Stop()
+	}
+}
+
+// StartRecording starts recording from INPUT_DEVICE.
+//
+//export StartRecording
+func StartRecording() C.int {
+	r, err := current()
+	if err == nil {
+		err = r.StartRecording()
+	}
+	if err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+// StopRecording stops the recording and returns its transcript, or NULL on
+// failure. It blocks until the ASR service has answered.
+//
+//export StopRecording
+func StopRecording() *C.char {
+	r, err := current()
+	if err != nil {
+		fail(err)
+		return nil
+	}
+	text, err := r.StopRecording()
+	if err != nil {
+		fail(err)
+		return nil
+	}
+	return C.CString(text)
+}
+
+// TranscribeFile transcribes the audio file at path and returns the text, or
+// NULL on failure.
+//
+//export TranscribeFile
+func TranscribeFile(path *C.char) *C.char {
+	r, err := current()
+	if err != nil {
+		fail(err)
+		return nil
+	}
+	name := C.GoString(path)
+	f, err := os.Open(name)
+	if err != nil {
+		fail(err)
+		return nil
+	}
+	defer f.Close()
+	text, err := r.Transcribe(context.Background(), filepath.Base(name), f)
+	if err != nil {
+		fail(err)
+		return nil
+	}
+	return C.CString(text)
+}
+
+// STTLastError returns the message of the last failure, or an empty string.
+//
+//export STTLastError
+func STTLastError() *C.char {
+	errMu.Lock()
+	defer errMu.Unlock()
+	return C.CString(lastErr)
+}
+
+// STTFree releases a string returned by the DLL.
+//
+//export STTFree
+func STTFree(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// current returns the open engine.
+func current() (*appcore.Runtime, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if rt == nil {
+		return nil, errors.New("not open; call STTOpen first")
+	}
+	return rt, nil
+}
+
+// fail records err for STTLastError and returns -1.
+func fail(err error) C.int {
+	errMu.Lock()
+	defer errMu.Unlock()
+	lastErr = err.Error()
+	return -1
+}
+
+// loadConfig reads the config like the CLI does: the file at path, or the
+// default config file, with STT_* environment overrides.
+func loadConfig(path string) (config.Config, error) {
+	if path == "" {
+		path = config.SelectDefault(false)
+	}
+	cfg := config.DefaultConfig()
+	if _, err := os.Stat(path); err == nil {
+		if cfg, err = config.Load(path); err != nil {
+			return cfg, err
+		}
+	}
+	if err := config.ApplyEnv(&cfg); err != nil {
+		return cfg, err
+	}
+	if err := config.Validate(&cfg); err != nil {
+		return cfg, err
+	}
+	config.InitCacheDir(&cfg)
+	return cfg, nil
+}
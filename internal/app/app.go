@@ -12,16 +12,96 @@
 package app
 
 import (
+	"context"
+	"io"
+
 	"stt/internal/appcore"
+	"stt/internal/cachecrypt"
 	"stt/internal/config"
+	"stt/internal/history"
+	"stt/pkg/record"
 )
 
-// RunRecordMode starts hotkeys and runs the recording loop.
-func RunRecordMode(cfg config.Config) error {
-	return appcore.RunRecordMode(cfg)
+// RunRecordMode starts hotkeys and runs the recording loop. load re-reads the
+// config when Reload is chosen from the tray menu.
+func RunRecordMode(cfg config.Config, load func() (config.Config, error)) error {
+	return appcore.RunRecordMode(cfg, load)
 }
 
 // RunFileMode uploads an existing file and writes the result to a .txt file.
 func RunFileMode(cfg config.Config, inputPath string, outputPath string) error {
 	return appcore.RunFileMode(cfg, inputPath, outputPath)
 }
+
+// FileReport is the outcome of file mode that -json prints.
+type FileReport = appcore.FileReport
+
+// FileResult is the outcome for one file of a FileReport.
+type FileResult = appcore.FileResult
+
+// ExitConfig is the exit code for an invalid config; see appcore.ExitCode.
+const ExitConfig = appcore.ExitConfig
+
+// ReportFileMode runs file mode, reading stdin when it is set, and returns
+// the outcome for -json.
+func ReportFileMode(cfg config.Config, inputPath, outputPath string, stdin io.Reader) FileReport {
+	return appcore.ReportFileMode(cfg, inputPath, outputPath, stdin)
+}
+
+// ExitCode returns the exit code of file mode for err.
+func ExitCode(err error) int {
+	return appcore.ExitCode(err)
+}
+
+// RunStdinMode transcribes audio piped to stdin and writes the transcript to
+// stdout, or to outputPath when set.
+func RunStdinMode(cfg config.Config, stdin io.Reader, stdout io.Writer, outputPath string) error {
+	return appcore.RunStdinMode(cfg, stdin, stdout, outputPath)
+}
+
+// RunSimulation runs a synthetic recording through the whole pipeline, for
+// -simulate. mock uploads to a built-in mock endpoint.
+func RunSimulation(cfg config.Config, mock bool) error {
+	return appcore.RunSimulation(cfg, mock)
+}
+
+// FlushQueue retries recordings waiting in the failed-upload queue once.
+func FlushQueue(cfg config.Config) (appcore.QueueResult, error) {
+	return appcore.FlushQueue(cfg)
+}
+
+// Retranscribe uploads the cached audio of a history entry again and records
+// the result as a new entry.
+func Retranscribe(cfg config.Config, store *history.Store, c *cachecrypt.Cipher, audioPath string) (string, error) {
+	return appcore.Retranscribe(cfg, store, c, audioPath)
+}
+
+// InputDevices lists the devices INPUT_DEVICE can select.
+func InputDevices() ([]string, error) {
+	return appcore.InputDevices()
+}
+
+// DeviceInfo describes a device that can be recorded from.
+type DeviceInfo = record.DeviceInfo
+
+// InputDeviceDetails describes the devices INPUT_DEVICE can select, with
+// their index, host API, channels and sample rates.
+func InputDeviceDetails() ([]DeviceInfo, error) {
+	return appcore.InputDeviceDetails()
+}
+
+// RunService runs the engine of the STT Windows service until stop is closed.
+func RunService(cfg config.Config, stop <-chan struct{}) error {
+	return appcore.RunService(cfg, stop)
+}
+
+// RunWatchdog runs record mode in a worker process started from exe with
+// args and restarts it when it crashes.
+func RunWatchdog(cfg config.Config, exe string, args []string) int {
+	return appcore.RunWatchdog(cfg, exe, args)
+}
+
+// Doctor checks the config, ffmpeg and the ASR endpoint for `stt doctor`.
+func Doctor(ctx context.Context, cfg config.Config) []appcore.DoctorCheck {
+	return appcore.Doctor(ctx, cfg)
+}
@@ -0,0 +1,221 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package appcoretest provides fakes of the recorder, converter,
+// transcriber and paste step of an appcore.Runtime, for tests that run the
+// hotkey to paste flow without a microphone, ffmpeg or the network.
+package appcoretest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"stt/internal/appcore"
+	"stt/pkg/asr"
+	"stt/pkg/audio/ffmpeg"
+	"stt/pkg/record"
+)
+
+var (
+	_ appcore.Recorder    = (*Recorder)(nil)
+	_ appcore.Converter   = (*Converter)(nil)
+	_ appcore.Transcriber = (*Transcriber)(nil)
+)
+
+// Recorder is a fake appcore.Recorder. Stop writes a placeholder WAV file
+// into Dir and returns it as the recording.
+type Recorder struct {
+	Dir string
+	// Duration is the length reported for every recording.
+	Duration time.Duration
+	// StartErr and StopErr, when set, are returned by Start and Stop.
+	StartErr error
+	StopErr  error
+
+	mu    sync.Mutex
+	state record.State
+	count int
+}
+
+// Start begins a recording.
+func (r *Recorder) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.StartErr != nil {
+		return r.StartErr
+	}
+	if r.state != record.StateIdle {
+		return fmt.Errorf("already recording")
+	}
+	r.state = record.StateRecording
+	return nil
+}
+
+// Stop ends the recording and returns its file.
+func (r *Recorder) Stop() (record.Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.state == record.StateIdle {
+		return record.Result{}, fmt.Errorf("not recording")
+	}
+	r.state = record.StateIdle
+	if r.StopErr != nil {
+		return record.Result{}, r.StopErr
+	}
+	r.count++
+	path := filepath.Join(r.Dir, fmt.Sprintf("RecordTemp_fake%d.wav", r.count))
+	if err := os.WriteFile(path, []byte("RIFF"), 0644); err != nil {
+		return record.Result{}, err
+	}
+	return record.Result{WavPath: path, Duration: r.Duration}, nil
+}
+
+// Cancel ends the recording and discards it.
+func (r *Recorder) Cancel() (record.Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.state == record.StateIdle {
+		return record.Result{}, fmt.Errorf("not recording")
+	}
+	r.state = record.StateIdle
+	return record.Result{Canceled: true}, nil
+}
+
+// TogglePause pauses or resumes the recording.
+func (r *Recorder) TogglePause() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch r.state {
+	case record.StateRecording:
+		r.state = record.StatePaused
+	case record.StatePaused:
+		r.state = record.StateRecording
+	default:
+		return fmt.Errorf("not recording")
+	}
+	return nil
+}
+
+// State returns the recorder state.
+func (r *Recorder) State() record.State {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+// Converter is a fake appcore.Converter that copies its input to the
+// output path.
+type Converter struct {
+	// Err, when set, fails every conversion.
+	Err error
+
+	mu     sync.Mutex
+	inputs []string
+}
+
+// Convert copies inPath to outPath and reports it done.
+func (c *Converter) Convert(ctx context.Context, opts ffmpeg.Options, inPath, outPath string, rate int, report func(float64)) error {
+	c.mu.Lock()
+	c.inputs = append(c.inputs, inPath)
+	c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	b, err := os.ReadFile(inPath)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, b, 0644); err != nil {
+		return err
+	}
+	if report != nil {
+		report(1)
+	}
+	return nil
+}
+
+// Inputs returns the files converted so far.
+func (c *Converter) Inputs() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.inputs)
+}
+
+// Transcriber is a fake appcore.Transcriber that answers every upload with
+// Text.
+type Transcriber struct {
+	Text string
+	// Err, when set, fails every upload.
+	Err error
+
+	mu    sync.Mutex
+	paths []string
+}
+
+// TranscribeAttempts returns Text in a JSON response after one attempt.
+func (t *Transcriber) TranscribeAttempts(ctx context.Context, path string) (string, []byte, int, error) {
+	t.mu.Lock()
+	t.paths = append(t.paths, path)
+	t.mu.Unlock()
+	if t.Err != nil {
+		return "", nil, 1, t.Err
+	}
+	raw, err := json.Marshal(map[string]string{"text": t.Text})
+	if err != nil {
+		return "", nil, 1, err
+	}
+	return t.Text, raw, 1, nil
+}
+
+// Probe reports a reachable endpoint that accepts the credentials.
+func (t *Transcriber) Probe(ctx context.Context) asr.ProbeResult {
+	return asr.ProbeResult{Reachable: true, StatusCode: 200, AuthOK: true}
+}
+
+// Paths returns the files uploaded so far.
+func (t *Transcriber) Paths() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.Clone(t.paths)
+}
+
+// Paster is a fake paste step; pass its Paste method as appcore.Deps.Paste.
+type Paster struct {
+	// Err, when set, fails every paste.
+	Err error
+
+	mu    sync.Mutex
+	texts []string
+}
+
+// Paste records text as pasted.
+func (p *Paster) Paste(text string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Err != nil {
+		return p.Err
+	}
+	p.texts = append(p.texts, text)
+	return nil
+}
+
+// Texts returns the texts pasted so far.
+func (p *Paster) Texts() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.texts)
+}
@@ -0,0 +1,371 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"stt/internal/cachecrypt"
+	"stt/internal/config"
+	"stt/internal/history"
+	"stt/internal/i18n"
+	"stt/internal/transcript"
+	"stt/pkg/audio/ffmpeg"
+)
+
+// fileResult is the outcome of transcribing one file of several.
+type fileResult struct {
+	file    string
+	text    string
+	raw     []byte
+	latency time.Duration
+	// duration is the length of the file, or 0 when it is unknown.
+	duration time.Duration
+	err      error
+}
+
+// transcribeFiles transcribes files like transcribeFile and passes each
+// result to fn, in the order of files. Up to CONVERT_WORKERS files are
+// converted at once ahead of the uploads, which start in the order of files,
+// up to MAX_CONCURRENT_UPLOADS at once, or one at a time without a limit. A
+// converted file waits
+// on disk until its upload, so no more files than both allow are there at
+// once. Once ctx is done the remaining files are dropped without calling fn.
+func transcribeFiles(ctx context.Context, cfg config.Config, asrClient Transcriber, store *history.Store, cacheCipher *cachecrypt.Cipher, tempDir string, files []string, fn func(fileResult)) {
+	converting := make(chan struct{}, max(1, cfg.ConvertWorkers))
+	uploading := make(chan struct{}, uploadParallelism(cfg))
+	slots := make(chan struct{}, cap(converting)+cap(uploading)-1)
+	results := make([]chan fileResult, len(files))
+	for i := range results {
+		results[i] = make(chan fileResult, 1)
+	}
+	// turns[i] is closed once the files before file i started uploading.
+	turns := make([]chan struct{}, len(files)+1)
+	for i := range turns {
+		turns[i] = make(chan struct{})
+	}
+	close(turns[0])
+	go func() {
+		for i, f := range files {
+			slots <- struct{}{}
+			go func() {
+				defer func() { <-slots }()
+				progress := newProgressNotice(cfg)
+				defer progress.done()
+
+				converting <- struct{}{}
+				p := prepareFile(ctx, cfg, tempDir, f, progress)
+				<-converting
+				if !takeTurn(ctx, turns[i], uploading) {
+					close(turns[i+1])
+					if p.out != "" {
+						_ = os.Remove(p.out)
+					}
+					results[i] <- fileResult{file: f, err: ctx.Err()}
+					return
+				}
+				close(turns[i+1])
+				text, raw, latency, err := uploadPrepared(ctx, cfg, asrClient, store, cacheCipher, tempDir, f, f, p, progress)
+				<-uploading
+				results[i] <- fileResult{file: f, text: text, raw: raw, latency: latency, duration: p.total, err: err}
+			}()
+		}
+	}()
+	for i := range files {
+		res := <-results[i]
+		if ctx.Err() != nil {
+			continue
+		}
+		fn(res)
+	}
+}
+
+// takeTurn waits for turn to be closed and then for a free slot in slots,
+// and reports whether it got one before ctx was done.
+func takeTurn(ctx context.Context, turn <-chan struct{}, slots chan struct{}) bool {
+	select {
+	case <-turn:
+	case <-ctx.Done():
+		return false
+	}
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// uploadParallelism is how many files of a batch, or chunks of a file, are
+// uploaded at once.
+func uploadParallelism(cfg config.Config) int {
+	return max(1, cfg.MaxConcurrentUploads)
+}
+
+// fileInputs returns the files -file names: the file itself, the audio and
+// video files directly inside a folder, or the files matching a glob pattern
+// such as recordings\*.m4a. With RECURSIVE, the folder, or each folder the
+// pattern's folder part matches, is searched with its subfolders. The files
+// of a folder or pattern are then picked by INCLUDE_EXTENSIONS and
+// EXCLUDE_EXTENSIONS. batch is false for a single file.
+func fileInputs(cfg config.Config, path string) (files []string, batch bool, err error) {
+	info, err := os.Stat(path)
+	switch {
+	case err == nil && !info.IsDir():
+		return []string{path}, false, nil
+	case err == nil:
+		if files, err = walkFiles(path, cfg.Recursive, extFilter(cfg, folderExts)); err != nil {
+			return nil, true, err
+		}
+	case strings.ContainsAny(path, "*?["):
+		base := filepath.Base(path)
+		if _, err := filepath.Match(base, ""); err != nil {
+			return nil, true, fmt.Errorf("invalid pattern '%s': %w", path, err)
+		}
+		dirs, err := filepath.Glob(filepath.Dir(path))
+		if err != nil {
+			return nil, true, fmt.Errorf("invalid pattern '%s': %w", path, err)
+		}
+		keepExt := extFilter(cfg, nil)
+		keep := func(name string) bool {
+			ok, _ := filepath.Match(base, name)
+			return ok && keepExt(name)
+		}
+		for _, dir := range dirs {
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				continue
+			}
+			found, err := walkFiles(dir, cfg.Recursive, keep)
+			if err != nil {
+				return nil, true, err
+			}
+			files = append(files, found...)
+		}
+	default:
+		return nil, false, fmt.Errorf("file '%s' stat failed: %w", path, err)
+	}
+	if len(files) == 0 {
+		return nil, true, fmt.Errorf("no audio or video files match '%s'", path)
+	}
+	return files, true, nil
+}
+
+// walkFiles returns the files in dir whose name keep accepts, in path order,
+// searching subfolders when recursive. Subfolders that cannot be read are
+// reported and left out.
+func walkFiles(dir string, recursive bool, keep func(name string) bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		switch {
+		case err != nil && path == dir:
+			return err
+		case err != nil:
+			fmt.Printf("[file] %s\n", i18n.Sprintf("skipping %s: %v", path, err))
+			return nil
+		case d.IsDir() && path != dir && !recursive:
+			return filepath.SkipDir
+		case !d.IsDir() && keep(d.Name()):
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// extFilter returns whether a file name has one of INCLUDE_EXTENSIONS, or
+// else of exts, and none of EXCLUDE_EXTENSIONS. nil exts allows any.
+func extFilter(cfg config.Config, exts []string) func(name string) bool {
+	if include := extensionList(cfg.IncludeExtensions); len(include) > 0 {
+		exts = include
+	}
+	exclude := extensionList(cfg.ExcludeExtensions)
+	return func(name string) bool {
+		ext := strings.ToLower(filepath.Ext(name))
+		return (exts == nil || slices.Contains(exts, ext)) && !slices.Contains(exclude, ext)
+	}
+}
+
+// extensionList parses a list of extensions separated by commas or spaces,
+// with or without the dot, e.g. "m4a, .WAV", into [".m4a" ".wav"].
+func extensionList(s string) []string {
+	var exts []string
+	for _, e := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' || r == ' ' }) {
+		exts = append(exts, "."+strings.ToLower(strings.TrimPrefix(e, ".")))
+	}
+	return exts
+}
+
+// durationFilter leaves the files shorter than FILE_MIN_SECONDS or longer
+// than FILE_MAX_SECONDS out of files, keeping those it cannot measure.
+func durationFilter(ctx context.Context, cfg config.Config, files []string) []string {
+	if cfg.FileMinSeconds <= 0 && cfg.FileMaxSeconds <= 0 {
+		return files
+	}
+	fmt.Printf("[file] %s\n", i18n.Sprintf("measuring %d files", len(files)))
+	minimum := time.Duration(cfg.FileMinSeconds) * time.Second
+	maximum := time.Duration(cfg.FileMaxSeconds) * time.Second
+	return slices.DeleteFunc(files, func(f string) bool {
+		d, err := ffmpeg.Duration(ctx, ffmpegOptions(cfg), f)
+		if err != nil {
+			fmt.Printf("[file] %s\n", i18n.Sprintf("cannot measure %s, keeping it: %v", f, err))
+			return false
+		}
+		return d < minimum || maximum > 0 && d > maximum
+	})
+}
+
+// runFileBatch transcribes the files of -file with a folder or pattern,
+// writing each transcript in OUTPUT_FORMAT where batchOutputPath puts it, and
+// ends with a summary. Each file is added to report unless it is nil. It
+// fails when any file did, with ExitPartial when others were transcribed, or
+// else with the exit code of the first failure.
+func runFileBatch(cfg config.Config, asrClient Transcriber, store *history.Store, cacheCipher *cachecrypt.Cipher, tempDir string, files []string, outDir string, report *FileReport) error {
+	if outDir != "" && cfg.OutputTemplate == "" {
+		if err := os.MkdirAll(outDir, 0755); err != nil {
+			return err
+		}
+	}
+	for i, f := range files {
+		if abs, err := filepath.Abs(f); err == nil {
+			files[i] = abs
+		}
+	}
+	total := len(files)
+	progress := newBatchProgress(cfg, total)
+	if kept := durationFilter(context.Background(), cfg, files); len(kept) < len(files) {
+		progress.skipped(len(files) - len(kept))
+		report.skip(len(files) - len(kept))
+		fmt.Printf("[file] %s\n", i18n.Sprintf("leaving out %d files outside FILE_MIN_SECONDS to FILE_MAX_SECONDS", len(files)-len(kept)))
+		files = kept
+	}
+	if cfg.SkipExisting {
+		kept := slices.DeleteFunc(files, func(f string) bool { return alreadyTranscribed(cfg, store, f, outDir) })
+		if len(kept) < len(files) {
+			progress.skipped(len(files) - len(kept))
+			report.skip(len(files) - len(kept))
+			fmt.Printf("[file] %s\n", i18n.Sprintf("skipping %d of %d files that are already transcribed", len(files)-len(kept), total))
+		}
+		files = kept
+	}
+	restore := progress.capture()
+	var failed []string
+	var firstErr error
+	succeeded := 0
+	transcribeFiles(context.Background(), cfg, asrClient, store, cacheCipher, tempDir, files, func(res fileResult) {
+		out := batchOutputPath(cfg, res.file, outDir, res.raw)
+		err := res.err
+		var b []byte
+		if err == nil {
+			b, err = formatTranscript(cfg, res.text, res.raw)
+		}
+		if err == nil && cfg.OutputTemplate != "" {
+			err = os.MkdirAll(filepath.Dir(out), 0755)
+		}
+		if err == nil {
+			err = os.WriteFile(out, b, 0644)
+		}
+		result := FileResult{File: res.file, Text: res.text, DurationSeconds: res.duration.Seconds(), LatencyMS: res.latency.Milliseconds()}
+		if err != nil {
+			progress.finished(res, false)
+			report.add(result, err)
+			fmt.Printf("[file] %s %s: %v\n", progress.count(), res.file, err)
+			failed = append(failed, fmt.Sprintf("%s: %v", res.file, err))
+			if firstErr == nil {
+				firstErr = err
+			}
+			return
+		}
+		if res.text != "" {
+			runPostCommand(cfg, "file", res.text, res.latency)
+		}
+		result.Output = out
+		report.add(result, nil)
+		succeeded++
+		progress.finished(res, true)
+		fmt.Printf("[file] %s %s -> %s\n", progress.count(), res.file, out)
+	})
+	restore()
+
+	fmt.Printf("[file] %s\n", progress.summary())
+	for _, f := range failed {
+		fmt.Printf("[file]   %s\n", i18n.Sprintf("failed: %s", f))
+	}
+	if len(failed) > 0 {
+		code := ExitCode(firstErr)
+		if succeeded > 0 {
+			code = ExitPartial
+		}
+		return &ExitError{Code: code, Err: fmt.Errorf("%d of %d files failed", len(failed), total)}
+	}
+	return nil
+}
+
+// alreadyTranscribed reports whether SKIP_EXISTING leaves file out of a
+// batch: its transcript exists where this run would write it, or history
+// records it transcribed. A template with {lang} and no LANGUAGE names the
+// transcript by the response, so only history can tell.
+func alreadyTranscribed(cfg config.Config, store *history.Store, file, outDir string) bool {
+	if _, err := os.Stat(batchOutputPath(cfg, file, outDir, nil)); err == nil {
+		return true
+	}
+	if store == nil {
+		return false
+	}
+	done, err := store.Transcribed(file)
+	if err != nil {
+		fmt.Printf("[file] %s\n", i18n.Sprintf("history lookup for %s failed: %v", file, err))
+	}
+	return done
+}
+
+// batchOutputPath returns where runFileBatch writes the transcript of file:
+// the OUTPUT_TEMPLATE path when set, else next to the file or in outDir.
+// {lang} is LANGUAGE, or the language the response raw reports.
+func batchOutputPath(cfg config.Config, file, outDir string, raw []byte) string {
+	if cfg.OutputTemplate != "" {
+		lang := cfg.Language
+		if lang == "" {
+			lang = transcript.Language(raw)
+		}
+		return transcript.Path(cfg.OutputTemplate, transcript.PathFields{Input: file, Format: outputFormat(cfg), Language: lang})
+	}
+	out := strings.TrimSuffix(file, filepath.Ext(file)) + "." + outputFormat(cfg)
+	if outDir != "" {
+		out = filepath.Join(outDir, filepath.Base(out))
+	}
+	return out
+}
+
+// outputFormat returns OUTPUT_FORMAT, which is also the extension of the
+// transcripts file mode writes.
+func outputFormat(cfg config.Config) string {
+	if cfg.OutputFormat == "" {
+		return "txt"
+	}
+	return strings.ToLower(cfg.OutputFormat)
+}
+
+// formatTranscript returns the transcript text, with the service's response
+// raw, in OUTPUT_FORMAT.
+func formatTranscript(cfg config.Config, text string, raw []byte) ([]byte, error) {
+	var b bytes.Buffer
+	err := transcript.Write(&b, outputFormat(cfg), transcript.Result{Text: text, Response: raw, Speed: cfg.AudioSpeed})
+	return b.Bytes(), err
+}
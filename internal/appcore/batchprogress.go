@@ -0,0 +1,197 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"stt/internal/applog"
+	"stt/internal/config"
+)
+
+// barWidth is the number of cells of the batch progress bar.
+const barWidth = 24
+
+// batchProgress counts the files of a batch run for the per-file lines and
+// the summary. On a terminal, with PROGRESS_BAR on, it also draws a bar
+// below the console output, redrawn as files finish.
+type batchProgress struct {
+	mu    sync.Mutex
+	total int
+	start time.Time
+	done  int
+	fails int
+	skips int
+	audio time.Duration
+
+	bar bool
+	out io.Writer
+	// shown is the length of the bar on screen, and midLine is set while
+	// the output ends in an unfinished line, below which no bar is drawn.
+	shown   int
+	midLine bool
+}
+
+func newBatchProgress(cfg config.Config, total int) *batchProgress {
+	return &batchProgress{
+		total: total,
+		start: time.Now(),
+		bar:   cfg.ProgressBar && isTerminal(applog.Console()),
+	}
+}
+
+// isTerminal reports whether f is a console rather than a file or pipe,
+// which is what CI logs are.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// capture routes stdout through p while the bar is drawn, so that it stays
+// below everything else printed, until the returned function is called.
+func (p *batchProgress) capture() func() {
+	if !p.bar {
+		return func() {}
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		p.bar = false
+		return func() {}
+	}
+	stdout := os.Stdout
+	p.out = stdout
+	os.Stdout = w
+	copied := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(p, r)
+		close(copied)
+	}()
+	// Redraw every second for the elapsed time.
+	stop := make(chan struct{})
+	ticked := make(chan struct{})
+	go func() {
+		defer close(ticked)
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			_, _ = p.Write(nil)
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-ticked
+		os.Stdout = stdout
+		_ = w.Close()
+		<-copied
+		_ = r.Close()
+		p.mu.Lock()
+		p.clear()
+		p.mu.Unlock()
+	}
+}
+
+// Write passes output on to the console, taking the bar away first and
+// drawing it again after each complete line. An empty write redraws it.
+func (p *batchProgress) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	n, err := p.out.Write(b)
+	if len(b) > 0 {
+		p.midLine = !bytes.HasSuffix(b, []byte("\n"))
+	}
+	if !p.midLine {
+		p.draw()
+	}
+	return n, err
+}
+
+func (p *batchProgress) clear() {
+	if p.shown > 0 {
+		fmt.Fprintf(p.out, "\r%s\r", strings.Repeat(" ", p.shown))
+		p.shown = 0
+	}
+}
+
+func (p *batchProgress) draw() {
+	line := p.barLine()
+	fmt.Fprint(p.out, line)
+	p.shown = len(line)
+}
+
+// barLine returns the bar, e.g. "[#########---------------] 3/8 files,
+// 1 failed, 2m10s, about 3m40s left". Callers hold p.mu.
+func (p *batchProgress) barLine() string {
+	finished := p.done + p.fails + p.skips
+	cells := barWidth * finished / max(1, p.total)
+	line := fmt.Sprintf("[%s%s] %d/%d files", strings.Repeat("#", cells), strings.Repeat("-", barWidth-cells), finished, p.total)
+	if p.fails > 0 {
+		line += fmt.Sprintf(", %d failed", p.fails)
+	}
+	elapsed := time.Since(p.start)
+	line += ", " + elapsed.Round(time.Second).String()
+	// Skipped files take no time, so they do not count for the estimate.
+	if processed := p.done + p.fails; processed > 0 && finished < p.total {
+		left := elapsed / time.Duration(processed) * time.Duration(p.total-finished)
+		line += fmt.Sprintf(", about %s left", left.Round(time.Second))
+	}
+	return line
+}
+
+// skipped counts n files as skipped.
+func (p *batchProgress) skipped(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.skips += n
+}
+
+// finished counts a file as succeeded, with its duration, or failed.
+func (p *batchProgress) finished(res fileResult, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !ok {
+		p.fails++
+		return
+	}
+	p.done++
+	p.audio += res.duration
+}
+
+// count returns how many of the files are finished, e.g. "3/8".
+func (p *batchProgress) count() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return fmt.Sprintf("%d/%d", p.done+p.fails+p.skips, p.total)
+}
+
+// summary returns the line that ends a batch run.
+func (p *batchProgress) summary() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := fmt.Sprintf("batch of %d files done in %s: %d succeeded, %d failed, %d skipped",
+		p.total, time.Since(p.start).Round(time.Second), p.done, p.fails, p.skips)
+	if p.audio > 0 {
+		s += fmt.Sprintf(", %.1f minutes of audio", p.audio.Minutes())
+	}
+	return s
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+package appcore
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatchProgressKeepsBarBelowOutput(t *testing.T) {
+	var out strings.Builder
+	p := &batchProgress{total: 2, start: time.Now(), bar: true, out: &out}
+	bar := "[------------------------] 0/2 files, 0s"
+
+	_, _ = p.Write([]byte("[file] conver"))
+	_, _ = p.Write([]byte("ting\n"))
+	if want := "[file] converting\n" + bar; out.String() != want {
+		t.Fatalf("output = %q, want %q", out.String(), want)
+	}
+	out.Reset()
+	p.finished(fileResult{duration: 90 * time.Second}, true)
+	_, _ = p.Write([]byte("[file] 1/2 a.wav -> a.txt\n"))
+	clear := "\r" + strings.Repeat(" ", len(bar)) + "\r"
+	if got := out.String(); !strings.HasPrefix(got, clear+"[file] 1/2 a.wav -> a.txt\n[############------------] 1/2 files, 0s, about ") {
+		t.Fatalf("output = %q, want the bar cleared and redrawn at 1/2", got)
+	}
+
+	p.finished(fileResult{}, false)
+	if got, want := p.summary(), "batch of 2 files done in 0s: 1 succeeded, 1 failed, 0 skipped, 1.5 minutes of audio"; got != want {
+		t.Fatalf("summary = %q, want %q", got, want)
+	}
+}
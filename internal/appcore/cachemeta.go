@@ -0,0 +1,87 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"stt/internal/atomicfile"
+	"stt/internal/config"
+	"stt/internal/i18n"
+)
+
+// cacheMetaExt is appended to the CACHE_NAME of each kept group of files.
+const cacheMetaExt = ".meta.json"
+
+// cacheMeta describes how a cached recording was produced. It is written as
+// <name>.meta.json next to the cached audio when KEEP_CACHE is on.
+type cacheMeta struct {
+	CreatedAt  time.Time `json:"created_at"`
+	Source     string    `json:"source"`
+	DurationMS int64     `json:"duration_ms,omitempty"`
+	SampleRate int       `json:"sample_rate"`
+	Channels   int       `json:"channels"`
+	Codec      string    `json:"codec"`
+	Container  string    `json:"container"`
+	BitRate    int       `json:"bit_rate_kbps"`
+	LatencyMS  int64     `json:"latency_ms"`
+	Attempts   int       `json:"attempts"`
+	Provider   string    `json:"provider"`
+	Model      string    `json:"model"`
+	Language   string    `json:"language"`
+	Machine    string    `json:"machine,omitempty"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+
+	// File names of the cached artifacts, which sit in the same directory.
+	Audio      string `json:"audio,omitempty"`
+	Encoded    string `json:"encoded,omitempty"`
+	Response   string `json:"response,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+
+	text string // written to Transcript; not part of the sidecar
+}
+
+// newCacheMeta collects the metadata of one transcription attempt.
+func newCacheMeta(cfg config.Config, source string, duration, latency time.Duration, attempts int, text string, err error) cacheMeta {
+	return cacheMeta{
+		CreatedAt:  time.Now(),
+		Source:     source,
+		DurationMS: duration.Milliseconds(),
+		SampleRate: cfg.SAMPLING_RATE,
+		Channels:   cfg.Channels,
+		Codec:      cfg.CODECS,
+		Container:  cfg.CONTAINER,
+		BitRate:    cfg.BIT_RATE,
+		LatencyMS:  latency.Milliseconds(),
+		Attempts:   attempts,
+		Provider:   providerName(cfg),
+		Model:      cfg.Model,
+		Language:   cfg.Language,
+		Machine:    config.MachineName(&cfg),
+		Status:     historyStatus(text, err),
+		Error:      errorString(err),
+		text:       text,
+	}
+}
+
+func writeCacheMeta(path string, meta cacheMeta) {
+	b, err := json.MarshalIndent(meta, "", "  ")
+	if err == nil {
+		err = atomicfile.WriteFile(path, b, 0644)
+	}
+	if err != nil {
+		fmt.Printf("[cache] %s\n", i18n.Sprintf("failed to write metadata to %s: %v", path, err))
+	}
+}
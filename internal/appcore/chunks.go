@@ -0,0 +1,341 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"stt/internal/config"
+	"stt/internal/history"
+	"stt/internal/i18n"
+	"stt/internal/transcript"
+	"stt/pkg/audio/ffmpeg"
+)
+
+// chunkSpan is the part of a file one chunk covers. A zero Length runs to
+// the end of the file.
+type chunkSpan struct {
+	Start  time.Duration
+	Length time.Duration
+}
+
+// fileChunking returns the duration of the file at path and the length of
+// the chunks to transcribe it in, or a zero length to upload it whole. size
+// is the size of the converted file, or 0 before converting.
+func fileChunking(ctx context.Context, cfg config.Config, path string, size int64) (time.Duration, time.Duration) {
+	if cfg.FileChunkSeconds <= 0 && (cfg.MaxUploadMB <= 0 || size == 0) {
+		return 0, 0
+	}
+	total, err := ffmpeg.Duration(ctx, ffmpegOptions(cfg), path)
+	if err != nil {
+		fmt.Printf("[file] %s\n", i18n.Sprintf("cannot measure %s, uploading it whole: %v", path, err))
+		return 0, 0
+	}
+	return total, chunkLength(cfg, total, size)
+}
+
+// chunkLength returns the length of the chunks a file of duration total
+// is transcribed in: FILE_CHUNK_SECONDS when it is longer than that, and
+// shorter when its converted size exceeds MAX_UPLOAD_MB. It returns 0 when
+// the file can be uploaded whole.
+func chunkLength(cfg config.Config, total time.Duration, size int64) time.Duration {
+	var length time.Duration
+	if cfg.FileChunkSeconds > 0 && total > time.Duration(cfg.FileChunkSeconds)*time.Second {
+		length = time.Duration(cfg.FileChunkSeconds) * time.Second
+	}
+	if limit := int64(cfg.MaxUploadMB) << 20; limit > 0 && size > limit {
+		// A tenth to spare for the overlap and variable bitrates.
+		fit := time.Duration(float64(total) * float64(limit) / float64(size) * 0.9).Round(time.Second)
+		if length == 0 || fit < length {
+			length = max(fit, time.Second)
+		}
+	}
+	return length
+}
+
+// chunkSpans splits total into chunks of length, each overlapping the next
+// by overlap. The last chunk runs to the end, so a duration reported a bit
+// short loses nothing.
+func chunkSpans(total, length, overlap time.Duration) []chunkSpan {
+	overlap = min(overlap, length/2)
+	var spans []chunkSpan
+	for start := time.Duration(0); ; start += length - overlap {
+		if start+length >= total {
+			return append(spans, chunkSpan{Start: start})
+		}
+		spans = append(spans, chunkSpan{Start: start, Length: length})
+	}
+}
+
+// transcribeChunks transcribes the file at inputPath in overlapping chunks of
+// length and merges their transcripts. Up to MAX_CONCURRENT_UPLOADS chunks
+// are converted and uploaded at once, or one at a time without a limit. The
+// whole file is recorded in history once; the converted chunks are not kept
+// in the cache.
+func transcribeChunks(ctx context.Context, cfg config.Config, asrClient Transcriber, store *history.Store, tempDir, inputPath, audioPath string, total, length time.Duration, progress *progressNotice) (string, []byte, time.Duration, error) {
+	spans := chunkSpans(total, length, time.Duration(cfg.FileChunkOverlap)*time.Second)
+	fmt.Printf("[file] %s\n", i18n.Sprintf("%s is %s long, transcribing it in %d chunks", inputPath, total.Round(time.Second), len(spans)))
+	texts := make([]string, len(spans))
+	responses := make([][]byte, len(spans))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu        sync.Mutex
+		latency   time.Duration
+		err       error
+		convErr   bool
+		converted = make([]float64, len(spans))
+		wg        sync.WaitGroup
+	)
+	// fail keeps the first error, which cancels the other chunks.
+	fail := func(e error, converting bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err == nil {
+			err, convErr = e, converting
+			cancel()
+		}
+	}
+	slots := make(chan struct{}, uploadParallelism(cfg))
+	for i, span := range spans {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			report := func(f float64) {
+				mu.Lock()
+				converted[i] = f
+				var sum float64
+				for _, c := range converted {
+					sum += c
+				}
+				mu.Unlock()
+				progress.converting(sum / float64(len(spans)))
+			}
+			text, raw, took, cerr := transcribeChunk(ctx, cfg, asrClient, tempDir, inputPath, span, report, progress)
+			mu.Lock()
+			latency += took
+			mu.Unlock()
+			if cerr != nil {
+				fail(fmt.Errorf("chunk %d/%d: %w", i+1, len(spans), cerr), took == 0)
+				return
+			}
+			fmt.Printf("[file] %s\n", i18n.Sprintf("transcribed chunk %d/%d", i+1, len(spans)))
+			texts[i], responses[i] = text, raw
+		}()
+	}
+	wg.Wait()
+	if err == nil {
+		// Canceled by the caller before a chunk failed.
+		err = ctx.Err()
+	}
+	if convErr {
+		// Like a failed conversion of a whole file, before any upload.
+		return "", nil, 0, err
+	}
+
+	text := ""
+	var raw []byte
+	if err == nil {
+		text = mergeTranscripts(texts)
+		if segs := chunkSegments(spans, responses, cfg.AudioSpeed); len(segs) > 0 {
+			raw = transcript.Response(text, segs)
+		}
+	}
+	recordHistory(store, cfg, history.Entry{
+		Source:    "file",
+		Duration:  total,
+		Text:      text,
+		Latency:   latency,
+		AudioPath: audioPath,
+		Status:    historyStatus(text, err),
+		Error:     errorString(err),
+		Response:  raw,
+	})
+	if err != nil {
+		playCue(cfg, uploadFailedCue(cfg))
+		if cfg.Notification {
+			notifyFailure(i18n.T("Upload failed"), err)
+		}
+		return "", nil, latency, err
+	}
+	return text, raw, latency, nil
+}
+
+// transcribeChunk converts the part of the file at inputPath in span and
+// uploads it, returning the transcript, the response and the upload latency,
+// which is 0 only when the conversion failed.
+func transcribeChunk(ctx context.Context, cfg config.Config, asrClient Transcriber, tempDir, inputPath string, span chunkSpan, report func(float64), progress *progressNotice) (string, []byte, time.Duration, error) {
+	opts := fileOptions(cfg)
+	opts.Start, opts.Length = span.Start, span.Length
+	out := tempOutputPath(tempDir, config.ContainerExt(cfg.CONTAINER))
+	defer os.Remove(out)
+	if err := ffmpeg.ConvertContext(ctx, opts, inputPath, out, cfg.SAMPLING_RATE, report); err != nil {
+		return "", nil, 0, withExitCode(ExitConvert, err)
+	}
+	start := time.Now()
+	text, raw, _, err := upload(withProgress(ctx, progress), asrClient, out)
+	return text, raw, time.Since(start), withExitCode(ExitUpload, err)
+}
+
+// chunkSegments joins the timed segments in the responses of the chunks in
+// spans into those of the whole file, on the timeline of the audio as
+// uploaded, i.e. sped up by speed. Of the segments two chunks both heard,
+// each keeps those starting on its side of the middle of their overlap.
+func chunkSegments(spans []chunkSpan, responses [][]byte, speed float64) []transcript.Segment {
+	if speed <= 0 {
+		speed = 1
+	}
+	uploaded := func(d time.Duration) time.Duration { return time.Duration(float64(d) / speed) }
+	// cut returns where chunk i hands over to the next.
+	cut := func(i int) time.Duration {
+		if i+1 >= len(spans) {
+			return time.Duration(math.MaxInt64)
+		}
+		return uploaded((spans[i+1].Start + spans[i].Start + spans[i].Length) / 2)
+	}
+	var segs []transcript.Segment
+	from := time.Duration(0)
+	for i, span := range spans {
+		offset, to := uploaded(span.Start), cut(i)
+		for _, s := range transcript.Segments(responses[i]) {
+			s.Start += offset
+			s.End += offset
+			if s.Start >= from && s.Start < to {
+				segs = append(segs, s)
+			}
+		}
+		from = to
+	}
+	return segs
+}
+
+// overlapWindow is how many words at the end of one chunk's transcript and
+// the start of the next are searched for the text both chunks heard; a few
+// seconds of overlap hold far fewer. minOverlapWords is the shortest run of
+// words taken as that text rather than a chance repeat.
+const (
+	overlapWindow   = 80
+	minOverlapWords = 3
+)
+
+// mergeTranscripts joins the transcripts of consecutive overlapping chunks.
+// Where the end of one and the start of the next share a run of words, the
+// run is kept once: the first transcript up to its end, the next from after
+// it, which also drops words cut off at either chunk boundary.
+func mergeTranscripts(texts []string) string {
+	merged := ""
+	for _, text := range texts {
+		merged = mergeTwo(merged, strings.TrimSpace(text))
+	}
+	return merged
+}
+
+func mergeTwo(a, b string) string {
+	ta, tb := transcriptWords(a), transcriptWords(b)
+	ta = ta[max(0, len(ta)-overlapWindow):]
+	tb = tb[:min(len(tb), overlapWindow)]
+	// Longest run of words common to both, ending at ta[endA] and tb[endB].
+	best, endA, endB := 0, 0, 0
+	prev := make([]int, len(tb)+1)
+	for i := range ta {
+		cur := make([]int, len(tb)+1)
+		for j := range tb {
+			if ta[i].norm == tb[j].norm {
+				cur[j+1] = prev[j] + 1
+				if cur[j+1] > best {
+					best, endA, endB = cur[j+1], i, j
+				}
+			}
+		}
+		prev = cur
+	}
+	if best < minOverlapWords {
+		return joinTranscripts(a, b)
+	}
+	return a[:ta[endA].end] + b[tb[endB].end:]
+}
+
+// joinTranscripts joins a and b with a space, or directly between Chinese or
+// Japanese text, which has none.
+func joinTranscripts(a, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	last, _ := utf8.DecodeLastRuneInString(a)
+	first, _ := utf8.DecodeRuneInString(b)
+	if isWide(last) || isWide(first) {
+		return a + b
+	}
+	return a + " " + b
+}
+
+// transcriptWord is a word of a transcript, lowercased, and the offset in
+// the transcript where it ends.
+type transcriptWord struct {
+	norm string
+	end  int
+}
+
+// transcriptWords splits s into words, ignoring punctuation and case. Han,
+// Hiragana and Katakana characters are written without spaces and count as
+// a word each.
+func transcriptWords(s string) []transcriptWord {
+	var words []transcriptWord
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			words = append(words, transcriptWord{norm: strings.ToLower(s[start:end]), end: end})
+			start = -1
+		}
+	}
+	for i, r := range s {
+		switch {
+		case isCJK(r):
+			flush(i)
+			words = append(words, transcriptWord{norm: string(r), end: i + utf8.RuneLen(r)})
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'':
+			if start < 0 {
+				start = i
+			}
+		default:
+			flush(i)
+		}
+	}
+	flush(len(s))
+	return words
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// isWide reports whether r is a CJK character or full-width punctuation.
+func isWide(r rune) bool {
+	return isCJK(r) || r >= 0x3000 && r <= 0x303f || r >= 0xff00 && r <= 0xffef
+}
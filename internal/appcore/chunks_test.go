@@ -0,0 +1,102 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"stt/internal/config"
+)
+
+func TestChunkSpansOverlapAndRunToTheEnd(t *testing.T) {
+	got := chunkSpans(25*time.Minute, 10*time.Minute, 5*time.Second)
+	want := []chunkSpan{
+		{Start: 0, Length: 10 * time.Minute},
+		{Start: 10*time.Minute - 5*time.Second, Length: 10 * time.Minute},
+		{Start: 20*time.Minute - 10*time.Second},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("chunkSpans = %v, want %v", got, want)
+	}
+}
+
+func TestChunkLengthFromDurationAndSize(t *testing.T) {
+	cfg := config.Config{FileChunkSeconds: 600, MaxUploadMB: 25}
+	if got := chunkLength(cfg, 5*time.Minute, 1<<20); got != 0 {
+		t.Fatalf("short small file: chunkLength = %s, want 0", got)
+	}
+	if got := chunkLength(cfg, 30*time.Minute, 0); got != 10*time.Minute {
+		t.Fatalf("long file: chunkLength = %s, want 10m", got)
+	}
+	// 100 MB for 8 minutes fits 25 MB in 2 minutes, less a tenth.
+	if got := chunkLength(cfg, 8*time.Minute, 100<<20); got != 108*time.Second {
+		t.Fatalf("large file: chunkLength = %s, want 1m48s", got)
+	}
+}
+
+func TestMergeTranscriptsDropsOverlap(t *testing.T) {
+	tests := []struct {
+		name  string
+		texts []string
+		want  string
+	}{
+		{
+			name:  "words",
+			texts: []string{"We met on Monday. The budget is due next", "the budget is due next week, said Anna."},
+			want:  "We met on Monday. The budget is due next week, said Anna.",
+		},
+		{
+			name:  "cut-off words",
+			texts: []string{"please send the final report to the tea", "nal report to the team before noon"},
+			want:  "please send the final report to the team before noon",
+		},
+		{
+			name:  "chinese",
+			texts: []string{"今天我们讨论预算问题，下周一", "讨论预算问题，下周一之前提交。"},
+			want:  "今天我们讨论预算问题，下周一之前提交。",
+		},
+		{
+			name:  "no overlap",
+			texts: []string{"first part.", "", "second part."},
+			want:  "first part. second part.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeTranscripts(tt.texts); got != tt.want {
+				t.Fatalf("mergeTranscripts = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunkSegmentsSplitAtTheMiddleOfTheOverlap(t *testing.T) {
+	spans := []chunkSpan{{Start: 0, Length: 60 * time.Second}, {Start: 50 * time.Second}}
+	// Audio sped up 2x: the chunks upload 30s and 25s, the second from 25s,
+	// and hand over at 27.5s.
+	responses := [][]byte{
+		[]byte(`{"segments":[{"start":0,"end":15,"text":"one"},{"start":15,"end":26.5,"text":"two"},{"start":28,"end":30,"text":"three"}]}`),
+		[]byte(`{"segments":[{"start":0,"end":1.5,"text":"two"},{"start":3,"end":5,"text":"three"},{"start":5,"end":10,"text":"four"}]}`),
+	}
+	var got []string
+	for _, s := range chunkSegments(spans, responses, 2) {
+		got = append(got, fmt.Sprintf("%s@%s", s.Text, s.Start))
+	}
+	want := []string{"one@0s", "two@15s", "three@28s", "four@30s"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("chunkSegments = %v, want %v", got, want)
+	}
+}
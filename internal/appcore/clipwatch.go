@@ -0,0 +1,114 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"stt/internal/clipboard"
+	"stt/internal/config"
+	"stt/internal/i18n"
+	"stt/internal/notify"
+	"stt/internal/shellmenu"
+	"stt/internal/urlscheme"
+)
+
+// clipboardPoll is how often the clipboard watcher checks whether the
+// clipboard changed.
+const clipboardPoll = time.Second
+
+// clipboardOffers caps the notifications for one copy of many files.
+const clipboardOffers = 3
+
+// startClipboardWatch offers to transcribe audio and video files copied to
+// the clipboard while CLIPBOARD_WATCH is on. The offer is a notification
+// whose Transcribe button opens an stt://transcribe link, which sends the
+// file back to this instance. The returned func stops the watcher.
+func (r *Runtime) startClipboardWatch(cfg config.Config) func() {
+	if !cfg.ClipboardWatch || r.serviceMode {
+		return func() {}
+	}
+	if command, err := urlscheme.Installed(); err == nil && command == "" {
+		fmt.Println("[clipboard] " + i18n.T("stt:// links are not registered; run `stt protocol install` for the Transcribe button to work"))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		seq := clipboard.Sequence()
+		// last keeps the files of the previous offer, since pasting a
+		// transcript restores the clipboard, which counts as a change.
+		var last []string
+		ticker := time.NewTicker(clipboardPoll)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			n := clipboard.Sequence()
+			if n == seq {
+				continue
+			}
+			seq = n
+			files, err := clipboard.Files()
+			if err != nil {
+				fmt.Printf("[clipboard] %v\n", err)
+				continue
+			}
+			files = mediaFiles(files)
+			if len(files) == 0 || slices.Equal(files, last) {
+				continue
+			}
+			last = files
+			offerTranscription(files)
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// mediaFiles returns the existing audio and video files among paths.
+func mediaFiles(paths []string) []string {
+	var files []string
+	for _, p := range paths {
+		if !slices.Contains(shellmenu.Exts, strings.ToLower(filepath.Ext(p))) {
+			continue
+		}
+		if info, err := os.Stat(p); err == nil && info.Mode().IsRegular() {
+			files = append(files, p)
+		}
+	}
+	return files
+}
+
+// offerTranscription shows a notification with a Transcribe button for
+// each of the first clipboardOffers files.
+func offerTranscription(files []string) {
+	for i, f := range files {
+		if i == clipboardOffers {
+			fmt.Printf("[clipboard] %s\n", i18n.Sprintf("%d more file(s) not offered", len(files)-i))
+			return
+		}
+		fmt.Printf("[clipboard] %s\n", i18n.Sprintf("offering to transcribe %s", f))
+		notify.NotifyAction("STT", i18n.Sprintf("Transcribe %s?", filepath.Base(f)), i18n.T("Transcribe"), urlscheme.Link("transcribe", f))
+	}
+}
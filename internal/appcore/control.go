@@ -0,0 +1,142 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"stt/internal/crash"
+	"stt/internal/i18n"
+	"stt/internal/metrics"
+	"stt/internal/notify"
+)
+
+// StatusReport is the status Control returns for "status", which
+// `stt ctl status -json` prints.
+type StatusReport struct {
+	State   State  `json:"state"`
+	Message string `json:"message"`
+	Error   string `json:"error,omitempty"`
+	// ElapsedSeconds is the time recorded so far while recording or paused.
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	Profile        string  `json:"profile"`
+	// LatencySeconds is how long the last successful transcription took,
+	// or 0.
+	LatencySeconds float64 `json:"latency_seconds"`
+}
+
+// Control answers a request from `stt ctl`. "status" returns a
+// StatusReport as JSON and "stats" a metrics.Snapshot; "start", "stop", "toggle", "pause", "resume" and
+// "cancel" act like the hotkeys and return once the action is under way, or
+// an error when the state does not allow it. "transcribe <path>" starts
+// transcribing the file at path into a .txt next to it.
+func (r *Runtime) Control(request string) (string, error) {
+	if path, ok := strings.CutPrefix(request, "transcribe "); ok {
+		if _, err := os.Stat(path); err != nil {
+			return "", err
+		}
+		go r.transcribeNextTo(path)
+		return "", nil
+	}
+	s := r.Status()
+	active := s.State == StateRecording || s.State == StatePaused
+	// The actions run on their own goroutines since stopping waits for the
+	// upload, and Control runs on the tray's window thread.
+	switch request {
+	case "status":
+		b, err := json.Marshal(StatusReport{
+			State:          s.State,
+			Message:        s.Message,
+			Error:          s.Error,
+			ElapsedSeconds: s.Elapsed.Seconds(),
+			Profile:        s.Profile,
+			LatencySeconds: s.Latency.Seconds(),
+		})
+		return string(b), err
+	case "stats":
+		b, err := json.Marshal(metrics.Read())
+		return string(b), err
+	case "toggle":
+		go r.HandleAction(1)
+	case "start":
+		if s.State != StateIdle && s.State != StateError {
+			return "", fmt.Errorf("cannot start recording while %s", s.State)
+		}
+		go r.handleActionIn(1, StateIdle, StateError)
+	case "stop":
+		if !active {
+			return "", fmt.Errorf("not recording")
+		}
+		go r.handleActionIn(1, StateRecording, StatePaused)
+	case "pause":
+		if s.State != StateRecording {
+			return "", fmt.Errorf("not recording")
+		}
+		go r.handleActionIn(2, StateRecording)
+	case "resume":
+		if s.State != StatePaused {
+			return "", fmt.Errorf("not paused")
+		}
+		go r.handleActionIn(2, StatePaused)
+	case "cancel":
+		if !active {
+			return "", fmt.Errorf("not recording")
+		}
+		go r.handleActionIn(3, StateRecording, StatePaused)
+	default:
+		return "", fmt.Errorf("unknown request %q", request)
+	}
+	return "", nil
+}
+
+// transcribeNextTo transcribes the file at path with the running config and
+// writes the transcript to a .txt next to it, like `stt transcribe`.
+func (r *Runtime) transcribeNextTo(path string) {
+	defer crash.Recover("transcribe")
+	r.mu.Lock()
+	cfg := r.cfg
+	asrClient := r.asrClient
+	store := r.history
+	cacheCipher := r.cacheCipher
+	tempDir := r.tempDir
+	r.mu.Unlock()
+
+	text, _, latency, err := transcribeFile(context.Background(), cfg, asrClient, store, cacheCipher, tempDir, path, path)
+	if err != nil {
+		fmt.Printf("[transcribe] %s: %v\n", path, err)
+		// transcribeFile reports failed uploads itself; a failed conversion
+		// returns before the upload, without a latency.
+		if latency == 0 && cfg.Notification {
+			notifyFailure(i18n.T("FFmpeg conversion failed"), err)
+		}
+		return
+	}
+	if err := os.WriteFile(transcriptPath(path), []byte(text), 0644); err != nil {
+		fmt.Printf("[transcribe] %s: %v\n", path, err)
+		if cfg.Notification {
+			notifyFailure(i18n.Sprintf("file mode failed: %v", err), err)
+		}
+		return
+	}
+	if text != "" {
+		runPostCommand(cfg, "file", text, latency)
+	}
+	msg := i18n.Sprintf("transcript written to %s", transcriptPath(path))
+	fmt.Printf("[transcribe] %s\n", msg)
+	if cfg.Notification {
+		notify.Notify("STT", msg)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime/debug"
+
+	"stt/internal/applog"
+	"stt/internal/config"
+	"stt/internal/crash"
+	"stt/internal/i18n"
+	"stt/internal/notify"
+)
+
+// setupCrashReports writes crash reports next to the log, or to the cache
+// dir without one, with the credentials in cfg masked, and notifies the
+// user of each.
+func setupCrashReports(cfg config.Config) {
+	dir := config.TempDir(&cfg)
+	if p := applog.Path(); p != "" {
+		dir = filepath.Dir(p)
+	}
+	redacted, err := config.RedactedJSON(&cfg)
+	if err != nil {
+		fmt.Printf("[crash] %s\n", i18n.Sprintf("failed to summarize config: %v", err))
+	}
+	crash.Setup(dir, redacted, func(where, report string) {
+		if !cfg.Notification {
+			return
+		}
+		msg := i18n.T("STT hit an unexpected error and kept running")
+		if report == "" {
+			notify.Notify("STT", msg)
+			return
+		}
+		notify.NotifyFile("STT", msg+"\n"+i18n.T("Click for details"), report)
+	})
+}
+
+// recoverPanic is deferred by the runtime's goroutines: a panic in one is
+// written to a crash report and leaves the runtime in the error state
+// instead of ending the process.
+func (r *Runtime) recoverPanic(where string) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	crash.Handle(where, v, debug.Stack())
+	r.setState(StateError, "Unexpected error", fmt.Errorf("panic in %s: %v", where, v))
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+
+	"stt/internal/clipboard"
+	"stt/internal/config"
+	"stt/pkg/asr"
+	"stt/pkg/audio/ffmpeg"
+	"stt/pkg/record"
+)
+
+// Recorder captures audio from the microphone. *record.Recorder implements
+// it.
+type Recorder interface {
+	Start(ctx context.Context) error
+	Stop() (record.Result, error)
+	Cancel() (record.Result, error)
+	TogglePause() error
+	State() record.State
+}
+
+// Converter converts a recording into the upload format. The default calls
+// ffmpeg, or the built-in libav in GUI builds.
+type Converter interface {
+	Convert(ctx context.Context, opts ffmpeg.Options, inPath, outPath string, rate int, report func(float64)) error
+}
+
+// Transcriber uploads audio to the ASR service and returns the text, the raw
+// response and the number of attempts; Probe checks that the service is
+// reachable. *asr.Client implements it.
+type Transcriber interface {
+	TranscribeAttempts(ctx context.Context, path string) (string, []byte, int, error)
+	Probe(ctx context.Context) asr.ProbeResult
+}
+
+// Deps replaces the parts of a Runtime that need a microphone, ffmpeg, the
+// network or a focused window, so that the hotkey to paste flow can run in
+// tests; see package appcoretest for fakes. Nil fields keep the real ones,
+// which are rebuilt from the config on Reload. Replacements are kept.
+type Deps struct {
+	Recorder    Recorder
+	Converter   Converter
+	Transcriber Transcriber
+	// Paste puts text into the focused window.
+	Paste func(text string) error
+}
+
+// ffmpegConverter is the default Converter.
+type ffmpegConverter struct{}
+
+func (ffmpegConverter) Convert(ctx context.Context, opts ffmpeg.Options, inPath, outPath string, rate int, report func(float64)) error {
+	return ffmpeg.ConvertContext(ctx, opts, inPath, outPath, rate, report)
+}
+
+// withDefaults fills the nil fields of d with the real implementations,
+// except Recorder and Transcriber, which depend on the config.
+func (d Deps) withDefaults() Deps {
+	if d.Converter == nil {
+		d.Converter = ffmpegConverter{}
+	}
+	if d.Paste == nil {
+		d.Paste = clipboard.PasteText
+	}
+	return d
+}
+
+// transcriber returns the Transcriber given to NewRuntimeWith, or a new ASR
+// client for cfg.
+func (d Deps) transcriber(cfg config.Config) (Transcriber, error) {
+	if d.Transcriber != nil {
+		return d.Transcriber, nil
+	}
+	c, err := newASRClient(cfg, newHTTPClient(cfg))
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
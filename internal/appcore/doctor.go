@@ -0,0 +1,72 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"stt/internal/config"
+	"stt/internal/redact"
+	"stt/pkg/audio/ffmpeg"
+)
+
+// DoctorCheck is the outcome of one check of `stt doctor`.
+type DoctorCheck struct {
+	// Name is the English name of what was checked.
+	Name string
+	// Detail describes what was found, e.g. the ffmpeg version.
+	Detail string
+	Err    error
+}
+
+// Doctor checks what record mode needs besides a microphone: a valid
+// config, an ffmpeg that converts recordings to CODECS in CONTAINER, and a
+// reachable ASR endpoint. The other checks are skipped when the config is
+// invalid.
+func Doctor(ctx context.Context, cfg config.Config) []DoctorCheck {
+	if err := config.Validate(&cfg); err != nil {
+		return []DoctorCheck{{Name: "config", Err: err}}
+	}
+	checks := []DoctorCheck{{Name: "config", Detail: "ok"}}
+	config.InitCacheDir(&cfg)
+	opts := ffmpegOptions(cfg)
+
+	version, verr := ffmpeg.Version(ctx, opts)
+	cerr := checkFFmpeg(ctx, cfg, opts, config.TempDir(&cfg))
+	switch {
+	case verr == nil:
+		checks = append(checks, DoctorCheck{Name: "ffmpeg", Detail: version})
+	case cerr == nil:
+		// Builds encoding Opus themselves convert recordings without it.
+		checks = append(checks, DoctorCheck{Name: "ffmpeg", Detail: fmt.Sprintf("not found; recordings are converted without it, files need it (%v)", verr)})
+	default:
+		checks = append(checks, DoctorCheck{Name: "ffmpeg", Err: verr})
+	}
+	// Without ffmpeg the conversion fails for the same reason.
+	if verr == nil || cerr == nil {
+		format := fmt.Sprintf("%s in .%s", cfg.CODECS, config.ContainerExt(cfg.CONTAINER))
+		checks = append(checks, DoctorCheck{Name: "conversion", Detail: format, Err: cerr})
+	}
+
+	client, err := newASRClient(cfg, newHTTPClient(cfg))
+	if err != nil {
+		return append(checks, DoctorCheck{Name: "ASR endpoint", Detail: redact.Text(cfg.APIEndpoint), Err: err})
+	}
+	res := client.Probe(ctx)
+	check := DoctorCheck{Name: "ASR endpoint", Detail: redact.Text(cfg.APIEndpoint) + ": " + res.Summary()}
+	if !res.OK() {
+		check.Err = errors.New(res.Summary())
+	}
+	return append(checks, check)
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import "errors"
+
+// Exit codes of file mode, for scripts that run -file.
+const (
+	ExitOK = 0
+	// ExitFailure is any other error, such as a missing input file or an
+	// output file that cannot be written.
+	ExitFailure = 1
+	// ExitConfig is an invalid config file, environment variable or flag.
+	ExitConfig = 2
+	// ExitConvert is a missing ffmpeg or a failed conversion.
+	ExitConvert = 3
+	// ExitUpload is a failed upload to the ASR service.
+	ExitUpload = 4
+	// ExitPartial is a batch in which some files failed and others were
+	// transcribed.
+	ExitPartial = 5
+)
+
+// ExitError is an error that ends file mode with Code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string { return e.Err.Error() }
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// withExitCode returns err as an ExitError with code, unless it is nil or
+// carries a code already.
+func withExitCode(code int, err error) error {
+	var e *ExitError
+	if err == nil || errors.As(err, &e) {
+		return err
+	}
+	return &ExitError{Code: code, Err: err}
+}
+
+// ExitCode returns the exit code of file mode for err: ExitOK for nil, the
+// code of an ExitError, or else ExitFailure.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var e *ExitError
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ExitFailure
+}
+
+// FileReport is the outcome of file mode that -json prints.
+type FileReport struct {
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+	// Succeeded, Failed and Skipped count the files of a batch, or the
+	// single file.
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Skipped   int          `json:"skipped"`
+	Files     []FileResult `json:"files"`
+}
+
+// FileResult is the outcome for one file of a FileReport.
+type FileResult struct {
+	File string `json:"file"`
+	// Output is the transcript file, or "" when the transcript went to
+	// stdout or the file failed.
+	Output          string  `json:"output,omitempty"`
+	Text            string  `json:"text"`
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+	LatencyMS       int64   `json:"latency_ms,omitempty"`
+	ExitCode        int     `json:"exit_code"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// skip counts n files left out of a batch.
+func (r *FileReport) skip(n int) {
+	if r != nil {
+		r.Skipped += n
+	}
+}
+
+// add records the outcome for a file in the report.
+func (r *FileReport) add(res FileResult, err error) {
+	if r == nil {
+		return
+	}
+	res.ExitCode = ExitCode(err)
+	res.Error = errorString(err)
+	if err != nil {
+		r.Failed++
+	} else {
+		r.Succeeded++
+	}
+	r.Files = append(r.Files, res)
+}
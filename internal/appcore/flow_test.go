@@ -0,0 +1,141 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore_test
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"stt/internal/appcore"
+	"stt/internal/appcore/appcoretest"
+	"stt/internal/config"
+	"stt/internal/hotkey"
+)
+
+// newFakeRuntime returns a runtime whose recorder, converter, transcriber
+// and paste step are fakes.
+func newFakeRuntime(t *testing.T, tr *appcoretest.Transcriber) (*appcore.Runtime, *appcoretest.Converter, *appcoretest.Paster) {
+	t.Helper()
+	return newFakeRuntimeWith(t, tr, nil)
+}
+
+// newFakeRuntimeWith is newFakeRuntime with the config changed by mutate.
+func newFakeRuntimeWith(t *testing.T, tr *appcoretest.Transcriber, mutate func(*config.Config)) (*appcore.Runtime, *appcoretest.Converter, *appcoretest.Paster) {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Notification = false
+	cfg.SoundMute = true
+	if mutate != nil {
+		mutate(&cfg)
+	}
+	conv := &appcoretest.Converter{}
+	paster := &appcoretest.Paster{}
+	r, err := appcore.NewRuntimeWith(cfg, appcore.Deps{
+		Recorder:    &appcoretest.Recorder{Dir: t.TempDir(), Duration: 2 * time.Second},
+		Converter:   conv,
+		Transcriber: tr,
+		Paste:       paster.Paste,
+	})
+	if err != nil {
+		t.Fatalf("NewRuntimeWith failed: %v", err)
+	}
+	t.Cleanup(r.Stop)
+	return r, conv, paster
+}
+
+func TestHotkeyToPasteFlow(t *testing.T) {
+	tr := &appcoretest.Transcriber{Text: "hello world"}
+	r, conv, paster := newFakeRuntime(t, tr)
+
+	if ev := r.ToggleRecording(); ev.State != appcore.StateRecording {
+		t.Fatalf("state after first toggle = %s, want recording", ev.State)
+	}
+	if ev := r.TogglePause(); ev.State != appcore.StatePaused {
+		t.Fatalf("state after pause = %s, want paused", ev.State)
+	}
+	r.TogglePause()
+	ev := r.ToggleRecording()
+	if ev.State != appcore.StateIdle || ev.Message != "Transcription pasted" {
+		t.Fatalf("event after second toggle = %+v, want idle after paste", ev)
+	}
+
+	if got := paster.Texts(); !slices.Equal(got, []string{"hello world"}) {
+		t.Fatalf("pasted %q, want [hello world]", got)
+	}
+	inputs := conv.Inputs()
+	if len(inputs) != 1 || filepath.Ext(inputs[0]) != ".wav" {
+		t.Fatalf("converted %q, want one wav", inputs)
+	}
+	paths := tr.Paths()
+	if len(paths) != 1 || paths[0] != strings.TrimSuffix(inputs[0], ".wav")+"."+config.ContainerExt(r.Config().CONTAINER) {
+		t.Fatalf("uploaded %q, want the converted %s", paths, inputs[0])
+	}
+}
+
+func TestHotkeyFlowUploadFailure(t *testing.T) {
+	tr := &appcoretest.Transcriber{Err: errors.New("service down")}
+	r, _, paster := newFakeRuntime(t, tr)
+
+	r.ToggleRecording()
+	ev := r.ToggleRecording()
+	if ev.State != appcore.StateError || ev.Error != "service down" {
+		t.Fatalf("event = %+v, want upload error", ev)
+	}
+	if got := paster.Texts(); len(got) != 0 {
+		t.Fatalf("pasted %q after a failed upload", got)
+	}
+}
+
+func TestHotkeyFlowCancel(t *testing.T) {
+	tr := &appcoretest.Transcriber{Text: "unused"}
+	r, conv, paster := newFakeRuntime(t, tr)
+
+	r.ToggleRecording()
+	if ev := r.Cancel(); ev.State != appcore.StateIdle {
+		t.Fatalf("state after cancel = %s, want idle", ev.State)
+	}
+	if len(conv.Inputs()) != 0 || len(tr.Paths()) != 0 || len(paster.Texts()) != 0 {
+		t.Fatalf("canceled recording was converted, uploaded or pasted")
+	}
+}
+
+func TestPushToTalkStopsOnRelease(t *testing.T) {
+	tr := &appcoretest.Transcriber{Text: "hello world"}
+	// Without PTT releasing the start hotkey does nothing.
+	r, _, _ := newFakeRuntime(t, tr)
+	r.ToggleRecording()
+	r.HandleAction(hotkey.StartReleased)
+	if ev := r.Snapshot(); ev.State != appcore.StateRecording {
+		t.Fatalf("state after release = %s, want recording", ev.State)
+	}
+
+	r, _, paster := newFakeRuntimeWith(t, tr, func(c *config.Config) { c.PTT = true })
+	r.ToggleRecording()
+	r.HandleAction(hotkey.StartReleased)
+	if ev := r.Snapshot(); ev.State != appcore.StateIdle || ev.Message != "Transcription pasted" {
+		t.Fatalf("event after release = %+v, want idle after paste", ev)
+	}
+	if got := paster.Texts(); !slices.Equal(got, []string{"hello world"}) {
+		t.Fatalf("pasted %q, want [hello world]", got)
+	}
+	// A release when nothing is recording is ignored.
+	r.HandleAction(hotkey.StartReleased)
+	if ev := r.Snapshot(); ev.State != appcore.StateIdle {
+		t.Fatalf("state after idle release = %s, want idle", ev.State)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"stt/internal/cachecrypt"
+	"stt/internal/config"
+	"stt/internal/history"
+	"stt/internal/i18n"
+	"stt/internal/metrics"
+	"stt/pkg/asr"
+	"stt/pkg/audio/ffmpeg"
+)
+
+// historyEnabled reports whether transcripts are recorded in the history
+// database. The database lives in the cache directory, so one must be set.
+func historyEnabled(cfg config.Config) bool {
+	return cfg.History && cfg.CacheDir != ""
+}
+
+// openCacheCipher loads the cache encryption key when CACHE_ENCRYPTION is set.
+// It returns nil without a cache directory, since nothing is kept then.
+func openCacheCipher(cfg config.Config) (*cachecrypt.Cipher, error) {
+	if cfg.CacheEncryption == "" || cfg.CacheDir == "" {
+		return nil, nil
+	}
+	c, err := cachecrypt.Load(cfg.CacheDir, cfg.CacheEncryption, cfg.CachePassphrase)
+	if err != nil {
+		return nil, fmt.Errorf("cache encryption: %w", err)
+	}
+	return c, nil
+}
+
+// openHistory opens the history database, logging and returning nil when it
+// is disabled or cannot be opened so transcription keeps working. Entries are
+// encrypted with c when it is non-nil.
+func openHistory(cfg config.Config, c *cachecrypt.Cipher) *history.Store {
+	if !historyEnabled(cfg) {
+		return nil
+	}
+	store, err := history.Open(history.Path(cfg.CacheDir))
+	if err != nil {
+		fmt.Printf("[history] %v\n", err)
+		return nil
+	}
+	store.SetCipher(c)
+	return store
+}
+
+// recordHistory counts one transcription attempt in the metrics and stores
+// it when history is enabled.
+func recordHistory(store *history.Store, cfg config.Config, e history.Entry) {
+	metrics.Transcription(e.Source, e.Status, e.Duration)
+	if store == nil {
+		return
+	}
+	e.Provider = providerName(cfg)
+	e.Model = cfg.Model
+	e.Language = cfg.Language
+	e.Machine = config.MachineName(&cfg)
+	if _, err := store.Add(e); err != nil {
+		fmt.Printf("[history] %s\n", i18n.Sprintf("failed to record transcript: %v", err))
+	}
+}
+
+// Retranscribe uploads the cached audio of a history entry again, e.g. after
+// switching model or language, and records the result as a new entry with
+// source "history". Encrypted audio is decrypted with c first.
+func Retranscribe(cfg config.Config, store *history.Store, c *cachecrypt.Cipher, audioPath string) (string, error) {
+	if audioPath == "" {
+		return "", fmt.Errorf("entry has no cached audio")
+	}
+	if err := checkFFmpeg(context.Background(), cfg, ffmpegOptions(cfg), config.TempDir(&cfg)); err != nil {
+		return "", err
+	}
+	asrClient, err := newASRClient(cfg, newHTTPClient(cfg))
+	if err != nil {
+		return "", err
+	}
+	tempDir := config.TempDir(&cfg)
+	src := audioPath
+	if strings.HasSuffix(audioPath, cachecrypt.Ext) {
+		if c == nil {
+			return "", fmt.Errorf("'%s' is encrypted and no cache key is loaded", audioPath)
+		}
+		if src, err = decryptToTemp(c, audioPath, tempDir); err != nil {
+			return "", err
+		}
+		defer os.Remove(src)
+	}
+	out := tempOutputPath(tempDir, config.ContainerExt(cfg.CONTAINER))
+	defer os.Remove(out)
+	progress := newProgressNotice(cfg)
+	defer progress.done()
+	converting := time.Now()
+	if err := ffmpeg.ConvertProgress(ffmpegOptions(cfg), src, out, cfg.SAMPLING_RATE, progress.converting); err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+	text, raw, _, err := upload(withProgress(context.Background(), progress), asrClient, out)
+	recordHistory(store, cfg, history.Entry{
+		Source:      "history",
+		Text:        text,
+		Latency:     time.Since(start),
+		AudioPath:   audioPath,
+		Status:      historyStatus(text, err),
+		Error:       errorString(err),
+		Response:    raw,
+		ConvertTime: start.Sub(converting),
+	})
+	return text, err
+}
+
+// providerName returns PROVIDER, the provider detected from the endpoint, or
+// the endpoint host as a last resort.
+func providerName(cfg config.Config) string {
+	if cfg.Provider != "" {
+		return cfg.Provider
+	}
+	if p := asr.DetectProvider(cfg.APIEndpoint); p != "" {
+		return p
+	}
+	if u, err := url.Parse(cfg.APIEndpoint); err == nil {
+		return u.Hostname()
+	}
+	return ""
+}
+
+// historyStatus classifies a transcription attempt.
+func historyStatus(text string, err error) string {
+	switch {
+	case err != nil:
+		return history.StatusFailed
+	case text == "":
+		return history.StatusEmpty
+	}
+	return history.StatusOK
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
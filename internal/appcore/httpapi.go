@@ -0,0 +1,133 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"stt/internal/config"
+	"stt/internal/grpcapi"
+	"stt/internal/history"
+	"stt/internal/httpapi"
+	"stt/internal/i18n"
+)
+
+// startHTTPAPI serves the local HTTP API when HTTP_API is set. The returned
+// function stops the server.
+func (r *Runtime) startHTTPAPI(cfg config.Config, tempDir string) func() {
+	if cfg.HTTPAPI == "" || r.serviceMode {
+		return func() {}
+	}
+	token, err := apiToken(cfg.HTTPAPIToken, tempDir, "http-api-token")
+	if err != nil {
+		fmt.Printf("[api] %v\n", err)
+		return func() {}
+	}
+	stop, err := httpapi.Listen(cfg.HTTPAPI, &httpapi.Server{Token: token, Backend: r})
+	if err != nil {
+		fmt.Printf("[api] %s\n", i18n.Sprintf("listen on %s failed: %v", cfg.HTTPAPI, err))
+		return func() {}
+	}
+	fmt.Printf("[api] %s\n", i18n.Sprintf("listening on http://%s", cfg.HTTPAPI))
+	return stop
+}
+
+// startGRPCAPI serves the local gRPC API when GRPC_API is set. The returned
+// function stops the server.
+func (r *Runtime) startGRPCAPI(cfg config.Config, tempDir string) func() {
+	if cfg.GRPCAPI == "" || r.serviceMode {
+		return func() {}
+	}
+	token, err := apiToken(cfg.GRPCAPIToken, tempDir, "grpc-api-token")
+	if err != nil {
+		fmt.Printf("[grpc] %v\n", err)
+		return func() {}
+	}
+	stop, err := grpcapi.Listen(cfg.GRPCAPI, token, r)
+	if err != nil {
+		fmt.Printf("[grpc] %s\n", i18n.Sprintf("listen on %s failed: %v", cfg.GRPCAPI, err))
+		return func() {}
+	}
+	fmt.Printf("[grpc] %s\n", i18n.Sprintf("listening on %s", cfg.GRPCAPI))
+	return stop
+}
+
+// apiToken returns the configured API token, or generates a new one and
+// writes it to name in the cache directory for clients to read.
+func apiToken(configured, tempDir, name string) (string, error) {
+	if configured != "" {
+		return configured, nil
+	}
+	token, err := httpapi.NewToken()
+	if err != nil {
+		return "", fmt.Errorf("generate token failed: %w", err)
+	}
+	path := filepath.Join(tempDir, name)
+	if err := os.WriteFile(path, []byte(token), 0600); err != nil {
+		return "", fmt.Errorf("write token failed: %w", err)
+	}
+	fmt.Printf("[api] %s\n", i18n.Sprintf("token written to %s", path))
+	return token, nil
+}
+
+// Transcribe transcribes uploaded audio for the HTTP API like file mode
+// does, returning the text instead of writing it to a file.
+func (r *Runtime) Transcribe(ctx context.Context, name string, audio io.Reader) (string, error) {
+	r.mu.Lock()
+	cfg := r.cfg
+	asrClient := r.asrClient
+	store := r.history
+	cacheCipher := r.cacheCipher
+	tempDir := r.tempDir
+	r.mu.Unlock()
+
+	// The name comes from the client, so only its extension is used.
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(name), "."))
+	if ext == "" || strings.Trim(ext, "abcdefghijklmnopqrstuvwxyz0123456789") != "" {
+		ext = "bin"
+	}
+	inputPath := tempOutputPath(tempDir, ext)
+	f, err := os.Create(inputPath)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(inputPath)
+	_, err = io.Copy(f, audio)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+	text, _, latency, err := transcribeFile(ctx, cfg, asrClient, store, cacheCipher, tempDir, inputPath, "")
+	if err == nil && text != "" {
+		go runPostCommand(cfg, "file", text, latency)
+	}
+	return text, err
+}
+
+// History searches the history database for the HTTP API.
+func (r *Runtime) History(q history.Query) ([]history.Entry, error) {
+	r.mu.Lock()
+	store := r.history
+	r.mu.Unlock()
+	if store == nil {
+		return nil, errors.New("history is disabled")
+	}
+	return store.Search(q)
+}
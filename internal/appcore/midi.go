@@ -0,0 +1,52 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"fmt"
+
+	"stt/internal/config"
+	"stt/internal/i18n"
+	"stt/internal/midi"
+)
+
+// startMIDI runs the MIDI_MAP actions for keys pressed on MIDI_INPUT. A
+// missing device is only logged, so the hotkeys keep working without it.
+// The returned function stops listening.
+func (r *Runtime) startMIDI(cfg config.Config) func() {
+	if cfg.MIDIInput == "" || r.serviceMode {
+		return func() {}
+	}
+	bindings, err := midi.ParseMap(cfg.MIDIMap)
+	if err != nil {
+		fmt.Printf("[midi] %v\n", err)
+		return func() {}
+	}
+	stop, err := midi.Listen(cfg.MIDIInput, func(k midi.Key) {
+		action, ok := bindings[k]
+		if cfg.HOTKEY_DEBUG {
+			fmt.Printf("[midi] %s pressed (action %q)\n", k, action)
+		}
+		if !ok {
+			return
+		}
+		if _, err := r.Control(action); err != nil {
+			fmt.Printf("[midi] %s: %v\n", action, err)
+		}
+	})
+	if err != nil {
+		fmt.Printf("[midi] %v\n", err)
+		return func() {}
+	}
+	fmt.Printf("[midi] %s\n", i18n.Sprintf("listening on '%s'", cfg.MIDIInput))
+	return stop
+}
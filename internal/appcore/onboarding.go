@@ -0,0 +1,116 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"stt/internal/config"
+	"stt/internal/i18n"
+	"stt/internal/notify"
+	"stt/pkg/asr"
+)
+
+// onboardingMarker is written to the cache directory once a test recording
+// has been pasted; its presence turns the first-run guide off.
+const onboardingMarker = ".stt-onboarded"
+
+// onboarding is the first-run guide of record mode: it reports the endpoint
+// check, explains the hotkeys and then follows runtime events until a test
+// recording has made it all the way to a paste.
+type onboarding struct {
+	mu       sync.Mutex
+	cfg      config.Config
+	marker   string
+	active   bool
+	uploaded bool
+}
+
+// newOnboarding returns the guide for cfg, or nil when ONBOARDING is off or
+// the guide has been completed before. The methods are no-ops on nil.
+func newOnboarding(cfg config.Config) *onboarding {
+	if !cfg.Onboarding {
+		return nil
+	}
+	marker := filepath.Join(config.TempDir(&cfg), onboardingMarker)
+	if _, err := os.Stat(marker); err == nil {
+		return nil
+	}
+	return &onboarding{cfg: cfg, marker: marker}
+}
+
+// begin prints the guide and asks for a test recording. probe is the result
+// of the startup endpoint check.
+func (o *onboarding) begin(probe asr.ProbeResult) {
+	if o == nil {
+		return
+	}
+	cfg := o.cfg
+	say := func(msg string) { fmt.Printf("[setup] %s\n", msg) }
+	say(i18n.T("Welcome to STT. Let's check that everything works."))
+	if probe.OK() {
+		say(i18n.Sprintf("1/3 ASR endpoint reachable: %s", probe.Summary()))
+	} else {
+		say(i18n.Sprintf("1/3 ASR endpoint check failed: %s. Check API_ENDPOINT and TOKEN in the config.", probe.Summary()))
+	}
+	hotkeys := i18n.Sprintf("%s starts and stops recording, %s pauses and resumes, %s cancels.", cfg.StartKey, cfg.PauseKey, cfg.CancelKey)
+	say("2/3 " + hotkeys)
+	test := i18n.Sprintf("Put the cursor in a text field, press %s, say a few words and press %s again.", cfg.StartKey, cfg.StartKey)
+	say("3/3 " + test)
+	notify.Notify(i18n.T("STT setup"), hotkeys+"\n"+test)
+
+	o.mu.Lock()
+	o.active = true
+	o.mu.Unlock()
+}
+
+// observe follows runtime events while the guide waits for the test
+// recording, and finishes the guide once its text has been pasted.
+func (o *onboarding) observe(event Event) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.active {
+		return
+	}
+
+	switch {
+	case event.State == StateUploading:
+		o.uploaded = true
+	case event.State == StateError && o.uploaded:
+		o.uploaded = false
+		o.tell(i18n.Sprintf("The test recording failed: %s. Fix the problem and try again.", event.Error))
+	case event.State == StateError && (event.Message == "Recording start failed" || event.Message == "Recording failed"):
+		o.tell(i18n.Sprintf("Recording failed: %s. Check INPUT_DEVICE (see stt devices) and try again.", event.Error))
+	case event.State == StateIdle && o.uploaded && event.Message == "Empty result from ASR":
+		o.uploaded = false
+		o.tell(i18n.T("No speech was recognized. Check the microphone and INPUT_DEVICE, then try again."))
+	case event.State == StateIdle && o.uploaded && event.Message == "Transcription pasted":
+		o.active = false
+		o.tell(i18n.T("The test recording was pasted. Setup is complete."))
+		if err := os.WriteFile(o.marker, []byte(time.Now().Format(time.RFC3339)+"\n"), 0o644); err != nil {
+			fmt.Printf("[setup] %s\n", i18n.Sprintf("failed to write %s: %v", o.marker, err))
+		}
+	}
+}
+
+// tell prints a guide message and shows it as a notification.
+func (o *onboarding) tell(msg string) {
+	fmt.Printf("[setup] %s\n", msg)
+	notify.Notify(i18n.T("STT setup"), msg)
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"stt/internal/config"
+	"stt/pkg/asr"
+	"stt/pkg/audio/ffmpeg"
+	"stt/pkg/record"
+)
+
+// newASRClient builds the upload client for cfg. ExtraConfig is decoded here
+// so a bad value fails the same way at startup, on reload, and in commands.
+func newASRClient(cfg config.Config, httpClient *http.Client) (*asr.Client, error) {
+	extra, err := cfg.ExtraConfig.Object()
+	if err != nil {
+		return nil, fmt.Errorf("invalid extra-config JSON: %w", err)
+	}
+	return asr.New(asr.Options{
+		Endpoint:          cfg.APIEndpoint,
+		Token:             cfg.Token,
+		Model:             cfg.Model,
+		Language:          cfg.Language,
+		Provider:          cfg.Provider,
+		Prompt:            cfg.Prompt,
+		TextPath:          cfg.TEXTPath,
+		Extra:             extra,
+		Timeout:           time.Duration(cfg.RequestTimeout) * time.Second,
+		MaxRetry:          cfg.MaxRetry,
+		RetryBaseDelay:    time.Duration(cfg.RetryBaseDelay * float64(time.Second)),
+		MaxConcurrent:     cfg.MaxConcurrentUploads,
+		RequestsPerMinute: cfg.RequestsPerMinute,
+		RequestsBurst:     cfg.RequestsBurst,
+		VerifySSL:         cfg.VerifySSL,
+		Debug:             cfg.UPLOAD_DEBUG,
+	}, httpClient)
+}
+
+func recorderOptions(cfg config.Config) record.Options {
+	opts := record.Options{
+		Channels:    cfg.Channels,
+		SampleRate:  cfg.SAMPLING_RATE,
+		InputDevice: cfg.InputDevice,
+		Source:      cfg.Source,
+		Debug:       cfg.RECORD_DEBUG,
+	}
+	if segmented(cfg) {
+		opts.SegmentLength = time.Duration(cfg.RecordSegmentSeconds) * time.Second
+	}
+	// Recordings go straight into ffmpeg unless the cache keeps their WAV.
+	if !cfg.KeepCache || !cfg.KeepWav {
+		opts.Encode = func(base string) (record.Encoder, error) {
+			enc, err := ffmpeg.StartEncoder(ffmpegOptions(cfg), base+"."+config.ContainerExt(cfg.CONTAINER), cfg.SAMPLING_RATE, cfg.Channels)
+			if err != nil {
+				return nil, err
+			}
+			return enc, nil
+		}
+	}
+	return opts
+}
+
+func ffmpegOptions(cfg config.Config) ffmpeg.Options {
+	return ffmpeg.Options{
+		Codec:      cfg.CODECS,
+		Channels:   cfg.Channels,
+		SampleRate: cfg.SAMPLING_RATE,
+		Bitrate:    cfg.BIT_RATE,
+		Depth:      cfg.SAMPLING_RATE_DEPTH,
+		Path:       cfg.FFMPEG_PATH,
+		Debug:      cfg.FFMPEG_DEBUG,
+		Timeout:    time.Duration(cfg.FFMPEG_TIMEOUT) * time.Second,
+		Highpass:   cfg.AudioHighpass,
+		Lowpass:    cfg.AudioLowpass,
+		Denoise:    cfg.AudioDenoise,
+		Speed:      cfg.AudioSpeed,
+		InputArgs:  splitArgs(cfg.FFMPEG_INPUT_ARGS),
+		ExtraArgs:  splitArgs(cfg.FFMPEG_EXTRA_ARGS),
+		Threads:    cfg.FFMPEG_THREADS,
+		VBR:        cfg.OpusVBR,
+
+		CompressionLevel: cfg.CompressionLevel,
+	}
+}
+
+// fileOptions is ffmpegOptions for converting an existing file rather than a
+// recording, which may be a video with several audio tracks.
+func fileOptions(cfg config.Config) ffmpeg.Options {
+	opts := ffmpegOptions(cfg)
+	opts.AudioTrack = cfg.AudioTrack
+	return opts
+}
+
+// splitArgs splits ffmpeg arguments whose quoting Validate has checked.
+func splitArgs(s string) []string {
+	args, _ := ffmpeg.SplitArgs(s)
+	return args
+}
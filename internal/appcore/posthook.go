@@ -0,0 +1,43 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"stt/internal/config"
+	"stt/internal/i18n"
+	"stt/internal/posthook"
+)
+
+// runPostCommand runs POST_COMMAND with a transcript and logs a failure.
+// Record mode runs it on its own goroutine so a slow hook does not hold up
+// pasting.
+func runPostCommand(cfg config.Config, source, text string, latency time.Duration) {
+	if cfg.PostCommand == "" {
+		return
+	}
+	m := posthook.Meta{
+		Source:   source,
+		Profile:  cfg.Profile,
+		Provider: providerName(cfg),
+		Model:    cfg.Model,
+		Language: cfg.Language,
+		Latency:  latency,
+		Time:     time.Now(),
+	}
+	if err := posthook.Run(context.Background(), cfg.PostCommand, text, m); err != nil {
+		fmt.Printf("[hook] %s\n", i18n.Sprintf("POST_COMMAND failed: %v", err))
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"stt/internal/config"
+	"stt/internal/i18n"
+	"stt/internal/notify"
+	"stt/pkg/asr"
+)
+
+// progressDelay is how long a conversion and upload run before their progress
+// notification appears, so short dictations stay quiet.
+const progressDelay = 3 * time.Second
+
+// progressNotice reports the conversion and upload of one recording or file
+// in the log and in a single notification, updated in place, so a long file
+// does not look like a hang. A nil notice reports nothing.
+type progressNotice struct {
+	start time.Time
+	// show is whether the progress also appears as a notification.
+	show bool
+
+	mu    sync.Mutex
+	n     *notify.Progress
+	label string
+	pct   int
+}
+
+// newProgressNotice returns a notice for a job starting now. It only logs
+// when PROGRESS_NOTIFICATION or NOTIFICATION is off.
+func newProgressNotice(cfg config.Config) *progressNotice {
+	return &progressNotice{start: time.Now(), pct: -1, show: cfg.Notification && cfg.ProgressNotification}
+}
+
+// converting reports the fraction of the audio converted.
+func (p *progressNotice) converting(f float64) {
+	p.update("Converting %d%%…", f)
+}
+
+// uploading reports the bytes of the upload sent. Once all are, the
+// notification waits on the ASR service instead.
+func (p *progressNotice) uploading(sent, total int64) {
+	if total <= 0 {
+		return
+	}
+	if sent >= total {
+		p.update("Waiting for transcription…", 1)
+		return
+	}
+	p.update("Uploading %d%%…", float64(sent)/float64(total))
+}
+
+func (p *progressNotice) update(label string, f float64) {
+	if p == nil {
+		return
+	}
+	pct := int(f * 100)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if time.Since(p.start) < progressDelay {
+		return
+	}
+	// Whole tens are enough; each update costs a PowerShell run.
+	if label == p.label && pct/10 == p.pct/10 {
+		return
+	}
+	p.label, p.pct = label, pct
+	logged := label
+	if strings.Contains(label, "%d") {
+		logged = fmt.Sprintf(label, pct)
+	}
+	fmt.Printf("[progress] %s (%s)\n", logged, time.Since(p.start).Round(time.Second))
+	if !p.show {
+		return
+	}
+	if p.n == nil {
+		p.n = notify.NewProgress("STT")
+	}
+	status := i18n.T(label)
+	if strings.Contains(label, "%d") {
+		status = i18n.Sprintf(label, pct)
+	}
+	p.n.Update(status, f)
+}
+
+// done removes the notification once the job has finished.
+func (p *progressNotice) done() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.n != nil {
+		p.n.Close()
+	}
+}
+
+type progressKey struct{}
+
+// withProgress returns ctx carrying p for processQueued, with uploads made
+// with it reported to p.
+func withProgress(ctx context.Context, p *progressNotice) context.Context {
+	if p == nil {
+		return ctx
+	}
+	return asr.WithUploadProgress(context.WithValue(ctx, progressKey{}, p), p.uploading)
+}
+
+// progressFrom returns the notice carried by ctx, or nil.
+func progressFrom(ctx context.Context) *progressNotice {
+	p, _ := ctx.Value(progressKey{}).(*progressNotice)
+	return p
+}
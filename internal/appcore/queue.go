@@ -0,0 +1,294 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"stt/internal/cachecrypt"
+	"stt/internal/config"
+	"stt/internal/history"
+	"stt/internal/i18n"
+	"stt/internal/notify"
+	"stt/internal/queue"
+	"stt/internal/service"
+	"stt/pkg/audio/ffmpeg"
+)
+
+// QueueResult summarizes one pass over the retry queue.
+type QueueResult struct {
+	Done      int
+	Remaining int
+}
+
+// queueEnabled reports whether the pending queue is in use. The queue lives
+// in the cache directory, so one must be set.
+func queueEnabled(cfg config.Config) bool {
+	return (cfg.RetryQueue || cfg.OfflineFirst) && cfg.CacheDir != ""
+}
+
+// offlineFirst reports whether every recording goes through the queue.
+func offlineFirst(cfg config.Config) bool {
+	return cfg.OfflineFirst && cfg.CacheDir != ""
+}
+
+// enqueueAudio moves the audio at path into the queue in the cache dir,
+// encrypting it with c when set.
+func enqueueAudio(cfg config.Config, c *cachecrypt.Cipher, path string, it queue.Item) (*queue.Queue, queue.Item, error) {
+	q, err := queue.Open(queue.Path(cfg.CacheDir))
+	if err != nil {
+		return nil, it, err
+	}
+	if c == nil {
+		it, err = q.Add(path, it)
+		return q, it, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, it, err
+	}
+	it, err = q.AddWith(filepath.Ext(path)+cachecrypt.Ext, it, func(dst string) error {
+		return c.WriteFile(dst, data, 0644)
+	})
+	if err == nil {
+		_ = os.Remove(path)
+	}
+	return q, it, err
+}
+
+// flushQueue transcribes queued recordings oldest first, leaving those queued
+// less than minAge ago. It stops at the first failure, since that usually
+// means the endpoint is still unreachable.
+func flushQueue(ctx context.Context, cfg config.Config, client Transcriber, store *history.Store, c *cachecrypt.Cipher, tempDir string, minAge time.Duration) (QueueResult, error) {
+	var res QueueResult
+	if _, err := os.Stat(queue.Path(cfg.CacheDir)); os.IsNotExist(err) {
+		return res, nil
+	}
+	q, err := queue.Open(queue.Path(cfg.CacheDir))
+	if err != nil {
+		return res, err
+	}
+	items, err := q.List()
+	if err != nil {
+		return res, err
+	}
+	if minAge > 0 {
+		cutoff := time.Now().Add(-minAge)
+		n := len(items)
+		items = slices.DeleteFunc(items, func(it queue.Item) bool { return it.CreatedAt.After(cutoff) })
+		res.Remaining = n - len(items)
+	}
+	for i, it := range items {
+		text, t, err := processQueued(ctx, cfg, client, store, c, tempDir, q, it, "queue")
+		if err != nil {
+			fmt.Printf("[queue] %s\n", i18n.Sprintf("retry of %s failed: %v", it.ID, err))
+			res.Remaining += len(items) - i
+			return res, nil
+		}
+		if store == nil && !cfg.KeepCache && text != "" {
+			saveQueuedText(cfg, c, it, text)
+		}
+		fmt.Printf("[queue] %s\n", i18n.Sprintf("transcribed %s (%s)", it.ID, t))
+		res.Done++
+	}
+	return res, nil
+}
+
+// processQueued converts (when needed) and uploads one queued recording. On
+// success the item leaves the queue, its audio is handled like any other
+// upload, and the transcript is recorded with the given history source. On
+// failure the item stays queued with its attempt count and error updated.
+// The timings returned hold the conversion and upload times.
+func processQueued(ctx context.Context, cfg config.Config, client Transcriber, store *history.Store, c *cachecrypt.Cipher, tempDir string, q *queue.Queue, it queue.Item, source string) (string, timings, error) {
+	var t timings
+	var temps []string
+	defer func() {
+		for _, p := range temps {
+			_ = os.Remove(p)
+		}
+	}()
+	fail := func(err error) (string, timings, error) {
+		it.LastError = err.Error()
+		if uerr := q.Update(it); uerr != nil {
+			fmt.Printf("[queue] %s\n", i18n.Sprintf("failed to update %s: %v", it.ID, uerr))
+		}
+		return "", t, err
+	}
+
+	src := q.AudioPath(it)
+	if strings.HasSuffix(src, cachecrypt.Ext) {
+		if c == nil {
+			return fail(fmt.Errorf("%s is encrypted but CACHE_ENCRYPTION is off", it.Audio))
+		}
+		plain, err := decryptToTemp(c, src, tempDir)
+		if err != nil {
+			return fail(err)
+		}
+		src = plain
+		temps = append(temps, src)
+	}
+	wavPath, uploadPath := "", src
+	if it.NeedsConvert {
+		wavPath = src
+		uploadPath = tempOutputPath(tempDir, config.ContainerExt(cfg.CONTAINER))
+		temps = append(temps, uploadPath)
+		converting := time.Now()
+		err := ffmpeg.ConvertContext(ctx, ffmpegOptions(cfg), src, uploadPath, cfg.SAMPLING_RATE, progressFrom(ctx).converting)
+		t.convert = time.Since(converting)
+		if err != nil {
+			return fail(err)
+		}
+	}
+
+	start := time.Now()
+	text, raw, attempts, err := upload(ctx, client, uploadPath)
+	latency := time.Since(start)
+	t.upload = latency
+	it.Attempts += attempts
+	if err != nil {
+		return fail(err)
+	}
+
+	meta := newCacheMeta(cfg, source, it.Duration, latency, it.Attempts, text, nil)
+	meta.CreatedAt = it.CreatedAt
+	audioPath := handleCache(cfg, c, wavPath, uploadPath, true, raw, meta)
+	recordHistory(store, cfg, history.Entry{
+		CreatedAt:   it.CreatedAt,
+		Source:      source,
+		Duration:    it.Duration,
+		Text:        text,
+		Latency:     latency,
+		AudioPath:   audioPath,
+		Status:      historyStatus(text, nil),
+		Response:    raw,
+		ConvertTime: t.convert,
+	})
+	if err := q.Remove(it); err != nil {
+		fmt.Printf("[queue] %s\n", i18n.Sprintf("failed to remove %s: %v", it.ID, err))
+	}
+	return text, t, nil
+}
+
+// decryptToTemp writes the plaintext of the encrypted file at path to a new
+// temporary file in tempDir, keeping the original extension.
+func decryptToTemp(c *cachecrypt.Cipher, path, tempDir string) (string, error) {
+	data, err := c.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	ext := filepath.Ext(strings.TrimSuffix(path, cachecrypt.Ext))
+	out := tempOutputPath(tempDir, strings.TrimPrefix(ext, "."))
+	if err := os.WriteFile(out, data, 0600); err != nil {
+		_ = os.Remove(out)
+		return "", err
+	}
+	return out, nil
+}
+
+// saveQueuedText keeps the transcript of a queued recording when neither the
+// history database nor KEEP_CACHE records it.
+func saveQueuedText(cfg config.Config, c *cachecrypt.Cipher, it queue.Item, text string) {
+	path, err := writeCacheFile(c, filepath.Join(cfg.CacheDir, "queue-"+it.ID+".txt"), []byte(text))
+	if err != nil {
+		fmt.Printf("[queue] %s\n", i18n.Sprintf("failed to write %s: %v", path, err))
+	}
+}
+
+// FlushQueue retries the queued recordings once and returns what happened.
+func (r *Runtime) FlushQueue() (QueueResult, error) {
+	return r.flushQueue(context.Background())
+}
+
+func (r *Runtime) flushQueue(ctx context.Context) (QueueResult, error) {
+	r.queueMu.Lock()
+	defer r.queueMu.Unlock()
+
+	r.mu.Lock()
+	cfg := r.cfg
+	asrClient := r.asrClient
+	store := r.history
+	cacheCipher := r.cacheCipher
+	tempDir := r.tempDir
+	r.mu.Unlock()
+
+	if !queueEnabled(cfg) {
+		return QueueResult{}, nil
+	}
+	var minAge time.Duration
+	if r.serviceMode {
+		minAge = serviceQueueDelay
+	}
+	res, err := flushQueue(ctx, cfg, asrClient, store, cacheCipher, tempDir, minAge)
+	if res.Done > 0 && cfg.Notification {
+		notify.Notify("STT", i18n.Sprintf("Transcribed %d queued recording(s)", res.Done))
+	}
+	return res, err
+}
+
+// startQueueRetrier retries the queue in the background every
+// QUEUE_RETRY_INTERVAL seconds, except while the STT service runs and
+// retries it instead. The returned function cancels any upload in progress
+// and waits for the retrier to exit.
+func (r *Runtime) startQueueRetrier(cfg config.Config) func() {
+	if !queueEnabled(cfg) {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(time.Duration(cfg.QueueRetryInterval) * time.Second)
+		defer ticker.Stop()
+		for {
+			if r.serviceMode || !service.Active() {
+				if _, err := r.flushQueue(ctx); err != nil {
+					fmt.Printf("[queue] %v\n", err)
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// FlushQueue retries the queued recordings of cfg once, for `stt queue flush`.
+func FlushQueue(cfg config.Config) (QueueResult, error) {
+	if cfg.CacheDir == "" {
+		return QueueResult{}, fmt.Errorf("cache-dir is not set")
+	}
+	asrClient, err := newASRClient(cfg, newHTTPClient(cfg))
+	if err != nil {
+		return QueueResult{}, err
+	}
+	cacheCipher, err := openCacheCipher(cfg)
+	if err != nil {
+		return QueueResult{}, err
+	}
+	store := openHistory(cfg, cacheCipher)
+	if store != nil {
+		defer store.Close()
+	}
+	return flushQueue(context.Background(), cfg, asrClient, store, cacheCipher, config.TempDir(&cfg), 0)
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stt/internal/config"
+	"stt/internal/queue"
+)
+
+func TestFlushQueueRetriesUntilUploadSucceeds(t *testing.T) {
+	var online atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !online.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"text":"queued ok"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = dir
+	cfg.APIEndpoint = server.URL
+	cfg.TEXTPath = "text"
+	cfg.MaxRetry = 1
+	cfg.RetryBaseDelay = 0
+	client, err := newASRClient(cfg, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("newASRClient failed: %v", err)
+	}
+	store := openHistory(cfg, nil)
+	defer store.Close()
+
+	out := filepath.Join(dir, "output.ogg")
+	if err := os.WriteFile(out, []byte("out"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, _, err := enqueueAudio(cfg, nil, out, queue.Item{Source: "record", Attempts: 1}); err != nil {
+		t.Fatalf("enqueueAudio failed: %v", err)
+	}
+
+	res, err := flushQueue(context.Background(), cfg, client, store, nil, dir, 0)
+	if err != nil || res.Done != 0 || res.Remaining != 1 {
+		t.Fatalf("offline flush = %+v, %v; want one remaining", res, err)
+	}
+	q, _ := queue.Open(queue.Path(dir))
+	items, _ := q.List()
+	if len(items) != 1 || items[0].Attempts != 2 || items[0].LastError == "" {
+		t.Fatalf("queued items after failed retry = %#v", items)
+	}
+
+	online.Store(true)
+	res, err = flushQueue(context.Background(), cfg, client, store, nil, dir, 0)
+	if err != nil || res.Done != 1 || res.Remaining != 0 {
+		t.Fatalf("online flush = %+v, %v; want one done", res, err)
+	}
+	if items, _ := q.List(); len(items) != 0 {
+		t.Fatalf("queue not empty after flush: %#v", items)
+	}
+	entries, err := store.Recent(1)
+	if err != nil || len(entries) != 1 || entries[0].Source != "queue" || entries[0].Text != "queued ok" {
+		t.Fatalf("history = %#v, %v; want queued transcript", entries, err)
+	}
+}
+
+func TestProcessQueuedKeepsRecordingWhenConversionFails(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = dir
+	cfg.OfflineFirst = true
+	cfg.FFMPEG_PATH = filepath.Join(dir, "missing-ffmpeg")
+	client, err := newASRClient(cfg, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("newASRClient failed: %v", err)
+	}
+
+	wav := filepath.Join(dir, "RecordTemp_1.wav")
+	if err := os.WriteFile(wav, []byte("not a wav"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	q, it, err := enqueueAudio(cfg, nil, wav, queue.Item{Source: "record", NeedsConvert: true})
+	if err != nil {
+		t.Fatalf("enqueueAudio failed: %v", err)
+	}
+	if _, err := os.Stat(wav); !os.IsNotExist(err) {
+		t.Fatalf("recording still in temp dir after enqueue: %v", err)
+	}
+
+	if _, _, err := processQueued(context.Background(), cfg, client, nil, nil, dir, q, it, "record"); err == nil {
+		t.Fatalf("processQueued succeeded without ffmpeg")
+	}
+	items, err := q.List()
+	if err != nil || len(items) != 1 || !items[0].NeedsConvert || items[0].LastError == "" {
+		t.Fatalf("queued items after failed conversion = %#v, %v", items, err)
+	}
+	if _, err := os.Stat(q.AudioPath(items[0])); err != nil {
+		t.Fatalf("queued recording lost: %v", err)
+	}
+}
+
+func TestFlushQueueLeavesNewItemsAlone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"text":"hello"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = dir
+	cfg.APIEndpoint = server.URL
+	cfg.TEXTPath = "text"
+	client, err := newASRClient(cfg, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("newASRClient failed: %v", err)
+	}
+	for i, age := range []time.Duration{time.Hour, time.Second} {
+		out := filepath.Join(dir, fmt.Sprintf("output%d.ogg", i))
+		if err := os.WriteFile(out, []byte("out"), 0644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		if _, _, err := enqueueAudio(cfg, nil, out, queue.Item{Source: "record", CreatedAt: time.Now().Add(-age)}); err != nil {
+			t.Fatalf("enqueueAudio failed: %v", err)
+		}
+	}
+
+	res, err := flushQueue(context.Background(), cfg, client, nil, nil, dir, time.Minute)
+	if err != nil || res.Done != 1 || res.Remaining != 1 {
+		t.Fatalf("flush = %+v, %v; want the old item done and the new one left", res, err)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"stt/internal/cacheindex"
+	"stt/internal/config"
+	"stt/internal/history"
+	"stt/internal/i18n"
+)
+
+// retentionPolicy returns the cache retention policy configured in cfg.
+func retentionPolicy(cfg config.Config) cacheindex.Policy {
+	return cacheindex.Policy{
+		MaxAge:  time.Duration(cfg.CacheMaxAgeDays) * 24 * time.Hour,
+		MaxSize: int64(cfg.CacheMaxSizeMB) << 20,
+	}
+}
+
+// retentionEnabled reports whether kept cache files are archived or purged
+// automatically.
+func retentionEnabled(cfg config.Config) bool {
+	return cfg.CacheDir != "" && (cfg.CacheArchiveDays > 0 || retentionPolicy(cfg) != cacheindex.Policy{})
+}
+
+// archiveCache moves entries older than CACHE_ARCHIVE_DAYS into the monthly
+// zip archives and points their history rows at the archived copies. It
+// returns the number of entries archived.
+func archiveCache(cfg config.Config, store *history.Store, now time.Time) (int, error) {
+	if cfg.CacheArchiveDays <= 0 {
+		return 0, nil
+	}
+	entries, err := cacheindex.Scan(cfg.CacheDir)
+	if err != nil {
+		return 0, err
+	}
+	victims := cacheindex.Select(entries, cacheindex.Policy{MaxAge: time.Duration(cfg.CacheArchiveDays) * 24 * time.Hour}, now)
+	if len(victims) == 0 {
+		return 0, nil
+	}
+	moved, err := cacheindex.Archive(cfg.CacheDir, victims)
+	if store != nil {
+		for src, dst := range moved {
+			if rerr := store.RelocateAudio(src, dst); rerr != nil {
+				fmt.Printf("[history] %s\n", i18n.Sprintf("failed to relocate %s: %v", src, rerr))
+			}
+		}
+	}
+	fmt.Printf("[cache] %s\n", i18n.Sprintf("archived %d entries", len(victims)))
+	return len(victims), err
+}
+
+// purgeCache applies the retention policy to the cache dir once and returns
+// the number of entries removed.
+func purgeCache(cfg config.Config, now time.Time) (int, error) {
+	entries, err := cacheindex.Scan(cfg.CacheDir)
+	if err != nil {
+		return 0, err
+	}
+	victims := cacheindex.Select(entries, retentionPolicy(cfg), now)
+	if len(victims) == 0 {
+		return 0, nil
+	}
+	freed, err := cacheindex.Remove(cfg.CacheDir, victims)
+	fmt.Printf("[cache] %s\n", i18n.Sprintf("purged %d entries (%d bytes)", len(victims), freed))
+	return len(victims), err
+}
+
+// startCachePurger archives and purges the cache now and then every
+// CACHE_PURGE_INTERVAL minutes, since the app usually runs for weeks without
+// a restart. The returned function stops the purger and waits for it.
+func (r *Runtime) startCachePurger(cfg config.Config) func() {
+	if !retentionEnabled(cfg) {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(time.Duration(cfg.CachePurgeInterval) * time.Minute)
+		defer ticker.Stop()
+		for {
+			r.mu.Lock()
+			store := r.history
+			r.mu.Unlock()
+			if _, err := archiveCache(cfg, store, time.Now()); err != nil {
+				fmt.Printf("[cache] %s\n", i18n.Sprintf("archive failed: %v", err))
+			}
+			if _, err := purgeCache(cfg, time.Now()); err != nil {
+				fmt.Printf("[cache] %s\n", i18n.Sprintf("purge failed: %v", err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
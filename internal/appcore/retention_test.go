@@ -0,0 +1,96 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"stt/internal/cacheindex"
+	"stt/internal/config"
+	"stt/internal/history"
+)
+
+func TestPurgeCacheAppliesRetention(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for name, age := range map[string]time.Duration{"old": 10 * 24 * time.Hour, "new": time.Hour} {
+		path := filepath.Join(dir, "2026", "audio-"+name+".ogg")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatalf("Chtimes failed: %v", err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = dir
+	if retentionEnabled(cfg) {
+		t.Fatalf("retention enabled without CACHE_MAX_AGE_DAYS or CACHE_MAX_SIZE_MB")
+	}
+	cfg.CacheMaxAgeDays = 7
+	if !retentionEnabled(cfg) {
+		t.Fatalf("retention disabled with CACHE_MAX_AGE_DAYS set")
+	}
+	n, err := purgeCache(cfg, now)
+	if err != nil || n != 1 {
+		t.Fatalf("purgeCache = %d, %v; want one entry removed", n, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2026", "audio-old.ogg")); !os.IsNotExist(err) {
+		t.Fatalf("expired file still present: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2026", "audio-new.ogg")); err != nil {
+		t.Fatalf("recent file removed: %v", err)
+	}
+}
+
+func TestArchiveCacheRelocatesHistory(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	audio := filepath.Join(dir, "audio-old.ogg")
+	if err := os.WriteFile(audio, []byte("ogg"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	old := now.AddDate(0, 0, -40)
+	if err := os.Chtimes(audio, old, old); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = dir
+	cfg.CacheArchiveDays = 30
+	store := openHistory(cfg, nil)
+	defer store.Close()
+	if _, err := store.Add(history.Entry{Source: "record", Text: "kept", AudioPath: audio, Status: history.StatusOK}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	n, err := archiveCache(cfg, store, now)
+	if err != nil || n != 1 {
+		t.Fatalf("archiveCache = %d, %v; want one entry archived", n, err)
+	}
+	entries, err := store.Recent(1)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Recent = %#v, %v", entries, err)
+	}
+	want := cacheindex.ArchivePath(dir, old.Format("2006-01")) + "!audio-old.ogg"
+	if entries[0].AudioPath != want {
+		t.Fatalf("AudioPath = %q, want %q", entries[0].AudioPath, want)
+	}
+}
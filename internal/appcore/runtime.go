@@ -16,9 +16,11 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -26,13 +28,25 @@ import (
 	"github.com/google/uuid"
 	"golang.org/x/net/http2"
 
-	"stt/internal/asr"
-	"stt/internal/audio/ffmpeg"
-	"stt/internal/clipboard"
+	"stt/internal/applog"
+	"stt/internal/atomicfile"
+	"stt/internal/cachecrypt"
+	"stt/internal/cachepath"
 	"stt/internal/config"
+	"stt/internal/crash"
+	"stt/internal/history"
 	"stt/internal/hotkey"
+	"stt/internal/i18n"
+	"stt/internal/meter"
+	"stt/internal/metrics"
 	"stt/internal/notify"
-	"stt/internal/record"
+	"stt/internal/queue"
+	"stt/internal/redact"
+	"stt/internal/sound"
+	"stt/internal/tray"
+	"stt/pkg/asr"
+	"stt/pkg/audio/ffmpeg"
+	"stt/pkg/record"
 )
 
 // State is the GUI/CLI-visible runtime state.
@@ -53,42 +67,123 @@ type Event struct {
 	Error   string `json:"error,omitempty"`
 }
 
+// Status is a fuller snapshot than Event, for the tray tooltip and
+// `stt status`.
+type Status struct {
+	Event
+	// Elapsed is the time recorded so far, not counting pauses, while
+	// recording or paused.
+	Elapsed time.Duration
+	Profile string
+	// Latency is how long the last successful transcription took, or 0.
+	Latency time.Duration
+}
+
 // Runtime owns recorder, uploader, hotkeys, and shared state transitions.
 type Runtime struct {
 	mu          sync.Mutex
 	actionMu    sync.Mutex
 	cfg         config.Config
 	tempDir     string
-	recorder    *record.Recorder
-	asrClient   *asr.Client
+	recorder    Recorder
+	asrClient   Transcriber
+	history     *history.Store
+	cacheCipher *cachecrypt.Cipher
+	stopQueue   func()
+	stopPurge   func()
+	stopTimer   func()
+	stopAPI     func()
+	stopGRPC    func()
+	stopMIDI    func()
+	stopSched   func()
+	stopClip    func()
+	stopSession func()
+	queueMu     sync.Mutex
 	stopHotkeys func()
+	meter       *meter.Window
 	onEvent     func(Event)
 	state       State
 	lastMessage string
 	lastError   string
+	lastLatency time.Duration
+
+	// subscribers receive record-mode transcripts; see SubscribeTranscripts.
+	subscribers    map[int]transcriptFunc
+	nextSubscriber int
+
+	// serviceMode is set in the runtime of the STT service, which owns the
+	// retry queue and has no desktop.
+	serviceMode bool
+	// noPaste keeps transcripts out of the focused window, for hosts that
+	// take them from StopRecording instead; see SetPaste.
+	noPaste bool
+
+	// started is when the current recording began; pausedAt and paused
+	// track its pauses so Elapsed leaves them out.
+	started  time.Time
+	pausedAt time.Time
+	paused   time.Duration
+
+	// segments transcribes the current recording's segments; see
+	// RECORD_SEGMENT_SECONDS.
+	segments *segmentPipeline
+
+	// deps holds the replacements given to NewRuntimeWith.
+	deps Deps
 }
 
 // NewRuntime creates a reusable record-mode runtime.
 func NewRuntime(cfg config.Config) (*Runtime, error) {
+	return newRuntime(cfg, false, Deps{})
+}
+
+// NewRuntimeWith is NewRuntime with the recorder, converter, transcriber or
+// paste step replaced by deps.
+func NewRuntimeWith(cfg config.Config, deps Deps) (*Runtime, error) {
+	return newRuntime(cfg, false, deps)
+}
+
+func newRuntime(cfg config.Config, serviceMode bool, deps Deps) (*Runtime, error) {
 	if err := config.Validate(&cfg); err != nil {
 		return nil, err
 	}
+	i18n.Set(cfg.UILang)
+	notify.SetQuietMode(cfg.QuietMode)
 	config.InitCacheDir(&cfg)
 	tempDir := config.TempDir(&cfg)
 	cleanupOldTempFiles(tempDir)
 
-	asrClient, err := asr.New(cfg, newHTTPClient(cfg))
+	deps = deps.withDefaults()
+	asrClient, err := deps.transcriber(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cacheCipher, err := openCacheCipher(cfg)
 	if err != nil {
 		return nil, err
 	}
 
+	setupCrashReports(cfg)
 	r := &Runtime{
-		cfg:       cfg,
-		tempDir:   tempDir,
-		recorder:  record.New(cfg, tempDir),
-		asrClient: asrClient,
-		state:     StateIdle,
+		cfg:         cfg,
+		tempDir:     tempDir,
+		asrClient:   asrClient,
+		history:     openHistory(cfg, cacheCipher),
+		cacheCipher: cacheCipher,
+		state:       StateIdle,
+		serviceMode: serviceMode,
+		deps:        deps,
 	}
+	r.recorder = r.newRecorder(cfg, tempDir)
+	r.stopQueue = r.startQueueRetrier(cfg)
+	r.stopPurge = r.startCachePurger(cfg)
+	r.stopTimer = r.startRecordingTimer()
+	r.stopAPI = r.startHTTPAPI(cfg, tempDir)
+	r.stopGRPC = r.startGRPCAPI(cfg, tempDir)
+	r.stopMIDI = r.startMIDI(cfg)
+	r.stopSched = r.startScheduler(cfg)
+	r.stopClip = r.startClipboardWatch(cfg)
+	r.stopSession = r.startSessionWatch()
 	return r, nil
 }
 
@@ -106,6 +201,29 @@ func (r *Runtime) Snapshot() Event {
 	return Event{State: r.state, Message: r.lastMessage, Error: r.lastError}
 }
 
+// Status returns the current state with the recording time, profile and
+// last transcription latency.
+func (r *Runtime) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.statusLocked()
+}
+
+func (r *Runtime) statusLocked() Status {
+	s := Status{
+		Event:   Event{State: r.state, Message: r.lastMessage, Error: r.lastError},
+		Profile: r.cfg.Profile,
+		Latency: r.lastLatency,
+	}
+	switch r.state {
+	case StateRecording:
+		s.Elapsed = time.Since(r.started) - r.paused
+	case StatePaused:
+		s.Elapsed = r.pausedAt.Sub(r.started) - r.paused
+	}
+	return s
+}
+
 // Config returns the active config.
 func (r *Runtime) Config() config.Config {
 	r.mu.Lock()
@@ -137,10 +255,16 @@ func (r *Runtime) Reload(cfg config.Config) error {
 	}
 
 	config.InitCacheDir(&cfg)
-	asrClient, err := asr.New(cfg, newHTTPClient(cfg))
+	asrClient, err := r.deps.transcriber(cfg)
 	if err != nil {
 		return err
 	}
+	cacheCipher, err := openCacheCipher(cfg)
+	if err != nil {
+		return err
+	}
+	i18n.Set(cfg.UILang)
+	notify.SetQuietMode(cfg.QuietMode)
 
 	if r.stopHotkeys != nil {
 		r.stopHotkeys()
@@ -148,10 +272,64 @@ func (r *Runtime) Reload(cfg config.Config) error {
 	}
 
 	r.mu.Lock()
+	stopQueue := r.stopQueue
+	stopPurge := r.stopPurge
+	stopAPI := r.stopAPI
+	stopGRPC := r.stopGRPC
+	stopMIDI := r.stopMIDI
+	stopSched := r.stopSched
+	stopClip := r.stopClip
+	r.mu.Unlock()
+	if stopQueue != nil {
+		stopQueue()
+	}
+	if stopPurge != nil {
+		stopPurge()
+	}
+	if stopAPI != nil {
+		stopAPI()
+	}
+	if stopGRPC != nil {
+		stopGRPC()
+	}
+	if stopMIDI != nil {
+		stopMIDI()
+	}
+	if stopSched != nil {
+		stopSched()
+	}
+	if stopClip != nil {
+		stopClip()
+	}
+
+	setupCrashReports(cfg)
+	r.mu.Lock()
+	oldHistory := r.history
 	r.cfg = cfg
 	r.tempDir = config.TempDir(&cfg)
-	r.recorder = record.New(cfg, r.tempDir)
+	r.recorder = r.newRecorder(cfg, r.tempDir)
 	r.asrClient = asrClient
+	r.history = openHistory(cfg, cacheCipher)
+	r.cacheCipher = cacheCipher
+	r.mu.Unlock()
+	if oldHistory != nil {
+		_ = oldHistory.Close()
+	}
+	stopQueue = r.startQueueRetrier(cfg)
+	stopPurge = r.startCachePurger(cfg)
+	stopAPI = r.startHTTPAPI(cfg, config.TempDir(&cfg))
+	stopGRPC = r.startGRPCAPI(cfg, config.TempDir(&cfg))
+	stopMIDI = r.startMIDI(cfg)
+	stopSched = r.startScheduler(cfg)
+	stopClip = r.startClipboardWatch(cfg)
+	r.mu.Lock()
+	r.stopQueue = stopQueue
+	r.stopPurge = stopPurge
+	r.stopAPI = stopAPI
+	r.stopGRPC = stopGRPC
+	r.stopMIDI = stopMIDI
+	r.stopSched = stopSched
+	r.stopClip = stopClip
 	r.mu.Unlock()
 
 	if err := r.StartHotkeys(); err != nil {
@@ -162,6 +340,33 @@ func (r *Runtime) Reload(cfg config.Config) error {
 	return nil
 }
 
+// CheckEndpoint probes the configured ASR endpoint without uploading audio.
+// A failed probe is reported through the state handler and, when enabled,
+// a notification, so the user knows before recording.
+func (r *Runtime) CheckEndpoint(ctx context.Context) asr.ProbeResult {
+	r.mu.Lock()
+	cfg := r.cfg
+	asrClient := r.asrClient
+	r.mu.Unlock()
+
+	res := asrClient.Probe(ctx)
+	fmt.Printf("[probe] %s: %s\n", redact.Text(cfg.APIEndpoint), res.Summary())
+	if res.OK() {
+		return res
+	}
+	playCue(cfg, cfg.SoundError)
+	if cfg.Notification {
+		notifyFailure(i18n.T("ASR endpoint check failed"), errors.New(res.Summary()))
+	}
+	r.mu.Lock()
+	state := r.state
+	r.mu.Unlock()
+	if state == StateIdle || state == StateError {
+		r.setState(StateError, "ASR endpoint check failed", errors.New(res.Summary()))
+	}
+	return res
+}
+
 // StartHotkeys registers global hotkeys and wires them to runtime actions.
 func (r *Runtime) StartHotkeys() error {
 	r.mu.Lock()
@@ -178,6 +383,9 @@ func (r *Runtime) StartHotkeys() error {
 	if err != nil {
 		return err
 	}
+	if cfg.PTT && !reg.Releases() {
+		fmt.Printf("[hotkey] %s\n", i18n.T("push-to-talk is not available: this platform does not report hotkey releases, so the start hotkey toggles recording"))
+	}
 
 	r.mu.Lock()
 	r.stopHotkeys = reg.Stop
@@ -194,14 +402,69 @@ func (r *Runtime) Stop() {
 	stopHotkeys := r.stopHotkeys
 	r.stopHotkeys = nil
 	state := r.state
+	store := r.history
+	r.history = nil
+	stopQueue := r.stopQueue
+	r.stopQueue = nil
+	stopPurge := r.stopPurge
+	r.stopPurge = nil
+	stopTimer := r.stopTimer
+	r.stopTimer = nil
+	stopAPI := r.stopAPI
+	r.stopAPI = nil
+	stopGRPC := r.stopGRPC
+	r.stopGRPC = nil
+	stopMIDI := r.stopMIDI
+	r.stopMIDI = nil
+	stopSched := r.stopSched
+	r.stopSched = nil
+	stopClip := r.stopClip
+	r.stopClip = nil
+	stopSession := r.stopSession
+	r.stopSession = nil
+	levelMeter := r.meter
+	r.meter = nil
 	r.mu.Unlock()
 
 	if stopHotkeys != nil {
 		stopHotkeys()
 	}
+	if stopQueue != nil {
+		stopQueue()
+	}
+	if stopPurge != nil {
+		stopPurge()
+	}
+	if stopTimer != nil {
+		stopTimer()
+	}
+	if stopAPI != nil {
+		stopAPI()
+	}
+	if stopGRPC != nil {
+		stopGRPC()
+	}
+	if stopMIDI != nil {
+		stopMIDI()
+	}
+	if stopSched != nil {
+		stopSched()
+	}
+	if stopClip != nil {
+		stopClip()
+	}
+	if stopSession != nil {
+		stopSession()
+	}
 	if state == StateRecording || state == StatePaused {
 		_, _ = r.cancelRecording()
 	}
+	if store != nil {
+		_ = store.Close()
+	}
+	if levelMeter != nil {
+		levelMeter.Close()
+	}
 }
 
 // ToggleRecording starts recording when idle, otherwise stops and uploads.
@@ -226,7 +489,25 @@ func (r *Runtime) Cancel() Event {
 func (r *Runtime) HandleAction(id int) {
 	r.actionMu.Lock()
 	defer r.actionMu.Unlock()
+	r.handleActionLocked(id)
+}
+
+// handleActionIn runs the action like HandleAction, but only if the state is
+// still one of states once no other action is running.
+func (r *Runtime) handleActionIn(id int, states ...State) {
+	r.actionMu.Lock()
+	defer r.actionMu.Unlock()
 
+	r.mu.Lock()
+	state := r.state
+	r.mu.Unlock()
+	if slices.Contains(states, state) {
+		r.handleActionLocked(id)
+	}
+}
+
+func (r *Runtime) handleActionLocked(id int) {
+	defer r.recoverPanic("action")
 	switch id {
 	case 1:
 		r.toggleRecordingLocked()
@@ -234,51 +515,130 @@ func (r *Runtime) HandleAction(id int) {
 		r.togglePauseLocked()
 	case 3:
 		_, _ = r.cancelRecording()
+	case hotkey.StartReleased:
+		r.releaseStartLocked()
+	}
+}
+
+// releaseStartLocked stops and uploads the recording when push-to-talk is on
+// and the start hotkey comes back up.
+func (r *Runtime) releaseStartLocked() {
+	r.mu.Lock()
+	ptt := r.cfg.PTT
+	state := r.state
+	r.mu.Unlock()
+	if ptt && (state == StateRecording || state == StatePaused) {
+		_, _ = r.stopRecordingLocked()
 	}
 }
 
 func (r *Runtime) toggleRecordingLocked() {
 	r.mu.Lock()
 	state := r.state
-	recorder := r.recorder
-	cfg := r.cfg
 	r.mu.Unlock()
 
-	if state == StateIdle || state == StateError {
-		if err := recorder.Start(context.Background()); err != nil {
-			r.setState(StateError, "Recording start failed", err)
-			return
-		}
-		if cfg.Notification {
-			notify.Notify("STT", "Recording started")
-		}
-		r.setState(StateRecording, "Recording started", nil)
-		return
+	switch state {
+	case StateIdle, StateError:
+		_ = r.startRecordingLocked()
+	case StateRecording, StatePaused:
+		_, _ = r.stopRecordingLocked()
 	}
+}
+
+// StartRecording starts recording like the start hotkey, but returns once
+// the recorder runs or has failed to start.
+func (r *Runtime) StartRecording() error {
+	r.actionMu.Lock()
+	defer r.actionMu.Unlock()
+
+	r.mu.Lock()
+	state := r.state
+	r.mu.Unlock()
+	if state != StateIdle && state != StateError {
+		return fmt.Errorf("cannot start recording while %s", state)
+	}
+	return r.startRecordingLocked()
+}
 
+// StopRecording stops the active recording like the stop hotkey and waits
+// for its transcript. The text is "" when the ASR service heard nothing.
+func (r *Runtime) StopRecording() (string, error) {
+	r.actionMu.Lock()
+	defer r.actionMu.Unlock()
+
+	r.mu.Lock()
+	state := r.state
+	r.mu.Unlock()
 	if state != StateRecording && state != StatePaused {
-		return
+		return "", fmt.Errorf("not recording")
+	}
+	return r.stopRecordingLocked()
+}
+
+func (r *Runtime) startRecordingLocked() error {
+	r.mu.Lock()
+	recorder := r.recorder
+	cfg := r.cfg
+	if segmented(cfg) {
+		r.segments = newSegmentPipeline(cfg, r.asrClient, r.deps.Converter)
 	}
+	r.mu.Unlock()
+
+	if err := recorder.Start(context.Background()); err != nil {
+		r.takeSegments().discard()
+		r.setState(StateError, "Recording start failed", err)
+		return err
+	}
+	playCue(cfg, cfg.SoundStart)
+	if cfg.Notification {
+		notify.Notify("STT", i18n.T("Recording started"))
+	}
+	r.setState(StateRecording, "Recording started", nil)
+	return nil
+}
 
+func (r *Runtime) stopRecordingLocked() (string, error) {
+	r.mu.Lock()
+	recorder := r.recorder
+	cfg := r.cfg
+	r.mu.Unlock()
+
+	stopping := time.Now()
 	res, err := recorder.Stop()
+	t := timings{stop: time.Since(stopping)}
+	segments := r.takeSegments()
 	if err != nil {
+		segments.discard()
 		r.setState(StateError, "Recording stop failed", err)
-		return
+		return "", err
 	}
 	if res.Canceled {
+		segments.discard()
 		r.setState(StateIdle, "Recording canceled", nil)
-		return
+		return "", fmt.Errorf("recording canceled")
 	}
 	if res.Err != nil {
+		segments.discard()
+		var pe *record.PanicError
+		if errors.As(res.Err, &pe) {
+			crash.Handle("recorder", pe.Value, pe.Stack)
+		}
 		r.setState(StateError, "Recording failed", res.Err)
-		return
+		return "", res.Err
 	}
+	metrics.Recording(res.Duration)
 
+	playCue(cfg, cfg.SoundStop)
 	if cfg.Notification {
-		notify.Notify("STT", "Recording finished")
+		notify.Notify("STT", i18n.T("Recording finished"))
 	}
 	r.setState(StateUploading, "Uploading ASR request", nil)
-	r.transcribeResult(res)
+	if segments != nil {
+		if text, ok := r.transcribeSegments(res, segments, t); ok {
+			return text, nil
+		}
+	}
+	return r.transcribeResult(res, t)
 }
 
 func (r *Runtime) togglePauseLocked() {
@@ -315,6 +675,7 @@ func (r *Runtime) cancelRecording() (record.Result, error) {
 		return record.Result{}, nil
 	}
 	res, err := recorder.Cancel()
+	r.takeSegments().discard()
 	if err != nil {
 		r.setState(StateError, "Cancel failed", err)
 		return res, err
@@ -323,69 +684,240 @@ func (r *Runtime) cancelRecording() (record.Result, error) {
 	return res, nil
 }
 
-func (r *Runtime) transcribeResult(res record.Result) {
+// transcribeResult converts and uploads a finished recording and delivers
+// its text; t holds the time it took to finish the recording.
+func (r *Runtime) transcribeResult(res record.Result, t timings) (string, error) {
 	r.mu.Lock()
 	cfg := r.cfg
 	asrClient := r.asrClient
+	converter := r.deps.Converter
+	store := r.history
+	cacheCipher := r.cacheCipher
+	tempDir := r.tempDir
 	r.mu.Unlock()
 
-	outPath := strings.TrimSuffix(res.WavPath, filepath.Ext(res.WavPath)) + "." + config.ContainerExt(cfg.CONTAINER)
-	if err := ffmpeg.Convert(cfg, res.WavPath, outPath, cfg.SAMPLING_RATE); err != nil {
-		_ = os.Remove(res.WavPath)
-		_ = os.Remove(outPath)
-		r.setState(StateError, "FFmpeg conversion failed", err)
-		return
+	// Deferred so the notification is removed only after the text is pasted.
+	progress := newProgressNotice(cfg)
+	defer progress.done()
+	if offlineFirst(cfg) {
+		// Hold the queue lock from enqueue to upload so the background
+		// retrier cannot pick this recording up and transcribe it unpasted.
+		r.queueMu.Lock()
+		path, needsConvert := res.WavPath, true
+		if res.EncodedPath != "" {
+			path, needsConvert = res.EncodedPath, false
+		}
+		q, it, err := enqueueAudio(cfg, cacheCipher, path, queue.Item{Source: "record", Duration: res.Duration, NeedsConvert: needsConvert})
+		if err == nil {
+			start := time.Now()
+			text, qt, err := processQueued(withProgress(context.Background(), progress), cfg, asrClient, store, cacheCipher, tempDir, q, it, "record")
+			r.queueMu.Unlock()
+			r.recordLatency(time.Since(start), err)
+			t.convert, t.upload = qt.convert, qt.upload
+			r.deliverText(cfg, text, err, true, &t, func() {})
+			return text, err
+		}
+		r.queueMu.Unlock()
+		fmt.Printf("[queue] %s\n", i18n.Sprintf("failed to persist recording, uploading directly: %v", err))
 	}
 
-	text, raw, err := asrClient.Transcribe(context.Background(), outPath)
+	// Recordings streamed into ffmpeg are converted already.
+	outPath := res.EncodedPath
+	if outPath == "" {
+		outPath = strings.TrimSuffix(res.WavPath, filepath.Ext(res.WavPath)) + "." + config.ContainerExt(cfg.CONTAINER)
+		converting := time.Now()
+		err := converter.Convert(context.Background(), ffmpegOptions(cfg), res.WavPath, outPath, cfg.SAMPLING_RATE, progress.converting)
+		t.convert = time.Since(converting)
+		if err != nil {
+			_ = os.Remove(res.WavPath)
+			_ = os.Remove(outPath)
+			playCue(cfg, cfg.SoundError)
+			r.setState(StateError, "FFmpeg conversion failed", err)
+			return "", err
+		}
+	}
+
+	start := time.Now()
+	text, raw, attempts, err := upload(withProgress(context.Background(), progress), asrClient, outPath)
+	latency := time.Since(start)
+	t.upload = latency
+	r.recordLatency(latency, err)
 	uploadOk := err == nil
+	queued := false
+	var re *asr.RetryExhaustedError
+	if errors.As(err, &re) && queueEnabled(cfg) {
+		if _, _, qerr := enqueueAudio(cfg, cacheCipher, outPath, queue.Item{Source: "record", Duration: res.Duration, Attempts: attempts, LastError: err.Error()}); qerr != nil {
+			fmt.Printf("[queue] %s\n", i18n.Sprintf("failed to queue %s: %v", outPath, qerr))
+		} else {
+			queued = true
+			outPath = ""
+		}
+	}
+	r.deliverText(cfg, text, err, queued, &t, func() {
+		meta := newCacheMeta(cfg, "record", res.Duration, latency, attempts, text, err)
+		audioPath := handleCache(cfg, cacheCipher, res.WavPath, outPath, uploadOk, raw, meta)
+		recordHistory(store, cfg, history.Entry{
+			Source:      "record",
+			Duration:    res.Duration,
+			Text:        text,
+			Latency:     latency,
+			AudioPath:   audioPath,
+			Status:      historyStatus(text, err),
+			Error:       errorString(err),
+			Response:    raw,
+			StopTime:    t.stop,
+			ConvertTime: t.convert,
+			PasteTime:   t.paste,
+		})
+	})
+	return text, err
+}
+
+// recordLatency remembers how long a successful transcription took.
+func (r *Runtime) recordLatency(latency time.Duration, err error) {
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	r.lastLatency = latency
+	r.mu.Unlock()
+}
+
+// deliverText reports the outcome of a transcription and pastes its text.
+// queued tells whether a failed recording is waiting in the retry queue;
+// finish runs once the text has been pasted, to cache and record it, with
+// the paste time added to t. t is logged before finish runs.
+func (r *Runtime) deliverText(cfg config.Config, text string, err error, queued bool, t *timings, finish func()) {
+	done := finish
+	finish = func() {
+		fmt.Printf("[timing] %s\n", t)
+		done()
+	}
 	if err != nil {
+		playCue(cfg, uploadFailedCue(cfg))
 		if cfg.Notification {
-			notify.Notify("STT", "Upload failed")
+			if queued {
+				notifyFailure(i18n.T("Upload failed; recording queued for retry"), err)
+			} else {
+				notifyFailure(i18n.T("Upload failed"), err)
+			}
 		}
 		if cfg.RequestFailedNotification {
 			var re *asr.RetryExhaustedError
 			if errors.As(err, &re) {
-				if pasteErr := clipboard.PasteText("[request failed]"); pasteErr != nil {
-					fmt.Printf("[paste] failed: %v\n", pasteErr)
+				if pasteErr := r.deps.Paste("[request failed]"); pasteErr != nil {
+					fmt.Printf("[paste] %s\n", i18n.Sprintf("failed: %v", pasteErr))
 				} else if cfg.Notification {
-					notify.Notify("STT", "Request failed")
+					notify.Notify("STT", i18n.T("Request failed"))
 				}
 			}
 		}
-		handleCache(cfg, res.WavPath, outPath, uploadOk, raw)
+		finish()
 		r.setState(StateError, "Upload failed", err)
 		return
 	}
 
 	if text == "" {
 		if cfg.Notification {
-			notify.Notify("STT", "Empty result from ASR")
+			notify.Notify("STT", i18n.T("Empty result from ASR"))
 		}
-		handleCache(cfg, res.WavPath, outPath, uploadOk, raw)
+		finish()
 		r.setState(StateIdle, "Empty result from ASR", nil)
 		return
 	}
 
-	if err := clipboard.PasteText(text); err != nil {
+	r.publishTranscript(text)
+	r.mu.Lock()
+	latency := r.lastLatency
+	r.mu.Unlock()
+	go runPostCommand(cfg, "record", text, latency)
+	r.mu.Lock()
+	noPaste := r.noPaste
+	r.mu.Unlock()
+	if noPaste {
+		finish()
+		r.setState(StateIdle, "Transcription ready", nil)
+		return
+	}
+	pasting := time.Now()
+	err = r.deps.Paste(text)
+	t.paste = time.Since(pasting)
+	if err != nil {
+		playCue(cfg, cfg.SoundError)
 		if cfg.Notification {
-			notify.Notify("STT", "Paste failed")
+			notifyFailure(i18n.T("Paste failed"), err)
 		}
-		handleCache(cfg, res.WavPath, outPath, uploadOk, raw)
+		finish()
 		r.setState(StateError, "Paste failed", err)
 		return
 	}
 
+	playCue(cfg, cfg.SoundPasteSuccess)
 	if cfg.Notification {
-		notify.Notify("STT", "Paste success")
+		msg := i18n.T("Paste success")
+		if cfg.TimingNotification {
+			msg += "\n" + t.notice()
+		}
+		notify.Notify("STT", msg)
 	}
-	handleCache(cfg, res.WavPath, outPath, uploadOk, raw)
+	finish()
 	r.setState(StateIdle, "Transcription pasted", nil)
 }
 
+// SetPaste turns pasting transcripts into the focused window on or off. A
+// host that takes the text from StopRecording turns it off.
+func (r *Runtime) SetPaste(on bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.noPaste = !on
+}
+
+// uploadFailedCue returns SOUND_UPLOAD_FAILED, or SOUND_ERROR when it is
+// empty.
+func uploadFailedCue(cfg config.Config) string {
+	if cfg.SoundUploadFailed != "" {
+		return cfg.SoundUploadFailed
+	}
+	return cfg.SoundError
+}
+
+// notifyFailure shows a failure notification. While a log is written, the
+// notification opens a report of err and the recent log when clicked.
+func notifyFailure(msg string, err error) {
+	details, derr := applog.Details(msg, err)
+	if derr != nil {
+		fmt.Printf("[log] %s\n", i18n.Sprintf("failed to write error details: %v", derr))
+	}
+	if details == "" {
+		notify.Notify("STT", msg)
+		return
+	}
+	notify.NotifyFile("STT", msg+"\n"+i18n.T("Click for details"), details)
+}
+
+// playCue plays one of the SOUND_* cues unless SOUND_MUTE or quiet mode
+// silences it.
+func playCue(cfg config.Config, spec string) {
+	if cfg.SoundMute || spec == "" || notify.Suppressed() {
+		return
+	}
+	if err := sound.Play(spec); err != nil {
+		fmt.Printf("[sound] %v\n", err)
+	}
+}
+
 func (r *Runtime) setState(state State, message string, err error) {
 	var event Event
 	r.mu.Lock()
+	now := time.Now()
+	switch {
+	case state == StateRecording && r.state == StatePaused:
+		r.paused += now.Sub(r.pausedAt)
+	case state == StateRecording:
+		r.started, r.paused = now, 0
+	case state == StatePaused && r.state == StateRecording:
+		r.pausedAt = now
+	}
 	r.state = state
 	r.lastMessage = message
 	r.lastError = ""
@@ -396,82 +928,455 @@ func (r *Runtime) setState(state State, message string, err error) {
 	handler := r.onEvent
 	r.mu.Unlock()
 
+	r.showMeter(state)
+
 	if handler != nil {
 		handler(event)
 	}
 }
 
-// RunRecordMode starts hotkeys and blocks forever for CLI compatibility.
-func RunRecordMode(cfg config.Config) error {
+// newRecorder creates the recorder for cfg and feeds its levels to the level
+// meter. A recorder given to NewRuntimeWith is used as it is.
+func (r *Runtime) newRecorder(cfg config.Config, tempDir string) Recorder {
+	if r.deps.Recorder != nil {
+		return r.deps.Recorder
+	}
+	rec := record.New(recorderOptions(cfg), tempDir)
+	rec.OnLevel(func(l record.Level) {
+		r.mu.Lock()
+		m := r.meter
+		r.mu.Unlock()
+		if m != nil {
+			m.Push(l.Peak, l.RMS)
+		}
+	})
+	rec.OnSegment(func(s record.Segment) {
+		r.mu.Lock()
+		p := r.segments
+		r.mu.Unlock()
+		if p != nil {
+			p.add(s)
+		} else if s.WavPath != "" {
+			_ = os.Remove(s.WavPath)
+		}
+	})
+	return rec
+}
+
+// takeSegments returns the segment pipeline of the current recording, or
+// nil, and clears it.
+func (r *Runtime) takeSegments() *segmentPipeline {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.segments
+	r.segments = nil
+	return p
+}
+
+// showMeter shows the level meter while recording or paused when VU_METER
+// is on and hides it otherwise, opening its window the first time.
+func (r *Runtime) showMeter(state State) {
+	r.mu.Lock()
+	cfg := r.cfg
+	m := r.meter
+	r.mu.Unlock()
+
+	on := cfg.VUMeter && (state == StateRecording || state == StatePaused)
+	if m == nil {
+		if !on {
+			return
+		}
+		var err error
+		if m, err = meter.Open(); err != nil {
+			fmt.Printf("[meter] %v\n", err)
+			return
+		}
+		r.mu.Lock()
+		r.meter = m
+		r.mu.Unlock()
+	}
+	m.Show(on)
+}
+
+// spoolStdin copies the audio piped to stdin into a temporary file in
+// tempDir, since ffmpeg and the upload both need a file.
+func spoolStdin(stdin io.Reader, tempDir string) (string, error) {
+	path := tempOutputPath(tempDir, "input")
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	n, err := io.Copy(f, stdin)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && n == 0 {
+		err = fmt.Errorf("no audio on stdin")
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// CheckFFmpeg checks that recordings can be converted to CODECS in
+// CONTAINER, see ffmpeg.CheckFormat, and reports a failure like
+// CheckEndpoint, so a missing ffmpeg or encoder shows at startup rather
+// than after the first recording.
+func (r *Runtime) CheckFFmpeg(ctx context.Context) error {
+	r.mu.Lock()
+	cfg := r.cfg
+	tempDir := r.tempDir
+	r.mu.Unlock()
+
+	err := checkFFmpeg(ctx, cfg, ffmpegOptions(cfg), tempDir)
+	if err == nil {
+		return nil
+	}
+	fmt.Printf("[ffmpeg] %v\n", err)
+	playCue(cfg, cfg.SoundError)
+	if cfg.Notification {
+		notifyFailure(i18n.T("FFmpeg check failed"), err)
+	}
+	r.mu.Lock()
+	state := r.state
+	r.mu.Unlock()
+	if state == StateIdle || state == StateError {
+		r.setState(StateError, "FFmpeg check failed", err)
+	}
+	return err
+}
+
+// checkFFmpeg checks that audio can be converted with opts to the upload
+// format of cfg, within a time limit for a hanging ffmpeg.
+func checkFFmpeg(ctx context.Context, cfg config.Config, opts ffmpeg.Options, tempDir string) error {
+	ctx, cancel := context.WithTimeout(ctx, ffmpegCheckTimeout)
+	defer cancel()
+	return ffmpeg.CheckFormat(ctx, opts, config.ContainerExt(cfg.CONTAINER), tempDir)
+}
+
+// ffmpegCheckTimeout bounds checkFFmpeg.
+const ffmpegCheckTimeout = 30 * time.Second
+
+// RunRecordMode starts hotkeys and blocks until Quit is chosen from the tray
+// menu, or forever without a tray, for CLI compatibility. load re-reads the
+// config for the tray's Reload item; nil leaves the item out.
+func RunRecordMode(cfg config.Config, load func() (config.Config, error)) error {
 	r, err := NewRuntime(cfg)
 	if err != nil {
 		return err
 	}
+	quit := make(chan struct{})
+	var quitOnce sync.Once
+	var icon *tray.Tray
+	if cfg.Tray {
+		icon, err = r.startTray(load, func() { quitOnce.Do(func() { close(quit) }) })
+		if err != nil {
+			fmt.Printf("[tray] %v\n", err)
+		}
+	}
+	guide := newOnboarding(cfg)
 	r.SetEventHandler(func(event Event) {
+		guide.observe(event)
+		if icon != nil {
+			icon.Set(trayStatus(event.State), trayTooltip(r.Status()))
+		}
 		if event.Error != "" {
-			fmt.Printf("[state] %s: %s (%s)\n", event.State, event.Message, event.Error)
+			fmt.Printf("[state] %s: %s (%s)\n", event.State, i18n.T(event.Message), event.Error)
 			return
 		}
-		fmt.Printf("[state] %s: %s\n", event.State, event.Message)
+		fmt.Printf("[state] %s: %s\n", event.State, i18n.T(event.Message))
 	})
 	if err := r.StartHotkeys(); err != nil {
+		if icon != nil {
+			icon.Close()
+		}
 		return err
 	}
-	fmt.Println("[main] ready. Use hotkeys to start/stop/pause/cancel.")
-	for {
-		time.Sleep(time.Hour)
+	_ = r.CheckFFmpeg(context.Background())
+	var probe asr.ProbeResult
+	if cfg.StartupCheck || guide != nil {
+		probe = r.CheckEndpoint(context.Background())
+	}
+	if cfg.UpdateCheck {
+		go checkForUpdate(cfg)
+	}
+	fmt.Println("[main] " + i18n.T("ready. Use hotkeys to start/stop/pause/cancel."))
+	guide.begin(probe)
+	if icon == nil {
+		for {
+			time.Sleep(time.Hour)
+		}
 	}
+	go r.tickTooltip(icon, quit)
+	<-quit
+	r.Stop()
+	icon.Close()
+	return nil
 }
 
-// RunFileMode uploads an existing file and writes the result to a .txt file.
+// InputDevices lists the devices INPUT_DEVICE can select.
+func InputDevices() ([]string, error) {
+	return record.Devices()
+}
+
+// InputDeviceDetails describes the devices INPUT_DEVICE can select, for
+// -list-devices.
+func InputDeviceDetails() ([]record.DeviceInfo, error) {
+	return record.DeviceDetails()
+}
+
+// RunFileMode uploads an existing file and writes the result to a file named
+// after it in OUTPUT_FORMAT, e.g. a .txt. inputPath may also be a folder or a
+// glob pattern, whose files are each transcribed into such a file next to
+// them, or into the folder outputPath.
 func RunFileMode(cfg config.Config, inputPath string, outputPath string) error {
+	return runFileMode(cfg, inputPath, outputPath, nil, nil, nil)
+}
+
+// RunStdinMode transcribes the audio read from stdin, for `-file -`, and
+// writes the transcript to stdout, or to outputPath when set.
+func RunStdinMode(cfg config.Config, stdin io.Reader, stdout io.Writer, outputPath string) error {
+	return runFileMode(cfg, "-", outputPath, stdin, stdout, nil)
+}
+
+// ReportFileMode runs RunFileMode, or RunStdinMode when stdin is set, and
+// returns the outcome for -json. The transcript of stdin goes only into the
+// report unless outputPath is set.
+func ReportFileMode(cfg config.Config, inputPath, outputPath string, stdin io.Reader) FileReport {
+	report := FileReport{Files: []FileResult{}}
+	if stdin != nil {
+		inputPath = "-"
+	}
+	err := runFileMode(cfg, inputPath, outputPath, stdin, io.Discard, &report)
+	report.ExitCode = ExitCode(err)
+	report.Error = errorString(err)
+	return report
+}
+
+// runFileMode transcribes the file, folder or pattern inputPath, or stdin
+// when set, and adds the outcome for each file to report unless it is nil.
+// The error carries the exit code; see ExitCode.
+func runFileMode(cfg config.Config, inputPath, outputPath string, stdin io.Reader, stdout io.Writer, report *FileReport) error {
 	if err := config.Validate(&cfg); err != nil {
-		return err
+		return withExitCode(ExitConfig, err)
 	}
+	notify.SetQuietMode(cfg.QuietMode)
 	config.InitCacheDir(&cfg)
 	tempDir := config.TempDir(&cfg)
 	cleanupOldTempFiles(tempDir)
 
-	if _, err := os.Stat(inputPath); err != nil {
-		return fmt.Errorf("file '%s' stat failed: %w", inputPath, err)
+	name, audioPath := inputPath, ""
+	if stdin != nil {
+		spooled, err := spoolStdin(stdin, tempDir)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(spooled)
+		inputPath = spooled
+	} else {
+		audioPath, _ = filepath.Abs(inputPath)
 	}
-
-	asrClient, err := asr.New(cfg, newHTTPClient(cfg))
+	files, batch, err := fileInputs(cfg, inputPath)
 	if err != nil {
 		return err
 	}
+	// Measuring durations for FILE_MIN_SECONDS and FILE_MAX_SECONDS takes
+	// ffmpeg as well.
+	measure := batch && (cfg.FileMinSeconds > 0 || cfg.FileMaxSeconds > 0)
+	if measure || slices.ContainsFunc(files, func(f string) bool { return !uploadableAsIs(cfg, f) }) {
+		if err := checkFFmpeg(context.Background(), cfg, fileOptions(cfg), tempDir); err != nil {
+			return withExitCode(ExitConvert, err)
+		}
+	}
 
-	tempOut := tempOutputPath(tempDir, config.ContainerExt(cfg.CONTAINER))
-	if err := ffmpeg.Convert(cfg, inputPath, tempOut, cfg.SAMPLING_RATE); err != nil {
-		_ = os.Remove(tempOut)
-		return err
+	asrClient, err := newASRClient(cfg, newHTTPClient(cfg))
+	if err != nil {
+		return withExitCode(ExitConfig, err)
 	}
 
-	text, raw, err := asrClient.Transcribe(context.Background(), tempOut)
-	uploadOk := err == nil
+	cacheCipher, err := openCacheCipher(cfg)
 	if err != nil {
-		if cfg.Notification {
-			notify.Notify("STT", "Upload failed")
-		}
-		handleCache(cfg, "", tempOut, uploadOk, raw)
+		return withExitCode(ExitConfig, err)
+	}
+	store := openHistory(cfg, cacheCipher)
+	if store != nil {
+		defer store.Close()
+	}
+	if batch {
+		return runFileBatch(cfg, asrClient, store, cacheCipher, tempDir, files, outputPath, report)
+	}
+	text, raw, latency, err := transcribeFile(context.Background(), cfg, asrClient, store, cacheCipher, tempDir, inputPath, audioPath)
+	res := FileResult{File: name, Text: text, LatencyMS: latency.Milliseconds()}
+	if err != nil {
+		report.add(res, err)
+		return err
+	}
+	if text != "" {
+		runPostCommand(cfg, "file", text, latency)
+	}
+	out, err := formatTranscript(cfg, text, raw)
+	if err != nil {
+		report.add(res, err)
 		return err
 	}
 
+	if stdin != nil && outputPath == "" {
+		_, err := fmt.Fprintln(stdout, strings.TrimRight(string(out), "\n"))
+		report.add(res, err)
+		return err
+	}
 	outPath := outputPath
 	if outPath == "" {
 		base := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
-		outPath = filepath.Join(".", base+".txt")
+		outPath = filepath.Join(".", base+"."+outputFormat(cfg))
+	}
+	err = os.WriteFile(outPath, out, 0644)
+	if err == nil {
+		res.Output = outPath
 	}
+	report.add(res, err)
+	return err
+}
 
-	if err := os.WriteFile(outPath, []byte(text), 0644); err != nil {
-		handleCache(cfg, "", tempOut, uploadOk, raw)
-		return err
+// transcribeFile converts and uploads the audio file at inputPath, keeps the
+// cache files and records the attempt in history with source "file".
+// audioPath is the path history records when the cache keeps no copy. Files
+// longer than FILE_CHUNK_SECONDS, or larger than MAX_UPLOAD_MB once
+// converted, are transcribed in chunks. It returns the transcript, the
+// service's response and the upload latency.
+func transcribeFile(ctx context.Context, cfg config.Config, asrClient Transcriber, store *history.Store, cacheCipher *cachecrypt.Cipher, tempDir, inputPath, audioPath string) (string, []byte, time.Duration, error) {
+	progress := newProgressNotice(cfg)
+	defer progress.done()
+	p := prepareFile(ctx, cfg, tempDir, inputPath, progress)
+	return uploadPrepared(ctx, cfg, asrClient, store, cacheCipher, tempDir, inputPath, audioPath, p, progress)
+}
+
+// preparedFile is a file made ready for upload by prepareFile.
+type preparedFile struct {
+	// out is the converted file, or "" when the file is transcribed in
+	// chunks of length chunk.
+	out string
+	// total is the duration of the file, or 0 when it is unknown.
+	total time.Duration
+	chunk time.Duration
+	// convert is how long converting it took.
+	convert time.Duration
+	err     error
+}
+
+// prepareFile converts the file at inputPath for upload, or picks the chunks
+// it is transcribed in, which are converted as they are uploaded.
+func prepareFile(ctx context.Context, cfg config.Config, tempDir, inputPath string, progress *progressNotice) preparedFile {
+	if total, length := fileChunking(ctx, cfg, inputPath, 0); length > 0 {
+		return preparedFile{total: total, chunk: length}
 	}
+	tempOut := tempOutputPath(tempDir, config.ContainerExt(cfg.CONTAINER))
+	converting := time.Now()
+	if err := convertForUpload(ctx, cfg, inputPath, tempOut, progress.converting); err != nil {
+		_ = os.Remove(tempOut)
+		return preparedFile{err: withExitCode(ExitConvert, err)}
+	}
+	convert := time.Since(converting)
+	var total time.Duration
+	if info, err := os.Stat(tempOut); err == nil && cfg.MaxUploadMB > 0 {
+		var length time.Duration
+		if total, length = fileChunking(ctx, cfg, inputPath, info.Size()); length > 0 {
+			_ = os.Remove(tempOut)
+			return preparedFile{total: total, chunk: length}
+		}
+	}
+	if total == 0 {
+		// Only for history and batch summaries, so a failure goes unreported.
+		total, _ = ffmpeg.Duration(ctx, ffmpegOptions(cfg), inputPath)
+	}
+	return preparedFile{out: tempOut, total: total, convert: convert}
+}
 
-	handleCache(cfg, "", tempOut, uploadOk, raw)
+// uploadPrepared uploads a file prepareFile made ready and handles the result
+// like transcribeFile. A failed conversion returns its error without a
+// latency.
+func uploadPrepared(ctx context.Context, cfg config.Config, asrClient Transcriber, store *history.Store, cacheCipher *cachecrypt.Cipher, tempDir, inputPath, audioPath string, p preparedFile, progress *progressNotice) (string, []byte, time.Duration, error) {
+	if p.err != nil {
+		return "", nil, 0, p.err
+	}
+	if p.chunk > 0 {
+		return transcribeChunks(ctx, cfg, asrClient, store, tempDir, inputPath, audioPath, p.total, p.chunk, progress)
+	}
+
+	start := time.Now()
+	text, raw, attempts, err := upload(withProgress(ctx, progress), asrClient, p.out)
+	err = withExitCode(ExitUpload, err)
+	latency := time.Since(start)
+	fmt.Printf("[timing] %s: %s\n", inputPath, timings{convert: p.convert, upload: latency})
+	meta := newCacheMeta(cfg, "file", p.total, latency, attempts, text, err)
+	if kept := handleCache(cfg, cacheCipher, "", p.out, err == nil, raw, meta); kept != "" {
+		audioPath = kept
+	}
+	recordHistory(store, cfg, history.Entry{
+		Source:      "file",
+		Duration:    p.total,
+		Text:        text,
+		Latency:     latency,
+		AudioPath:   audioPath,
+		Status:      historyStatus(text, err),
+		Error:       errorString(err),
+		Response:    raw,
+		ConvertTime: p.convert,
+	})
+	if err != nil {
+		playCue(cfg, uploadFailedCue(cfg))
+		if cfg.Notification {
+			notifyFailure(i18n.T("Upload failed"), err)
+		}
+		return "", nil, latency, err
+	}
+	return text, raw, latency, nil
+}
+
+// convertForUpload converts the file at inputPath into the upload format
+// at out. A file already in that format, at the configured sample rate and
+// channels, is copied instead, which saves the conversion and a lossy
+// re-encode.
+func convertForUpload(ctx context.Context, cfg config.Config, inputPath, out string, report func(float64)) error {
+	if !uploadableAsIs(cfg, inputPath) {
+		return ffmpeg.ConvertContext(ctx, fileOptions(cfg), inputPath, out, cfg.SAMPLING_RATE, report)
+	}
+	fmt.Printf("[ffmpeg] %s\n", i18n.Sprintf("%s is already %s/%s, skipping conversion", inputPath, cfg.CODECS, cfg.CONTAINER))
+	if err := copyFile(inputPath, out); err != nil {
+		return err
+	}
+	if report != nil {
+		report(1)
+	}
 	return nil
 }
 
+// uploadableAsIs reports whether the file at path can be uploaded without
+// converting it; see ffmpeg.Info.Matches.
+func uploadableAsIs(cfg config.Config, path string) bool {
+	info, err := ffmpeg.Probe(path)
+	return err == nil && info.Matches(fileOptions(cfg), config.ContainerExt(cfg.CONTAINER))
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
 func newHTTPClient(cfg config.Config) *http.Client {
 	tr := &http.Transport{
 		MaxIdleConns:          100,
@@ -482,6 +1387,10 @@ func newHTTPClient(cfg config.Config) *http.Client {
 	}
 	if !cfg.VerifySSL {
 		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	} else if pool, err := config.CACertPool(&cfg); err != nil {
+		fmt.Printf("[upload] %s\n", i18n.Sprintf("%v; using the system certificates", err))
+	} else if pool != nil {
+		tr.TLSClientConfig = &tls.Config{RootCAs: pool}
 	}
 	if cfg.EnableHTTP2 {
 		_ = http2.ConfigureTransport(tr)
@@ -495,7 +1404,7 @@ func newHTTPClient(cfg config.Config) *http.Client {
 func cleanupOldTempFiles(dir string) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
-		fmt.Printf("[cleanup] read dir '%s' failed: %v\n", dir, err)
+		fmt.Printf("[cleanup] %s\n", i18n.Sprintf("read dir '%s' failed: %v", dir, err))
 		return
 	}
 	for _, e := range entries {
@@ -503,43 +1412,95 @@ func cleanupOldTempFiles(dir string) {
 		if strings.HasPrefix(name, "RecordTemp_") {
 			path := filepath.Join(dir, name)
 			if err := os.Remove(path); err != nil {
-				fmt.Printf("[cleanup] failed remove %s: %v\n", path, err)
+				fmt.Printf("[cleanup] %s\n", i18n.Sprintf("failed remove %s: %v", path, err))
 			} else {
-				fmt.Printf("[cleanup] removed %s\n", path)
+				fmt.Printf("[cleanup] %s\n", i18n.Sprintf("removed %s", path))
 			}
 		}
 	}
 }
 
-func handleCache(cfg config.Config, wavPath string, outPath string, uploadOk bool, resBody []byte) {
+// handleCache keeps or removes the temporary audio files and returns the
+// cached path of the uploaded audio (the WAV when only KEEP_WAV applies), or
+// "" when nothing was kept. The response
+// JSON is only written as a file when the history database is disabled; the
+// transcript (<name>.txt) and meta are always written alongside the kept
+// files. With c set, kept audio and
+// response files are encrypted and get the cachecrypt.Ext suffix.
+func handleCache(cfg config.Config, c *cachecrypt.Cipher, wavPath string, outPath string, uploadOk bool, resBody []byte, meta cacheMeta) string {
+	kept := ""
 	if cfg.KeepCache && cfg.CacheDir != "" {
-		timestamp := time.Now().Format("2006-01-02-15.04.05")
-		base := fmt.Sprintf("audio-%s", timestamp)
+		now := time.Now()
+		dir := filepath.Join(cfg.CacheDir, cachepath.Dir(cfg.CacheLayout, now))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			fmt.Printf("[cache] %s\n", i18n.Sprintf("cannot create %s: %v. Keeping files in cache-dir.", dir, err))
+			dir = cfg.CacheDir
+		}
+		base, err := cachepath.Reserve(dir, cfg.CacheName, cachepath.Fields{
+			Time:     now,
+			Profile:  cfg.Profile,
+			Language: meta.Language,
+			Model:    meta.Model,
+			Provider: meta.Provider,
+			Source:   meta.Source,
+			Machine:  meta.Machine,
+		}, cacheMetaExt)
+		if err != nil {
+			fmt.Printf("[cache] %s\n", i18n.Sprintf("cannot name cache files in %s: %v. Falling back to a timestamp.", dir, err))
+			base = fmt.Sprintf("audio-%s", now.Format("2006-01-02-15.04.05"))
+		}
 
-		if wavPath != "" {
+		keptWav := ""
+		if wavPath != "" && !cfg.KeepWav {
+			_ = os.Remove(wavPath)
+		} else if wavPath != "" {
 			wavExt := filepath.Ext(wavPath)
-			newWav := filepath.Join(cfg.CacheDir, base+wavExt)
-			if err := os.Rename(wavPath, newWav); err != nil {
-				fmt.Printf("[cache] failed to rename wav to %s: %v\n", newWav, err)
+			newWav, err := keepCacheFile(c, wavPath, filepath.Join(dir, base+wavExt))
+			if err != nil {
+				fmt.Printf("[cache] %s\n", i18n.Sprintf("failed to keep wav as %s: %v", newWav, err))
 				_ = os.Remove(wavPath)
+			} else {
+				keptWav = newWav
+				meta.Audio = filepath.Base(newWav)
 			}
 		}
 
-		if outPath != "" {
+		if outPath != "" && !cfg.KeepConverted {
+			_ = os.Remove(outPath)
+		} else if outPath != "" {
 			outExt := filepath.Ext(outPath)
-			newOut := filepath.Join(cfg.CacheDir, base+outExt)
-			if err := os.Rename(outPath, newOut); err != nil {
-				fmt.Printf("[cache] failed to rename output to %s: %v\n", newOut, err)
+			newOut, err := keepCacheFile(c, outPath, filepath.Join(dir, base+outExt))
+			if err != nil {
+				fmt.Printf("[cache] %s\n", i18n.Sprintf("failed to keep output as %s: %v", newOut, err))
 				_ = os.Remove(outPath)
+			} else {
+				kept = newOut
+				meta.Encoded = filepath.Base(newOut)
+			}
+		}
+		if kept == "" {
+			kept = keptWav
+		}
+
+		if uploadOk && len(resBody) > 0 && !historyEnabled(cfg) {
+			jsonPath, err := writeCacheFile(c, filepath.Join(dir, base+".json"), resBody)
+			if err != nil {
+				fmt.Printf("[cache] %s\n", i18n.Sprintf("failed to write json to %s: %v", jsonPath, err))
+			} else {
+				meta.Response = filepath.Base(jsonPath)
 			}
 		}
 
-		if uploadOk && len(resBody) > 0 {
-			jsonPath := filepath.Join(cfg.CacheDir, base+".json")
-			if err := os.WriteFile(jsonPath, resBody, 0644); err != nil {
-				fmt.Printf("[cache] failed to write json to %s: %v\n", jsonPath, err)
+		if uploadOk && meta.text != "" {
+			txtPath, err := writeCacheFile(c, filepath.Join(dir, base+".txt"), []byte(meta.text))
+			if err != nil {
+				fmt.Printf("[cache] %s\n", i18n.Sprintf("failed to write transcript to %s: %v", txtPath, err))
+			} else {
+				meta.Transcript = filepath.Base(txtPath)
 			}
 		}
+
+		writeCacheMeta(filepath.Join(dir, base+cacheMetaExt), meta)
 	} else {
 		if wavPath != "" {
 			_ = os.Remove(wavPath)
@@ -548,6 +1509,35 @@ func handleCache(cfg config.Config, wavPath string, outPath string, uploadOk boo
 			_ = os.Remove(outPath)
 		}
 	}
+	return kept
+}
+
+// keepCacheFile moves src to dst, encrypting it to dst+cachecrypt.Ext when c
+// is set, and returns the final path.
+func keepCacheFile(c *cachecrypt.Cipher, src, dst string) (string, error) {
+	if c == nil {
+		return dst, atomicfile.Move(src, dst)
+	}
+	dst += cachecrypt.Ext
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return dst, err
+	}
+	if err := c.WriteFile(dst, data, 0644); err != nil {
+		return dst, err
+	}
+	_ = os.Remove(src)
+	return dst, nil
+}
+
+// writeCacheFile writes data to path, encrypting it to path+cachecrypt.Ext
+// when c is set, and returns the final path.
+func writeCacheFile(c *cachecrypt.Cipher, path string, data []byte) (string, error) {
+	if c == nil {
+		return path, atomicfile.WriteFile(path, data, 0644)
+	}
+	path += cachecrypt.Ext
+	return path, c.WriteFile(path, data, 0644)
 }
 
 func tempOutputPath(dir, ext string) string {
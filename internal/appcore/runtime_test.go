@@ -13,14 +13,25 @@
 package appcore
 
 import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"stt/internal/cachecrypt"
 	"stt/internal/config"
+	"stt/internal/history"
+	"stt/internal/i18n"
+	"stt/pkg/asr"
 )
 
 func TestRuntimeSnapshotAndEventHandler(t *testing.T) {
@@ -30,6 +41,7 @@ func TestRuntimeSnapshotAndEventHandler(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewRuntime failed: %v", err)
 	}
+	t.Cleanup(r.Stop)
 
 	var got Event
 	r.SetEventHandler(func(event Event) { got = event })
@@ -66,6 +78,50 @@ func TestNewHTTPClientHonorsConfig(t *testing.T) {
 	}
 }
 
+func TestNewHTTPClientTrustsCACertFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	if _, err := newHTTPClient(cfg).Get(server.URL); err == nil {
+		t.Fatalf("request to a self-signed server succeeded without CA_CERT_FILE")
+	}
+	cfg.CACertFile = filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(cfg.CACertFile, ca, 0644); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := newHTTPClient(cfg).Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with CA_CERT_FILE failed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewASRClientRejectsInvalidExtraConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ExtraConfig = `{"unterminated"`
+
+	if _, err := newASRClient(cfg, nil); err == nil {
+		t.Fatalf("newASRClient succeeded, want invalid extra-config error")
+	}
+}
+
+func TestOptionsFollowConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CODECS = "mp3"
+	cfg.FFMPEG_PATH = `C:\ffmpeg\ffmpeg.exe`
+	cfg.InputDevice = "USB"
+	cfg.RECORD_DEBUG = true
+
+	if got := ffmpegOptions(cfg); got.Codec != "mp3" || got.Path != cfg.FFMPEG_PATH || got.SampleRate != cfg.SAMPLING_RATE {
+		t.Fatalf("ffmpegOptions = %+v", got)
+	}
+	if got := recorderOptions(cfg); got.InputDevice != "USB" || !got.Debug || got.Channels != cfg.Channels {
+		t.Fatalf("recorderOptions = %+v", got)
+	}
+}
+
 func TestCleanupOldTempFilesOnlyRemovesRecordTemps(t *testing.T) {
 	dir := t.TempDir()
 	remove := filepath.Join(dir, "RecordTemp_old.wav")
@@ -100,7 +156,7 @@ func TestHandleCacheRemovesFilesWhenKeepCacheDisabled(t *testing.T) {
 
 	cfg := config.DefaultConfig()
 	cfg.KeepCache = false
-	handleCache(cfg, wav, out, true, []byte(`{"text":"ok"}`))
+	handleCache(cfg, nil, wav, out, true, []byte(`{"text":"ok"}`), cacheMeta{})
 
 	if _, err := os.Stat(wav); !os.IsNotExist(err) {
 		t.Fatalf("wav still exists or stat error was unexpected: %v", err)
@@ -124,18 +180,37 @@ func TestHandleCacheKeepsAudioAndResponseWhenEnabled(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.CacheDir = dir
 	cfg.KeepCache = true
-	handleCache(cfg, wav, out, true, []byte(`{"text":"ok"}`))
+	cfg.CacheLayout = ""
+	cfg.History = false
+	handleCache(cfg, nil, wav, out, true, []byte(`{"text":"ok"}`), cacheMeta{Source: "record", Attempts: 2})
 
 	matches, err := filepath.Glob(filepath.Join(dir, "audio-*"))
 	if err != nil {
 		t.Fatalf("Glob failed: %v", err)
 	}
 	counts := map[string]int{}
+	var metaPath string
 	for _, match := range matches {
+		if strings.HasSuffix(match, ".meta.json") {
+			metaPath = match
+			continue
+		}
 		counts[filepath.Ext(match)]++
 	}
-	if counts[".wav"] != 1 || counts[".ogg"] != 1 || counts[".json"] != 1 {
-		t.Fatalf("cached file counts = %#v from matches %#v, want one .wav, .ogg, and .json", counts, matches)
+	if counts[".wav"] != 1 || counts[".ogg"] != 1 || counts[".json"] != 1 || metaPath == "" {
+		t.Fatalf("cached file counts = %#v from matches %#v, want one .wav, .ogg, .json, and .meta.json", counts, matches)
+	}
+
+	b, err := os.ReadFile(metaPath)
+	if err != nil {
+		t.Fatalf("ReadFile meta failed: %v", err)
+	}
+	var meta cacheMeta
+	if err := json.Unmarshal(b, &meta); err != nil {
+		t.Fatalf("meta is not valid JSON: %v", err)
+	}
+	if meta.Attempts != 2 || filepath.Ext(meta.Audio) != ".wav" || filepath.Ext(meta.Encoded) != ".ogg" || filepath.Ext(meta.Response) != ".json" {
+		t.Fatalf("meta = %#v, want attempts and cached file names", meta)
 	}
 	if _, err := os.Stat(wav); !os.IsNotExist(err) {
 		t.Fatalf("original wav still exists or stat error was unexpected: %v", err)
@@ -158,3 +233,713 @@ func TestTempOutputPathUsesDirectoryAndExtension(t *testing.T) {
 		t.Fatalf("tempOutputPath base = %q, unexpected length", got)
 	}
 }
+
+func TestHandleCacheRecordsResponseInHistoryInsteadOfJSON(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "output.ogg")
+	if err := os.WriteFile(out, []byte("out"), 0644); err != nil {
+		t.Fatalf("WriteFile out failed: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = dir
+	cfg.KeepCache = true
+	cfg.APIEndpoint = "https://api.openai.com/v1/audio/transcriptions"
+	cfg.Model = "whisper-1"
+	store := openHistory(cfg, nil)
+	if store == nil {
+		t.Fatalf("openHistory returned nil with CACHE_DIR set")
+	}
+	defer store.Close()
+
+	kept := handleCache(cfg, nil, "", out, true, []byte(`{"text":"ok"}`), cacheMeta{})
+	if filepath.Ext(kept) != ".ogg" {
+		t.Fatalf("handleCache returned %q, want cached .ogg path", kept)
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "audio-*.json"))
+	for _, match := range matches {
+		if !strings.HasSuffix(match, ".meta.json") {
+			t.Fatalf("response JSON written despite history: %v", matches)
+		}
+	}
+
+	recordHistory(store, cfg, history.Entry{Source: "file", Text: "ok", AudioPath: kept, Status: history.StatusOK, Response: []byte(`{"text":"ok"}`)})
+	entries, err := store.Recent(10)
+	if err != nil {
+		t.Fatalf("Recent failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Provider != "openai" || entries[0].Model != "whisper-1" || entries[0].AudioPath != kept {
+		t.Fatalf("history entries = %#v", entries)
+	}
+}
+
+func TestHandleCacheEncryptsKeptFiles(t *testing.T) {
+	dir := t.TempDir()
+	wav := filepath.Join(dir, "input.wav")
+	out := filepath.Join(dir, "output.ogg")
+	if err := os.WriteFile(wav, []byte("wav"), 0644); err != nil {
+		t.Fatalf("WriteFile wav failed: %v", err)
+	}
+	if err := os.WriteFile(out, []byte("out"), 0644); err != nil {
+		t.Fatalf("WriteFile out failed: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = dir
+	cfg.KeepCache = true
+	cfg.CacheLayout = ""
+	cfg.History = false
+	cfg.CacheEncryption = cachecrypt.ModePassphrase
+	cfg.CachePassphrase = "pw"
+	c, err := openCacheCipher(cfg)
+	if err != nil || c == nil {
+		t.Fatalf("openCacheCipher = %v, %v; want cipher", c, err)
+	}
+	kept := handleCache(cfg, c, wav, out, true, []byte(`{"text":"ok"}`), cacheMeta{})
+	if !strings.HasSuffix(kept, ".ogg"+cachecrypt.Ext) {
+		t.Fatalf("handleCache returned %q, want encrypted .ogg path", kept)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "audio-*"+cachecrypt.Ext))
+	if len(matches) != 3 {
+		t.Fatalf("encrypted files = %v, want .wav, .ogg, and .json", matches)
+	}
+	for _, match := range matches {
+		raw, err := os.ReadFile(match)
+		if err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+		if !cachecrypt.IsSealed(raw) {
+			t.Fatalf("%s is not encrypted", match)
+		}
+	}
+	if got, err := c.ReadFile(kept); err != nil || string(got) != "out" {
+		t.Fatalf("decrypted %s = %q, %v; want out", kept, got, err)
+	}
+}
+
+func TestHandleCacheUsesDateLayout(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "output.ogg")
+	if err := os.WriteFile(out, []byte("out"), 0644); err != nil {
+		t.Fatalf("WriteFile out failed: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = dir
+	cfg.KeepCache = true
+	cfg.CacheLayout = "kept/{yyyy}"
+	year := time.Now().Format("2006")
+	kept := handleCache(cfg, nil, "", out, true, nil, cacheMeta{})
+
+	if filepath.Dir(kept) != filepath.Join(dir, "kept", year) {
+		t.Fatalf("handleCache kept %q, want it under kept/%s", kept, year)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "kept", year, "audio-*.meta.json")); len(matches) != 1 {
+		t.Fatalf("metadata files = %v, want one next to the audio", matches)
+	}
+}
+
+func TestHandleCacheNameTemplateAvoidsCollisions(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = dir
+	cfg.KeepCache = true
+	cfg.CacheLayout = ""
+	cfg.CacheName = "{date}_{profile}_{lang}_{seq}"
+	cfg.Profile = "work"
+
+	var kept []string
+	for i := 0; i < 2; i++ {
+		out := filepath.Join(dir, "output.ogg")
+		if err := os.WriteFile(out, []byte("out"), 0644); err != nil {
+			t.Fatalf("WriteFile out failed: %v", err)
+		}
+		kept = append(kept, handleCache(cfg, nil, "", out, true, nil, cacheMeta{Language: "zh"}))
+	}
+
+	prefix := time.Now().Format("2006-01-02") + "_work_zh_"
+	if kept[0] == kept[1] {
+		t.Fatalf("both recordings kept as %s", kept[0])
+	}
+	for i, k := range kept {
+		if want := prefix + []string{"01", "02"}[i] + ".ogg"; filepath.Base(k) != want {
+			t.Fatalf("kept[%d] = %s, want %s", i, filepath.Base(k), want)
+		}
+	}
+}
+
+func TestHandleCacheKeepsOnlySelectedAudio(t *testing.T) {
+	dir := t.TempDir()
+	wav := filepath.Join(dir, "RecordTemp_1.wav")
+	out := filepath.Join(dir, "RecordTemp_1.ogg")
+	for _, p := range []string{wav, out} {
+		if err := os.WriteFile(p, []byte("audio"), 0644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = dir
+	cfg.KeepCache = true
+	cfg.CacheLayout = ""
+	cfg.KeepWav = false
+	kept := handleCache(cfg, nil, wav, out, true, nil, cacheMeta{})
+
+	if filepath.Ext(kept) != ".ogg" {
+		t.Fatalf("handleCache kept %q, want the converted file", kept)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.wav")); len(matches) != 0 {
+		t.Fatalf("WAV files left with KEEP_WAV off: %v", matches)
+	}
+}
+
+func TestHandleCacheWritesTranscript(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "RecordTemp_1.ogg")
+	if err := os.WriteFile(out, []byte("out"), 0644); err != nil {
+		t.Fatalf("WriteFile out failed: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = dir
+	cfg.KeepCache = true
+	cfg.CacheLayout = ""
+	meta := newCacheMeta(cfg, "record", time.Second, time.Second, 1, "hello world", nil)
+	kept := handleCache(cfg, nil, "", out, true, []byte(`{"text":"hello world"}`), meta)
+
+	txt := strings.TrimSuffix(kept, filepath.Ext(kept)) + ".txt"
+	if b, err := os.ReadFile(txt); err != nil || string(b) != "hello world" {
+		t.Fatalf("transcript %s = %q, %v; want hello world", txt, b, err)
+	}
+	b, err := os.ReadFile(strings.TrimSuffix(kept, filepath.Ext(kept)) + cacheMetaExt)
+	if err != nil || !strings.Contains(string(b), `"transcript": "`+filepath.Base(txt)+`"`) {
+		t.Fatalf("meta = %s, %v; want it to name the transcript", b, err)
+	}
+}
+
+func TestUploadFailedCueFallsBackToErrorSound(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SoundError = "SystemHand"
+	if got := uploadFailedCue(cfg); got != "SystemHand" {
+		t.Fatalf("uploadFailedCue = %q, want the SOUND_ERROR fallback", got)
+	}
+	cfg.SoundUploadFailed = "SystemExclamation"
+	if got := uploadFailedCue(cfg); got != "SystemExclamation" {
+		t.Fatalf("uploadFailedCue = %q, want SOUND_UPLOAD_FAILED", got)
+	}
+}
+
+func TestOnboardingFinishesAfterPastedTestRecording(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	guide := newOnboarding(cfg)
+	if guide == nil {
+		t.Fatal("newOnboarding returned nil on first run")
+	}
+	marker := filepath.Join(cfg.CacheDir, onboardingMarker)
+	guide.observe(Event{State: StateUploading})
+	guide.observe(Event{State: StateIdle, Message: "Transcription pasted"})
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatalf("marker written before the guide began: %v", err)
+	}
+
+	guide.begin(asr.ProbeResult{})
+	guide.observe(Event{State: StateUploading, Message: "Uploading ASR request"})
+	guide.observe(Event{State: StateIdle, Message: "Empty result from ASR"})
+	guide.observe(Event{State: StateIdle, Message: "Transcription pasted"})
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatalf("marker written without a pasted test recording: %v", err)
+	}
+	guide.observe(Event{State: StateUploading, Message: "Uploading ASR request"})
+	guide.observe(Event{State: StateIdle, Message: "Transcription pasted"})
+	if _, err := os.Stat(marker); err != nil {
+		t.Fatalf("marker not written: %v", err)
+	}
+	if newOnboarding(cfg) != nil {
+		t.Fatal("newOnboarding should return nil once the guide is complete")
+	}
+	cfg.Onboarding = false
+	if err := os.Remove(marker); err != nil {
+		t.Fatal(err)
+	}
+	if newOnboarding(cfg) != nil {
+		t.Fatal("newOnboarding should return nil when ONBOARDING is off")
+	}
+}
+
+func TestStatusLeavesPausesOutOfElapsed(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Profile = "work"
+	r, err := NewRuntime(cfg)
+	if err != nil {
+		t.Fatalf("NewRuntime failed: %v", err)
+	}
+	t.Cleanup(r.Stop)
+
+	r.setState(StateRecording, "Recording started", nil)
+	r.setState(StatePaused, "Recording paused", nil)
+	r.mu.Lock()
+	r.started = r.started.Add(-10 * time.Second)
+	r.pausedAt = r.pausedAt.Add(-4 * time.Second)
+	r.mu.Unlock()
+	r.setState(StateRecording, "Recording resumed", nil)
+	if got := r.Status().Elapsed; got < 6*time.Second || got > 7*time.Second {
+		t.Fatalf("Elapsed = %v, want about 6s", got)
+	}
+	r.recordLatency(1500*time.Millisecond, nil)
+	r.recordLatency(time.Minute, errors.New("failed"))
+
+	prev := i18n.Current()
+	i18n.Set("en")
+	t.Cleanup(func() { i18n.Set(string(prev)) })
+	want := "STT - Recording resumed\nRecorded 0:06\nProfile: work\nLast transcription: 1.5s"
+	if got := trayTooltip(r.Status()); got != want {
+		t.Fatalf("trayTooltip = %q, want %q", got, want)
+	}
+}
+
+func TestRecordingTimerStopsAtMaxRecordSeconds(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.MaxRecordSeconds = 5
+	r, err := NewRuntime(cfg)
+	if err != nil {
+		t.Fatalf("NewRuntime failed: %v", err)
+	}
+	t.Cleanup(r.Stop)
+
+	r.setState(StateRecording, "Recording started", nil)
+	r.mu.Lock()
+	r.started = r.started.Add(-6 * time.Second)
+	started := r.started
+	r.mu.Unlock()
+
+	r.stopRecording(started.Add(-time.Minute))
+	if got := r.Snapshot().State; got != StateRecording {
+		t.Fatalf("state after stopping an earlier recording = %s, want %s", got, StateRecording)
+	}
+
+	// The recorder never started, so the stop the timer triggers fails,
+	// which shows that it was attempted.
+	var timer recordingTimer
+	r.tickRecording(&timer)
+	deadline := time.Now().Add(2 * time.Second)
+	for r.Snapshot().State == StateRecording && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := r.Snapshot(); got.State != StateError || got.Message != "Recording stop failed" {
+		t.Fatalf("state after reaching the limit = %+v, want a failed stop", got)
+	}
+}
+
+func TestControlChecksStateAndReportsStatus(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Profile = "work"
+	r, err := NewRuntime(cfg)
+	if err != nil {
+		t.Fatalf("NewRuntime failed: %v", err)
+	}
+	t.Cleanup(r.Stop)
+
+	missing := "transcribe " + filepath.Join(t.TempDir(), "missing.mp3")
+	for _, request := range []string{"stop", "pause", "resume", "cancel", "unknown", missing} {
+		if _, err := r.Control(request); err == nil {
+			t.Fatalf("Control(%q) while idle succeeded, want an error", request)
+		}
+	}
+
+	r.setState(StateRecording, "Recording started", nil)
+	r.mu.Lock()
+	r.started = r.started.Add(-3 * time.Second)
+	r.mu.Unlock()
+	if _, err := r.Control("start"); err == nil {
+		t.Fatal("Control(start) while recording succeeded, want an error")
+	}
+	reply, err := r.Control("status")
+	if err != nil {
+		t.Fatalf("Control(status) failed: %v", err)
+	}
+	var got StatusReport
+	if err := json.Unmarshal([]byte(reply), &got); err != nil {
+		t.Fatalf("status reply %q is not JSON: %v", reply, err)
+	}
+	if got.State != StateRecording || got.Profile != "work" || got.ElapsedSeconds < 3 || got.ElapsedSeconds > 4 {
+		t.Fatalf("status = %+v, want Recording for about 3s with profile work", got)
+	}
+}
+
+func TestStartAndStopRecordingCheckState(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	r, err := NewRuntime(cfg)
+	if err != nil {
+		t.Fatalf("NewRuntime failed: %v", err)
+	}
+	t.Cleanup(r.Stop)
+
+	if _, err := r.StopRecording(); err == nil {
+		t.Fatal("StopRecording while idle succeeded, want an error")
+	}
+	r.setState(StateUploading, "Uploading ASR request", nil)
+	if err := r.StartRecording(); err == nil {
+		t.Fatal("StartRecording while uploading succeeded, want an error")
+	}
+}
+
+func TestJobFilesSkipsTranscribedAndOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.mp3", "b.WAV", "b.txt", "c.m4a", "notes.docx"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "c.txt"), []byte("done"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files, err := jobFiles(dir)
+	if err != nil {
+		t.Fatalf("jobFiles failed: %v", err)
+	}
+	if len(files) != 1 || files[0] != filepath.Join(dir, "a.mp3") {
+		t.Fatalf("jobFiles = %v, want only a.mp3", files)
+	}
+	if files, err := jobFiles(filepath.Join(dir, "a.mp3")); err != nil || len(files) != 1 {
+		t.Fatalf("jobFiles(file) = %v, %v", files, err)
+	}
+	if _, err := jobFiles(filepath.Join(dir, "missing")); err == nil {
+		t.Fatalf("jobFiles(missing) succeeded")
+	}
+}
+
+func TestFileInputsExpandsFoldersAndPatterns(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub.m4a", "deep"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.m4a", "b.wav", "notes.txt", filepath.Join("sub.m4a", "c.m4a"), filepath.Join("sub.m4a", "deep", "d.webm")} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plain := config.DefaultConfig()
+	recursive := plain
+	recursive.Recursive = true
+	filtered := recursive
+	filtered.ExcludeExtensions = "webm"
+	included := plain
+	included.IncludeExtensions = ".txt, .WAV"
+	tests := []struct {
+		cfg   config.Config
+		path  string
+		want  []string
+		batch bool
+	}{
+		{cfg: plain, path: filepath.Join(dir, "b.wav"), want: []string{"b.wav"}},
+		{cfg: plain, path: dir, want: []string{"a.m4a", "b.wav"}, batch: true},
+		{cfg: plain, path: filepath.Join(dir, "*.m4a"), want: []string{"a.m4a"}, batch: true},
+		{cfg: recursive, path: dir, want: []string{"a.m4a", "b.wav", "c.m4a", "d.webm"}, batch: true},
+		{cfg: recursive, path: filepath.Join(dir, "*.m4a"), want: []string{"a.m4a", "c.m4a"}, batch: true},
+		{cfg: filtered, path: dir, want: []string{"a.m4a", "b.wav", "c.m4a"}, batch: true},
+		{cfg: included, path: dir, want: []string{"b.wav", "notes.txt"}, batch: true},
+	}
+	for _, tt := range tests {
+		files, batch, err := fileInputs(tt.cfg, tt.path)
+		if err != nil {
+			t.Fatalf("fileInputs(%s) failed: %v", tt.path, err)
+		}
+		var got []string
+		for _, f := range files {
+			got = append(got, filepath.Base(f))
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") || batch != tt.batch {
+			t.Errorf("fileInputs(%s) = %v, %v; want %v, %v", tt.path, got, batch, tt.want, tt.batch)
+		}
+	}
+	if _, _, err := fileInputs(plain, filepath.Join(dir, "*.mp3")); err == nil {
+		t.Errorf("pattern without matches succeeded")
+	}
+	if _, _, err := fileInputs(plain, filepath.Join(dir, "missing.wav")); err == nil {
+		t.Errorf("missing file succeeded")
+	}
+}
+
+func TestAlreadyTranscribedChecksOutputAndHistory(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.OutputFormat = "srt"
+	if err := os.WriteFile(filepath.Join(dir, "a.srt"), []byte("1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A .txt is not what this run writes.
+	if err := os.WriteFile(filepath.Join(dir, "c.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := history.Open(history.Path(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if _, err := store.Add(history.Entry{Source: "file", AudioPath: filepath.Join(dir, "b.m4a"), Status: history.StatusOK}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]bool{"a.m4a": true, "b.m4a": true, "c.m4a": false} {
+		if got := alreadyTranscribed(cfg, store, filepath.Join(dir, name), ""); got != want {
+			t.Errorf("alreadyTranscribed(%s) = %v, want %v", name, got, want)
+		}
+	}
+	if alreadyTranscribed(cfg, nil, filepath.Join(dir, "b.m4a"), "") {
+		t.Errorf("alreadyTranscribed without history found b.m4a")
+	}
+}
+
+func TestMediaFilesKeepsExistingAudio(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.MP3", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "folder.wav"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	paths := []string{
+		filepath.Join(dir, "a.MP3"),
+		filepath.Join(dir, "notes.txt"),
+		filepath.Join(dir, "folder.wav"),
+		filepath.Join(dir, "missing.wav"),
+	}
+	if files := mediaFiles(paths); len(files) != 1 || files[0] != paths[0] {
+		t.Fatalf("mediaFiles = %v, want only a.MP3", files)
+	}
+}
+
+func TestConvertForUploadCopiesMatchingFile(t *testing.T) {
+	dir := t.TempDir()
+	// A 16 kHz mono 16-bit WAV header with an empty data chunk.
+	wav := []byte("RIFF\x24\x00\x00\x00WAVEfmt \x10\x00\x00\x00\x01\x00\x01\x00\x80\x3e\x00\x00\x00\x7d\x00\x00\x02\x00\x10\x00data\x00\x00\x00\x00")
+	in := filepath.Join(dir, "in.wav")
+	if err := os.WriteFile(in, wav, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.CODECS, cfg.CONTAINER = "pcm", "wav"
+	cfg.FFMPEG_PATH = filepath.Join(dir, "missing-ffmpeg")
+	if !uploadableAsIs(cfg, in) {
+		t.Fatalf("matching WAV is not uploadable as is")
+	}
+	out := filepath.Join(dir, "out.wav")
+	if err := convertForUpload(context.Background(), cfg, in, out, nil); err != nil {
+		t.Fatalf("convertForUpload failed: %v", err)
+	}
+	if got, _ := os.ReadFile(out); string(got) != string(wav) {
+		t.Fatalf("output differs from input")
+	}
+
+	cfg.SAMPLING_RATE = 8000
+	if uploadableAsIs(cfg, in) {
+		t.Fatalf("16 kHz WAV is uploadable as 8 kHz")
+	}
+}
+
+func TestTranscribeFilesReportsInOrder(t *testing.T) {
+	var uploads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"text":"text %d"}`, uploads.Add(1))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	wav := []byte("RIFF\x24\x00\x00\x00WAVEfmt \x10\x00\x00\x00\x01\x00\x01\x00\x80\x3e\x00\x00\x00\x7d\x00\x00\x02\x00\x10\x00data\x00\x00\x00\x00")
+	files := []string{filepath.Join(dir, "a.wav"), filepath.Join(dir, "b.mp3"), filepath.Join(dir, "c.wav")}
+	for _, f := range files {
+		data := wav
+		if strings.HasSuffix(f, ".mp3") {
+			data = []byte("not audio")
+		}
+		if err := os.WriteFile(f, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := config.DefaultConfig()
+	cfg.CODECS, cfg.CONTAINER = "pcm", "wav"
+	cfg.FFMPEG_PATH = filepath.Join(dir, "missing-ffmpeg")
+	cfg.APIEndpoint = server.URL
+	cfg.TEXTPath = "text"
+	cfg.MaxRetry = 0
+	cfg.ConvertWorkers = 3
+	cfg.Notification = false
+	client, err := newASRClient(cfg, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("newASRClient failed: %v", err)
+	}
+	tempDir := filepath.Join(dir, "temp")
+	if err := os.Mkdir(tempDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	transcribeFiles(context.Background(), cfg, client, nil, nil, tempDir, files, func(res fileResult) {
+		got = append(got, fmt.Sprintf("%s: %q %v", filepath.Base(res.file), res.text, res.err != nil))
+	})
+	// b.mp3 needs converting, and ffmpeg is missing.
+	want := []string{`a.wav: "text 1" false`, `b.mp3: "" true`, `c.wav: "text 2" false`}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("results = %q, want %q", got, want)
+	}
+	if left, _ := os.ReadDir(tempDir); len(left) != 0 {
+		t.Fatalf("converted files left in the temp dir: %v", left)
+	}
+}
+
+func TestTranscribeFilesUploadsUpToTheLimitAtOnce(t *testing.T) {
+	var active, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(30 * time.Millisecond)
+		active.Add(-1)
+		_, _ = w.Write([]byte(`{"text":"ok"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	wav := []byte("RIFF\x24\x00\x00\x00WAVEfmt \x10\x00\x00\x00\x01\x00\x01\x00\x80\x3e\x00\x00\x00\x7d\x00\x00\x02\x00\x10\x00data\x00\x00\x00\x00")
+	var files []string
+	for i := range 6 {
+		f := filepath.Join(dir, fmt.Sprintf("%d.wav", i))
+		if err := os.WriteFile(f, wav, 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, f)
+	}
+	cfg := config.DefaultConfig()
+	cfg.CODECS, cfg.CONTAINER = "pcm", "wav"
+	cfg.FFMPEG_PATH = filepath.Join(dir, "missing-ffmpeg")
+	cfg.APIEndpoint = server.URL
+	cfg.TEXTPath = "text"
+	cfg.ConvertWorkers = 1
+	cfg.MaxConcurrentUploads = 3
+	cfg.Notification = false
+	client, err := newASRClient(cfg, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("newASRClient failed: %v", err)
+	}
+
+	done := 0
+	transcribeFiles(context.Background(), cfg, client, nil, nil, t.TempDir(), files, func(res fileResult) {
+		if res.err != nil {
+			t.Errorf("%s: %v", res.file, res.err)
+		}
+		done++
+	})
+	if done != len(files) || peak.Load() != 3 {
+		t.Fatalf("%d files done with up to %d uploads at once, want %d with 3", done, peak.Load(), len(files))
+	}
+}
+
+func TestRunStdinModePrintsTranscript(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"text":"piped text"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = dir
+	cfg.CODECS, cfg.CONTAINER = "pcm", "wav"
+	cfg.APIEndpoint = server.URL
+	cfg.TEXTPath = "text"
+	cfg.Notification = false
+	wav := "RIFF\x24\x00\x00\x00WAVEfmt \x10\x00\x00\x00\x01\x00\x01\x00\x80\x3e\x00\x00\x00\x7d\x00\x00\x02\x00\x10\x00data\x00\x00\x00\x00"
+
+	var out strings.Builder
+	if err := RunStdinMode(cfg, strings.NewReader(wav), &out, ""); err != nil {
+		t.Fatalf("RunStdinMode failed: %v", err)
+	}
+	if out.String() != "piped text\n" {
+		t.Fatalf("stdout = %q, want the transcript", out.String())
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "RecordTemp_") {
+			t.Fatalf("temporary file %s left behind", e.Name())
+		}
+	}
+	if err := RunStdinMode(cfg, strings.NewReader(""), &out, ""); err == nil {
+		t.Fatalf("empty stdin succeeded")
+	}
+}
+
+func TestReportFileModeExitCodes(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 1 {
+			http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"text":"first"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	wav := "RIFF\x24\x00\x00\x00WAVEfmt \x10\x00\x00\x00\x01\x00\x01\x00\x80\x3e\x00\x00\x00\x7d\x00\x00\x02\x00\x10\x00data\x00\x00\x00\x00"
+	for _, name := range []string{"a.wav", "b.wav"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(wav), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.CODECS, cfg.CONTAINER = "pcm", "wav"
+	cfg.APIEndpoint = server.URL
+	cfg.TEXTPath = "text"
+	cfg.MaxRetry = 1
+	cfg.Notification = false
+
+	report := ReportFileMode(cfg, dir, t.TempDir(), nil)
+	if report.ExitCode != ExitPartial || report.Succeeded != 1 || report.Failed != 1 || len(report.Files) != 2 {
+		t.Fatalf("report = %+v, want one file transcribed and one failed", report)
+	}
+	if f := report.Files[0]; f.Text != "first" || f.Output == "" || f.ExitCode != ExitOK {
+		t.Fatalf("first file = %+v, want its transcript", f)
+	}
+	if f := report.Files[1]; f.ExitCode != ExitUpload || f.Error == "" {
+		t.Fatalf("second file = %+v, want an upload error", f)
+	}
+
+	// Every file failing gives the code of the failure.
+	if report := ReportFileMode(cfg, dir, t.TempDir(), nil); report.ExitCode != ExitUpload || report.Failed != 2 {
+		t.Fatalf("report = %+v, want both uploads failed", report)
+	}
+	if err := RunFileMode(cfg, filepath.Join(dir, "a.wav"), filepath.Join(t.TempDir(), "a.txt")); ExitCode(err) != ExitUpload {
+		t.Fatalf("single file exit code = %d (%v), want %d", ExitCode(err), err, ExitUpload)
+	}
+	cfg.Channels = 0
+	if err := RunFileMode(cfg, dir, ""); ExitCode(err) != ExitConfig {
+		t.Fatalf("invalid config exit code = %d (%v), want %d", ExitCode(err), err, ExitConfig)
+	}
+}
+
+func TestProgressNoticeLogsWithoutNotification(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Notification = false
+	p := newProgressNotice(cfg)
+	if p == nil || p.show {
+		t.Fatalf("newProgressNotice = %+v, want a notice that only logs", p)
+	}
+	p.converting(0.42)
+	if p.label != "" {
+		t.Fatalf("progress reported before progressDelay")
+	}
+	p.start = p.start.Add(-progressDelay)
+	p.converting(0.42)
+	if p.label != "Converting %d%%…" || p.pct != 42 || p.n != nil {
+		t.Fatalf("after converting: label %q pct %d notification %v", p.label, p.pct, p.n)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"stt/internal/config"
+	"stt/internal/i18n"
+	"stt/internal/notify"
+	"stt/internal/schedule"
+)
+
+// scheduleTick is how often the scheduler checks for due jobs. Checking
+// against the wall clock rather than sleeping until the next run keeps jobs
+// on time across clock changes, and a run missed while the computer was
+// asleep happens once it wakes.
+const scheduleTick = 20 * time.Second
+
+// folderExts are the audio and video files picked up from a folder by a
+// scheduled job or -file.
+var folderExts = []string{".wav", ".mp3", ".m4a", ".aac", ".flac", ".ogg", ".opus", ".wma", ".mp4", ".webm", ".mkv", ".mov", ".avi", ".wmv", ".m4v"}
+
+// startScheduler runs the SCHEDULE jobs at their times. The returned func
+// stops the scheduler and cancels the upload of a job that is running.
+func (r *Runtime) startScheduler(cfg config.Config) func() {
+	if cfg.Schedule == "" || r.serviceMode {
+		return func() {}
+	}
+	jobs, err := schedule.Parse(cfg.Schedule)
+	if err != nil {
+		fmt.Printf("[schedule] %v\n", err)
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		next := make([]time.Time, len(jobs))
+		for i, j := range jobs {
+			next[i] = j.Next(time.Now())
+			fmt.Printf("[schedule] %s\n", i18n.Sprintf("'%s' -> %s, next run %s", j.Spec, j.Path, next[i].Format("2006-01-02 15:04")))
+		}
+		ticker := time.NewTicker(scheduleTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			now := time.Now()
+			for i, j := range jobs {
+				if next[i].IsZero() || now.Before(next[i]) {
+					continue
+				}
+				r.runJob(ctx, j, next[i])
+				next[i] = j.Next(time.Now())
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// runJob transcribes the audio files of a job that was due at at, writing
+// each transcript to a .txt next to its file. Files that already have one
+// are skipped, so a folder can be scheduled again and again. Up to
+// CONVERT_WORKERS files are converted in parallel.
+func (r *Runtime) runJob(ctx context.Context, j schedule.Job, at time.Time) {
+	path := j.PathAt(at)
+	files, err := jobFiles(path)
+	if err != nil {
+		fmt.Printf("[schedule] %s: %v\n", path, err)
+		return
+	}
+	if len(files) == 0 {
+		fmt.Printf("[schedule] %s\n", i18n.Sprintf("%s: nothing new to transcribe", path))
+		return
+	}
+
+	r.mu.Lock()
+	cfg := r.cfg
+	asrClient := r.asrClient
+	store := r.history
+	cacheCipher := r.cacheCipher
+	tempDir := r.tempDir
+	r.mu.Unlock()
+
+	done := 0
+	transcribeFiles(ctx, cfg, asrClient, store, cacheCipher, tempDir, files, func(res fileResult) {
+		if res.err != nil {
+			fmt.Printf("[schedule] %s: %v\n", res.file, res.err)
+			return
+		}
+		if err := os.WriteFile(transcriptPath(res.file), []byte(res.text), 0644); err != nil {
+			fmt.Printf("[schedule] %s: %v\n", res.file, err)
+			return
+		}
+		if res.text != "" {
+			runPostCommand(cfg, "file", res.text, res.latency)
+		}
+		done++
+	})
+	if ctx.Err() != nil {
+		return
+	}
+	fmt.Printf("[schedule] %s\n", i18n.Sprintf("%s: transcribed %d of %d files", path, done, len(files)))
+	if cfg.Notification {
+		notify.Notify("STT", i18n.Sprintf("Scheduled job transcribed %d of %d files in %s", done, len(files), path))
+	}
+}
+
+// jobFiles returns the audio files at path without a transcript yet: path
+// itself when it is a file, otherwise the audio files directly inside it.
+func jobFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = folderFiles(path); err != nil {
+			return nil, err
+		}
+	}
+	return slices.DeleteFunc(files, func(f string) bool {
+		_, err := os.Stat(transcriptPath(f))
+		return err == nil
+	}), nil
+}
+
+// folderFiles returns the audio and video files directly inside dir, in
+// name order.
+func folderFiles(dir string) ([]string, error) {
+	return walkFiles(dir, false, func(name string) bool {
+		return slices.Contains(folderExts, strings.ToLower(filepath.Ext(name)))
+	})
+}
+
+// transcriptPath is the .txt a job writes next to an audio file.
+func transcriptPath(audioPath string) string {
+	return strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + ".txt"
+}
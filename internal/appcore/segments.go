@@ -0,0 +1,183 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"stt/internal/config"
+	"stt/internal/history"
+	"stt/internal/i18n"
+	"stt/internal/transcript"
+	"stt/pkg/record"
+)
+
+// segmentPipeline converts and uploads the segments of a recording while it
+// continues, when RECORD_SEGMENT_SECONDS is set, so that only the last one
+// is left to transcribe once the recording stops.
+type segmentPipeline struct {
+	cfg     config.Config
+	client  Transcriber
+	conv    Converter
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	texts   []string
+	raws    [][]byte
+	spans   []chunkSpan
+	err     error
+	uploads int
+}
+
+// segmented reports whether recordings made with cfg are transcribed in
+// segments. Offline-first recordings go through the retry queue whole.
+func segmented(cfg config.Config) bool {
+	return cfg.RecordSegmentSeconds > 0 && !offlineFirst(cfg)
+}
+
+func newSegmentPipeline(cfg config.Config, client Transcriber, conv Converter) *segmentPipeline {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &segmentPipeline{cfg: cfg, client: client, conv: conv, ctx: ctx, cancel: cancel}
+}
+
+// add starts transcribing seg. It is the recorder's OnSegment callback and
+// does not block.
+func (p *segmentPipeline) add(seg record.Segment) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.spans) <= seg.Index {
+		p.texts = append(p.texts, "")
+		p.raws = append(p.raws, nil)
+		p.spans = append(p.spans, chunkSpan{})
+	}
+	p.spans[seg.Index] = chunkSpan{Start: seg.Start, Length: seg.Duration}
+	if seg.Err != nil {
+		p.failLocked(seg.Err)
+		return
+	}
+	if seg.WavPath == "" {
+		return
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer os.Remove(seg.WavPath)
+		text, raw, attempts, err := p.transcribe(seg.WavPath)
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.uploads += attempts
+		if err != nil {
+			p.failLocked(fmt.Errorf("segment %d: %w", seg.Index+1, err))
+			return
+		}
+		if p.cfg.RECORD_DEBUG {
+			fmt.Printf("[record] transcribed segment %d\n", seg.Index+1)
+		}
+		p.texts[seg.Index], p.raws[seg.Index] = text, raw
+	}()
+}
+
+// transcribe converts the segment at wavPath and uploads it.
+func (p *segmentPipeline) transcribe(wavPath string) (string, []byte, int, error) {
+	out := strings.TrimSuffix(wavPath, filepath.Ext(wavPath)) + "." + config.ContainerExt(p.cfg.CONTAINER)
+	defer os.Remove(out)
+	if err := p.conv.Convert(p.ctx, ffmpegOptions(p.cfg), wavPath, out, p.cfg.SAMPLING_RATE, nil); err != nil {
+		return "", nil, 0, err
+	}
+	return upload(p.ctx, p.client, out)
+}
+
+// failLocked keeps the first error, which stops the other segments.
+func (p *segmentPipeline) failLocked(err error) {
+	if p.err == nil {
+		p.err = err
+		p.cancel()
+	}
+}
+
+// wait waits for the segments of a stopped recording and returns their
+// joined transcript, with the timed segments of their responses merged
+// when there are any, and the number of upload attempts made.
+func (p *segmentPipeline) wait() (string, []byte, int, error) {
+	p.wg.Wait()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.cancel()
+	if p.err != nil {
+		return "", nil, p.uploads, p.err
+	}
+	text := ""
+	for _, t := range p.texts {
+		text = joinTranscripts(text, strings.TrimSpace(t))
+	}
+	var raw []byte
+	if segs := chunkSegments(p.spans, p.raws, p.cfg.AudioSpeed); len(segs) > 0 {
+		raw = transcript.Response(text, segs)
+	} else if len(p.raws) == 1 {
+		raw = p.raws[0]
+	}
+	return text, raw, p.uploads, nil
+}
+
+// discard stops the segments of a recording that is not transcribed; they
+// remove their files as they stop. p may be nil.
+func (p *segmentPipeline) discard() {
+	if p != nil {
+		p.cancel()
+	}
+}
+
+// transcribeSegments delivers the transcript of a recording from its
+// segments and reports whether it did; when a segment failed, the recording
+// is left to be transcribed whole. The cache keeps the recording as it was
+// written, a WAV or the file it was streamed into. t holds the time it took
+// to finish the recording; the wait for the last segments counts as upload.
+func (r *Runtime) transcribeSegments(res record.Result, p *segmentPipeline, t timings) (string, bool) {
+	r.mu.Lock()
+	cfg := r.cfg
+	store := r.history
+	cacheCipher := r.cacheCipher
+	r.mu.Unlock()
+
+	start := time.Now()
+	text, raw, attempts, err := p.wait()
+	if err != nil {
+		fmt.Printf("[record] %s\n", i18n.Sprintf("segmented upload failed, transcribing the whole recording: %v", err))
+		return "", false
+	}
+	latency := time.Since(start)
+	t.upload = latency
+	r.recordLatency(latency, nil)
+	r.deliverText(cfg, text, nil, false, &t, func() {
+		meta := newCacheMeta(cfg, "record", res.Duration, latency, attempts, text, nil)
+		audioPath := handleCache(cfg, cacheCipher, res.WavPath, res.EncodedPath, true, raw, meta)
+		recordHistory(store, cfg, history.Entry{
+			Source:    "record",
+			Duration:  res.Duration,
+			Text:      text,
+			Latency:   latency,
+			AudioPath: audioPath,
+			Status:    historyStatus(text, nil),
+			Response:  raw,
+			StopTime:  t.stop,
+			PasteTime: t.paste,
+		})
+	})
+	return text, true
+}
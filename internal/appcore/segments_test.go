@@ -0,0 +1,89 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+package appcore
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stt/internal/config"
+	"stt/pkg/record"
+)
+
+func TestSegmentPipelineJoinsSegmentsAndFallsBackOnFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the ffmpeg stand-in is a shell script")
+	}
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"text":" part "}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	wav := []byte("RIFF\x24\x00\x00\x00WAVEfmt \x10\x00\x00\x00\x01\x00\x01\x00\x80\x3e\x00\x00\x00\x7d\x00\x00\x02\x00\x10\x00data\x00\x00\x00\x00")
+	segment := func(i int) record.Segment {
+		path := filepath.Join(dir, fmt.Sprintf("RecordTemp_x_%d.wav", i))
+		if err := os.WriteFile(path, wav, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return record.Segment{Index: i, WavPath: path, Start: time.Duration(i) * 30 * time.Second, Duration: 30 * time.Second}
+	}
+	cfg := config.DefaultConfig()
+	// A stand-in for ffmpeg that writes a placeholder as the converted file.
+	cfg.FFMPEG_PATH = filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(cfg.FFMPEG_PATH, []byte("#!/bin/sh\nfor a; do out=$a; done\nprintf ogg > \"$out\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg.APIEndpoint = server.URL
+	cfg.TEXTPath = "text"
+	cfg.MaxRetry = 0
+	cfg.Notification = false
+	client, err := newASRClient(cfg, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("newASRClient failed: %v", err)
+	}
+
+	p := newSegmentPipeline(cfg, client, ffmpegConverter{})
+	// Segments may arrive while earlier ones are still uploading; the last
+	// one can be empty when the recording stopped just after a segment ended.
+	p.add(segment(1))
+	p.add(segment(0))
+	p.add(record.Segment{Index: 2, Start: time.Minute, Last: true})
+	text, _, attempts, err := p.wait()
+	if err != nil || text != "part part" || attempts != 2 {
+		t.Fatalf("wait = %q, %d attempts, %v; want both parts in 2", text, attempts, err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Fatalf("%d segment files left behind", len(entries))
+	}
+
+	fail.Store(true)
+	p = newSegmentPipeline(cfg, client, ffmpegConverter{})
+	p.add(segment(0))
+	if _, _, _, err := p.wait(); err == nil || !strings.Contains(err.Error(), "segment 1") {
+		t.Fatalf("wait error = %v, want segment 1 failing", err)
+	}
+}
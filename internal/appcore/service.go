@@ -0,0 +1,48 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"stt/internal/config"
+	"stt/internal/i18n"
+)
+
+// serviceQueueDelay is how long the service leaves a new queue item alone,
+// since with OFFLINE_FIRST the agent that queued it transcribes and pastes
+// it right away.
+const serviceQueueDelay = 2 * time.Minute
+
+// RunService runs the engine of the STT service until stop is closed: the
+// retry queue and cache retention of cfg. Services have no desktop, so there
+// are no hotkeys, tray or notifications.
+func RunService(cfg config.Config, stop <-chan struct{}) error {
+	cfg.Notification = false
+	cfg.Tray = false
+	cfg.VUMeter = false
+	r, err := newRuntime(cfg, true, Deps{})
+	if err != nil {
+		return err
+	}
+	defer r.Stop()
+	cfg = r.Config()
+	_ = r.CheckFFmpeg(context.Background())
+	if !queueEnabled(cfg) {
+		fmt.Println("[service] " + i18n.T("RETRY_QUEUE or OFFLINE_FIRST with a CACHE_DIR is needed for the service to transcribe queued recordings"))
+	}
+	fmt.Printf("[service] %s\n", i18n.Sprintf("running; cache dir %s", cfg.CacheDir))
+	<-stop
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"fmt"
+
+	"stt/internal/i18n"
+	"stt/internal/notify"
+	"stt/internal/session"
+	"stt/pkg/record"
+)
+
+// lockMessages are the notifications for each LOCK_ACTION that changes a
+// recording.
+var lockMessages = map[string]string{
+	"pause":  "Recording paused because the workstation was locked",
+	"stop":   "Recording stopped because the workstation was locked",
+	"cancel": "Recording canceled because the workstation was locked",
+}
+
+// startSessionWatch applies LOCK_ACTION to the active recording when the
+// workstation locks, and stops it when the computer goes to sleep. The
+// setting is read when the lock happens, so a reload needs no restart. The
+// returned function stops watching.
+func (r *Runtime) startSessionWatch() func() {
+	if r.serviceMode {
+		return func() {}
+	}
+	stop, err := session.Watch(func(ev session.Event) {
+		switch ev {
+		case session.Lock:
+			r.onLock()
+		case session.Suspend:
+			r.onSuspend()
+		case session.Resume:
+			r.onResume()
+		}
+	})
+	if err != nil {
+		fmt.Printf("[session] %v\n", err)
+		return func() {}
+	}
+	return stop
+}
+
+func (r *Runtime) onLock() {
+	cfg := r.Config()
+	msg, ok := lockMessages[cfg.LockAction]
+	if !ok {
+		return
+	}
+	// A recording the user already paused is left alone unless it is to be
+	// stopped or canceled.
+	s := r.Status()
+	if s.State != StateRecording && (s.State != StatePaused || cfg.LockAction == "pause") {
+		return
+	}
+	if _, err := r.Control(cfg.LockAction); err != nil {
+		fmt.Printf("[session] %s\n", i18n.Sprintf("%s on lock: %v", cfg.LockAction, err))
+		return
+	}
+	fmt.Printf("[session] %s\n", msg)
+	if cfg.Notification {
+		notify.Notify("STT", i18n.T(msg))
+	}
+}
+
+// onSuspend stops the active recording, so its stream is closed and
+// PortAudio terminated before sleep rather than left on a device that may
+// not come back the same. The recording is transcribed as usual, or queued
+// if the network is already gone.
+func (r *Runtime) onSuspend() {
+	cfg := r.Config()
+	s := r.Status()
+	if s.State != StateRecording && s.State != StatePaused {
+		return
+	}
+	if _, err := r.Control("stop"); err != nil {
+		fmt.Printf("[session] %s\n", i18n.Sprintf("stop on suspend: %v", err))
+		return
+	}
+	msg := "Recording stopped because the computer went to sleep"
+	fmt.Printf("[session] %s\n", msg)
+	if cfg.Notification {
+		notify.Notify("STT", i18n.T(msg))
+	}
+}
+
+// onResume registers the hotkeys again, since Windows may drop them, the
+// low-level hook in particular, while the computer sleeps. It also lists the
+// input devices, so a microphone that has not come back shows in the log
+// rather than as a silent first recording.
+func (r *Runtime) onResume() {
+	r.actionMu.Lock()
+	defer r.actionMu.Unlock()
+
+	r.mu.Lock()
+	stopHotkeys := r.stopHotkeys
+	r.stopHotkeys = nil
+	cfg := r.cfg
+	r.mu.Unlock()
+	if stopHotkeys == nil {
+		return
+	}
+	stopHotkeys()
+	if err := r.StartHotkeys(); err != nil {
+		fmt.Printf("[session] %s\n", i18n.Sprintf("re-registering hotkeys after resume: %v", err))
+		r.setState(StateError, "Failed to register hotkeys", err)
+		return
+	}
+	devices, err := record.Devices()
+	switch {
+	case err != nil:
+		fmt.Printf("[session] %s\n", i18n.Sprintf("listing input devices after resume: %v", err))
+	case len(devices) == 0:
+		fmt.Println("[session] " + i18n.T("no input devices after resume"))
+	case cfg.RECORD_DEBUG:
+		fmt.Printf("[session] resumed; %d input device(s)\n", len(devices))
+	}
+}
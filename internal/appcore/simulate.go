@@ -0,0 +1,250 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+
+	"stt/internal/config"
+	"stt/internal/i18n"
+	"stt/internal/redact"
+	"stt/pkg/record"
+)
+
+// simulationLength is the length of the synthetic recording.
+const simulationLength = 3 * time.Second
+
+// RunSimulation runs a synthetic recording through the hotkey to paste flow
+// for -simulate: the audio is converted with the configured codec, uploaded
+// and the transcript printed instead of pasted. It uploads to the configured
+// endpoint, or to a built-in mock one when mock is set or API_ENDPOINT is
+// empty. The cache, history and queue go to a temporary directory, and the
+// HTTP, gRPC and MIDI controls, the scheduler, the clipboard watch,
+// POST_COMMAND, notifications and sounds are off.
+func RunSimulation(cfg config.Config, mock bool) error {
+	return runSimulation(cfg, mock, Deps{})
+}
+
+func runSimulation(cfg config.Config, mock bool, deps Deps) error {
+	dir, err := os.MkdirTemp("", "stt-simulate-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	cfg.CacheDir = dir
+	cfg.HTTPAPI = ""
+	cfg.GRPCAPI = ""
+	cfg.MIDIInput = ""
+	cfg.Schedule = ""
+	cfg.ClipboardWatch = false
+	cfg.PostCommand = ""
+	cfg.Notification = false
+	cfg.SoundMute = true
+	if mock || cfg.APIEndpoint == "" {
+		server, err := startMockEndpoint()
+		if err != nil {
+			return fmt.Errorf("start mock endpoint: %w", err)
+		}
+		defer server.Close()
+		cfg.APIEndpoint = "http://" + server.Addr + "/v1/audio/transcriptions"
+		cfg.Provider = ""
+		cfg.TEXTPath = "text"
+		fmt.Printf("[simulate] %s\n", i18n.Sprintf("using the built-in mock endpoint %s", cfg.APIEndpoint))
+	} else {
+		fmt.Printf("[simulate] %s\n", i18n.Sprintf("using the endpoint %s", redact.Text(cfg.APIEndpoint, cfg.Token)))
+	}
+
+	rec := &simulatedRecorder{dir: dir, rate: cfg.SAMPLING_RATE, channels: cfg.Channels}
+	var pasted []string
+	deps.Recorder = rec
+	deps.Paste = func(text string) error {
+		pasted = append(pasted, text)
+		fmt.Printf("[simulate] %s\n", i18n.Sprintf("paste: %s", text))
+		return nil
+	}
+	r, err := NewRuntimeWith(cfg, deps)
+	if err != nil {
+		return err
+	}
+	defer r.Stop()
+
+	if err := r.StartRecording(); err != nil {
+		return err
+	}
+	start := time.Now()
+	text, err := r.StopRecording()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("[simulate] %s\n", i18n.Sprintf("%s of synthetic audio transcribed in %s", simulationLength, time.Since(start).Round(time.Millisecond)))
+	if text == "" {
+		// Real services often hear nothing in a tone; the upload still
+		// went through.
+		fmt.Println("[simulate] " + i18n.T("the endpoint returned no text for the synthetic audio"))
+	} else if len(pasted) != 1 || pasted[0] != text {
+		return fmt.Errorf("transcript was not pasted")
+	}
+	fmt.Println("[simulate] " + i18n.T("simulation passed"))
+	return nil
+}
+
+// mockTranscript is what the mock endpoint answers, with the size of the
+// uploaded audio.
+const mockTranscript = "STT simulation received %d bytes of audio."
+
+// startMockEndpoint serves an OpenAI-style transcription endpoint on a
+// local port. It accepts any credentials and answers uploads with
+// mockTranscript; other requests, such as the startup probe, get 200.
+func startMockEndpoint() (*http.Server, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	server := &http.Server{
+		Addr: ln.Addr().String(),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodPost {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			f, h, err := req.FormFile("file")
+			if err != nil {
+				http.Error(w, "missing file field", http.StatusBadRequest)
+				return
+			}
+			f.Close()
+			if h.Size == 0 {
+				http.Error(w, "empty audio", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{"text": fmt.Sprintf(mockTranscript, h.Size)})
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() { _ = server.Serve(ln) }()
+	return server, nil
+}
+
+// simulatedRecorder is the Recorder of -simulate. Stop returns a generated
+// recording of simulationLength.
+type simulatedRecorder struct {
+	dir      string
+	rate     int
+	channels int
+
+	mu    sync.Mutex
+	state record.State
+}
+
+func (s *simulatedRecorder) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != record.StateIdle {
+		return fmt.Errorf("already recording")
+	}
+	s.state = record.StateRecording
+	return nil
+}
+
+func (s *simulatedRecorder) Stop() (record.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == record.StateIdle {
+		return record.Result{}, fmt.Errorf("not recording")
+	}
+	s.state = record.StateIdle
+	path := filepath.Join(s.dir, "RecordTemp_simulate.wav")
+	if err := writeSyntheticWav(path, s.rate, s.channels, simulationLength); err != nil {
+		return record.Result{}, err
+	}
+	return record.Result{WavPath: path, Duration: simulationLength}, nil
+}
+
+func (s *simulatedRecorder) Cancel() (record.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = record.StateIdle
+	return record.Result{Canceled: true}, nil
+}
+
+func (s *simulatedRecorder) TogglePause() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch s.state {
+	case record.StateRecording:
+		s.state = record.StatePaused
+	case record.StatePaused:
+		s.state = record.StateRecording
+	default:
+		return fmt.Errorf("not recording")
+	}
+	return nil
+}
+
+func (s *simulatedRecorder) State() record.State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// writeSyntheticWav writes a 16-bit PCM WAV of length d that sounds roughly
+// like speech: a voice-like tone with a gliding pitch and a few harmonics,
+// shaped into syllables with short pauses between words.
+func writeSyntheticWav(path string, rate, channels int, d time.Duration) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	n := int(d.Seconds() * float64(rate))
+	data := make([]int, 0, n*channels)
+	phase := 0.0
+	for i := range n {
+		t := float64(i) / float64(rate)
+		pitch := 140 + 40*math.Sin(2*math.Pi*0.7*t)
+		phase += 2 * math.Pi * pitch / float64(rate)
+		voice := math.Sin(phase) + 0.5*math.Sin(2*phase) + 0.25*math.Sin(3*phase)
+		// Four syllables a second, with every fourth one silent.
+		syllable := math.Pow(math.Sin(math.Pi*4*t), 2)
+		if int(t*4)%4 == 3 {
+			syllable = 0
+		}
+		v := int(voice / 1.75 * syllable * 0.3 * math.MaxInt16)
+		for range channels {
+			data = append(data, v)
+		}
+	}
+	enc := wav.NewEncoder(f, rate, 16, channels, 1)
+	buf := &audio.IntBuffer{Format: &audio.Format{NumChannels: channels, SampleRate: rate}, Data: data, SourceBitDepth: 16}
+	if err := enc.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-audio/wav"
+
+	"stt/internal/config"
+)
+
+func TestWriteSyntheticWav(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tone.wav")
+	if err := writeSyntheticWav(path, 16000, 2, time.Second); err != nil {
+		t.Fatalf("writeSyntheticWav failed: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dec := wav.NewDecoder(f)
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if len(buf.Data) != 2*16000 || dec.NumChans != 2 || dec.SampleRate != 16000 || dec.BitDepth != 16 {
+		t.Fatalf("decoded %d samples, %d channels at %d Hz, %d bit; want 1s of stereo 16 kHz 16 bit", len(buf.Data), dec.NumChans, dec.SampleRate, dec.BitDepth)
+	}
+}
+
+func TestSimulationAgainstMockEndpoint(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the ffmpeg stand-in is a shell script")
+	}
+	cfg := config.DefaultConfig()
+	// A stand-in for ffmpeg that copies the input, so the upload is not
+	// empty.
+	cfg.FFMPEG_PATH = filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(cfg.FFMPEG_PATH, []byte("#!/bin/sh\nfor a; do if [ \"$prev\" = -i ]; then in=$a; fi; prev=$a; out=$a; done\ncat \"$in\" > \"$out\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg.APIEndpoint = "http://127.0.0.1:1/unused"
+	cfg.MaxRetry = 1
+	if err := RunSimulation(cfg, true); err != nil {
+		t.Fatalf("RunSimulation failed: %v", err)
+	}
+
+	cfg.FFMPEG_PATH = filepath.Join(t.TempDir(), "missing-ffmpeg")
+	if err := RunSimulation(cfg, true); err == nil {
+		t.Fatal("RunSimulation succeeded without ffmpeg")
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"stt/internal/i18n"
+	"stt/internal/notify"
+)
+
+// timerWarnAhead is how long before MAX_RECORD_SECONDS the recording timer
+// warns that the recording is about to stop.
+const timerWarnAhead = 10 * time.Second
+
+// recordingTimer is what startRecordingTimer tracks about the current
+// recording, told apart from the next one by its start time.
+type recordingTimer struct {
+	started  time.Time
+	progress *notify.Progress
+	status   string
+	warned   bool
+	stopping bool
+}
+
+// startRecordingTimer checks the recording once a second: it keeps the
+// RECORDING_TIMER notification current, warns shortly before
+// MAX_RECORD_SECONDS and stops the recording once it is reached. The returned
+// func stops the checks and removes the notification.
+func (r *Runtime) startRecordingTimer() func() {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		var t recordingTimer
+		for {
+			select {
+			case <-done:
+				t.reset(time.Time{})
+				return
+			case <-ticker.C:
+				r.tickRecording(&t)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+func (r *Runtime) tickRecording(t *recordingTimer) {
+	r.mu.Lock()
+	s := r.statusLocked()
+	cfg := r.cfg
+	started := r.started
+	r.mu.Unlock()
+
+	if s.State != StateRecording && s.State != StatePaused {
+		t.reset(time.Time{})
+		return
+	}
+	if !started.Equal(t.started) {
+		t.reset(started)
+	}
+
+	limit := time.Duration(cfg.MaxRecordSeconds) * time.Second
+	if limit > 0 && s.Elapsed >= limit {
+		if !t.stopping {
+			t.stopping = true
+			fmt.Printf("[record] %s\n", i18n.Sprintf("MAX_RECORD_SECONDS (%d) reached; stopping", cfg.MaxRecordSeconds))
+			// Stopping uploads the recording, which must not hold up the
+			// ticker or Stop, which waits for it.
+			go r.stopRecording(started)
+		}
+		return
+	}
+	if !cfg.Notification || !cfg.RecordingTimer {
+		return
+	}
+
+	left := limit - s.Elapsed
+	if limit > 0 && left <= timerWarnAhead && !t.warned {
+		t.warned = true
+		notify.Notify("STT", i18n.Sprintf("Recording stops in %d seconds", int((left+time.Second-1)/time.Second)))
+	}
+
+	var status string
+	switch {
+	case s.State == StatePaused:
+		status = i18n.Sprintf("Paused at %s", formatElapsed(s.Elapsed))
+	case limit > 0:
+		status = i18n.Sprintf("Recording %s, %s left", formatElapsed(s.Elapsed), formatElapsed(left))
+	default:
+		status = i18n.Sprintf("Recording %s", formatElapsed(s.Elapsed))
+	}
+	if status == t.status {
+		return
+	}
+	t.status = status
+	value := -1.0
+	if limit > 0 {
+		value = float64(s.Elapsed) / float64(limit)
+	}
+	if t.progress == nil {
+		t.progress = notify.NewProgress("STT")
+	}
+	t.progress.Update(status, value)
+}
+
+// reset removes the notification of the previous recording and starts
+// tracking the one that began at started.
+func (t *recordingTimer) reset(started time.Time) {
+	if t.progress != nil {
+		t.progress.Close()
+	}
+	*t = recordingTimer{started: started}
+}
+
+// stopRecording stops and uploads the recording that began at started,
+// unless it has already been stopped or canceled.
+func (r *Runtime) stopRecording(started time.Time) {
+	r.actionMu.Lock()
+	defer r.actionMu.Unlock()
+
+	r.mu.Lock()
+	current := r.state == StateRecording && r.started.Equal(started)
+	r.mu.Unlock()
+	if current {
+		r.toggleRecordingLocked()
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"stt/internal/i18n"
+)
+
+// timings is how long the steps of one transcription took, to tell a slow
+// network or provider from a slow machine. Steps that did not happen are 0.
+type timings struct {
+	// stop is the time from stopping the recording until its file was
+	// finished, including flushing a streamed encoder.
+	stop    time.Duration
+	convert time.Duration
+	// upload includes retries.
+	upload time.Duration
+	paste  time.Duration
+}
+
+// String lists the steps that took time, and always the upload, e.g.
+// "convert 0.41s, upload 1.83s, paste 0.05s".
+func (t timings) String() string {
+	return t.format("%s %.2fs", "stop", "convert", "upload", "paste")
+}
+
+// notice is String for the paste notification, in the UI language.
+func (t timings) notice() string {
+	return t.format(i18n.T("%s %.1fs"), i18n.T("stop"), i18n.T("convert"), i18n.T("upload"), i18n.T("paste"))
+}
+
+func (t timings) format(step, stop, convert, upload, paste string) string {
+	var parts []string
+	add := func(name string, d time.Duration, always bool) {
+		if d > 0 || always {
+			parts = append(parts, fmt.Sprintf(step, name, d.Seconds()))
+		}
+	}
+	add(stop, t.stop, false)
+	add(convert, t.convert, false)
+	add(upload, t.upload, true)
+	add(paste, t.paste, false)
+	return strings.Join(parts, ", ")
+}
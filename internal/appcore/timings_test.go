@@ -0,0 +1,35 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimingsListStepsThatTookTime(t *testing.T) {
+	for _, tc := range []struct {
+		t    timings
+		want string
+	}{
+		{timings{stop: 50 * time.Millisecond, convert: 410 * time.Millisecond, upload: 1830 * time.Millisecond, paste: 30 * time.Millisecond},
+			"stop 0.05s, convert 0.41s, upload 1.83s, paste 0.03s"},
+		// A streamed recording has no conversion, a failed upload no paste.
+		{timings{stop: 120 * time.Millisecond, upload: 2 * time.Second}, "stop 0.12s, upload 2.00s"},
+		{timings{}, "upload 0.00s"},
+	} {
+		if got := tc.t.String(); got != tc.want {
+			t.Errorf("String() = %q, want %q", got, tc.want)
+		}
+	}
+}
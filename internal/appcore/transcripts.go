@@ -0,0 +1,51 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import "time"
+
+// transcriptFunc receives a transcript from record mode.
+type transcriptFunc func(text string, at time.Time, latency time.Duration)
+
+// SubscribeTranscripts calls fn with the text of each successful record-mode
+// transcription, before it is pasted, until the returned function is called.
+// fn runs on the upload goroutine, so it must not block.
+func (r *Runtime) SubscribeTranscripts(fn func(text string, at time.Time, latency time.Duration)) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.subscribers == nil {
+		r.subscribers = make(map[int]transcriptFunc)
+	}
+	id := r.nextSubscriber
+	r.nextSubscriber++
+	r.subscribers[id] = fn
+	return func() {
+		r.mu.Lock()
+		delete(r.subscribers, id)
+		r.mu.Unlock()
+	}
+}
+
+// publishTranscript passes text to the SubscribeTranscripts callbacks.
+func (r *Runtime) publishTranscript(text string) {
+	r.mu.Lock()
+	latency := r.lastLatency
+	subs := make([]transcriptFunc, 0, len(r.subscribers))
+	for _, fn := range r.subscribers {
+		subs = append(subs, fn)
+	}
+	r.mu.Unlock()
+	at := time.Now()
+	for _, fn := range subs {
+		fn(text, at, latency)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"stt/internal/config"
+	"stt/internal/i18n"
+	"stt/internal/notify"
+	"stt/internal/tray"
+)
+
+// trayStatus picks the tray icon for a runtime state.
+func trayStatus(s State) tray.Status {
+	switch s {
+	case StateRecording:
+		return tray.StatusRecording
+	case StatePaused:
+		return tray.StatusPaused
+	case StateUploading:
+		return tray.StatusUploading
+	case StateError:
+		return tray.StatusError
+	}
+	return tray.StatusIdle
+}
+
+// trayTooltip describes the runtime status in the tray tooltip, which
+// `stt status` also prints: the last state message, the recording time
+// while recording, the profile and the last transcription latency.
+func trayTooltip(s Status) string {
+	var b strings.Builder
+	b.WriteString("STT")
+	if s.Message != "" {
+		b.WriteString(" - " + i18n.T(s.Message))
+	}
+	if s.State == StateRecording || s.State == StatePaused {
+		b.WriteString("\n" + i18n.Sprintf("Recorded %s", formatElapsed(s.Elapsed)))
+	}
+	profile := s.Profile
+	if profile == "" {
+		profile = "default"
+	}
+	b.WriteString("\n" + i18n.Sprintf("Profile: %s", profile))
+	if s.Latency > 0 {
+		b.WriteString("\n" + i18n.Sprintf("Last transcription: %.1fs", s.Latency.Seconds()))
+	}
+	return b.String()
+}
+
+// formatElapsed formats d as m:ss.
+func formatElapsed(d time.Duration) string {
+	sec := int(d / time.Second)
+	return fmt.Sprintf("%d:%02d", sec/60, sec%60)
+}
+
+// tickTooltip refreshes the tooltip every second while recording so the
+// recording time stays current, until stop is closed.
+func (r *Runtime) tickTooltip(icon *tray.Tray, stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if s := r.Status(); s.State == StateRecording {
+				icon.SetTooltip(trayTooltip(s))
+			}
+		}
+	}
+}
+
+// startTray shows the record-mode tray icon. Reload re-reads the config with
+// load (the item is left out when load is nil) and Quit calls quit. Requests
+// from `stt ctl` go to Control.
+func (r *Runtime) startTray(load func() (config.Config, error), quit func()) (*tray.Tray, error) {
+	items := []tray.MenuItem{
+		{Label: i18n.T("Start/stop recording"), Command: "toggle", OnClick: func() { r.ToggleRecording() }},
+		{Label: i18n.T("Pause/resume"), OnClick: func() { r.TogglePause() }},
+		{Label: i18n.T("Cancel recording"), OnClick: func() { r.Cancel() }},
+		{},
+		{Label: i18n.T("Open cache folder"), OnClick: r.openCacheFolder},
+	}
+	if load != nil {
+		items = append(items, tray.MenuItem{Label: i18n.T("Reload config"), OnClick: func() { r.reloadFrom(load) }})
+	}
+	items = append(items, tray.MenuItem{}, tray.MenuItem{Label: i18n.T("Quit"), OnClick: quit})
+	icon, err := tray.Start(trayTooltip(r.Status()), tray.Theme(r.Config().TrayTheme), items)
+	if err != nil {
+		return nil, err
+	}
+	icon.Handle(r.Control)
+	return icon, nil
+}
+
+func (r *Runtime) openCacheFolder() {
+	dir := r.Config().CacheDir
+	if dir == "" {
+		fmt.Printf("[tray] %s\n", i18n.T("CACHE_DIR is not set"))
+		return
+	}
+	if err := tray.OpenFolder(dir); err != nil {
+		fmt.Printf("[tray] %s\n", i18n.Sprintf("failed to open %s: %v", dir, err))
+	}
+}
+
+// reloadFrom applies the config returned by load. Failures leave the current
+// config in place and are reported like other runtime errors.
+func (r *Runtime) reloadFrom(load func() (config.Config, error)) {
+	cfg, err := load()
+	if err == nil {
+		err = r.Reload(cfg)
+	}
+	if err == nil {
+		fmt.Printf("[tray] %s\n", i18n.T("config reloaded"))
+		return
+	}
+	fmt.Printf("[tray] %s\n", i18n.Sprintf("config reload failed: %v", err))
+	if r.Config().Notification {
+		notify.Notify("STT", i18n.Sprintf("config reload failed: %v", err))
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"stt/internal/config"
+	"stt/internal/i18n"
+	"stt/internal/notify"
+	"stt/internal/update"
+)
+
+// checkForUpdate tells the user when a newer release is published than the
+// one running. Builds without version information are never reported.
+func checkForUpdate(cfg config.Config) {
+	current := update.Current()
+	if current == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	latest, err := update.Latest(ctx, &http.Client{})
+	if err != nil {
+		fmt.Printf("[update] %s\n", i18n.Sprintf("check failed: %v", err))
+		return
+	}
+	if latest == current {
+		return
+	}
+	msg := i18n.T("A new version of STT is available. Run stt update to install it.")
+	fmt.Printf("[update] %s\n", msg)
+	if cfg.Notification {
+		notify.Notify("STT", msg)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"stt/internal/crash"
+	"stt/internal/metrics"
+)
+
+// upload sends the file at path to the ASR service like
+// Transcriber.TranscribeAttempts and counts the request in the metrics. A
+// panic while uploading fails the upload with a crash report.
+func upload(ctx context.Context, c Transcriber, path string) (text string, raw []byte, attempts int, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = crash.AsError("upload", v, debug.Stack())
+		}
+	}()
+	start := time.Now()
+	text, raw, attempts, err = c.TranscribeAttempts(ctx, path)
+	metrics.Upload(time.Since(start), attempts, err)
+	return text, raw, attempts, err
+}
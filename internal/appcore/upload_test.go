@@ -0,0 +1,33 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"stt/internal/crash"
+)
+
+func TestUploadTurnsPanicIntoError(t *testing.T) {
+	crash.Setup(t.TempDir(), nil, nil)
+	defer crash.Setup("", nil, nil)
+
+	// A nil client panics as soon as it is used.
+	_, _, _, err := upload(context.Background(), nil, "clip.ogg")
+	var ce *crash.Error
+	if !errors.As(err, &ce) || ce.Where != "upload" || ce.Report == "" {
+		t.Fatalf("upload error = %v, want a crash.Error with a report", err)
+	}
+}
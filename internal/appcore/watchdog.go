@@ -0,0 +1,34 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package appcore
+
+import (
+	"path/filepath"
+	"time"
+
+	"stt/internal/config"
+	"stt/internal/i18n"
+	"stt/internal/notify"
+	"stt/internal/watchdog"
+)
+
+// RunWatchdog runs record mode as a worker process started from exe with
+// args, restarting it whenever it crashes, and returns the exit code for the
+// watchdog process.
+func RunWatchdog(cfg config.Config, exe string, args []string) int {
+	crashLog := filepath.Join(config.TempDir(&cfg), "crash.log")
+	return watchdog.Run(exe, args, crashLog, func(code int, restartIn time.Duration) {
+		if cfg.Notification {
+			notify.Notify("STT", i18n.Sprintf("STT stopped unexpectedly and will restart in %s. Details are in %s.", restartIn, crashLog))
+		}
+	})
+}
@@ -0,0 +1,333 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package applog copies the console output of record and file mode to a log
+// file, and writes short error reports from the recent log that failure
+// notifications can open.
+package applog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// recentLines is how many log lines an error report includes.
+	recentLines = 40
+	// detailsName is the error report, written next to the log.
+	detailsName = "stt-error.txt"
+	// timeLayout prefixes every line of the log.
+	timeLayout = "2006-01-02 15:04:05.000"
+)
+
+// Rotation says when the log is moved aside to <file>.1, older logs
+// shifting to <file>.2 and on, and how many of those are kept.
+type Rotation struct {
+	// MaxSize rotates the log before it grows past this many bytes; 0
+	// never does.
+	MaxSize int64
+	// Days rotates the log at the first line logged on a day this many
+	// days after its first line; 0 never does.
+	Days int
+	// Backups is how many rotated logs are kept; 0 deletes the old log.
+	Backups int
+}
+
+var (
+	mu      sync.Mutex
+	path    string
+	recent  []string
+	console *os.File
+	out     *logFile
+)
+
+// Start appends everything written to stdout and stderr to file, each line
+// prefixed with the time, until the returned function is called, rotating
+// it as rot says. The console output itself is unchanged.
+func Start(file string, rot Rotation) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := openLog(file, rot)
+	if err != nil {
+		return nil, err
+	}
+	var wg sync.WaitGroup
+	var restore []func()
+	var writers []*lineWriter
+	for _, std := range []**os.File{&os.Stdout, &os.Stderr} {
+		r, w, err := os.Pipe()
+		if err != nil {
+			for _, fn := range restore {
+				fn()
+			}
+			wg.Wait()
+			_ = f.close()
+			return nil, err
+		}
+		console := *std
+		*std = w
+		lw := &lineWriter{console: console}
+		writers = append(writers, lw)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = io.Copy(io.MultiWriter(console, lw), r)
+			_ = r.Close()
+		}()
+		restore = append(restore, func() {
+			*std = console
+			_ = w.Close()
+		})
+	}
+
+	mu.Lock()
+	path = file
+	recent = nil
+	console = writers[0].console
+	out = f
+	mu.Unlock()
+	return func() {
+		for _, fn := range restore {
+			fn()
+		}
+		wg.Wait()
+		for _, lw := range writers {
+			lw.flush()
+		}
+		mu.Lock()
+		_ = f.close()
+		path = ""
+		console = nil
+		out = nil
+		mu.Unlock()
+	}, nil
+}
+
+// Console returns the stdout that Start copies to the log, so callers can
+// tell whether it is a terminal; os.Stdout when no log is being written.
+func Console() *os.File {
+	mu.Lock()
+	defer mu.Unlock()
+	if console == nil {
+		return os.Stdout
+	}
+	return console
+}
+
+// Path returns the log file, or "" when Start has not been called.
+func Path() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return path
+}
+
+// Details writes a report of a failure to stt-error.txt next to the log:
+// what failed, the error and the last lines of the log. It returns the
+// report's path, or "" when no log is being written.
+func Details(what string, cause error) (string, error) {
+	mu.Lock()
+	logPath := path
+	lines := append([]string(nil), recent...)
+	mu.Unlock()
+	if logPath == "" {
+		return "", nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %s\r\n", time.Now().Format("2006-01-02 15:04:05"), what)
+	if cause != nil {
+		fmt.Fprintf(&b, "%v\r\n", cause)
+	}
+	fmt.Fprintf(&b, "\r\n%s:\r\n", logPath)
+	for _, line := range lines {
+		b.WriteString(line + "\r\n")
+	}
+	details := filepath.Join(filepath.Dir(logPath), detailsName)
+	if err := os.WriteFile(details, []byte(b.String()), 0o644); err != nil {
+		return "", err
+	}
+	return details, nil
+}
+
+// lineWriter writes the complete lines of one stream to the log with a time
+// prefix and keeps the most recent ones for Details. A line redrawn after a
+// carriage return, like a progress bar, is logged as it ends up on screen.
+type lineWriter struct {
+	mu      sync.Mutex
+	console *os.File
+	partial []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.visible(strings.TrimRight(string(w.partial[:i]), "\r"))
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.partial) > 0 {
+		w.visible(string(w.partial))
+		w.partial = nil
+	}
+}
+
+// visible logs the text of s after its last carriage return, skipping lines
+// that a redraw left blank.
+func (w *lineWriter) visible(s string) {
+	if i := strings.LastIndexByte(s, '\r'); i >= 0 {
+		if s = strings.TrimSpace(s[i+1:]); s == "" {
+			return
+		}
+	}
+	w.line(s)
+}
+
+func (w *lineWriter) line(s string) {
+	line := time.Now().Format(timeLayout) + " " + s
+	mu.Lock()
+	if out != nil {
+		out.write(line + "\n")
+	}
+	recent = append(recent, line)
+	if len(recent) > recentLines {
+		recent = recent[len(recent)-recentLines:]
+	}
+	mu.Unlock()
+}
+
+// logFile is the open log and what its rotation needs to know.
+type logFile struct {
+	path  string
+	rot   Rotation
+	f     *os.File
+	size  int64
+	start time.Time
+}
+
+// openLog opens the log at path for appending, rotating it first when it is
+// due already.
+func openLog(path string, rot Rotation) (*logFile, error) {
+	l := &logFile{path: path, rot: rot}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	if l.due(time.Now(), 0) {
+		if err := l.rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// open opens the log and finds its size and the day of its first line.
+func (l *logFile) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	l.f, l.size, l.start = f, info.Size(), time.Now()
+	first := make([]byte, len(timeLayout))
+	if _, err := f.ReadAt(first, 0); err == nil {
+		if t, err := time.ParseInLocation(timeLayout, string(first), time.Local); err == nil {
+			l.start = t
+		}
+	}
+	return nil
+}
+
+// due reports whether the log must be rotated before n more bytes are
+// written at now.
+func (l *logFile) due(now time.Time, n int) bool {
+	if l.size == 0 {
+		return false
+	}
+	if l.rot.MaxSize > 0 && l.size+int64(n) > l.rot.MaxSize {
+		return true
+	}
+	if l.rot.Days > 0 {
+		y, m, d := l.start.Date()
+		return !now.Before(time.Date(y, m, d+l.rot.Days, 0, 0, 0, 0, time.Local))
+	}
+	return false
+}
+
+// rotate moves the log to <path>.1, shifting the older ones up and deleting
+// those past rot.Backups, and starts a new one.
+func (l *logFile) rotate() error {
+	_ = l.f.Close()
+	l.f = nil
+	// Logs past rot.Backups are left over from a larger setting.
+	for i := l.rot.Backups + 1; ; i++ {
+		if os.Remove(backupName(l.path, i)) != nil {
+			break
+		}
+	}
+	for i := l.rot.Backups - 1; i >= 1; i-- {
+		_ = os.Rename(backupName(l.path, i), backupName(l.path, i+1))
+	}
+	if l.rot.Backups > 0 {
+		_ = os.Rename(l.path, backupName(l.path, 1))
+	} else {
+		_ = os.Remove(l.path)
+	}
+	return l.open()
+}
+
+// write writes s, rotating the log first when it is due. Nothing more is
+// logged once the new log cannot be opened.
+func (l *logFile) write(s string) {
+	if l.f == nil {
+		return
+	}
+	if l.due(time.Now(), len(s)) {
+		if err := l.rotate(); err != nil {
+			return
+		}
+	}
+	n, _ := l.f.WriteString(s)
+	l.size += int64(n)
+}
+
+func (l *logFile) close() error {
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+func backupName(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
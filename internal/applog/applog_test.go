@@ -0,0 +1,137 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package applog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartCopiesOutputAndDetailsQuoteIt(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "logs", "stt.log")
+	stop, err := Start(file, Rotation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println("[asr] request failed")
+	fmt.Fprint(os.Stderr, "[main] partial")
+	stop()
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], " [asr] request failed") || !strings.HasSuffix(lines[1], " [main] partial") {
+		t.Fatalf("log = %q, want both lines with a time prefix", data)
+	}
+	if Path() != "" {
+		t.Fatalf("Path() = %q after stop, want empty", Path())
+	}
+
+	if details, err := Details("Upload failed", errors.New("boom")); err != nil || details != "" {
+		t.Fatalf("Details without a log = %q, %v; want no report", details, err)
+	}
+	stop, err = Start(file, Rotation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	details, err := Details("Upload failed", errors.New("boom"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(filepath.Dir(file), detailsName); details != want {
+		t.Fatalf("Details path = %q, want %q", details, want)
+	}
+	report, err := os.ReadFile(details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Upload failed", "boom", file} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("report %q does not mention %q", report, want)
+		}
+	}
+}
+
+func TestRedrawnLinesAreLoggedAsShown(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "stt.log")
+	stop, err := Start(file, Rotation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Print("[##--] 1/2 files\r                \r[file] 2/2 b.wav -> b.txt\n")
+	fmt.Print("[####] 2/2 files\r                \r")
+	fmt.Println("[file] done")
+	stop()
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], " [file] 2/2 b.wav -> b.txt") || !strings.HasSuffix(lines[1], " [file] done") {
+		t.Fatalf("log = %q, want the lines without the bar", data)
+	}
+}
+
+func TestLogRotatesBySizeAndDay(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "stt.log")
+	if err := os.WriteFile(backupName(file, 3), []byte("left over\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stop, err := Start(file, Rotation{MaxSize: 120, Backups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 5 {
+		fmt.Printf("[asr] request %d of a long job\n", i)
+	}
+	stop()
+	for i, want := range []string{"request 4", "request 2", "request 0"} {
+		name := file
+		if i > 0 {
+			name = backupName(file, i)
+		}
+		data, err := os.ReadFile(name)
+		if err != nil || !strings.Contains(string(data), want) || int64(len(data)) > 120 {
+			t.Errorf("%s = %q, %v; want %q within 120 bytes", name, data, err, want)
+		}
+	}
+	if _, err := os.Stat(backupName(file, 3)); !os.IsNotExist(err) {
+		t.Errorf("log past Backups: %v, want it deleted", err)
+	}
+
+	old := time.Now().AddDate(0, 0, -2).Format(timeLayout) + " [main] started\n"
+	if err := os.WriteFile(file, []byte(old), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stop, err = Start(file, Rotation{Days: 2, Backups: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println("[main] today")
+	stop()
+	if data, _ := os.ReadFile(backupName(file, 1)); string(data) != old {
+		t.Errorf("rotated log = %q, want the one started two days ago", data)
+	}
+	if data, _ := os.ReadFile(file); !strings.HasSuffix(string(data), " [main] today\n") {
+		t.Errorf("log = %q, want only today's line", data)
+	}
+}
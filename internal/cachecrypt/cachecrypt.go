@@ -0,0 +1,248 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package cachecrypt encrypts cached recordings and transcripts with
+// AES-256-GCM. The key lives in cache.key inside the cache directory, either
+// wrapped with Windows DPAPI or derived from a user passphrase.
+package cachecrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"stt/internal/atomicfile"
+	"stt/internal/secret"
+)
+
+// Encryption modes accepted by CACHE_ENCRYPTION.
+const (
+	ModeDPAPI      = "dpapi"
+	ModePassphrase = "passphrase"
+)
+
+// Ext is appended to the name of every encrypted cache file.
+const Ext = ".enc"
+
+// KeyFileName is the key file created inside the cache directory.
+const KeyFileName = "cache.key"
+
+// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256.
+const pbkdf2Iterations = 600000
+
+// magic prefixes every sealed payload so encrypted and plain data can be told
+// apart.
+var magic = []byte("STTENC1\x00")
+
+// checkText is sealed into the key file so a wrong passphrase is detected
+// before anything is written with it.
+var checkText = []byte("stt-cache-key-check")
+
+// ErrWrongPassphrase is returned when the passphrase does not match cache.key.
+var ErrWrongPassphrase = errors.New("cache passphrase does not match " + KeyFileName)
+
+// Cipher seals and opens cache data.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+type keyFile struct {
+	Mode  string `json:"mode"`
+	Key   string `json:"key,omitempty"`
+	Salt  string `json:"salt,omitempty"`
+	Check string `json:"check,omitempty"`
+}
+
+// Valid reports whether mode is a supported CACHE_ENCRYPTION value. The empty
+// string disables encryption.
+func Valid(mode string) bool {
+	return mode == "" || mode == ModeDPAPI || mode == ModePassphrase
+}
+
+// Load returns the cipher for the key in dir, creating the key for mode on
+// first use. An empty mode accepts whatever mode the existing key uses.
+func Load(dir, mode, passphrase string) (*Cipher, error) {
+	path := filepath.Join(dir, KeyFileName)
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if mode == "" {
+			return nil, fmt.Errorf("no %s in '%s'", KeyFileName, dir)
+		}
+		return create(path, mode, passphrase)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var kf keyFile
+	if err := json.Unmarshal(b, &kf); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if mode != "" && kf.Mode != mode {
+		return nil, fmt.Errorf("%s was created for %s encryption, not %s", path, kf.Mode, mode)
+	}
+
+	switch kf.Mode {
+	case ModeDPAPI:
+		encoded, err := secret.Decrypt(kf.Key)
+		if err != nil {
+			return nil, err
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decode cache key: %w", err)
+		}
+		return newCipher(key)
+	case ModePassphrase:
+		salt, err := base64.StdEncoding.DecodeString(kf.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("decode cache key salt: %w", err)
+		}
+		check, err := base64.StdEncoding.DecodeString(kf.Check)
+		if err != nil {
+			return nil, fmt.Errorf("decode cache key check: %w", err)
+		}
+		c, err := passphraseCipher(passphrase, salt)
+		if err != nil {
+			return nil, err
+		}
+		if got, err := c.Open(check); err != nil || !bytes.Equal(got, checkText) {
+			return nil, ErrWrongPassphrase
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("%s has unknown mode %q", path, kf.Mode)
+}
+
+func create(path, mode, passphrase string) (*Cipher, error) {
+	var kf keyFile
+	var c *Cipher
+	switch mode {
+	case ModeDPAPI:
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		wrapped, err := secret.Encrypt(base64.StdEncoding.EncodeToString(key))
+		if err != nil {
+			return nil, err
+		}
+		if c, err = newCipher(key); err != nil {
+			return nil, err
+		}
+		kf = keyFile{Mode: mode, Key: wrapped}
+	case ModePassphrase:
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		var err error
+		if c, err = passphraseCipher(passphrase, salt); err != nil {
+			return nil, err
+		}
+		check, err := c.Seal(checkText)
+		if err != nil {
+			return nil, err
+		}
+		kf = keyFile{Mode: mode, Salt: base64.StdEncoding.EncodeToString(salt), Check: base64.StdEncoding.EncodeToString(check)}
+	default:
+		return nil, fmt.Errorf("unknown cache encryption mode %q", mode)
+	}
+	b, err := json.MarshalIndent(kf, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := atomicfile.WriteFile(path, b, 0600); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func passphraseCipher(passphrase string, salt []byte) (*Cipher, error) {
+	if passphrase == "" {
+		return nil, errors.New("cache passphrase is empty")
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	return newCipher(key)
+}
+
+func newCipher(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// IsSealed reports whether data was produced by Seal.
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// Seal encrypts plain.
+func (c *Cipher) Seal(plain []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append(append([]byte{}, magic...), nonce...)
+	return c.aead.Seal(out, nonce, plain, magic), nil
+}
+
+// Open decrypts data produced by Seal.
+func (c *Cipher) Open(data []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		return nil, errors.New("data is not encrypted")
+	}
+	data = data[len(magic):]
+	n := c.aead.NonceSize()
+	if len(data) < n {
+		return nil, errors.New("encrypted data is truncated")
+	}
+	plain, err := c.aead.Open(nil, data[:n], data[n:], magic)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return plain, nil
+}
+
+// WriteFile seals data and writes it to path.
+func (c *Cipher) WriteFile(path string, data []byte, perm os.FileMode) error {
+	sealed, err := c.Seal(data)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, sealed, perm)
+}
+
+// ReadFile reads and opens the sealed file at path.
+func (c *Cipher) ReadFile(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Open(b)
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package cachecrypt
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestPassphraseKeyRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Load(dir, ModePassphrase, "correct horse")
+	if err != nil {
+		t.Fatalf("Load (create) failed: %v", err)
+	}
+	path := filepath.Join(dir, "audio.wav"+Ext)
+	if err := c.WriteFile(path, []byte("secret audio"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	again, err := Load(dir, "", "correct horse")
+	if err != nil {
+		t.Fatalf("Load (existing) failed: %v", err)
+	}
+	got, err := again.ReadFile(path)
+	if err != nil || string(got) != "secret audio" {
+		t.Fatalf("ReadFile = %q, %v; want secret audio", got, err)
+	}
+
+	if _, err := Load(dir, ModePassphrase, "wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("Load with wrong passphrase err = %v, want ErrWrongPassphrase", err)
+	}
+	if _, err := Load(dir, ModeDPAPI, ""); err == nil {
+		t.Fatalf("Load with mismatched mode succeeded, want error")
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	c, err := newCipher(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("newCipher failed: %v", err)
+	}
+	sealed, err := c.Seal([]byte("hello"))
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	if !IsSealed(sealed) || IsSealed([]byte("hello")) {
+		t.Fatalf("IsSealed did not distinguish sealed from plain data")
+	}
+	got, err := c.Open(sealed)
+	if err != nil || string(got) != "hello" {
+		t.Fatalf("Open = %q, %v; want hello", got, err)
+	}
+
+	sealed[len(sealed)-1] ^= 1
+	if _, err := c.Open(sealed); err == nil {
+		t.Fatalf("Open succeeded on tampered data, want error")
+	}
+	if _, err := Load(t.TempDir(), "", ""); err == nil {
+		t.Fatalf("Load without key file or mode succeeded, want error")
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package cacheindex
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"stt/internal/atomicfile"
+)
+
+// ArchiveDirName is the directory inside the cache directory that holds the
+// monthly archives. Scan does not look inside it.
+const ArchiveDirName = "archive"
+
+// ArchivePath returns the path of the archive for month (YYYY-MM).
+func ArchivePath(cacheDir, month string) string {
+	return filepath.Join(cacheDir, ArchiveDirName, month+".zip")
+}
+
+// Archive moves the files of entries into monthly zip files named after the
+// month each entry was created (archive/YYYY-MM.zip), appending to archives
+// that already exist, and removes the originals. It returns where each
+// archived file went, as "<zip path>!<name inside the zip>", keyed by the
+// file's original path.
+func Archive(cacheDir string, entries []Entry) (map[string]string, error) {
+	months := map[string][]Entry{}
+	for _, e := range entries {
+		month := e.CreatedAt.Local().Format("2006-01")
+		months[month] = append(months[month], e)
+	}
+	names := make([]string, 0, len(months))
+	for month := range months {
+		names = append(names, month)
+	}
+	sort.Strings(names)
+
+	moved := map[string]string{}
+	for _, month := range names {
+		zipPath := ArchivePath(cacheDir, month)
+		members, err := appendToZip(zipPath, cacheDir, months[month])
+		if err != nil {
+			return moved, fmt.Errorf("archive %s: %w", zipPath, err)
+		}
+		for src, member := range members {
+			moved[src] = zipPath + "!" + member
+		}
+		if _, err := Remove(cacheDir, months[month]); err != nil {
+			return moved, err
+		}
+	}
+	return moved, nil
+}
+
+// appendToZip rewrites the zip at zipPath with its current members plus the
+// files of entries, replacing it atomically. Members are named by their path
+// relative to cacheDir with forward slashes.
+func appendToZip(zipPath, cacheDir string, entries []Entry) (map[string]string, error) {
+	if err := os.MkdirAll(filepath.Dir(zipPath), 0755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(zipPath), "."+filepath.Base(zipPath)+".*"+atomicfile.TempSuffix)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := zip.NewWriter(tmp)
+	seen := map[string]bool{}
+	if old, err := zip.OpenReader(zipPath); err == nil {
+		for _, f := range old.File {
+			seen[f.Name] = true
+			if err := w.Copy(f); err != nil {
+				_ = old.Close()
+				_ = tmp.Close()
+				return nil, err
+			}
+		}
+		_ = old.Close()
+	} else if !os.IsNotExist(err) {
+		_ = tmp.Close()
+		return nil, err
+	}
+
+	members := map[string]string{}
+	for _, e := range entries {
+		for _, src := range e.Files {
+			rel, err := filepath.Rel(cacheDir, src)
+			if err != nil {
+				rel = filepath.Base(src)
+			}
+			name := filepath.ToSlash(rel)
+			for n := 2; seen[name]; n++ {
+				name = fmt.Sprintf("%s~%d", filepath.ToSlash(rel), n)
+			}
+			seen[name] = true
+			if err := addToZip(w, src, name); err != nil {
+				_ = tmp.Close()
+				return nil, err
+			}
+			members[src] = name
+		}
+	}
+	err = w.Close()
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), zipPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func addToZip(w *zip.Writer, src, name string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	hdr.Method = zip.Deflate
+	dst, err := w.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, f)
+	return err
+}
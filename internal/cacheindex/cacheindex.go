@@ -0,0 +1,278 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package cacheindex scans the files kept in CACHE_DIR, groups them into
+// entries (one recording with its encoded audio, response, and metadata), and
+// prunes old entries.
+package cacheindex
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"stt/internal/atomicfile"
+	"stt/internal/cachecrypt"
+	"stt/internal/queue"
+)
+
+// metaExt is the suffix of the metadata sidecar written for each entry.
+const metaExt = ".meta.json"
+
+// Entry is one group of kept files sharing a base name.
+type Entry struct {
+	Dir       string
+	Name      string
+	Files     []string
+	Size      int64
+	CreatedAt time.Time
+	Provider  string
+}
+
+// ProviderStats counts the entries produced by one provider.
+type ProviderStats struct {
+	Entries int
+	Size    int64
+}
+
+// Stats summarizes a cache directory.
+type Stats struct {
+	Entries    int
+	Size       int64
+	Oldest     *Entry
+	Newest     *Entry
+	ByProvider map[string]ProviderStats
+}
+
+// Scan walks cacheDir and returns its entries, oldest first. Only the files
+// handleCache writes are entries; among those, the config, the API tokens,
+// crash and error reports, the retry queue, the archives, and temporary
+// files are skipped too.
+func Scan(cacheDir string) ([]Entry, error) {
+	groups := map[string]*Entry{}
+	err := filepath.WalkDir(cacheDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if filepath.Dir(path) == filepath.Clean(cacheDir) && (d.Name() == queue.DirName || d.Name() == ArchiveDirName) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if skipped(path, cacheDir, d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		key := filepath.Join(filepath.Dir(path), baseName(d.Name()))
+		e, ok := groups[key]
+		if !ok {
+			e = &Entry{Dir: filepath.Dir(path), Name: baseName(d.Name())}
+			groups[key] = e
+		}
+		e.Files = append(e.Files, path)
+		e.Size += info.Size()
+		if e.CreatedAt.IsZero() || info.ModTime().Before(e.CreatedAt) {
+			e.CreatedAt = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(groups))
+	for _, e := range groups {
+		readMeta(e)
+		sort.Strings(e.Files)
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// Summarize totals entries, which must be sorted oldest first as returned by
+// Scan.
+func Summarize(entries []Entry) Stats {
+	s := Stats{Entries: len(entries), ByProvider: map[string]ProviderStats{}}
+	for i := range entries {
+		e := &entries[i]
+		s.Size += e.Size
+		p := s.ByProvider[e.Provider]
+		p.Entries++
+		p.Size += e.Size
+		s.ByProvider[e.Provider] = p
+	}
+	if len(entries) > 0 {
+		s.Oldest = &entries[0]
+		s.Newest = &entries[len(entries)-1]
+	}
+	return s
+}
+
+// Policy selects entries to prune. Zero fields are ignored.
+type Policy struct {
+	MaxAge  time.Duration // remove entries older than this
+	MaxSize int64         // then remove the oldest entries until the total fits
+}
+
+// Select returns the entries that p would remove, oldest first. entries must
+// be sorted oldest first as returned by Scan.
+func Select(entries []Entry, p Policy, now time.Time) []Entry {
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+	}
+	var out []Entry
+	for _, e := range entries {
+		expired := p.MaxAge > 0 && now.Sub(e.CreatedAt) > p.MaxAge
+		oversize := p.MaxSize > 0 && total > p.MaxSize
+		if !expired && !oversize {
+			break
+		}
+		out = append(out, e)
+		total -= e.Size
+	}
+	return out
+}
+
+// Remove deletes the files of entries and then any directories under
+// cacheDir left empty. It returns the number of bytes freed and the first
+// error encountered; removal continues past errors.
+func Remove(cacheDir string, entries []Entry) (int64, error) {
+	var freed int64
+	var firstErr error
+	dirs := map[string]bool{}
+	for _, e := range entries {
+		for _, f := range e.Files {
+			info, err := os.Stat(f)
+			if err == nil {
+				err = os.Remove(f)
+			}
+			if err != nil && !os.IsNotExist(err) {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			if info != nil {
+				freed += info.Size()
+			}
+		}
+		dirs[e.Dir] = true
+	}
+	for dir := range dirs {
+		removeEmptyDirs(cacheDir, dir)
+	}
+	return freed, firstErr
+}
+
+// removeEmptyDirs removes dir and its parents up to, but not including,
+// cacheDir while they are empty.
+func removeEmptyDirs(cacheDir, dir string) {
+	root := filepath.Clean(cacheDir)
+	for dir = filepath.Clean(dir); dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
+
+// rootFiles are the files STT keeps in the root of the cache directory
+// besides the history database and the cache key: the API tokens generated
+// for clients to read, and the config when CACHE_DIR is the config dir.
+var rootFiles = map[string]bool{
+	"http-api-token": true,
+	"grpc-api-token": true,
+	"config.json":    true,
+}
+
+// appFile reports whether name is a crash report or the error report, which
+// STT writes next to its log and which defaults to the cache directory.
+func appFile(name string) bool {
+	return strings.HasPrefix(name, "stt-crash-") || name == "stt-error.txt"
+}
+
+// entryExts are the extensions of the files handleCache keeps, before any
+// cachecrypt.Ext: the recording, its encoded audio in any CONTAINER, the
+// response JSON, the transcript and the metadata sidecar. Only files with
+// one of them are entries, so whatever else shares CACHE_DIR, such as the
+// onboarding marker, a .env file or the config template, is left alone.
+var entryExts = map[string]bool{
+	".wav": true, ".ac3": true, ".ac4": true, ".ogg": true, ".oga": true,
+	".mp3": true, ".flac": true, ".eac3": true, ".aac": true, ".m4a": true,
+	".mp4": true, ".opus": true, ".webm": true,
+	".s8": true, ".s16be": true, ".s16le": true, ".s24be": true, ".s24le": true,
+	".s32be": true, ".s32le": true, ".f32be": true, ".f32le": true,
+	".f64be": true, ".f64le": true,
+	".json": true, ".txt": true,
+}
+
+// entryFile reports whether name is a file handleCache writes.
+func entryFile(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	return entryExts[strings.ToLower(filepath.Ext(strings.TrimSuffix(name, cachecrypt.Ext)))]
+}
+
+func skipped(path, cacheDir, name string) bool {
+	if !entryFile(name) {
+		return true
+	}
+	if filepath.Dir(path) == filepath.Clean(cacheDir) {
+		if strings.HasPrefix(name, "history.db") || name == cachecrypt.KeyFileName || rootFiles[name] {
+			return true
+		}
+	}
+	return strings.HasPrefix(name, "RecordTemp_") || strings.HasSuffix(name, atomicfile.TempSuffix) || appFile(name)
+}
+
+// baseName strips the extensions handleCache appends to a CACHE_NAME.
+func baseName(name string) string {
+	name = strings.TrimSuffix(name, cachecrypt.Ext)
+	if strings.HasSuffix(name, metaExt) {
+		return strings.TrimSuffix(name, metaExt)
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// readMeta takes the creation time and provider of e from its metadata
+// sidecar when there is one.
+func readMeta(e *Entry) {
+	b, err := os.ReadFile(filepath.Join(e.Dir, e.Name+metaExt))
+	if err != nil {
+		return
+	}
+	var meta struct {
+		CreatedAt time.Time `json:"created_at"`
+		Provider  string    `json:"provider"`
+	}
+	if json.Unmarshal(b, &meta) != nil {
+		return
+	}
+	if !meta.CreatedAt.IsZero() {
+		e.CreatedAt = meta.CreatedAt
+	}
+	e.Provider = meta.Provider
+}
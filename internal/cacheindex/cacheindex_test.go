@@ -0,0 +1,194 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package cacheindex
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, data string, mod time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+}
+
+func TestScanGroupsAndSummarizes(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	recent := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	day := filepath.Join(dir, "2026", "01", "01")
+	writeFile(t, filepath.Join(day, "audio-a.wav"), "wavwav", old)
+	writeFile(t, filepath.Join(day, "audio-a.ogg.enc"), "ogg", old)
+	writeFile(t, filepath.Join(day, "audio-a.meta.json"), `{"created_at":"2026-01-01T07:59:00Z","provider":"openai"}`, old)
+	writeFile(t, filepath.Join(dir, "audio-b.ogg"), "ogg", recent)
+	writeFile(t, filepath.Join(dir, "history.db"), "db", recent)
+	writeFile(t, filepath.Join(dir, "cache.key"), "key", recent)
+	writeFile(t, filepath.Join(dir, "http-api-token"), "token", old)
+	writeFile(t, filepath.Join(dir, "grpc-api-token"), "token", old)
+	for _, name := range []string{"stt.log", "stt.log.1", "stt.log.12", "crash.log", "stt-crash-20260101-080000-1.txt", "stt-error.txt", "config.json"} {
+		writeFile(t, filepath.Join(dir, name), "app", old)
+	}
+	writeFile(t, filepath.Join(dir, "logs", "stt.log.2"), "app", old)
+	writeFile(t, filepath.Join(dir, "pending", "x.wav"), "queued", recent)
+	writeFile(t, filepath.Join(dir, "RecordTemp_1.wav"), "tmp", recent)
+
+	entries, err := Scan(dir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Scan = %#v, want two entries", entries)
+	}
+	a, b := entries[0], entries[1]
+	if a.Name != "audio-a" || len(a.Files) != 3 || a.Provider != "openai" || !a.CreatedAt.Equal(old.Add(-time.Minute)) {
+		t.Fatalf("first entry = %#v", a)
+	}
+	if b.Name != "audio-b" || b.Provider != "" || b.Size != 3 {
+		t.Fatalf("second entry = %#v", b)
+	}
+
+	s := Summarize(entries)
+	if s.Entries != 2 || s.Size != a.Size+b.Size || s.Oldest.Name != "audio-a" || s.Newest.Name != "audio-b" {
+		t.Fatalf("Summarize = %#v", s)
+	}
+	if p := s.ByProvider["openai"]; p.Entries != 1 || p.Size != a.Size {
+		t.Fatalf("openai stats = %#v", p)
+	}
+}
+
+func TestSelectAndRemove(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	for i, name := range []string{"a", "b", "c"} {
+		mod := now.AddDate(0, 0, -30+10*i)
+		writeFile(t, filepath.Join(dir, "2026", name, name+".ogg"), "0123456789", mod)
+	}
+	entries, err := Scan(dir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if got := Select(entries, Policy{MaxAge: 15 * 24 * time.Hour}, now); len(got) != 2 || got[0].Name != "a" || got[1].Name != "b" {
+		t.Fatalf("Select by age = %#v", got)
+	}
+	if got := Select(entries, Policy{MaxSize: 15}, now); len(got) != 2 {
+		t.Fatalf("Select by size = %#v, want the two oldest", got)
+	}
+	if got := Select(entries, Policy{}, now); len(got) != 0 {
+		t.Fatalf("Select with empty policy = %#v", got)
+	}
+
+	freed, err := Remove(dir, entries[:1])
+	if err != nil || freed != 10 {
+		t.Fatalf("Remove = %d, %v; want 10 bytes", freed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2026", "a")); !os.IsNotExist(err) {
+		t.Fatalf("empty entry dir not removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2026")); err != nil {
+		t.Fatalf("non-empty parent removed: %v", err)
+	}
+}
+
+func TestPruneLeavesOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	writeFile(t, filepath.Join(dir, "audio-a.ogg"), "ogg", old)
+	others := []string{".stt-onboarded", ".env", "config.example.jsonc", "notes.md", "audio-a.ogg.part"}
+	for _, name := range others {
+		writeFile(t, filepath.Join(dir, name), "keep", old)
+	}
+
+	entries, err := Scan(dir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	victims := Select(entries, Policy{MaxAge: time.Hour}, old.AddDate(0, 1, 0))
+	if len(victims) != 1 || len(victims[0].Files) != 1 {
+		t.Fatalf("Select = %#v, want only audio-a.ogg", victims)
+	}
+	if _, err := Remove(dir, victims); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	for _, name := range others {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("%s was pruned: %v", name, err)
+		}
+	}
+}
+
+func TestArchiveAppendsToMonthlyZip(t *testing.T) {
+	dir := t.TempDir()
+	jan := time.Date(2026, 1, 15, 12, 0, 0, 0, time.Local)
+	writeFile(t, filepath.Join(dir, "2026", "01", "15", "a.ogg"), "aaaa", jan)
+	writeFile(t, filepath.Join(dir, "2026", "01", "15", "a.txt"), "hello", jan)
+
+	entries, err := Scan(dir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	moved, err := Archive(dir, entries)
+	if err != nil {
+		t.Fatalf("Archive failed: %v", err)
+	}
+	zipPath := ArchivePath(dir, "2026-01")
+	src := filepath.Join(dir, "2026", "01", "15", "a.txt")
+	if got, want := moved[src], zipPath+"!2026/01/15/a.txt"; got != want {
+		t.Fatalf("moved[a.txt] = %q, want %q", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2026")); !os.IsNotExist(err) {
+		t.Fatalf("archived files left behind: %v", err)
+	}
+
+	writeFile(t, filepath.Join(dir, "b.ogg"), "bb", jan.AddDate(0, 0, 1))
+	entries, _ = Scan(dir)
+	if len(entries) != 1 {
+		t.Fatalf("Scan after archiving = %#v, want only the new entry", entries)
+	}
+	if _, err := Archive(dir, entries); err != nil {
+		t.Fatalf("second Archive failed: %v", err)
+	}
+
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	defer r.Close()
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	if len(names) != 3 || names[2] != "b.ogg" {
+		t.Fatalf("zip members = %v, want the first two plus b.ogg", names)
+	}
+	rc, err := r.File[1].Open()
+	if err != nil {
+		t.Fatalf("Open member failed: %v", err)
+	}
+	defer rc.Close()
+	if b, _ := io.ReadAll(rc); string(b) != "hello" {
+		t.Fatalf("member content = %q", b)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package cachepath expands the placeholders used to lay out and name kept
+// cache files, e.g. "{yyyy}/{mm}/{dd}" and "{date}_{profile}_{lang}_{seq}".
+package cachepath
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultLayout groups cache files into one folder per day.
+const DefaultLayout = "{yyyy}/{mm}/{dd}"
+
+var placeholder = regexp.MustCompile(`\{([a-z]+)\}`)
+
+var dateTokens = map[string]func(time.Time) string{
+	"yyyy": func(t time.Time) string { return t.Format("2006") },
+	"yy":   func(t time.Time) string { return t.Format("06") },
+	"mm":   func(t time.Time) string { return t.Format("01") },
+	"dd":   func(t time.Time) string { return t.Format("02") },
+	"hh":   func(t time.Time) string { return t.Format("15") },
+	"ww": func(t time.Time) string {
+		_, w := t.ISOWeek()
+		return fmt.Sprintf("%02d", w)
+	},
+}
+
+// ValidateLayout reports unknown placeholders and layouts that would escape
+// the cache directory. An empty layout keeps files flat in the cache dir.
+func ValidateLayout(layout string) error {
+	if layout == "" {
+		return nil
+	}
+	if filepath.IsAbs(layout) || strings.HasPrefix(layout, "/") || strings.HasPrefix(layout, `\`) {
+		return fmt.Errorf("must be relative to CACHE_DIR")
+	}
+	for _, part := range strings.FieldsFunc(layout, isSeparator) {
+		if part == ".." {
+			return fmt.Errorf("must not contain '..'")
+		}
+	}
+	for _, m := range placeholder.FindAllStringSubmatch(layout, -1) {
+		if _, ok := dateTokens[m[1]]; !ok {
+			return fmt.Errorf("unknown placeholder %s (allowed: {yyyy}, {yy}, {mm}, {dd}, {hh}, {ww})", m[0])
+		}
+	}
+	return nil
+}
+
+// Dir expands layout for t into a relative directory using the OS separator.
+// Unknown placeholders are left as-is; call ValidateLayout first.
+func Dir(layout string, t time.Time) string {
+	if layout == "" {
+		return ""
+	}
+	out := placeholder.ReplaceAllStringFunc(layout, func(m string) string {
+		if f, ok := dateTokens[m[1:len(m)-1]]; ok {
+			return f(t)
+		}
+		return m
+	})
+	return filepath.Clean(filepath.FromSlash(strings.ReplaceAll(out, `\`, "/")))
+}
+
+func isSeparator(r rune) bool {
+	return r == '/' || r == '\\'
+}
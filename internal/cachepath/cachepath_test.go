@@ -0,0 +1,47 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package cachepath
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDir(t *testing.T) {
+	at := time.Date(2026, 1, 5, 9, 30, 0, 0, time.Local)
+	tests := map[string]string{
+		"":                  "",
+		DefaultLayout:       filepath.Join("2026", "01", "05"),
+		`{yyyy}\{mm}`:       filepath.Join("2026", "01"),
+		"{yy}-W{ww}/{hh}h/": filepath.Join("26-W02", "09h"),
+	}
+	for layout, want := range tests {
+		if got := Dir(layout, at); got != want {
+			t.Fatalf("Dir(%q) = %q, want %q", layout, got, want)
+		}
+	}
+}
+
+func TestValidateLayout(t *testing.T) {
+	for _, layout := range []string{"", DefaultLayout, "archive/{yyyy}"} {
+		if err := ValidateLayout(layout); err != nil {
+			t.Fatalf("ValidateLayout(%q) = %v, want nil", layout, err)
+		}
+	}
+	for _, layout := range []string{"/abs/{yyyy}", "{yyyy}/../x", "{month}"} {
+		if err := ValidateLayout(layout); err == nil {
+			t.Fatalf("ValidateLayout(%q) succeeded, want error", layout)
+		}
+	}
+}
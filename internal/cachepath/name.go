@@ -0,0 +1,132 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package cachepath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultName keeps the original audio-<timestamp> naming.
+const DefaultName = "audio-{date}-{time}"
+
+// invalidNameChars cannot appear in Windows file names.
+const invalidNameChars = `/\<>:"|?*`
+
+// maxSeq bounds the search for a free name so a broken directory cannot spin
+// forever.
+const maxSeq = 10000
+
+// Fields are the per-recording values a name template can refer to.
+type Fields struct {
+	Time     time.Time
+	Profile  string
+	Language string
+	Model    string
+	Provider string
+	Source   string
+	Machine  string
+}
+
+var nameTokens = map[string]func(Fields) string{
+	"date":     func(f Fields) string { return f.Time.Format("2006-01-02") },
+	"time":     func(f Fields) string { return f.Time.Format("15.04.05") },
+	"profile":  func(f Fields) string { return orDefault(f.Profile, "default") },
+	"lang":     func(f Fields) string { return orDefault(f.Language, "auto") },
+	"model":    func(f Fields) string { return f.Model },
+	"provider": func(f Fields) string { return f.Provider },
+	"source":   func(f Fields) string { return f.Source },
+	"machine":  func(f Fields) string { return orDefault(f.Machine, "unknown") },
+}
+
+// ValidateName reports unknown placeholders and characters that cannot appear
+// in a file name. Names may not contain path separators; use the layout for
+// subdirectories. An empty name stands for DefaultName.
+func ValidateName(name string) error {
+	if strings.ContainsAny(placeholder.ReplaceAllString(name, ""), invalidNameChars) {
+		return fmt.Errorf(`must not contain / \ < > : " | ? *`)
+	}
+	for _, m := range placeholder.FindAllStringSubmatch(name, -1) {
+		_, date := dateTokens[m[1]]
+		_, field := nameTokens[m[1]]
+		if !date && !field && m[1] != "seq" {
+			return fmt.Errorf("unknown placeholder %s (allowed: {date}, {time}, {profile}, {lang}, {model}, {provider}, {source}, {machine}, {seq}, {yyyy}, {yy}, {mm}, {dd}, {hh}, {ww})", m[0])
+		}
+	}
+	return nil
+}
+
+// Name expands the template for f, using seq for {seq}. Values are stripped of
+// characters that are not allowed in Windows file names.
+func Name(template string, f Fields, seq int) string {
+	return placeholder.ReplaceAllStringFunc(template, func(m string) string {
+		key := m[1 : len(m)-1]
+		if key == "seq" {
+			return fmt.Sprintf("%02d", seq)
+		}
+		if fn, ok := nameTokens[key]; ok {
+			return sanitize(fn(f))
+		}
+		if fn, ok := dateTokens[key]; ok {
+			return fn(f.Time)
+		}
+		return m
+	})
+}
+
+// Reserve picks the first name from template that is free in dir and claims
+// it by creating dir/<name><marker>, so concurrent callers never get the same
+// name. {seq} counts up from 1; templates without it get "-2", "-3", ...
+// appended on collision. An empty template means DefaultName.
+//
+// The marker is created empty and the caller is expected to overwrite it.
+// When that write fails the empty marker stays behind on purpose: it keeps
+// the name claimed for the other files already written under it.
+func Reserve(dir, template string, f Fields, marker string) (string, error) {
+	if template == "" {
+		template = DefaultName
+	}
+	hasSeq := strings.Contains(template, "{seq}")
+	for n := 1; n <= maxSeq; n++ {
+		name := Name(template, f, n)
+		if !hasSeq && n > 1 {
+			name = fmt.Sprintf("%s-%d", name, n)
+		}
+		file, err := os.OpenFile(filepath.Join(dir, name+marker), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			return name, file.Close()
+		}
+		if !os.IsExist(err) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no free name for %q in %s", template, dir)
+}
+
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(invalidNameChars, r) || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
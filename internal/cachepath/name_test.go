@@ -0,0 +1,85 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package cachepath
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestName(t *testing.T) {
+	f := Fields{
+		Time:     time.Date(2026, 1, 5, 9, 30, 7, 0, time.Local),
+		Model:    "openai/whisper-1",
+		Provider: "openai",
+		Source:   "record",
+		Machine:  "office-pc",
+	}
+	tests := map[string]string{
+		DefaultName:                     "audio-2026-01-05-09.30.07",
+		"{date}_{profile}_{lang}_{seq}": "2026-01-05_default_auto_03",
+		"{yyyy}{mm}{dd}-{model}":        "20260105-openai_whisper-1",
+		"{source}-{provider}":           "record-openai",
+		"audio-{date}-{time}-{machine}": "audio-2026-01-05-09.30.07-office-pc",
+	}
+	for tmpl, want := range tests {
+		if got := Name(tmpl, f, 3); got != want {
+			t.Fatalf("Name(%q) = %q, want %q", tmpl, got, want)
+		}
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"", DefaultName, "{date}_{profile}_{lang}_{seq}", "rec {yyyy}"} {
+		if err := ValidateName(name); err != nil {
+			t.Fatalf("ValidateName(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"{yyyy}/{date}", "a:b", "{nope}"} {
+		if err := ValidateName(name); err == nil {
+			t.Fatalf("ValidateName(%q) succeeded, want error", name)
+		}
+	}
+}
+
+func TestReserveSkipsTakenNames(t *testing.T) {
+	dir := t.TempDir()
+	f := Fields{Time: time.Date(2026, 1, 5, 9, 30, 7, 0, time.Local)}
+
+	var got []string
+	for i := 0; i < 3; i++ {
+		name, err := Reserve(dir, "{date}_{seq}", f, ".meta.json")
+		if err != nil {
+			t.Fatalf("Reserve failed: %v", err)
+		}
+		got = append(got, name)
+	}
+	want := []string{"2026-01-05_01", "2026-01-05_02", "2026-01-05_03"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Reserve #%d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	for _, want := range []string{"audio-2026-01-05-09.30.07", "audio-2026-01-05-09.30.07-2"} {
+		name, err := Reserve(dir, "", f, ".meta.json")
+		if err != nil || name != want {
+			t.Fatalf("Reserve without {seq} = %q, %v; want %q", name, err, want)
+		}
+		if _, err := os.Stat(filepath.Join(dir, name+".meta.json")); err != nil {
+			t.Fatalf("marker for %s not created: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build darwin
+
+package clipboard
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/atotto/clipboard"
+)
+
+// pasteScript presses Cmd+V in the frontmost app. System Events only
+// accepts it once the terminal or STT is allowed under Privacy & Security >
+// Accessibility.
+const pasteScript = `tell application "System Events" to keystroke "v" using command down`
+
+// PasteText writes text to clipboard through pbcopy, sends Cmd+V, and
+// restores clipboard.
+func PasteText(text string) error {
+	orig, _ := clipboard.ReadAll()
+	if err := clipboard.WriteAll(text); err != nil {
+		return err
+	}
+	time.Sleep(80 * time.Millisecond)
+
+	if out, err := exec.Command("osascript", "-e", pasteScript).CombinedOutput(); err != nil {
+		return fmt.Errorf("osascript: %v: %s", err, out)
+	}
+	time.Sleep(120 * time.Millisecond)
+	_ = clipboard.WriteAll(orig)
+	return nil
+}
+
+// CopyText puts text on the clipboard without pasting it.
+func CopyText(text string) error {
+	return clipboard.WriteAll(text)
+}
+
+// Sequence always returns 0 on macOS builds.
+func Sequence() uint32 {
+	return 0
+}
+
+// Files is not supported on macOS builds.
+func Files() ([]string, error) {
+	return nil, fmt.Errorf("clipboard watch not supported on this platform")
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build linux
+
+package clipboard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/atotto/clipboard"
+)
+
+// PasteText writes text to clipboard, sends Ctrl+V, and restores clipboard.
+// The clipboard goes through wl-copy/wl-paste on Wayland and xclip or xsel
+// on X11; the keystroke is typed by wtype, ydotool or xdotool.
+func PasteText(text string) error {
+	name, args, err := pasteCommand(os.Getenv, exec.LookPath)
+	if err != nil {
+		return err
+	}
+	orig, _ := clipboard.ReadAll()
+	if err := clipboard.WriteAll(text); err != nil {
+		return err
+	}
+	time.Sleep(80 * time.Millisecond)
+
+	if out, err := exec.Command(name, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %v: %s", name, err, out)
+	}
+	time.Sleep(120 * time.Millisecond)
+	_ = clipboard.WriteAll(orig)
+	return nil
+}
+
+// CopyText puts text on the clipboard without pasting it.
+func CopyText(text string) error {
+	return clipboard.WriteAll(text)
+}
+
+// Sequence always returns 0 on Linux builds.
+func Sequence() uint32 {
+	return 0
+}
+
+// Files is not supported on Linux builds.
+func Files() ([]string, error) {
+	return nil, fmt.Errorf("clipboard watch not supported on this platform")
+}
+
+// pasteCommand picks the tool that sends Ctrl+V to the focused window.
+// Wayland sessions prefer wtype and then ydotool; X11 sessions, and
+// XWayland as a last resort, use xdotool.
+func pasteCommand(getenv func(string) string, lookPath func(string) (string, error)) (string, []string, error) {
+	type tool struct {
+		name string
+		args []string
+	}
+	var tools []tool
+	if getenv("WAYLAND_DISPLAY") != "" {
+		tools = append(tools,
+			tool{"wtype", []string{"-M", "ctrl", "v", "-m", "ctrl"}},
+			// 29 and 47 are the evdev codes of left Ctrl and V.
+			tool{"ydotool", []string{"key", "29:1", "47:1", "47:0", "29:0"}},
+		)
+	}
+	if getenv("DISPLAY") != "" {
+		tools = append(tools, tool{"xdotool", []string{"key", "--clearmodifiers", "ctrl+v"}})
+	}
+	if len(tools) == 0 {
+		return "", nil, fmt.Errorf("clipboard paste needs a Wayland or X11 session")
+	}
+	for _, t := range tools {
+		if _, err := lookPath(t.name); err == nil {
+			return t.name, t.args, nil
+		}
+	}
+	if getenv("WAYLAND_DISPLAY") != "" {
+		return "", nil, fmt.Errorf("clipboard paste needs wtype or ydotool (or xdotool for XWayland windows)")
+	}
+	return "", nil, fmt.Errorf("clipboard paste needs xdotool")
+}
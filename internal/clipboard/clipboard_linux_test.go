@@ -0,0 +1,58 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build linux
+
+package clipboard
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPasteCommand(t *testing.T) {
+	tests := []struct {
+		env       map[string]string
+		installed []string
+		want      string
+		wantErr   string
+	}{
+		{env: map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, installed: []string{"wtype", "ydotool", "xdotool"}, want: "wtype"},
+		{env: map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, installed: []string{"ydotool", "xdotool"}, want: "ydotool"},
+		{env: map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, installed: []string{"xdotool"}, want: "xdotool"},
+		{env: map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, installed: []string{"xdotool"}, wantErr: "wtype or ydotool"},
+		{env: map[string]string{"DISPLAY": ":0"}, installed: []string{"wtype", "xdotool"}, want: "xdotool"},
+		{env: map[string]string{"DISPLAY": ":0"}, installed: []string{"wtype"}, wantErr: "needs xdotool"},
+		{env: map[string]string{}, installed: []string{"xdotool"}, wantErr: "Wayland or X11"},
+	}
+	for _, tt := range tests {
+		getenv := func(k string) string { return tt.env[k] }
+		lookPath := func(name string) (string, error) {
+			if slices.Contains(tt.installed, name) {
+				return "/usr/bin/" + name, nil
+			}
+			return "", errors.New("not found")
+		}
+		got, _, err := pasteCommand(getenv, lookPath)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("pasteCommand(%v, %v) error = %v, want %q", tt.env, tt.installed, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("pasteCommand(%v, %v) = %q, %v, want %q", tt.env, tt.installed, got, err, tt.want)
+		}
+	}
+}
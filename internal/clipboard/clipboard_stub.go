@@ -9,7 +9,7 @@
 // implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
 // See <https://www.gnu.org/licenses/> for more details.
 
-//go:build !windows
+//go:build !windows && !linux && !darwin
 
 package clipboard
 
@@ -19,3 +19,18 @@ import "fmt"
 func PasteText(text string) error {
 	return fmt.Errorf("clipboard paste not supported on this platform")
 }
+
+// CopyText is not supported on non-Windows builds.
+func CopyText(text string) error {
+	return fmt.Errorf("clipboard copy not supported on this platform")
+}
+
+// Sequence always returns 0 on non-Windows builds.
+func Sequence() uint32 {
+	return 0
+}
+
+// Files is not supported on non-Windows builds.
+func Files() ([]string, error) {
+	return nil, fmt.Errorf("clipboard watch not supported on this platform")
+}
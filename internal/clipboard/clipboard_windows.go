@@ -39,3 +39,8 @@ func PasteText(text string) error {
 	_ = clipboard.WriteAll(orig)
 	return nil
 }
+
+// CopyText puts text on the clipboard without pasting it.
+func CopyText(text string) error {
+	return clipboard.WriteAll(text)
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package clipboard
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/atotto/clipboard"
+)
+
+// cfHDROP is the clipboard format of files copied in Explorer.
+const cfHDROP = 15
+
+var (
+	user32                         = syscall.NewLazyDLL("user32.dll")
+	procGetClipboardSequenceNumber = user32.NewProc("GetClipboardSequenceNumber")
+	procIsClipboardFormatAvailable = user32.NewProc("IsClipboardFormatAvailable")
+	procOpenClipboard              = user32.NewProc("OpenClipboard")
+	procCloseClipboard             = user32.NewProc("CloseClipboard")
+	procGetClipboardData           = user32.NewProc("GetClipboardData")
+	procDragQueryFileW             = syscall.NewLazyDLL("shell32.dll").NewProc("DragQueryFileW")
+)
+
+// Sequence returns a number that changes whenever the clipboard does.
+func Sequence() uint32 {
+	n, _, _ := procGetClipboardSequenceNumber.Call()
+	return uint32(n)
+}
+
+// Files returns the files on the clipboard: those copied in Explorer, or
+// the paths in its text (see PathsInText). The files need not exist.
+func Files() ([]string, error) {
+	if ok, _, _ := procIsClipboardFormatAvailable.Call(cfHDROP); ok == 0 {
+		text, err := clipboard.ReadAll()
+		if err != nil {
+			return nil, nil // empty or not text
+		}
+		return PathsInText(text), nil
+	}
+	// Another program may hold the clipboard for a moment after changing it.
+	var opened bool
+	for i := 0; i < 10 && !opened; i++ {
+		if ok, _, _ := procOpenClipboard.Call(0); ok != 0 {
+			opened = true
+		} else {
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	if !opened {
+		return nil, fmt.Errorf("clipboard is busy")
+	}
+	defer procCloseClipboard.Call()
+
+	h, _, err := procGetClipboardData.Call(cfHDROP)
+	if h == 0 {
+		return nil, err
+	}
+	n, _, _ := procDragQueryFileW.Call(h, 0xFFFFFFFF, 0, 0)
+	files := make([]string, 0, n)
+	for i := uintptr(0); i < n; i++ {
+		size, _, _ := procDragQueryFileW.Call(h, i, 0, 0)
+		buf := make([]uint16, size+1)
+		procDragQueryFileW.Call(h, i, uintptr(unsafe.Pointer(&buf[0])), size+1)
+		files = append(files, syscall.UTF16ToString(buf))
+	}
+	return files, nil
+}
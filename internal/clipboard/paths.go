@@ -0,0 +1,55 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package clipboard
+
+import "strings"
+
+// maxPathText bounds the clipboard text PathsInText looks at; longer text is
+// not a list of paths.
+const maxPathText = 64 << 10
+
+// PathsInText returns the absolute Windows paths in text, one per line, as
+// Explorer's "Copy as path" puts them on the clipboard. Quotes around a path
+// are removed. Text with any line that is not a path returns nil, so copying
+// prose that merely mentions a file does not count.
+func PathsInText(text string) []string {
+	if len(text) > maxPathText {
+		return nil
+	}
+	var paths []string
+	for _, line := range strings.Split(text, "\n") {
+		p := strings.TrimSpace(line)
+		if p == "" {
+			continue
+		}
+		if len(p) >= 2 && p[0] == '"' && p[len(p)-1] == '"' {
+			p = p[1 : len(p)-1]
+		}
+		if !isAbs(p) {
+			return nil
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// isAbs reports whether p is a drive or UNC path.
+func isAbs(p string) bool {
+	if strings.HasPrefix(p, `\\`) {
+		return len(p) > 2
+	}
+	if len(p) < 3 || p[1] != ':' || (p[2] != '\\' && p[2] != '/') {
+		return false
+	}
+	c := p[0] | 0x20
+	return c >= 'a' && c <= 'z'
+}
@@ -0,0 +1,37 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+package clipboard
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPathsInText(t *testing.T) {
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{`D:\Calls\a.mp3`, []string{`D:\Calls\a.mp3`}},
+		{"\"C:\\My Files\\b.wav\"\r\n\"C:\\My Files\\c.wav\"\r\n", []string{`C:\My Files\b.wav`, `C:\My Files\c.wav`}},
+		{`\\nas\share\d.m4a`, []string{`\\nas\share\d.m4a`}},
+		{"see D:\\Calls\\a.mp3 for details", nil},
+		{"D:\\Calls\\a.mp3\nnot a path", nil},
+		{"a.mp3", nil},
+		{"", nil},
+	}
+	for _, c := range cases {
+		if got := PathsInText(c.text); !slices.Equal(got, c.want) {
+			t.Errorf("PathsInText(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
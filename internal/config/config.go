@@ -12,45 +12,144 @@
 package config
 
 import (
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+
+	"stt/internal/applog"
+	"stt/internal/cachecrypt"
+	"stt/internal/cachepath"
+	"stt/internal/hotkey"
+	"stt/internal/httpapi"
+	"stt/internal/i18n"
+	"stt/internal/midi"
+	"stt/internal/schedule"
+	"stt/internal/sound"
+	"stt/internal/transcript"
+	"stt/internal/tray"
+	"stt/pkg/audio/ffmpeg"
+	"stt/pkg/record/source"
 )
 
 // Config holds configurable parameters.
 type Config struct {
-	APIEndpoint               string  `json:"API_ENDPOINT"`
-	Token                     string  `json:"TOKEN"`
-	Model                     string  `json:"MODEL"`
-	Language                  string  `json:"LANGUAGE"`
-	Prompt                    string  `json:"PROMPT"`
-	TEXTPath                  string  `json:"TEXT_PATH"`
-	ExtraConfig               string  `json:"ExtraConfig"`
-	Channels                  int     `json:"CHANNELS"`
-	SAMPLING_RATE             int     `json:"SAMPLING_RATE"`
-	SAMPLING_RATE_DEPTH       int     `json:"SAMPLING_RATE_DEPTH"`
-	BIT_RATE                  int     `json:"BIT_RATE"`
-	CODECS                    string  `json:"CODECS"`
-	CONTAINER                 string  `json:"CONTAINER"`
-	RequestTimeout            int     `json:"REQUEST_TIMEOUT"`
-	MaxRetry                  int     `json:"MAX_RETRY"`
-	RetryBaseDelay            float64 `json:"RETRY_BASE_DELAY"`
-	EnableHTTP2               bool    `json:"ENABLE_HTTP2"`
-	VerifySSL                 bool    `json:"VERIFY_SSL"`
-	HotKeyHook                bool    `json:"HOTKEY_HOOK"`
-	StartKey                  string  `json:"START_KEY"`
-	PauseKey                  string  `json:"PAUSE_KEY"`
-	CancelKey                 string  `json:"CANCEL_KEY"`
-	CacheDir                  string  `json:"CACHE_DIR"`
-	KeepCache                 bool    `json:"KEEP_CACHE"`
-	Notification              bool    `json:"NOTIFICATION"`
-	RequestFailedNotification bool    `json:"REQUEST_FAILED_NOTIFICATION"`
-	FFMPEG_DEBUG              bool    `json:"FFMPEG_DEBUG"`
-	RECORD_DEBUG              bool    `json:"RECORD_DEBUG"`
-	HOTKEY_DEBUG              bool    `json:"HOTKEY_DEBUG"`
-	UPLOAD_DEBUG              bool    `json:"UPLOAD_DEBUG"`
+	APIEndpoint               string    `json:"API_ENDPOINT"`
+	Token                     string    `json:"TOKEN"`
+	Model                     string    `json:"MODEL"`
+	Language                  string    `json:"LANGUAGE"`
+	Provider                  string    `json:"PROVIDER"`
+	Profile                   string    `json:"PROFILE"`
+	MachineID                 string    `json:"MACHINE_ID"`
+	Prompt                    string    `json:"PROMPT"`
+	TEXTPath                  string    `json:"TEXT_PATH"`
+	PostCommand               string    `json:"POST_COMMAND"`
+	ExtraConfig               ExtraJSON `json:"ExtraConfig"`
+	Channels                  int       `json:"CHANNELS"`
+	InputDevice               string    `json:"INPUT_DEVICE"`
+	Source                    string    `json:"SOURCE"`
+	MaxRecordSeconds          int       `json:"MAX_RECORD_SECONDS"`
+	RecordSegmentSeconds      int       `json:"RECORD_SEGMENT_SECONDS"`
+	LockAction                string    `json:"LOCK_ACTION"`
+	SAMPLING_RATE             int       `json:"SAMPLING_RATE"`
+	SAMPLING_RATE_DEPTH       int       `json:"SAMPLING_RATE_DEPTH"`
+	BIT_RATE                  int       `json:"BIT_RATE"`
+	CODECS                    string    `json:"CODECS"`
+	CONTAINER                 string    `json:"CONTAINER"`
+	RequestTimeout            int       `json:"REQUEST_TIMEOUT"`
+	MaxRetry                  int       `json:"MAX_RETRY"`
+	RetryBaseDelay            float64   `json:"RETRY_BASE_DELAY"`
+	EnableHTTP2               bool      `json:"ENABLE_HTTP2"`
+	VerifySSL                 bool      `json:"VERIFY_SSL"`
+	CACertFile                string    `json:"CA_CERT_FILE"`
+	StartupCheck              bool      `json:"STARTUP_CHECK"`
+	UpdateCheck               bool      `json:"UPDATE_CHECK"`
+	Watchdog                  bool      `json:"WATCHDOG"`
+	Onboarding                bool      `json:"ONBOARDING"`
+	HTTPAPI                   string    `json:"HTTP_API"`
+	HTTPAPIToken              string    `json:"HTTP_API_TOKEN"`
+	GRPCAPI                   string    `json:"GRPC_API"`
+	GRPCAPIToken              string    `json:"GRPC_API_TOKEN"`
+	HotKeyHook                bool      `json:"HOTKEY_HOOK"`
+	PTT                       bool      `json:"PTT"`
+	StartKey                  string    `json:"START_KEY"`
+	PauseKey                  string    `json:"PAUSE_KEY"`
+	CancelKey                 string    `json:"CANCEL_KEY"`
+	MIDIInput                 string    `json:"MIDI_INPUT"`
+	MIDIMap                   string    `json:"MIDI_MAP"`
+	Schedule                  string    `json:"SCHEDULE"`
+	ClipboardWatch            bool      `json:"CLIPBOARD_WATCH"`
+	CacheDir                  string    `json:"CACHE_DIR"`
+	KeepCache                 bool      `json:"KEEP_CACHE"`
+	KeepWav                   bool      `json:"KEEP_WAV"`
+	KeepConverted             bool      `json:"KEEP_CONVERTED"`
+	CacheLayout               string    `json:"CACHE_LAYOUT"`
+	CacheName                 string    `json:"CACHE_NAME"`
+	CacheArchiveDays          int       `json:"CACHE_ARCHIVE_DAYS"`
+	CacheMaxAgeDays           int       `json:"CACHE_MAX_AGE_DAYS"`
+	CacheMaxSizeMB            int       `json:"CACHE_MAX_SIZE_MB"`
+	CachePurgeInterval        int       `json:"CACHE_PURGE_INTERVAL"`
+	History                   bool      `json:"HISTORY"`
+	CacheEncryption           string    `json:"CACHE_ENCRYPTION"`
+	CachePassphrase           string    `json:"CACHE_PASSPHRASE"`
+	RetryQueue                bool      `json:"RETRY_QUEUE"`
+	QueueRetryInterval        int       `json:"QUEUE_RETRY_INTERVAL"`
+	OfflineFirst              bool      `json:"OFFLINE_FIRST"`
+	Notification              bool      `json:"NOTIFICATION"`
+	Tray                      bool      `json:"TRAY"`
+	TrayTheme                 string    `json:"TRAY_THEME"`
+	VUMeter                   bool      `json:"VU_METER"`
+	RecordingTimer            bool      `json:"RECORDING_TIMER"`
+	SoundMute                 bool      `json:"SOUND_MUTE"`
+	SoundStart                string    `json:"SOUND_START"`
+	SoundStop                 string    `json:"SOUND_STOP"`
+	SoundPasteSuccess         string    `json:"SOUND_PASTE_SUCCESS"`
+	SoundUploadFailed         string    `json:"SOUND_UPLOAD_FAILED"`
+	SoundError                string    `json:"SOUND_ERROR"`
+	RequestFailedNotification bool      `json:"REQUEST_FAILED_NOTIFICATION"`
+	ProgressNotification      bool      `json:"PROGRESS_NOTIFICATION"`
+	TimingNotification        bool      `json:"TIMING_NOTIFICATION"`
+	QuietMode                 bool      `json:"QUIET_MODE"`
+	LogFile                   string    `json:"LOG_FILE"`
+	LogMaxSizeMB              int       `json:"LOG_MAX_SIZE_MB"`
+	LogRotateDays             int       `json:"LOG_ROTATE_DAYS"`
+	LogMaxBackups             int       `json:"LOG_MAX_BACKUPS"`
+	FFMPEG_PATH               string    `json:"FFMPEG_PATH"`
+	FFMPEG_DEBUG              bool      `json:"FFMPEG_DEBUG"`
+	FFMPEG_TIMEOUT            int       `json:"FFMPEG_TIMEOUT"`
+	FFMPEG_INPUT_ARGS         string    `json:"FFMPEG_INPUT_ARGS"`
+	FFMPEG_EXTRA_ARGS         string    `json:"FFMPEG_EXTRA_ARGS"`
+	FFMPEG_THREADS            int       `json:"FFMPEG_THREADS"`
+	CompressionLevel          int       `json:"COMPRESSION_LEVEL"`
+	OpusVBR                   string    `json:"OPUS_VBR"`
+	AudioHighpass             int       `json:"AUDIO_HIGHPASS"`
+	AudioLowpass              int       `json:"AUDIO_LOWPASS"`
+	AudioDenoise              bool      `json:"AUDIO_DENOISE"`
+	AudioSpeed                float64   `json:"AUDIO_SPEED"`
+	FileChunkSeconds          int       `json:"FILE_CHUNK_SECONDS"`
+	FileChunkOverlap          int       `json:"FILE_CHUNK_OVERLAP"`
+	MaxUploadMB               int       `json:"MAX_UPLOAD_MB"`
+	AudioTrack                int       `json:"AUDIO_TRACK"`
+	ConvertWorkers            int       `json:"CONVERT_WORKERS"`
+	OutputFormat              string    `json:"OUTPUT_FORMAT"`
+	OutputTemplate            string    `json:"OUTPUT_TEMPLATE"`
+	ProgressBar               bool      `json:"PROGRESS_BAR"`
+	SkipExisting              bool      `json:"SKIP_EXISTING"`
+	Recursive                 bool      `json:"RECURSIVE"`
+	IncludeExtensions         string    `json:"INCLUDE_EXTENSIONS"`
+	ExcludeExtensions         string    `json:"EXCLUDE_EXTENSIONS"`
+	FileMinSeconds            int       `json:"FILE_MIN_SECONDS"`
+	FileMaxSeconds            int       `json:"FILE_MAX_SECONDS"`
+	MaxConcurrentUploads      int       `json:"MAX_CONCURRENT_UPLOADS"`
+	RequestsPerMinute         int       `json:"REQUESTS_PER_MINUTE"`
+	RequestsBurst             int       `json:"REQUESTS_BURST"`
+	RECORD_DEBUG              bool      `json:"RECORD_DEBUG"`
+	HOTKEY_DEBUG              bool      `json:"HOTKEY_DEBUG"`
+	UPLOAD_DEBUG              bool      `json:"UPLOAD_DEBUG"`
+	UILang                    string    `json:"UI_LANG"`
 }
 
 // DefaultConfig returns a Config with default values.
@@ -60,10 +159,19 @@ func DefaultConfig() Config {
 		Token:                     "",
 		Model:                     "",
 		Language:                  "",
+		Provider:                  "",
+		Profile:                   "",
+		MachineID:                 "",
 		Prompt:                    "",
 		TEXTPath:                  "text",
+		PostCommand:               "",
 		ExtraConfig:               "",
 		Channels:                  1,
+		InputDevice:               "",
+		Source:                    "microphone",
+		MaxRecordSeconds:          0,
+		RecordSegmentSeconds:      0,
+		LockAction:                "pause",
 		SAMPLING_RATE:             16000,
 		SAMPLING_RATE_DEPTH:       16,
 		BIT_RATE:                  32,
@@ -74,39 +182,159 @@ func DefaultConfig() Config {
 		RetryBaseDelay:            0.5,
 		EnableHTTP2:               true,
 		VerifySSL:                 true,
+		CACertFile:                "",
+		StartupCheck:              false,
+		UpdateCheck:               false,
+		Watchdog:                  false,
+		Onboarding:                true,
+		HTTPAPI:                   "",
+		HTTPAPIToken:              "",
+		GRPCAPI:                   "",
+		GRPCAPIToken:              "",
 		HotKeyHook:                true,
+		PTT:                       false,
 		StartKey:                  "ctrl+alt+q",
 		PauseKey:                  "ctrl+alt+s",
 		CancelKey:                 "alt+esc",
+		MIDIInput:                 "",
+		MIDIMap:                   midi.DefaultMap,
+		Schedule:                  "",
+		ClipboardWatch:            false,
 		CacheDir:                  "",
 		KeepCache:                 false,
+		KeepWav:                   true,
+		KeepConverted:             true,
+		CacheLayout:               cachepath.DefaultLayout,
+		CacheName:                 cachepath.DefaultName,
+		CacheArchiveDays:          0,
+		CacheMaxAgeDays:           0,
+		CacheMaxSizeMB:            0,
+		CachePurgeInterval:        60,
+		History:                   true,
+		CacheEncryption:           "",
+		CachePassphrase:           "",
+		RetryQueue:                true,
+		QueueRetryInterval:        60,
+		OfflineFirst:              false,
 		Notification:              false,
+		Tray:                      true,
+		TrayTheme:                 "auto",
+		VUMeter:                   false,
+		RecordingTimer:            false,
+		SoundMute:                 false,
+		SoundStart:                "",
+		SoundStop:                 "",
+		SoundPasteSuccess:         "",
+		SoundUploadFailed:         "",
+		SoundError:                "",
 		RequestFailedNotification: false,
+		ProgressNotification:      true,
+		TimingNotification:        false,
+		QuietMode:                 true,
+		LogFile:                   "stt.log",
+		LogMaxSizeMB:              5,
+		LogRotateDays:             0,
+		LogMaxBackups:             1,
+		FFMPEG_PATH:               "",
 		FFMPEG_DEBUG:              false,
+		FFMPEG_TIMEOUT:            1800,
+		FFMPEG_INPUT_ARGS:         "",
+		FFMPEG_EXTRA_ARGS:         "",
+		FFMPEG_THREADS:            0,
+		CompressionLevel:          -1,
+		OpusVBR:                   "",
+		AudioHighpass:             0,
+		AudioLowpass:              0,
+		AudioDenoise:              false,
+		AudioSpeed:                1,
+		FileChunkSeconds:          0,
+		FileChunkOverlap:          5,
+		MaxUploadMB:               0,
+		AudioTrack:                0,
+		ConvertWorkers:            2,
+		OutputFormat:              "txt",
+		OutputTemplate:            "",
+		ProgressBar:               true,
+		SkipExisting:              false,
+		Recursive:                 false,
+		IncludeExtensions:         "",
+		ExcludeExtensions:         "",
+		FileMinSeconds:            0,
+		FileMaxSeconds:            0,
+		MaxConcurrentUploads:      0,
+		RequestsPerMinute:         0,
+		RequestsBurst:             1,
 		RECORD_DEBUG:              false,
 		HOTKEY_DEBUG:              true,
 		UPLOAD_DEBUG:              false,
+		UILang:                    "",
 	}
 }
 
 // Load loads config from JSON file if provided.
 func Load(path string) (Config, error) {
-	cfg := DefaultConfig()
 	if path == "" {
-		return cfg, nil
+		return DefaultConfig(), nil
 	}
-	f, err := os.Open(path)
+	cfg, err := readRaw(path)
 	if err != nil {
 		return cfg, err
 	}
-	defer f.Close()
-	dec := json.NewDecoder(f)
-	if err := dec.Decode(&cfg); err != nil {
+	if err := DecryptSecrets(&cfg); err != nil {
+		return cfg, err
+	}
+	baseDir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
 		return cfg, err
 	}
+	ResolvePaths(&cfg, baseDir)
 	return cfg, nil
 }
 
+// ResolvePaths makes relative path settings absolute against baseDir, which is
+// normally the directory containing the config file. Empty values are left
+// untouched so that defaults keep their meaning.
+func ResolvePaths(cfg *Config, baseDir string) {
+	for _, p := range pathFields(cfg) {
+		if *p == "" || filepath.IsAbs(*p) {
+			continue
+		}
+		*p = filepath.Join(baseDir, *p)
+	}
+	for _, cue := range soundFields(cfg) {
+		if sound.IsFile(*cue.spec) && !filepath.IsAbs(*cue.spec) {
+			*cue.spec = filepath.Join(baseDir, *cue.spec)
+		}
+	}
+}
+
+// pathFields lists the config fields that hold filesystem paths.
+func pathFields(cfg *Config) []*string {
+	return []*string{
+		&cfg.CacheDir,
+		&cfg.FFMPEG_PATH,
+		&cfg.CACertFile,
+	}
+}
+
+// soundFields lists the SOUND_* cues, which hold either a WAV path or a
+// Windows sound alias.
+func soundFields(cfg *Config) []struct {
+	key  string
+	spec *string
+} {
+	return []struct {
+		key  string
+		spec *string
+	}{
+		{"SOUND_START", &cfg.SoundStart},
+		{"SOUND_STOP", &cfg.SoundStop},
+		{"SOUND_PASTE_SUCCESS", &cfg.SoundPasteSuccess},
+		{"SOUND_UPLOAD_FAILED", &cfg.SoundUploadFailed},
+		{"SOUND_ERROR", &cfg.SoundError},
+	}
+}
+
 // SaveDefault writes a default config JSON to the provided path.
 func SaveDefault(path string) error {
 	cfg := DefaultConfig()
@@ -198,9 +426,198 @@ func Validate(cfg *Config) error {
 	if !allowedContainers[strings.ToLower(cfg.CONTAINER)] {
 		return fmt.Errorf("invalid CONTAINER: %s (allowed: WAV, AC3, AC4, OGG, OGA, MP3, FLAC, EAC3, AAC, M4A, MP4, OPUS, WEBM, S8, S16BE, S16LE, S24BE, S24LE, S32BE, S32LE, F32BE, F32LE, F64BE, F64LE)", cfg.CONTAINER)
 	}
+
+	if err := hotkey.Validate(cfg.StartKey, cfg.PauseKey, cfg.CancelKey); err != nil {
+		return err
+	}
+	// Without the hook, Windows hotkeys and the Linux portal never report
+	// the start hotkey coming back up, so push-to-talk could not stop.
+	if cfg.PTT && !cfg.HotKeyHook {
+		return fmt.Errorf("invalid PTT: push-to-talk needs HOTKEY_HOOK=true to see the start hotkey released")
+	}
+	if _, err := cfg.ExtraConfig.Object(); err != nil {
+		return fmt.Errorf("invalid ExtraConfig: %w", err)
+	}
+	if !i18n.Valid(cfg.UILang) {
+		return fmt.Errorf("invalid UI_LANG: %s (allowed: zh, en, or empty for auto)", cfg.UILang)
+	}
+	if !source.Valid(cfg.Source) {
+		return fmt.Errorf("invalid SOURCE: %s (allowed: microphone, loopback)", cfg.Source)
+	}
+	if !tray.ValidTheme(cfg.TrayTheme) {
+		return fmt.Errorf("invalid TRAY_THEME: %s (allowed: auto, light, dark)", cfg.TrayTheme)
+	}
+	if !cachecrypt.Valid(cfg.CacheEncryption) {
+		return fmt.Errorf("invalid CACHE_ENCRYPTION: %s (allowed: dpapi, passphrase, or empty to disable)", cfg.CacheEncryption)
+	}
+	if cfg.QueueRetryInterval <= 0 {
+		return fmt.Errorf("invalid QUEUE_RETRY_INTERVAL: %d (must be > 0)", cfg.QueueRetryInterval)
+	}
+	if cfg.FFMPEG_TIMEOUT < 0 {
+		return fmt.Errorf("invalid FFMPEG_TIMEOUT: %d (must be >= 0)", cfg.FFMPEG_TIMEOUT)
+	}
+	if _, err := ffmpeg.SplitArgs(cfg.FFMPEG_INPUT_ARGS); err != nil {
+		return fmt.Errorf("invalid FFMPEG_INPUT_ARGS: %w", err)
+	}
+	if _, err := ffmpeg.SplitArgs(cfg.FFMPEG_EXTRA_ARGS); err != nil {
+		return fmt.Errorf("invalid FFMPEG_EXTRA_ARGS: %w", err)
+	}
+	if cfg.FFMPEG_THREADS < 0 {
+		return fmt.Errorf("invalid FFMPEG_THREADS: %d (must be >= 0)", cfg.FFMPEG_THREADS)
+	}
+	maxLevel := 12
+	if codec := strings.ToLower(cfg.CODECS); codec == "opus" || codec == "libopus" {
+		maxLevel = 10
+	}
+	if cfg.CompressionLevel < -1 || cfg.CompressionLevel > maxLevel {
+		return fmt.Errorf("invalid COMPRESSION_LEVEL: %d (must be -1 to %d for CODECS %s)", cfg.CompressionLevel, maxLevel, cfg.CODECS)
+	}
+	switch strings.ToLower(cfg.OpusVBR) {
+	case "", "on", "off", "constrained":
+	default:
+		return fmt.Errorf("invalid OPUS_VBR: %s (allowed: on, off, constrained, or empty)", cfg.OpusVBR)
+	}
+	if cfg.AudioHighpass < 0 {
+		return fmt.Errorf("invalid AUDIO_HIGHPASS: %d (must be >= 0)", cfg.AudioHighpass)
+	}
+	if cfg.AudioLowpass < 0 {
+		return fmt.Errorf("invalid AUDIO_LOWPASS: %d (must be >= 0)", cfg.AudioLowpass)
+	}
+	if cfg.AudioSpeed != 0 && (cfg.AudioSpeed < 1 || cfg.AudioSpeed > 2) {
+		return fmt.Errorf("invalid AUDIO_SPEED: %g (must be between 1 and 2)", cfg.AudioSpeed)
+	}
+	if cfg.AudioHighpass > 0 && cfg.AudioLowpass > 0 && cfg.AudioHighpass >= cfg.AudioLowpass {
+		return fmt.Errorf("invalid AUDIO_HIGHPASS: %d (must be below AUDIO_LOWPASS %d)", cfg.AudioHighpass, cfg.AudioLowpass)
+	}
+	if cfg.FileChunkSeconds < 0 {
+		return fmt.Errorf("invalid FILE_CHUNK_SECONDS: %d (must be >= 0)", cfg.FileChunkSeconds)
+	}
+	if cfg.FileChunkOverlap < 0 || cfg.FileChunkSeconds > 0 && cfg.FileChunkOverlap*2 >= cfg.FileChunkSeconds {
+		return fmt.Errorf("invalid FILE_CHUNK_OVERLAP: %d (must be >= 0 and below half of FILE_CHUNK_SECONDS)", cfg.FileChunkOverlap)
+	}
+	if cfg.MaxUploadMB < 0 {
+		return fmt.Errorf("invalid MAX_UPLOAD_MB: %d (must be >= 0)", cfg.MaxUploadMB)
+	}
+	if cfg.AudioTrack < 0 {
+		return fmt.Errorf("invalid AUDIO_TRACK: %d (must be >= 0)", cfg.AudioTrack)
+	}
+	if cfg.ConvertWorkers < 1 {
+		return fmt.Errorf("invalid CONVERT_WORKERS: %d (must be >= 1)", cfg.ConvertWorkers)
+	}
+	if !slices.Contains(transcript.Formats, strings.ToLower(cfg.OutputFormat)) {
+		return fmt.Errorf("invalid OUTPUT_FORMAT: %s (allowed: %s)", cfg.OutputFormat, strings.Join(transcript.Formats, ", "))
+	}
+	if err := transcript.ValidateTemplate(cfg.OutputTemplate); err != nil {
+		return fmt.Errorf("invalid OUTPUT_TEMPLATE %q: %w", cfg.OutputTemplate, err)
+	}
+	if cfg.MaxConcurrentUploads < 0 {
+		return fmt.Errorf("invalid MAX_CONCURRENT_UPLOADS: %d (must be >= 0)", cfg.MaxConcurrentUploads)
+	}
+	if cfg.RequestsPerMinute < 0 {
+		return fmt.Errorf("invalid REQUESTS_PER_MINUTE: %d (must be >= 0)", cfg.RequestsPerMinute)
+	}
+	if cfg.RequestsBurst < 1 {
+		return fmt.Errorf("invalid REQUESTS_BURST: %d (must be >= 1)", cfg.RequestsBurst)
+	}
+	if cfg.FileMinSeconds < 0 {
+		return fmt.Errorf("invalid FILE_MIN_SECONDS: %d (must be >= 0)", cfg.FileMinSeconds)
+	}
+	if cfg.FileMaxSeconds < 0 || cfg.FileMaxSeconds > 0 && cfg.FileMaxSeconds < cfg.FileMinSeconds {
+		return fmt.Errorf("invalid FILE_MAX_SECONDS: %d (must be >= 0 and not below FILE_MIN_SECONDS)", cfg.FileMaxSeconds)
+	}
+	if err := cachepath.ValidateLayout(cfg.CacheLayout); err != nil {
+		return fmt.Errorf("invalid CACHE_LAYOUT %q: %w", cfg.CacheLayout, err)
+	}
+	if err := cachepath.ValidateName(cfg.CacheName); err != nil {
+		return fmt.Errorf("invalid CACHE_NAME %q: %w", cfg.CacheName, err)
+	}
+	if cfg.CacheArchiveDays < 0 {
+		return fmt.Errorf("invalid CACHE_ARCHIVE_DAYS: %d (must be >= 0)", cfg.CacheArchiveDays)
+	}
+	if cfg.CacheMaxAgeDays < 0 {
+		return fmt.Errorf("invalid CACHE_MAX_AGE_DAYS: %d (must be >= 0)", cfg.CacheMaxAgeDays)
+	}
+	if cfg.CacheMaxSizeMB < 0 {
+		return fmt.Errorf("invalid CACHE_MAX_SIZE_MB: %d (must be >= 0)", cfg.CacheMaxSizeMB)
+	}
+	if cfg.LogMaxSizeMB < 0 {
+		return fmt.Errorf("invalid LOG_MAX_SIZE_MB: %d (must be >= 0)", cfg.LogMaxSizeMB)
+	}
+	if cfg.LogRotateDays < 0 {
+		return fmt.Errorf("invalid LOG_ROTATE_DAYS: %d (must be >= 0)", cfg.LogRotateDays)
+	}
+	if cfg.LogMaxBackups < 0 {
+		return fmt.Errorf("invalid LOG_MAX_BACKUPS: %d (must be >= 0)", cfg.LogMaxBackups)
+	}
+	if cfg.MaxRecordSeconds < 0 {
+		return fmt.Errorf("invalid MAX_RECORD_SECONDS: %d (must be >= 0)", cfg.MaxRecordSeconds)
+	}
+	if cfg.RecordSegmentSeconds < 0 {
+		return fmt.Errorf("invalid RECORD_SEGMENT_SECONDS: %d (must be >= 0)", cfg.RecordSegmentSeconds)
+	}
+	switch cfg.LockAction {
+	case "none", "pause", "stop", "cancel":
+	default:
+		return fmt.Errorf("invalid LOCK_ACTION: %q (allowed: none,pause,stop,cancel)", cfg.LockAction)
+	}
+	if cfg.CachePurgeInterval <= 0 {
+		return fmt.Errorf("invalid CACHE_PURGE_INTERVAL: %d (must be > 0)", cfg.CachePurgeInterval)
+	}
+	if cfg.CacheEncryption == cachecrypt.ModePassphrase && cfg.CachePassphrase == "" {
+		return fmt.Errorf("CACHE_ENCRYPTION=passphrase requires CACHE_PASSPHRASE")
+	}
+	if _, err := midi.ParseMap(cfg.MIDIMap); err != nil {
+		return fmt.Errorf("invalid MIDI_MAP %q: %w", cfg.MIDIMap, err)
+	}
+	if _, err := schedule.Parse(cfg.Schedule); err != nil {
+		return fmt.Errorf("invalid SCHEDULE: %w", err)
+	}
+	if cfg.HTTPAPI != "" {
+		if err := httpapi.CheckAddr(cfg.HTTPAPI); err != nil {
+			return fmt.Errorf("invalid HTTP_API %q: %w", cfg.HTTPAPI, err)
+		}
+	}
+	if cfg.GRPCAPI != "" {
+		if err := httpapi.CheckAddr(cfg.GRPCAPI); err != nil {
+			return fmt.Errorf("invalid GRPC_API %q: %w", cfg.GRPCAPI, err)
+		}
+		if cfg.GRPCAPI == cfg.HTTPAPI {
+			return fmt.Errorf("GRPC_API and HTTP_API must use different addresses")
+		}
+	}
+	for _, cue := range soundFields(cfg) {
+		if sound.IsFile(*cue.spec) {
+			if _, err := os.Stat(*cue.spec); err != nil {
+				return fmt.Errorf("invalid %s: %w", cue.key, err)
+			}
+		}
+	}
+	if _, err := CACertPool(cfg); err != nil {
+		return err
+	}
 	return nil
 }
 
+// CACertPool returns the system root certificates plus those in
+// CA_CERT_FILE, or nil when CA_CERT_FILE is empty.
+func CACertPool(cfg *Config) (*x509.CertPool, error) {
+	if cfg.CACertFile == "" {
+		return nil, nil
+	}
+	pem, err := os.ReadFile(cfg.CACertFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA_CERT_FILE: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("invalid CA_CERT_FILE %q: no PEM certificates found", cfg.CACertFile)
+	}
+	return pool, nil
+}
+
 // InitCacheDir validates/creates the configured cache directory.
 // It mutates cfg.CacheDir to an absolute path or clears it on failure.
 func InitCacheDir(cfg *Config) {
@@ -209,44 +626,79 @@ func InitCacheDir(cfg *Config) {
 	}
 	abs, err := filepath.Abs(cfg.CacheDir)
 	if err != nil {
-		fmt.Printf("[main] cache-dir path invalid '%s': %v. Falling back to cwd.\n", cfg.CacheDir, err)
+		fmt.Printf("[main] %s\n", i18n.Sprintf("cache-dir path invalid '%s': %v. Falling back to the data directory.", cfg.CacheDir, err))
 		cfg.CacheDir = ""
 		return
 	}
 	info, err := os.Stat(abs)
 	if err == nil {
 		if !info.IsDir() {
-			fmt.Printf("[main] cache-dir '%s' exists but is not a directory. Falling back to cwd.\n", abs)
+			fmt.Printf("[main] %s\n", i18n.Sprintf("cache-dir '%s' exists but is not a directory. Falling back to the data directory.", abs))
 			cfg.CacheDir = ""
 			return
 		}
 		cfg.CacheDir = abs
-		fmt.Printf("[main] using existing cache-dir: %s\n", cfg.CacheDir)
+		fmt.Printf("[main] %s\n", i18n.Sprintf("using existing cache-dir: %s", cfg.CacheDir))
 		return
 	}
 	if os.IsNotExist(err) {
 		if err := os.MkdirAll(abs, 0755); err != nil {
-			fmt.Printf("[main] cannot create cache-dir '%s': %v. Falling back to cwd.\n", abs, err)
+			fmt.Printf("[main] %s\n", i18n.Sprintf("cannot create cache-dir '%s': %v. Falling back to the data directory.", abs, err))
 			cfg.CacheDir = ""
 			return
 		}
 		cfg.CacheDir = abs
-		fmt.Printf("[main] created and using cache-dir: %s\n", cfg.CacheDir)
+		fmt.Printf("[main] %s\n", i18n.Sprintf("created and using cache-dir: %s", cfg.CacheDir))
 		return
 	}
-	fmt.Printf("[main] cannot access cache-dir '%s': %v. Falling back to cwd.\n", abs, err)
+	fmt.Printf("[main] %s\n", i18n.Sprintf("cannot access cache-dir '%s': %v. Falling back to the data directory.", abs, err))
 	cfg.CacheDir = ""
 }
 
-// TempDir returns the directory to use for temporary files.
+// TempDir returns the directory to use for temporary files: CACHE_DIR, else
+// the data directory SelectDefault chose, else the working directory.
 func TempDir(cfg *Config) string {
 	if cfg.CacheDir != "" {
 		return cfg.CacheDir
 	}
+	if dataDir != "" {
+		return dataDir
+	}
 	cwd, _ := os.Getwd()
 	return cwd
 }
 
+// LogPath returns the absolute path of LOG_FILE, resolved against the cache
+// directory, or "" when logging is off.
+func LogPath(cfg *Config) string {
+	if cfg.LogFile == "" {
+		return ""
+	}
+	if filepath.IsAbs(cfg.LogFile) {
+		return cfg.LogFile
+	}
+	return filepath.Join(TempDir(cfg), cfg.LogFile)
+}
+
+// LogRotation returns how the log at LogPath is rotated.
+func LogRotation(cfg *Config) applog.Rotation {
+	return applog.Rotation{
+		MaxSize: int64(cfg.LogMaxSizeMB) << 20,
+		Days:    cfg.LogRotateDays,
+		Backups: cfg.LogMaxBackups,
+	}
+}
+
+// MachineName identifies this computer in cache file names and history:
+// MACHINE_ID when set, otherwise the host name.
+func MachineName(cfg *Config) string {
+	if cfg.MachineID != "" {
+		return cfg.MachineID
+	}
+	host, _ := os.Hostname()
+	return host
+}
+
 // ContainerExt maps container names to file extensions (lowercase).
 func ContainerExt(container string) string {
 	c := strings.ToLower(container)
@@ -57,6 +57,51 @@ func TestSaveDefaultAndLoadRoundTrip(t *testing.T) {
 	}
 }
 
+func TestLoadResolvesRelativePathsAgainstConfigDir(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"CACHE_DIR":"cache"}`), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if want := filepath.Join(dir, "cache"); cfg.CacheDir != want {
+		t.Fatalf("CacheDir = %q, want %q", cfg.CacheDir, want)
+	}
+}
+
+func TestResolvePathsKeepsAbsoluteAndEmptyValues(t *testing.T) {
+	abs := filepath.Join(t.TempDir(), "cache")
+	cfg := DefaultConfig()
+	cfg.CacheDir = abs
+	ResolvePaths(&cfg, "/elsewhere")
+	if cfg.CacheDir != abs {
+		t.Fatalf("CacheDir = %q, want %q", cfg.CacheDir, abs)
+	}
+
+	cfg.CacheDir = ""
+	ResolvePaths(&cfg, "/elsewhere")
+	if cfg.CacheDir != "" {
+		t.Fatalf("CacheDir = %q, want empty", cfg.CacheDir)
+	}
+}
+
+func TestResolvePathsOnlyTouchesSoundFiles(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SoundStart = "SystemAsterisk"
+	cfg.SoundStop = "stop.wav"
+	ResolvePaths(&cfg, "/elsewhere")
+	if cfg.SoundStart != "SystemAsterisk" {
+		t.Fatalf("SoundStart = %q, want the alias unchanged", cfg.SoundStart)
+	}
+	if want := filepath.Join("/elsewhere", "stop.wav"); cfg.SoundStop != want {
+		t.Fatalf("SoundStop = %q, want %q", cfg.SoundStop, want)
+	}
+}
+
 func TestValidateAcceptsCaseInsensitiveKnownCodecAndContainer(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.CODECS = "MP3"
@@ -79,6 +124,28 @@ func TestValidateRejectsInvalidValues(t *testing.T) {
 		{name: "bitrate", mutate: func(c *Config) { c.BIT_RATE = 0 }, wantErr: "invalid BIT_RATE"},
 		{name: "codec", mutate: func(c *Config) { c.CODECS = "bad-codec" }, wantErr: "invalid CODECS"},
 		{name: "container", mutate: func(c *Config) { c.CONTAINER = "bad-container" }, wantErr: "invalid CONTAINER"},
+		{name: "start key", mutate: func(c *Config) { c.StartKey = "ctrl+alt+nope" }, wantErr: "invalid START_KEY"},
+		{name: "modifier typo", mutate: func(c *Config) { c.PauseKey = "ctlr+s" }, wantErr: "invalid PAUSE_KEY"},
+		{name: "duplicate key", mutate: func(c *Config) { c.CancelKey = "Ctrl+Alt+Q" }, wantErr: "duplicate hotkey"},
+		{name: "ptt without hook", mutate: func(c *Config) { c.PTT, c.HotKeyHook = true, false }, wantErr: "invalid PTT"},
+		{name: "segment length", mutate: func(c *Config) { c.RecordSegmentSeconds = -1 }, wantErr: "invalid RECORD_SEGMENT_SECONDS"},
+		{name: "ui lang", mutate: func(c *Config) { c.UILang = "klingon" }, wantErr: "invalid UI_LANG"},
+		{name: "source", mutate: func(c *Config) { c.Source = "speakers" }, wantErr: "invalid SOURCE"},
+		{name: "tray theme", mutate: func(c *Config) { c.TrayTheme = "blue" }, wantErr: "invalid TRAY_THEME"},
+		{name: "ffmpeg threads", mutate: func(c *Config) { c.FFMPEG_THREADS = -1 }, wantErr: "invalid FFMPEG_THREADS"},
+		{name: "opus compression level", mutate: func(c *Config) { c.CompressionLevel = 11 }, wantErr: "invalid COMPRESSION_LEVEL"},
+		{name: "opus vbr", mutate: func(c *Config) { c.OpusVBR = "auto" }, wantErr: "invalid OPUS_VBR"},
+		{name: "log backups", mutate: func(c *Config) { c.LogMaxBackups = -1 }, wantErr: "invalid LOG_MAX_BACKUPS"},
+		{name: "band filters", mutate: func(c *Config) { c.AudioHighpass, c.AudioLowpass = 3000, 300 }, wantErr: "invalid AUDIO_HIGHPASS"},
+		{name: "audio speed", mutate: func(c *Config) { c.AudioSpeed = 3 }, wantErr: "invalid AUDIO_SPEED"},
+		{name: "output format", mutate: func(c *Config) { c.OutputFormat = "docx" }, wantErr: "invalid OUTPUT_FORMAT"},
+		{name: "output template", mutate: func(c *Config) { c.OutputTemplate = "{dir}/{stem}.txt" }, wantErr: "invalid OUTPUT_TEMPLATE"},
+		{name: "duration range", mutate: func(c *Config) { c.FileMinSeconds, c.FileMaxSeconds = 600, 60 }, wantErr: "invalid FILE_MAX_SECONDS"},
+		{name: "missing sound", mutate: func(c *Config) { c.SoundStart = filepath.Join(os.TempDir(), "no-such-cue.wav") }, wantErr: "invalid SOUND_START"},
+		{name: "requests per minute", mutate: func(c *Config) { c.RequestsPerMinute = -1 }, wantErr: "invalid REQUESTS_PER_MINUTE"},
+		{name: "requests burst", mutate: func(c *Config) { c.RequestsBurst = 0 }, wantErr: "invalid REQUESTS_BURST"},
+		{name: "missing ca cert", mutate: func(c *Config) { c.CACertFile = filepath.Join(os.TempDir(), "no-such-ca.pem") }, wantErr: "invalid CA_CERT_FILE"},
+		{name: "ca cert not pem", mutate: func(c *Config) { c.CACertFile = "config_test.go" }, wantErr: "no PEM certificates"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,71 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// PortableMarker is the file that, next to the program, keeps all of STT's
+// files in the working directory instead of the per-user data directory.
+const PortableMarker = "portable"
+
+// dataDir holds the cache, log and history when CACHE_DIR is empty; "" means
+// the working directory.
+var dataDir string
+
+// SetDataDir makes dir the place for files without a configured location.
+func SetDataDir(dir string) {
+	dataDir = dir
+}
+
+// UserDir returns the per-user data directory, %APPDATA%\stt on Windows, or
+// "" when the user has no configuration directory.
+func UserDir() string {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(base, "stt")
+}
+
+// Portable reports whether PortableMarker sits next to the program.
+func Portable() bool {
+	exe, err := os.Executable()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(filepath.Dir(exe), PortableMarker))
+	return err == nil
+}
+
+// SelectDefault returns the config file to use when -config is not given.
+// In portable mode, when the working directory already has a config.json, or
+// when there is no usable per-user directory, that is config.json in the
+// working directory, which also keeps the other files as before. Otherwise
+// it is config.json in UserDir, which then becomes the data directory too.
+func SelectDefault(portable bool) string {
+	const name = "config.json"
+	if portable || Portable() {
+		return name
+	}
+	if _, err := os.Stat(name); err == nil {
+		return name
+	}
+	dir := UserDir()
+	if dir == "" || os.MkdirAll(dir, 0755) != nil {
+		return name
+	}
+	SetDataDir(dir)
+	return filepath.Join(dir, name)
+}
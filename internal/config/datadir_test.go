@@ -0,0 +1,76 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useUserDir points UserDir at a temporary directory and restores the data
+// directory afterwards.
+func useUserDir(t *testing.T) string {
+	t.Helper()
+	base := t.TempDir()
+	t.Setenv("APPDATA", base)
+	t.Setenv("XDG_CONFIG_HOME", base)
+	t.Cleanup(func() { SetDataDir("") })
+	return filepath.Join(base, "stt")
+}
+
+func TestSelectDefaultUsesUserDir(t *testing.T) {
+	dir := useUserDir(t)
+	t.Chdir(t.TempDir())
+
+	if got, want := SelectDefault(false), filepath.Join(dir, "config.json"); got != want {
+		t.Fatalf("SelectDefault = %q, want %q", got, want)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Fatalf("user dir not created: %v", err)
+	}
+	if got := TempDir(&Config{}); got != dir {
+		t.Fatalf("TempDir = %q, want the user dir %q", got, dir)
+	}
+	if got := TempDir(&Config{CacheDir: "/cache"}); got != "/cache" {
+		t.Fatalf("TempDir with CACHE_DIR = %q, want /cache", got)
+	}
+}
+
+func TestSelectDefaultKeepsWorkingDirectoryConfig(t *testing.T) {
+	useUserDir(t)
+	wd := t.TempDir()
+	t.Chdir(wd)
+	if err := os.WriteFile("config.json", []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := SelectDefault(false); got != "config.json" {
+		t.Fatalf("SelectDefault = %q, want config.json", got)
+	}
+	if got := TempDir(&Config{}); got != wd {
+		t.Fatalf("TempDir = %q, want the working directory %q", got, wd)
+	}
+}
+
+func TestSelectDefaultPortable(t *testing.T) {
+	dir := useUserDir(t)
+	t.Chdir(t.TempDir())
+
+	if got := SelectDefault(true); got != "config.json" {
+		t.Fatalf("SelectDefault(true) = %q, want config.json", got)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("portable mode created the user dir: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadDotEnvSetsUnsetVariablesOnly(t *testing.T) {
+	t.Setenv("STT_TEST_KEEP", "from-env")
+	os.Unsetenv("STT_TEST_TOKEN")
+	os.Unsetenv("STT_TEST_QUOTED")
+	os.Unsetenv("STT_TEST_COMMENT")
+	t.Cleanup(func() {
+		os.Unsetenv("STT_TEST_TOKEN")
+		os.Unsetenv("STT_TEST_QUOTED")
+		os.Unsetenv("STT_TEST_COMMENT")
+	})
+
+	path := filepath.Join(t.TempDir(), ".env")
+	content := strings.Join([]string{
+		"# comment",
+		"",
+		"STT_TEST_TOKEN=sk-file",
+		"export STT_TEST_QUOTED=\"a b\\tc\"",
+		"STT_TEST_COMMENT=value # trailing",
+		"STT_TEST_KEEP=from-file",
+	}, "\n")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if err := LoadDotEnv(path); err != nil {
+		t.Fatalf("LoadDotEnv failed: %v", err)
+	}
+	if got := os.Getenv("STT_TEST_TOKEN"); got != "sk-file" {
+		t.Fatalf("STT_TEST_TOKEN = %q, want sk-file", got)
+	}
+	if got := os.Getenv("STT_TEST_QUOTED"); got != "a b\tc" {
+		t.Fatalf("STT_TEST_QUOTED = %q, want unquoted value", got)
+	}
+	if got := os.Getenv("STT_TEST_COMMENT"); got != "value" {
+		t.Fatalf("STT_TEST_COMMENT = %q, want value", got)
+	}
+	if got := os.Getenv("STT_TEST_KEEP"); got != "from-env" {
+		t.Fatalf("STT_TEST_KEEP = %q, want existing env value", got)
+	}
+}
+
+func TestLoadDotEnvRejectsMalformedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("NOT_A_PAIR\n"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := LoadDotEnv(path); err == nil {
+		t.Fatalf("LoadDotEnv succeeded, want error")
+	}
+}
+
+func TestApplyEnvOverridesTypedFields(t *testing.T) {
+	t.Setenv("STT_TOKEN", "sk-env")
+	t.Setenv("STT_MAX_RETRY", "7")
+	t.Setenv("STT_RETRY_BASE_DELAY", "1.5")
+	t.Setenv("STT_VERIFY_SSL", "no")
+
+	cfg := DefaultConfig()
+	if err := ApplyEnv(&cfg); err != nil {
+		t.Fatalf("ApplyEnv failed: %v", err)
+	}
+	if cfg.Token != "sk-env" || cfg.MaxRetry != 7 || cfg.RetryBaseDelay != 1.5 || cfg.VerifySSL {
+		t.Fatalf("env overrides not applied: %#v", cfg)
+	}
+}
+
+func TestApplyEnvReportsInvalidValue(t *testing.T) {
+	t.Setenv("STT_CHANNELS", "two")
+	cfg := DefaultConfig()
+	err := ApplyEnv(&cfg)
+	if err == nil || !strings.Contains(err.Error(), "STT_CHANNELS") {
+		t.Fatalf("ApplyEnv error = %v, want error naming STT_CHANNELS", err)
+	}
+}
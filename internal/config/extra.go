@@ -0,0 +1,69 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ExtraJSON holds the ExtraConfig request fields as compact JSON text. In a
+// config file it may be written as a native JSON object or, for compatibility,
+// as an escaped JSON string; it is saved back as an object.
+type ExtraJSON string
+
+// UnmarshalJSON accepts an object, a string containing JSON, or null.
+func (e *ExtraJSON) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*e = ""
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*e = ExtraJSON(s)
+	case len(data) > 0 && data[0] == '{':
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, data); err != nil {
+			return err
+		}
+		*e = ExtraJSON(buf.String())
+	default:
+		return errors.New("ExtraConfig must be a JSON object or a string containing one")
+	}
+	return nil
+}
+
+// MarshalJSON writes a valid object natively and anything else as a string.
+func (e ExtraJSON) MarshalJSON() ([]byte, error) {
+	s := strings.TrimSpace(string(e))
+	if strings.HasPrefix(s, "{") && json.Valid([]byte(s)) {
+		return []byte(s), nil
+	}
+	return json.Marshal(string(e))
+}
+
+// Object decodes the fields. An empty value yields a nil map.
+func (e ExtraJSON) Object() (map[string]interface{}, error) {
+	if strings.TrimSpace(string(e)) == "" {
+		return nil, nil
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(e), &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
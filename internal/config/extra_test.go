@@ -0,0 +1,72 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadAcceptsExtraConfigObjectAndString(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]string{
+		"object.json": `{"ExtraConfig": {"temperature": 0, "prompt": null}}`,
+		"string.json": `{"ExtraConfig": "{\"temperature\":0,\"prompt\":null}"}`,
+	}
+	for name, content := range cases {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s) failed: %v", name, err)
+		}
+		extra, err := cfg.ExtraConfig.Object()
+		if err != nil {
+			t.Fatalf("Object(%s) failed: %v", name, err)
+		}
+		if v, ok := extra["prompt"]; !ok || v != nil || extra["temperature"] != float64(0) {
+			t.Fatalf("%s ExtraConfig = %#v", name, extra)
+		}
+	}
+}
+
+func TestExtraConfigMarshalsAsObject(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ExtraConfig = `{"temperature":0}`
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(b), `"ExtraConfig":{"temperature":0}`) {
+		t.Fatalf("ExtraConfig not written as object: %s", b)
+	}
+
+	cfg.ExtraConfig = ""
+	b, _ = json.Marshal(cfg)
+	if !strings.Contains(string(b), `"ExtraConfig":""`) {
+		t.Fatalf("empty ExtraConfig = %s, want empty string", b)
+	}
+}
+
+func TestValidateRejectsMalformedExtraConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ExtraConfig = `{"temperature":`
+	if err := Validate(&cfg); err == nil || !strings.Contains(err.Error(), "invalid ExtraConfig") {
+		t.Fatalf("Validate error = %v, want invalid ExtraConfig", err)
+	}
+}
@@ -28,14 +28,32 @@ type FlagValues struct {
 	ModelSet                     bool
 	Language                     string
 	LanguageSet                  bool
+	Provider                     string
+	ProviderSet                  bool
+	Profile                      string
+	ProfileSet                   bool
+	MachineID                    string
+	MachineIDSet                 bool
 	Prompt                       string
 	PromptSet                    bool
 	TEXTPath                     string
 	TEXTPathSet                  bool
+	PostCommand                  string
+	PostCommandSet               bool
 	ExtraConfig                  string
 	ExtraConfigSet               bool
 	Channels                     int
 	ChannelsSet                  bool
+	InputDevice                  string
+	InputDeviceSet               bool
+	Source                       string
+	SourceSet                    bool
+	MaxRecordSeconds             int
+	MaxRecordSecondsSet          bool
+	RecordSegmentSeconds         int
+	RecordSegmentSecondsSet      bool
+	LockAction                   string
+	LockActionSet                bool
 	SAMPLING_RATE                int
 	SAMPLING_RATESet             bool
 	SAMPLING_RATE_DEPTH          int
@@ -56,30 +74,178 @@ type FlagValues struct {
 	EnableHTTP2Set               bool
 	VerifySSL                    bool
 	VerifySSLSet                 bool
+	CACertFile                   string
+	CACertFileSet                bool
+	StartupCheck                 bool
+	StartupCheckSet              bool
+	UpdateCheck                  bool
+	UpdateCheckSet               bool
+	Watchdog                     bool
+	WatchdogSet                  bool
+	Onboarding                   bool
+	OnboardingSet                bool
+	HTTPAPI                      string
+	HTTPAPISet                   bool
+	HTTPAPIToken                 string
+	HTTPAPITokenSet              bool
+	GRPCAPI                      string
+	GRPCAPISet                   bool
+	GRPCAPIToken                 string
+	GRPCAPITokenSet              bool
 	HotKeyHook                   bool
 	HotKeyHookSet                bool
+	PTT                          bool
+	PTTSet                       bool
 	StartKey                     string
 	StartKeySet                  bool
 	PauseKey                     string
 	PauseKeySet                  bool
 	CancelKey                    string
 	CancelKeySet                 bool
+	MIDIInput                    string
+	MIDIInputSet                 bool
+	MIDIMap                      string
+	MIDIMapSet                   bool
+	Schedule                     string
+	ScheduleSet                  bool
+	ClipboardWatch               bool
+	ClipboardWatchSet            bool
 	CacheDir                     string
 	CacheDirSet                  bool
 	KeepCache                    bool
 	KeepCacheSet                 bool
+	KeepWav                      bool
+	KeepWavSet                   bool
+	KeepConverted                bool
+	KeepConvertedSet             bool
+	CacheLayout                  string
+	CacheLayoutSet               bool
+	CacheName                    string
+	CacheNameSet                 bool
+	CacheArchiveDays             int
+	CacheArchiveDaysSet          bool
+	CacheMaxAgeDays              int
+	CacheMaxAgeDaysSet           bool
+	CacheMaxSizeMB               int
+	CacheMaxSizeMBSet            bool
+	CachePurgeInterval           int
+	CachePurgeIntervalSet        bool
+	History                      bool
+	HistorySet                   bool
+	CacheEncryption              string
+	CacheEncryptionSet           bool
+	CachePassphrase              string
+	CachePassphraseSet           bool
+	RetryQueue                   bool
+	RetryQueueSet                bool
+	QueueRetryInterval           int
+	QueueRetryIntervalSet        bool
+	OfflineFirst                 bool
+	OfflineFirstSet              bool
 	Notification                 bool
 	NotificationSet              bool
+	Tray                         bool
+	TraySet                      bool
+	TrayTheme                    string
+	TrayThemeSet                 bool
+	VUMeter                      bool
+	VUMeterSet                   bool
+	RecordingTimer               bool
+	RecordingTimerSet            bool
+	SoundMute                    bool
+	SoundMuteSet                 bool
+	SoundStart                   string
+	SoundStartSet                bool
+	SoundStop                    string
+	SoundStopSet                 bool
+	SoundPasteSuccess            string
+	SoundPasteSuccessSet         bool
+	SoundUploadFailed            string
+	SoundUploadFailedSet         bool
+	SoundError                   string
+	SoundErrorSet                bool
 	RequestFailedNotification    bool
 	RequestFailedNotificationSet bool
+	ProgressNotification         bool
+	ProgressNotificationSet      bool
+	TimingNotification           bool
+	TimingNotificationSet        bool
+	QuietMode                    bool
+	QuietModeSet                 bool
+	LogFile                      string
+	LogFileSet                   bool
+	LogMaxSizeMB                 int
+	LogMaxSizeMBSet              bool
+	LogRotateDays                int
+	LogRotateDaysSet             bool
+	LogMaxBackups                int
+	LogMaxBackupsSet             bool
+	FFMPEG_PATH                  string
+	FFMPEG_PATHSet               bool
 	FFMPEG_DEBUG                 bool
 	FFMPEG_DEBUGSet              bool
+	FFMPEG_TIMEOUT               int
+	FFMPEG_TIMEOUTSet            bool
+	FFMPEG_INPUT_ARGS            string
+	FFMPEG_INPUT_ARGSSet         bool
+	FFMPEG_EXTRA_ARGS            string
+	FFMPEG_EXTRA_ARGSSet         bool
+	FFMPEG_THREADS               int
+	FFMPEG_THREADSSet            bool
+	CompressionLevel             int
+	CompressionLevelSet          bool
+	OpusVBR                      string
+	OpusVBRSet                   bool
+	AudioHighpass                int
+	AudioHighpassSet             bool
+	AudioLowpass                 int
+	AudioLowpassSet              bool
+	AudioDenoise                 bool
+	AudioDenoiseSet              bool
+	AudioSpeed                   float64
+	AudioSpeedSet                bool
+	FileChunkSeconds             int
+	FileChunkSecondsSet          bool
+	FileChunkOverlap             int
+	FileChunkOverlapSet          bool
+	MaxUploadMB                  int
+	MaxUploadMBSet               bool
+	AudioTrack                   int
+	AudioTrackSet                bool
+	ConvertWorkers               int
+	ConvertWorkersSet            bool
+	OutputFormat                 string
+	OutputFormatSet              bool
+	OutputTemplate               string
+	OutputTemplateSet            bool
+	ProgressBar                  bool
+	ProgressBarSet               bool
+	SkipExisting                 bool
+	SkipExistingSet              bool
+	Recursive                    bool
+	RecursiveSet                 bool
+	IncludeExtensions            string
+	IncludeExtensionsSet         bool
+	ExcludeExtensions            string
+	ExcludeExtensionsSet         bool
+	FileMinSeconds               int
+	FileMinSecondsSet            bool
+	FileMaxSeconds               int
+	FileMaxSecondsSet            bool
+	MaxConcurrentUploads         int
+	MaxConcurrentUploadsSet      bool
+	RequestsPerMinute            int
+	RequestsPerMinuteSet         bool
+	RequestsBurst                int
+	RequestsBurstSet             bool
 	RECORD_DEBUG                 bool
 	RECORD_DEBUGSet              bool
 	HOTKEY_DEBUG                 bool
 	HOTKEY_DEBUGSet              bool
 	UPLOAD_DEBUG                 bool
 	UPLOAD_DEBUGSet              bool
+	UILang                       string
+	UILangSet                    bool
 
 	OutputPath    string
 	OutputPathSet bool
@@ -204,13 +370,22 @@ func BindFlags(fs *flag.FlagSet) *FlagValues {
 	fs.Var(&stringFlag{&fv.Token, &fv.TokenSet}, "token", "Authorization token")
 	fs.Var(&stringFlag{&fv.Model, &fv.ModelSet}, "model", "model")
 	fs.Var(&stringFlag{&fv.Language, &fv.LanguageSet}, "language", "language")
+	fs.Var(&stringFlag{&fv.Provider, &fv.ProviderSet}, "provider", "ASR provider convention for LANGUAGE=auto")
+	fs.Var(&stringFlag{&fv.Profile, &fv.ProfileSet}, "profile", "Name of this configuration, available as {profile} in CACHE_NAME")
+	fs.Var(&stringFlag{&fv.MachineID, &fv.MachineIDSet}, "machine-id", "Identifier of this computer, available as {machine} in CACHE_NAME and recorded in history (default: host name)")
 	fs.Var(&stringFlag{&fv.Prompt, &fv.PromptSet}, "prompt", "prompt")
 	fs.Var(&stringFlag{&fv.TEXTPath, &fv.TEXTPathSet}, "text-path", "JSON path to extract text")
+	fs.Var(&stringFlag{&fv.PostCommand, &fv.PostCommandSet}, "post-command", "command run after each transcription with the text on stdin and STT_* metadata in the environment")
 	fs.Var(&stringFlag{&fv.ExtraConfig, &fv.ExtraConfigSet}, "extra-config", "extra JSON config to merge into request payload")
 
 	fs.Var(&stringFlag{&fv.CODECS, &fv.CODECSSet}, "codecs", "audio codec (e.g. OPUS, AAC, MP3, FLAC)")
 	fs.Var(&stringFlag{&fv.CONTAINER, &fv.CONTAINERSet}, "container", "audio container (e.g. OGG, MP3, FLAC, M4A)")
 	fs.Var(&intFlag{&fv.Channels, &fv.ChannelsSet}, "channels", "channels (int)")
+	fs.Var(&stringFlag{&fv.InputDevice, &fv.InputDeviceSet}, "input-device", "Index, name or part of the name of the microphone to record from; empty uses the system default (see stt devices)")
+	fs.Var(&stringFlag{&fv.Source, &fv.SourceSet}, "source", "Record from: microphone, or loopback for what the PC plays (WASAPI loopback of the default output device)")
+	fs.Var(&intFlag{&fv.MaxRecordSeconds, &fv.MaxRecordSecondsSet}, "max-record-seconds", "Stop recording automatically after this many seconds (0 = no limit)")
+	fs.Var(&intFlag{&fv.RecordSegmentSeconds, &fv.RecordSegmentSecondsSet}, "record-segment-seconds", "Transcribe recordings in segments of about this many seconds while recording continues (0 = off)")
+	fs.Var(&stringFlag{&fv.LockAction, &fv.LockActionSet}, "lock-action", "what to do with an active recording when the workstation locks: none, pause, stop or cancel")
 	fs.Var(&intFlag{&fv.SAMPLING_RATE, &fv.SAMPLING_RATESet}, "sampling-rate", "sampling rate (Hz)")
 	// deprecated alias
 	fs.Var(&intFlag{&fv.SAMPLING_RATE, &fv.SAMPLING_RATESet}, "rate", "deprecated: rate (Hz) — use -sampling-rate")
@@ -222,23 +397,98 @@ func BindFlags(fs *flag.FlagSet) *FlagValues {
 	fs.Var(&floatFlag{&fv.RetryBaseDelay, &fv.RetryBaseDelaySet}, "retry-base-delay", "retry base delay seconds (float)")
 	fs.Var(&boolFlag{&fv.EnableHTTP2, &fv.EnableHTTP2Set}, "enable-http2", "enable HTTP/2 (true/false)")
 	fs.Var(&boolFlag{&fv.VerifySSL, &fv.VerifySSLSet}, "verify-ssl", "verify TLS certificates (true/false)")
+	fs.Var(&stringFlag{&fv.CACertFile, &fv.CACertFileSet}, "ca-cert-file", "PEM file of extra root certificates to trust")
+	fs.Var(&boolFlag{&fv.StartupCheck, &fv.StartupCheckSet}, "startup-check", "probe the ASR endpoint at startup (true/false)")
+	fs.Var(&boolFlag{&fv.UpdateCheck, &fv.UpdateCheckSet}, "update-check", "check for a newer release at startup (true/false)")
+	fs.Var(&boolFlag{&fv.Watchdog, &fv.WatchdogSet}, "watchdog", "restart record mode automatically if it crashes (true/false)")
+	fs.Var(&boolFlag{&fv.Onboarding, &fv.OnboardingSet}, "onboarding", "show the first-run guide and test recording until one succeeds (true/false)")
+	fs.Var(&stringFlag{&fv.HTTPAPI, &fv.HTTPAPISet}, "http-api", "address of the local HTTP control API, e.g. 127.0.0.1:8765 (empty disables it)")
+	fs.Var(&stringFlag{&fv.HTTPAPIToken, &fv.HTTPAPITokenSet}, "http-api-token", "token required by the local HTTP API (empty generates one per start)")
+	fs.Var(&stringFlag{&fv.GRPCAPI, &fv.GRPCAPISet}, "grpc-api", "address of the local gRPC API, e.g. 127.0.0.1:8766 (empty disables it)")
+	fs.Var(&stringFlag{&fv.GRPCAPIToken, &fv.GRPCAPITokenSet}, "grpc-api-token", "token required by the local gRPC API (empty generates one per start)")
 
 	fs.Var(&stringFlag{&fv.StartKey, &fv.StartKeySet}, "start-key", "start/stop hotkey")
 	fs.Var(&stringFlag{&fv.PauseKey, &fv.PauseKeySet}, "pause-key", "pause/resume hotkey")
 	fs.Var(&stringFlag{&fv.CancelKey, &fv.CancelKeySet}, "cancel-key", "cancel hotkey")
+	fs.Var(&stringFlag{&fv.MIDIInput, &fv.MIDIInputSet}, "midi-input", "MIDI input device to take triggers from (part of its name; empty disables MIDI)")
+	fs.Var(&stringFlag{&fv.MIDIMap, &fv.MIDIMapSet}, "midi-map", "MIDI notes/controllers mapped to actions, e.g. 36=toggle,37=pause,cc64=toggle")
+	fs.Var(&stringFlag{&fv.Schedule, &fv.ScheduleSet}, "schedule", "scheduled transcription jobs, e.g. \"0 7 * * * D:\\Calls\\{yesterday}\" (separate several with ;)")
+	fs.Var(&boolFlag{&fv.ClipboardWatch, &fv.ClipboardWatchSet}, "clipboard-watch", "offer to transcribe audio/video files copied to the clipboard")
 	fs.Var(&boolFlag{&fv.HotKeyHook, &fv.HotKeyHookSet}, "hotkeyhook", "use low-level keyboard hook (true/false)")
+	fs.Var(&boolFlag{&fv.PTT, &fv.PTTSet}, "ptt", "push-to-talk: record only while the start hotkey is held, upload on release (true/false)")
 
 	fs.Var(&stringFlag{&fv.CacheDir, &fv.CacheDirSet}, "cache-dir", "cache directory")
 	fs.Var(&boolFlag{&fv.KeepCache, &fv.KeepCacheSet}, "keep-cache", "keep cache files (true/false)")
+	fs.Var(&boolFlag{&fv.KeepWav, &fv.KeepWavSet}, "keep-wav", "Keep the recorded WAV when KEEP_CACHE is on (true/false)")
+	fs.Var(&boolFlag{&fv.KeepConverted, &fv.KeepConvertedSet}, "keep-converted", "Keep the converted upload file when KEEP_CACHE is on (true/false)")
+	fs.Var(&stringFlag{&fv.CacheLayout, &fv.CacheLayoutSet}, "cache-layout", "Subdirectory layout for kept cache files, e.g. {yyyy}/{mm}/{dd}; empty keeps them flat")
+	fs.Var(&stringFlag{&fv.CacheName, &fv.CacheNameSet}, "cache-name", "File name template for kept cache files, e.g. {date}_{profile}_{lang}_{seq}")
+	fs.Var(&intFlag{&fv.CacheArchiveDays, &fv.CacheArchiveDaysSet}, "cache-archive-days", "Move kept cache files older than this many days into monthly zip archives (0 = off)")
+	fs.Var(&intFlag{&fv.CacheMaxAgeDays, &fv.CacheMaxAgeDaysSet}, "cache-max-age-days", "Delete kept cache files older than this many days (0 keeps them forever)")
+	fs.Var(&intFlag{&fv.CacheMaxSizeMB, &fv.CacheMaxSizeMBSet}, "cache-max-size-mb", "Delete the oldest kept cache files once the cache exceeds this many MB (0 = no limit)")
+	fs.Var(&intFlag{&fv.CachePurgeInterval, &fv.CachePurgeIntervalSet}, "cache-purge-interval", "Minutes between automatic cache purges")
+	fs.Var(&boolFlag{&fv.History, &fv.HistorySet}, "history", "record transcripts in the history database under cache-dir (true/false)")
+	fs.Var(&stringFlag{&fv.CacheEncryption, &fv.CacheEncryptionSet}, "cache-encryption", "Encrypt cached audio and transcripts: dpapi, passphrase, or empty to disable")
+	fs.Var(&stringFlag{&fv.CachePassphrase, &fv.CachePassphraseSet}, "cache-passphrase", "Passphrase for CACHE_ENCRYPTION=passphrase")
+	fs.Var(&boolFlag{&fv.RetryQueue, &fv.RetryQueueSet}, "retry-queue", "Queue recordings whose upload failed under <cache-dir>/pending and retry them in the background")
+	fs.Var(&intFlag{&fv.QueueRetryInterval, &fv.QueueRetryIntervalSet}, "queue-retry-interval", "Seconds between background retries of the pending queue")
+	fs.Var(&boolFlag{&fv.OfflineFirst, &fv.OfflineFirstSet}, "offline-first", "Persist every recording under <cache-dir>/pending before converting and uploading it")
 
 	fs.Var(&boolFlag{&fv.Notification, &fv.NotificationSet}, "notification", "enable notifications (true/false)")
+	fs.Var(&boolFlag{&fv.Tray, &fv.TraySet}, "tray", "Show a notification-area icon with a control menu in record mode (Windows)")
+	fs.Var(&stringFlag{&fv.TrayTheme, &fv.TrayThemeSet}, "tray-theme", "tray icon palette: auto, light, dark")
+	fs.Var(&boolFlag{&fv.VUMeter, &fv.VUMeterSet}, "vu-meter", "show a small live level meter and waveform window while recording (true/false)")
+	fs.Var(&boolFlag{&fv.RecordingTimer, &fv.RecordingTimerSet}, "recording-timer", "show a notification with the elapsed recording time while recording (true/false)")
+	fs.Var(&boolFlag{&fv.SoundMute, &fv.SoundMuteSet}, "sound-mute", "Silence all SOUND_* cues")
+	fs.Var(&stringFlag{&fv.SoundStart, &fv.SoundStartSet}, "sound-start", "Sound played when recording starts: a .wav path or a Windows sound alias such as SystemAsterisk")
+	fs.Var(&stringFlag{&fv.SoundStop, &fv.SoundStopSet}, "sound-stop", "Sound played when recording stops")
+	fs.Var(&stringFlag{&fv.SoundPasteSuccess, &fv.SoundPasteSuccessSet}, "sound-paste-success", "Sound played after the transcript is pasted")
+	fs.Var(&stringFlag{&fv.SoundUploadFailed, &fv.SoundUploadFailedSet}, "sound-upload-failed", "Sound played when an upload fails")
+	fs.Var(&stringFlag{&fv.SoundError, &fv.SoundErrorSet}, "sound-error", "sound for other failures and for upload failures without -sound-upload-failed: WAV path or system sound name")
 	fs.Var(&boolFlag{&fv.RequestFailedNotification, &fv.RequestFailedNotificationSet}, "request-failed-notification", "paste [request failed] after retry exhaustion in record mode (true/false)")
+	fs.Var(&boolFlag{&fv.ProgressNotification, &fv.ProgressNotificationSet}, "progress-notification", "show an updating progress notification for long conversions and uploads (true/false)")
+	fs.Var(&boolFlag{&fv.TimingNotification, &fv.TimingNotificationSet}, "timing-notification", "add how long recording, converting, uploading and pasting took to the paste notification (true/false)")
+	fs.Var(&boolFlag{&fv.QuietMode, &fv.QuietModeSet}, "quiet-mode", "suppress notifications and sounds while a full-screen app, presentation or quiet hours are active (true/false)")
+	fs.Var(&stringFlag{&fv.LogFile, &fv.LogFileSet}, "log-file", "copy console output to this file; relative to the cache directory, empty disables")
+	fs.Var(&intFlag{&fv.LogMaxSizeMB, &fv.LogMaxSizeMBSet}, "log-max-size-mb", "rotate the log file once it grows past this many MB; 0 disables")
+	fs.Var(&intFlag{&fv.LogRotateDays, &fv.LogRotateDaysSet}, "log-rotate-days", "start a new log file every this many days; 0 disables")
+	fs.Var(&intFlag{&fv.LogMaxBackups, &fv.LogMaxBackupsSet}, "log-max-backups", "number of rotated log files kept (stt.log.1, .2, ...); 0 deletes them")
+	fs.Var(&stringFlag{&fv.FFMPEG_PATH, &fv.FFMPEG_PATHSet}, "ffmpeg-path", "path to ffmpeg executable")
 	fs.Var(&boolFlag{&fv.FFMPEG_DEBUG, &fv.FFMPEG_DEBUGSet}, "ffmpeg-debug", "enable ffmpeg debug output (true/false)")
+	fs.Var(&intFlag{&fv.FFMPEG_TIMEOUT, &fv.FFMPEG_TIMEOUTSet}, "ffmpeg-timeout", "seconds an ffmpeg conversion may take before it is killed (0 = no limit)")
+	fs.Var(&stringFlag{&fv.FFMPEG_INPUT_ARGS, &fv.FFMPEG_INPUT_ARGSSet}, "ffmpeg-input-args", "extra ffmpeg arguments placed before -i")
+	fs.Var(&stringFlag{&fv.FFMPEG_EXTRA_ARGS, &fv.FFMPEG_EXTRA_ARGSSet}, "ffmpeg-extra-args", "extra ffmpeg output arguments placed before the output file")
+	fs.Var(&intFlag{&fv.FFMPEG_THREADS, &fv.FFMPEG_THREADSSet}, "ffmpeg-threads", "ffmpeg encoder threads; 0 lets ffmpeg choose")
+	fs.Var(&intFlag{&fv.CompressionLevel, &fv.CompressionLevelSet}, "compression-level", "opus (0-10) or flac (0-12) compression level; -1 keeps the encoder default")
+	fs.Var(&stringFlag{&fv.OpusVBR, &fv.OpusVBRSet}, "opus-vbr", "opus bitrate mode: on, off or constrained; empty keeps the encoder default")
+	fs.Var(&intFlag{&fv.AudioHighpass, &fv.AudioHighpassSet}, "highpass", "cut audio below this frequency in Hz before upload (0 = off)")
+	fs.Var(&intFlag{&fv.AudioLowpass, &fv.AudioLowpassSet}, "lowpass", "cut audio above this frequency in Hz before upload (0 = off)")
+	fs.Var(&boolFlag{&fv.AudioDenoise, &fv.AudioDenoiseSet}, "denoise", "reduce steady background noise with ffmpeg's afftdn filter before upload")
+	fs.Var(&floatFlag{&fv.AudioSpeed, &fv.AudioSpeedSet}, "speed", "speed up the audio by this factor (1-2) before upload; 1 = off")
+	fs.Var(&intFlag{&fv.FileChunkSeconds, &fv.FileChunkSecondsSet}, "chunk-seconds", "split files longer than this many seconds into chunks in file mode; 0 = off")
+	fs.Var(&intFlag{&fv.FileChunkOverlap, &fv.FileChunkOverlapSet}, "chunk-overlap", "seconds each chunk overlaps the next one")
+	fs.Var(&intFlag{&fv.MaxUploadMB, &fv.MaxUploadMBSet}, "max-upload-mb", "split converted files larger than this many MB into chunks in file mode; 0 = no limit")
+	fs.Var(&intFlag{&fv.AudioTrack, &fv.AudioTrackSet}, "audio-track", "audio track of the input to transcribe, counting from 1, e.g. of a video with several languages; 0 = ffmpeg's pick")
+	fs.Var(&intFlag{&fv.ConvertWorkers, &fv.ConvertWorkersSet}, "convert-workers", "number of files converted in parallel when transcribing a folder")
+	fs.Var(&stringFlag{&fv.OutputFormat, &fv.OutputFormatSet}, "output-format", "format of the transcripts written by -file: txt, json, srt, vtt or tsv")
+	fs.Var(&stringFlag{&fv.OutputTemplate, &fv.OutputTemplateSet}, "output-template", "path of each transcript when -file transcribes a folder or pattern, e.g. {dir}/out/{name}.{lang}.{ext}")
+	fs.Var(&boolFlag{&fv.ProgressBar, &fv.ProgressBarSet}, "progress-bar", "draw a progress bar when -file transcribes several files on a terminal; off prints one line per file")
+	fs.Var(&boolFlag{&fv.SkipExisting, &fv.SkipExistingSet}, "skip-existing", "skip files of a -file folder or pattern that already have a transcript or are transcribed in history, to resume an interrupted run")
+	fs.Var(&boolFlag{&fv.Recursive, &fv.RecursiveSet}, "recursive", "also transcribe the files in subfolders when -file is a folder or pattern")
+	fs.Var(&stringFlag{&fv.IncludeExtensions, &fv.IncludeExtensionsSet}, "include-ext", "extensions a -file folder or pattern picks, e.g. .m4a,.wav; empty picks the audio and video files of a folder and anything a pattern matches")
+	fs.Var(&stringFlag{&fv.ExcludeExtensions, &fv.ExcludeExtensionsSet}, "exclude-ext", "extensions a -file folder or pattern leaves out, e.g. .webm")
+	fs.Var(&intFlag{&fv.FileMinSeconds, &fv.FileMinSecondsSet}, "min-seconds", "leave files shorter than this many seconds out of a batch; 0 = no limit")
+	fs.Var(&intFlag{&fv.FileMaxSeconds, &fv.FileMaxSecondsSet}, "max-seconds", "leave files longer than this many seconds out of a batch; 0 = no limit")
+	fs.Var(&intFlag{&fv.MaxConcurrentUploads, &fv.MaxConcurrentUploadsSet}, "max-concurrent-uploads", "most uploads at once; batch runs and chunked files upload up to this many in parallel; 0 = no limit, with batches and chunks uploaded one at a time")
+	fs.Var(&intFlag{&fv.RequestsPerMinute, &fv.RequestsPerMinuteSet}, "requests-per-minute", "most upload requests started per minute, retries included; 0 = no limit")
+	fs.Var(&intFlag{&fv.RequestsBurst, &fv.RequestsBurstSet}, "requests-burst", "requests that may start at once after an idle spell under requests-per-minute; 1 = no burst")
 	fs.Var(&boolFlag{&fv.RECORD_DEBUG, &fv.RECORD_DEBUGSet}, "record-debug", "enable record debug output (true/false)")
 	fs.Var(&boolFlag{&fv.HOTKEY_DEBUG, &fv.HOTKEY_DEBUGSet}, "hotkey-debug", "enable hotkey debug output (true/false)")
 	fs.Var(&boolFlag{&fv.UPLOAD_DEBUG, &fv.UPLOAD_DEBUGSet}, "upload-debug", "enable upload debug output (true/false)")
 
-	fs.Var(&stringFlag{&fv.OutputPath, &fv.OutputPathSet}, "output", "output txt path for -file mode")
+	fs.Var(&stringFlag{&fv.UILang, &fv.UILangSet}, "ui-lang", "UI language for help, logs, and notifications (zh/en)")
+
+	fs.Var(&stringFlag{&fv.OutputPath, &fv.OutputPathSet}, "output", "output txt path for -file mode, or the folder for the transcripts of several files")
 
 	return fv
 }
@@ -257,14 +507,26 @@ func ApplyFlags(cfg *Config, fv *FlagValues) {
 	if fv.LanguageSet {
 		cfg.Language = fv.Language
 	}
+	if fv.ProviderSet {
+		cfg.Provider = fv.Provider
+	}
+	if fv.ProfileSet {
+		cfg.Profile = fv.Profile
+	}
+	if fv.MachineIDSet {
+		cfg.MachineID = fv.MachineID
+	}
 	if fv.PromptSet {
 		cfg.Prompt = fv.Prompt
 	}
 	if fv.TEXTPathSet {
 		cfg.TEXTPath = fv.TEXTPath
 	}
+	if fv.PostCommandSet {
+		cfg.PostCommand = fv.PostCommand
+	}
 	if fv.ExtraConfigSet {
-		cfg.ExtraConfig = fv.ExtraConfig
+		cfg.ExtraConfig = ExtraJSON(fv.ExtraConfig)
 	}
 
 	if fv.CODECSSet {
@@ -276,6 +538,21 @@ func ApplyFlags(cfg *Config, fv *FlagValues) {
 	if fv.ChannelsSet {
 		cfg.Channels = fv.Channels
 	}
+	if fv.InputDeviceSet {
+		cfg.InputDevice = fv.InputDevice
+	}
+	if fv.SourceSet {
+		cfg.Source = fv.Source
+	}
+	if fv.MaxRecordSecondsSet {
+		cfg.MaxRecordSeconds = fv.MaxRecordSeconds
+	}
+	if fv.RecordSegmentSecondsSet {
+		cfg.RecordSegmentSeconds = fv.RecordSegmentSeconds
+	}
+	if fv.LockActionSet {
+		cfg.LockAction = fv.LockAction
+	}
 	if fv.SAMPLING_RATESet {
 		cfg.SAMPLING_RATE = fv.SAMPLING_RATE
 	}
@@ -301,6 +578,33 @@ func ApplyFlags(cfg *Config, fv *FlagValues) {
 	if fv.VerifySSLSet {
 		cfg.VerifySSL = fv.VerifySSL
 	}
+	if fv.CACertFileSet {
+		cfg.CACertFile = fv.CACertFile
+	}
+	if fv.StartupCheckSet {
+		cfg.StartupCheck = fv.StartupCheck
+	}
+	if fv.UpdateCheckSet {
+		cfg.UpdateCheck = fv.UpdateCheck
+	}
+	if fv.WatchdogSet {
+		cfg.Watchdog = fv.Watchdog
+	}
+	if fv.OnboardingSet {
+		cfg.Onboarding = fv.Onboarding
+	}
+	if fv.HTTPAPISet {
+		cfg.HTTPAPI = fv.HTTPAPI
+	}
+	if fv.HTTPAPITokenSet {
+		cfg.HTTPAPIToken = fv.HTTPAPIToken
+	}
+	if fv.GRPCAPISet {
+		cfg.GRPCAPI = fv.GRPCAPI
+	}
+	if fv.GRPCAPITokenSet {
+		cfg.GRPCAPIToken = fv.GRPCAPIToken
+	}
 
 	if fv.StartKeySet {
 		cfg.StartKey = fv.StartKey
@@ -311,9 +615,24 @@ func ApplyFlags(cfg *Config, fv *FlagValues) {
 	if fv.CancelKeySet {
 		cfg.CancelKey = fv.CancelKey
 	}
+	if fv.MIDIInputSet {
+		cfg.MIDIInput = fv.MIDIInput
+	}
+	if fv.MIDIMapSet {
+		cfg.MIDIMap = fv.MIDIMap
+	}
+	if fv.ScheduleSet {
+		cfg.Schedule = fv.Schedule
+	}
+	if fv.ClipboardWatchSet {
+		cfg.ClipboardWatch = fv.ClipboardWatch
+	}
 	if fv.HotKeyHookSet {
 		cfg.HotKeyHook = fv.HotKeyHook
 	}
+	if fv.PTTSet {
+		cfg.PTT = fv.PTT
+	}
 
 	if fv.CacheDirSet {
 		cfg.CacheDir = fv.CacheDir
@@ -321,16 +640,193 @@ func ApplyFlags(cfg *Config, fv *FlagValues) {
 	if fv.KeepCacheSet {
 		cfg.KeepCache = fv.KeepCache
 	}
+	if fv.KeepWavSet {
+		cfg.KeepWav = fv.KeepWav
+	}
+	if fv.KeepConvertedSet {
+		cfg.KeepConverted = fv.KeepConverted
+	}
+	if fv.CacheLayoutSet {
+		cfg.CacheLayout = fv.CacheLayout
+	}
+	if fv.CacheNameSet {
+		cfg.CacheName = fv.CacheName
+	}
+	if fv.CacheArchiveDaysSet {
+		cfg.CacheArchiveDays = fv.CacheArchiveDays
+	}
+	if fv.CacheMaxAgeDaysSet {
+		cfg.CacheMaxAgeDays = fv.CacheMaxAgeDays
+	}
+	if fv.CacheMaxSizeMBSet {
+		cfg.CacheMaxSizeMB = fv.CacheMaxSizeMB
+	}
+	if fv.CachePurgeIntervalSet {
+		cfg.CachePurgeInterval = fv.CachePurgeInterval
+	}
+	if fv.HistorySet {
+		cfg.History = fv.History
+	}
+	if fv.CacheEncryptionSet {
+		cfg.CacheEncryption = fv.CacheEncryption
+	}
+	if fv.CachePassphraseSet {
+		cfg.CachePassphrase = fv.CachePassphrase
+	}
+	if fv.RetryQueueSet {
+		cfg.RetryQueue = fv.RetryQueue
+	}
+	if fv.QueueRetryIntervalSet {
+		cfg.QueueRetryInterval = fv.QueueRetryInterval
+	}
+	if fv.OfflineFirstSet {
+		cfg.OfflineFirst = fv.OfflineFirst
+	}
 
 	if fv.NotificationSet {
 		cfg.Notification = fv.Notification
 	}
+	if fv.TraySet {
+		cfg.Tray = fv.Tray
+	}
+	if fv.TrayThemeSet {
+		cfg.TrayTheme = fv.TrayTheme
+	}
+	if fv.VUMeterSet {
+		cfg.VUMeter = fv.VUMeter
+	}
+	if fv.RecordingTimerSet {
+		cfg.RecordingTimer = fv.RecordingTimer
+	}
+	if fv.SoundMuteSet {
+		cfg.SoundMute = fv.SoundMute
+	}
+	if fv.SoundStartSet {
+		cfg.SoundStart = fv.SoundStart
+	}
+	if fv.SoundStopSet {
+		cfg.SoundStop = fv.SoundStop
+	}
+	if fv.SoundPasteSuccessSet {
+		cfg.SoundPasteSuccess = fv.SoundPasteSuccess
+	}
+	if fv.SoundUploadFailedSet {
+		cfg.SoundUploadFailed = fv.SoundUploadFailed
+	}
+	if fv.SoundErrorSet {
+		cfg.SoundError = fv.SoundError
+	}
 	if fv.RequestFailedNotificationSet {
 		cfg.RequestFailedNotification = fv.RequestFailedNotification
 	}
+	if fv.ProgressNotificationSet {
+		cfg.ProgressNotification = fv.ProgressNotification
+	}
+	if fv.TimingNotificationSet {
+		cfg.TimingNotification = fv.TimingNotification
+	}
+	if fv.QuietModeSet {
+		cfg.QuietMode = fv.QuietMode
+	}
+	if fv.LogFileSet {
+		cfg.LogFile = fv.LogFile
+	}
+	if fv.LogMaxSizeMBSet {
+		cfg.LogMaxSizeMB = fv.LogMaxSizeMB
+	}
+	if fv.LogRotateDaysSet {
+		cfg.LogRotateDays = fv.LogRotateDays
+	}
+	if fv.LogMaxBackupsSet {
+		cfg.LogMaxBackups = fv.LogMaxBackups
+	}
+	if fv.FFMPEG_PATHSet {
+		cfg.FFMPEG_PATH = fv.FFMPEG_PATH
+	}
 	if fv.FFMPEG_DEBUGSet {
 		cfg.FFMPEG_DEBUG = fv.FFMPEG_DEBUG
 	}
+	if fv.FFMPEG_TIMEOUTSet {
+		cfg.FFMPEG_TIMEOUT = fv.FFMPEG_TIMEOUT
+	}
+	if fv.FFMPEG_INPUT_ARGSSet {
+		cfg.FFMPEG_INPUT_ARGS = fv.FFMPEG_INPUT_ARGS
+	}
+	if fv.FFMPEG_EXTRA_ARGSSet {
+		cfg.FFMPEG_EXTRA_ARGS = fv.FFMPEG_EXTRA_ARGS
+	}
+	if fv.FFMPEG_THREADSSet {
+		cfg.FFMPEG_THREADS = fv.FFMPEG_THREADS
+	}
+	if fv.CompressionLevelSet {
+		cfg.CompressionLevel = fv.CompressionLevel
+	}
+	if fv.OpusVBRSet {
+		cfg.OpusVBR = fv.OpusVBR
+	}
+	if fv.AudioHighpassSet {
+		cfg.AudioHighpass = fv.AudioHighpass
+	}
+	if fv.AudioLowpassSet {
+		cfg.AudioLowpass = fv.AudioLowpass
+	}
+	if fv.AudioDenoiseSet {
+		cfg.AudioDenoise = fv.AudioDenoise
+	}
+	if fv.AudioSpeedSet {
+		cfg.AudioSpeed = fv.AudioSpeed
+	}
+	if fv.FileChunkSecondsSet {
+		cfg.FileChunkSeconds = fv.FileChunkSeconds
+	}
+	if fv.FileChunkOverlapSet {
+		cfg.FileChunkOverlap = fv.FileChunkOverlap
+	}
+	if fv.MaxUploadMBSet {
+		cfg.MaxUploadMB = fv.MaxUploadMB
+	}
+	if fv.AudioTrackSet {
+		cfg.AudioTrack = fv.AudioTrack
+	}
+	if fv.ConvertWorkersSet {
+		cfg.ConvertWorkers = fv.ConvertWorkers
+	}
+	if fv.OutputFormatSet {
+		cfg.OutputFormat = fv.OutputFormat
+	}
+	if fv.OutputTemplateSet {
+		cfg.OutputTemplate = fv.OutputTemplate
+	}
+	if fv.ProgressBarSet {
+		cfg.ProgressBar = fv.ProgressBar
+	}
+	if fv.SkipExistingSet {
+		cfg.SkipExisting = fv.SkipExisting
+	}
+	if fv.RecursiveSet {
+		cfg.Recursive = fv.Recursive
+	}
+	if fv.IncludeExtensionsSet {
+		cfg.IncludeExtensions = fv.IncludeExtensions
+	}
+	if fv.ExcludeExtensionsSet {
+		cfg.ExcludeExtensions = fv.ExcludeExtensions
+	}
+	if fv.FileMinSecondsSet {
+		cfg.FileMinSeconds = fv.FileMinSeconds
+	}
+	if fv.FileMaxSecondsSet {
+		cfg.FileMaxSeconds = fv.FileMaxSeconds
+	}
+	if fv.MaxConcurrentUploadsSet {
+		cfg.MaxConcurrentUploads = fv.MaxConcurrentUploads
+	}
+	if fv.RequestsPerMinuteSet {
+		cfg.RequestsPerMinute = fv.RequestsPerMinute
+	}
+	if fv.RequestsBurstSet {
+		cfg.RequestsBurst = fv.RequestsBurst
+	}
 	if fv.RECORD_DEBUGSet {
 		cfg.RECORD_DEBUG = fv.RECORD_DEBUG
 	}
@@ -340,6 +836,10 @@ func ApplyFlags(cfg *Config, fv *FlagValues) {
 	if fv.UPLOAD_DEBUGSet {
 		cfg.UPLOAD_DEBUG = fv.UPLOAD_DEBUG
 	}
+
+	if fv.UILangSet {
+		cfg.UILang = fv.UILang
+	}
 }
 
 // AnySet reports whether any flag was explicitly set by the user.
@@ -348,10 +848,19 @@ func (fv *FlagValues) AnySet() bool {
 		fv.TokenSet ||
 		fv.ModelSet ||
 		fv.LanguageSet ||
+		fv.ProviderSet ||
+		fv.ProfileSet ||
+		fv.MachineIDSet ||
 		fv.PromptSet ||
 		fv.TEXTPathSet ||
+		fv.PostCommandSet ||
 		fv.ExtraConfigSet ||
 		fv.ChannelsSet ||
+		fv.InputDeviceSet ||
+		fv.SourceSet ||
+		fv.MaxRecordSecondsSet ||
+		fv.RecordSegmentSecondsSet ||
+		fv.LockActionSet ||
 		fv.SAMPLING_RATESet ||
 		fv.SAMPLING_RATE_DEPTHSet ||
 		fv.BIT_RATESet ||
@@ -362,17 +871,91 @@ func (fv *FlagValues) AnySet() bool {
 		fv.RetryBaseDelaySet ||
 		fv.EnableHTTP2Set ||
 		fv.VerifySSLSet ||
+		fv.CACertFileSet ||
+		fv.StartupCheckSet ||
+		fv.UpdateCheckSet ||
+		fv.WatchdogSet ||
+		fv.OnboardingSet ||
+		fv.HTTPAPISet ||
+		fv.HTTPAPITokenSet ||
+		fv.GRPCAPISet ||
+		fv.GRPCAPITokenSet ||
 		fv.HotKeyHookSet ||
+		fv.PTTSet ||
 		fv.StartKeySet ||
 		fv.PauseKeySet ||
 		fv.CancelKeySet ||
+		fv.MIDIInputSet ||
+		fv.MIDIMapSet ||
+		fv.ScheduleSet ||
+		fv.ClipboardWatchSet ||
 		fv.CacheDirSet ||
 		fv.KeepCacheSet ||
+		fv.KeepWavSet ||
+		fv.KeepConvertedSet ||
+		fv.CacheLayoutSet ||
+		fv.CacheNameSet ||
+		fv.CacheArchiveDaysSet ||
+		fv.CacheMaxAgeDaysSet ||
+		fv.CacheMaxSizeMBSet ||
+		fv.CachePurgeIntervalSet ||
+		fv.HistorySet ||
+		fv.CacheEncryptionSet ||
+		fv.CachePassphraseSet ||
+		fv.RetryQueueSet ||
+		fv.QueueRetryIntervalSet ||
+		fv.OfflineFirstSet ||
 		fv.NotificationSet ||
+		fv.TraySet ||
+		fv.TrayThemeSet ||
+		fv.VUMeterSet ||
+		fv.RecordingTimerSet ||
+		fv.SoundMuteSet ||
+		fv.SoundStartSet ||
+		fv.SoundStopSet ||
+		fv.SoundPasteSuccessSet ||
+		fv.SoundUploadFailedSet ||
+		fv.SoundErrorSet ||
 		fv.RequestFailedNotificationSet ||
+		fv.ProgressNotificationSet ||
+		fv.TimingNotificationSet ||
+		fv.QuietModeSet ||
+		fv.LogFileSet ||
+		fv.LogMaxSizeMBSet ||
+		fv.LogRotateDaysSet ||
+		fv.LogMaxBackupsSet ||
+		fv.FFMPEG_PATHSet ||
 		fv.FFMPEG_DEBUGSet ||
+		fv.FFMPEG_TIMEOUTSet ||
+		fv.FFMPEG_INPUT_ARGSSet ||
+		fv.FFMPEG_EXTRA_ARGSSet ||
+		fv.FFMPEG_THREADSSet ||
+		fv.CompressionLevelSet ||
+		fv.OpusVBRSet ||
+		fv.AudioHighpassSet ||
+		fv.AudioLowpassSet ||
+		fv.AudioDenoiseSet ||
+		fv.AudioSpeedSet ||
+		fv.FileChunkSecondsSet ||
+		fv.FileChunkOverlapSet ||
+		fv.MaxUploadMBSet ||
+		fv.AudioTrackSet ||
+		fv.ConvertWorkersSet ||
+		fv.OutputFormatSet ||
+		fv.OutputTemplateSet ||
+		fv.ProgressBarSet ||
+		fv.SkipExistingSet ||
+		fv.RecursiveSet ||
+		fv.IncludeExtensionsSet ||
+		fv.ExcludeExtensionsSet ||
+		fv.FileMinSecondsSet ||
+		fv.FileMaxSecondsSet ||
+		fv.MaxConcurrentUploadsSet ||
+		fv.RequestsPerMinuteSet ||
+		fv.RequestsBurstSet ||
 		fv.RECORD_DEBUGSet ||
 		fv.HOTKEY_DEBUGSet ||
 		fv.UPLOAD_DEBUGSet ||
+		fv.UILangSet ||
 		fv.OutputPathSet
 }
@@ -29,6 +29,7 @@ func TestBindFlagsApplyFlagsAndAnySet(t *testing.T) {
 		"-token", "secret",
 		"-model", "whisper",
 		"-language", "en",
+		"-provider", "groq",
 		"-prompt", "say words",
 		"-text-path", "data.text",
 		"-extra-config", `{"temperature":0}`,
@@ -43,6 +44,7 @@ func TestBindFlagsApplyFlagsAndAnySet(t *testing.T) {
 		"-retry-base-delay", "0.25",
 		"-enable-http2", "no",
 		"-verify-ssl", "0",
+		"-startup-check", "1",
 		"-start-key", "ctrl+a",
 		"-pause-key", "ctrl+b",
 		"-cancel-key", "ctrl+c",
@@ -51,10 +53,12 @@ func TestBindFlagsApplyFlagsAndAnySet(t *testing.T) {
 		"-keep-cache", "yes",
 		"-notification", "true",
 		"-request-failed-notification", "1",
+		"-ffmpeg-path", "C:/ffmpeg/bin/ffmpeg.exe",
 		"-ffmpeg-debug", "y",
 		"-record-debug", "true",
 		"-hotkey-debug", "false",
 		"-upload-debug", "true",
+		"-ui-lang", "en",
 		"-output", "out.txt",
 	}
 	if err := fs.Parse(args); err != nil {
@@ -70,13 +74,13 @@ func TestBindFlagsApplyFlagsAndAnySet(t *testing.T) {
 	if cfg.APIEndpoint != "https://example.test/asr" || cfg.Token != "secret" || cfg.Model != "whisper" {
 		t.Fatalf("string flags not applied: %#v", cfg)
 	}
-	if cfg.Language != "en" || cfg.Prompt != "say words" || cfg.TEXTPath != "data.text" || cfg.ExtraConfig != `{"temperature":0}` {
+	if cfg.Language != "en" || cfg.Provider != "groq" || cfg.Prompt != "say words" || cfg.TEXTPath != "data.text" || cfg.ExtraConfig != `{"temperature":0}` {
 		t.Fatalf("request flags not applied: %#v", cfg)
 	}
 	if cfg.CODECS != "mp3" || cfg.CONTAINER != "mp3" || cfg.Channels != 2 || cfg.SAMPLING_RATE != 48000 || cfg.SAMPLING_RATE_DEPTH != 24 || cfg.BIT_RATE != 192 {
 		t.Fatalf("audio flags not applied: %#v", cfg)
 	}
-	if cfg.RequestTimeout != 9 || cfg.MaxRetry != 5 || cfg.RetryBaseDelay != 0.25 || cfg.EnableHTTP2 || cfg.VerifySSL {
+	if cfg.RequestTimeout != 9 || cfg.MaxRetry != 5 || cfg.RetryBaseDelay != 0.25 || cfg.EnableHTTP2 || cfg.VerifySSL || !cfg.StartupCheck {
 		t.Fatalf("HTTP flags not applied: %#v", cfg)
 	}
 	if cfg.StartKey != "ctrl+a" || cfg.PauseKey != "ctrl+b" || cfg.CancelKey != "ctrl+c" || cfg.HotKeyHook {
@@ -85,6 +89,12 @@ func TestBindFlagsApplyFlagsAndAnySet(t *testing.T) {
 	if cfg.CacheDir != "cache" || !cfg.KeepCache || !cfg.Notification || !cfg.RequestFailedNotification || !cfg.FFMPEG_DEBUG || !cfg.RECORD_DEBUG || cfg.HOTKEY_DEBUG || !cfg.UPLOAD_DEBUG {
 		t.Fatalf("misc flags not applied: %#v", cfg)
 	}
+	if cfg.UILang != "en" {
+		t.Fatalf("ui-lang flag not applied: %#v", cfg)
+	}
+	if cfg.FFMPEG_PATH != "C:/ffmpeg/bin/ffmpeg.exe" {
+		t.Fatalf("ffmpeg path flag not applied: %#v", cfg)
+	}
 	if fv.OutputPath != "out.txt" || !fv.OutputPathSet {
 		t.Fatalf("output flag = %q set=%v, want out.txt true", fv.OutputPath, fv.OutputPathSet)
 	}
@@ -0,0 +1,250 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"stt/internal/atomicfile"
+	"stt/internal/redact"
+	"stt/internal/secret"
+)
+
+// sensitiveFields lists the settings that `stt config encrypt` protects.
+func sensitiveFields(cfg *Config) map[string]*string {
+	return map[string]*string{
+		"TOKEN":            &cfg.Token,
+		"API_ENDPOINT":     &cfg.APIEndpoint,
+		"CACHE_PASSPHRASE": &cfg.CachePassphrase,
+	}
+}
+
+// RedactedJSON returns cfg as indented JSON with its credentials masked,
+// for -print-config.
+func RedactedJSON(cfg *Config) ([]byte, error) {
+	c := *cfg
+	v := reflect.ValueOf(&c).Elem()
+	extraType := reflect.TypeOf(c.ExtraConfig)
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() != reflect.String || f.Type() == extraType || f.String() == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		if redact.SecretName(name) {
+			f.SetString(redact.Mask)
+		} else {
+			f.SetString(redact.Text(f.String()))
+		}
+	}
+	if extra, err := c.ExtraConfig.Object(); err == nil && extra != nil {
+		data, err := json.Marshal(redact.JSON(extra))
+		if err != nil {
+			return nil, err
+		}
+		c.ExtraConfig = ExtraJSON(data)
+	}
+	return json.MarshalIndent(&c, "", "  ")
+}
+
+// EncryptSecrets encrypts the sensitive fields of cfg in place.
+func EncryptSecrets(cfg *Config) error {
+	for key, p := range sensitiveFields(cfg) {
+		v, err := secret.Encrypt(*p)
+		if err != nil {
+			return fmt.Errorf("encrypt %s: %w", key, err)
+		}
+		*p = v
+	}
+	return nil
+}
+
+// DecryptSecrets decrypts any encrypted sensitive fields of cfg in place.
+func DecryptSecrets(cfg *Config) error {
+	for key, p := range sensitiveFields(cfg) {
+		v, err := secret.Decrypt(*p)
+		if err != nil {
+			return fmt.Errorf("decrypt %s: %w", key, err)
+		}
+		*p = v
+	}
+	return nil
+}
+
+// HasEncryptedSecrets reports whether the config file at path stores any
+// sensitive field in encrypted form. Unreadable files report false.
+func HasEncryptedSecrets(path string) bool {
+	cfg, err := readRaw(path)
+	if err != nil {
+		return false
+	}
+	for _, p := range sensitiveFields(&cfg) {
+		if secret.IsEncrypted(*p) {
+			return true
+		}
+	}
+	return false
+}
+
+// EncryptFile rewrites the config file at path with sensitive fields encrypted.
+func EncryptFile(path string) error {
+	return rewriteSecrets(path, EncryptSecrets)
+}
+
+// DecryptFile rewrites the config file at path with sensitive fields in plain text.
+func DecryptFile(path string) error {
+	return rewriteSecrets(path, DecryptSecrets)
+}
+
+// rewriteSecrets passes the sensitive fields the config file at path sets to
+// transform and writes back their new values in place, leaving the rest of
+// the file, comments included, as it was. The file is replaced atomically,
+// so a crash cannot leave it half written.
+func rewriteSecrets(path string, transform func(*Config) error) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(stripJSONComments(src), &Config{}); err != nil {
+		return err
+	}
+	spans := stringValues(src)
+	var cfg Config
+	for key, p := range sensitiveFields(&cfg) {
+		if sp, ok := spans[key]; ok {
+			if err := json.Unmarshal(src[sp[0]:sp[1]], p); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+	if err := transform(&cfg); err != nil {
+		return err
+	}
+	values := map[string]string{}
+	for key, p := range sensitiveFields(&cfg) {
+		if _, ok := spans[key]; ok {
+			values[key] = *p
+		}
+	}
+	out, err := replaceStrings(src, spans, values)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, out, 0600)
+}
+
+// replaceStrings returns src with the string at spans[key] replaced by
+// values[key] for each key of values.
+func replaceStrings(src []byte, spans map[string][2]int, values map[string]string) ([]byte, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	// Replacing from the end of the file keeps the earlier spans valid.
+	sort.Slice(keys, func(i, j int) bool { return spans[keys[i]][0] > spans[keys[j]][0] })
+	out := slices.Clone(src)
+	for _, key := range keys {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(values[key]); err != nil {
+			return nil, err
+		}
+		sp := spans[key]
+		out = slices.Replace(out, sp[0], sp[1], bytes.TrimSuffix(buf.Bytes(), []byte("\n"))...)
+	}
+	return out, nil
+}
+
+// stringValues finds the members of the top-level object of the JSONC
+// document src whose values are strings, and returns the span of each value,
+// quotes included, by key.
+func stringValues(src []byte) map[string][2]int {
+	spans := map[string][2]int{}
+	depth := 0
+	expectKey, expectValue := false, false
+	key := ""
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '"':
+			end := stringEnd(src, i)
+			if depth == 1 && expectKey {
+				_ = json.Unmarshal(src[i:end], &key)
+				expectKey = false
+			} else if depth == 1 && expectValue {
+				spans[key] = [2]int{i, end}
+				expectValue = false
+			}
+			i = end - 1
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			i += 2
+			for i+1 < len(src) && !(src[i] == '*' && src[i+1] == '/') {
+				i++
+			}
+			i++
+		case c == '{' || c == '[':
+			depth++
+			expectKey = depth == 1 && c == '{'
+			expectValue = false
+		case c == '}' || c == ']':
+			depth--
+		case c == ':' && depth == 1:
+			expectValue = true
+		case c == ',' && depth == 1:
+			expectKey, expectValue = true, false
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+		default:
+			expectValue = false
+		}
+	}
+	return spans
+}
+
+// stringEnd returns the index just past the string literal starting at
+// src[start], or len(src) when it is not closed.
+func stringEnd(src []byte, start int) int {
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(src)
+}
+
+// readRaw decodes the config file without resolving paths or decrypting secrets,
+// so it can be written back unchanged apart from the intended edits.
+func readRaw(path string) (Config, error) {
+	cfg := DefaultConfig()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(stripJSONComments(b), &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,262 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// fieldDoc documents one config key in the annotated template.
+type fieldDoc struct {
+	Key   string
+	Lines []string
+}
+
+// fieldDocs lists every config key in template order. A test keeps it in sync
+// with the Config struct.
+var fieldDocs = []fieldDoc{
+	{"API_ENDPOINT", []string{"ASR 上传端点 URL。", "示例: https://api.openai.com/v1/audio/transcriptions"}},
+	{"TOKEN", []string{"授权 Token（Bearer）。也可以通过 .env 或 STT_TOKEN 环境变量提供。", "运行 stt config encrypt 后以 dpapi:... 形式加密保存，读取时自动解密。"}},
+	{"MODEL", []string{"模型名称，例如 gpt-4o-mini-transcribe、whisper-1。"}},
+	{"LANGUAGE", []string{"识别语言，例如 zh、en；留空则不发送。", "auto 表示自动检测，会按 PROVIDER 的约定省略该字段或发送 auto / und。"}},
+	{"PROVIDER", []string{"服务商约定。允许: openai, azure, groq, siliconflow, deepinfra（省略 language）, whispercpp, sensevoice（发送 auto）, bcp47（发送 und）。", "留空则按 API_ENDPOINT 域名识别，无法识别时省略 language。"}},
+	{"PROFILE", []string{"配置名称（例如 work、home），可在 CACHE_NAME 中以 {profile} 引用；留空时为 default。"}},
+	{"MACHINE_ID", []string{"本机标识，可在 CACHE_NAME 中以 {machine} 引用，并记录到 history.db；留空时使用计算机名。", "多台电脑同步同一个缓存目录（OneDrive、Syncthing 等）时，在 CACHE_NAME 中加入 {machine} 可避免同一时刻的文件重名。"}},
+	{"PROMPT", []string{"识别提示文本，对应请求字段 prompt；留空则不发送。"}},
+	{"TEXT_PATH", []string{"从返回 JSON 中抽取文本的路径，点分 + 方括号下标。", "示例: text、results[0].alternatives[0].transcript"}},
+	{"POST_COMMAND", []string{"每次转写成功后运行的命令（Windows 上经 cmd.exe 执行），转写文本以 UTF-8 从标准输入传入。", "STT_AUDIO_SOURCE、STT_PROFILE、STT_PROVIDER、STT_MODEL、STT_LANGUAGE、STT_LATENCY_MS、STT_TIME 环境变量提供元数据，其中 STT_AUDIO_SOURCE 为 record 或 file，与配置项 SOURCE 无关；命令中再次运行 stt 时，STT_PROFILE 等与配置项同名的变量会作为环境变量配置生效。为空表示不运行。"}},
+	{"ExtraConfig", []string{"合并到请求根级字段的额外 JSON，可直接写成对象，也兼容转义字符串。将内置字段设为 null 可删除该字段。", `示例: {"response_format": "json", "temperature": 0}`}},
+	{"CHANNELS", []string{"录音通道数，允许 1..8。"}},
+	{"INPUT_DEVICE", []string{"录音设备的 PortAudio 编号或名称（或名称的一部分，不区分大小写）；留空使用系统默认麦克风。可用 stt devices 列出设备。"}},
+	{"SOURCE", []string{"录音来源：microphone 为麦克风；loopback 录制电脑正在播放的声音（默认输出设备的 WASAPI 环回），此时 INPUT_DEVICE 在环回设备中选择。"}},
+	{"MAX_RECORD_SECONDS", []string{"单次录音的最长时长（秒），到达后自动停止并上传；0 表示不限制。", "开启 RECORDING_TIMER 时，结束前 10 秒会发出提醒。"}},
+	{"RECORD_SEGMENT_SECONDS", []string{"录音时每隔约这么多秒（在安静处）切出一段，边录边转换上传，停止后很快得到全文；0 表示关闭。", "某段上传失败时改为照常转写整段录音；开启 OFFLINE_FIRST 时不生效。缓存只保留录音的 WAV（需开启 KEEP_WAV）。"}},
+	{"LOCK_ACTION", []string{"锁定工作站（Win+L 或自动锁屏）时如何处理正在进行的录音：none 不处理；pause 暂停（默认），解锁后可继续；stop 停止并转写；cancel 取消并丢弃录音。"}},
+	{"SAMPLING_RATE", []string{"采样率，单位 Hz，必须 > 0。常用 16000、44100、48000。"}},
+	{"SAMPLING_RATE_DEPTH", []string{"采样位深，单位 bits。允许: 8, 16, 24, 32。"}},
+	{"BIT_RATE", []string{"目标比特率，单位 kbps，必须 > 0。无损/PCM 编码会忽略该值。"}},
+	{"CODECS", []string{"编码器。允许: opus, libopus, wavpack, aac, ac3, eac3, mp3, mp2, mp1, flac, alac, pcm, vorbis, libvorbis, vorb, adpcm, amr, pcm_*。"}},
+	{"CONTAINER", []string{"容器格式，同时决定上传文件扩展名。允许: wav, ac3, ac4, ogg, oga, mp3, flac, eac3, aac, m4a, mp4, opus, webm 以及 s16le 等裸格式。"}},
+	{"REQUEST_TIMEOUT", []string{"单次请求超时，单位秒。"}},
+	{"MAX_RETRY", []string{"上传最大尝试次数。"}},
+	{"RETRY_BASE_DELAY", []string{"重试基准延迟，单位秒；每次重试翻倍。"}},
+	{"ENABLE_HTTP2", []string{"是否启用 HTTP/2。"}},
+	{"VERIFY_SSL", []string{"是否验证 HTTPS 证书。设为 false 会跳过校验，存在安全风险。"}},
+	{"CA_CERT_FILE", []string{"额外信任的根证书文件（PEM，可包含多个证书），用于企业代理或自签名证书的 HTTPS 端点，无需关闭 VERIFY_SSL。相对路径相对于配置文件所在目录。"}},
+	{"STARTUP_CHECK", []string{"录音模式启动时是否探测 API_ENDPOINT，报告可达性、TLS 证书和鉴权状态（不上传音频）。"}},
+	{"UPDATE_CHECK", []string{"录音模式启动时检查 GitHub 上是否有更新的版本，有则提示运行 stt update（默认关闭）。"}},
+	{"WATCHDOG", []string{"录音模式由一个看护进程启动，意外退出（崩溃）时自动重启，间隔从 5 秒起逐次加倍、最长 5 分钟，并把崩溃输出追加到缓存目录下的 crash.log（默认关闭）。"}},
+	{"ONBOARDING", []string{"录音模式首次启动时显示引导：检查端点、介绍热键并引导完成一次测试录音；测试成功后不再显示。"}},
+	{"HTTP_API", []string{"本地 HTTP 控制接口的监听地址，例如 127.0.0.1:8765；为空表示关闭。只允许回环地址。", "提供 /status、/start、/stop、/transcribe、/history 等接口，请求需携带 HTTP_API_TOKEN。"}},
+	{"HTTP_API_TOKEN", []string{"HTTP 控制接口的访问令牌，通过 Authorization: Bearer <令牌> 或 X-STT-Token 请求头传入。", "为空时每次启动随机生成，并写入缓存目录（未设置 CACHE_DIR 时为当前目录）下的 http-api-token 文件。"}},
+	{"GRPC_API", []string{"本地 gRPC 接口的监听地址，例如 127.0.0.1:8766；为空表示关闭。只允许回环地址。", "接口定义见 internal/grpcapi/sttpb/stt.proto，调用需在 authorization 元数据中携带 Bearer <GRPC_API_TOKEN>。"}},
+	{"GRPC_API_TOKEN", []string{"gRPC 接口的访问令牌；为空时每次启动随机生成，并写入缓存目录（未设置 CACHE_DIR 时为当前目录）下的 grpc-api-token 文件。"}},
+	{"HOTKEY_HOOK", []string{"是否使用低级键盘钩子 (WH_KEYBOARD_LL) 独占热键。"}},
+	{"PTT", []string{"按住说话：只在按住开始热键时录音，松开即停止并上传（需开启 HOTKEY_HOOK；macOS 不支持，开始热键仍按切换方式工作）。"}},
+	{"START_KEY", []string{"开始/停止录音热键。修饰键: ctrl, alt, shift, win；按键: a-z, 0-9, f1-f24, esc, space, enter, tab, numpad0-9 等。", "示例: ctrl+alt+q"}},
+	{"PAUSE_KEY", []string{"暂停/恢复录音热键，不能与其他热键重复。"}},
+	{"CANCEL_KEY", []string{"取消录音热键，不能与其他热键重复。"}},
+	{"MIDI_INPUT", []string{"接收触发的 MIDI 输入设备，填写设备名称的一部分（不区分大小写）；为空表示不使用 MIDI。", "可用设备可通过 stt devices -midi 查看。"}},
+	{"MIDI_MAP", []string{"MIDI 音符或控制器到动作的映射，以逗号分隔，例如 36=toggle,37=pause,cc64=toggle。", "动作可为 toggle、start、stop、pause、resume、cancel；控制器的值达到 64 时触发（如延音踏板踩下）。"}},
+	{"SCHEDULE", []string{"定时转写任务，多个任务以分号分隔，每个任务为“分 时 日 月 周 路径”，例如 0 7 * * 1-5 D:\\Calls\\{yesterday}。", "路径可以是文件夹或文件，可用占位符 {date}、{yesterday}、{yyyy}、{mm}、{dd}；已有同名 .txt 的音频会跳过。"}},
+	{"CLIPBOARD_WATCH", []string{"监视剪贴板：复制音频或视频文件（或其路径）后弹出通知，点击“转写”即写出同名 .txt。"}},
+	{"CACHE_DIR", []string{"缓存/临时文件目录。相对路径以本配置文件所在目录为基准；留空使用数据目录（%APPDATA%\\stt；便携模式或使用当前目录的 config.json 时为当前目录）。"}},
+	{"KEEP_CACHE", []string{"是否保留录音、转码文件和响应 JSON（需要设置 CACHE_DIR）。", "每次还会写出 <文件名>.txt（转写文本）和 <文件名>.meta.json，记录时长、采样率、编码、请求耗时、重试次数和服务商。"}},
+	{"KEEP_WAV", []string{"KEEP_CACHE 开启时是否保留原始 WAV 录音；关闭可节省空间，只保留转码后的小文件。"}},
+	{"KEEP_CONVERTED", []string{"KEEP_CACHE 开启时是否保留转码后上传的音频（如 opus）。"}},
+	{"CACHE_LAYOUT", []string{"保留的缓存文件按此布局放入 CACHE_DIR 下的子目录，默认 {yyyy}/{mm}/{dd}（每天一个文件夹）。", "可用占位符：{yyyy}、{yy}、{mm}、{dd}、{hh}、{ww}（ISO 周）；留空则全部放在 CACHE_DIR 根目录。history.db 与 cache.key 始终位于 CACHE_DIR 根目录。"}},
+	{"CACHE_NAME", []string{"保留文件的命名模板（不含扩展名），默认 audio-{date}-{time}。", "占位符：{date} {time} {profile} {lang} {model} {provider} {source} {machine} {seq} 以及 {yyyy} {yy} {mm} {dd} {hh} {ww}；{seq} 为同名时递增的两位序号，模板不含 {seq} 时重名文件会追加 -2、-3…"}},
+	{"CACHE_ARCHIVE_DAYS", []string{"超过该天数的缓存会压缩进 CACHE_DIR/archive/YYYY-MM.zip 并删除原文件；0 表示不归档。", "history.db 中的音频路径会改为 <zip>!<压缩包内路径>，转写记录保持可查。"}},
+	{"CACHE_MAX_AGE_DAYS", []string{"保留缓存的最长天数，超过后自动删除；0 表示永久保留。", "与 CACHE_MAX_SIZE_MB 一起在启动时以及每隔 CACHE_PURGE_INTERVAL 分钟执行。"}},
+	{"CACHE_MAX_SIZE_MB", []string{"缓存总大小上限（MB），超出时从最旧的一组开始删除；0 表示不限制。"}},
+	{"CACHE_PURGE_INTERVAL", []string{"自动清理缓存的间隔（分钟）。程序常驻运行时按此间隔重复执行保留策略。"}},
+	{"HISTORY", []string{"是否把每次转写（时间、时长、服务商、模型、文本、耗时、音频路径）记录到 CACHE_DIR 下的 history.db（需要设置 CACHE_DIR）。", "启用后响应 JSON 保存在数据库中，不再单独写出 .json 文件。"}},
+	{"CACHE_ENCRYPTION", []string{"缓存加密方式：dpapi（绑定当前 Windows 用户）、passphrase（使用 CACHE_PASSPHRASE 派生密钥）或留空不加密。", "启用后保留的录音、转码文件、响应 JSON 以 .enc 结尾加密保存，history.db 中的文本与响应也会加密；密钥保存在 CACHE_DIR/cache.key。"}},
+	{"CACHE_PASSPHRASE", []string{"CACHE_ENCRYPTION=passphrase 时使用的口令；建议通过环境变量 STT_CACHE_PASSPHRASE 提供，或用 stt config encrypt 加密保存。"}},
+	{"RETRY_QUEUE", []string{"录音模式下上传重试耗尽时，把转码后的音频移入 CACHE_DIR/pending/ 队列，并在后台定期重试（需要设置 CACHE_DIR）。", "也可以运行 stt queue flush 手动重试，stt queue list 查看队列。"}},
+	{"QUEUE_RETRY_INTERVAL", []string{"后台重试 pending/ 队列的间隔（秒）。每轮从最早的录音开始，遇到第一个失败即停止，等待下一轮。"}},
+	{"OFFLINE_FIRST", []string{"离线优先：录音结束后先把原始录音写入 CACHE_DIR/pending/ 队列，再转码上传；成功后才从队列移除（需要设置 CACHE_DIR）。", "程序在转码或上传途中崩溃、断网时，录音会在下次启动或后台重试时继续上传（至少一次语义，极端情况下可能重复转写）。"}},
+	{"NOTIFICATION", []string{"是否启用 Windows 系统通知。"}},
+	{"TRAY", []string{"录音模式下是否在任务栏通知区域显示状态图标（Windows），右键菜单可开始/停止、暂停、取消录音、打开缓存目录、重新加载配置和退出。"}},
+	{"TRAY_THEME", []string{"托盘图标配色：auto 跟随任务栏主题，light 适用于浅色任务栏，dark 适用于深色任务栏。"}},
+	{"VU_METER", []string{"录音时在屏幕右下角显示一个小窗口，实时显示输入电平和波形，便于确认麦克风正在收音；窗口不会抢占焦点，可拖动。"}},
+	{"RECORDING_TIMER", []string{"录音时显示一条常驻通知，每秒更新已录制时长；设置了 MAX_RECORD_SECONDS 时同时显示剩余时间并在接近上限时提醒（需开启 NOTIFICATION）。"}},
+	{"SOUND_MUTE", []string{"是否静音所有 SOUND_* 提示音。"}},
+	{"SOUND_START", []string{"开始录音时播放的声音：.wav 文件路径，或 Windows 系统声音名称（如 SystemAsterisk、SystemExclamation、SystemHand、SystemNotification、SystemDefault）；留空不播放。"}},
+	{"SOUND_STOP", []string{"停止录音时播放的声音，格式同 SOUND_START。"}},
+	{"SOUND_PASTE_SUCCESS", []string{"转写结果粘贴成功后播放的声音，格式同 SOUND_START。"}},
+	{"SOUND_UPLOAD_FAILED", []string{"上传失败时播放的声音，格式同 SOUND_START。"}},
+	{"SOUND_ERROR", []string{"其他失败（粘贴失败、转换失败、端点检查失败）时播放的声音，SOUND_UPLOAD_FAILED 留空时上传失败也播放它；格式同 SOUND_START。"}},
+	{"REQUEST_FAILED_NOTIFICATION", []string{"录音模式下上传重试耗尽后，是否粘贴占位符 [request failed]。"}},
+	{"PROGRESS_NOTIFICATION", []string{"转换和上传耗时较长时，显示一条原地更新的进度通知；需同时开启 NOTIFICATION。"}},
+	{"TIMING_NOTIFICATION", []string{"在粘贴成功的通知中附上本次录音收尾、转换、上传和粘贴各自的耗时；需同时开启 NOTIFICATION。耗时也会写入日志和历史记录。"}},
+	{"QUIET_MODE", []string{"全屏应用、演示模式或 Windows 免打扰时段期间不弹出通知、不播放提示音，但仍会粘贴识别结果。"}},
+	{"LOG_FILE", []string{"把控制台输出同时写入此日志文件，相对路径以 CACHE_DIR（未设置时为当前目录）为基准；留空不写日志。", "失败通知可点击，打开包含错误与最近日志的详情文件。"}},
+	{"LOG_MAX_SIZE_MB", []string{"日志超过此大小（MB）时改名为 stt.log.1 并开始新日志，0 表示不按大小轮转。"}},
+	{"LOG_ROTATE_DAYS", []string{"每隔多少天开始新日志（1 表示每天零点后第一行日志写入新文件），0 表示不按时间轮转。"}},
+	{"LOG_MAX_BACKUPS", []string{"保留的旧日志数量（stt.log.1、stt.log.2……，数字越大越旧），0 表示轮转时直接删除旧日志。"}},
+	{"FFMPEG_PATH", []string{"ffmpeg 可执行文件路径；留空则自动查找 PATH、程序目录和常见安装位置。"}},
+	{"FFMPEG_DEBUG", []string{"输出 ffmpeg 调试信息。"}},
+	{"FFMPEG_TIMEOUT", []string{"ffmpeg 转码的最长秒数，超时会结束 ffmpeg 进程（含其子进程）并报错；0 表示不限制。"}},
+	{"FFMPEG_INPUT_ARGS", []string{"放在 -i 之前的额外 ffmpeg 参数（作用于输入），以空格分隔，含空格的参数用引号括起，例如 -ss 5。"}},
+	{"FFMPEG_EXTRA_ARGS", []string{"放在输出文件之前的额外 ffmpeg 参数，可覆盖 STT 设置的选项，例如 -af \"highpass=f=200\"。", "设置任一参数后，转码总是调用 ffmpeg，不再跳过转码或使用内置 Opus 编码；GUI 的内置转码会忽略这两项。"}},
+	{"FFMPEG_THREADS", []string{"ffmpeg 编码使用的线程数（-threads），0 表示由 ffmpeg 决定。长文件在多核电脑上可调大以加快转码；内置 Opus 编码与 GUI 的内置转码会忽略该值。"}},
+	{"COMPRESSION_LEVEL", []string{"Opus（0-10）或 FLAC（0-12）编码的压缩等级（-compression_level），越低越快、越高压缩越好；-1 使用编码器默认值（Opus 为 10，FLAC 为 5）。其他编码忽略该值。"}},
+	{"OPUS_VBR", []string{"Opus 的码率模式（-vbr）：on 可变码率、off 固定码率、constrained 受限可变码率；留空使用编码器默认值（on）。"}},
+	{"AUDIO_HIGHPASS", []string{"转码时滤除低于该频率（Hz）的声音，如 100 可去掉风声、空调等低频噪声；0 表示关闭。"}},
+	{"AUDIO_LOWPASS", []string{"转码时滤除高于该频率（Hz）的声音，如 8000 可去掉嘶声；0 表示关闭。"}},
+	{"AUDIO_DENOISE", []string{"转码时用 ffmpeg 的 afftdn 滤镜降低持续的背景噪声。", "这三项需要外部 ffmpeg：开启后转码总是调用 ffmpeg；GUI 的内置转码会忽略它们。"}},
+	{"AUDIO_SPEED", []string{"上传前把音频加速到该倍数（1–2），按音频时长计费的服务可以省钱省时；1 表示不加速。"}},
+	{"FILE_CHUNK_SECONDS", []string{"转写文件时把长于该秒数的文件切成多段分别上传再合并结果；0 表示不切分。"}},
+	{"FILE_CHUNK_OVERLAP", []string{"相邻分段重叠的秒数，合并时去掉重叠部分的重复文字。"}},
+	{"MAX_UPLOAD_MB", []string{"服务的上传大小上限（MB），转码后超过它的文件按 FILE_CHUNK_SECONDS 的方式切分；0 表示不限制。"}},
+	{"AUDIO_TRACK", []string{"转写文件时使用的音轨，从 1 开始计数，如多语言视频的第二条音轨；0 表示由 ffmpeg 选择。"}},
+	{"CONVERT_WORKERS", []string{"批量转写文件夹时同时转码的文件数；同时上传的数量见 MAX_CONCURRENT_UPLOADS。"}},
+	{"OUTPUT_FORMAT", []string{"-file 写出的转写格式：txt、json（服务的完整响应）、srt、vtt 或 tsv（带时间戳的字幕，需要服务返回 segments）。"}},
+	{"OUTPUT_TEMPLATE", []string{"批量转写时每份转写的路径模板，如 {dir}/out/{name}.{lang}.{ext}；留空则写在各文件旁。"}},
+	{"PROGRESS_BAR", []string{"批量转写时在终端显示进度条；关闭或输出不是终端（如 CI 日志）时每个文件输出一行。"}},
+	{"SKIP_EXISTING", []string{"批量转写时跳过已有转写文件或历史记录中已成功转写的文件，便于中断后继续。"}},
+	{"RECURSIVE", []string{"-file 为文件夹或通配符时也转写子文件夹中的文件。"}},
+	{"INCLUDE_EXTENSIONS", []string{"批量转写时只转写这些扩展名的文件，逗号分隔，如 .m4a,.wav；留空则文件夹取所有音视频文件，通配符取所有匹配的文件。"}},
+	{"EXCLUDE_EXTENSIONS", []string{"批量转写时跳过这些扩展名的文件，逗号分隔。"}},
+	{"FILE_MIN_SECONDS", []string{"批量转写时跳过短于该秒数的文件；0 表示不限制。"}},
+	{"FILE_MAX_SECONDS", []string{"批量转写时跳过长于该秒数的文件；0 表示不限制。"}},
+	{"MAX_CONCURRENT_UPLOADS", []string{"同时进行的上传数上限；批量转写和分段转写最多同时上传这么多个；0 表示不限制，批量和分段仍逐个上传。"}},
+	{"REQUESTS_PER_MINUTE", []string{"每分钟最多发出的上传请求数（含重试），超出的等候，避免触发服务的速率限制（429）；0 表示不限制。"}},
+	{"REQUESTS_BURST", []string{"REQUESTS_PER_MINUTE 限制下，空闲一段时间后可立即连续发出的请求数；1 表示不突发，请求始终按间隔发出。"}},
+	{"RECORD_DEBUG", []string{"输出录音子系统调试信息。"}},
+	{"HOTKEY_DEBUG", []string{"输出热键/消息循环调试信息。"}},
+	{"UPLOAD_DEBUG", []string{"输出上传过程调试信息（可能包含响应内容）。"}},
+	{"UI_LANG", []string{"CLI 帮助、日志与通知的语言。允许: zh, en；留空则按系统语言自动选择。"}},
+}
+
+// TemplatePath returns the annotated template path that accompanies a config file,
+// e.g. config.json -> config.example.jsonc.
+func TemplatePath(configPath string) string {
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + ".example.jsonc"
+}
+
+// SaveTemplate writes an annotated JSONC template with default values and a
+// comment for every key. Load accepts the template directly as a config file.
+func SaveTemplate(path string) error {
+	b, err := templateBytes(DefaultConfig())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+func templateBytes(cfg Config) ([]byte, error) {
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// STT 配置模板（JSONC）。每个键上方的注释说明含义、允许值和示例。\n")
+	buf.WriteString("// 可直接重命名为 config.json 使用，注释会被忽略。\n")
+	buf.WriteString("{\n")
+	for i, doc := range fieldDocs {
+		value, ok := values[doc.Key]
+		if !ok {
+			return nil, fmt.Errorf("template key %s is not a config field", doc.Key)
+		}
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		for _, line := range doc.Lines {
+			buf.WriteString("  // " + line + "\n")
+		}
+		key, _ := json.Marshal(doc.Key)
+		buf.WriteString("  " + string(key) + ": " + string(value))
+		if i < len(fieldDocs)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+// configKeys returns the JSON keys of Config in declaration order.
+func configKeys() []string {
+	t := reflect.TypeOf(Config{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if tag != "" && tag != "-" {
+			keys = append(keys, tag)
+		}
+	}
+	return keys
+}
+
+// stripJSONComments removes // line comments and /* */ block comments outside
+// of string literals so JSONC files can be decoded with encoding/json.
+func stripJSONComments(src []byte) []byte {
+	out := make([]byte, 0, len(src))
+	inString := false
+	escaped := false
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if inString {
+			out = append(out, c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		if c == '"' {
+			inString = true
+			out = append(out, c)
+			continue
+		}
+		if c == '/' && i+1 < len(src) {
+			switch src[i+1] {
+			case '/':
+				for i < len(src) && src[i] != '\n' {
+					i++
+				}
+				if i < len(src) {
+					out = append(out, '\n')
+				}
+				continue
+			case '*':
+				i += 2
+				for i+1 < len(src) && !(src[i] == '*' && src[i+1] == '/') {
+					i++
+				}
+				i++
+				out = append(out, ' ')
+				continue
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
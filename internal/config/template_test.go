@@ -0,0 +1,76 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFieldDocsCoverEveryConfigKey(t *testing.T) {
+	documented := make(map[string]bool, len(fieldDocs))
+	for _, doc := range fieldDocs {
+		if documented[doc.Key] {
+			t.Fatalf("key %s documented twice", doc.Key)
+		}
+		documented[doc.Key] = true
+	}
+	for _, key := range configKeys() {
+		if !documented[key] {
+			t.Fatalf("config key %s has no template documentation", key)
+		}
+	}
+}
+
+func TestSaveTemplateLoadsAsDefaultConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := TemplatePath(filepath.Join(dir, "config.json"))
+	if filepath.Base(path) != "config.example.jsonc" {
+		t.Fatalf("TemplatePath = %s, want config.example.jsonc", path)
+	}
+	if err := SaveTemplate(path); err != nil {
+		t.Fatalf("SaveTemplate failed: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if !strings.Contains(string(raw), "// 编码器。允许:") {
+		t.Fatalf("template missing key comments:\n%s", raw)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load(template) failed: %v", err)
+	}
+	if cfg != DefaultConfig() {
+		t.Fatalf("template config = %#v, want defaults", cfg)
+	}
+}
+
+func TestStripJSONCommentsKeepsStrings(t *testing.T) {
+	src := `{
+  // line comment
+  "a": "http://x/*y*/", /* block
+  comment */ "b": "say \"//hi\""
+}`
+	got := string(stripJSONComments([]byte(src)))
+	if strings.Contains(got, "line comment") || strings.Contains(got, "block") {
+		t.Fatalf("comments not stripped: %s", got)
+	}
+	if !strings.Contains(got, `"http://x/*y*/"`) || !strings.Contains(got, `"say \"//hi\""`) {
+		t.Fatalf("string contents altered: %s", got)
+	}
+}
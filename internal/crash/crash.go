@@ -0,0 +1,155 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package crash turns a panic in one of STT's goroutines into a crash
+// report, so a bug in the hotkey, recording or upload code costs one
+// transcription instead of the whole process vanishing from the tray.
+package crash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"stt/internal/i18n"
+	"stt/internal/update"
+)
+
+// filePrefix starts the name of every report, followed by its time.
+const filePrefix = "stt-crash-"
+
+var (
+	mu      sync.Mutex
+	dir     string
+	config  []byte
+	handler func(where, report string)
+)
+
+// Setup makes reports go to reportDir, include config, which must have its
+// credentials masked already, and be passed to handle, which may be nil,
+// once written. It can be called again when the config changes.
+func Setup(reportDir string, redactedConfig []byte, handle func(where, report string)) {
+	mu.Lock()
+	defer mu.Unlock()
+	dir, config, handler = reportDir, redactedConfig, handle
+}
+
+// Recover is deferred at the top of a goroutine. A panic in it is written
+// to a crash report and handled as Setup says, and the goroutine returns
+// normally instead of ending the process.
+func Recover(where string) {
+	if v := recover(); v != nil {
+		Handle(where, v, debug.Stack())
+	}
+}
+
+// Handle writes a report of the panic v recovered in where, logs it and
+// passes it to the handler. It returns the report's path, or "" when it
+// could not be written.
+func Handle(where string, v any, stack []byte) string {
+	report := record(where, v, stack)
+	mu.Lock()
+	handle := handler
+	mu.Unlock()
+	if handle != nil {
+		handle(where, report)
+	}
+	return report
+}
+
+// Error is a panic that AsError turned into an error.
+type Error struct {
+	Where string
+	Value any
+	// Report is the crash report's path, or "" when it could not be
+	// written.
+	Report string
+}
+
+func (e *Error) Error() string {
+	if e.Report == "" {
+		return fmt.Sprintf("panic in %s: %v", e.Where, e.Value)
+	}
+	return fmt.Sprintf("panic in %s: %v (crash report: %s)", e.Where, e.Value, e.Report)
+}
+
+// AsError writes and logs a report of the panic v like Handle, but returns
+// it as an *Error for the caller to fail with like with any other error,
+// instead of passing it to the handler.
+func AsError(where string, v any, stack []byte) error {
+	return &Error{Where: where, Value: v, Report: record(where, v, stack)}
+}
+
+// record writes and logs a report and returns its path, or "".
+func record(where string, v any, stack []byte) string {
+	mu.Lock()
+	reportDir, cfg := dir, config
+	mu.Unlock()
+
+	fmt.Printf("[crash] %s\n%s", i18n.Sprintf("panic in %s: %v", where, v), stack)
+	report, err := Write(reportDir, where, v, stack, cfg, time.Now())
+	if err != nil {
+		fmt.Printf("[crash] %s\n", i18n.Sprintf("failed to write crash report: %v", err))
+		return ""
+	}
+	fmt.Printf("[crash] %s\n", i18n.Sprintf("report written to %s", report))
+	return report
+}
+
+// Write writes a report of the panic v in where with its stack, the STT and
+// Go versions and cfg to a new file in reportDir and returns its path.
+func Write(reportDir, where string, v any, stack, cfg []byte, now time.Time) (string, error) {
+	if reportDir == "" {
+		reportDir = "."
+	}
+	if err := os.MkdirAll(reportDir, 0o755); err != nil {
+		return "", err
+	}
+	version := update.Current()
+	if version == "" {
+		version = "unknown"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "STT crash report\r\n\r\n")
+	fmt.Fprintf(&b, "Time:    %s\r\n", now.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "Where:   %s\r\n", where)
+	fmt.Fprintf(&b, "Panic:   %v\r\n", v)
+	fmt.Fprintf(&b, "Version: %s\r\n", version)
+	fmt.Fprintf(&b, "Go:      %s %s/%s\r\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if len(cfg) > 0 {
+		fmt.Fprintf(&b, "\r\nConfig:\r\n%s\r\n", crlf(string(cfg)))
+	}
+	fmt.Fprintf(&b, "\r\nStack:\r\n%s", crlf(string(stack)))
+
+	f, err := os.CreateTemp(reportDir, filePrefix+now.Format("20060102-150405")+"-*.txt")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.WriteString(b.String())
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return filepath.Clean(f.Name()), nil
+}
+
+// crlf ends the lines of s with CRLF, so Notepad shows them as lines.
+func crlf(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package crash
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecoverWritesReport(t *testing.T) {
+	dir := t.TempDir()
+	var gotWhere, gotReport string
+	Setup(dir, []byte("{\n  \"TOKEN\": \"***\"\n}"), func(where, report string) {
+		gotWhere, gotReport = where, report
+	})
+	defer Setup("", nil, nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer Recover("upload")
+		var m map[string]int
+		m["boom"]++
+	}()
+	<-done
+
+	if gotWhere != "upload" || filepath.Dir(gotReport) != filepath.Clean(dir) {
+		t.Fatalf("handler got %q, %q", gotWhere, gotReport)
+	}
+	if !strings.HasPrefix(filepath.Base(gotReport), filePrefix) {
+		t.Fatalf("report name = %q", filepath.Base(gotReport))
+	}
+	data, err := os.ReadFile(gotReport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(data)
+	for _, want := range []string{
+		"Where:   upload\r\n",
+		"Panic:   assignment to entry in nil map\r\n",
+		"\"TOKEN\": \"***\"\r\n",
+		"TestRecoverWritesReport",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report lacks %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestAsErrorReturnsThePanic(t *testing.T) {
+	dir := t.TempDir()
+	called := false
+	Setup(dir, nil, func(string, string) { called = true })
+	defer Setup("", nil, nil)
+
+	err := func() (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = AsError("upload", v, nil)
+			}
+		}()
+		panic("boom")
+	}()
+
+	var ce *Error
+	if !errors.As(err, &ce) || ce.Where != "upload" || ce.Value != "boom" || ce.Report == "" {
+		t.Fatalf("err = %#v", err)
+	}
+	if !strings.Contains(err.Error(), ce.Report) {
+		t.Fatalf("error %q does not name the report", err)
+	}
+	if called {
+		t.Fatal("AsError passed the panic to the handler too")
+	}
+}
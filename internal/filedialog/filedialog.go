@@ -0,0 +1,20 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package filedialog shows the Windows open-file dialog.
+package filedialog
+
+// Filter is one entry of the dialog's file type list, such as "Audio" with
+// patterns "*.wav" and "*.mp3".
+type Filter struct {
+	Name     string
+	Patterns []string
+}
@@ -0,0 +1,21 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build !windows
+
+package filedialog
+
+import "fmt"
+
+// Open is not supported on non-Windows builds.
+func Open(title string, filters []Filter) (string, error) {
+	return "", fmt.Errorf("file dialogs not supported on this platform")
+}
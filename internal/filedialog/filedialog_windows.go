@@ -0,0 +1,100 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package filedialog
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	ofnNoChangeDir   = 0x00000008
+	ofnPathMustExist = 0x00000800
+	ofnFileMustExist = 0x00001000
+	ofnExplorer      = 0x00080000
+	coinitApartment  = 0x2
+	maxPath          = 32768
+)
+
+var (
+	procGetOpenFileNameW     = syscall.NewLazyDLL("comdlg32.dll").NewProc("GetOpenFileNameW")
+	procCommDlgExtendedError = syscall.NewLazyDLL("comdlg32.dll").NewProc("CommDlgExtendedError")
+	procCoInitializeEx       = syscall.NewLazyDLL("ole32.dll").NewProc("CoInitializeEx")
+	procCoUninitialize       = syscall.NewLazyDLL("ole32.dll").NewProc("CoUninitialize")
+)
+
+type openFileName struct {
+	StructSize      uint32
+	Owner           uintptr
+	Instance        uintptr
+	Filter          *uint16
+	CustomFilter    *uint16
+	MaxCustomFilter uint32
+	FilterIndex     uint32
+	File            *uint16
+	MaxFile         uint32
+	FileTitle       *uint16
+	MaxFileTitle    uint32
+	InitialDir      *uint16
+	Title           *uint16
+	Flags           uint32
+	FileOffset      uint16
+	FileExtension   uint16
+	DefExt          *uint16
+	CustData        uintptr
+	FnHook          uintptr
+	TemplateName    *uint16
+	Reserved        uintptr
+	Reserved2       uint32
+	FlagsEx         uint32
+}
+
+// Open asks for an existing file and returns its path, or "" when the dialog
+// is canceled.
+func Open(title string, filters []Filter) (string, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if hr, _, _ := procCoInitializeEx.Call(0, coinitApartment); hr == 0 || hr == 1 {
+		defer procCoUninitialize.Call()
+	}
+
+	// The filter is a list of NUL-terminated name and pattern pairs, ended
+	// by an extra NUL.
+	var spec strings.Builder
+	for _, f := range filters {
+		spec.WriteString(f.Name + "\x00" + strings.Join(f.Patterns, ";") + "\x00")
+	}
+	filter := syscall.StringToUTF16(spec.String())
+	file := make([]uint16, maxPath)
+	titleW, _ := syscall.UTF16PtrFromString(title)
+	ofn := openFileName{
+		Filter:      &filter[0],
+		FilterIndex: 1,
+		File:        &file[0],
+		MaxFile:     uint32(len(file)),
+		Title:       titleW,
+		Flags:       ofnExplorer | ofnFileMustExist | ofnPathMustExist | ofnNoChangeDir,
+	}
+	ofn.StructSize = uint32(unsafe.Sizeof(ofn))
+	if r, _, _ := procGetOpenFileNameW.Call(uintptr(unsafe.Pointer(&ofn))); r == 0 {
+		if code, _, _ := procCommDlgExtendedError.Call(); code != 0 {
+			return "", fmt.Errorf("GetOpenFileNameW failed: 0x%X", code)
+		}
+		return "", nil
+	}
+	return syscall.UTF16ToString(file), nil
+}
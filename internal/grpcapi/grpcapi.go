@@ -0,0 +1,220 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package grpcapi serves the local gRPC API defined in sttpb/stt.proto, so
+// other programs can drive record mode, transcribe files and receive
+// transcripts without running the exe. Like the HTTP API it only listens on
+// loopback addresses and every call needs the API token.
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"stt/internal/grpcapi/sttpb"
+	"stt/internal/httpapi"
+	"stt/internal/i18n"
+)
+
+// maxMessage limits the size of a TranscribeFile request carrying audio.
+const maxMessage = 512 << 20
+
+// streamBuffer is how many transcripts a slow StreamTranscripts client may
+// fall behind before newer ones are dropped.
+const streamBuffer = 16
+
+// Backend is what the API controls, normally the record-mode runtime.
+type Backend interface {
+	// Control answers "status" with the status JSON, or runs "start" or
+	// "stop", returning an error when the state does not allow it.
+	Control(request string) (string, error)
+	// Transcribe transcribes the audio read from r. name is the file name,
+	// whose extension hints at the format.
+	Transcribe(ctx context.Context, name string, r io.Reader) (string, error)
+	// SubscribeTranscripts calls fn with each transcript record mode
+	// produces until the returned function is called.
+	SubscribeTranscripts(fn func(text string, at time.Time, latency time.Duration)) func()
+}
+
+// Server implements sttpb.STTServer on top of a Backend.
+type Server struct {
+	sttpb.UnimplementedSTTServer
+	Backend Backend
+}
+
+// Listen serves b on addr until the returned function is called. Calls
+// must carry token in the "authorization" metadata as "Bearer <token>".
+func Listen(addr, token string, b Backend) (func(), error) {
+	if err := httpapi.CheckAddr(addr); err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	srv := newServer(token, b)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			fmt.Printf("[grpc] %s\n", i18n.Sprintf("serve failed: %v", err))
+		}
+	}()
+	return func() {
+		// Transcript streams only end when the client cancels, so a
+		// graceful stop gets a few seconds before the rest are cut off.
+		stopped := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			srv.Stop()
+		}
+		<-done
+	}, nil
+}
+
+// newServer returns a gRPC server for b that checks token on every call.
+func newServer(token string, b Backend) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxMessage),
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := checkToken(ctx, token); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := checkToken(ss.Context(), token); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	)
+	sttpb.RegisterSTTServer(srv, &Server{Backend: b})
+	return srv
+}
+
+// checkToken accepts "authorization: Bearer <token>" or "x-stt-token".
+func checkToken(ctx context.Context, token string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	got := ""
+	if v := md.Get("authorization"); len(v) > 0 {
+		got, _ = strings.CutPrefix(v[0], "Bearer ")
+	} else if v := md.Get("x-stt-token"); len(v) > 0 {
+		got = v[0]
+	}
+	if token == "" || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(got)), []byte(token)) != 1 {
+		return status.Error(codes.Unauthenticated, "missing or wrong token")
+	}
+	return nil
+}
+
+func (s *Server) GetStatus(context.Context, *sttpb.GetStatusRequest) (*sttpb.Status, error) {
+	return s.status()
+}
+
+func (s *Server) Start(context.Context, *sttpb.StartRequest) (*sttpb.Status, error) {
+	return s.control("start")
+}
+
+func (s *Server) Stop(context.Context, *sttpb.StopRequest) (*sttpb.Status, error) {
+	return s.control("stop")
+}
+
+func (s *Server) control(request string) (*sttpb.Status, error) {
+	if _, err := s.Backend.Control(request); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return s.status()
+}
+
+func (s *Server) status() (*sttpb.Status, error) {
+	body, err := s.Backend.Control("status")
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	// The status JSON uses the proto field names, so it decodes directly.
+	st := &sttpb.Status{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal([]byte(body), st); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return st, nil
+}
+
+func (s *Server) TranscribeFile(ctx context.Context, req *sttpb.TranscribeFileRequest) (*sttpb.TranscribeFileResponse, error) {
+	var (
+		name  string
+		audio io.Reader
+	)
+	switch src := req.GetSource().(type) {
+	case *sttpb.TranscribeFileRequest_Path:
+		f, err := os.Open(src.Path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, status.Error(codes.NotFound, err.Error())
+			}
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		defer f.Close()
+		name, audio = src.Path, f
+	case *sttpb.TranscribeFileRequest_Audio:
+		name, audio = req.GetName(), bytes.NewReader(src.Audio)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "path or audio is required")
+	}
+	text, err := s.Backend.Transcribe(ctx, name, audio)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &sttpb.TranscribeFileResponse{Text: text}, nil
+}
+
+func (s *Server) StreamTranscripts(_ *sttpb.StreamTranscriptsRequest, stream grpc.ServerStreamingServer[sttpb.Transcript]) error {
+	ch := make(chan *sttpb.Transcript, streamBuffer)
+	unsubscribe := s.Backend.SubscribeTranscripts(func(text string, at time.Time, latency time.Duration) {
+		t := &sttpb.Transcript{Text: text, Time: timestamppb.New(at), LatencySeconds: latency.Seconds()}
+		select {
+		case ch <- t:
+		default:
+			fmt.Println("[grpc] " + i18n.T("transcript stream is behind; dropping a transcript"))
+		}
+	})
+	defer unsubscribe()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case t := <-ch:
+			if err := stream.Send(t); err != nil {
+				return err
+			}
+		}
+	}
+}
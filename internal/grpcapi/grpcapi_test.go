@@ -0,0 +1,133 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"stt/internal/grpcapi/sttpb"
+)
+
+type fakeBackend struct {
+	mu     sync.Mutex
+	upload string
+	subs   []func(string, time.Time, time.Duration)
+	subbed chan struct{}
+}
+
+func (b *fakeBackend) Control(request string) (string, error) {
+	switch request {
+	case "status":
+		return `{"state":"Idle","elapsed_seconds":1.5,"profile":"work"}`, nil
+	case "stop":
+		return "", errors.New("not recording")
+	}
+	return "", nil
+}
+
+func (b *fakeBackend) Transcribe(ctx context.Context, name string, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	b.upload = name + ":" + string(data)
+	return "hello", err
+}
+
+func (b *fakeBackend) SubscribeTranscripts(fn func(string, time.Time, time.Duration)) func() {
+	b.mu.Lock()
+	b.subs = append(b.subs, fn)
+	b.mu.Unlock()
+	close(b.subbed)
+	return func() {}
+}
+
+func dial(t *testing.T, b Backend) sttpb.STTClient {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	srv := newServer("secret", b)
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return sttpb.NewSTTClient(conn)
+}
+
+func authed() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+}
+
+func TestServerChecksTokenAndControls(t *testing.T) {
+	c := dial(t, &fakeBackend{})
+
+	if _, err := c.GetStatus(context.Background(), &sttpb.GetStatusRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("no token: err = %v, want Unauthenticated", err)
+	}
+	st, err := c.GetStatus(authed(), &sttpb.GetStatusRequest{})
+	if err != nil || st.GetState() != "Idle" || st.GetElapsedSeconds() != 1.5 || st.GetProfile() != "work" {
+		t.Fatalf("GetStatus = %v, %v", st, err)
+	}
+	if _, err := c.Start(authed(), &sttpb.StartRequest{}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := c.Stop(authed(), &sttpb.StopRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Stop: err = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestServerTranscribesAndStreams(t *testing.T) {
+	b := &fakeBackend{subbed: make(chan struct{})}
+	c := dial(t, b)
+
+	resp, err := c.TranscribeFile(authed(), &sttpb.TranscribeFileRequest{Source: &sttpb.TranscribeFileRequest_Audio{Audio: []byte("audio")}, Name: "clip.ogg"})
+	if err != nil || resp.GetText() != "hello" || b.upload != "clip.ogg:audio" {
+		t.Fatalf("TranscribeFile = %v, %v (upload %q)", resp, err, b.upload)
+	}
+	if _, err := c.TranscribeFile(authed(), &sttpb.TranscribeFileRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("empty TranscribeFile: err = %v, want InvalidArgument", err)
+	}
+
+	ctx, cancel := context.WithCancel(authed())
+	defer cancel()
+	stream, err := c.StreamTranscripts(ctx, &sttpb.StreamTranscriptsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-b.subbed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream did not subscribe")
+	}
+	b.mu.Lock()
+	b.subs[0]("dictated", time.Now(), 2*time.Second)
+	b.mu.Unlock()
+	tr, err := stream.Recv()
+	if err != nil || tr.GetText() != "dictated" || tr.GetLatencySeconds() != 2 {
+		t.Fatalf("Recv = %v, %v", tr, err)
+	}
+}
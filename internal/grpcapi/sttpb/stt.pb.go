@@ -0,0 +1,576 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// The local gRPC API of STT for Windows, served on GRPC_API. Every call needs
+// the API token in the "authorization" metadata as "Bearer <token>".
+//
+// Regenerate the Go code with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	    --go-grpc_out=. --go-grpc_opt=paths=source_relative stt.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: stt.proto
+
+package sttpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_stt_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stt_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_stt_proto_rawDescGZIP(), []int{0}
+}
+
+type StartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartRequest) Reset() {
+	*x = StartRequest{}
+	mi := &file_stt_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRequest) ProtoMessage() {}
+
+func (x *StartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stt_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRequest.ProtoReflect.Descriptor instead.
+func (*StartRequest) Descriptor() ([]byte, []int) {
+	return file_stt_proto_rawDescGZIP(), []int{1}
+}
+
+type StopRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopRequest) Reset() {
+	*x = StopRequest{}
+	mi := &file_stt_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopRequest) ProtoMessage() {}
+
+func (x *StopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stt_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopRequest.ProtoReflect.Descriptor instead.
+func (*StopRequest) Descriptor() ([]byte, []int) {
+	return file_stt_proto_rawDescGZIP(), []int{2}
+}
+
+type Status struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of "Idle", "Recording", "Paused", "Uploading" and "Error".
+	State   string `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Error   string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// Time recorded so far while recording or paused, not counting pauses.
+	ElapsedSeconds float64 `protobuf:"fixed64,4,opt,name=elapsed_seconds,json=elapsedSeconds,proto3" json:"elapsed_seconds,omitempty"`
+	Profile        string  `protobuf:"bytes,5,opt,name=profile,proto3" json:"profile,omitempty"`
+	// How long the last successful transcription took, or 0.
+	LatencySeconds float64 `protobuf:"fixed64,6,opt,name=latency_seconds,json=latencySeconds,proto3" json:"latency_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Status) Reset() {
+	*x = Status{}
+	mi := &file_stt_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Status) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Status) ProtoMessage() {}
+
+func (x *Status) ProtoReflect() protoreflect.Message {
+	mi := &file_stt_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Status.ProtoReflect.Descriptor instead.
+func (*Status) Descriptor() ([]byte, []int) {
+	return file_stt_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Status) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Status) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Status) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Status) GetElapsedSeconds() float64 {
+	if x != nil {
+		return x.ElapsedSeconds
+	}
+	return 0
+}
+
+func (x *Status) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *Status) GetLatencySeconds() float64 {
+	if x != nil {
+		return x.LatencySeconds
+	}
+	return 0
+}
+
+type TranscribeFileRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Source:
+	//
+	//	*TranscribeFileRequest_Path
+	//	*TranscribeFileRequest_Audio
+	Source isTranscribeFileRequest_Source `protobuf_oneof:"source"`
+	// File name of audio, whose extension hints at the format.
+	Name          string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranscribeFileRequest) Reset() {
+	*x = TranscribeFileRequest{}
+	mi := &file_stt_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranscribeFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscribeFileRequest) ProtoMessage() {}
+
+func (x *TranscribeFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stt_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscribeFileRequest.ProtoReflect.Descriptor instead.
+func (*TranscribeFileRequest) Descriptor() ([]byte, []int) {
+	return file_stt_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TranscribeFileRequest) GetSource() isTranscribeFileRequest_Source {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *TranscribeFileRequest) GetPath() string {
+	if x != nil {
+		if x, ok := x.Source.(*TranscribeFileRequest_Path); ok {
+			return x.Path
+		}
+	}
+	return ""
+}
+
+func (x *TranscribeFileRequest) GetAudio() []byte {
+	if x != nil {
+		if x, ok := x.Source.(*TranscribeFileRequest_Audio); ok {
+			return x.Audio
+		}
+	}
+	return nil
+}
+
+func (x *TranscribeFileRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type isTranscribeFileRequest_Source interface {
+	isTranscribeFileRequest_Source()
+}
+
+type TranscribeFileRequest_Path struct {
+	// Path of an audio file on this computer.
+	Path string `protobuf:"bytes,1,opt,name=path,proto3,oneof"`
+}
+
+type TranscribeFileRequest_Audio struct {
+	// The audio itself.
+	Audio []byte `protobuf:"bytes,2,opt,name=audio,proto3,oneof"`
+}
+
+func (*TranscribeFileRequest_Path) isTranscribeFileRequest_Source() {}
+
+func (*TranscribeFileRequest_Audio) isTranscribeFileRequest_Source() {}
+
+type TranscribeFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranscribeFileResponse) Reset() {
+	*x = TranscribeFileResponse{}
+	mi := &file_stt_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranscribeFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscribeFileResponse) ProtoMessage() {}
+
+func (x *TranscribeFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stt_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscribeFileResponse.ProtoReflect.Descriptor instead.
+func (*TranscribeFileResponse) Descriptor() ([]byte, []int) {
+	return file_stt_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TranscribeFileResponse) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type StreamTranscriptsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTranscriptsRequest) Reset() {
+	*x = StreamTranscriptsRequest{}
+	mi := &file_stt_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTranscriptsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTranscriptsRequest) ProtoMessage() {}
+
+func (x *StreamTranscriptsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stt_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTranscriptsRequest.ProtoReflect.Descriptor instead.
+func (*StreamTranscriptsRequest) Descriptor() ([]byte, []int) {
+	return file_stt_proto_rawDescGZIP(), []int{6}
+}
+
+type Transcript struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Text           string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Time           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	LatencySeconds float64                `protobuf:"fixed64,3,opt,name=latency_seconds,json=latencySeconds,proto3" json:"latency_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Transcript) Reset() {
+	*x = Transcript{}
+	mi := &file_stt_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transcript) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transcript) ProtoMessage() {}
+
+func (x *Transcript) ProtoReflect() protoreflect.Message {
+	mi := &file_stt_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transcript.ProtoReflect.Descriptor instead.
+func (*Transcript) Descriptor() ([]byte, []int) {
+	return file_stt_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Transcript) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Transcript) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Transcript) GetLatencySeconds() float64 {
+	if x != nil {
+		return x.LatencySeconds
+	}
+	return 0
+}
+
+var File_stt_proto protoreflect.FileDescriptor
+
+const file_stt_proto_rawDesc = "" +
+	"\n" +
+	"\tstt.proto\x12\x06stt.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x12\n" +
+	"\x10GetStatusRequest\"\x0e\n" +
+	"\fStartRequest\"\r\n" +
+	"\vStopRequest\"\xba\x01\n" +
+	"\x06Status\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12'\n" +
+	"\x0felapsed_seconds\x18\x04 \x01(\x01R\x0eelapsedSeconds\x12\x18\n" +
+	"\aprofile\x18\x05 \x01(\tR\aprofile\x12'\n" +
+	"\x0flatency_seconds\x18\x06 \x01(\x01R\x0elatencySeconds\"c\n" +
+	"\x15TranscribeFileRequest\x12\x14\n" +
+	"\x04path\x18\x01 \x01(\tH\x00R\x04path\x12\x16\n" +
+	"\x05audio\x18\x02 \x01(\fH\x00R\x05audio\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04nameB\b\n" +
+	"\x06source\",\n" +
+	"\x16TranscribeFileResponse\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\"\x1a\n" +
+	"\x18StreamTranscriptsRequest\"y\n" +
+	"\n" +
+	"Transcript\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12'\n" +
+	"\x0flatency_seconds\x18\x03 \x01(\x01R\x0elatencySeconds2\xb6\x02\n" +
+	"\x03STT\x125\n" +
+	"\tGetStatus\x12\x18.stt.v1.GetStatusRequest\x1a\x0e.stt.v1.Status\x12-\n" +
+	"\x05Start\x12\x14.stt.v1.StartRequest\x1a\x0e.stt.v1.Status\x12+\n" +
+	"\x04Stop\x12\x13.stt.v1.StopRequest\x1a\x0e.stt.v1.Status\x12O\n" +
+	"\x0eTranscribeFile\x12\x1d.stt.v1.TranscribeFileRequest\x1a\x1e.stt.v1.TranscribeFileResponse\x12K\n" +
+	"\x11StreamTranscripts\x12 .stt.v1.StreamTranscriptsRequest\x1a\x12.stt.v1.Transcript0\x01B\x1cZ\x1astt/internal/grpcapi/sttpbb\x06proto3"
+
+var (
+	file_stt_proto_rawDescOnce sync.Once
+	file_stt_proto_rawDescData []byte
+)
+
+func file_stt_proto_rawDescGZIP() []byte {
+	file_stt_proto_rawDescOnce.Do(func() {
+		file_stt_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_stt_proto_rawDesc), len(file_stt_proto_rawDesc)))
+	})
+	return file_stt_proto_rawDescData
+}
+
+var file_stt_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_stt_proto_goTypes = []any{
+	(*GetStatusRequest)(nil),         // 0: stt.v1.GetStatusRequest
+	(*StartRequest)(nil),             // 1: stt.v1.StartRequest
+	(*StopRequest)(nil),              // 2: stt.v1.StopRequest
+	(*Status)(nil),                   // 3: stt.v1.Status
+	(*TranscribeFileRequest)(nil),    // 4: stt.v1.TranscribeFileRequest
+	(*TranscribeFileResponse)(nil),   // 5: stt.v1.TranscribeFileResponse
+	(*StreamTranscriptsRequest)(nil), // 6: stt.v1.StreamTranscriptsRequest
+	(*Transcript)(nil),               // 7: stt.v1.Transcript
+	(*timestamppb.Timestamp)(nil),    // 8: google.protobuf.Timestamp
+}
+var file_stt_proto_depIdxs = []int32{
+	8, // 0: stt.v1.Transcript.time:type_name -> google.protobuf.Timestamp
+	0, // 1: stt.v1.STT.GetStatus:input_type -> stt.v1.GetStatusRequest
+	1, // 2: stt.v1.STT.Start:input_type -> stt.v1.StartRequest
+	2, // 3: stt.v1.STT.Stop:input_type -> stt.v1.StopRequest
+	4, // 4: stt.v1.STT.TranscribeFile:input_type -> stt.v1.TranscribeFileRequest
+	6, // 5: stt.v1.STT.StreamTranscripts:input_type -> stt.v1.StreamTranscriptsRequest
+	3, // 6: stt.v1.STT.GetStatus:output_type -> stt.v1.Status
+	3, // 7: stt.v1.STT.Start:output_type -> stt.v1.Status
+	3, // 8: stt.v1.STT.Stop:output_type -> stt.v1.Status
+	5, // 9: stt.v1.STT.TranscribeFile:output_type -> stt.v1.TranscribeFileResponse
+	7, // 10: stt.v1.STT.StreamTranscripts:output_type -> stt.v1.Transcript
+	6, // [6:11] is the sub-list for method output_type
+	1, // [1:6] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_stt_proto_init() }
+func file_stt_proto_init() {
+	if File_stt_proto != nil {
+		return
+	}
+	file_stt_proto_msgTypes[4].OneofWrappers = []any{
+		(*TranscribeFileRequest_Path)(nil),
+		(*TranscribeFileRequest_Audio)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_stt_proto_rawDesc), len(file_stt_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_stt_proto_goTypes,
+		DependencyIndexes: file_stt_proto_depIdxs,
+		MessageInfos:      file_stt_proto_msgTypes,
+	}.Build()
+	File_stt_proto = out.File
+	file_stt_proto_goTypes = nil
+	file_stt_proto_depIdxs = nil
+}
@@ -0,0 +1,314 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// The local gRPC API of STT for Windows, served on GRPC_API. Every call needs
+// the API token in the "authorization" metadata as "Bearer <token>".
+//
+// Regenerate the Go code with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	    --go-grpc_out=. --go-grpc_opt=paths=source_relative stt.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: stt.proto
+
+package sttpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	STT_GetStatus_FullMethodName         = "/stt.v1.STT/GetStatus"
+	STT_Start_FullMethodName             = "/stt.v1.STT/Start"
+	STT_Stop_FullMethodName              = "/stt.v1.STT/Stop"
+	STT_TranscribeFile_FullMethodName    = "/stt.v1.STT/TranscribeFile"
+	STT_StreamTranscripts_FullMethodName = "/stt.v1.STT/StreamTranscripts"
+)
+
+// STTClient is the client API for STT service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type STTClient interface {
+	// GetStatus returns the record-mode state.
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Status, error)
+	// Start starts recording, like the start hotkey. It fails with
+	// FAILED_PRECONDITION unless the runtime is idle.
+	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*Status, error)
+	// Stop stops recording and uploads it, like the start hotkey. It fails
+	// with FAILED_PRECONDITION when nothing is being recorded.
+	Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*Status, error)
+	// TranscribeFile transcribes an audio file like `stt transcribe` and
+	// returns the text.
+	TranscribeFile(ctx context.Context, in *TranscribeFileRequest, opts ...grpc.CallOption) (*TranscribeFileResponse, error)
+	// StreamTranscripts sends each transcript record mode produces from now
+	// on, until the client cancels.
+	StreamTranscripts(ctx context.Context, in *StreamTranscriptsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Transcript], error)
+}
+
+type sTTClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSTTClient(cc grpc.ClientConnInterface) STTClient {
+	return &sTTClient{cc}
+}
+
+func (c *sTTClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Status, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Status)
+	err := c.cc.Invoke(ctx, STT_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sTTClient) Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*Status, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Status)
+	err := c.cc.Invoke(ctx, STT_Start_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sTTClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*Status, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Status)
+	err := c.cc.Invoke(ctx, STT_Stop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sTTClient) TranscribeFile(ctx context.Context, in *TranscribeFileRequest, opts ...grpc.CallOption) (*TranscribeFileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TranscribeFileResponse)
+	err := c.cc.Invoke(ctx, STT_TranscribeFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sTTClient) StreamTranscripts(ctx context.Context, in *StreamTranscriptsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Transcript], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &STT_ServiceDesc.Streams[0], STT_StreamTranscripts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamTranscriptsRequest, Transcript]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type STT_StreamTranscriptsClient = grpc.ServerStreamingClient[Transcript]
+
+// STTServer is the server API for STT service.
+// All implementations must embed UnimplementedSTTServer
+// for forward compatibility.
+type STTServer interface {
+	// GetStatus returns the record-mode state.
+	GetStatus(context.Context, *GetStatusRequest) (*Status, error)
+	// Start starts recording, like the start hotkey. It fails with
+	// FAILED_PRECONDITION unless the runtime is idle.
+	Start(context.Context, *StartRequest) (*Status, error)
+	// Stop stops recording and uploads it, like the start hotkey. It fails
+	// with FAILED_PRECONDITION when nothing is being recorded.
+	Stop(context.Context, *StopRequest) (*Status, error)
+	// TranscribeFile transcribes an audio file like `stt transcribe` and
+	// returns the text.
+	TranscribeFile(context.Context, *TranscribeFileRequest) (*TranscribeFileResponse, error)
+	// StreamTranscripts sends each transcript record mode produces from now
+	// on, until the client cancels.
+	StreamTranscripts(*StreamTranscriptsRequest, grpc.ServerStreamingServer[Transcript]) error
+	mustEmbedUnimplementedSTTServer()
+}
+
+// UnimplementedSTTServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSTTServer struct{}
+
+func (UnimplementedSTTServer) GetStatus(context.Context, *GetStatusRequest) (*Status, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedSTTServer) Start(context.Context, *StartRequest) (*Status, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Start not implemented")
+}
+func (UnimplementedSTTServer) Stop(context.Context, *StopRequest) (*Status, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
+}
+func (UnimplementedSTTServer) TranscribeFile(context.Context, *TranscribeFileRequest) (*TranscribeFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TranscribeFile not implemented")
+}
+func (UnimplementedSTTServer) StreamTranscripts(*StreamTranscriptsRequest, grpc.ServerStreamingServer[Transcript]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTranscripts not implemented")
+}
+func (UnimplementedSTTServer) mustEmbedUnimplementedSTTServer() {}
+func (UnimplementedSTTServer) testEmbeddedByValue()             {}
+
+// UnsafeSTTServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to STTServer will
+// result in compilation errors.
+type UnsafeSTTServer interface {
+	mustEmbedUnimplementedSTTServer()
+}
+
+func RegisterSTTServer(s grpc.ServiceRegistrar, srv STTServer) {
+	// If the following call pancis, it indicates UnimplementedSTTServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&STT_ServiceDesc, srv)
+}
+
+func _STT_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(STTServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: STT_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(STTServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _STT_Start_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(STTServer).Start(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: STT_Start_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(STTServer).Start(ctx, req.(*StartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _STT_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(STTServer).Stop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: STT_Stop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(STTServer).Stop(ctx, req.(*StopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _STT_TranscribeFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TranscribeFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(STTServer).TranscribeFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: STT_TranscribeFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(STTServer).TranscribeFile(ctx, req.(*TranscribeFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _STT_StreamTranscripts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTranscriptsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(STTServer).StreamTranscripts(m, &grpc.GenericServerStream[StreamTranscriptsRequest, Transcript]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type STT_StreamTranscriptsServer = grpc.ServerStreamingServer[Transcript]
+
+// STT_ServiceDesc is the grpc.ServiceDesc for STT service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var STT_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "stt.v1.STT",
+	HandlerType: (*STTServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _STT_GetStatus_Handler,
+		},
+		{
+			MethodName: "Start",
+			Handler:    _STT_Start_Handler,
+		},
+		{
+			MethodName: "Stop",
+			Handler:    _STT_Stop_Handler,
+		},
+		{
+			MethodName: "TranscribeFile",
+			Handler:    _STT_TranscribeFile_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamTranscripts",
+			Handler:       _STT_StreamTranscripts_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "stt.proto",
+}
@@ -0,0 +1,365 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package history stores transcription records in a SQLite database under
+// the cache directory.
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"stt/internal/cachecrypt"
+)
+
+// FileName is the database file created inside the cache directory.
+const FileName = "history.db"
+
+// Status values recorded for each transcription attempt.
+const (
+	StatusOK     = "ok"
+	StatusEmpty  = "empty"
+	StatusFailed = "failed"
+)
+
+// Entry is one transcription attempt.
+type Entry struct {
+	ID        int64
+	CreatedAt time.Time
+	Source    string // "record", "file", "queue", or "history"
+	Duration  time.Duration
+	Provider  string
+	Model     string
+	Language  string
+	Machine   string // MACHINE_ID or host name of the computer that transcribed it
+	Text      string
+	Latency   time.Duration // upload time, retries included
+	AudioPath string
+	Status    string
+	Error     string
+	Response  []byte
+	// StopTime, ConvertTime and PasteTime are how long finishing the
+	// recording after it was stopped, converting it and pasting the text
+	// took, or 0 for steps that did not happen or were not measured.
+	StopTime    time.Duration
+	ConvertTime time.Duration
+	PasteTime   time.Duration
+}
+
+// EncryptedText replaces the text of encrypted entries when the store has no
+// cipher to open them.
+const EncryptedText = "[encrypted]"
+
+// Store is an open history database.
+type Store struct {
+	db     *sql.DB
+	cipher *cachecrypt.Cipher
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS transcripts (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	created_at  TEXT    NOT NULL,
+	source      TEXT    NOT NULL DEFAULT '',
+	duration_ms INTEGER NOT NULL DEFAULT 0,
+	provider    TEXT    NOT NULL DEFAULT '',
+	model       TEXT    NOT NULL DEFAULT '',
+	language    TEXT    NOT NULL DEFAULT '',
+	text        TEXT    NOT NULL DEFAULT '',
+	latency_ms  INTEGER NOT NULL DEFAULT 0,
+	audio_path  TEXT    NOT NULL DEFAULT '',
+	status      TEXT    NOT NULL DEFAULT '',
+	error       TEXT    NOT NULL DEFAULT '',
+	response    BLOB,
+	machine     TEXT    NOT NULL DEFAULT '',
+	stop_ms     INTEGER NOT NULL DEFAULT 0,
+	convert_ms  INTEGER NOT NULL DEFAULT 0,
+	paste_ms    INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS transcripts_created_at ON transcripts(created_at);
+`
+
+// migrations add columns that databases created by older versions lack.
+var migrations = []struct{ column, ddl string }{
+	{"machine", `ALTER TABLE transcripts ADD COLUMN machine TEXT NOT NULL DEFAULT ''`},
+	{"stop_ms", `ALTER TABLE transcripts ADD COLUMN stop_ms INTEGER NOT NULL DEFAULT 0`},
+	{"convert_ms", `ALTER TABLE transcripts ADD COLUMN convert_ms INTEGER NOT NULL DEFAULT 0`},
+	{"paste_ms", `ALTER TABLE transcripts ADD COLUMN paste_ms INTEGER NOT NULL DEFAULT 0`},
+}
+
+// timeLayout is a fixed-width RFC 3339 layout so created_at sorts and compares
+// correctly as text.
+const timeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// Path returns the database path inside cacheDir.
+func Path(cacheDir string) string {
+	return filepath.Join(cacheDir, FileName)
+}
+
+// Open opens or creates the database at path and applies the schema.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", "file:"+filepath.ToSlash(path)+"?_busy_timeout=5000&_journal_mode=WAL&_synchronous=FULL")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("init history db '%s': %w", path, err)
+	}
+	if err := migrate(db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("migrate history db '%s': %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+func migrate(db *sql.DB) error {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info('transcripts')`)
+	if err != nil {
+		return err
+	}
+	have := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		have[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	// stop_ms was called record_ms, which read as the recording length.
+	if have["record_ms"] && !have["stop_ms"] {
+		if _, err := db.Exec(`ALTER TABLE transcripts RENAME COLUMN record_ms TO stop_ms`); err != nil {
+			return err
+		}
+		have["stop_ms"] = true
+	}
+	for _, m := range migrations {
+		if have[m.column] {
+			continue
+		}
+		if _, err := db.Exec(m.ddl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetCipher makes the store encrypt the text and response of new entries and
+// decrypt them on read. Entries written without a cipher stay readable.
+func (s *Store) SetCipher(c *cachecrypt.Cipher) {
+	s.cipher = c
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Add inserts e and returns its row ID. A zero CreatedAt is set to now.
+func (s *Store) Add(e Entry) (int64, error) {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	var text any = e.Text
+	if s.cipher != nil {
+		sealed, err := s.cipher.Seal([]byte(e.Text))
+		if err != nil {
+			return 0, err
+		}
+		text = sealed
+		if len(e.Response) > 0 {
+			if e.Response, err = s.cipher.Seal(e.Response); err != nil {
+				return 0, err
+			}
+		}
+	}
+	res, err := s.db.Exec(`INSERT INTO transcripts
+		(created_at, source, duration_ms, provider, model, language, machine, text, latency_ms, audio_path, status, error, response, stop_ms, convert_ms, paste_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.CreatedAt.UTC().Format(timeLayout), e.Source, e.Duration.Milliseconds(), e.Provider, e.Model,
+		e.Language, e.Machine, text, e.Latency.Milliseconds(), e.AudioPath, e.Status, e.Error, e.Response,
+		e.StopTime.Milliseconds(), e.ConvertTime.Milliseconds(), e.PasteTime.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// RelocateAudio points entries whose audio path is oldPath at newPath, e.g.
+// after the file was moved into an archive.
+func (s *Store) RelocateAudio(oldPath, newPath string) error {
+	_, err := s.db.Exec(`UPDATE transcripts SET audio_path = ? WHERE audio_path = ?`, newPath, oldPath)
+	return err
+}
+
+// Transcribed reports whether a file transcription of the audio at
+// audioPath succeeded, with or without speech in it.
+func (s *Store) Transcribed(audioPath string) (bool, error) {
+	var found bool
+	err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM transcripts WHERE audio_path = ? AND source = 'file' AND status IN (?, ?))`,
+		audioPath, StatusOK, StatusEmpty).Scan(&found)
+	return found, err
+}
+
+// Get returns the entry with the given ID.
+func (s *Store) Get(id int64) (Entry, error) {
+	row := s.db.QueryRow(`SELECT `+columns+` FROM transcripts WHERE id = ?`, id)
+	return s.scanEntry(row)
+}
+
+// Recent returns up to limit entries, newest first.
+func (s *Store) Recent(limit int) ([]Entry, error) {
+	return s.query(`SELECT `+columns+` FROM transcripts ORDER BY created_at DESC, id DESC LIMIT ?`, limit)
+}
+
+// Query filters a Search. Zero fields are ignored.
+type Query struct {
+	Text  string    // whitespace-separated terms, all of which must appear
+	Since time.Time // inclusive lower bound on CreatedAt
+	Until time.Time // exclusive upper bound on CreatedAt
+	Limit int       // maximum number of entries; <= 0 means no limit
+}
+
+// Search returns entries matching q, newest first. Terms are matched as
+// substrings of the transcript text (case-insensitive for ASCII) rather than
+// through an FTS tokenizer, which cannot split CJK text into words. With a
+// cipher set, text matching happens after decryption.
+func (s *Store) Search(q Query) ([]Entry, error) {
+	terms := strings.Fields(q.Text)
+	if s.cipher != nil && len(terms) > 0 {
+		all, err := s.Search(Query{Since: q.Since, Until: q.Until})
+		if err != nil {
+			return nil, err
+		}
+		var out []Entry
+		for _, e := range all {
+			if containsAll(e.Text, terms) {
+				out = append(out, e)
+				if q.Limit > 0 && len(out) == q.Limit {
+					break
+				}
+			}
+		}
+		return out, nil
+	}
+
+	var where []string
+	var args []any
+	for _, term := range terms {
+		where = append(where, `text LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(term)+"%")
+	}
+	if !q.Since.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, q.Since.UTC().Format(timeLayout))
+	}
+	if !q.Until.IsZero() {
+		where = append(where, "created_at < ?")
+		args = append(args, q.Until.UTC().Format(timeLayout))
+	}
+	stmt := `SELECT ` + columns + ` FROM transcripts`
+	if len(where) > 0 {
+		stmt += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	stmt += ` ORDER BY created_at DESC, id DESC`
+	if q.Limit > 0 {
+		stmt += ` LIMIT ?`
+		args = append(args, q.Limit)
+	}
+	return s.query(stmt, args...)
+}
+
+func (s *Store) query(stmt string, args ...any) ([]Entry, error) {
+	rows, err := s.db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Entry
+	for rows.Next() {
+		e, err := s.scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func containsAll(text string, terms []string) bool {
+	text = strings.ToLower(text)
+	for _, term := range terms {
+		if !strings.Contains(text, strings.ToLower(term)) {
+			return false
+		}
+	}
+	return true
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+const columns = `id, created_at, source, duration_ms, provider, model, language, machine, text, latency_ms, audio_path, status, error, response, stop_ms, convert_ms, paste_ms`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func (s *Store) scanEntry(row scanner) (Entry, error) {
+	var e Entry
+	var text []byte
+	var createdAt string
+	var durationMS, latencyMS, stopMS, convertMS, pasteMS int64
+	if err := row.Scan(&e.ID, &createdAt, &e.Source, &durationMS, &e.Provider, &e.Model, &e.Language,
+		&e.Machine, &text, &latencyMS, &e.AudioPath, &e.Status, &e.Error, &e.Response,
+		&stopMS, &convertMS, &pasteMS); err != nil {
+		return Entry{}, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return Entry{}, fmt.Errorf("parse created_at %q: %w", createdAt, err)
+	}
+	e.CreatedAt = t.Local()
+	e.Duration = time.Duration(durationMS) * time.Millisecond
+	e.Latency = time.Duration(latencyMS) * time.Millisecond
+	e.StopTime = time.Duration(stopMS) * time.Millisecond
+	e.ConvertTime = time.Duration(convertMS) * time.Millisecond
+	e.PasteTime = time.Duration(pasteMS) * time.Millisecond
+	if !cachecrypt.IsSealed(text) {
+		e.Text = string(text)
+		return e, nil
+	}
+	if s.cipher == nil {
+		e.Text, e.Response = EncryptedText, nil
+		return e, nil
+	}
+	if text, err = s.cipher.Open(text); err != nil {
+		return Entry{}, fmt.Errorf("entry %d: %w", e.ID, err)
+	}
+	e.Text = string(text)
+	if cachecrypt.IsSealed(e.Response) {
+		if e.Response, err = s.cipher.Open(e.Response); err != nil {
+			return Entry{}, fmt.Errorf("entry %d: %w", e.ID, err)
+		}
+	}
+	return e, nil
+}
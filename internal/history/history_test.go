@@ -0,0 +1,239 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package history
+
+import (
+	"bytes"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"stt/internal/cachecrypt"
+)
+
+func TestAddAndReadBack(t *testing.T) {
+	store, err := Open(Path(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer store.Close()
+
+	first := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	id1, err := store.Add(Entry{CreatedAt: first, Source: "record", Duration: 1500 * time.Millisecond, Text: "hello", Status: StatusOK,
+		Latency: 900 * time.Millisecond, StopTime: 20 * time.Millisecond, ConvertTime: 150 * time.Millisecond, PasteTime: 40 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := store.Add(Entry{CreatedAt: first.Add(time.Minute), Source: "file", Status: StatusFailed, Error: "boom"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	got, err := store.Get(id1)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Text != "hello" || got.Duration != 1500*time.Millisecond || !got.CreatedAt.Equal(first) {
+		t.Fatalf("Get = %#v", got)
+	}
+	if got.Latency != 900*time.Millisecond || got.StopTime != 20*time.Millisecond || got.ConvertTime != 150*time.Millisecond || got.PasteTime != 40*time.Millisecond {
+		t.Fatalf("timings = %v/%v/%v/%v, want 20ms/150ms/900ms/40ms", got.StopTime, got.ConvertTime, got.Latency, got.PasteTime)
+	}
+
+	recent, err := store.Recent(10)
+	if err != nil {
+		t.Fatalf("Recent failed: %v", err)
+	}
+	if len(recent) != 2 || recent[0].Status != StatusFailed || recent[1].ID != id1 {
+		t.Fatalf("Recent = %#v, want newest first", recent)
+	}
+}
+
+func TestTranscribed(t *testing.T) {
+	store, err := Open(Path(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer store.Close()
+
+	for _, e := range []Entry{
+		{Source: "file", AudioPath: `D:\rec\a.m4a`, Status: StatusOK},
+		{Source: "file", AudioPath: `D:\rec\b.m4a`, Status: StatusFailed},
+		{Source: "record", AudioPath: `D:\rec\c.wav`, Status: StatusOK},
+	} {
+		if _, err := store.Add(e); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	for path, want := range map[string]bool{`D:\rec\a.m4a`: true, `D:\rec\b.m4a`: false, `D:\rec\c.wav`: false, `D:\rec\d.m4a`: false} {
+		if got, err := store.Transcribed(path); err != nil || got != want {
+			t.Fatalf("Transcribed(%s) = %v, %v; want %v", path, got, err, want)
+		}
+	}
+}
+
+func TestSearchFiltersByTextAndDate(t *testing.T) {
+	store, err := Open(Path(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer store.Close()
+
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, text := range []string{"Hello World", "今天天气很好", "hello again", "100% done_"} {
+		if _, err := store.Add(Entry{CreatedAt: day.Add(time.Duration(i) * 24 * time.Hour), Text: text, Status: StatusOK}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		q    Query
+		want []string
+	}{
+		{"all", Query{}, []string{"100% done_", "hello again", "今天天气很好", "Hello World"}},
+		{"case insensitive", Query{Text: "HELLO"}, []string{"hello again", "Hello World"}},
+		{"all terms", Query{Text: "hello world"}, []string{"Hello World"}},
+		{"cjk substring", Query{Text: "天气"}, []string{"今天天气很好"}},
+		{"literal wildcards", Query{Text: "0%"}, []string{"100% done_"}},
+		{"underscore", Query{Text: "e_"}, []string{"100% done_"}},
+		{"since", Query{Since: day.Add(48 * time.Hour)}, []string{"100% done_", "hello again"}},
+		{"until", Query{Until: day.Add(24 * time.Hour)}, []string{"Hello World"}},
+		{"limit", Query{Limit: 1}, []string{"100% done_"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Search(tt.q)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			var texts []string
+			for _, e := range got {
+				texts = append(texts, e.Text)
+			}
+			if strings.Join(texts, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("Search(%+v) = %q, want %q", tt.q, texts, tt.want)
+			}
+		})
+	}
+}
+
+func TestCipherEncryptsTextAndResponse(t *testing.T) {
+	dir := t.TempDir()
+	c, err := cachecrypt.Load(dir, cachecrypt.ModePassphrase, "pw")
+	if err != nil {
+		t.Fatalf("cachecrypt.Load failed: %v", err)
+	}
+	store, err := Open(Path(dir))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer store.Close()
+
+	if _, err := store.Add(Entry{Text: "plain before", Status: StatusOK}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	store.SetCipher(c)
+	id, err := store.Add(Entry{Text: "机密会议", Status: StatusOK, Response: []byte(`{"text":"机密会议"}`)})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	var rawText, rawResponse []byte
+	if err := store.db.QueryRow(`SELECT text, response FROM transcripts WHERE id = ?`, id).Scan(&rawText, &rawResponse); err != nil {
+		t.Fatalf("raw select failed: %v", err)
+	}
+	if bytes.Contains(rawText, []byte("机密")) || bytes.Contains(rawResponse, []byte("机密")) {
+		t.Fatalf("transcript stored in plain text: %q %q", rawText, rawResponse)
+	}
+
+	got, err := store.Search(Query{Text: "机密"})
+	if err != nil || len(got) != 1 || got[0].Text != "机密会议" || string(got[0].Response) != `{"text":"机密会议"}` {
+		t.Fatalf("Search = %#v, %v; want decrypted entry", got, err)
+	}
+	if all, err := store.Search(Query{}); err != nil || len(all) != 2 {
+		t.Fatalf("Search all = %d entries, %v; want plain and encrypted", len(all), err)
+	}
+
+	store.SetCipher(nil)
+	locked, err := store.Get(id)
+	if err != nil || locked.Text != EncryptedText || locked.Response != nil {
+		t.Fatalf("Get without cipher = %#v, %v; want placeholder", locked, err)
+	}
+}
+
+func TestOpenAddsNewColumnsToOldDatabase(t *testing.T) {
+	path := Path(t.TempDir())
+	db, err := sql.Open("sqlite3", "file:"+filepath.ToSlash(path))
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	old, _, found := strings.Cut(schema, ",\n\tmachine     TEXT    NOT NULL DEFAULT ''")
+	if !found {
+		t.Fatal("schema no longer has the machine column")
+	}
+	old += "\n);"
+	if _, err := db.Exec(old); err != nil {
+		t.Fatalf("create old schema: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO transcripts (created_at, text) VALUES (?, ?)`, time.Now().UTC().Format(timeLayout), "old"); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	_ = db.Close()
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer store.Close()
+	id, err := store.Add(Entry{Text: "new", Machine: "office-pc", PasteTime: time.Second})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	got, err := store.Get(id)
+	if err != nil || got.Machine != "office-pc" || got.PasteTime != time.Second {
+		t.Fatalf("Get = %#v, %v; want machine office-pc and a paste time", got, err)
+	}
+	if got, err := store.Get(1); err != nil || got.Text != "old" || got.Machine != "" {
+		t.Fatalf("Get(old) = %#v, %v", got, err)
+	}
+}
+
+func TestOpenRenamesRecordMS(t *testing.T) {
+	path := Path(t.TempDir())
+	db, err := sql.Open("sqlite3", "file:"+filepath.ToSlash(path))
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	old := strings.Replace(schema, "stop_ms     INTEGER", "record_ms   INTEGER", 1)
+	if old == schema {
+		t.Fatal("schema no longer has the stop_ms column")
+	}
+	if _, err := db.Exec(old); err != nil {
+		t.Fatalf("create old schema: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO transcripts (created_at, text, record_ms) VALUES (?, ?, ?)`, time.Now().UTC().Format(timeLayout), "old", 250); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	_ = db.Close()
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer store.Close()
+	if got, err := store.Get(1); err != nil || got.StopTime != 250*time.Millisecond {
+		t.Fatalf("Get(old) = %#v, %v; want the record_ms value as StopTime", got, err)
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package hotkey
+
+// Handlers get id 1, 2 and 3 for the start, pause and cancel hotkeys.
+// Where the platform reports key releases (the low-level hook on Windows and
+// /dev/input on Linux), they also get StartReleased when the key that fired
+// the start hotkey comes back up, which push-to-talk stops recording on.
+const StartReleased = 4
@@ -0,0 +1,78 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build darwin && cgo
+
+#include <ApplicationServices/ApplicationServices.h>
+#include <stdint.h>
+#include <stdlib.h>
+#include "_cgo_export.h"
+
+typedef struct {
+	uintptr_t handle;
+	CFMachPortRef tap;
+	CFRunLoopSourceRef source;
+} sttTap;
+
+static CGEventRef sttTapCallback(CGEventTapProxy proxy, CGEventType type, CGEventRef event, void *refcon) {
+	sttTap *t = refcon;
+	// macOS turns off a tap whose callback was slow; turn it back on.
+	if (type == kCGEventTapDisabledByTimeout || type == kCGEventTapDisabledByUserInput) {
+		CGEventTapEnable(t->tap, true);
+		return event;
+	}
+	if (type != kCGEventKeyDown || CGEventGetIntegerValueField(event, kCGKeyboardEventAutorepeat) != 0) {
+		return event;
+	}
+	int64_t code = CGEventGetIntegerValueField(event, kCGKeyboardEventKeycode);
+	if (sttHotkeyEvent(t->handle, (int)code, (uint64_t)CGEventGetFlags(event))) {
+		return NULL;
+	}
+	return event;
+}
+
+// sttTapCreate installs a keyboard event tap on the current thread's run
+// loop. It returns NULL when macOS refuses the tap, i.e. when the app lacks
+// the Input Monitoring (listenOnly) or Accessibility permission.
+void *sttTapCreate(uintptr_t handle, int listenOnly) {
+	sttTap *t = calloc(1, sizeof(sttTap));
+	if (t == NULL) {
+		return NULL;
+	}
+	t->handle = handle;
+	t->tap = CGEventTapCreate(kCGSessionEventTap, kCGHeadInsertEventTap,
+		listenOnly ? kCGEventTapOptionListenOnly : kCGEventTapOptionDefault,
+		CGEventMaskBit(kCGEventKeyDown), sttTapCallback, t);
+	if (t->tap == NULL) {
+		free(t);
+		return NULL;
+	}
+	t->source = CFMachPortCreateRunLoopSource(kCFAllocatorDefault, t->tap, 0);
+	CFRunLoopAddSource(CFRunLoopGetCurrent(), t->source, kCFRunLoopCommonModes);
+	CGEventTapEnable(t->tap, true);
+	return t;
+}
+
+// sttTapRunFor runs the current thread's run loop for up to seconds.
+void sttTapRunFor(double seconds) {
+	CFRunLoopRunInMode(kCFRunLoopDefaultMode, seconds, false);
+}
+
+// sttTapDestroy removes a tap created by sttTapCreate on the same thread.
+void sttTapDestroy(void *p) {
+	sttTap *t = p;
+	CGEventTapEnable(t->tap, false);
+	CFRunLoopRemoveSource(CFRunLoopGetCurrent(), t->source, kCFRunLoopCommonModes);
+	CFRelease(t->source);
+	CFMachPortInvalidate(t->tap);
+	CFRelease(t->tap);
+	free(t);
+}
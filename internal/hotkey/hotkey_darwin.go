@@ -0,0 +1,140 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build darwin && cgo
+
+package hotkey
+
+/*
+#cgo LDFLAGS: -framework ApplicationServices -framework CoreFoundation
+#include <stdint.h>
+void *sttTapCreate(uintptr_t handle, int listenOnly);
+void sttTapRunFor(double seconds);
+void sttTapDestroy(void *tap);
+*/
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/cgo"
+	"sync"
+	"sync/atomic"
+)
+
+// Registration represents a registered hotkey set.
+type Registration struct {
+	once sync.Once
+	stop func()
+}
+
+// Stop releases registered hotkeys.
+func (r *Registration) Stop() {
+	if r == nil {
+		return
+	}
+	r.once.Do(func() {
+		if r.stop != nil {
+			r.stop()
+		}
+	})
+}
+
+// Releases reports whether handlers get StartReleased, which push-to-talk
+// needs to stop recording. The event tap only watches key presses.
+func (r *Registration) Releases() bool {
+	return false
+}
+
+// Register installs hotkeys and wires them to handler.
+func Register(startKey, pauseKey, cancelKey string, hook bool, handler func(id int), debug bool) error {
+	_, err := RegisterWithStop(startKey, pauseKey, cancelKey, hook, handler, debug)
+	return err
+}
+
+// RegisterWithStop installs hotkeys and returns a handle that can unregister them.
+//
+// Keys are watched with a CGEvent tap. With hook set the tap also swallows
+// the hotkeys so the frontmost app never sees them, which needs the
+// Accessibility permission; otherwise it only listens, which needs Input
+// Monitoring.
+func RegisterWithStop(startKey, pauseKey, cancelKey string, hook bool, handler func(id int), debug bool) (*Registration, error) {
+	bindings, err := parseMacHotkeys(startKey, pauseKey, cancelKey)
+	if err != nil {
+		return nil, err
+	}
+	if debug {
+		for i, spec := range []string{startKey, pauseKey, cancelKey} {
+			fmt.Printf("[hotkey-debug] parsed '%s' -> mod=0x%X keycode=0x%X\n", spec, bindings[i].mod, bindings[i].code)
+		}
+	}
+
+	h := cgo.NewHandle(&tapState{bindings: bindings, handler: handler, swallow: hook, debug: debug})
+	var stopped atomic.Bool
+	resultCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer h.Delete()
+
+		listenOnly := C.int(1)
+		if hook {
+			listenOnly = 0
+		}
+		tap := C.sttTapCreate(C.uintptr_t(h), listenOnly)
+		if tap == nil {
+			permission := "Input Monitoring"
+			if hook {
+				permission = "Accessibility"
+			}
+			resultCh <- fmt.Errorf("cannot watch the keyboard; allow this app under System Settings > Privacy & Security > %s", permission)
+			return
+		}
+		defer C.sttTapDestroy(tap)
+		resultCh <- nil
+		for !stopped.Load() {
+			C.sttTapRunFor(0.2)
+		}
+	}()
+	if err := <-resultCh; err != nil {
+		return nil, err
+	}
+	return &Registration{stop: func() { stopped.Store(true) }}, nil
+}
+
+// tapState is what the event tap callback reaches through its cgo.Handle.
+type tapState struct {
+	bindings []macBinding
+	handler  func(id int)
+	swallow  bool
+	debug    bool
+}
+
+// sttHotkeyEvent is called by the event tap for every key press and
+// reports whether the event should be swallowed.
+//
+//export sttHotkeyEvent
+func sttHotkeyEvent(handle C.uintptr_t, code C.int, flags C.uint64_t) C.int {
+	s := cgo.Handle(handle).Value().(*tapState)
+	id := matchMacHotkey(s.bindings, uint16(code), uint64(flags))
+	if id == 0 {
+		return 0
+	}
+	if s.debug {
+		fmt.Printf("[hotkey-debug] hotkey %d (keycode=0x%X flags=0x%X)\n", id, int(code), uint64(flags))
+	}
+	// The handler may block; a slow callback would get the tap disabled.
+	go s.handler(id)
+	if s.swallow {
+		return 1
+	}
+	return 0
+}
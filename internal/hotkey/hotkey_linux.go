@@ -0,0 +1,241 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build linux
+
+package hotkey
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Registration represents a registered hotkey set.
+type Registration struct {
+	once     sync.Once
+	stop     func()
+	releases bool
+}
+
+// Stop releases registered hotkeys.
+func (r *Registration) Stop() {
+	if r == nil {
+		return
+	}
+	r.once.Do(func() {
+		if r.stop != nil {
+			r.stop()
+		}
+	})
+}
+
+// Releases reports whether handlers get StartReleased, which push-to-talk
+// needs to stop recording.
+func (r *Registration) Releases() bool {
+	return r != nil && r.releases
+}
+
+// Register installs hotkeys and wires them to handler.
+func Register(startKey, pauseKey, cancelKey string, hook bool, handler func(id int), debug bool) error {
+	_, err := RegisterWithStop(startKey, pauseKey, cancelKey, hook, handler, debug)
+	return err
+}
+
+// RegisterWithStop installs hotkeys and returns a handle that can unregister them.
+//
+// With hook set, keys are read straight from the keyboards under
+// /dev/input, which works on X11 and Wayland alike but needs the user to be
+// in the input group. Otherwise the desktop's GlobalShortcuts portal is
+// asked to bind them, falling back to /dev/input when there is no portal.
+func RegisterWithStop(startKey, pauseKey, cancelKey string, hook bool, handler func(id int), debug bool) (*Registration, error) {
+	specs := []string{startKey, pauseKey, cancelKey}
+	bindings := make([]binding, len(specs))
+	for i, spec := range specs {
+		mod, key, err := parseLinuxHotkey(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hotkey '%s': %v", spec, err)
+		}
+		bindings[i] = binding{id: i + 1, spec: spec, mod: mod, key: key}
+		if debug {
+			fmt.Printf("[hotkey-debug] parsed '%s' -> mod=0x%X code=%d sym=%s\n", spec, mod, key.code, key.sym)
+		}
+	}
+	if hook {
+		return startEvdev(bindings, handler, debug)
+	}
+	reg, err := startPortal(bindings, handler, debug)
+	if err == nil {
+		return reg, nil
+	}
+	if debug {
+		fmt.Printf("[hotkey-debug] GlobalShortcuts portal unavailable: %v\n", err)
+	}
+	reg, evErr := startEvdev(bindings, handler, debug)
+	if evErr != nil {
+		return nil, fmt.Errorf("%v (GlobalShortcuts portal: %v)", evErr, err)
+	}
+	return reg, nil
+}
+
+// binding is one parsed hotkey and the id handed to the handler.
+type binding struct {
+	id   int
+	spec string
+	mod  uint32
+	key  linuxKey
+}
+
+// Evdev event types and key values, see linux/input-event-codes.h.
+const (
+	evKey      = 0x01
+	keyRelease = 0
+	keyPress   = 1
+)
+
+// inputEvent mirrors struct input_event.
+type inputEvent struct {
+	Time  unix.Timeval
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+// matcher tracks which modifiers are held across all keyboards and turns
+// key presses into hotkey ids.
+type matcher struct {
+	mu       sync.Mutex
+	bindings []binding
+	held     map[uint16]bool
+	// started is the key that fired the start hotkey while it is down.
+	started uint16
+}
+
+func newMatcher(bindings []binding) *matcher {
+	return &matcher{bindings: bindings, held: make(map[uint16]bool)}
+}
+
+// feed processes one key event and returns the id of the hotkey it
+// completes, StartReleased when it releases the key of the start hotkey, or
+// 0. Modifiers must match exactly, as with RegisterHotKey on Windows, and
+// auto-repeat does not fire a hotkey again.
+func (m *matcher) feed(code uint16, value int32) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := modifierMask[code]; ok {
+		switch value {
+		case keyPress:
+			m.held[code] = true
+		case keyRelease:
+			delete(m.held, code)
+		}
+		return 0
+	}
+	if value == keyRelease && m.started != 0 && code == m.started {
+		m.started = 0
+		return StartReleased
+	}
+	if value != keyPress {
+		return 0
+	}
+	var mod uint32
+	for c := range m.held {
+		mod |= modifierMask[c]
+	}
+	for _, b := range m.bindings {
+		if b.key.code == code && b.mod == mod {
+			if b.id == 1 {
+				m.started = code
+			}
+			return b.id
+		}
+	}
+	return 0
+}
+
+// startEvdev watches every keyboard under /dev/input for the hotkeys.
+func startEvdev(bindings []binding, handler func(id int), debug bool) (*Registration, error) {
+	paths, _ := filepath.Glob("/dev/input/event*")
+	var keyboards []*os.File
+	var denied bool
+	for _, p := range paths {
+		f, err := os.Open(p)
+		if err != nil {
+			if errors.Is(err, os.ErrPermission) {
+				denied = true
+			}
+			continue
+		}
+		if !isKeyboard(f) {
+			f.Close()
+			continue
+		}
+		if debug {
+			fmt.Printf("[hotkey-debug] reading keyboard %s\n", p)
+		}
+		keyboards = append(keyboards, f)
+	}
+	if len(keyboards) == 0 {
+		if denied {
+			return nil, fmt.Errorf("no permission to read keyboards in /dev/input; add your user to the input group and log in again")
+		}
+		return nil, fmt.Errorf("no keyboard found in /dev/input")
+	}
+
+	m := newMatcher(bindings)
+	for _, f := range keyboards {
+		go readEvents(f, m, handler, debug)
+	}
+	return &Registration{releases: true, stop: func() {
+		for _, f := range keyboards {
+			f.Close()
+		}
+	}}, nil
+}
+
+// readEvents feeds key events from r to m until r is closed.
+func readEvents(r io.Reader, m *matcher, handler func(id int), debug bool) {
+	var ev inputEvent
+	for {
+		if err := binary.Read(r, binary.NativeEndian, &ev); err != nil {
+			return
+		}
+		if ev.Type != evKey {
+			continue
+		}
+		if id := m.feed(ev.Code, ev.Value); id != 0 {
+			if debug {
+				fmt.Printf("[hotkey-debug] hotkey %d (code=%d)\n", id, ev.Code)
+			}
+			handler(id)
+		}
+	}
+}
+
+// isKeyboard reports whether the device behind f has letter keys, which
+// tells keyboards apart from mice, power buttons and the like.
+func isKeyboard(f *os.File) bool {
+	var bits [64]byte // KEY_MAX is 0x2ff
+	// EVIOCGBIT(EV_KEY, len(bits))
+	req := uintptr(2<<30 | len(bits)<<16 | 'E'<<8 | (0x20 + evKey))
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), req, uintptr(unsafe.Pointer(&bits[0]))); errno != 0 {
+		return false
+	}
+	has := func(code uint16) bool { return bits[code/8]&(1<<(code%8)) != 0 }
+	return has(linuxKeys['A'].code) && has(linuxKeys['Z'].code) && has(linuxKeys[' '].code)
+}
@@ -9,7 +9,7 @@
 // implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
 // See <https://www.gnu.org/licenses/> for more details.
 
-//go:build !windows
+//go:build !windows && !linux && !(darwin && cgo)
 
 package hotkey
 
@@ -21,6 +21,9 @@ type Registration struct{}
 // Stop releases registered hotkeys.
 func (r *Registration) Stop() {}
 
+// Releases reports whether handlers get StartReleased.
+func (r *Registration) Releases() bool { return false }
+
 // Register is not supported on non-Windows builds.
 func Register(startKey, pauseKey, cancelKey string, hook bool, handler func(id int), debug bool) error {
 	return fmt.Errorf("hotkey not supported on this platform")
@@ -16,8 +16,6 @@ package hotkey
 import (
 	"fmt"
 	"runtime"
-	"strconv"
-	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -26,8 +24,9 @@ import (
 
 // Registration represents a registered hotkey set.
 type Registration struct {
-	once sync.Once
-	stop func()
+	once     sync.Once
+	stop     func()
+	releases bool
 }
 
 // Stop releases registered hotkeys.
@@ -42,6 +41,12 @@ func (r *Registration) Stop() {
 	})
 }
 
+// Releases reports whether handlers get StartReleased, which push-to-talk
+// needs to stop recording.
+func (r *Registration) Releases() bool {
+	return r != nil && r.releases
+}
+
 // Register installs hotkeys and wires them to handler.
 func Register(startKey, pauseKey, cancelKey string, hook bool, handler func(id int), debug bool) error {
 	_, err := RegisterWithStop(startKey, pauseKey, cancelKey, hook, handler, debug)
@@ -274,7 +279,28 @@ func startLowLevelHook(startKey, pauseKey, cancelKey string, handler func(id int
 			return true
 		}
 
-		swallowed := make(map[uint32]bool)
+		// swallowed maps each hotkey key that is down to the id it fired.
+		swallowed := make(map[uint32]int)
+		// Hotkeys reach the handler in order on their own goroutine, so a
+		// quick press and release never arrive swapped. The hook must not
+		// wait for the handler, which can be busy uploading, so hotkeys
+		// beyond a full queue are dropped.
+		ids := make(chan int, 64)
+		go func() {
+			for id := range ids {
+				handler(id)
+			}
+		}()
+		defer close(ids)
+		fire := func(id int) {
+			select {
+			case ids <- id:
+			default:
+				if debug {
+					fmt.Printf("[hotkey-debug] dropped hotkey %d, handler busy\n", id)
+				}
+			}
+		}
 
 		callback := syscall.NewCallback(func(nCode, wParam, lParam uintptr) uintptr {
 			if int32(nCode) < 0 {
@@ -293,14 +319,18 @@ func startLowLevelHook(startKey, pauseKey, cancelKey string, handler func(id int
 			}
 
 			if msg == WM_KEYDOWN || msg == WM_SYSKEYDOWN {
+				// Auto-repeat of a held hotkey does not fire it again.
+				if _, held := swallowed[vk]; held {
+					return uintptr(1)
+				}
 				if cands, ok := lookup[vk]; ok {
 					for _, c := range cands {
 						if modsSatisfied(c.mod) {
-							swallowed[vk] = true
+							swallowed[vk] = c.id
 							if debug {
 								fmt.Printf("[hotkey-debug] swallowed keydown vk=0x%X id=%d\n", vk, c.id)
 							}
-							go handler(c.id)
+							fire(c.id)
 							return uintptr(1)
 						}
 					}
@@ -308,11 +338,14 @@ func startLowLevelHook(startKey, pauseKey, cancelKey string, handler func(id int
 			}
 
 			if msg == WM_KEYUP || msg == WM_SYSKEYUP {
-				if swallowed[vk] {
+				if id, ok := swallowed[vk]; ok {
 					if debug {
 						fmt.Printf("[hotkey-debug] swallowed keyup vk=0x%X\n", vk)
 					}
 					delete(swallowed, vk)
+					if id == 1 {
+						fire(StartReleased)
+					}
 					return uintptr(1)
 				}
 			}
@@ -338,7 +371,7 @@ func startLowLevelHook(startKey, pauseKey, cancelKey string, handler func(id int
 		}
 
 		threadID, _, _ := procGetCurrentThreadId.Call()
-		resultCh <- result{reg: &Registration{stop: func() {
+		resultCh <- result{reg: &Registration{releases: true, stop: func() {
 			const WM_QUIT = 0x0012
 			procPostThreadMessageW.Call(threadID, uintptr(WM_QUIT), 0, 0)
 		}}}
@@ -377,120 +410,3 @@ func startLowLevelHook(startKey, pauseKey, cancelKey string, handler func(id int
 		return nil, fmt.Errorf("timeout installing low-level hook")
 	}
 }
-
-const (
-	VK_NUMPAD0  = 0x60
-	VK_NUMPAD1  = 0x61
-	VK_NUMPAD2  = 0x62
-	VK_NUMPAD3  = 0x63
-	VK_NUMPAD4  = 0x64
-	VK_NUMPAD5  = 0x65
-	VK_NUMPAD6  = 0x66
-	VK_NUMPAD7  = 0x67
-	VK_NUMPAD8  = 0x68
-	VK_NUMPAD9  = 0x69
-	VK_ADD      = 0x6B
-	VK_SUBTRACT = 0x6D
-)
-
-// parseHotkey accepts strings like "alt+q", "ctrl+shift+F1", "esc" and returns modifier mask and vk.
-func parseHotkey(s string) (uint32, uint32, error) {
-	if s == "" {
-		return 0, 0, fmt.Errorf("empty key")
-	}
-	parts := strings.Split(s, "+")
-	for i := range parts {
-		parts[i] = strings.TrimSpace(strings.ToLower(parts[i]))
-	}
-	var mod uint32
-	var keyToken string
-	if len(parts) == 1 {
-		keyToken = parts[0]
-	} else {
-		keyToken = parts[len(parts)-1]
-		for _, p := range parts[:len(parts)-1] {
-			switch p {
-			case "alt", "menu":
-				mod |= 0x0001
-			case "ctrl", "control":
-				mod |= 0x0002
-			case "shift":
-				mod |= 0x0004
-			case "win", "meta", "super":
-				mod |= 0x0008
-			default:
-			}
-		}
-	}
-	if len(keyToken) == 1 {
-		ch := keyToken[0]
-		if ch >= 'a' && ch <= 'z' {
-			return mod, uint32(ch - 'a' + 'A'), nil
-		}
-		if ch >= '0' && ch <= '9' {
-			return mod, uint32(ch), nil
-		}
-	}
-	switch keyToken {
-	case "esc", "escape":
-		return mod, 0x1B, nil
-	case "space":
-		return mod, 0x20, nil
-	case "enter", "return":
-		return mod, 0x0D, nil
-	}
-	if strings.HasPrefix(keyToken, "f") {
-		nStr := strings.TrimPrefix(keyToken, "f")
-		if n, err := strconv.Atoi(nStr); err == nil && n >= 1 && n <= 24 {
-			return mod, 0x70 + uint32(n-1), nil
-		}
-	}
-	switch keyToken {
-	case "numpad0", "num0", "kp0":
-		return mod, VK_NUMPAD0, nil
-	case "numpad1", "num1", "kp1":
-		return mod, VK_NUMPAD1, nil
-	case "numpad2", "num2", "kp2":
-		return mod, VK_NUMPAD2, nil
-	case "numpad3", "num3", "kp3":
-		return mod, VK_NUMPAD3, nil
-	case "numpad4", "num4", "kp4":
-		return mod, VK_NUMPAD4, nil
-	case "numpad5", "num5", "kp5":
-		return mod, VK_NUMPAD5, nil
-	case "numpad6", "num6", "kp6":
-		return mod, VK_NUMPAD6, nil
-	case "numpad7", "num7", "kp7":
-		return mod, VK_NUMPAD7, nil
-	case "numpad8", "num8", "kp8":
-		return mod, VK_NUMPAD8, nil
-	case "numpad9", "num9", "kp9":
-		return mod, VK_NUMPAD9, nil
-	case "add", "plus", "kpadd":
-		return mod, VK_ADD, nil
-	case "subtract", "minus", "kpsubtract":
-		return mod, VK_SUBTRACT, nil
-	}
-
-	named := map[string]uint32{
-		"tab":       0x09,
-		"backspace": 0x08,
-		"insert":    0x2D,
-		"delete":    0x2E,
-		"home":      0x24,
-		"end":       0x23,
-		"pageup":    0x21,
-		"pagedown":  0x22,
-		"left":      0x25,
-		"up":        0x26,
-		"right":     0x27,
-		"down":      0x28,
-	}
-	if v, ok := named[keyToken]; ok {
-		return mod, v, nil
-	}
-	if len(keyToken) == 1 {
-		return mod, uint32(strings.ToUpper(keyToken)[0]), nil
-	}
-	return 0, 0, fmt.Errorf("unsupported key token: %s", s)
-}
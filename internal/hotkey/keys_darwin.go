@@ -0,0 +1,118 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build darwin
+
+package hotkey
+
+import "fmt"
+
+// macKeys maps the virtual-key codes returned by parseHotkey to macOS
+// virtual key codes (kVK_* in HIToolbox/Events.h). F21 to F24 have no Mac
+// equivalent.
+var macKeys = map[uint32]uint16{
+	'A': 0x00, 'S': 0x01, 'D': 0x02, 'F': 0x03, 'H': 0x04, 'G': 0x05, 'Z': 0x06,
+	'X': 0x07, 'C': 0x08, 'V': 0x09, 'B': 0x0B, 'Q': 0x0C, 'W': 0x0D, 'E': 0x0E,
+	'R': 0x0F, 'Y': 0x10, 'T': 0x11, 'O': 0x1F, 'U': 0x20, 'I': 0x22, 'P': 0x23,
+	'L': 0x25, 'J': 0x26, 'K': 0x28, 'N': 0x2D, 'M': 0x2E,
+
+	'1': 0x12, '2': 0x13, '3': 0x14, '4': 0x15, '5': 0x17,
+	'6': 0x16, '7': 0x1A, '8': 0x1C, '9': 0x19, '0': 0x1D,
+
+	0x0D: 0x24, // return
+	0x09: 0x30, // tab
+	0x20: 0x31, // space
+	0x08: 0x33, // backspace (delete)
+	0x1B: 0x35, // escape
+	0x2D: 0x72, // insert (help)
+	0x24: 0x73, // home
+	0x21: 0x74, // page up
+	0x2E: 0x75, // delete (forward delete)
+	0x23: 0x77, // end
+	0x22: 0x79, // page down
+	0x25: 0x7B, // left
+	0x27: 0x7C, // right
+	0x28: 0x7D, // down
+	0x26: 0x7E, // up
+
+	0x70: 0x7A, 0x71: 0x78, 0x72: 0x63, 0x73: 0x76, 0x74: 0x60, // F1-F5
+	0x75: 0x61, 0x76: 0x62, 0x77: 0x64, 0x78: 0x65, 0x79: 0x6D, // F6-F10
+	0x7A: 0x67, 0x7B: 0x6F, 0x7C: 0x69, 0x7D: 0x6B, 0x7E: 0x71, // F11-F15
+	0x7F: 0x6A, 0x80: 0x40, 0x81: 0x4F, 0x82: 0x50, 0x83: 0x5A, // F16-F20
+
+	VK_NUMPAD0: 0x52, VK_NUMPAD1: 0x53, VK_NUMPAD2: 0x54, VK_NUMPAD3: 0x55,
+	VK_NUMPAD4: 0x56, VK_NUMPAD5: 0x57, VK_NUMPAD6: 0x58, VK_NUMPAD7: 0x59,
+	VK_NUMPAD8: 0x5B, VK_NUMPAD9: 0x5C, VK_ADD: 0x45, VK_SUBTRACT: 0x4E,
+}
+
+// CGEventFlags bits of the modifier keys.
+const (
+	flagShift     = 0x00020000
+	flagControl   = 0x00040000
+	flagAlternate = 0x00080000
+	flagCommand   = 0x00100000
+)
+
+// modsFromFlags converts CGEventFlags to the modifier mask returned by
+// parseHotkey. Option stands in for alt and Command for win.
+func modsFromFlags(flags uint64) uint32 {
+	var mod uint32
+	if flags&flagAlternate != 0 {
+		mod |= 0x0001
+	}
+	if flags&flagControl != 0 {
+		mod |= 0x0002
+	}
+	if flags&flagShift != 0 {
+		mod |= 0x0004
+	}
+	if flags&flagCommand != 0 {
+		mod |= 0x0008
+	}
+	return mod
+}
+
+// macBinding is one parsed hotkey and the id handed to the handler.
+type macBinding struct {
+	id   int
+	mod  uint32
+	code uint16
+}
+
+// parseMacHotkeys parses the start, pause and cancel specs into bindings
+// with ids 1, 2 and 3.
+func parseMacHotkeys(specs ...string) ([]macBinding, error) {
+	bindings := make([]macBinding, 0, len(specs))
+	for i, spec := range specs {
+		mod, vk, err := parseHotkey(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hotkey '%s': %v", spec, err)
+		}
+		code, ok := macKeys[vk]
+		if !ok {
+			return nil, fmt.Errorf("invalid hotkey '%s': unsupported key on macOS", spec)
+		}
+		bindings = append(bindings, macBinding{id: i + 1, mod: mod, code: code})
+	}
+	return bindings, nil
+}
+
+// matchMacHotkey returns the id of the binding a key-down event completes,
+// or 0. Modifiers must match exactly, as with RegisterHotKey on Windows.
+func matchMacHotkey(bindings []macBinding, code uint16, flags uint64) int {
+	mod := modsFromFlags(flags)
+	for _, b := range bindings {
+		if b.code == code && b.mod == mod {
+			return b.id
+		}
+	}
+	return 0
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build darwin
+
+package hotkey
+
+import "testing"
+
+func TestParseMacHotkeys(t *testing.T) {
+	bindings, err := parseMacHotkeys("ctrl+alt+q", "win+shift+F5", "esc")
+	if err != nil {
+		t.Fatalf("parseMacHotkeys failed: %v", err)
+	}
+	want := []macBinding{
+		{id: 1, mod: 0x0003, code: 0x0C},
+		{id: 2, mod: 0x000C, code: 0x60},
+		{id: 3, mod: 0, code: 0x35},
+	}
+	for i := range want {
+		if bindings[i] != want[i] {
+			t.Errorf("binding %d = %+v, want %+v", i, bindings[i], want[i])
+		}
+	}
+	if _, err := parseMacHotkeys("f24"); err == nil {
+		t.Fatal("parseMacHotkeys(\"f24\") succeeded, want error")
+	}
+}
+
+func TestMatchMacHotkey(t *testing.T) {
+	bindings, err := parseMacHotkeys("ctrl+alt+q", "ctrl+q", "esc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		code  uint16
+		flags uint64
+		want  int
+	}{
+		{code: 0x0C, flags: flagControl | flagAlternate, want: 1},
+		{code: 0x0C, flags: flagControl, want: 2},
+		{code: 0x0C, flags: flagControl | flagAlternate | flagShift, want: 0},
+		{code: 0x0C, flags: 0, want: 0},
+		{code: 0x35, flags: 0x100, want: 3}, // non-modifier flag bits are ignored
+		{code: 0x35, flags: flagCommand, want: 0},
+	}
+	for _, tt := range tests {
+		if got := matchMacHotkey(bindings, tt.code, tt.flags); got != tt.want {
+			t.Errorf("matchMacHotkey(0x%X, 0x%X) = %d, want %d", tt.code, tt.flags, got, tt.want)
+		}
+	}
+}
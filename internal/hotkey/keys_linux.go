@@ -0,0 +1,143 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build linux
+
+package hotkey
+
+import (
+	"fmt"
+	"strings"
+)
+
+// linuxKey is the evdev code and the XKB keysym name of a key.
+type linuxKey struct {
+	code uint16
+	sym  string
+}
+
+// Evdev codes of the modifier keys, left and right.
+const (
+	keyLeftCtrl   = 29
+	keyLeftShift  = 42
+	keyRightShift = 54
+	keyLeftAlt    = 56
+	keyRightCtrl  = 97
+	keyRightAlt   = 100
+	keyLeftMeta   = 125
+	keyRightMeta  = 126
+)
+
+// modifierMask maps a modifier's evdev code to its bit in the mask
+// returned by parseHotkey.
+var modifierMask = map[uint16]uint32{
+	keyLeftAlt:    0x0001,
+	keyRightAlt:   0x0001,
+	keyLeftCtrl:   0x0002,
+	keyRightCtrl:  0x0002,
+	keyLeftShift:  0x0004,
+	keyRightShift: 0x0004,
+	keyLeftMeta:   0x0008,
+	keyRightMeta:  0x0008,
+}
+
+// linuxKeys maps the virtual-key codes returned by parseHotkey to keys.
+var linuxKeys = func() map[uint32]linuxKey {
+	m := map[uint32]linuxKey{
+		0x1B: {1, "Escape"},
+		0x20: {57, "space"},
+		0x0D: {28, "Return"},
+		0x09: {15, "Tab"},
+		0x08: {14, "BackSpace"},
+		0x2D: {110, "Insert"},
+		0x2E: {111, "Delete"},
+		0x24: {102, "Home"},
+		0x23: {107, "End"},
+		0x21: {104, "Page_Up"},
+		0x22: {109, "Page_Down"},
+		0x25: {105, "Left"},
+		0x26: {103, "Up"},
+		0x27: {106, "Right"},
+		0x28: {108, "Down"},
+
+		VK_NUMPAD0:  {82, "KP_0"},
+		VK_NUMPAD1:  {79, "KP_1"},
+		VK_NUMPAD2:  {80, "KP_2"},
+		VK_NUMPAD3:  {81, "KP_3"},
+		VK_NUMPAD4:  {75, "KP_4"},
+		VK_NUMPAD5:  {76, "KP_5"},
+		VK_NUMPAD6:  {77, "KP_6"},
+		VK_NUMPAD7:  {71, "KP_7"},
+		VK_NUMPAD8:  {72, "KP_8"},
+		VK_NUMPAD9:  {73, "KP_9"},
+		VK_ADD:      {78, "KP_Add"},
+		VK_SUBTRACT: {74, "KP_Subtract"},
+	}
+	// Letters follow the QWERTY rows, not the alphabet.
+	for i, c := range "QWERTYUIOP" {
+		m[uint32(c)] = linuxKey{uint16(16 + i), strings.ToLower(string(c))}
+	}
+	for i, c := range "ASDFGHJKL" {
+		m[uint32(c)] = linuxKey{uint16(30 + i), strings.ToLower(string(c))}
+	}
+	for i, c := range "ZXCVBNM" {
+		m[uint32(c)] = linuxKey{uint16(44 + i), strings.ToLower(string(c))}
+	}
+	for i, c := range "1234567890" {
+		m[uint32(c)] = linuxKey{uint16(2 + i), string(c)}
+	}
+	for n := 1; n <= 24; n++ {
+		var code uint16
+		switch {
+		case n <= 10:
+			code = uint16(58 + n)
+		case n <= 12:
+			code = uint16(76 + n)
+		default:
+			code = uint16(170 + n)
+		}
+		m[0x70+uint32(n-1)] = linuxKey{code, fmt.Sprintf("F%d", n)}
+	}
+	return m
+}()
+
+// parseLinuxHotkey parses spec like parseHotkey and returns the modifier
+// mask and the key it names.
+func parseLinuxHotkey(spec string) (uint32, linuxKey, error) {
+	mod, vk, err := parseHotkey(spec)
+	if err != nil {
+		return 0, linuxKey{}, err
+	}
+	key, ok := linuxKeys[vk]
+	if !ok {
+		return 0, linuxKey{}, fmt.Errorf("unsupported key on Linux: %s", spec)
+	}
+	return mod, key, nil
+}
+
+// shortcutTrigger returns spec in the form the XDG GlobalShortcuts portal
+// expects for a preferred trigger, e.g. "CTRL+ALT+q".
+func shortcutTrigger(mod uint32, key linuxKey) string {
+	var parts []string
+	if mod&0x0002 != 0 {
+		parts = append(parts, "CTRL")
+	}
+	if mod&0x0001 != 0 {
+		parts = append(parts, "ALT")
+	}
+	if mod&0x0004 != 0 {
+		parts = append(parts, "SHIFT")
+	}
+	if mod&0x0008 != 0 {
+		parts = append(parts, "LOGO")
+	}
+	return strings.Join(append(parts, key.sym), "+")
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build linux
+
+package hotkey
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func testBindings(t *testing.T, specs ...string) []binding {
+	t.Helper()
+	var bindings []binding
+	for i, spec := range specs {
+		mod, key, err := parseLinuxHotkey(spec)
+		if err != nil {
+			t.Fatalf("parseLinuxHotkey(%q) failed: %v", spec, err)
+		}
+		bindings = append(bindings, binding{id: i + 1, spec: spec, mod: mod, key: key})
+	}
+	return bindings
+}
+
+func TestParseLinuxHotkey(t *testing.T) {
+	tests := []struct {
+		spec    string
+		code    uint16
+		trigger string
+	}{
+		{spec: "ctrl+alt+q", code: 16, trigger: "CTRL+ALT+q"},
+		{spec: "Shift + F12", code: 88, trigger: "SHIFT+F12"},
+		{spec: "f13", code: 183, trigger: "F13"},
+		{spec: "win+numpad5", code: 76, trigger: "LOGO+KP_5"},
+		{spec: "alt+m", code: 50, trigger: "ALT+m"},
+		{spec: "ctrl+0", code: 11, trigger: "CTRL+0"},
+		{spec: "pagedown", code: 109, trigger: "Page_Down"},
+	}
+	for _, tt := range tests {
+		mod, key, err := parseLinuxHotkey(tt.spec)
+		if err != nil {
+			t.Fatalf("parseLinuxHotkey(%q) failed: %v", tt.spec, err)
+		}
+		if key.code != tt.code {
+			t.Errorf("parseLinuxHotkey(%q) code = %d, want %d", tt.spec, key.code, tt.code)
+		}
+		if got := shortcutTrigger(mod, key); got != tt.trigger {
+			t.Errorf("shortcutTrigger(%q) = %q, want %q", tt.spec, got, tt.trigger)
+		}
+	}
+	if _, _, err := parseLinuxHotkey("ctrl+;"); err == nil {
+		t.Fatal("parseLinuxHotkey(\"ctrl+;\") succeeded, want error")
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	m := newMatcher(testBindings(t, "ctrl+alt+q", "ctrl+q", "esc"))
+	steps := []struct {
+		code  uint16
+		value int32
+		want  int
+	}{
+		{code: 16, value: keyPress, want: 0},
+		{code: 16, value: keyRelease, want: 0},
+		{code: keyLeftCtrl, value: keyPress, want: 0},
+		{code: 16, value: keyPress, want: 2},
+		{code: 16, value: 2, want: 0}, // auto-repeat
+		{code: 16, value: keyRelease, want: 0},
+		{code: keyRightAlt, value: keyPress, want: 0},
+		{code: 16, value: keyPress, want: 1},
+		{code: 16, value: 2, want: 0}, // auto-repeat
+		{code: 16, value: keyRelease, want: StartReleased},
+		{code: keyLeftCtrl, value: keyRelease, want: 0},
+		{code: keyRightAlt, value: keyRelease, want: 0},
+		{code: 1, value: keyPress, want: 3},
+	}
+	for i, s := range steps {
+		if got := m.feed(s.code, s.value); got != s.want {
+			t.Fatalf("step %d: feed(%d, %d) = %d, want %d", i, s.code, s.value, got, s.want)
+		}
+	}
+}
+
+func TestReadEvents(t *testing.T) {
+	var buf bytes.Buffer
+	for _, ev := range []inputEvent{
+		{Type: evKey, Code: keyLeftShift, Value: keyPress},
+		{Type: 0x04, Code: 0x04, Value: 30}, // EV_MSC scan code
+		{Type: evKey, Code: 59, Value: keyPress},
+		{Type: 0x00}, // EV_SYN
+	} {
+		if err := binary.Write(&buf, binary.NativeEndian, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var got []int
+	readEvents(&buf, newMatcher(testBindings(t, "shift+f1")), func(id int) { got = append(got, id) }, false)
+	if len(got) != 1 || got[0] != 1 {
+		t.Fatalf("handler calls = %v, want [1]", got)
+	}
+}
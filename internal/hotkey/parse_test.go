@@ -0,0 +1,59 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package hotkey
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHotkey(t *testing.T) {
+	tests := []struct {
+		spec string
+		mod  uint32
+		vk   uint32
+	}{
+		{spec: "ctrl+alt+q", mod: 0x0003, vk: 'Q'},
+		{spec: "Shift + F12", mod: 0x0004, vk: 0x7B},
+		{spec: "alt+esc", mod: 0x0001, vk: 0x1B},
+		{spec: "win+numpad5", mod: 0x0008, vk: VK_NUMPAD5},
+		{spec: "pagedown", mod: 0, vk: 0x22},
+	}
+	for _, tt := range tests {
+		mod, vk, err := parseHotkey(tt.spec)
+		if err != nil {
+			t.Fatalf("parseHotkey(%q) failed: %v", tt.spec, err)
+		}
+		if mod != tt.mod || vk != tt.vk {
+			t.Fatalf("parseHotkey(%q) = (0x%X, 0x%X), want (0x%X, 0x%X)", tt.spec, mod, vk, tt.mod, tt.vk)
+		}
+	}
+}
+
+func TestParseHotkeyRejectsInvalidSpecs(t *testing.T) {
+	for _, spec := range []string{"", "ctrl+f25", "ctrl+bogus", "hyper+q"} {
+		if _, _, err := parseHotkey(spec); err == nil {
+			t.Fatalf("parseHotkey(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestValidateDetectsDuplicates(t *testing.T) {
+	if err := Validate("ctrl+alt+q", "ctrl+alt+s", "alt+esc"); err != nil {
+		t.Fatalf("Validate failed for distinct keys: %v", err)
+	}
+	err := Validate("ctrl+alt+q", "alt+ctrl+Q", "alt+esc")
+	if err == nil || !strings.Contains(err.Error(), "duplicate hotkey") {
+		t.Fatalf("Validate error = %v, want duplicate hotkey", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build linux
+
+package hotkey
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	portalName    = "org.freedesktop.portal.Desktop"
+	portalPath    = "/org/freedesktop/portal/desktop"
+	portalIface   = "org.freedesktop.portal.GlobalShortcuts"
+	requestIface  = "org.freedesktop.portal.Request"
+	sessionIface  = "org.freedesktop.portal.Session"
+	portalTimeout = 2 * time.Minute
+)
+
+// shortcutNames are the portal shortcut ids of hotkey ids 1, 2 and 3.
+var shortcutNames = []string{"start", "pause", "cancel"}
+
+// shortcutDescriptions are shown by the desktop when it asks the user to
+// confirm the shortcuts.
+var shortcutDescriptions = []string{"Start or stop recording", "Pause or resume recording", "Cancel recording"}
+
+// startPortal binds the hotkeys through the XDG GlobalShortcuts portal. The
+// desktop may show a dialog where the user confirms or changes them.
+func startPortal(bindings []binding, handler func(id int), debug bool) (*Registration, error) {
+	conn, err := dbus.SessionBusPrivate()
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Auth(nil); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.Hello(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	signals := make(chan *dbus.Signal, 16)
+	conn.Signal(signals)
+	for _, iface := range []string{requestIface, portalIface} {
+		if err := conn.AddMatchSignal(dbus.WithMatchInterface(iface)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	portal := conn.Object(portalName, portalPath)
+	token := "stt" + strconv.Itoa(os.Getpid())
+	results, err := portalRequest(portal, signals, portalIface+".CreateSession", map[string]dbus.Variant{
+		"handle_token":         dbus.MakeVariant(token + "_create"),
+		"session_handle_token": dbus.MakeVariant(token),
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	var session dbus.ObjectPath
+	switch v := results["session_handle"].Value().(type) {
+	case string:
+		session = dbus.ObjectPath(v)
+	case dbus.ObjectPath:
+		session = v
+	}
+	if !session.IsValid() {
+		conn.Close()
+		return nil, fmt.Errorf("portal returned no session")
+	}
+	closeSession := func() {
+		conn.Object(portalName, session).Call(sessionIface+".Close", 0)
+		conn.Close()
+	}
+
+	type shortcut struct {
+		ID      string
+		Options map[string]dbus.Variant
+	}
+	shortcuts := make([]shortcut, len(bindings))
+	for i, b := range bindings {
+		shortcuts[i] = shortcut{ID: shortcutNames[b.id-1], Options: map[string]dbus.Variant{
+			"description":       dbus.MakeVariant(shortcutDescriptions[b.id-1]),
+			"preferred_trigger": dbus.MakeVariant(shortcutTrigger(b.mod, b.key)),
+		}}
+	}
+	if _, err := portalRequest(portal, signals, portalIface+".BindShortcuts", session, shortcuts, "", map[string]dbus.Variant{
+		"handle_token": dbus.MakeVariant(token + "_bind"),
+	}); err != nil {
+		closeSession()
+		return nil, err
+	}
+	if debug {
+		fmt.Printf("[hotkey-debug] bound shortcuts through the GlobalShortcuts portal, session %s\n", session)
+	}
+
+	go func() {
+		for s := range signals {
+			if s.Name != portalIface+".Activated" || len(s.Body) < 2 {
+				continue
+			}
+			if path, _ := s.Body[0].(dbus.ObjectPath); path != session {
+				continue
+			}
+			name, _ := s.Body[1].(string)
+			for i, n := range shortcutNames {
+				if n == name {
+					if debug {
+						fmt.Printf("[hotkey-debug] hotkey %d (portal shortcut %s)\n", i+1, name)
+					}
+					handler(i + 1)
+				}
+			}
+		}
+	}()
+	return &Registration{stop: closeSession}, nil
+}
+
+// portalRequest calls method on the portal and waits for the Response
+// signal of the request object it returns.
+func portalRequest(portal dbus.BusObject, signals <-chan *dbus.Signal, method string, args ...any) (map[string]dbus.Variant, error) {
+	var handle dbus.ObjectPath
+	if err := portal.Call(method, 0, args...).Store(&handle); err != nil {
+		return nil, err
+	}
+	timeout := time.After(portalTimeout)
+	for {
+		select {
+		case s, ok := <-signals:
+			if !ok {
+				return nil, fmt.Errorf("%s: connection closed", method)
+			}
+			if s.Path != handle || s.Name != requestIface+".Response" {
+				continue
+			}
+			var code uint32
+			var results map[string]dbus.Variant
+			if err := dbus.Store(s.Body, &code, &results); err != nil {
+				return nil, err
+			}
+			if code != 0 {
+				return nil, fmt.Errorf("%s was cancelled", method)
+			}
+			return results, nil
+		case <-timeout:
+			return nil, fmt.Errorf("%s timed out", method)
+		}
+	}
+}
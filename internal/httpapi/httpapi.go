@@ -0,0 +1,326 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package httpapi serves the local HTTP control API, which lets browser
+// extensions and other programs drive record mode, transcribe uploaded files,
+// read history and scrape metrics. It only listens on loopback addresses and
+// every request needs the API token.
+package httpapi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"stt/internal/history"
+	"stt/internal/i18n"
+	"stt/internal/metrics"
+)
+
+// maxUpload limits the size of a /transcribe upload.
+const maxUpload = 512 << 20
+
+// defaultHistoryLimit is the number of entries /history returns without a
+// limit parameter.
+const defaultHistoryLimit = 50
+
+// Backend is what the API controls, normally the record-mode runtime.
+type Backend interface {
+	// Control answers "status" with the status JSON, or runs one of the
+	// actions "start", "stop", "toggle", "pause", "resume" and "cancel",
+	// returning an error when the state does not allow it.
+	Control(request string) (string, error)
+	// Transcribe transcribes the audio read from r. name is the uploaded
+	// file name, whose extension hints at the format.
+	Transcribe(ctx context.Context, name string, r io.Reader) (string, error)
+	// History returns the history entries matching q, newest first.
+	History(q history.Query) ([]history.Entry, error)
+}
+
+// Server is the API's http.Handler.
+type Server struct {
+	Token   string
+	Backend Backend
+}
+
+// actions are the POST endpoints passed on to Backend.Control.
+var actions = []string{"start", "stop", "toggle", "pause", "resume", "cancel"}
+
+// CheckAddr reports whether addr is a host:port the API may listen on. Only
+// loopback hosts are allowed, since the API can start the microphone.
+func CheckAddr(addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+	if !isLoopback(host) {
+		return fmt.Errorf("host %q is not a loopback address", host)
+	}
+	return nil
+}
+
+// NewToken returns a random API token.
+func NewToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Listen serves s on addr until the returned function is called, which
+// shuts the server down and waits briefly for requests in flight.
+func Listen(addr string, s *Server) (func(), error) {
+	if err := CheckAddr(addr); err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	srv := &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("[api] %s\n", i18n.Sprintf("serve failed: %v", err))
+		}
+	}()
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			_ = srv.Close()
+		}
+		<-done
+	}, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// Checking Host keeps web pages from reaching the API through DNS
+	// rebinding; the token keeps out everything else.
+	host := req.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if !isLoopback(host) {
+		writeError(w, http.StatusForbidden, "host not allowed")
+		return
+	}
+	if s.Token == "" || subtle.ConstantTimeCompare([]byte(requestToken(req)), []byte(s.Token)) != 1 {
+		writeError(w, http.StatusUnauthorized, "missing or wrong token")
+		return
+	}
+
+	name := strings.TrimPrefix(req.URL.Path, "/")
+	switch {
+	case name == "status":
+		if !allowMethod(w, req, http.MethodGet) {
+			return
+		}
+		s.status(w, http.StatusOK)
+	case name == "transcribe":
+		if !allowMethod(w, req, http.MethodPost) {
+			return
+		}
+		s.transcribe(w, req)
+	case name == "history":
+		if !allowMethod(w, req, http.MethodGet) {
+			return
+		}
+		s.history(w, req)
+	case name == "metrics":
+		if !allowMethod(w, req, http.MethodGet) {
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = metrics.WritePrometheus(w)
+	case slices.Contains(actions, name):
+		if !allowMethod(w, req, http.MethodPost) {
+			return
+		}
+		if _, err := s.Backend.Control(name); err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		s.status(w, http.StatusAccepted)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) status(w http.ResponseWriter, code int) {
+	body, err := s.Backend.Control("status")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = io.WriteString(w, body+"\n")
+}
+
+func (s *Server) transcribe(w http.ResponseWriter, req *http.Request) {
+	req.Body = http.MaxBytesReader(w, req.Body, maxUpload)
+	file, header, err := req.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("read upload: %v", err))
+		return
+	}
+	defer file.Close()
+	text, err := s.Backend.Transcribe(req.Context(), header.Filename, file)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"text": text})
+}
+
+// historyEntry is the JSON form of a history entry. The raw response is left
+// out to keep listings small.
+type historyEntry struct {
+	ID         int64     `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	Source     string    `json:"source"`
+	DurationMS int64     `json:"duration_ms"`
+	Provider   string    `json:"provider"`
+	Model      string    `json:"model"`
+	Language   string    `json:"language"`
+	Machine    string    `json:"machine,omitempty"`
+	Text       string    `json:"text"`
+	LatencyMS  int64     `json:"latency_ms"`
+	StopMS     int64     `json:"stop_ms"`
+	ConvertMS  int64     `json:"convert_ms"`
+	PasteMS    int64     `json:"paste_ms"`
+	AudioPath  string    `json:"audio_path"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+}
+
+func (s *Server) history(w http.ResponseWriter, req *http.Request) {
+	params := req.URL.Query()
+	q := history.Query{Text: params.Get("q"), Limit: defaultHistoryLimit}
+	if v := params.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid limit %q", v))
+			return
+		}
+		q.Limit = n
+	}
+	var err error
+	if q.Since, err = parseDate(params.Get("since"), false); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid since: %v", err))
+		return
+	}
+	if q.Until, err = parseDate(params.Get("until"), true); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid until: %v", err))
+		return
+	}
+
+	entries, err := s.Backend.History(q)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	out := make([]historyEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, historyEntry{
+			ID:         e.ID,
+			CreatedAt:  e.CreatedAt,
+			Source:     e.Source,
+			DurationMS: e.Duration.Milliseconds(),
+			Provider:   e.Provider,
+			Model:      e.Model,
+			Language:   e.Language,
+			Machine:    e.Machine,
+			Text:       e.Text,
+			LatencyMS:  e.Latency.Milliseconds(),
+			StopMS:     e.StopTime.Milliseconds(),
+			ConvertMS:  e.ConvertTime.Milliseconds(),
+			PasteMS:    e.PasteTime.Milliseconds(),
+			AudioPath:  e.AudioPath,
+			Status:     e.Status,
+			Error:      e.Error,
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// parseDate parses a since/until parameter in local time, like the -since
+// and -until flags of `stt history`. A bare date means the start of that
+// day, or with endOfDay the start of the next one.
+func parseDate(s string, endOfDay bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("want YYYY-MM-DD or RFC 3339, got %q", s)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// requestToken returns the token from "Authorization: Bearer <token>" or the
+// X-STT-Token header.
+func requestToken(req *http.Request) string {
+	if v, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(v)
+	}
+	return req.Header.Get("X-STT-Token")
+}
+
+func allowMethod(w http.ResponseWriter, req *http.Request, method string) bool {
+	if req.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	return false
+}
+
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
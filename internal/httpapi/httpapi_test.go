@@ -0,0 +1,182 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"stt/internal/history"
+)
+
+type fakeBackend struct {
+	requests []string
+	upload   string
+	query    history.Query
+}
+
+func (b *fakeBackend) Control(request string) (string, error) {
+	b.requests = append(b.requests, request)
+	switch request {
+	case "status":
+		return `{"state":"Idle"}`, nil
+	case "stop":
+		return "", errors.New("not recording")
+	}
+	return "", nil
+}
+
+func (b *fakeBackend) Transcribe(ctx context.Context, name string, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	b.upload = name + ":" + string(data)
+	return "hello", err
+}
+
+func (b *fakeBackend) History(q history.Query) ([]history.Entry, error) {
+	b.query = q
+	return []history.Entry{{ID: 7, Text: "hi"}}, nil
+}
+
+func serve(s *Server, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func authed(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Host = "127.0.0.1:8765"
+	req.Header.Set("Authorization", "Bearer secret")
+	return req
+}
+
+func TestServerChecksTokenAndHost(t *testing.T) {
+	s := &Server{Token: "secret", Backend: &fakeBackend{}}
+
+	req := authed(http.MethodGet, "/status", nil)
+	req.Header.Del("Authorization")
+	if rec := serve(s, req); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("no token: got %d, want 401", rec.Code)
+	}
+	req.Header.Set("X-STT-Token", "wrong")
+	if rec := serve(s, req); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("wrong token: got %d, want 401", rec.Code)
+	}
+	req.Header.Set("X-STT-Token", "secret")
+	if rec := serve(s, req); rec.Code != http.StatusOK || rec.Body.String() != "{\"state\":\"Idle\"}\n" {
+		t.Fatalf("X-STT-Token: got %d %q", rec.Code, rec.Body.String())
+	}
+
+	req = authed(http.MethodGet, "/status", nil)
+	req.Host = "evil.example:8765"
+	if rec := serve(s, req); rec.Code != http.StatusForbidden {
+		t.Fatalf("foreign host: got %d, want 403", rec.Code)
+	}
+}
+
+func TestServerActions(t *testing.T) {
+	b := &fakeBackend{}
+	s := &Server{Token: "secret", Backend: b}
+
+	if rec := serve(s, authed(http.MethodPost, "/start", nil)); rec.Code != http.StatusAccepted {
+		t.Fatalf("start: got %d, want 202", rec.Code)
+	}
+	rec := serve(s, authed(http.MethodPost, "/stop", nil))
+	if rec.Code != http.StatusConflict || !bytes.Contains(rec.Body.Bytes(), []byte("not recording")) {
+		t.Fatalf("stop: got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := serve(s, authed(http.MethodGet, "/start", nil)); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET /start: got %d, want 405", rec.Code)
+	}
+	if rec := serve(s, authed(http.MethodPost, "/quit", nil)); rec.Code != http.StatusNotFound {
+		t.Fatalf("/quit: got %d, want 404", rec.Code)
+	}
+	want := []string{"start", "status", "stop"}
+	if len(b.requests) != len(want) {
+		t.Fatalf("requests = %v, want %v", b.requests, want)
+	}
+	for i := range want {
+		if b.requests[i] != want[i] {
+			t.Fatalf("requests = %v, want %v", b.requests, want)
+		}
+	}
+}
+
+func TestServerTranscribeAndHistory(t *testing.T) {
+	b := &fakeBackend{}
+	s := &Server{Token: "secret", Backend: b}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("file", "clip.ogg")
+	_, _ = fw.Write([]byte("audio"))
+	_ = mw.Close()
+	req := authed(http.MethodPost, "/transcribe", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rec := serve(s, req)
+	var got map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || rec.Code != http.StatusOK || got["text"] != "hello" {
+		t.Fatalf("transcribe: got %d %q", rec.Code, rec.Body.String())
+	}
+	if b.upload != "clip.ogg:audio" {
+		t.Fatalf("upload = %q", b.upload)
+	}
+
+	rec = serve(s, authed(http.MethodGet, "/history?q=hi&limit=5&since=2026-01-02", nil))
+	var entries []historyEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil || len(entries) != 1 || entries[0].ID != 7 {
+		t.Fatalf("history: got %d %q", rec.Code, rec.Body.String())
+	}
+	if b.query.Text != "hi" || b.query.Limit != 5 || b.query.Since.IsZero() {
+		t.Fatalf("query = %+v", b.query)
+	}
+	if rec := serve(s, authed(http.MethodGet, "/history?limit=x", nil)); rec.Code != http.StatusBadRequest {
+		t.Fatalf("bad limit: got %d, want 400", rec.Code)
+	}
+}
+
+func TestServerMetrics(t *testing.T) {
+	s := &Server{Token: "secret", Backend: &fakeBackend{}}
+
+	rec := serve(s, authed(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("metrics: got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), "# TYPE stt_uploads_total counter\n") {
+		t.Fatalf("metrics body lacks stt_uploads_total:\n%s", rec.Body.String())
+	}
+	if rec := serve(s, authed(http.MethodPost, "/metrics", nil)); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST /metrics: got %d, want 405", rec.Code)
+	}
+}
+
+func TestCheckAddr(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:8765", "localhost:1", "[::1]:8765"} {
+		if err := CheckAddr(addr); err != nil {
+			t.Errorf("CheckAddr(%q) = %v", addr, err)
+		}
+	}
+	for _, addr := range []string{"0.0.0.0:8765", ":8765", "192.168.1.2:80", "127.0.0.1", "127.0.0.1:0", "localhost:http"} {
+		if err := CheckAddr(addr); err == nil {
+			t.Errorf("CheckAddr(%q) = nil, want error", addr)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package i18n
+
+import "syscall"
+
+var procGetUserDefaultUILanguage = syscall.NewLazyDLL("kernel32.dll").NewProc("GetUserDefaultUILanguage")
+
+// langChinese is the primary language ID of all Chinese locales.
+const langChinese = 0x04
+
+func systemLang() (Lang, bool) {
+	if err := procGetUserDefaultUILanguage.Find(); err != nil {
+		return "", false
+	}
+	langID, _, _ := procGetUserDefaultUILanguage.Call()
+	if langID&0x3ff == langChinese {
+		return ZH, true
+	}
+	return EN, true
+}
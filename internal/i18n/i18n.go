@@ -0,0 +1,113 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package i18n selects the language of user-facing CLI text and notifications.
+// Messages are keyed by their English source text, so an untranslated message
+// simply falls back to English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Lang is a supported UI language.
+type Lang string
+
+const (
+	ZH Lang = "zh"
+	EN Lang = "en"
+)
+
+var (
+	mu      sync.RWMutex
+	current = ZH
+)
+
+// Set selects the active language from a UI_LANG value. An empty value
+// detects the language from the environment.
+func Set(value string) {
+	lang := Resolve(value)
+	mu.Lock()
+	current = lang
+	mu.Unlock()
+}
+
+// Current returns the active language.
+func Current() Lang {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Resolve maps a UI_LANG value to a supported language. Unknown values and
+// an empty value fall back to environment detection.
+func Resolve(value string) Lang {
+	if lang, ok := parse(value); ok {
+		return lang
+	}
+	return Detect()
+}
+
+// Valid reports whether value is an accepted UI_LANG setting.
+func Valid(value string) bool {
+	if strings.TrimSpace(value) == "" {
+		return true
+	}
+	_, ok := parse(value)
+	return ok
+}
+
+// Detect guesses the language from LC_ALL, LC_MESSAGES, LANG, and on Windows
+// the user's UI language. It defaults to Chinese.
+func Detect() Lang {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if lang, ok := parse(os.Getenv(key)); ok {
+			return lang
+		}
+	}
+	if lang, ok := systemLang(); ok {
+		return lang
+	}
+	return ZH
+}
+
+func parse(value string) (Lang, bool) {
+	v := strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case v == "":
+		return "", false
+	case strings.HasPrefix(v, "zh"):
+		return ZH, true
+	case strings.HasPrefix(v, "en"):
+		return EN, true
+	}
+	return "", false
+}
+
+// T returns msg translated into the active language. msg is the English source
+// text and may be a format string for fmt.
+func T(msg string) string {
+	if Current() == EN {
+		return msg
+	}
+	if s, ok := zh[msg]; ok {
+		return s
+	}
+	return msg
+}
+
+// Sprintf formats the translation of format with args.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSetSelectsTranslation(t *testing.T) {
+	t.Cleanup(func() { Set("zh") })
+
+	Set("en")
+	if got := T("Paste success"); got != "Paste success" {
+		t.Fatalf("T(en) = %q, want English source", got)
+	}
+	Set("zh")
+	if got := T("Paste success"); got != "粘贴成功" {
+		t.Fatalf("T(zh) = %q, want Chinese translation", got)
+	}
+	if got := T("untranslated message"); got != "untranslated message" {
+		t.Fatalf("T(untranslated) = %q, want source text", got)
+	}
+	if got := Sprintf("invalid config: %v", "x"); got != "配置无效: x" {
+		t.Fatalf("Sprintf = %q", got)
+	}
+}
+
+func TestResolveDetectsFromEnvironment(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "en_US.UTF-8")
+	if got := Resolve(""); got != EN {
+		t.Fatalf("Resolve(\"\") with LANG=en_US = %q, want en", got)
+	}
+	if got := Resolve("zh-CN"); got != ZH {
+		t.Fatalf("Resolve(zh-CN) = %q, want zh", got)
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, v := range []string{"", "zh", "EN", "zh_CN"} {
+		if !Valid(v) {
+			t.Fatalf("Valid(%q) = false, want true", v)
+		}
+	}
+	if Valid("fr") {
+		t.Fatalf("Valid(fr) = true, want false")
+	}
+}
+
+// messageRe matches the literal messages passed to T and Sprintf, and the
+// runtime state messages, which the tray tooltip and log translate.
+var messageRe = regexp.MustCompile(`(?:i18n\.(?:T|Sprintf)\(|setState\(\w+, )("(?:[^"\\]|\\.)*")`)
+
+func TestCatalogCoversSourceMessages(t *testing.T) {
+	root := filepath.Join("..", "..")
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (d.Name() == "GUI" || strings.HasPrefix(d.Name(), ".")) && path != root {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, m := range messageRe.FindAllSubmatch(src, -1) {
+			msg, err := strconv.Unquote(string(m[1]))
+			if err != nil {
+				t.Errorf("%s: %v", path, err)
+				continue
+			}
+			if _, ok := zh[msg]; !ok {
+				t.Errorf("%s: no Chinese translation for %q", path, msg)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
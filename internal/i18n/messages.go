@@ -0,0 +1,364 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package i18n
+
+// zh holds Chinese translations keyed by the English source text.
+var zh = map[string]string{
+	// CLI startup
+	"failed to load env file '%s': %v":                         "加载 env 文件 '%s' 失败: %v",
+	"failed to print config: %v":                               "输出配置失败: %v",
+	"failed to load config '%s': %v":                           "加载配置文件 '%s' 失败: %v",
+	"failed to write default config: %v":                       "写入默认配置失败: %v",
+	"failed to write config template: %v":                      "写入配置模板失败: %v",
+	"annotated template written to %s":                         "带注释的配置模板已写入 %s",
+	"default config created at %s. Please edit it and re-run.": "已在 %s 生成默认配置，请编辑后重新运行。",
+	"failed to stat config '%s': %v":                           "读取配置 '%s' 状态失败: %v",
+	"invalid environment override: %v":                         "环境变量覆盖无效: %v",
+	"invalid config: %v":                                       "配置无效: %v",
+	"failed to start watchdog: %v":                             "启动看护进程失败: %v",
+	"file mode failed: %v":                                     "文件模式失败: %v",
+	"failed to open log file '%s': %v":                         "无法打开日志文件 '%s': %v",
+	"record mode failed: %v":                                   "录音模式失败: %v",
+	"ready. Use hotkeys to start/stop/pause/cancel.":           "就绪。使用热键开始/停止/暂停/取消录音。",
+
+	// stt config
+	"usage: stt config <encrypt|decrypt> [-config path]": "用法: stt config <encrypt|decrypt> [-config 路径]",
+	"failed to encrypt '%s': %v":                         "加密 '%s' 失败: %v",
+	"failed to decrypt '%s': %v":                         "解密 '%s' 失败: %v",
+	"TOKEN, API_ENDPOINT and CACHE_PASSPHRASE in %s are now encrypted for the current Windows user": "%s 中的 TOKEN、API_ENDPOINT 与 CACHE_PASSPHRASE 已使用当前 Windows 用户加密",
+	"TOKEN, API_ENDPOINT and CACHE_PASSPHRASE in %s are now stored in plain text":                   "%s 中的 TOKEN、API_ENDPOINT 与 CACHE_PASSPHRASE 已恢复为明文",
+
+	// stt history
+	"usage: stt history <list|search <query>|show <id>|tui [query]> [-config path] [-db path] [-since date] [-until date] [-limit n] [-json]": "用法: stt history <list|search <关键词>|show <编号>|tui [关键词]> [-config 路径] [-db 路径] [-since 日期] [-until 日期] [-limit 数量] [-json]",
+	"invalid -since '%s': %v":                                       "-since 参数 '%s' 无效: %v",
+	"invalid -until '%s': %v":                                       "-until 参数 '%s' 无效: %v",
+	"invalid entry id '%s'":                                         "记录编号 '%s' 无效",
+	"history database '%s' not found: %v":                           "未找到历史数据库 '%s': %v",
+	"failed to open history database '%s': %v":                      "打开历史数据库 '%s' 失败: %v",
+	"entry %d not found: %v":                                        "未找到记录 %d: %v",
+	"history query failed: %v":                                      "查询历史记录失败: %v",
+	"CACHE_DIR is not set; pass -db to point at a history database": "未设置 CACHE_DIR，请使用 -db 指定历史数据库",
+	"encrypted entries cannot be read: %v":                          "无法读取加密的记录: %v",
+	"STT history":                                                   "STT 历史记录",
+	"No transcripts found.":                                         "没有找到转写记录。",
+	"[Enter/j] down  [k] up  [number] select  [c] copy  [p] play  [r] re-transcribe  [/text] search  [g] refresh  [q] quit": "[回车/j] 下移  [k] 上移  [序号] 选择  [c] 复制  [p] 播放  [r] 重新转写  [/文本] 搜索  [g] 刷新  [q] 退出",
+	"unknown command '%s'":                               "未知命令 '%s'",
+	"This entry has no text":                             "该记录没有文本",
+	"This entry is encrypted and no cache key is loaded": "该记录已加密，但未加载缓存密钥",
+	"Copy failed: %v":                                    "复制失败: %v",
+	"Copied to clipboard":                                "已复制到剪贴板",
+	"This entry has no cached audio":                     "该记录没有缓存的音频",
+	"Audio file '%s' is missing":                         "音频文件 '%s' 不存在",
+	"Playback failed: %v":                                "播放失败: %v",
+	"Playing %s":                                         "正在播放 %s",
+	"Re-transcribing...":                                 "正在重新转写...",
+	"Re-transcription failed: %v":                        "重新转写失败: %v",
+	"Re-transcribed: %s":                                 "重新转写完成: %s",
+
+	// stt cache
+	"usage: stt cache <decrypt <file.enc>...|stats|prune|archive> [-config path] [-out dir] [-older-than days] [-max-size MB] [-dry-run] [-json]": "用法: stt cache <decrypt <文件.enc>...|stats|prune|archive> [-config 路径] [-out 目录] [-older-than 天数] [-max-size MB] [-dry-run] [-json]",
+	"CACHE_DIR is not set":              "未设置 CACHE_DIR",
+	"failed to scan cache dir '%s': %v": "扫描缓存目录 '%s' 失败: %v",
+	"nothing to prune; pass -older-than or -max-size, or set CACHE_MAX_AGE_DAYS or CACHE_MAX_SIZE_MB": "未指定清理条件，请使用 -older-than 或 -max-size，或设置 CACHE_MAX_AGE_DAYS、CACHE_MAX_SIZE_MB",
+	"would remove %d entries (%s)":                                   "将删除 %d 组缓存 (%s)",
+	"removed %d entries (%s)":                                        "已删除 %d 组缓存 (%s)",
+	"nothing to archive; pass -older-than or set CACHE_ARCHIVE_DAYS": "未指定归档条件，请使用 -older-than 或设置 CACHE_ARCHIVE_DAYS",
+	"would archive %d entries (%s)":                                  "将归档 %d 组缓存 (%s)",
+	"archived %d entries (%s)":                                       "已归档 %d 组缓存 (%s)",
+	"failed to load cache key for '%s': %v":                          "加载 '%s' 的缓存密钥失败: %v",
+	"decrypted %s -> %s":                                             "已解密 %s -> %s",
+
+	// stt queue
+	"usage: stt queue <list|flush> [-config path]":                 "用法: stt queue <list|flush> [-config 路径]",
+	"CACHE_DIR is not set; the retry queue lives in the cache dir": "未设置 CACHE_DIR，重试队列位于缓存目录中",
+	"%d transcribed, %d still pending":                             "已转写 %d 条，仍有 %d 条待处理",
+
+	// stt devices
+	"failed to list input devices: %v": "无法列出录音设备: %v",
+	"failed to list MIDI devices: %v":  "无法列出 MIDI 设备: %v",
+
+	// stt doctor
+	"config":            "配置",
+	"conversion":        "转换",
+	"ASR endpoint":      "ASR 端点",
+	"FAILED: %v":        "失败: %v",
+	"all checks passed": "全部检查通过",
+
+	// stt ctl
+	"usage: stt ctl <start|stop|toggle|pause|resume|cancel|status [-json]|stats [-json]|transcribe <file> [-config path]>": "用法: stt ctl <start|stop|toggle|pause|resume|cancel|status [-json]|stats [-json]|transcribe <文件> [-config 路径]>",
+	"%s failed: %v": "%s 失败: %v",
+
+	// stt service
+	"usage: stt service <install|uninstall|run> [-config path]":     "用法: stt service <install|uninstall|run> [-config 路径]",
+	"failed to install service %s: %v":                              "安装服务 %s 失败: %v",
+	"service %s installed and started; it starts the agent with %s": "服务 %s 已安装并启动，将使用 %s 启动代理",
+	"service %s removed": "服务 %s 已移除",
+	"stt service run is started by Windows; use stt service install": "stt service run 由 Windows 启动，请使用 stt service install",
+
+	// stt autostart
+	"usage: stt autostart <enable|disable|status> [-config path]": "用法: stt autostart <enable|disable|status> [-config 路径]",
+	"failed to create '%s': %v":                                   "创建 '%s' 失败: %v",
+	"failed to remove '%s': %v":                                   "删除 '%s' 失败: %v",
+	"STT starts with %s when you log on (%s)":                     "登录时将使用 %s 启动 STT（%s）",
+	"STT no longer starts when you log on":                        "登录时不再自动启动 STT",
+	"autostart is off":                                            "未启用开机自启",
+	"autostart is on: %s":                                         "已启用开机自启: %s",
+
+	// stt shell-integration
+	"usage: stt shell-integration <install|uninstall|status> [-config path]": "用法: stt shell-integration <install|uninstall|status> [-config 路径]",
+	"Transcribe with STT":                                                        "使用 STT 转写",
+	"failed to install shell integration: %v":                                    "添加右键菜单失败: %v",
+	"failed to remove shell integration: %v":                                     "移除右键菜单失败: %v",
+	"\"Transcribe with STT\" added to the context menu of audio and video files": "已在音频和视频文件的右键菜单中添加“使用 STT 转写”",
+	"\"Transcribe with STT\" removed from the context menu":                      "已从右键菜单中移除“使用 STT 转写”",
+	"shell integration is off":                                                   "未启用右键菜单",
+	"shell integration is on: %s":                                                "已启用右键菜单: %s",
+
+	// stt protocol
+	"usage: stt protocol <install|uninstall|status> [-config path]": "用法: stt protocol <install|uninstall|status> [-config 路径]",
+	"failed to register stt:// links: %v":                           "注册 stt:// 链接失败: %v",
+	"failed to unregister stt:// links: %v":                         "取消注册 stt:// 链接失败: %v",
+	"stt:// links now open STT":                                     "stt:// 链接现在由 STT 打开",
+	"stt:// links no longer open STT":                               "stt:// 链接不再由 STT 打开",
+	"stt:// links are not registered":                               "未注册 stt:// 链接",
+	"stt:// links are registered: %s":                               "已注册 stt:// 链接: %s",
+	"not an audio or video file: %s":                                "不是音频或视频文件: %s",
+
+	// stt update
+	"usage: stt update [-check] [-force]":                                         "用法: stt update [-check] [-force]",
+	"failed to check for updates: %v":                                             "检查更新失败: %v",
+	"STT is up to date (%s)":                                                      "STT 已是最新版本（%s）",
+	"this build's version is unknown; the latest release is %s":                   "无法确定当前构建的版本，最新发布版本为 %s",
+	"update available: %s -> %s":                                                  "有可用更新: %s -> %s",
+	"this build's version is unknown; run stt update -force to install %s anyway": "无法确定当前构建的版本；如仍要安装 %s，请运行 stt update -force",
+	"downloading %s...":                                                           "正在下载 %s...",
+	"download failed: %v":                                                         "下载失败: %v",
+	"failed to replace '%s': %v":                                                  "替换 '%s' 失败: %v",
+	"updated to %s; restart STT to use it":                                        "已更新到 %s，重新启动 STT 后生效",
+
+	// Stats
+	"Since: %s":                 "统计起始: %s",
+	"Recordings: %d (%.1f min)": "录音: %d 次（%.1f 分钟）",
+	"Transcriptions: %d ok, %d empty, %d failed": "转写: 成功 %d，空结果 %d，失败 %d",
+	"Transcribed audio: %.1f min":                "已转写音频: %.1f 分钟",
+	"Uploads: %d ok, %d failed, %d retries":      "上传: 成功 %d，失败 %d，重试 %d",
+	"Average upload latency: %.2fs":              "平均上传耗时: %.2f 秒",
+
+	// Tray
+	"Start/stop recording":                  "开始/停止录音",
+	"Transcribe file…":                      "转写文件…",
+	"Open history":                          "打开历史记录",
+	"Audio files":                           "音频文件",
+	"All files":                             "所有文件",
+	"no running STT instance to toggle: %v": "没有可切换录音的 STT 实例: %v",
+	"no running STT instance: %v":           "没有正在运行的 STT 实例: %v",
+	"unexpected reply: %v":                  "无法解析的回复: %v",
+	"transcript written to %s":              "转写结果已写入 %s",
+	"sent %s to the running instance, which reports the result in a notification": "已交给正在运行的实例转写，结果将以通知告知: %s",
+	"the running instance could not transcribe %s: %v":                            "正在运行的实例无法转写 %s: %v",
+	"Transcribe %s?":           "转写 %s？",
+	"Transcribe":               "转写",
+	"Pause/resume":             "暂停/继续",
+	"Cancel recording":         "取消录音",
+	"Open cache folder":        "打开缓存目录",
+	"Reload config":            "重新加载配置",
+	"Quit":                     "退出",
+	"config reloaded":          "配置已重新加载",
+	"config reload failed: %v": "重新加载配置失败: %v",
+	"Recording paused":         "录音已暂停",
+	"Recording resumed":        "录音已继续",
+	"Recording canceled":       "录音已取消",
+	"Recording paused because the workstation was locked":   "工作站已锁定，录音已暂停",
+	"Recording stopped because the workstation was locked":  "工作站已锁定，录音已停止",
+	"Recording canceled because the workstation was locked": "工作站已锁定，录音已取消",
+	"Recording stopped because the computer went to sleep":  "电脑进入睡眠，录音已停止",
+	"Scheduled job transcribed %d of %d files in %s":        "定时任务已转写 %d/%d 个文件：%s",
+	"Uploading ASR request":                                 "正在上传",
+	"Settings saved":                                        "设置已保存",
+	"Failed to register hotkeys":                            "热键注册失败",
+	"Recording start failed":                                "录音启动失败",
+	"Recording stop failed":                                 "录音停止失败",
+	"Recording failed":                                      "录音失败",
+	"Cancel failed":                                         "取消失败",
+	"Unexpected error":                                      "意外错误",
+	"FFmpeg conversion failed":                              "FFmpeg 转换失败",
+	"FFmpeg check failed":                                   "FFmpeg 检查失败",
+	"Transcription pasted":                                  "转写结果已粘贴",
+	"Transcription ready":                                   "转写完成",
+	"Recorded %s":                                           "已录音 %s",
+	"Profile: %s":                                           "配置: %s",
+	"Last transcription: %.1fs":                             "上次转写耗时: %.1f 秒",
+	"Recording %s":                                          "录音中 %s",
+	"Recording %s, %s left":                                 "录音中 %s，剩余 %s",
+	"Paused at %s":                                          "已暂停于 %s",
+	"Recording stops in %d seconds":                         "录音将在 %d 秒后自动停止",
+
+	// Notifications
+	"A new version of STT is available. Run stt update to install it.":    "STT 有新版本可用，运行 stt update 即可安装。",
+	"STT stopped unexpectedly and will restart in %s. Details are in %s.": "STT 意外退出，将在 %s 后重新启动。详细信息见 %s。",
+	"STT hit an unexpected error and kept running":                        "STT 遇到意外错误，已继续运行",
+	"Recording started":                         "开始录音",
+	"Recording finished":                        "录音结束",
+	"Upload failed":                             "上传失败",
+	"Request failed":                            "请求失败",
+	"Empty result from ASR":                     "ASR 返回结果为空",
+	"Paste failed":                              "粘贴失败",
+	"Paste success":                             "粘贴成功",
+	"ASR endpoint check failed":                 "ASR 端点检查失败",
+	"Upload failed; recording queued for retry": "上传失败，录音已加入重试队列",
+	"Transcribed %d queued recording(s)":        "已转写 %d 条排队的录音",
+	"Converting %d%%…":                          "正在转换 %d%%…",
+	"Uploading %d%%…":                           "正在上传 %d%%…",
+	"Waiting for transcription…":                "等待转写结果…",
+	"Click for details":                         "点击查看详情",
+
+	// Timings in the paste notification
+	"%s %.1fs": "%s %.1f 秒",
+	"stop":     "录音收尾",
+	"convert":  "转换",
+	"upload":   "上传",
+	"paste":    "粘贴",
+
+	// First-run guide
+	"STT setup": "STT 初始设置",
+	"Welcome to STT. Let's check that everything works.":                               "欢迎使用 STT。下面检查各项设置是否正常。",
+	"1/3 ASR endpoint reachable: %s":                                                   "1/3 ASR 端点可用: %s",
+	"1/3 ASR endpoint check failed: %s. Check API_ENDPOINT and TOKEN in the config.":   "1/3 ASR 端点检查失败: %s。请检查配置中的 API_ENDPOINT 和 TOKEN。",
+	"%s starts and stops recording, %s pauses and resumes, %s cancels.":                "%s 开始/停止录音，%s 暂停/继续，%s 取消。",
+	"Put the cursor in a text field, press %s, say a few words and press %s again.":    "请把光标放在任意文本框中，按 %s 说几句话，再按 %s 结束。",
+	"The test recording failed: %s. Fix the problem and try again.":                    "测试录音失败: %s。请排除问题后重试。",
+	"Recording failed: %s. Check INPUT_DEVICE (see stt devices) and try again.":        "录音失败: %s。请检查 INPUT_DEVICE（可用 stt devices 查看）后重试。",
+	"No speech was recognized. Check the microphone and INPUT_DEVICE, then try again.": "未识别到语音。请检查麦克风和 INPUT_DEVICE 后重试。",
+	"The test recording was pasted. Setup is complete.":                                "测试录音已成功粘贴，设置完成。",
+
+	// Console log
+	"%d more file(s) not offered":                                       "另有 %d 个文件未提示",
+	"%s is %s long, transcribing it in %d chunks":                       "%s 时长 %s，分为 %d 段转写",
+	"%s is already %s/%s, skipping conversion":                          "%s 已经是 %s/%s，跳过转码",
+	"%s on lock: %v":                                                    "锁屏时执行 %s 失败: %v",
+	"%s: nothing new to transcribe":                                     "%s: 没有需要转写的新文件",
+	"%s: transcribed %d of %d files":                                    "%s: 已转写 %d/%d 个文件",
+	"'%s' -> %s, next run %s":                                           "'%s' -> %s，下次运行 %s",
+	"MAX_RECORD_SECONDS (%d) reached; stopping":                         "已达到 MAX_RECORD_SECONDS（%d 秒），停止录音",
+	"POST_COMMAND failed: %v":                                           "POST_COMMAND 执行失败: %v",
+	"app registration failed: %v":                                       "注册通知应用失败: %v",
+	"archive failed: %v":                                                "归档失败: %v",
+	"archived %d entries":                                               "已归档 %d 条记录",
+	"cannot create %s: %v. Keeping files in cache-dir.":                 "无法创建 %s: %v。文件保留在缓存目录中。",
+	"cannot measure %s, keeping it: %v":                                 "无法读取 %s 的时长，仍然保留: %v",
+	"cannot measure %s, uploading it whole: %v":                         "无法读取 %s 的时长，整体上传: %v",
+	"cannot name cache files in %s: %v. Falling back to a timestamp.":   "无法为 %s 中的缓存文件命名: %v。改用时间戳。",
+	"check failed: %v":                                                  "检查失败: %v",
+	"failed remove %s: %v":                                              "删除 %s 失败: %v",
+	"failed to keep output as %s: %v":                                   "保存转码文件为 %s 失败: %v",
+	"failed to keep wav as %s: %v":                                      "保存 wav 为 %s 失败: %v",
+	"failed to open %s: %v":                                             "打开 %s 失败: %v",
+	"failed to persist recording, uploading directly: %v":               "保存录音失败，直接上传: %v",
+	"failed to queue %s: %v":                                            "加入重试队列失败 %s: %v",
+	"failed to record transcript: %v":                                   "写入历史记录失败: %v",
+	"failed to relocate %s: %v":                                         "移动 %s 失败: %v",
+	"failed to remove %s: %v":                                           "删除 %s 失败: %v",
+	"failed to start %s: %v":                                            "启动 %s 失败: %v",
+	"failed to summarize config: %v":                                    "整理配置摘要失败: %v",
+	"failed to update %s: %v":                                           "更新 %s 失败: %v",
+	"failed to write %s: %v":                                            "写入 %s 失败: %v",
+	"failed to write crash log: %v":                                     "写入崩溃日志失败: %v",
+	"failed to write crash report: %v":                                  "写入崩溃报告失败: %v",
+	"failed to write error details: %v":                                 "写入错误详情失败: %v",
+	"failed to write json to %s: %v":                                    "写入 json 到 %s 失败: %v",
+	"failed to write metadata to %s: %v":                                "写入元数据到 %s 失败: %v",
+	"failed to write transcript to %s: %v":                              "写入转写结果到 %s 失败: %v",
+	"failed: %s":                                                        "失败: %s",
+	"failed: %v":                                                        "失败: %v",
+	"history lookup for %s failed: %v":                                  "查询 %s 的历史记录失败: %v",
+	"leaving out %d files outside FILE_MIN_SECONDS to FILE_MAX_SECONDS": "跳过 %d 个时长不在 FILE_MIN_SECONDS 到 FILE_MAX_SECONDS 之间的文件",
+	"listen on %s failed: %v":                                           "监听 %s 失败: %v",
+	"listening on %s":                                                   "正在监听 %s",
+	"listening on '%s'":                                                 "正在监听 '%s'",
+	"listening on http://%s":                                            "正在监听 http://%s",
+	"listing input devices after resume: %v":                            "唤醒后列出输入设备失败: %v",
+	"measuring %d files":                                                "正在读取 %d 个文件的时长",
+	"no input devices after resume":                                     "唤醒后没有可用的输入设备",
+	"offering to transcribe %s":                                         "提示转写 %s",
+	"panic in %s: %v":                                                   "%s 中发生 panic: %v",
+	"progress: %v":                                                      "进度通知: %v",
+	"purge failed: %v":                                                  "清理失败: %v",
+	"purged %d entries (%d bytes)":                                      "已清理 %d 条记录（%d 字节）",
+	"re-registering hotkeys after resume: %v":                           "唤醒后重新注册热键失败: %v",
+	"read dir '%s' failed: %v":                                          "读取目录 '%s' 失败: %v",
+	"removed %s":                                                        "已删除 %s",
+	"report written to %s":                                              "报告已写入 %s",
+	"retry of %s failed: %v":                                            "重试 %s 失败: %v",
+	"running; cache dir %s":                                             "正在运行；缓存目录 %s",
+	"segmented upload failed, transcribing the whole recording: %v":     "分段上传失败，改为转写整段录音: %v",
+	"serve failed: %v":                                                  "服务出错: %v",
+	"skipping %d of %d files that are already transcribed":              "跳过已转写的 %d/%d 个文件",
+	"skipping %s: %v":                                                   "跳过 %s: %v",
+	"skipping unreadable %s: %v":                                        "跳过无法读取的 %s: %v",
+	"stop on suspend: %v":                                               "休眠时停止录音失败: %v",
+	"stt:// links are not registered; run `stt protocol install` for the Transcribe button to work": "stt:// 链接尚未注册；运行 `stt protocol install` 后“转写”按钮才能使用",
+	"token written to %s":                                   "令牌已写入 %s",
+	"transcribed %s (%s)":                                   "已转写 %s（%s）",
+	"transcribed chunk %d/%d":                               "已转写第 %d/%d 段",
+	"transcript stream is behind; dropping a transcript":    "转写结果订阅者处理过慢，丢弃一条结果",
+	"worker exited with code %d after %s; restarting in %s": "工作进程运行 %[2]s 后退出，退出码 %[1]d；%[3]s 后重启",
+	"RETRY_QUEUE or OFFLINE_FIRST with a CACHE_DIR is needed for the service to transcribe queued recordings": "服务需要 RETRY_QUEUE 或 OFFLINE_FIRST 以及 CACHE_DIR 才能转写队列中的录音",
+
+	// Cache directory
+	"cache-dir '%s' exists but is not a directory. Falling back to the data directory.": "cache-dir '%s' 已存在但不是目录，改用数据目录。",
+	"cache-dir path invalid '%s': %v. Falling back to the data directory.":              "cache-dir 路径 '%s' 无效: %v，改用数据目录。",
+	"cannot access cache-dir '%s': %v. Falling back to the data directory.":             "无法访问 cache-dir '%s': %v，改用数据目录。",
+	"cannot create cache-dir '%s': %v. Falling back to the data directory.":             "无法创建 cache-dir '%s': %v，改用数据目录。",
+	"created and using cache-dir: %s":                                                   "已创建并使用缓存目录: %s",
+	"using existing cache-dir: %s":                                                      "使用已有缓存目录: %s",
+
+	// Cache and history details
+	"Cache dir:  %s":     "缓存目录:  %s",
+	"Entries:    %d":     "条目数:    %d",
+	"Total size: %s":     "总大小:    %s",
+	"Oldest:     %s  %s": "最早:      %s  %s",
+	"Newest:     %s  %s": "最新:      %s  %s",
+	"ID:        %d":      "编号:  %d",
+	"Time:      %s":      "时间:  %s",
+	"Source:    %s":      "来源:  %s",
+	"Duration:  %.1fs":   "时长:  %.1f 秒",
+	"Provider:  %s":      "服务:  %s",
+	"Model:     %s":      "模型:  %s",
+	"Language:  %s":      "语言:  %s",
+	"Machine:   %s":      "机器:  %s",
+	"Latency:   %v":      "耗时:  %v",
+	"Steps:     stop %v, convert %v, upload %v, paste %v": "步骤:  录音收尾 %v，转码 %v，上传 %v，粘贴 %v",
+	"Audio:     %s":                               "音频:  %s",
+	"Status:    %s":                               "状态:  %s",
+	"Error:     %s":                               "错误:  %s",
+	"usage: stt open <stt://action>":              "用法: stt open <stt://动作>",
+	"usage: stt transcribe [file] [-config path]": "用法: stt transcribe [文件] [-config 路径]",
+
+	// Simulation
+	"using the built-in mock endpoint %s":                   "使用内置的模拟端点 %s",
+	"using the endpoint %s":                                 "使用端点 %s",
+	"paste: %s":                                             "粘贴: %s",
+	"%s of synthetic audio transcribed in %s":               "%s 的合成音频转写用时 %s",
+	"the endpoint returned no text for the synthetic audio": "端点未从合成音频中识别出文字",
+	"simulation passed":                                     "模拟通过",
+	"simulation failed: %v":                                 "模拟失败: %v",
+
+	// CA certificates
+	"%v; using the system certificates": "%v；改用系统证书",
+
+	// Device list
+	"No input devices found.":                                      "未找到录音设备。",
+	"* system default. Set INPUT_DEVICE to the index or the name.": "* 为系统默认设备。INPUT_DEVICE 可填编号或名称。",
+
+	// Push-to-talk
+	"push-to-talk is not available: this platform does not report hotkey releases, so the start hotkey toggles recording": "按住说话不可用：当前平台无法得知热键松开，开始热键将按切换方式工作",
+}
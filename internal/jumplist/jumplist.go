@@ -0,0 +1,24 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package jumplist registers the tasks shown when the taskbar button is
+// right-clicked, the Start menu shortcut that identifies the app and other
+// shortcuts to it.
+package jumplist
+
+// Task is a jump-list entry that runs this executable with Args. Minimized
+// starts its console window minimized, for tasks that only signal the
+// running instance.
+type Task struct {
+	Title     string
+	Args      string
+	Minimized bool
+}
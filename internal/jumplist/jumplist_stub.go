@@ -0,0 +1,31 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build !windows
+
+package jumplist
+
+import "fmt"
+
+// Set is not supported on non-Windows builds.
+func Set(tasks []Task) error {
+	return fmt.Errorf("jump lists not supported on this platform")
+}
+
+// Shortcut is not supported on non-Windows builds.
+func Shortcut(path, appID, icon string) error {
+	return fmt.Errorf("shortcuts not supported on this platform")
+}
+
+// Save is not supported on non-Windows builds.
+func Save(path, dir string, t Task) error {
+	return fmt.Errorf("shortcuts not supported on this platform")
+}
@@ -0,0 +1,365 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package jumplist
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	clsctxInprocServer      = 0x1
+	coinitApartmentThreaded = 0x2
+	rpcEChangedMode         = 0x80010106
+	vtLPWSTR                = 31
+	swShowNormal            = 1
+	swShowMinNoActive       = 7
+)
+
+// Vtable slots of the interfaces used, counting the IUnknown methods.
+const (
+	methodQueryInterface = 0
+	methodRelease        = 2
+
+	destListBeginList    = 4
+	destListAddUserTasks = 7
+	destListCommitList   = 8
+
+	collectionAddObject = 5
+
+	linkSetDescription      = 7
+	linkSetWorkingDirectory = 9
+	linkSetArguments        = 11
+	linkSetShowCmd          = 15
+	linkSetIconLocation     = 17
+	linkSetPath             = 20
+
+	storeSetValue = 6
+	storeCommit   = 7
+
+	persistFileSave = 6
+)
+
+var (
+	ole32                = syscall.NewLazyDLL("ole32.dll")
+	procCoInitializeEx   = ole32.NewProc("CoInitializeEx")
+	procCoUninitialize   = ole32.NewProc("CoUninitialize")
+	procCoCreateInstance = ole32.NewProc("CoCreateInstance")
+
+	clsidDestinationList            = guid{0x77f10cf0, 0x3db5, 0x4966, [8]byte{0xb5, 0x20, 0xb7, 0xc5, 0x4f, 0xd3, 0x5e, 0xd6}}
+	iidCustomDestinationList        = guid{0x6332debf, 0x87b5, 0x4670, [8]byte{0x90, 0xc0, 0x5e, 0x57, 0xb4, 0x08, 0xa4, 0x9e}}
+	clsidEnumerableObjectCollection = guid{0x2d3468c1, 0x36a7, 0x43b6, [8]byte{0xac, 0x24, 0xd3, 0xf0, 0x2f, 0xd9, 0x60, 0x7a}}
+	iidObjectCollection             = guid{0x5632b1a4, 0xe38a, 0x400a, [8]byte{0x92, 0x8a, 0xd4, 0xcd, 0x63, 0x23, 0x02, 0x95}}
+	iidObjectArray                  = guid{0x92ca9dcd, 0x5622, 0x4bba, [8]byte{0xa8, 0x05, 0x5e, 0x9f, 0x54, 0x1b, 0xd8, 0xc9}}
+	clsidShellLink                  = guid{0x00021401, 0x0000, 0x0000, [8]byte{0xc0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46}}
+	iidShellLinkW                   = guid{0x000214f9, 0x0000, 0x0000, [8]byte{0xc0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46}}
+	iidPropertyStore                = guid{0x886d8eeb, 0x8cf2, 0x4446, [8]byte{0x8d, 0x02, 0xcd, 0xba, 0x1d, 0xbd, 0xcf, 0x99}}
+	iidPersistFile                  = guid{0x0000010b, 0x0000, 0x0000, [8]byte{0xc0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46}}
+
+	// pkeyTitle is PKEY_Title, the text a jump-list task shows.
+	pkeyTitle = propertyKey{guid{0xf29f85e0, 0x4ff9, 0x1068, [8]byte{0xab, 0x91, 0x08, 0x00, 0x2b, 0x27, 0xb3, 0xd9}}, 2}
+	// pkeyAppUserModelID is PKEY_AppUserModel_ID.
+	pkeyAppUserModelID = propertyKey{guid{0x9f4c2855, 0x9f79, 0x4b39, [8]byte{0xa8, 0xd0, 0xe1, 0xd4, 0x2d, 0xe1, 0xd5, 0xf3}}, 5}
+)
+
+type guid struct {
+	data1 uint32
+	data2 uint16
+	data3 uint16
+	data4 [8]byte
+}
+
+type propertyKey struct {
+	fmtid guid
+	pid   uint32
+}
+
+// propVariant is a PROPVARIANT holding a VT_LPWSTR.
+type propVariant struct {
+	vt  uint16
+	_   [3]uint16
+	str *uint16
+	_   uintptr
+}
+
+// comObject is any COM interface pointer.
+type comObject struct {
+	vtbl *[32]uintptr
+}
+
+func (o *comObject) call(method int, args ...uintptr) uintptr {
+	r, _, _ := syscall.SyscallN(o.vtbl[method], append([]uintptr{uintptr(unsafe.Pointer(o))}, args...)...)
+	return r
+}
+
+func (o *comObject) release() {
+	if o != nil {
+		o.call(methodRelease)
+	}
+}
+
+// Set replaces the user tasks of this executable's jump list. The tasks run
+// in the current working directory.
+func Set(tasks []Task) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	uninit, err := initCOM()
+	if err != nil {
+		return err
+	}
+	defer uninit()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	list, err := create(&clsidDestinationList, &iidCustomDestinationList)
+	if err != nil {
+		return err
+	}
+	defer list.release()
+	var slots uint32
+	var removed *comObject
+	if hr := list.call(destListBeginList, uintptr(unsafe.Pointer(&slots)), uintptr(unsafe.Pointer(&iidObjectArray)), uintptr(unsafe.Pointer(&removed))); failed(hr) {
+		return hresultError("ICustomDestinationList.BeginList", hr)
+	}
+	defer removed.release()
+
+	coll, err := create(&clsidEnumerableObjectCollection, &iidObjectCollection)
+	if err != nil {
+		return err
+	}
+	defer coll.release()
+	for _, t := range tasks {
+		link, err := newLink(exe, dir, t)
+		if err != nil {
+			return err
+		}
+		hr := coll.call(collectionAddObject, uintptr(unsafe.Pointer(link)))
+		link.release()
+		if failed(hr) {
+			return hresultError("IObjectCollection.AddObject", hr)
+		}
+	}
+	// IObjectCollection derives from IObjectArray, which AddUserTasks takes.
+	if hr := list.call(destListAddUserTasks, uintptr(unsafe.Pointer(coll))); failed(hr) {
+		return hresultError("ICustomDestinationList.AddUserTasks", hr)
+	}
+	if hr := list.call(destListCommitList); failed(hr) {
+		return hresultError("ICustomDestinationList.CommitList", hr)
+	}
+	return nil
+}
+
+// newLink returns a shell link running exe with the task's arguments and
+// titled with its title.
+func newLink(exe, dir string, t Task) (*comObject, error) {
+	link, err := create(&clsidShellLink, &iidShellLinkW)
+	if err != nil {
+		return nil, err
+	}
+	exeW, argsW, dirW, title := utf16(exe), utf16(t.Args), utf16(dir), utf16(t.Title)
+	show := uintptr(swShowNormal)
+	if t.Minimized {
+		show = swShowMinNoActive
+	}
+	steps := []struct {
+		name   string
+		method int
+		args   []uintptr
+	}{
+		{"SetPath", linkSetPath, []uintptr{uintptr(unsafe.Pointer(exeW))}},
+		{"SetArguments", linkSetArguments, []uintptr{uintptr(unsafe.Pointer(argsW))}},
+		{"SetWorkingDirectory", linkSetWorkingDirectory, []uintptr{uintptr(unsafe.Pointer(dirW))}},
+		{"SetIconLocation", linkSetIconLocation, []uintptr{uintptr(unsafe.Pointer(exeW)), 0}},
+		{"SetDescription", linkSetDescription, []uintptr{uintptr(unsafe.Pointer(title))}},
+		{"SetShowCmd", linkSetShowCmd, []uintptr{show}},
+	}
+	for _, s := range steps {
+		if hr := link.call(s.method, s.args...); failed(hr) {
+			link.release()
+			return nil, hresultError("IShellLinkW."+s.name, hr)
+		}
+	}
+	// The steps only hold the strings as uintptrs.
+	runtime.KeepAlive(exeW)
+	runtime.KeepAlive(argsW)
+	runtime.KeepAlive(dirW)
+
+	var store *comObject
+	if hr := link.call(methodQueryInterface, uintptr(unsafe.Pointer(&iidPropertyStore)), uintptr(unsafe.Pointer(&store))); failed(hr) {
+		link.release()
+		return nil, hresultError("IShellLinkW.QueryInterface(IPropertyStore)", hr)
+	}
+	defer store.release()
+	pv := propVariant{vt: vtLPWSTR, str: title}
+	if hr := store.call(storeSetValue, uintptr(unsafe.Pointer(&pkeyTitle)), uintptr(unsafe.Pointer(&pv))); failed(hr) {
+		link.release()
+		return nil, hresultError("IPropertyStore.SetValue", hr)
+	}
+	if hr := store.call(storeCommit); failed(hr) {
+		link.release()
+		return nil, hresultError("IPropertyStore.Commit", hr)
+	}
+	return link, nil
+}
+
+// Shortcut writes a shell link to this executable at path, replacing any
+// existing one. The link carries appID as its AppUserModelID and shows
+// icon, an .ico file; it starts in the executable's directory.
+func Shortcut(path, appID, icon string) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	uninit, err := initCOM()
+	if err != nil {
+		return err
+	}
+	defer uninit()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	link, err := create(&clsidShellLink, &iidShellLinkW)
+	if err != nil {
+		return err
+	}
+	defer link.release()
+	exeW, dirW, iconW, appIDW, pathW := utf16(exe), utf16(filepath.Dir(exe)), utf16(icon), utf16(appID), utf16(path)
+	steps := []struct {
+		name   string
+		method int
+		args   []uintptr
+	}{
+		{"SetPath", linkSetPath, []uintptr{uintptr(unsafe.Pointer(exeW))}},
+		{"SetWorkingDirectory", linkSetWorkingDirectory, []uintptr{uintptr(unsafe.Pointer(dirW))}},
+		{"SetIconLocation", linkSetIconLocation, []uintptr{uintptr(unsafe.Pointer(iconW)), 0}},
+	}
+	for _, s := range steps {
+		if hr := link.call(s.method, s.args...); failed(hr) {
+			return hresultError("IShellLinkW."+s.name, hr)
+		}
+	}
+	runtime.KeepAlive(exeW)
+	runtime.KeepAlive(dirW)
+	runtime.KeepAlive(iconW)
+
+	var store *comObject
+	if hr := link.call(methodQueryInterface, uintptr(unsafe.Pointer(&iidPropertyStore)), uintptr(unsafe.Pointer(&store))); failed(hr) {
+		return hresultError("IShellLinkW.QueryInterface(IPropertyStore)", hr)
+	}
+	defer store.release()
+	pv := propVariant{vt: vtLPWSTR, str: appIDW}
+	if hr := store.call(storeSetValue, uintptr(unsafe.Pointer(&pkeyAppUserModelID)), uintptr(unsafe.Pointer(&pv))); failed(hr) {
+		return hresultError("IPropertyStore.SetValue", hr)
+	}
+	if hr := store.call(storeCommit); failed(hr) {
+		return hresultError("IPropertyStore.Commit", hr)
+	}
+
+	var file *comObject
+	if hr := link.call(methodQueryInterface, uintptr(unsafe.Pointer(&iidPersistFile)), uintptr(unsafe.Pointer(&file))); failed(hr) {
+		return hresultError("IShellLinkW.QueryInterface(IPersistFile)", hr)
+	}
+	defer file.release()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if hr := file.call(persistFileSave, uintptr(unsafe.Pointer(pathW)), 1); failed(hr) {
+		return hresultError("IPersistFile.Save", hr)
+	}
+	runtime.KeepAlive(pathW)
+	return nil
+}
+
+// Save writes a shell link that runs this executable as t does, starting in
+// dir, to path, replacing any existing one.
+func Save(path, dir string, t Task) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	uninit, err := initCOM()
+	if err != nil {
+		return err
+	}
+	defer uninit()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	link, err := newLink(exe, dir, t)
+	if err != nil {
+		return err
+	}
+	defer link.release()
+	var file *comObject
+	if hr := link.call(methodQueryInterface, uintptr(unsafe.Pointer(&iidPersistFile)), uintptr(unsafe.Pointer(&file))); failed(hr) {
+		return hresultError("IShellLinkW.QueryInterface(IPersistFile)", hr)
+	}
+	defer file.release()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	pathW := utf16(path)
+	if hr := file.call(persistFileSave, uintptr(unsafe.Pointer(pathW)), 1); failed(hr) {
+		return hresultError("IPersistFile.Save", hr)
+	}
+	runtime.KeepAlive(pathW)
+	return nil
+}
+
+// initCOM initializes COM on the calling thread, which must be locked, and
+// returns the function that undoes it.
+func initCOM() (func(), error) {
+	hr, _, _ := procCoInitializeEx.Call(0, coinitApartmentThreaded)
+	if hr == 0 || hr == 1 {
+		return func() { procCoUninitialize.Call() }, nil
+	}
+	if failed(hr) && uint32(hr) != rpcEChangedMode {
+		return nil, hresultError("CoInitializeEx", hr)
+	}
+	return func() {}, nil
+}
+
+func create(clsid, iid *guid) (*comObject, error) {
+	var obj *comObject
+	hr, _, _ := procCoCreateInstance.Call(uintptr(unsafe.Pointer(clsid)), 0, clsctxInprocServer,
+		uintptr(unsafe.Pointer(iid)), uintptr(unsafe.Pointer(&obj)))
+	if failed(hr) {
+		return nil, hresultError("CoCreateInstance", hr)
+	}
+	return obj, nil
+}
+
+func utf16(s string) *uint16 {
+	p, _ := syscall.UTF16PtrFromString(s)
+	return p
+}
+
+func failed(hr uintptr) bool {
+	return int32(uint32(hr)) < 0
+}
+
+func hresultError(name string, hr uintptr) error {
+	return fmt.Errorf("%s failed with HRESULT 0x%08X", name, uint32(hr))
+}
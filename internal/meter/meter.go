@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package meter shows a small always-on-top window with a live level meter
+// and a scrolling waveform of the microphone while recording.
+package meter
+
+import "math"
+
+// historySize is how many levels the waveform shows, one per pixel column.
+const historySize = 200
+
+// floorDB is the quietest level the meter shows; anything below is empty.
+const floorDB = -60
+
+// history keeps the most recent peak levels for the waveform and the last
+// RMS level for the bar.
+type history struct {
+	peaks [historySize]float64
+	next  int
+	rms   float64
+}
+
+// push adds the level of one buffer.
+func (h *history) push(peak, rms float64) {
+	h.peaks[h.next] = peak
+	h.next = (h.next + 1) % historySize
+	h.rms = rms
+}
+
+// values returns the peaks, oldest first.
+func (h *history) values() []float64 {
+	out := make([]float64, 0, historySize)
+	out = append(out, h.peaks[h.next:]...)
+	return append(out, h.peaks[:h.next]...)
+}
+
+// scale maps a level in full-scale fraction to 0..1 on a decibel scale from
+// floorDB to 0 dB, so speech fills the meter instead of staying near the
+// bottom.
+func scale(level float64) float64 {
+	if level <= 0 {
+		return 0
+	}
+	db := 20 * math.Log10(level)
+	switch {
+	case db <= floorDB:
+		return 0
+	case db >= 0:
+		return 1
+	}
+	return 1 - db/floorDB
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build !windows
+
+package meter
+
+import "fmt"
+
+// Window is the level meter window.
+type Window struct{}
+
+// Open is not supported on non-Windows builds.
+func Open() (*Window, error) {
+	return nil, fmt.Errorf("level meter not supported on this platform")
+}
+
+// Show shows or hides the window.
+func (w *Window) Show(on bool) {}
+
+// Push adds the level of one buffer of audio.
+func (w *Window) Push(peak, rms float64) {}
+
+// Close destroys the window.
+func (w *Window) Close() {}
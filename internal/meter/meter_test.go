@@ -0,0 +1,41 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package meter
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHistoryValuesAreOldestFirst(t *testing.T) {
+	var h history
+	for i := 1; i <= historySize+2; i++ {
+		h.push(float64(i), 0.5)
+	}
+	got := h.values()
+	if len(got) != historySize || got[0] != 3 || got[historySize-1] != historySize+2 {
+		t.Fatalf("values = %v..%v (%d), want 3..%d", got[0], got[len(got)-1], len(got), historySize+2)
+	}
+	if h.rms != 0.5 {
+		t.Fatalf("rms = %v, want the last pushed level", h.rms)
+	}
+}
+
+func TestScale(t *testing.T) {
+	tests := map[float64]float64{0: 0, 0.0001: 0, 1: 1, 2: 1, 0.001: 0, 0.1: 2.0 / 3}
+	for level, want := range tests {
+		if got := scale(level); math.Abs(got-want) > 1e-9 {
+			t.Errorf("scale(%v) = %v, want %v", level, got, want)
+		}
+	}
+}
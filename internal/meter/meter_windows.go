@@ -0,0 +1,354 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package meter
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	wmDestroy    = 0x0002
+	wmClose      = 0x0010
+	wmPaint      = 0x000F
+	wmEraseBkgnd = 0x0014
+	wmNCHitTest  = 0x0084
+	wmTimer      = 0x0113
+	wmApp        = 0x8000
+	wmShow       = wmApp + 1
+
+	wsPopup        = 0x80000000
+	wsExTopmost    = 0x00000008
+	wsExToolWindow = 0x00000080
+	wsExNoActivate = 0x08000000
+
+	swHide           = 0
+	swShowNoActivate = 4
+	htCaption        = 2
+	spiGetWorkArea   = 0x0030
+	srcCopy          = 0x00CC0020
+
+	repaintTimer    = 1
+	repaintInterval = 40 * time.Millisecond
+)
+
+// Layout of the window in pixels: the waveform on top, the level bar below.
+const (
+	margin     = 8
+	width      = historySize + 2*margin
+	waveHeight = 32
+	barHeight  = 8
+	height     = margin + waveHeight + margin/2 + barHeight + margin
+)
+
+// Colors as COLORREF (0x00BBGGRR).
+const (
+	colorBackground = 0x00202020
+	colorWave       = 0x00FAA560
+	colorTrack      = 0x00404040
+	colorLow        = 0x005EC522
+	colorHigh       = 0x0008B3EA
+	colorClip       = 0x004444EF
+)
+
+var (
+	user32 = syscall.NewLazyDLL("user32.dll")
+	gdi32  = syscall.NewLazyDLL("gdi32.dll")
+
+	procRegisterClassExW      = user32.NewProc("RegisterClassExW")
+	procCreateWindowExW       = user32.NewProc("CreateWindowExW")
+	procDestroyWindow         = user32.NewProc("DestroyWindow")
+	procDefWindowProcW        = user32.NewProc("DefWindowProcW")
+	procGetMessageW           = user32.NewProc("GetMessageW")
+	procDispatchMessageW      = user32.NewProc("DispatchMessageW")
+	procPostMessageW          = user32.NewProc("PostMessageW")
+	procPostQuitMessage       = user32.NewProc("PostQuitMessage")
+	procShowWindow            = user32.NewProc("ShowWindow")
+	procInvalidateRect        = user32.NewProc("InvalidateRect")
+	procBeginPaint            = user32.NewProc("BeginPaint")
+	procEndPaint              = user32.NewProc("EndPaint")
+	procFillRect              = user32.NewProc("FillRect")
+	procSetTimer              = user32.NewProc("SetTimer")
+	procKillTimer             = user32.NewProc("KillTimer")
+	procSystemParametersInfoW = user32.NewProc("SystemParametersInfoW")
+	procCreateSolidBrush      = gdi32.NewProc("CreateSolidBrush")
+	procCreateCompatibleDC    = gdi32.NewProc("CreateCompatibleDC")
+	procCreateCompatibleBmp   = gdi32.NewProc("CreateCompatibleBitmap")
+	procSelectObject          = gdi32.NewProc("SelectObject")
+	procDeleteObject          = gdi32.NewProc("DeleteObject")
+	procDeleteDC              = gdi32.NewProc("DeleteDC")
+	procBitBlt                = gdi32.NewProc("BitBlt")
+	procGetModuleHandleW      = syscall.NewLazyDLL("kernel32.dll").NewProc("GetModuleHandleW")
+)
+
+type wndClassEx struct {
+	Size       uint32
+	Style      uint32
+	WndProc    uintptr
+	ClsExtra   int32
+	WndExtra   int32
+	Instance   uintptr
+	Icon       uintptr
+	Cursor     uintptr
+	Background uintptr
+	MenuName   *uint16
+	ClassName  *uint16
+	IconSm     uintptr
+}
+
+type msg struct {
+	Hwnd    uintptr
+	Message uint32
+	WParam  uintptr
+	LParam  uintptr
+	Time    uint32
+	PtX     int32
+	PtY     int32
+}
+
+type rect struct {
+	Left, Top, Right, Bottom int32
+}
+
+type paintStruct struct {
+	Hdc         uintptr
+	Erase       int32
+	Paint       rect
+	Restore     int32
+	IncUpdate   int32
+	RGBReserved [32]byte
+}
+
+const className = "STTMeterWindow"
+
+var (
+	registerOnce sync.Once
+	registerErr  error
+
+	// active is the window the window procedure dispatches to. Only one
+	// meter exists at a time.
+	activeMu sync.Mutex
+	active   *Window
+)
+
+// Window is the level meter window. It starts hidden.
+type Window struct {
+	hwnd uintptr
+	done chan struct{}
+	once sync.Once
+
+	mu   sync.Mutex
+	hist history
+}
+
+// Open creates the hidden meter window in the bottom-right corner of the
+// work area. It can be dragged elsewhere and never takes the focus, so
+// pasting still goes to the window the user is typing in.
+func Open() (*Window, error) {
+	w := &Window{done: make(chan struct{})}
+	resultCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer close(w.done)
+
+		if err := w.create(); err != nil {
+			resultCh <- err
+			return
+		}
+		activeMu.Lock()
+		active = w
+		activeMu.Unlock()
+		resultCh <- nil
+
+		var m msg
+		for {
+			ret, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+			if int32(ret) <= 0 {
+				break
+			}
+			procDispatchMessageW.Call(uintptr(unsafe.Pointer(&m)))
+		}
+		activeMu.Lock()
+		active = nil
+		activeMu.Unlock()
+	}()
+
+	select {
+	case err := <-resultCh:
+		if err != nil {
+			return nil, err
+		}
+		return w, nil
+	case <-time.After(2 * time.Second):
+		return nil, fmt.Errorf("timeout creating level meter window")
+	}
+}
+
+func (w *Window) create() error {
+	instance, _, _ := procGetModuleHandleW.Call(0)
+	registerOnce.Do(func() {
+		wc := wndClassEx{
+			WndProc:   syscall.NewCallback(wndProc),
+			Instance:  instance,
+			ClassName: syscall.StringToUTF16Ptr(className),
+		}
+		wc.Size = uint32(unsafe.Sizeof(wc))
+		if r, _, err := procRegisterClassExW.Call(uintptr(unsafe.Pointer(&wc))); r == 0 {
+			registerErr = fmt.Errorf("RegisterClassExW failed: %v", err)
+		}
+	})
+	if registerErr != nil {
+		return registerErr
+	}
+
+	var area rect
+	procSystemParametersInfoW.Call(spiGetWorkArea, 0, uintptr(unsafe.Pointer(&area)), 0)
+	x := area.Right - width - 2*margin
+	y := area.Bottom - height - 2*margin
+	hwnd, _, err := procCreateWindowExW.Call(wsExTopmost|wsExToolWindow|wsExNoActivate,
+		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(className))),
+		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr("STT"))),
+		wsPopup, uintptr(x), uintptr(y), width, height, 0, 0, instance, 0)
+	if hwnd == 0 {
+		return fmt.Errorf("CreateWindowExW failed: %v", err)
+	}
+	w.hwnd = hwnd
+	return nil
+}
+
+// Show shows the window with an empty waveform, or hides it.
+func (w *Window) Show(on bool) {
+	var wParam uintptr
+	if on {
+		w.mu.Lock()
+		w.hist = history{}
+		w.mu.Unlock()
+		wParam = 1
+	}
+	// Timers belong to the window's thread, so it starts or stops its own.
+	procPostMessageW.Call(w.hwnd, wmShow, wParam, 0)
+}
+
+// Push adds the peak and RMS level of one buffer of audio.
+func (w *Window) Push(peak, rms float64) {
+	w.mu.Lock()
+	w.hist.push(peak, rms)
+	w.mu.Unlock()
+}
+
+// Close destroys the window and waits for its thread to exit.
+func (w *Window) Close() {
+	w.once.Do(func() {
+		procPostMessageW.Call(w.hwnd, wmClose, 0, 0)
+		<-w.done
+	})
+}
+
+func (w *Window) show(on bool) {
+	if on {
+		procShowWindow.Call(w.hwnd, swShowNoActivate)
+		procSetTimer.Call(w.hwnd, repaintTimer, uintptr(repaintInterval/time.Millisecond), 0)
+		return
+	}
+	procKillTimer.Call(w.hwnd, repaintTimer)
+	procShowWindow.Call(w.hwnd, swHide)
+}
+
+// paint draws the waveform and level bar off-screen and copies them to the
+// window in one step, so the meter does not flicker.
+func (w *Window) paint() {
+	var ps paintStruct
+	hdc, _, _ := procBeginPaint.Call(w.hwnd, uintptr(unsafe.Pointer(&ps)))
+	defer procEndPaint.Call(w.hwnd, uintptr(unsafe.Pointer(&ps)))
+
+	mem, _, _ := procCreateCompatibleDC.Call(hdc)
+	defer procDeleteDC.Call(mem)
+	bmp, _, _ := procCreateCompatibleBmp.Call(hdc, width, height)
+	defer procDeleteObject.Call(bmp)
+	old, _, _ := procSelectObject.Call(mem, bmp)
+	defer procSelectObject.Call(mem, old)
+
+	w.mu.Lock()
+	peaks := w.hist.values()
+	level := scale(w.hist.rms)
+	w.mu.Unlock()
+
+	fill(mem, rect{0, 0, width, height}, colorBackground)
+
+	wave, _, _ := procCreateSolidBrush.Call(colorWave)
+	mid := int32(margin + waveHeight/2)
+	for i, p := range peaks {
+		half := int32(scale(p) * waveHeight / 2)
+		x := int32(margin + i)
+		r := rect{x, mid - half, x + 1, mid + half + 1}
+		procFillRect.Call(mem, uintptr(unsafe.Pointer(&r)), wave)
+	}
+	procDeleteObject.Call(wave)
+
+	top := int32(margin + waveHeight + margin/2)
+	fill(mem, rect{margin, top, margin + historySize, top + barHeight}, colorTrack)
+	color := uint32(colorLow)
+	switch {
+	case level > 0.95:
+		color = colorClip
+	case level > 0.8:
+		color = colorHigh
+	}
+	fill(mem, rect{margin, top, margin + int32(level*historySize), top + barHeight}, color)
+
+	procBitBlt.Call(hdc, 0, 0, width, height, mem, 0, 0, srcCopy)
+}
+
+func fill(dc uintptr, r rect, color uint32) {
+	brush, _, _ := procCreateSolidBrush.Call(uintptr(color))
+	procFillRect.Call(dc, uintptr(unsafe.Pointer(&r)), brush)
+	procDeleteObject.Call(brush)
+}
+
+func wndProc(hwnd, message, wParam, lParam uintptr) uintptr {
+	activeMu.Lock()
+	w := active
+	activeMu.Unlock()
+	if w != nil && hwnd == w.hwnd {
+		switch message {
+		case wmShow:
+			w.show(wParam != 0)
+			return 0
+		case wmTimer:
+			procInvalidateRect.Call(hwnd, 0, 0)
+			return 0
+		case wmPaint:
+			w.paint()
+			return 0
+		case wmEraseBkgnd:
+			return 1
+		case wmNCHitTest:
+			// Dragging anywhere moves the window.
+			return htCaption
+		case wmClose:
+			procDestroyWindow.Call(hwnd)
+			return 0
+		case wmDestroy:
+			procPostQuitMessage.Call(0)
+			return 0
+		}
+	}
+	r, _, _ := procDefWindowProcW.Call(hwnd, message, wParam, lParam)
+	return r
+}
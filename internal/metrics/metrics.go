@@ -0,0 +1,206 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package metrics counts what STT has done since it started: recordings,
+// transcriptions, uploads, retries and upload latencies. The local HTTP API
+// serves the counts in the Prometheus text format and `stt ctl stats` prints
+// them.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the upload latency
+// histogram.
+var latencyBuckets = []float64{0.5, 1, 2, 5, 10, 30, 60, 120}
+
+// Snapshot is the counts at one point in time.
+type Snapshot struct {
+	Since           time.Time `json:"since"`
+	Recordings      int64     `json:"recordings"`
+	RecordedSeconds float64   `json:"recorded_seconds"`
+	// Transcriptions counts finished transcriptions by source ("record",
+	// "file", ...) and history status ("ok", "empty", "failed").
+	Transcriptions map[string]map[string]int64 `json:"transcriptions"`
+	// AudioSeconds is the length of the audio transcribed without an error.
+	AudioSeconds   float64   `json:"audio_seconds"`
+	Uploads        int64     `json:"uploads"`
+	UploadFailures int64     `json:"upload_failures"`
+	Retries        int64     `json:"retries"`
+	Latency        Histogram `json:"latency"`
+}
+
+// Histogram is a Prometheus-style histogram of upload latencies.
+type Histogram struct {
+	Count      int64   `json:"count"`
+	SumSeconds float64 `json:"sum_seconds"`
+	// Buckets are cumulative: each counts the uploads that took at most LE
+	// seconds.
+	Buckets []Bucket `json:"buckets"`
+}
+
+// Bucket is one bucket of a Histogram.
+type Bucket struct {
+	LE    float64 `json:"le"`
+	Count int64   `json:"count"`
+}
+
+type registry struct {
+	mu sync.Mutex
+	s  Snapshot
+}
+
+func newRegistry() *registry {
+	r := &registry{s: Snapshot{Since: time.Now(), Transcriptions: map[string]map[string]int64{}}}
+	for _, le := range latencyBuckets {
+		r.s.Latency.Buckets = append(r.s.Latency.Buckets, Bucket{LE: le})
+	}
+	return r
+}
+
+var std = newRegistry()
+
+// Recording counts a recording of length d that was handed on to be
+// transcribed.
+func Recording(d time.Duration) { std.recording(d) }
+
+// Upload counts a request to the ASR service that made attempts attempts,
+// all but the first retries, took latency in all and failed with err. A
+// request that never got to an attempt is not counted.
+func Upload(latency time.Duration, attempts int, err error) { std.upload(latency, attempts, err) }
+
+// Transcription counts a finished transcription of audio long from source
+// with a history status.
+func Transcription(source, status string, audio time.Duration) {
+	std.transcription(source, status, audio)
+}
+
+// Read returns the counts so far.
+func Read() Snapshot { return std.read() }
+
+// WritePrometheus writes the counts so far to w in the Prometheus text
+// exposition format.
+func WritePrometheus(w io.Writer) error { return std.read().WritePrometheus(w) }
+
+func (r *registry) recording(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.s.Recordings++
+	r.s.RecordedSeconds += d.Seconds()
+}
+
+func (r *registry) upload(latency time.Duration, attempts int, err error) {
+	if attempts < 1 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.s.UploadFailures++
+	} else {
+		r.s.Uploads++
+	}
+	r.s.Retries += int64(attempts - 1)
+	h := &r.s.Latency
+	h.Count++
+	h.SumSeconds += latency.Seconds()
+	for i := range h.Buckets {
+		if latency.Seconds() <= h.Buckets[i].LE {
+			h.Buckets[i].Count++
+		}
+	}
+}
+
+func (r *registry) transcription(source, status string, audio time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bySource := r.s.Transcriptions[source]
+	if bySource == nil {
+		bySource = map[string]int64{}
+		r.s.Transcriptions[source] = bySource
+	}
+	bySource[status]++
+	if status != "failed" {
+		r.s.AudioSeconds += audio.Seconds()
+	}
+}
+
+func (r *registry) read() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.s
+	s.Transcriptions = make(map[string]map[string]int64, len(r.s.Transcriptions))
+	for source, counts := range r.s.Transcriptions {
+		s.Transcriptions[source] = maps.Clone(counts)
+	}
+	s.Latency.Buckets = slices.Clone(r.s.Latency.Buckets)
+	return s
+}
+
+// WritePrometheus writes s to w in the Prometheus text exposition format.
+func (s Snapshot) WritePrometheus(w io.Writer) error {
+	p := &promWriter{w: w}
+	p.metric("stt_start_time_seconds", "gauge", "Unix time STT started at.")
+	p.sample("stt_start_time_seconds", "", float64(s.Since.UnixMilli())/1000)
+	p.metric("stt_recordings_total", "counter", "Recordings handed on to be transcribed.")
+	p.sample("stt_recordings_total", "", float64(s.Recordings))
+	p.metric("stt_recorded_seconds_total", "counter", "Length of those recordings.")
+	p.sample("stt_recorded_seconds_total", "", s.RecordedSeconds)
+	p.metric("stt_transcriptions_total", "counter", "Finished transcriptions by source and status.")
+	for _, source := range slices.Sorted(maps.Keys(s.Transcriptions)) {
+		counts := s.Transcriptions[source]
+		for _, status := range slices.Sorted(maps.Keys(counts)) {
+			p.sample("stt_transcriptions_total", fmt.Sprintf(`{source=%q,status=%q}`, source, status), float64(counts[status]))
+		}
+	}
+	p.metric("stt_transcribed_audio_seconds_total", "counter", "Length of the audio transcribed without an error.")
+	p.sample("stt_transcribed_audio_seconds_total", "", s.AudioSeconds)
+	p.metric("stt_uploads_total", "counter", "Requests to the ASR service by result.")
+	p.sample("stt_uploads_total", `{result="ok"}`, float64(s.Uploads))
+	p.sample("stt_uploads_total", `{result="failed"}`, float64(s.UploadFailures))
+	p.metric("stt_upload_retries_total", "counter", "Upload attempts after the first of a request.")
+	p.sample("stt_upload_retries_total", "", float64(s.Retries))
+	p.metric("stt_upload_latency_seconds", "histogram", "Time a request to the ASR service took, retries included.")
+	for _, b := range s.Latency.Buckets {
+		p.sample("stt_upload_latency_seconds_bucket", fmt.Sprintf(`{le="%g"}`, b.LE), float64(b.Count))
+	}
+	p.sample("stt_upload_latency_seconds_bucket", `{le="+Inf"}`, float64(s.Latency.Count))
+	p.sample("stt_upload_latency_seconds_sum", "", s.Latency.SumSeconds)
+	p.sample("stt_upload_latency_seconds_count", "", float64(s.Latency.Count))
+	return p.err
+}
+
+// promWriter writes the lines of the exposition format, keeping the first
+// write error.
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *promWriter) metric(name, kind, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (p *promWriter) sample(name, labels string, v float64) {
+	p.printf("%s%s %g\n", name, labels, v)
+}
+
+func (p *promWriter) printf(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistryCounts(t *testing.T) {
+	r := newRegistry()
+	r.recording(90 * time.Second)
+	r.upload(1500*time.Millisecond, 1, nil)
+	r.upload(40*time.Second, 3, errors.New("exceeded max retries"))
+	r.upload(0, 0, errors.New("canceled"))
+	r.transcription("record", "ok", 90*time.Second)
+	r.transcription("file", "failed", time.Hour)
+
+	s := r.read()
+	if s.Recordings != 1 || s.RecordedSeconds != 90 {
+		t.Fatalf("recordings = %d, %gs; want 1, 90s", s.Recordings, s.RecordedSeconds)
+	}
+	if s.Uploads != 1 || s.UploadFailures != 1 || s.Retries != 2 {
+		t.Fatalf("uploads = %d ok, %d failed, %d retries; want 1, 1, 2", s.Uploads, s.UploadFailures, s.Retries)
+	}
+	if s.AudioSeconds != 90 {
+		t.Fatalf("audio = %gs, want 90s without the failed file", s.AudioSeconds)
+	}
+	if s.Transcriptions["record"]["ok"] != 1 || s.Transcriptions["file"]["failed"] != 1 {
+		t.Fatalf("transcriptions = %v", s.Transcriptions)
+	}
+	if s.Latency.Count != 2 || s.Latency.SumSeconds != 41.5 {
+		t.Fatalf("latency = %d, %gs; want 2, 41.5s", s.Latency.Count, s.Latency.SumSeconds)
+	}
+	for _, b := range s.Latency.Buckets {
+		want := int64(0)
+		switch {
+		case b.LE >= 40:
+			want = 2
+		case b.LE >= 1.5:
+			want = 1
+		}
+		if b.Count != want {
+			t.Fatalf("bucket le=%g = %d, want %d", b.LE, b.Count, want)
+		}
+	}
+
+	// A snapshot does not change with later counts.
+	r.transcription("record", "ok", 0)
+	if s.Transcriptions["record"]["ok"] != 1 {
+		t.Fatal("snapshot shares its counts with the registry")
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	r := newRegistry()
+	r.upload(3*time.Second, 2, nil)
+	r.transcription("record", "ok", 10*time.Second)
+
+	var b strings.Builder
+	if err := r.read().WritePrometheus(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"# TYPE stt_recordings_total counter\nstt_recordings_total 0\n",
+		`stt_transcriptions_total{source="record",status="ok"} 1` + "\n",
+		"stt_transcribed_audio_seconds_total 10\n",
+		`stt_uploads_total{result="ok"} 1` + "\n",
+		"stt_upload_retries_total 1\n",
+		"# TYPE stt_upload_latency_seconds histogram\n",
+		`stt_upload_latency_seconds_bucket{le="2"} 0` + "\n",
+		`stt_upload_latency_seconds_bucket{le="5"} 1` + "\n",
+		`stt_upload_latency_seconds_bucket{le="+Inf"} 1` + "\n",
+		"stt_upload_latency_seconds_sum 3\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
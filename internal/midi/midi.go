@@ -0,0 +1,98 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package midi listens to a MIDI input device, so recording can be driven
+// from pad controllers, foot pedals and similar hardware.
+package midi
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Key identifies a note or a control change on any channel.
+type Key struct {
+	CC     bool // a control change rather than a note
+	Number int  // note or controller number, 0..127
+}
+
+func (k Key) String() string {
+	if k.CC {
+		return fmt.Sprintf("cc%d", k.Number)
+	}
+	return strconv.Itoa(k.Number)
+}
+
+// Actions are the action names a MIDI_MAP may bind.
+var Actions = []string{"toggle", "start", "stop", "pause", "resume", "cancel"}
+
+// DefaultMap binds the first three pads of the usual General MIDI drum
+// layout (C1, C#1 and D1).
+const DefaultMap = "36=toggle,37=pause,38=cancel"
+
+// ParseMap parses a MIDI_MAP, a comma-separated list of key=action pairs
+// where a key is a note number or "cc" and a controller number, e.g.
+// "36=toggle,cc64=toggle".
+func ParseMap(s string) (map[Key]string, error) {
+	m := make(map[Key]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, action, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("'%s' is not key=action", pair)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		action = strings.ToLower(strings.TrimSpace(action))
+		var k Key
+		if rest, ok := strings.CutPrefix(name, "cc"); ok {
+			k.CC = true
+			name = rest
+		}
+		n, err := strconv.Atoi(name)
+		if err != nil || n < 0 || n > 127 {
+			return nil, fmt.Errorf("invalid key in '%s' (want 0..127 or cc0..cc127)", pair)
+		}
+		k.Number = n
+		if !slices.Contains(Actions, action) {
+			return nil, fmt.Errorf("unknown action in '%s' (allowed: %s)", pair, strings.Join(Actions, ", "))
+		}
+		if _, dup := m[k]; dup {
+			return nil, fmt.Errorf("%s is bound more than once", k)
+		}
+		m[k] = action
+	}
+	return m, nil
+}
+
+// Decode returns the key a short MIDI message presses: a note-on with a
+// nonzero velocity, or a control change to 64 or more, as a sustain pedal
+// sends when pushed down. Other messages report false.
+func Decode(msg uint32) (Key, bool) {
+	status := msg & 0xF0
+	data1 := int(msg>>8) & 0x7F
+	data2 := int(msg>>16) & 0x7F
+	switch status {
+	case 0x90:
+		if data2 > 0 {
+			return Key{Number: data1}, true
+		}
+	case 0xB0:
+		if data2 >= 64 {
+			return Key{CC: true, Number: data1}, true
+		}
+	}
+	return Key{}, false
+}
@@ -0,0 +1,26 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build !windows
+
+package midi
+
+import "fmt"
+
+// Devices is not supported on non-Windows builds.
+func Devices() ([]string, error) {
+	return nil, fmt.Errorf("MIDI input not supported on this platform")
+}
+
+// Listen is not supported on non-Windows builds.
+func Listen(device string, fn func(Key)) (func(), error) {
+	return nil, fmt.Errorf("MIDI input not supported on this platform")
+}
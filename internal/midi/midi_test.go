@@ -0,0 +1,53 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package midi
+
+import "testing"
+
+func TestParseMap(t *testing.T) {
+	m, err := ParseMap(" 36=toggle, CC64=Stop ,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 2 || m[Key{Number: 36}] != "toggle" || m[Key{CC: true, Number: 64}] != "stop" {
+		t.Fatalf("ParseMap = %v", m)
+	}
+	if m, err := ParseMap(DefaultMap); err != nil || len(m) != 3 {
+		t.Fatalf("ParseMap(DefaultMap) = %v, %v", m, err)
+	}
+	for _, bad := range []string{"36", "128=toggle", "cc=toggle", "36=record", "36=toggle,36=pause"} {
+		if _, err := ParseMap(bad); err == nil {
+			t.Errorf("ParseMap(%q) = nil error", bad)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		msg  uint32
+		key  Key
+		want bool
+	}{
+		{0x7F2491, Key{Number: 36}, true},           // note on, channel 2
+		{0x002490, Key{}, false},                    // note on with velocity 0 is a note off
+		{0x402480, Key{}, false},                    // note off
+		{0x7F40B0, Key{CC: true, Number: 64}, true}, // sustain pedal down
+		{0x0040B0, Key{}, false},                    // sustain pedal up
+	}
+	for _, tt := range tests {
+		k, ok := Decode(tt.msg)
+		if ok != tt.want || k != tt.key {
+			t.Errorf("Decode(%#x) = %v, %v; want %v, %v", tt.msg, k, ok, tt.key, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package midi
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	callbackFunction = 0x00030000
+	mimData          = 0x3C3
+	// eventBuffer is how many key presses may wait for fn before newer ones
+	// are dropped.
+	eventBuffer = 16
+)
+
+var (
+	winmm                 = syscall.NewLazyDLL("winmm.dll")
+	procMidiInGetNumDevs  = winmm.NewProc("midiInGetNumDevs")
+	procMidiInGetDevCapsW = winmm.NewProc("midiInGetDevCapsW")
+	procMidiInOpen        = winmm.NewProc("midiInOpen")
+	procMidiInStart       = winmm.NewProc("midiInStart")
+	procMidiInStop        = winmm.NewProc("midiInStop")
+	procMidiInReset       = winmm.NewProc("midiInReset")
+	procMidiInClose       = winmm.NewProc("midiInClose")
+	callbackOnce          sync.Once
+	callbackPtr           uintptr
+	listenersMu           sync.Mutex
+	listeners             = map[uintptr]chan Key{}
+)
+
+type midiInCaps struct {
+	Mid           uint16
+	Pid           uint16
+	DriverVersion uint32
+	Pname         [32]uint16
+	Support       uint32
+}
+
+// Devices returns the names of the MIDI input devices.
+func Devices() ([]string, error) {
+	n, _, _ := procMidiInGetNumDevs.Call()
+	var names []string
+	for id := uintptr(0); id < n; id++ {
+		var caps midiInCaps
+		if r, _, _ := procMidiInGetDevCapsW.Call(id, uintptr(unsafe.Pointer(&caps)), unsafe.Sizeof(caps)); r != 0 {
+			return nil, fmt.Errorf("midiInGetDevCaps failed for device %d: MMRESULT %d", id, r)
+		}
+		names = append(names, syscall.UTF16ToString(caps.Pname[:]))
+	}
+	return names, nil
+}
+
+// Listen opens the first MIDI input whose name contains device
+// (case-insensitive) and calls fn for each key pressed on it until the
+// returned function is called.
+func Listen(device string, fn func(Key)) (func(), error) {
+	names, err := Devices()
+	if err != nil {
+		return nil, err
+	}
+	id := -1
+	for i, name := range names {
+		if strings.Contains(strings.ToLower(name), strings.ToLower(device)) {
+			id = i
+			break
+		}
+	}
+	if id < 0 {
+		return nil, fmt.Errorf("no MIDI input matches '%s' (found: %s)", device, strings.Join(names, ", "))
+	}
+
+	// The driver calls back on its own thread, where little more than
+	// queueing the key is allowed, so fn runs on a goroutine instead.
+	callbackOnce.Do(func() {
+		callbackPtr = syscall.NewCallback(midiInProc)
+	})
+	var h uintptr
+	if r, _, _ := procMidiInOpen.Call(uintptr(unsafe.Pointer(&h)), uintptr(id), callbackPtr, 0, callbackFunction); r != 0 {
+		return nil, fmt.Errorf("midiInOpen failed for '%s': MMRESULT %d", names[id], r)
+	}
+	keys := make(chan Key, eventBuffer)
+	listenersMu.Lock()
+	listeners[h] = keys
+	listenersMu.Unlock()
+	if r, _, _ := procMidiInStart.Call(h); r != 0 {
+		closeInput(h)
+		return nil, fmt.Errorf("midiInStart failed for '%s': MMRESULT %d", names[id], r)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for k := range keys {
+			fn(k)
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			_, _, _ = procMidiInStop.Call(h)
+			closeInput(h)
+			<-done
+		})
+	}, nil
+}
+
+// closeInput closes h and its key channel once no callback can use it.
+func closeInput(h uintptr) {
+	_, _, _ = procMidiInReset.Call(h)
+	_, _, _ = procMidiInClose.Call(h)
+	listenersMu.Lock()
+	keys := listeners[h]
+	delete(listeners, h)
+	listenersMu.Unlock()
+	if keys != nil {
+		close(keys)
+	}
+}
+
+func midiInProc(h, msg, instance, param1, param2 uintptr) uintptr {
+	if msg != mimData {
+		return 0
+	}
+	k, ok := Decode(uint32(param1))
+	if !ok {
+		return 0
+	}
+	listenersMu.Lock()
+	keys := listeners[h]
+	if keys != nil {
+		select {
+		case keys <- k:
+		default:
+		}
+	}
+	listenersMu.Unlock()
+	return 0
+}
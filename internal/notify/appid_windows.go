@@ -0,0 +1,90 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package notify
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gen2brain/beeep"
+	"golang.org/x/sys/windows/registry"
+
+	"stt/internal/i18n"
+	"stt/internal/jumplist"
+)
+
+// AppID is the AppUserModelID notifications are shown under.
+const AppID = "JoeyKot.STT"
+
+// appName is the name Action Center shows for AppID.
+const appName = "STT"
+
+//go:embed stt.ico
+var appIcon []byte
+
+var registerOnce sync.Once
+
+// register identifies STT to Windows before the first notification, so
+// toasts are grouped under "STT" with its icon instead of an unknown app or
+// PowerShell. Failures only cost the branding and are logged once.
+func register() {
+	registerOnce.Do(func() {
+		beeep.AppName = AppID
+		if err := registerApp(); err != nil {
+			fmt.Printf("[notify] %s\n", i18n.Sprintf("app registration failed: %v", err))
+		}
+	})
+}
+
+// registerApp writes the icon to the user's local app data, names AppID in
+// the registry and points a Start menu shortcut carrying AppID at this
+// executable.
+func registerApp() error {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return err
+	}
+	icon := filepath.Join(dir, appName, "stt.ico")
+	if old, err := os.ReadFile(icon); err != nil || !bytes.Equal(old, appIcon) {
+		if err := os.MkdirAll(filepath.Dir(icon), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(icon, appIcon, 0o644); err != nil {
+			return err
+		}
+	}
+
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, `Software\Classes\AppUserModelId\`+AppID, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+	if err := key.SetStringValue("DisplayName", appName); err != nil {
+		return err
+	}
+	if err := key.SetStringValue("IconUri", icon); err != nil {
+		return err
+	}
+
+	appData := os.Getenv("APPDATA")
+	if appData == "" {
+		return fmt.Errorf("APPDATA is not set; no Start menu shortcut created")
+	}
+	programs := filepath.Join(appData, "Microsoft", "Windows", "Start Menu", "Programs")
+	return jumplist.Shortcut(filepath.Join(programs, appName+".lnk"), AppID, icon)
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build darwin
+
+package notify
+
+import (
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Notify shows a macOS notification, unless quiet mode suppresses it.
+func Notify(title, message string) {
+	if Suppressed() {
+		return
+	}
+	_ = displayNotification(title, message)
+}
+
+// NotifyFile shows a notification that opens file when clicked. Clicks need
+// terminal-notifier on PATH; without it this is a plain notification.
+func NotifyFile(title, message, file string) {
+	if Suppressed() {
+		return
+	}
+	if err := terminalNotifier(title, message, fileURL(file)); err != nil {
+		_ = displayNotification(title, message)
+	}
+}
+
+// NotifyAction shows a notification that opens link when clicked. macOS
+// notifications from the command line cannot carry buttons, so label is
+// unused. Clicks need terminal-notifier on PATH; without it this is a plain
+// notification.
+func NotifyAction(title, message, label, link string) {
+	if Suppressed() {
+		return
+	}
+	if err := terminalNotifier(title, message, link); err != nil {
+		_ = displayNotification(title, message)
+	}
+}
+
+// displayNotification posts a notification through AppleScript.
+func displayNotification(title, message string) error {
+	script := "display notification " + appleScriptString(message) + " with title " + appleScriptString(title)
+	return exec.Command("osascript", "-e", script).Run()
+}
+
+// terminalNotifier posts a notification that opens target when clicked.
+func terminalNotifier(title, message, target string) error {
+	path, err := exec.LookPath("terminal-notifier")
+	if err != nil {
+		return err
+	}
+	return exec.Command(path, "-title", title, "-message", message, "-open", target).Run()
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// fileURL returns the file:// URL of path.
+func fileURL(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	u := url.URL{Scheme: "file", Path: path}
+	return u.String()
+}
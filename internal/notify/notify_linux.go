@@ -0,0 +1,135 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build linux
+
+package notify
+
+import (
+	"os/exec"
+	"sync"
+
+	dbusnotify "github.com/esiqveland/notify"
+	"github.com/gen2brain/beeep"
+	"github.com/godbus/dbus/v5"
+)
+
+var (
+	notifierOnce sync.Once
+	notifier     dbusnotify.Notifier
+
+	linksMu sync.Mutex
+	// links maps the id of a clickable notification to what it opens.
+	links = make(map[uint32]string)
+)
+
+// Notify shows a desktop notification, unless quiet mode suppresses it.
+func Notify(title, message string) {
+	if Suppressed() {
+		return
+	}
+	_ = beeep.Notify(title, message, "")
+}
+
+// NotifyFile shows a notification that opens file when clicked. It falls
+// back to a plain notification where the notification server is not
+// reachable over D-Bus.
+func NotifyFile(title, message, file string) {
+	if Suppressed() {
+		return
+	}
+	if !send(title, message, file, dbusnotify.NewDefaultAction("Open")) {
+		_ = beeep.Notify(title, message, "")
+	}
+}
+
+// NotifyAction shows a notification with a button labelled label that opens
+// link, as does clicking the notification. It falls back to a plain
+// notification where the notification server is not reachable over D-Bus.
+func NotifyAction(title, message, label, link string) {
+	if Suppressed() {
+		return
+	}
+	if !send(title, message, link, dbusnotify.NewDefaultAction(label), dbusnotify.Action{Key: "open", Label: label}) {
+		_ = beeep.Notify(title, message, "")
+	}
+}
+
+// send posts a notification whose actions all hand target to xdg-open and
+// reports whether the notification server accepted it.
+func send(title, message, target string, actions ...dbusnotify.Action) bool {
+	n := session()
+	if n == nil {
+		return false
+	}
+	id, err := n.SendNotification(dbusnotify.Notification{
+		AppName:       beeep.AppName,
+		Summary:       title,
+		Body:          message,
+		Actions:       actions,
+		ExpireTimeout: dbusnotify.ExpireTimeoutSetByNotificationServer,
+	})
+	if err != nil {
+		return false
+	}
+	linksMu.Lock()
+	links[id] = target
+	linksMu.Unlock()
+	return true
+}
+
+// session connects to the notification server on first use. The connection
+// stays open so that clicks on earlier notifications still reach onAction.
+func session() dbusnotify.Notifier {
+	notifierOnce.Do(func() {
+		conn, err := dbus.SessionBusPrivate()
+		if err != nil {
+			return
+		}
+		if err := conn.Auth(nil); err != nil {
+			conn.Close()
+			return
+		}
+		if err := conn.Hello(); err != nil {
+			conn.Close()
+			return
+		}
+		n, err := dbusnotify.New(conn, dbusnotify.WithOnAction(onAction), dbusnotify.WithOnClosed(onClosed))
+		if err != nil {
+			conn.Close()
+			return
+		}
+		notifier = n
+	})
+	return notifier
+}
+
+// onAction opens the file or link behind a clicked notification.
+func onAction(s *dbusnotify.ActionInvokedSignal) {
+	linksMu.Lock()
+	target, ok := links[s.ID]
+	delete(links, s.ID)
+	linksMu.Unlock()
+	if !ok || target == "" {
+		return
+	}
+	cmd := exec.Command("xdg-open", target)
+	if err := cmd.Start(); err == nil {
+		go cmd.Wait()
+	}
+}
+
+// onClosed forgets notifications that were dismissed without a click.
+func onClosed(s *dbusnotify.NotificationClosedSignal) {
+	linksMu.Lock()
+	delete(links, s.ID)
+	linksMu.Unlock()
+}
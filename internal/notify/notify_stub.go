@@ -9,9 +9,15 @@
 // implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
 // See <https://www.gnu.org/licenses/> for more details.
 
-//go:build !windows
+//go:build !windows && !linux && !darwin
 
 package notify
 
 // Notify is a no-op on non-Windows builds.
 func Notify(title, message string) {}
+
+// NotifyFile is a no-op on non-Windows builds.
+func NotifyFile(title, message, file string) {}
+
+// NotifyAction is a no-op on non-Windows builds.
+func NotifyAction(title, message, label, link string) {}
@@ -13,9 +13,70 @@
 
 package notify
 
-import "github.com/gen2brain/beeep"
+import (
+	"net/url"
+	"path/filepath"
 
-// Notify shows a Windows notification.
+	"git.sr.ht/~jackmordaunt/go-toast"
+	"github.com/gen2brain/beeep"
+)
+
+// Notify shows a Windows notification, unless quiet mode suppresses it.
 func Notify(title, message string) {
+	if Suppressed() {
+		return
+	}
+	register()
 	_ = beeep.Notify(title, message, "")
 }
+
+// NotifyFile shows a notification that opens file when clicked. It falls
+// back to a plain notification where toasts are not available.
+func NotifyFile(title, message, file string) {
+	if Suppressed() {
+		return
+	}
+	register()
+	n := toast.Notification{
+		AppID:               beeep.AppName,
+		Title:               title,
+		Body:                message,
+		ActivationType:      toast.Protocol,
+		ActivationArguments: fileURL(file),
+	}
+	if err := n.Push(); err != nil {
+		_ = beeep.Notify(title, message, "")
+	}
+}
+
+// NotifyAction shows a notification with a button labelled label that opens
+// link, as does clicking the notification. It falls back to a plain
+// notification where toasts are not available.
+func NotifyAction(title, message, label, link string) {
+	if Suppressed() {
+		return
+	}
+	register()
+	n := toast.Notification{
+		AppID:               beeep.AppName,
+		Title:               title,
+		Body:                message,
+		ActivationType:      toast.Protocol,
+		ActivationArguments: link,
+		Actions: []toast.Action{
+			{Type: toast.Protocol, Content: label, Arguments: link},
+		},
+	}
+	if err := n.Push(); err != nil {
+		_ = beeep.Notify(title, message, "")
+	}
+}
+
+// fileURL returns the file:/// URL of path.
+func fileURL(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	u := url.URL{Scheme: "file", Path: "/" + filepath.ToSlash(path)}
+	return u.String()
+}
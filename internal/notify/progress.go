@@ -0,0 +1,102 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package notify
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"stt/internal/i18n"
+)
+
+// Progress is a notification with a progress bar that is updated in place
+// instead of stacking a new toast for every step. Updates are applied in the
+// background; when they arrive faster than they can be shown, only the latest
+// one is.
+type Progress struct {
+	title string
+	tag   string
+
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	next    *progressStep
+	running bool
+	closed  bool
+
+	// Touched only by the goroutine applying steps.
+	shown bool
+	seq   int
+}
+
+type progressStep struct {
+	status string
+	value  float64
+	close  bool
+}
+
+var progressTags atomic.Uint32
+
+// NewProgress returns a progress notification titled title. Nothing is shown
+// before the first Update.
+func NewProgress(title string) *Progress {
+	return &Progress{title: title, tag: fmt.Sprintf("progress-%d", progressTags.Add(1))}
+}
+
+// Update shows status with the bar at value, from 0 to 1, or an animated bar
+// without a value when value is negative. Quiet mode holds back the update.
+func (p *Progress) Update(status string, value float64) {
+	if Suppressed() {
+		return
+	}
+	p.push(progressStep{status: status, value: value})
+}
+
+// Close removes the notification and waits until it is gone. Later updates
+// are ignored.
+func (p *Progress) Close() {
+	p.push(progressStep{close: true})
+	p.wg.Wait()
+}
+
+func (p *Progress) push(s progressStep) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = s.close
+	p.next = &s
+	if !p.running {
+		p.running = true
+		p.wg.Add(1)
+		go p.run()
+	}
+}
+
+func (p *Progress) run() {
+	defer p.wg.Done()
+	for {
+		p.mu.Lock()
+		s := p.next
+		p.next = nil
+		if s == nil {
+			p.running = false
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+		if err := p.apply(*s); err != nil {
+			fmt.Printf("[notify] %s\n", i18n.Sprintf("progress: %v", err))
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package notify
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"unicode/utf16"
+
+	"git.sr.ht/~jackmordaunt/go-toast"
+	"github.com/gen2brain/beeep"
+)
+
+// progressGroup groups the progress toasts in the notification center.
+const progressGroup = "stt"
+
+// progressXML binds every text to the notification data, so updates only
+// send new values and nothing needs XML escaping.
+const progressXML = `<toast><visual><binding template="ToastGeneric"><text>{title}</text><progress value="{value}" status="{status}"/></binding></visual><audio silent="true"/></toast>`
+
+// apply shows, updates or removes the toast with a PowerShell script, as
+// go-toast does for its fallback, since go-toast cannot tag or update toasts.
+func (p *Progress) apply(s progressStep) error {
+	register()
+	var b strings.Builder
+	b.WriteString("[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null\n")
+	b.WriteString("[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] | Out-Null\n")
+	fmt.Fprintf(&b, "$appID = %s\n", psQuote(beeep.AppName))
+	switch {
+	case s.close:
+		if !p.shown {
+			return nil
+		}
+		fmt.Fprintf(&b, "[Windows.UI.Notifications.ToastNotificationManager]::History.Remove(%s, %s, $appID)\n", psQuote(p.tag), psQuote(progressGroup))
+	default:
+		p.seq++
+		b.WriteString("$data = New-Object Windows.UI.Notifications.NotificationData\n")
+		fmt.Fprintf(&b, "$data.Values['title'] = %s\n", psQuote(p.title))
+		fmt.Fprintf(&b, "$data.Values['status'] = %s\n", psQuote(s.status))
+		value := "indeterminate"
+		if s.value >= 0 {
+			value = strconv.FormatFloat(s.value, 'f', 2, 64)
+		}
+		fmt.Fprintf(&b, "$data.Values['value'] = %s\n", psQuote(value))
+		fmt.Fprintf(&b, "$data.SequenceNumber = %d\n", p.seq)
+		b.WriteString("$notifier = [Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($appID)\n")
+		if p.shown {
+			fmt.Fprintf(&b, "$notifier.Update($data, %s, %s) | Out-Null\n", psQuote(p.tag), psQuote(progressGroup))
+			break
+		}
+		// Register the app ID the way beeep's toasts do, in case no other
+		// notification has been shown yet.
+		if err := toast.SetAppData(toast.AppData{AppID: beeep.AppName}); err != nil {
+			return err
+		}
+		b.WriteString("$xml = New-Object Windows.Data.Xml.Dom.XmlDocument\n")
+		fmt.Fprintf(&b, "$xml.LoadXml(%s)\n", psQuote(progressXML))
+		b.WriteString("$toast = New-Object Windows.UI.Notifications.ToastNotification $xml\n")
+		fmt.Fprintf(&b, "$toast.Tag = %s\n", psQuote(p.tag))
+		fmt.Fprintf(&b, "$toast.Group = %s\n", psQuote(progressGroup))
+		b.WriteString("$toast.Data = $data\n")
+		b.WriteString("$notifier.Show($toast)\n")
+		p.shown = true
+	}
+	return runPowerShell(b.String())
+}
+
+// runPowerShell runs script hidden, passed base64 encoded so non-ASCII text
+// survives the command line.
+func runPowerShell(script string) error {
+	u := utf16.Encode([]rune(script))
+	buf := make([]byte, len(u)*2)
+	for i, c := range u {
+		buf[2*i] = byte(c)
+		buf[2*i+1] = byte(c >> 8)
+	}
+	cmd := exec.Command("PowerShell", "-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-EncodedCommand", base64.StdEncoding.EncodeToString(buf))
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("powershell: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// psQuote returns s as a single-quoted PowerShell string.
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
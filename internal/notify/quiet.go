@@ -0,0 +1,29 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package notify
+
+import "sync/atomic"
+
+var quietMode atomic.Bool
+
+// SetQuietMode turns automatic quiet mode on or off. While it is on,
+// notifications are dropped whenever Busy reports a full-screen app, a
+// presentation or quiet hours.
+func SetQuietMode(on bool) {
+	quietMode.Store(on)
+}
+
+// Suppressed reports whether notifications and sounds should stay silent
+// right now.
+func Suppressed() bool {
+	return quietMode.Load() && Busy()
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package notify
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// QUERY_USER_NOTIFICATION_STATE values that mean the user should not be
+// disturbed.
+const (
+	qunsBusy                 = 2
+	qunsRunningD3DFullScreen = 3
+	qunsPresentationMode     = 4
+	qunsQuietTime            = 6
+)
+
+var procSHQueryUserNotificationState = syscall.NewLazyDLL("shell32.dll").NewProc("SHQueryUserNotificationState")
+
+// Busy reports whether a full-screen app or presentation is running, or
+// Windows is in its quiet hours, as Windows itself decides for notifications.
+func Busy() bool {
+	if err := procSHQueryUserNotificationState.Find(); err != nil {
+		return false
+	}
+	var state int32
+	if hr, _, _ := procSHQueryUserNotificationState.Call(uintptr(unsafe.Pointer(&state))); hr != 0 {
+		return false
+	}
+	switch state {
+	case qunsBusy, qunsRunningD3DFullScreen, qunsPresentationMode, qunsQuietTime:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package posthook runs the POST_COMMAND hook with each transcript, so
+// local automation can pick transcripts up without changes to STT.
+package posthook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timeout bounds how long a hook may run before it is killed.
+const Timeout = time.Minute
+
+// waitDelay bounds how long Run waits for the hook's output once the shell
+// has exited or been killed: a program the hook started in the background
+// (start "" notepad, foo &) keeps the output pipe open.
+var waitDelay = 5 * time.Second
+
+// maxOutput limits how much of a failed hook's output goes into its error.
+const maxOutput = 500
+
+// Meta describes a transcription. The hook gets it in STT_* environment
+// variables.
+type Meta struct {
+	Source   string // "record" or "file"
+	Profile  string
+	Provider string
+	Model    string
+	Language string
+	Latency  time.Duration
+	Time     time.Time
+}
+
+// Env returns m as environment variables. Source goes in STT_AUDIO_SOURCE
+// rather than STT_SOURCE, which config.ApplyEnv reads as the SOURCE setting
+// in an stt the command runs.
+func (m Meta) Env() []string {
+	return []string{
+		"STT_AUDIO_SOURCE=" + m.Source,
+		"STT_PROFILE=" + m.Profile,
+		"STT_PROVIDER=" + m.Provider,
+		"STT_MODEL=" + m.Model,
+		"STT_LANGUAGE=" + m.Language,
+		"STT_LATENCY_MS=" + strconv.FormatInt(m.Latency.Milliseconds(), 10),
+		"STT_TIME=" + m.Time.Format(time.RFC3339),
+	}
+}
+
+// Run runs command through the shell (cmd.exe on Windows, sh elsewhere)
+// with text as UTF-8 on stdin and m in the environment, and waits up to
+// Timeout for it to exit.
+func Run(ctx context.Context, command, text string, m Meta) error {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	cmd := shellCommand(ctx, command)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Env = append(os.Environ(), m.Env()...)
+	cmd.WaitDelay = waitDelay
+	out, err := cmd.CombinedOutput()
+	// The shell succeeded and only its background programs still hold the
+	// output, which Run stops waiting for.
+	if errors.Is(err, exec.ErrWaitDelay) {
+		return nil
+	}
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if len(msg) > maxOutput {
+			msg = msg[:maxOutput] + "..."
+		}
+		if msg == "" {
+			return err
+		}
+		return fmt.Errorf("%w: %s", err, msg)
+	}
+	return nil
+}
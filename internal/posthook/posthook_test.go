@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package posthook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunPassesTextAndMeta(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "out.txt")
+	m := Meta{Source: "record", Model: "whisper-1", Latency: 1500 * time.Millisecond, Time: time.Now()}
+	if err := Run(context.Background(), `{ cat; echo " $STT_AUDIO_SOURCE $STT_MODEL $STT_LATENCY_MS"; } > '`+out+`'`, "你好", m); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "你好 record whisper-1 1500\n"; string(got) != want {
+		t.Fatalf("hook wrote %q, want %q", got, want)
+	}
+
+	err = Run(context.Background(), "echo broken >&2; exit 3", "", m)
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("failing hook: err = %v", err)
+	}
+}
+
+func TestRunDoesNotWaitForBackgroundPrograms(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	defer func(d time.Duration) { waitDelay = d }(waitDelay)
+	waitDelay = 100 * time.Millisecond
+	start := time.Now()
+	if err := Run(context.Background(), "sleep 30 &", "", Meta{}); err != nil {
+		t.Fatalf("hook with a background program: err = %v", err)
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Fatalf("Run took %s, want it not to wait for the background program", d)
+	}
+}
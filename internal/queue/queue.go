@@ -0,0 +1,155 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package queue keeps recordings whose upload failed in a pending/ directory
+// so they can be transcribed later. Each item is an audio file plus a JSON
+// sidecar with the same base name.
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"stt/internal/atomicfile"
+	"stt/internal/i18n"
+)
+
+// DirName is the queue directory created inside the cache directory.
+const DirName = "pending"
+
+// Item is one queued recording.
+type Item struct {
+	ID        string        `json:"id"`
+	Audio     string        `json:"audio"` // file name inside the queue directory
+	CreatedAt time.Time     `json:"created_at"`
+	Source    string        `json:"source"`
+	Duration  time.Duration `json:"duration_ns"`
+	Attempts  int           `json:"attempts"`
+	LastError string        `json:"last_error,omitempty"`
+
+	// NeedsConvert marks a raw recording that still has to go through ffmpeg
+	// before it can be uploaded.
+	NeedsConvert bool `json:"needs_convert,omitempty"`
+}
+
+// Queue is a directory of pending recordings.
+type Queue struct {
+	dir string
+}
+
+// Path returns the queue directory inside cacheDir.
+func Path(cacheDir string) string {
+	return filepath.Join(cacheDir, DirName)
+}
+
+// Open returns the queue in dir, creating the directory if needed.
+func Open(dir string) (*Queue, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create queue dir '%s': %w", dir, err)
+	}
+	return &Queue{dir: dir}, nil
+}
+
+// Dir returns the queue directory.
+func (q *Queue) Dir() string {
+	return q.dir
+}
+
+// AudioPath returns the full path of the item's audio file.
+func (q *Queue) AudioPath(it Item) string {
+	return filepath.Join(q.dir, it.Audio)
+}
+
+// Add moves the audio file at src into the queue.
+func (q *Queue) Add(src string, it Item) (Item, error) {
+	return q.add(it, filepath.Ext(src), func(dst string) error { return os.Rename(src, dst) })
+}
+
+// AddWith stores a new item whose audio is written by write, for callers that
+// transform the audio on the way in (e.g. encryption). ext is the stored
+// file extension including the dot.
+func (q *Queue) AddWith(ext string, it Item, write func(dst string) error) (Item, error) {
+	return q.add(it, ext, write)
+}
+
+func (q *Queue) add(it Item, ext string, write func(dst string) error) (Item, error) {
+	if it.CreatedAt.IsZero() {
+		it.CreatedAt = time.Now()
+	}
+	it.ID = it.CreatedAt.Format("20060102-150405") + "-" + strings.ReplaceAll(uuid.New().String(), "-", "")[:8]
+	it.Audio = it.ID + ext
+	if err := write(q.AudioPath(it)); err != nil {
+		return Item{}, err
+	}
+	if err := q.Update(it); err != nil {
+		_ = os.Remove(q.AudioPath(it))
+		return Item{}, err
+	}
+	return it, nil
+}
+
+// Update rewrites the sidecar of it. The write goes through a temporary file
+// so a crash never leaves a truncated sidecar behind.
+func (q *Queue) Update(it Item) error {
+	b, err := json.MarshalIndent(it, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(q.metaPath(it.ID), b, 0644)
+}
+
+// Remove deletes it from the queue.
+func (q *Queue) Remove(it Item) error {
+	if err := os.Remove(q.AudioPath(it)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Remove(q.metaPath(it.ID))
+}
+
+// List returns the queued items, oldest first. Sidecars whose audio file is
+// missing are skipped.
+func (q *Queue) List() ([]Item, error) {
+	matches, err := filepath.Glob(filepath.Join(q.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var items []Item
+	for _, m := range matches {
+		b, err := os.ReadFile(m)
+		if err != nil {
+			return nil, err
+		}
+		var it Item
+		if err := json.Unmarshal(b, &it); err != nil {
+			fmt.Printf("[queue] %s\n", i18n.Sprintf("skipping unreadable %s: %v", m, err))
+			continue
+		}
+		if _, err := os.Stat(q.AudioPath(it)); err != nil {
+			continue
+		}
+		items = append(items, it)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+	return items, nil
+}
+
+func (q *Queue) metaPath(id string) string {
+	return filepath.Join(q.dir, id+".json")
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package queue
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAddListUpdateRemove(t *testing.T) {
+	dir := t.TempDir()
+	q, err := Open(Path(dir))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	older := time.Date(2026, 1, 1, 8, 0, 0, 0, time.Local)
+	for i, name := range []string{"b.ogg", "a.ogg"} {
+		src := filepath.Join(dir, name)
+		if err := os.WriteFile(src, []byte(name), 0644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		if _, err := q.Add(src, Item{CreatedAt: older.Add(-time.Duration(i) * time.Minute), Source: "record"}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if _, err := os.Stat(src); !os.IsNotExist(err) {
+			t.Fatalf("source %s still exists after Add: %v", src, err)
+		}
+	}
+
+	items, err := q.List()
+	if err != nil || len(items) != 2 {
+		t.Fatalf("List = %v, %v; want 2 items", items, err)
+	}
+	if b, _ := os.ReadFile(q.AudioPath(items[0])); string(b) != "a.ogg" {
+		t.Fatalf("first item audio = %q, want the oldest recording", b)
+	}
+
+	items[0].Attempts, items[0].LastError = 3, "offline"
+	if err := q.Update(items[0]); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := q.Remove(items[1]); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	items, err = q.List()
+	if err != nil || len(items) != 1 || items[0].Attempts != 3 || items[0].LastError != "offline" {
+		t.Fatalf("List after update/remove = %#v, %v", items, err)
+	}
+}
+
+func TestAddWithWritesAudio(t *testing.T) {
+	q, err := Open(Path(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	it, err := q.AddWith(".ogg.enc", Item{}, func(dst string) error {
+		return os.WriteFile(dst, []byte("sealed"), 0644)
+	})
+	if err != nil {
+		t.Fatalf("AddWith failed: %v", err)
+	}
+	if filepath.Ext(it.Audio) != ".enc" || it.CreatedAt.IsZero() {
+		t.Fatalf("AddWith item = %#v", it)
+	}
+	if b, err := os.ReadFile(q.AudioPath(it)); err != nil || string(b) != "sealed" {
+		t.Fatalf("queued audio = %q, %v", b, err)
+	}
+}
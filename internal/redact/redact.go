@@ -0,0 +1,130 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package redact masks credentials in what STT prints: debug output, error
+// messages and the printed config.
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Mask takes the place of a hidden value.
+const Mask = "***"
+
+// minSecret is the length below which a value is too common to mask
+// wherever it appears, like a 1 or "on" in ExtraConfig.
+const minSecret = 4
+
+// secretNames are the parts of setting, form field and query parameter
+// names that mark a credential.
+var secretNames = []string{"token", "secret", "password", "passwd", "passphrase", "apikey", "api_key", "api-key", "access_key", "private_key", "authorization", "credential", "signature"}
+
+var (
+	userInfo   = regexp.MustCompile(`(://[^/@\s:]*):[^/@\s]*@`)
+	queryParam = regexp.MustCompile(`([?&;])([^=&#\s"'?]+)=([^&#\s"']*)`)
+	authValue  = regexp.MustCompile(`(?i)\b(Bearer|Basic)\s+[A-Za-z0-9._~+/=-]+`)
+)
+
+// SecretName reports whether a setting, form field or query parameter
+// called name holds a credential.
+func SecretName(name string) bool {
+	n := strings.ToLower(name)
+	if n == "key" || n == "sig" {
+		return true
+	}
+	for _, s := range secretNames {
+		if strings.Contains(n, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Text masks in s each of secrets, the passwords and secret query
+// parameters of URLs, and Bearer and Basic authorization values.
+func Text(s string, secrets ...string) string {
+	for _, v := range secrets {
+		if len(v) >= minSecret {
+			s = strings.ReplaceAll(s, v, Mask)
+		}
+	}
+	s = userInfo.ReplaceAllString(s, "$1:"+Mask+"@")
+	s = queryParam.ReplaceAllStringFunc(s, func(m string) string {
+		p := queryParam.FindStringSubmatch(m)
+		if !SecretName(p[2]) {
+			return m
+		}
+		return p[1] + p[2] + "=" + Mask
+	})
+	return authValue.ReplaceAllString(s, "$1 "+Mask)
+}
+
+// Error returns err with Text applied to its message, still wrapping err;
+// nil stays nil.
+func Error(err error, secrets ...string) error {
+	if err == nil {
+		return nil
+	}
+	msg := Text(err.Error(), secrets...)
+	if msg == err.Error() {
+		return err
+	}
+	return &redactedError{msg: msg, err: err}
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// Values returns the string values of m, at any depth, whose names mark
+// them as credentials, for masking with Text.
+func Values(m map[string]interface{}) []string {
+	var values []string
+	for k, v := range m {
+		switch val := v.(type) {
+		case string:
+			if SecretName(k) && val != "" {
+				values = append(values, val)
+			}
+		case map[string]interface{}:
+			values = append(values, Values(val)...)
+		}
+	}
+	return values
+}
+
+// JSON masks decoded JSON in place and returns it: string values named as
+// credentials are replaced by Mask, and all others pass through Text.
+func JSON(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if s, ok := item.(string); ok && s != "" && SecretName(k) {
+				val[k] = Mask
+				continue
+			}
+			val[k] = JSON(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = JSON(item)
+		}
+	case string:
+		return Text(val)
+	}
+	return v
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package schedule parses SCHEDULE, a list of cron-style entries that each
+// name a folder or file to transcribe, e.g. "0 7 * * 1-5 D:\Calls\{yesterday}".
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// horizon bounds the search for the next run, so entries such as
+// "0 0 30 2 *" that can never match fail instead of looping.
+const horizon = 5 * 366 * 24 * time.Hour
+
+// field is one cron time field as a set of allowed values.
+type field uint64
+
+func (f field) has(v int) bool { return f&(1<<uint(v)) != 0 }
+
+// Job is one SCHEDULE entry.
+type Job struct {
+	Spec string // the five time fields as written
+	Path string // folder or file, before placeholders are expanded
+
+	minute, hour, dom, month, dow field
+	anyDom, anyDow                bool
+}
+
+// bounds are the value ranges of the minute, hour, day-of-month, month and
+// day-of-week fields. Day of week 7 is another name for Sunday.
+var bounds = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// Parse parses a SCHEDULE: entries separated by ';' or line breaks, each
+// "minute hour day month weekday path". Time fields take *, numbers, a-b
+// ranges, comma lists and /step. An empty SCHEDULE has no jobs.
+func Parse(s string) ([]Job, error) {
+	var jobs []Job
+	for _, entry := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == '\n' || r == '\r' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		j, err := parseJob(entry)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, nil
+}
+
+func parseJob(entry string) (Job, error) {
+	fields := strings.Fields(entry)
+	if len(fields) < 6 {
+		return Job{}, fmt.Errorf("'%s' needs five time fields and a path", entry)
+	}
+	j := Job{Spec: strings.Join(fields[:5], " ")}
+	// The path may contain spaces, so take the rest of the entry as written.
+	rest := entry
+	for _, f := range fields[:5] {
+		rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), f))
+	}
+	j.Path = strings.Trim(rest, "\"")
+	sets := [5]*field{&j.minute, &j.hour, &j.dom, &j.month, &j.dow}
+	for i, f := range fields[:5] {
+		set, err := parseField(f, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return Job{}, fmt.Errorf("invalid time '%s' in '%s': %w", f, entry, err)
+		}
+		*sets[i] = set
+	}
+	if j.dow.has(7) {
+		j.dow |= 1
+	}
+	j.anyDom = fields[2] == "*"
+	j.anyDow = fields[4] == "*"
+	if j.Next(time.Now()).IsZero() {
+		return Job{}, fmt.Errorf("'%s' never runs", entry)
+	}
+	return j, nil
+}
+
+func parseField(s string, lo, hi int) (field, error) {
+	var set field
+	for _, part := range strings.Split(s, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("bad step '%s'", stepStr)
+			}
+			step = n
+		}
+		first, last := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if first, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("'%s' is not a number", a)
+			}
+			last = first
+			if isRange {
+				if last, err = strconv.Atoi(b); err != nil {
+					return 0, fmt.Errorf("'%s' is not a number", b)
+				}
+			} else if hasStep {
+				last = hi
+			}
+			if first < lo || last > hi || first > last {
+				return 0, fmt.Errorf("'%s' is outside %d-%d", rng, lo, hi)
+			}
+		}
+		for v := first; v <= last; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// Next returns the first minute after t at which the job is due, or the zero
+// time if there is none within a few years.
+func (j Job) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+	end := t.Add(horizon)
+	for t.Before(end) {
+		switch {
+		case !j.month.has(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !j.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !j.hour.has(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !j.minute.has(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches follows cron: when both the day of month and the day of week
+// are restricted, either one matching is enough.
+func (j Job) dayMatches(t time.Time) bool {
+	dom := j.dom.has(t.Day())
+	dow := j.dow.has(int(t.Weekday()))
+	switch {
+	case j.anyDom && j.anyDow:
+		return true
+	case j.anyDom:
+		return dow
+	case j.anyDow:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// PathAt expands the placeholders in the job's path for a run at t: {date} is
+// t as 2006-01-02, {yesterday} the day before, and {yyyy}, {mm} and {dd}
+// the parts of t.
+func (j Job) PathAt(t time.Time) string {
+	return strings.NewReplacer(
+		"{date}", t.Format("2006-01-02"),
+		"{yesterday}", t.AddDate(0, 0, -1).Format("2006-01-02"),
+		"{yyyy}", t.Format("2006"),
+		"{mm}", t.Format("01"),
+		"{dd}", t.Format("02"),
+	).Replace(j.Path)
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	jobs, err := Parse(`0 7 * * 1-5 D:\Calls\{yesterday}; */15 9-17 * * * "C:\My Recordings" ;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("Parse = %d jobs, want 2", len(jobs))
+	}
+	if jobs[0].Spec != "0 7 * * 1-5" || jobs[0].Path != `D:\Calls\{yesterday}` {
+		t.Fatalf("job 0 = %q %q", jobs[0].Spec, jobs[0].Path)
+	}
+	if jobs[1].Path != `C:\My Recordings` {
+		t.Fatalf("job 1 path = %q", jobs[1].Path)
+	}
+	if jobs, err := Parse(""); err != nil || len(jobs) != 0 {
+		t.Fatalf("Parse(\"\") = %v, %v", jobs, err)
+	}
+	for _, bad := range []string{"0 7 * * D:\\Calls", "60 7 * * * D:\\Calls", "0 7 * * mon D:\\Calls", "*/0 * * * * x", "0 0 30 2 * x", "5-1 * * * * x"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) = nil error", bad)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	loc := time.UTC
+	// 2026-01-05 is a Monday.
+	from := time.Date(2026, 1, 5, 7, 0, 30, 0, loc)
+	tests := []struct {
+		entry string
+		want  time.Time
+	}{
+		{"0 7 * * * x", time.Date(2026, 1, 6, 7, 0, 0, 0, loc)},
+		{"*/15 * * * * x", time.Date(2026, 1, 5, 7, 15, 0, 0, loc)},
+		{"30 6 * * 1-5 x", time.Date(2026, 1, 6, 6, 30, 0, 0, loc)},
+		{"0 9 * * 0 x", time.Date(2026, 1, 11, 9, 0, 0, 0, loc)},
+		{"0 9 * * 7 x", time.Date(2026, 1, 11, 9, 0, 0, 0, loc)},
+		{"0 0 1 3 * x", time.Date(2026, 3, 1, 0, 0, 0, 0, loc)},
+		// Day of month or day of week, as in cron.
+		{"0 8 10 * 3 x", time.Date(2026, 1, 7, 8, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		jobs, err := Parse(tt.entry)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.entry, err)
+		}
+		if got := jobs[0].Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q.Next = %v, want %v", tt.entry, got, tt.want)
+		}
+	}
+}
+
+func TestPathAt(t *testing.T) {
+	jobs, err := Parse(`0 7 * * * D:\Calls\{yyyy}\{mm}\{dd} {yesterday} {date}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := jobs[0].PathAt(time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC))
+	if want := `D:\Calls\2026\03\01 2026-02-28 2026-03-01`; got != want {
+		t.Fatalf("PathAt = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package secret encrypts config values at rest with Windows DPAPI. Encrypted
+// values are stored as "dpapi:<base64>" and can only be decrypted by the same
+// Windows user account that encrypted them.
+package secret
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Prefix marks an encrypted value.
+const Prefix = "dpapi:"
+
+// ErrUnsupported is returned on platforms without DPAPI.
+var ErrUnsupported = errors.New("config encryption requires Windows DPAPI")
+
+// entropy is mixed into DPAPI so other applications running as the same user
+// cannot decrypt the values without knowing it.
+var entropy = []byte("stt-config-v1")
+
+// IsEncrypted reports whether value carries the encrypted-value prefix.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Encrypt protects value and returns it in the prefixed form. Empty and
+// already encrypted values are returned unchanged.
+func Encrypt(value string) (string, error) {
+	if value == "" || IsEncrypted(value) {
+		return value, nil
+	}
+	out, err := protect([]byte(value))
+	if err != nil {
+		return "", err
+	}
+	return Prefix + base64.StdEncoding.EncodeToString(out), nil
+}
+
+// Decrypt reverses Encrypt. Values without the prefix are returned unchanged.
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, Prefix))
+	if err != nil {
+		return "", fmt.Errorf("decode encrypted value: %w", err)
+	}
+	out, err := unprotect(raw)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
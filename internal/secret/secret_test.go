@@ -0,0 +1,36 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package secret
+
+import "testing"
+
+func TestPlainValuesPassThrough(t *testing.T) {
+	for _, v := range []string{"", "sk-plain"} {
+		got, err := Decrypt(v)
+		if err != nil || got != v {
+			t.Fatalf("Decrypt(%q) = %q, %v; want unchanged", v, got, err)
+		}
+	}
+	if got, err := Encrypt(""); err != nil || got != "" {
+		t.Fatalf("Encrypt(\"\") = %q, %v; want empty", got, err)
+	}
+	if got, err := Encrypt(Prefix + "abc"); err != nil || got != Prefix+"abc" {
+		t.Fatalf("Encrypt(encrypted) = %q, %v; want unchanged", got, err)
+	}
+}
+
+func TestDecryptRejectsBadEncoding(t *testing.T) {
+	if _, err := Decrypt(Prefix + "!!not-base64!!"); err == nil {
+		t.Fatalf("Decrypt succeeded on invalid base64, want error")
+	}
+}
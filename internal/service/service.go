@@ -0,0 +1,26 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package service runs STT as a Windows service. The service owns the retry
+// queue and cache retention, which need no desktop, so recordings queued by
+// one login are transcribed while nobody is logged in. Hotkeys, recording and
+// the clipboard need the user's desktop, so the service starts a record-mode
+// agent in every logged-on session and restarts it when it crashes.
+package service
+
+// Name is the name the service is installed under.
+const Name = "STT"
+
+// DisplayName and Description are shown in the Services console.
+const (
+	DisplayName = "STT speech to text"
+	Description = "Transcribes queued STT recordings and starts the STT hotkey agent in every logged-on session."
+)
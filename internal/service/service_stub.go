@@ -0,0 +1,37 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build !windows
+
+package service
+
+import "fmt"
+
+// Install is not supported on non-Windows builds.
+func Install(exe string, args []string) error {
+	return fmt.Errorf("services not supported on this platform")
+}
+
+// Uninstall is not supported on non-Windows builds.
+func Uninstall() error {
+	return fmt.Errorf("services not supported on this platform")
+}
+
+// IsService reports false on non-Windows builds.
+func IsService() bool { return false }
+
+// Active reports false on non-Windows builds.
+func Active() bool { return false }
+
+// Run is not supported on non-Windows builds.
+func Run(engine func(stop <-chan struct{}) error, agent []string, dir string) error {
+	return fmt.Errorf("services not supported on this platform")
+}
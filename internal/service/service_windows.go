@@ -0,0 +1,372 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+
+	"stt/internal/watchdog"
+)
+
+// errStopped is returned by runAgent when the service stops first.
+var errStopped = errors.New("service stopping")
+
+// Install registers the service to start automatically with the given
+// arguments to exe and to be restarted when it fails, then starts it.
+// Installing needs an elevated prompt.
+func Install(exe string, args []string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	if s, err := m.OpenService(Name); err == nil {
+		s.Close()
+		return fmt.Errorf("service %s is already installed", Name)
+	}
+	s, err := m.CreateService(Name, exe, mgr.Config{
+		StartType:        mgr.StartAutomatic,
+		DelayedAutoStart: true,
+		DisplayName:      DisplayName,
+		Description:      Description,
+	}, args...)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	err = s.SetRecoveryActions([]mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: time.Minute},
+		{Type: mgr.ServiceRestart, Delay: time.Minute},
+		{Type: mgr.ServiceRestart, Delay: 10 * time.Minute},
+	}, uint32((24 * time.Hour).Seconds()))
+	if err != nil {
+		return err
+	}
+	return s.Start()
+}
+
+// Uninstall stops and removes the service. The agents keep running until
+// their users quit them or log off.
+func Uninstall() error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(Name)
+	if err != nil {
+		return fmt.Errorf("service %s is not installed: %w", Name, err)
+	}
+	defer s.Close()
+	if st, err := s.Query(); err == nil && st.State != svc.Stopped {
+		if _, err := s.Control(svc.Stop); err != nil {
+			return err
+		}
+		for deadline := time.Now().Add(30 * time.Second); time.Now().Before(deadline); time.Sleep(500 * time.Millisecond) {
+			if st, err := s.Query(); err != nil || st.State == svc.Stopped {
+				break
+			}
+		}
+	}
+	return s.Delete()
+}
+
+var isService = sync.OnceValue(func() bool {
+	ok, err := svc.IsWindowsService()
+	return err == nil && ok
+})
+
+// IsService reports whether the service control manager started this
+// process.
+func IsService() bool {
+	return isService()
+}
+
+// Active reports whether the service is running in another process, in which
+// case record-mode instances leave the retry queue to it.
+func Active() bool {
+	if isService() {
+		return false
+	}
+	// mgr.Connect asks for full access, which only administrators have.
+	m, err := windows.OpenSCManager(nil, nil, windows.SC_MANAGER_CONNECT)
+	if err != nil {
+		return false
+	}
+	defer windows.CloseServiceHandle(m)
+	s, err := windows.OpenService(m, windows.StringToUTF16Ptr(Name), windows.SERVICE_QUERY_STATUS)
+	if err != nil {
+		return false
+	}
+	defer windows.CloseServiceHandle(s)
+	var st windows.SERVICE_STATUS
+	if err := windows.QueryServiceStatus(s, &st); err != nil {
+		return false
+	}
+	return st.CurrentState == windows.SERVICE_RUNNING
+}
+
+// Run runs the service until the service control manager stops it. engine
+// runs until its stop channel is closed; agent, the command line of the
+// record-mode agent, is started in dir in every session a user is logged on
+// to.
+func Run(engine func(stop <-chan struct{}) error, agent []string, dir string) error {
+	sup, err := newSupervisor(agent, dir)
+	if err != nil {
+		return err
+	}
+	return svc.Run(Name, &handler{engine: engine, sup: sup})
+}
+
+type handler struct {
+	engine func(stop <-chan struct{}) error
+	sup    *supervisor
+}
+
+func (h *handler) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	const accepts = svc.AcceptStop | svc.AcceptShutdown | svc.AcceptSessionChange
+	status <- svc.Status{State: svc.StartPending}
+	stop := make(chan struct{})
+	engineDone := make(chan error, 1)
+	go func() { engineDone <- h.engine(stop) }()
+	h.sup.startAll()
+	status <- svc.Status{State: svc.Running, Accepts: accepts}
+
+	for {
+		select {
+		case err := <-engineDone:
+			status <- svc.Status{State: svc.StopPending}
+			h.sup.close()
+			if err != nil {
+				// A service-specific exit code counts as a failure, so the
+				// recovery actions restart the service.
+				fmt.Printf("[service] %v\n", err)
+				return true, 1
+			}
+			return false, 0
+		case c := <-requests:
+			switch c.Cmd {
+			case svc.Interrogate:
+				status <- c.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				close(stop)
+				h.sup.close()
+				if err := <-engineDone; err != nil {
+					fmt.Printf("[service] %v\n", err)
+				}
+				return false, 0
+			case svc.SessionChange:
+				if c.EventType == windows.WTS_SESSION_LOGON {
+					n := *(**windows.WTSSESSION_NOTIFICATION)(unsafe.Pointer(&c.EventData))
+					h.sup.start(n.SessionID)
+				}
+			}
+		}
+	}
+}
+
+// supervisor keeps one agent running in every session a user is logged on
+// to. Agents outlive the service, so a restarted service adopts the agents
+// already running instead of starting more.
+type supervisor struct {
+	cmdline string
+	exeName string
+	dir     string
+	stop    windows.Handle
+
+	mu       sync.Mutex
+	sessions map[uint32]bool
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+func newSupervisor(agent []string, dir string) (*supervisor, error) {
+	stop, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &supervisor{
+		cmdline:  windows.ComposeCommandLine(agent),
+		exeName:  filepath.Base(agent[0]),
+		dir:      dir,
+		stop:     stop,
+		sessions: map[uint32]bool{},
+	}, nil
+}
+
+// startAll starts agents in the sessions users are already logged on to,
+// for when the service starts after they logged on.
+func (s *supervisor) startAll() {
+	var infos *windows.WTS_SESSION_INFO
+	var n uint32
+	if err := windows.WTSEnumerateSessions(0, 0, 1, &infos, &n); err != nil {
+		fmt.Printf("[service] failed to list sessions: %v\n", err)
+		return
+	}
+	defer windows.WTSFreeMemory(uintptr(unsafe.Pointer(infos)))
+	for _, info := range unsafe.Slice(infos, n) {
+		if info.SessionID != 0 && (info.State == windows.WTSActive || info.State == windows.WTSDisconnected) {
+			s.start(info.SessionID)
+		}
+	}
+}
+
+// start supervises the agent of session unless that is already done.
+func (s *supervisor) start(session uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.sessions[session] {
+		return
+	}
+	s.sessions[session] = true
+	s.wg.Add(1)
+	go s.supervise(session)
+}
+
+// close stops supervising and waits until every supervisor has returned,
+// leaving the agents running.
+func (s *supervisor) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	windows.SetEvent(s.stop)
+	s.wg.Wait()
+	windows.CloseHandle(s.stop)
+}
+
+// supervise restarts the agent of session whenever it crashes. It gives up
+// when the agent quits normally, which is the user choosing Quit, or when it
+// cannot be started, which is the user having logged off.
+func (s *supervisor) supervise(session uint32) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.sessions, session)
+		s.mu.Unlock()
+	}()
+	crashes := 0
+	for {
+		started := time.Now()
+		code, err := s.runAgent(session)
+		switch {
+		case errors.Is(err, errStopped):
+			return
+		case err != nil:
+			fmt.Printf("[service] session %d: %v\n", session, err)
+			return
+		case code == 0:
+			fmt.Printf("[service] the agent in session %d quit\n", session)
+			return
+		}
+		if time.Since(started) >= watchdog.StableRun {
+			crashes = 0
+		}
+		crashes++
+		delay := watchdog.RestartDelay(crashes)
+		fmt.Printf("[service] the agent in session %d exited with code %d; restarting in %s\n", session, code, delay)
+		if ev, _ := windows.WaitForSingleObject(s.stop, uint32(delay/time.Millisecond)); ev == windows.WAIT_OBJECT_0 {
+			return
+		}
+	}
+}
+
+// runAgent starts the agent in session, or adopts one already running there,
+// and waits for it to exit. It returns errStopped, leaving the agent
+// running, when the service stops first.
+func (s *supervisor) runAgent(session uint32) (uint32, error) {
+	proc := s.findAgent(session)
+	if proc == 0 {
+		var err error
+		if proc, err = s.launch(session); err != nil {
+			return 0, err
+		}
+	}
+	defer windows.CloseHandle(proc)
+	ev, err := windows.WaitForMultipleObjects([]windows.Handle{proc, s.stop}, false, windows.INFINITE)
+	if err != nil {
+		return 0, err
+	}
+	if ev != windows.WAIT_OBJECT_0 {
+		return 0, errStopped
+	}
+	var code uint32
+	if err := windows.GetExitCodeProcess(proc, &code); err != nil {
+		return 0, err
+	}
+	return code, nil
+}
+
+// findAgent returns a handle to a process of the agent's executable running
+// in session, or 0.
+func (s *supervisor) findAgent(session uint32) windows.Handle {
+	snap, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return 0
+	}
+	defer windows.CloseHandle(snap)
+	e := windows.ProcessEntry32{Size: uint32(unsafe.Sizeof(windows.ProcessEntry32{}))}
+	for err = windows.Process32First(snap, &e); err == nil; err = windows.Process32Next(snap, &e) {
+		if e.ProcessID == uint32(os.Getpid()) || !strings.EqualFold(windows.UTF16ToString(e.ExeFile[:]), s.exeName) {
+			continue
+		}
+		var sid uint32
+		if windows.ProcessIdToSessionId(e.ProcessID, &sid) != nil || sid != session {
+			continue
+		}
+		if h, err := windows.OpenProcess(windows.SYNCHRONIZE|windows.PROCESS_QUERY_LIMITED_INFORMATION, false, e.ProcessID); err == nil {
+			fmt.Printf("[service] adopted agent %d in session %d\n", e.ProcessID, session)
+			return h
+		}
+	}
+	return 0
+}
+
+// launch starts the agent as the user logged on to session, on their
+// desktop, without a console window.
+func (s *supervisor) launch(session uint32) (windows.Handle, error) {
+	var token windows.Token
+	if err := windows.WTSQueryUserToken(session, &token); err != nil {
+		return 0, fmt.Errorf("no user logged on: %w", err)
+	}
+	defer token.Close()
+	var env *uint16
+	if err := windows.CreateEnvironmentBlock(&env, token, false); err != nil {
+		return 0, err
+	}
+	defer windows.DestroyEnvironmentBlock(env)
+
+	si := windows.StartupInfo{Desktop: windows.StringToUTF16Ptr(`winsta0\default`)}
+	si.Cb = uint32(unsafe.Sizeof(si))
+	var pi windows.ProcessInformation
+	err := windows.CreateProcessAsUser(token, nil, windows.StringToUTF16Ptr(s.cmdline), nil, nil, false,
+		windows.CREATE_UNICODE_ENVIRONMENT|windows.CREATE_NO_WINDOW, env, windows.StringToUTF16Ptr(s.dir), &si, &pi)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start the agent: %w", err)
+	}
+	windows.CloseHandle(pi.Thread)
+	fmt.Printf("[service] started agent %d in session %d\n", pi.ProcessId, session)
+	return pi.Process, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package session reports changes to the Windows session the program runs
+// in, such as the workstation being locked or the computer going to sleep.
+package session
+
+// Event is a change to the session.
+type Event int
+
+// The events Watch reports.
+const (
+	Lock Event = iota + 1
+	Unlock
+	Suspend
+	Resume
+)
+
+func (e Event) String() string {
+	switch e {
+	case Lock:
+		return "lock"
+	case Unlock:
+		return "unlock"
+	case Suspend:
+		return "suspend"
+	case Resume:
+		return "resume"
+	}
+	return "unknown"
+}
+
+// Reasons carried in the wParam of WM_WTSSESSION_CHANGE.
+const (
+	wtsSessionLock   = 0x7
+	wtsSessionUnlock = 0x8
+)
+
+// sessionEvent returns the Event for a WM_WTSSESSION_CHANGE reason, or false
+// for the changes Watch does not report.
+func sessionEvent(reason uintptr) (Event, bool) {
+	switch reason {
+	case wtsSessionLock:
+		return Lock, true
+	case wtsSessionUnlock:
+		return Unlock, true
+	}
+	return 0, false
+}
+
+// Power events carried in the wParam of WM_POWERBROADCAST. Windows sends
+// PBT_APMRESUMEAUTOMATIC on every resume, and PBT_APMRESUMESUSPEND as well
+// when a user woke the computer, so only the former is reported.
+const (
+	pbtAPMSuspend         = 0x4
+	pbtAPMResumeAutomatic = 0x12
+)
+
+// powerEvent returns the Event for a WM_POWERBROADCAST event, or false for
+// the events Watch does not report.
+func powerEvent(event uintptr) (Event, bool) {
+	switch event {
+	case pbtAPMSuspend:
+		return Suspend, true
+	case pbtAPMResumeAutomatic:
+		return Resume, true
+	}
+	return 0, false
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package session
+
+import "testing"
+
+func TestSessionEventReportsLockAndUnlock(t *testing.T) {
+	cases := []struct {
+		reason uintptr
+		want   Event
+		ok     bool
+	}{
+		{wtsSessionLock, Lock, true},
+		{wtsSessionUnlock, Unlock, true},
+		{0x1, 0, false}, // console connect
+		{0x5, 0, false}, // logon
+	}
+	for _, c := range cases {
+		got, ok := sessionEvent(c.reason)
+		if got != c.want || ok != c.ok {
+			t.Errorf("sessionEvent(%#x) = %v, %v; want %v, %v", c.reason, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestPowerEventReportsSuspendAndResumeOnce(t *testing.T) {
+	cases := []struct {
+		event uintptr
+		want  Event
+		ok    bool
+	}{
+		{pbtAPMSuspend, Suspend, true},
+		{pbtAPMResumeAutomatic, Resume, true},
+		{0x7, 0, false}, // PBT_APMRESUMESUSPEND follows PBT_APMRESUMEAUTOMATIC
+		{0xA, 0, false}, // PBT_APMPOWERSTATUSCHANGE
+	}
+	for _, c := range cases {
+		got, ok := powerEvent(c.event)
+		if got != c.want || ok != c.ok {
+			t.Errorf("powerEvent(%#x) = %v, %v; want %v, %v", c.event, got, ok, c.want, c.ok)
+		}
+	}
+}
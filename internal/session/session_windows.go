@@ -0,0 +1,242 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package session
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	wmDestroy            = 0x0002
+	wmClose              = 0x0010
+	wmPowerBroadcast     = 0x0218
+	wmWTSSessionChange   = 0x02B1
+	notifyForThisSession = 0
+	deviceNotifyWindow   = 0
+	// eventBuffer is how many events may wait for fn before newer ones are
+	// dropped.
+	eventBuffer = 16
+)
+
+var (
+	user32   = syscall.NewLazyDLL("user32.dll")
+	wtsapi32 = syscall.NewLazyDLL("wtsapi32.dll")
+
+	procRegisterClassExW                 = user32.NewProc("RegisterClassExW")
+	procCreateWindowExW                  = user32.NewProc("CreateWindowExW")
+	procDestroyWindow                    = user32.NewProc("DestroyWindow")
+	procDefWindowProcW                   = user32.NewProc("DefWindowProcW")
+	procGetMessageW                      = user32.NewProc("GetMessageW")
+	procDispatchMessageW                 = user32.NewProc("DispatchMessageW")
+	procPostMessageW                     = user32.NewProc("PostMessageW")
+	procPostQuitMessage                  = user32.NewProc("PostQuitMessage")
+	procRegisterSuspendResume            = user32.NewProc("RegisterSuspendResumeNotification")
+	procUnregisterSuspendResume          = user32.NewProc("UnregisterSuspendResumeNotification")
+	procWTSRegisterSessionNotification   = wtsapi32.NewProc("WTSRegisterSessionNotification")
+	procWTSUnRegisterSessionNotification = wtsapi32.NewProc("WTSUnRegisterSessionNotification")
+	procGetModuleHandleW                 = syscall.NewLazyDLL("kernel32.dll").NewProc("GetModuleHandleW")
+)
+
+type wndClassEx struct {
+	Size       uint32
+	Style      uint32
+	WndProc    uintptr
+	ClsExtra   int32
+	WndExtra   int32
+	Instance   uintptr
+	Icon       uintptr
+	Cursor     uintptr
+	Background uintptr
+	MenuName   *uint16
+	ClassName  *uint16
+	IconSm     uintptr
+}
+
+type msg struct {
+	Hwnd    uintptr
+	Message uint32
+	WParam  uintptr
+	LParam  uintptr
+	Time    uint32
+	PtX     int32
+	PtY     int32
+}
+
+const className = "STTSessionWindow"
+
+var (
+	registerOnce sync.Once
+	registerErr  error
+
+	// watchers maps each hidden window to the channel its events go to.
+	watchersMu sync.Mutex
+	watchers   = map[uintptr]*watcher{}
+)
+
+type watcher struct {
+	events chan Event
+	// power is the suspend and resume registration, or 0 where Windows
+	// has none.
+	power uintptr
+}
+
+// Watch calls fn for each Event in the current session until the returned
+// function is called. The events arrive on a hidden window, which is never
+// shown.
+func Watch(fn func(Event)) (func(), error) {
+	events := make(chan Event, eventBuffer)
+	done := make(chan struct{})
+	hwndCh := make(chan uintptr, 1)
+	resultCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer close(done)
+
+		hwnd, err := create()
+		if err != nil {
+			resultCh <- err
+			return
+		}
+		if r, _, err := procWTSRegisterSessionNotification.Call(hwnd, notifyForThisSession); r == 0 {
+			procDestroyWindow.Call(hwnd)
+			resultCh <- fmt.Errorf("WTSRegisterSessionNotification failed: %v", err)
+			return
+		}
+		// Broadcasts reach the window on computers with classic sleep; with
+		// modern standby the suspend and resume events need registering.
+		// RegisterSuspendResumeNotification is missing before Windows 8.
+		w := &watcher{events: events}
+		if procRegisterSuspendResume.Find() == nil {
+			w.power, _, _ = procRegisterSuspendResume.Call(hwnd, deviceNotifyWindow)
+		}
+		watchersMu.Lock()
+		watchers[hwnd] = w
+		watchersMu.Unlock()
+		hwndCh <- hwnd
+		resultCh <- nil
+
+		var m msg
+		for {
+			ret, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+			if int32(ret) <= 0 {
+				break
+			}
+			procDispatchMessageW.Call(uintptr(unsafe.Pointer(&m)))
+		}
+	}()
+
+	select {
+	case err := <-resultCh:
+		if err != nil {
+			return nil, err
+		}
+	case <-time.After(2 * time.Second):
+		return nil, fmt.Errorf("timeout creating session window")
+	}
+	hwnd := <-hwndCh
+
+	// fn runs on its own goroutine so a slow callback never holds up the
+	// window thread.
+	go func() {
+		for ev := range events {
+			fn(ev)
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			procPostMessageW.Call(hwnd, wmClose, 0, 0)
+			<-done
+			close(events)
+		})
+	}, nil
+}
+
+func create() (uintptr, error) {
+	instance, _, _ := procGetModuleHandleW.Call(0)
+	registerOnce.Do(func() {
+		wc := wndClassEx{
+			WndProc:   syscall.NewCallback(wndProc),
+			Instance:  instance,
+			ClassName: syscall.StringToUTF16Ptr(className),
+		}
+		wc.Size = uint32(unsafe.Sizeof(wc))
+		if r, _, err := procRegisterClassExW.Call(uintptr(unsafe.Pointer(&wc))); r == 0 {
+			registerErr = fmt.Errorf("RegisterClassExW failed: %v", err)
+		}
+	})
+	if registerErr != nil {
+		return 0, registerErr
+	}
+	// A top-level window rather than a message-only one, since only
+	// top-level windows receive broadcast messages.
+	hwnd, _, err := procCreateWindowExW.Call(0,
+		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(className))),
+		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr("STT"))),
+		0, 0, 0, 0, 0, 0, 0, instance, 0)
+	if hwnd == 0 {
+		return 0, fmt.Errorf("CreateWindowExW failed: %v", err)
+	}
+	return hwnd, nil
+}
+
+func wndProc(hwnd, message, wParam, lParam uintptr) uintptr {
+	watchersMu.Lock()
+	w, ok := watchers[hwnd]
+	watchersMu.Unlock()
+	if ok {
+		switch message {
+		case wmWTSSessionChange:
+			if ev, ok := sessionEvent(wParam); ok {
+				w.send(ev)
+			}
+			return 0
+		case wmPowerBroadcast:
+			if ev, ok := powerEvent(wParam); ok {
+				w.send(ev)
+			}
+			return 1
+		case wmClose:
+			procWTSUnRegisterSessionNotification.Call(hwnd)
+			if w.power != 0 {
+				procUnregisterSuspendResume.Call(w.power)
+			}
+			procDestroyWindow.Call(hwnd)
+			return 0
+		case wmDestroy:
+			watchersMu.Lock()
+			delete(watchers, hwnd)
+			watchersMu.Unlock()
+			procPostQuitMessage.Call(0)
+			return 0
+		}
+	}
+	r, _, _ := procDefWindowProcW.Call(hwnd, message, wParam, lParam)
+	return r
+}
+
+// send queues ev for fn, dropping it when fn has fallen behind.
+func (w *watcher) send(ev Event) {
+	select {
+	case w.events <- ev:
+	default:
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package shellmenu registers the Explorer context-menu entry that sends
+// audio and video files to STT.
+package shellmenu
+
+// Verb is the registry key of the entry under each file type's shell key.
+const Verb = "STT.Transcribe"
+
+// Exts are the file types the entry is added to.
+var Exts = []string{".wav", ".mp3", ".m4a", ".aac", ".flac", ".ogg", ".opus", ".wma", ".mp4", ".webm", ".mkv", ".mov", ".avi", ".wmv", ".m4v"}
+
+// Command returns a command line for the registry: exe with args, each
+// quoted, followed by "%1", which Windows replaces with the file or link.
+func Command(exe string, args ...string) string {
+	cmd := quote(exe)
+	for _, a := range args {
+		cmd += " " + quote(a)
+	}
+	return cmd + ` "%1"`
+}
+
+func quote(s string) string {
+	return `"` + s + `"`
+}
@@ -0,0 +1,23 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package shellmenu
+
+import "testing"
+
+func TestCommand(t *testing.T) {
+	got := Command(`C:\Program Files\STT\stt.exe`, "transcribe", "-config", `C:\Users\me\stt.json`)
+	want := `"C:\Program Files\STT\stt.exe" "transcribe" "-config" "C:\Users\me\stt.json" "%1"`
+	if got != want {
+		t.Fatalf("Command = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package shellmenu
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// keyPath is where the entry for ext lives. SystemFileAssociations applies
+// to the type whichever program opens it, and HKCU needs no elevation.
+func keyPath(ext string) string {
+	return `Software\Classes\SystemFileAssociations\` + ext + `\shell\` + Verb
+}
+
+// Install adds the entry titled title, running command with icon as its
+// icon, to every type in Exts, replacing an earlier one.
+func Install(title, command, icon string) error {
+	for _, ext := range Exts {
+		key, _, err := registry.CreateKey(registry.CURRENT_USER, keyPath(ext), registry.SET_VALUE)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ext, err)
+		}
+		err = key.SetStringValue("MUIVerb", title)
+		if err == nil {
+			err = key.SetStringValue("Icon", icon)
+		}
+		key.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", ext, err)
+		}
+		cmd, _, err := registry.CreateKey(registry.CURRENT_USER, keyPath(ext)+`\command`, registry.SET_VALUE)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ext, err)
+		}
+		err = cmd.SetStringValue("", command)
+		cmd.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", ext, err)
+		}
+	}
+	return nil
+}
+
+// Uninstall removes the entry from every type in Exts. Types without it are
+// skipped.
+func Uninstall() error {
+	for _, ext := range Exts {
+		for _, path := range []string{keyPath(ext) + `\command`, keyPath(ext)} {
+			err := registry.DeleteKey(registry.CURRENT_USER, path)
+			if err != nil && !errors.Is(err, registry.ErrNotExist) {
+				return fmt.Errorf("%s: %w", ext, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Installed returns the command of the installed entry, or "" when there is
+// none.
+func Installed() (string, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, keyPath(Exts[0])+`\command`, registry.QUERY_VALUE)
+	if errors.Is(err, registry.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer key.Close()
+	command, _, err := key.GetStringValue("")
+	return command, err
+}
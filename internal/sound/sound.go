@@ -0,0 +1,24 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package sound plays the audio cues configured with the SOUND_* settings.
+package sound
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// IsFile reports whether spec names a WAV file rather than a Windows sound
+// alias such as SystemAsterisk.
+func IsFile(spec string) bool {
+	return strings.EqualFold(filepath.Ext(spec), ".wav") || strings.ContainsAny(spec, `/\`)
+}
@@ -0,0 +1,30 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package sound
+
+import "testing"
+
+func TestIsFile(t *testing.T) {
+	tests := map[string]bool{
+		"SystemAsterisk":                false,
+		"SystemNotification":            false,
+		"ding.wav":                      true,
+		"C:\\Windows\\Media\\chord.WAV": true,
+		"sounds/start":                  true,
+	}
+	for spec, want := range tests {
+		if got := IsFile(spec); got != want {
+			t.Fatalf("IsFile(%q) = %v, want %v", spec, got, want)
+		}
+	}
+}
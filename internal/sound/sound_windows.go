@@ -0,0 +1,49 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package sound
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	sndAsync     = 0x0001
+	sndNoDefault = 0x0002
+	sndAlias     = 0x00010000
+	sndFilename  = 0x00020000
+)
+
+var procPlaySoundW = syscall.NewLazyDLL("winmm.dll").NewProc("PlaySoundW")
+
+// Play starts playing spec, a WAV file path or a Windows sound alias, and
+// returns without waiting for it to finish. An empty spec plays nothing.
+func Play(spec string) error {
+	if spec == "" {
+		return nil
+	}
+	flags := uintptr(sndAsync | sndNoDefault | sndAlias)
+	if IsFile(spec) {
+		flags = sndAsync | sndNoDefault | sndFilename
+	}
+	name, err := syscall.UTF16PtrFromString(spec)
+	if err != nil {
+		return err
+	}
+	if r, _, err := procPlaySoundW.Call(uintptr(unsafe.Pointer(name)), 0, flags); r == 0 {
+		return fmt.Errorf("PlaySound failed for '%s': %v", spec, err)
+	}
+	return nil
+}
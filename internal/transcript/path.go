@@ -0,0 +1,78 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var placeholder = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// PathFields are the values an output template can refer to for one file.
+type PathFields struct {
+	// Input is the path of the transcribed file.
+	Input string
+	// Format is the output format, e.g. "srt".
+	Format string
+	// Language is the language of the transcript, or "" when unknown.
+	Language string
+}
+
+var pathTokens = map[string]func(PathFields) string{
+	"dir":  func(f PathFields) string { return filepath.Dir(f.Input) },
+	"name": func(f PathFields) string { return strings.TrimSuffix(filepath.Base(f.Input), filepath.Ext(f.Input)) },
+	"ext":  func(f PathFields) string { return f.Format },
+	"lang": func(f PathFields) string {
+		if f.Language == "" {
+			return "auto"
+		}
+		return f.Language
+	},
+}
+
+// ValidateTemplate reports unknown placeholders in an output template. An
+// empty template is valid and means the default naming.
+func ValidateTemplate(template string) error {
+	for _, m := range placeholder.FindAllStringSubmatch(template, -1) {
+		if _, ok := pathTokens[m[1]]; !ok {
+			return fmt.Errorf("unknown placeholder %s (allowed: {dir}, {name}, {ext}, {lang})", m[0])
+		}
+	}
+	return nil
+}
+
+// Path expands an output template such as "{dir}/out/{name}.{lang}.txt" for
+// f. / separates folders on every system; a relative result is relative to
+// the current directory.
+func Path(template string, f PathFields) string {
+	return filepath.Clean(filepath.FromSlash(placeholder.ReplaceAllStringFunc(template, func(m string) string {
+		if fn, ok := pathTokens[m[1:len(m)-1]]; ok {
+			return fn(f)
+		}
+		return m
+	})))
+}
+
+// Language returns the language an OpenAI-style response reports, or "".
+func Language(response []byte) string {
+	var body struct {
+		Language string `json:"language"`
+	}
+	if json.Unmarshal(response, &body) != nil {
+		return ""
+	}
+	return body.Language
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+package transcript
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPathExpandsTemplate(t *testing.T) {
+	f := PathFields{Input: filepath.Join("rec", "Monday.m4a"), Format: "srt", Language: "en"}
+	want := filepath.Join("rec", "out", "Monday.en.srt")
+	if got := Path("{dir}/out/{name}.{lang}.{ext}", f); got != want {
+		t.Fatalf("Path = %q, want %q", got, want)
+	}
+	f.Language = ""
+	want = filepath.Join("transcripts", "Monday.auto.txt")
+	if got := Path("transcripts/{name}.{lang}.txt", f); got != want {
+		t.Fatalf("Path without language = %q, want %q", got, want)
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	if err := ValidateTemplate("{dir}/{name}.{lang}.{ext}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateTemplate("{dir}/{basename}.txt"); err == nil {
+		t.Fatal("ValidateTemplate accepted {basename}")
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	if got := Language([]byte(`{"text":"hi","language":"english"}`)); got != "english" {
+		t.Fatalf("Language = %q, want english", got)
+	}
+	if got := Language([]byte("hi")); got != "" {
+		t.Fatalf("Language of text = %q, want empty", got)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package transcript writes the result of a file transcription as plain
+// text, the service's JSON response, or SRT, WebVTT or TSV subtitles built
+// from the timed segments in the response.
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Formats are the output formats Write supports.
+var Formats = []string{"txt", "json", "srt", "vtt", "tsv"}
+
+// ErrNoSegments is returned for a subtitle format when the response has no
+// timed segments.
+var ErrNoSegments = errors.New("the response has no timed segments; ask the service for them, e.g. with `\"response_format\": \"verbose_json\"` in EXTRA_CONFIG")
+
+// Result is what the transcription of one file returned.
+type Result struct {
+	Text string
+	// Response is the service's response, or nil.
+	Response []byte
+	// Speed is the factor the audio was sped up by before the upload; the
+	// timestamps in Response are scaled back by it. 0 means 1.
+	Speed float64
+}
+
+// Segment is a timed part of a transcript.
+type Segment struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+type jsonSegment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// Response returns an OpenAI-style verbose response with text and segs, for
+// a transcript put together from several responses.
+func Response(text string, segs []Segment) []byte {
+	body := struct {
+		Text     string        `json:"text"`
+		Segments []jsonSegment `json:"segments"`
+	}{Text: text, Segments: []jsonSegment{}}
+	for _, s := range segs {
+		body.Segments = append(body.Segments, jsonSegment{s.Start.Seconds(), s.End.Seconds(), s.Text})
+	}
+	b, _ := json.Marshal(body)
+	return b
+}
+
+// Segments returns the "segments" of an OpenAI-style verbose response, with
+// start and end in seconds, or nil when it has none.
+func Segments(response []byte) []Segment {
+	var body struct {
+		Segments []struct {
+			Start *float64 `json:"start"`
+			End   *float64 `json:"end"`
+			Text  string   `json:"text"`
+		} `json:"segments"`
+	}
+	if json.Unmarshal(response, &body) != nil {
+		return nil
+	}
+	var segs []Segment
+	for _, s := range body.Segments {
+		if s.Start == nil || s.End == nil {
+			return nil
+		}
+		segs = append(segs, Segment{
+			Start: seconds(*s.Start),
+			End:   seconds(*s.End),
+			Text:  strings.TrimSpace(s.Text),
+		})
+	}
+	return segs
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond)
+}
+
+// Write writes r to w in format, one of Formats; "" is "txt".
+func Write(w io.Writer, format string, r Result) error {
+	switch format {
+	case "", "txt":
+		_, err := io.WriteString(w, r.Text)
+		return err
+	case "json":
+		body := r.Response
+		if !json.Valid(body) {
+			body, _ = json.Marshal(struct {
+				Text string `json:"text"`
+			}{r.Text})
+		}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, body, "", "  "); err != nil {
+			return err
+		}
+		indented.WriteByte('\n')
+		_, err := indented.WriteTo(w)
+		return err
+	}
+
+	segs := Segments(r.Response)
+	if len(segs) == 0 {
+		return ErrNoSegments
+	}
+	if r.Speed > 0 && r.Speed != 1 {
+		for i := range segs {
+			segs[i].Start = time.Duration(float64(segs[i].Start) * r.Speed).Round(time.Millisecond)
+			segs[i].End = time.Duration(float64(segs[i].End) * r.Speed).Round(time.Millisecond)
+		}
+	}
+	var b strings.Builder
+	switch format {
+	case "srt":
+		for i, s := range segs {
+			fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, timestamp(s.Start, ","), timestamp(s.End, ","), s.Text)
+		}
+	case "vtt":
+		b.WriteString("WEBVTT\n\n")
+		for _, s := range segs {
+			fmt.Fprintf(&b, "%s --> %s\n%s\n\n", timestamp(s.Start, "."), timestamp(s.End, "."), s.Text)
+		}
+	case "tsv":
+		b.WriteString("start\tend\ttext\n")
+		for _, s := range segs {
+			fmt.Fprintf(&b, "%d\t%d\t%s\n", s.Start.Milliseconds(), s.End.Milliseconds(), strings.ReplaceAll(s.Text, "\t", " "))
+		}
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// timestamp formats d as hh:mm:ss followed by sep and milliseconds.
+func timestamp(d time.Duration, sep string) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+package transcript
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const verbose = `{"text":"Hello there. General Kenobi.","segments":[{"id":0,"start":0,"end":1.5,"text":" Hello there."},{"id":1,"start":1.5,"end":3.25,"text":" General Kenobi."}]}`
+
+func TestWriteSubtitles(t *testing.T) {
+	tests := []struct {
+		format string
+		speed  float64
+		want   string
+	}{
+		{"srt", 0, "1\n00:00:00,000 --> 00:00:01,500\nHello there.\n\n2\n00:00:01,500 --> 00:00:03,250\nGeneral Kenobi.\n\n"},
+		{"vtt", 1, "WEBVTT\n\n00:00:00.000 --> 00:00:01.500\nHello there.\n\n00:00:01.500 --> 00:00:03.250\nGeneral Kenobi.\n\n"},
+		// Timestamps of audio sped up 2x are twice as far into the original.
+		{"tsv", 2, "start\tend\ttext\n0\t3000\tHello there.\n3000\t6500\tGeneral Kenobi.\n"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := Write(&b, tt.format, Result{Text: "ignored", Response: []byte(verbose), Speed: tt.speed}); err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		if b.String() != tt.want {
+			t.Fatalf("%s = %q, want %q", tt.format, b.String(), tt.want)
+		}
+	}
+}
+
+func TestWriteTextAndJSON(t *testing.T) {
+	var b strings.Builder
+	if err := Write(&b, "txt", Result{Text: "hello"}); err != nil || b.String() != "hello" {
+		t.Fatalf("txt = %q, %v", b.String(), err)
+	}
+	b.Reset()
+	if err := Write(&b, "json", Result{Text: "hello", Response: []byte(`{"text":"hello","language":"en"}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"text\": \"hello\",\n  \"language\": \"en\"\n}\n"; b.String() != want {
+		t.Fatalf("json = %q, want %q", b.String(), want)
+	}
+	// Without a JSON response, e.g. from a text response_format.
+	b.Reset()
+	if err := Write(&b, "json", Result{Text: "hello", Response: []byte("hello")}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"text\": \"hello\"\n}\n"; b.String() != want {
+		t.Fatalf("json fallback = %q, want %q", b.String(), want)
+	}
+}
+
+func TestWriteSubtitlesWithoutSegments(t *testing.T) {
+	var b strings.Builder
+	err := Write(&b, "srt", Result{Text: "hello", Response: []byte(`{"text":"hello"}`)})
+	if !errors.Is(err, ErrNoSegments) {
+		t.Fatalf("err = %v, want ErrNoSegments", err)
+	}
+}
+
+func TestResponseRoundTrips(t *testing.T) {
+	segs := Segments([]byte(verbose))
+	if got := Segments(Response("Hello there. General Kenobi.", segs)); len(got) != 2 || got[1] != segs[1] {
+		t.Fatalf("Segments(Response) = %v, want %v", got, segs)
+	}
+}
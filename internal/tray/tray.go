@@ -0,0 +1,187 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package tray shows the record-mode status icon and control menu in the
+// Windows notification area.
+package tray
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// Status selects the icon shown in the tray.
+type Status int
+
+const (
+	StatusIdle Status = iota
+	StatusRecording
+	StatusPaused
+	StatusUploading
+	StatusError
+)
+
+// MenuItem is one entry of the tray menu. An item without a label is a
+// separator. An item with a Command can also be run from another process
+// with Send.
+type MenuItem struct {
+	Label   string
+	Command string
+	OnClick func()
+}
+
+// Handler answers a request another process made with Request, returning
+// the reply or an error to pass back. It runs on the tray's window thread, so
+// it must return quickly.
+type Handler func(request string) (string, error)
+
+// ReplyError is the error the Handler of the other instance returned for a
+// Request, as opposed to failing to reach it.
+type ReplyError string
+
+func (e ReplyError) Error() string { return string(e) }
+
+// Theme selects the icon palette.
+type Theme string
+
+const (
+	// ThemeAuto follows the Windows taskbar theme.
+	ThemeAuto  Theme = "auto"
+	ThemeLight Theme = "light"
+	ThemeDark  Theme = "dark"
+)
+
+// ValidTheme reports whether value is a supported TRAY_THEME.
+func ValidTheme(value string) bool {
+	switch Theme(value) {
+	case ThemeAuto, ThemeLight, ThemeDark:
+		return true
+	}
+	return false
+}
+
+// statusColors are the RGB colors of the status dot on a dark or light
+// taskbar. The light palette is darker so idle and paused keep their
+// contrast.
+var statusColors = map[Theme]map[Status]uint32{
+	ThemeDark: {
+		StatusIdle:      0x9e9e9e,
+		StatusRecording: 0xe53935,
+		StatusPaused:    0xfdd835,
+		StatusUploading: 0x1e88e5,
+		StatusError:     0xfb8c00,
+	},
+	ThemeLight: {
+		StatusIdle:      0x616161,
+		StatusRecording: 0xc62828,
+		StatusPaused:    0xf9a825,
+		StatusUploading: 0x1565c0,
+		StatusError:     0xe65100,
+	},
+}
+
+// palette resolves ThemeAuto to the current taskbar theme.
+func palette(theme Theme) Theme {
+	if theme == ThemeLight || theme == ThemeDark {
+		return theme
+	}
+	return SystemTheme()
+}
+
+// iconSize is the edge of the generated icons in pixels.
+const iconSize = 16
+
+// frameCount is the number of frames of the animated icons.
+const frameCount = 8
+
+// frameInterval is how long each frame of an animated icon is shown.
+const frameInterval = 125 * time.Millisecond
+
+// statusFrames returns the .ico files shown for s in the light or dark theme.
+// Recording pulses and uploading spins, so the state shows at a glance even
+// with notifications off; the other states have a single frame.
+func statusFrames(s Status, theme Theme) [][]byte {
+	rgb := statusColors[theme][s]
+	c := float64(iconSize-1) / 2
+	switch s {
+	case StatusRecording:
+		frames := make([][]byte, frameCount)
+		for i := range frames {
+			// The dot shrinks to 70% and grows back once per cycle.
+			scale := 0.85 + 0.15*math.Cos(2*math.Pi*float64(i)/frameCount)
+			frames[i] = drawIcon(rgb, func(d, _ float64) float64 {
+				return (c+0.5)*scale - d
+			})
+		}
+		return frames
+	case StatusUploading:
+		frames := make([][]byte, frameCount)
+		for i := range frames {
+			// A ring whose bright quarter turns clockwise.
+			head := 2 * math.Pi * float64(i) / frameCount
+			frames[i] = drawIcon(rgb, func(d, angle float64) float64 {
+				edge := math.Min(c+0.5-d, d-(c-4.5))
+				if math.Mod(angle-head+4*math.Pi, 2*math.Pi) > math.Pi/2 {
+					edge = math.Min(edge, 0.35)
+				}
+				return edge
+			})
+		}
+		return frames
+	default:
+		return [][]byte{statusIcon(s, theme)}
+	}
+}
+
+// statusIcon returns a .ico file with a filled dot in the color of s in the
+// light or dark theme, so the tray needs no icon resources.
+func statusIcon(s Status, theme Theme) []byte {
+	c := float64(iconSize-1) / 2
+	return drawIcon(statusColors[theme][s], func(d, _ float64) float64 { return c + 0.5 - d })
+}
+
+// drawIcon returns a .ico file in the color rgb whose alpha at each pixel is
+// shape of the distance from the center and the clockwise angle from the
+// top, clamped to [0, 1]; a shape that falls off by one per pixel gives an
+// antialiased edge.
+func drawIcon(rgb uint32, shape func(d, angle float64) float64) []byte {
+	const header = 6 + 16
+	const bmpHeader = 40
+	const pixels = iconSize * iconSize * 4
+	const mask = iconSize * 4 // 1bpp rows padded to 32 bits
+	var b bytes.Buffer
+	le := func(v any) { _ = binary.Write(&b, binary.LittleEndian, v) }
+
+	// ICONDIR and its single ICONDIRENTRY.
+	le([]uint16{0, 1, 1})
+	le([]uint8{iconSize, iconSize, 0, 0})
+	le([]uint16{1, 32})
+	le([]uint32{bmpHeader + pixels + mask, header})
+
+	// BITMAPINFOHEADER; the height covers the color and mask bitmaps.
+	le([]uint32{bmpHeader, iconSize, iconSize * 2})
+	le([]uint16{1, 32})
+	le([]uint32{0, pixels + mask, 0, 0, 0, 0})
+
+	// BGRA pixels, bottom row first.
+	c := float64(iconSize-1) / 2
+	for y := iconSize - 1; y >= 0; y-- {
+		for x := 0; x < iconSize; x++ {
+			dx, dy := float64(x)-c, float64(y)-c
+			alpha := math.Max(0, math.Min(1, shape(math.Hypot(dx, dy), math.Atan2(dx, -dy))))
+			b.Write([]byte{byte(rgb), byte(rgb >> 8), byte(rgb >> 16), byte(alpha * 255)})
+		}
+	}
+	b.Write(make([]byte, mask))
+	return b.Bytes()
+}
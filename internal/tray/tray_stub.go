@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build !windows
+
+package tray
+
+import "fmt"
+
+// Tray is a notification-area icon.
+type Tray struct{}
+
+// Start is not supported on non-Windows builds.
+func Start(tooltip string, theme Theme, items []MenuItem) (*Tray, error) {
+	return nil, fmt.Errorf("tray icon not supported on this platform")
+}
+
+// Set changes the icon and tooltip.
+func (t *Tray) Set(s Status, tooltip string) {}
+
+// SetTooltip changes the tooltip.
+func (t *Tray) SetTooltip(tooltip string) {}
+
+// Handle sets the handler for requests.
+func (t *Tray) Handle(h Handler) {}
+
+// Close removes the icon.
+func (t *Tray) Close() {}
+
+// Send is not supported on non-Windows builds.
+func Send(command string) error {
+	return fmt.Errorf("tray icon not supported on this platform")
+}
+
+// Query is not supported on non-Windows builds.
+func Query() (string, error) {
+	return "", fmt.Errorf("tray icon not supported on this platform")
+}
+
+// Request is not supported on non-Windows builds.
+func Request(request string) (string, error) {
+	return "", fmt.Errorf("tray icon not supported on this platform")
+}
+
+// SystemTheme reports the dark theme on non-Windows builds.
+func SystemTheme() Theme {
+	return ThemeDark
+}
+
+// OpenFolder is not supported on non-Windows builds.
+func OpenFolder(path string) error {
+	return fmt.Errorf("opening folders not supported on this platform")
+}
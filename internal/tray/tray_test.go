@@ -0,0 +1,83 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package tray
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestStatusIconIsSingleImageICO(t *testing.T) {
+	ico := statusIcon(StatusRecording, ThemeDark)
+	if binary.LittleEndian.Uint16(ico[2:]) != 1 || binary.LittleEndian.Uint16(ico[4:]) != 1 {
+		t.Fatalf("header = % x, want one icon image", ico[:6])
+	}
+	size := binary.LittleEndian.Uint32(ico[14:])
+	offset := binary.LittleEndian.Uint32(ico[18:])
+	if int(offset+size) != len(ico) {
+		t.Fatalf("entry covers %d+%d bytes of %d", offset, size, len(ico))
+	}
+
+	// The center pixel is opaque red, the corner transparent.
+	px := func(x, y int) []byte {
+		i := int(offset) + 40 + ((iconSize-1-y)*iconSize+x)*4
+		return ico[i : i+4]
+	}
+	if got := px(8, 8); got[2] != 0xe5 || got[3] != 0xff {
+		t.Fatalf("center pixel = % x", got)
+	}
+	if got := px(0, 0); got[3] != 0 {
+		t.Fatalf("corner pixel = % x, want transparent", got)
+	}
+}
+
+func TestAnimatedStatusesHaveDistinctFrames(t *testing.T) {
+	for _, s := range []Status{StatusRecording, StatusUploading} {
+		frames := statusFrames(s, ThemeDark)
+		if len(frames) != frameCount {
+			t.Fatalf("status %d has %d frames, want %d", s, len(frames), frameCount)
+		}
+		if bytes.Equal(frames[0], frames[1]) {
+			t.Fatalf("status %d frames 0 and 1 are identical", s)
+		}
+	}
+	if got := len(statusFrames(StatusIdle, ThemeDark)); got != 1 {
+		t.Fatalf("idle has %d frames, want 1", got)
+	}
+	if !bytes.Equal(statusFrames(StatusRecording, ThemeDark)[0], statusIcon(StatusRecording, ThemeDark)) {
+		t.Fatalf("first recording frame differs from the static icon")
+	}
+}
+
+func TestThemesCoverEveryStatus(t *testing.T) {
+	for _, theme := range []Theme{ThemeDark, ThemeLight} {
+		if len(statusColors[theme]) != len(statusColors[ThemeDark]) {
+			t.Fatalf("theme %s has %d colors, want %d", theme, len(statusColors[theme]), len(statusColors[ThemeDark]))
+		}
+	}
+	if palette(ThemeLight) != ThemeLight || palette(ThemeDark) != ThemeDark {
+		t.Fatalf("explicit themes are not kept")
+	}
+	if p := palette(ThemeAuto); p != ThemeLight && p != ThemeDark {
+		t.Fatalf("palette(auto) = %q", p)
+	}
+	for _, v := range []string{"auto", "light", "dark"} {
+		if !ValidTheme(v) {
+			t.Fatalf("ValidTheme(%q) = false", v)
+		}
+	}
+	if ValidTheme("") || ValidTheme("blue") {
+		t.Fatalf("ValidTheme accepted an unknown theme")
+	}
+}
@@ -0,0 +1,661 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package tray
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+const (
+	wmNull          = 0x0000
+	wmGetText       = 0x000D
+	wmQuit          = 0x0012
+	wmTimer         = 0x0113
+	wmSettingChange = 0x001A
+	wmCopyData      = 0x004A
+	wmLButtonUp     = 0x0202
+	wmRButtonUp     = 0x0205
+	wmApp           = 0x8000
+	wmTrayIcon      = wmApp + 1
+	wmAnimate       = wmApp + 2
+
+	animationTimer = 1
+
+	nimAdd    = 0x0
+	nimModify = 0x1
+	nimDelete = 0x2
+
+	nifMessage = 0x1
+	nifIcon    = 0x2
+	nifTip     = 0x4
+
+	mfString    = 0x0
+	mfSeparator = 0x800
+
+	tpmRightButton = 0x2
+	tpmNoNotify    = 0x80
+	tpmReturnCmd   = 0x100
+)
+
+var (
+	user32   = syscall.NewLazyDLL("user32.dll")
+	shell32  = syscall.NewLazyDLL("shell32.dll")
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	procRegisterClassExW         = user32.NewProc("RegisterClassExW")
+	procCreateWindowExW          = user32.NewProc("CreateWindowExW")
+	procDestroyWindow            = user32.NewProc("DestroyWindow")
+	procDefWindowProcW           = user32.NewProc("DefWindowProcW")
+	procGetMessageW              = user32.NewProc("GetMessageW")
+	procTranslateMessage         = user32.NewProc("TranslateMessage")
+	procDispatchMessageW         = user32.NewProc("DispatchMessageW")
+	procPostMessageW             = user32.NewProc("PostMessageW")
+	procSendMessageW             = user32.NewProc("SendMessageW")
+	procSetWindowTextW           = user32.NewProc("SetWindowTextW")
+	procFindWindowW              = user32.NewProc("FindWindowW")
+	procPostThreadMessageW       = user32.NewProc("PostThreadMessageW")
+	procRegisterWindowMessageW   = user32.NewProc("RegisterWindowMessageW")
+	procCreatePopupMenu          = user32.NewProc("CreatePopupMenu")
+	procAppendMenuW              = user32.NewProc("AppendMenuW")
+	procTrackPopupMenu           = user32.NewProc("TrackPopupMenu")
+	procDestroyMenu              = user32.NewProc("DestroyMenu")
+	procGetCursorPos             = user32.NewProc("GetCursorPos")
+	procSetForegroundWindow      = user32.NewProc("SetForegroundWindow")
+	procCreateIconFromResourceEx = user32.NewProc("CreateIconFromResourceEx")
+	procDestroyIcon              = user32.NewProc("DestroyIcon")
+	procSetTimer                 = user32.NewProc("SetTimer")
+	procKillTimer                = user32.NewProc("KillTimer")
+	procShellNotifyIconW         = shell32.NewProc("Shell_NotifyIconW")
+	procGetModuleHandleW         = kernel32.NewProc("GetModuleHandleW")
+	procGetCurrentThreadId       = kernel32.NewProc("GetCurrentThreadId")
+)
+
+type wndClassEx struct {
+	Size       uint32
+	Style      uint32
+	WndProc    uintptr
+	ClsExtra   int32
+	WndExtra   int32
+	Instance   uintptr
+	Icon       uintptr
+	Cursor     uintptr
+	Background uintptr
+	MenuName   *uint16
+	ClassName  *uint16
+	IconSm     uintptr
+}
+
+type notifyIconData struct {
+	Size            uint32
+	Wnd             uintptr
+	ID              uint32
+	Flags           uint32
+	CallbackMessage uint32
+	Icon            uintptr
+	Tip             [128]uint16
+	State           uint32
+	StateMask       uint32
+	Info            [256]uint16
+	Version         uint32
+	InfoTitle       [64]uint16
+	InfoFlags       uint32
+	GUIDItem        [16]byte
+	BalloonIcon     uintptr
+}
+
+type msg struct {
+	Hwnd    uintptr
+	Message uint32
+	WParam  uintptr
+	LParam  uintptr
+	Time    uint32
+	PtX     int32
+	PtY     int32
+}
+
+const className = "STTTrayWindow"
+
+// commandMagic tags the WM_COPYDATA messages Send posts to the tray window.
+const commandMagic = 0x53545443 // "STTC"
+
+// Request sends a WM_COPYDATA tagged requestMagic with the handle of a reply
+// window, which the tray answers with one tagged replyMagic or failMagic.
+const (
+	requestMagic = 0x53545452 // "STTR"
+	replyMagic   = 0x53545441 // "STTA"
+	failMagic    = 0x53545445 // "STTE"
+)
+
+const replyClassName = "STTTrayReply"
+
+// hwndMessage is HWND_MESSAGE, the parent of message-only windows.
+const hwndMessage = ^uintptr(2)
+
+// copyDataStruct is COPYDATASTRUCT.
+type copyDataStruct struct {
+	Data  uintptr
+	Size  uint32
+	Bytes *byte
+}
+
+var (
+	registerOnce sync.Once
+	registerErr  error
+
+	// active is the tray the window procedure dispatches to. Only one tray
+	// exists at a time.
+	activeMu sync.Mutex
+	active   *Tray
+)
+
+// Tray is a notification-area icon with a context menu.
+type Tray struct {
+	items    []MenuItem
+	icons    map[Status][]uintptr
+	hwnd     uintptr
+	threadID uintptr
+	done     chan struct{}
+	once     sync.Once
+
+	// taskbarCreated is broadcast when Explorer restarts; the icon has to be
+	// added again.
+	taskbarCreated uintptr
+
+	// theme is the configured TRAY_THEME; the window thread rebuilds the
+	// icons when it is auto and the taskbar theme changes.
+	theme   Theme
+	palette Theme
+
+	mu      sync.Mutex
+	status  Status
+	frame   int
+	tooltip string
+	handler Handler
+}
+
+// Start adds the icon to the notification area with the idle icon and the
+// given tooltip, colored for theme. Left or right clicking it opens a menu of
+// items; each OnClick runs on its own goroutine so the menu never blocks on
+// the caller.
+func Start(tooltip string, theme Theme, items []MenuItem) (*Tray, error) {
+	t := &Tray{
+		items:   items,
+		theme:   theme,
+		done:    make(chan struct{}),
+		status:  StatusIdle,
+		tooltip: tooltip,
+	}
+	resultCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer close(t.done)
+
+		if err := t.create(); err != nil {
+			t.destroy()
+			resultCh <- err
+			return
+		}
+		t.threadID, _, _ = procGetCurrentThreadId.Call()
+		activeMu.Lock()
+		active = t
+		activeMu.Unlock()
+		resultCh <- nil
+
+		var m msg
+		for {
+			ret, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+			if int32(ret) <= 0 {
+				break
+			}
+			procTranslateMessage.Call(uintptr(unsafe.Pointer(&m)))
+			procDispatchMessageW.Call(uintptr(unsafe.Pointer(&m)))
+		}
+		activeMu.Lock()
+		active = nil
+		activeMu.Unlock()
+		t.destroy()
+	}()
+
+	select {
+	case err := <-resultCh:
+		if err != nil {
+			return nil, err
+		}
+		return t, nil
+	case <-time.After(2 * time.Second):
+		return nil, fmt.Errorf("timeout creating tray icon")
+	}
+}
+
+// create builds the hidden window, the status icons and the tray icon on
+// the calling thread.
+func (t *Tray) create() error {
+	instance, _, _ := procGetModuleHandleW.Call(0)
+	registerOnce.Do(func() {
+		wc := wndClassEx{
+			WndProc:   syscall.NewCallback(wndProc),
+			Instance:  instance,
+			ClassName: syscall.StringToUTF16Ptr(className),
+		}
+		wc.Size = uint32(unsafe.Sizeof(wc))
+		if r, _, err := procRegisterClassExW.Call(uintptr(unsafe.Pointer(&wc))); r == 0 {
+			registerErr = fmt.Errorf("RegisterClassExW failed: %v", err)
+		}
+	})
+	if registerErr != nil {
+		return registerErr
+	}
+	// A hidden top-level window rather than a message-only one, which would
+	// miss the TaskbarCreated and WM_SETTINGCHANGE broadcasts.
+	// The window text mirrors the tooltip for Query.
+	hwnd, _, err := procCreateWindowExW.Call(0, uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(className))),
+		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(t.tooltip))), 0, 0, 0, 0, 0, 0, 0, instance, 0)
+	if hwnd == 0 {
+		return fmt.Errorf("CreateWindowExW failed: %v", err)
+	}
+	t.hwnd = hwnd
+	t.taskbarCreated, _, _ = procRegisterWindowMessageW.Call(uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr("TaskbarCreated"))))
+
+	if err := t.loadIcons(palette(t.theme)); err != nil {
+		return err
+	}
+	if !t.notify(nimAdd) {
+		return fmt.Errorf("Shell_NotifyIconW failed to add the icon")
+	}
+	return nil
+}
+
+// loadIcons creates the icons of every status in the light or dark palette
+// and swaps them in, destroying the previous set.
+func (t *Tray) loadIcons(theme Theme) error {
+	icons := map[Status][]uintptr{}
+	for s := range statusColors[theme] {
+		for _, ico := range statusFrames(s, theme) {
+			bits := ico[6+16:]
+			h, _, err := procCreateIconFromResourceEx.Call(uintptr(unsafe.Pointer(&bits[0])), uintptr(len(bits)),
+				1, 0x00030000, iconSize, iconSize, 0)
+			if h == 0 {
+				destroyIcons(icons)
+				return fmt.Errorf("CreateIconFromResourceEx failed: %v", err)
+			}
+			icons[s] = append(icons[s], h)
+		}
+	}
+	t.mu.Lock()
+	old := t.icons
+	t.icons, t.palette = icons, theme
+	t.mu.Unlock()
+	if old != nil {
+		t.notify(nimModify)
+		destroyIcons(old)
+	}
+	return nil
+}
+
+// themeChanged follows a taskbar theme switch when the theme is auto.
+func (t *Tray) themeChanged() {
+	if t.theme != ThemeAuto {
+		return
+	}
+	if p := SystemTheme(); p != t.palette {
+		if err := t.loadIcons(p); err != nil {
+			fmt.Printf("[tray] %v\n", err)
+		}
+	}
+}
+
+func destroyIcons(icons map[Status][]uintptr) {
+	for _, frames := range icons {
+		for _, h := range frames {
+			procDestroyIcon.Call(h)
+		}
+	}
+}
+
+func (t *Tray) destroy() {
+	if t.hwnd != 0 {
+		nid := notifyIconData{Wnd: t.hwnd, ID: 1}
+		nid.Size = uint32(unsafe.Sizeof(nid))
+		procShellNotifyIconW.Call(nimDelete, uintptr(unsafe.Pointer(&nid)))
+		procDestroyWindow.Call(t.hwnd)
+	}
+	destroyIcons(t.icons)
+}
+
+// notify adds or updates the icon with the current status and tooltip.
+func (t *Tray) notify(op uintptr) bool {
+	t.mu.Lock()
+	nid := notifyIconData{
+		Wnd:             t.hwnd,
+		ID:              1,
+		Flags:           nifMessage | nifIcon | nifTip,
+		CallbackMessage: wmTrayIcon,
+		Icon:            t.icons[t.status][t.frame%len(t.icons[t.status])],
+	}
+	tip, _ := syscall.UTF16FromString(t.tooltip)
+	copy(nid.Tip[:len(nid.Tip)-1], tip)
+	t.mu.Unlock()
+	nid.Size = uint32(unsafe.Sizeof(nid))
+	r, _, _ := procShellNotifyIconW.Call(op, uintptr(unsafe.Pointer(&nid)))
+	return r != 0
+}
+
+// Set changes the icon and tooltip. Animated icons start from their first
+// frame.
+func (t *Tray) Set(s Status, tooltip string) {
+	t.mu.Lock()
+	if s != t.status {
+		t.frame = 0
+	}
+	t.status, t.tooltip = s, tooltip
+	t.mu.Unlock()
+	t.notify(nimModify)
+	t.setWindowText(tooltip)
+	// Timers belong to the window's thread, so it starts or stops its own.
+	procPostMessageW.Call(t.hwnd, wmAnimate, 0, 0)
+}
+
+// SetTooltip changes the tooltip only, leaving the icon animation alone.
+func (t *Tray) SetTooltip(tooltip string) {
+	t.mu.Lock()
+	t.tooltip = tooltip
+	t.mu.Unlock()
+	t.notify(nimModify)
+	t.setWindowText(tooltip)
+}
+
+// Handle sets the handler for requests other processes make with Request.
+func (t *Tray) Handle(h Handler) {
+	t.mu.Lock()
+	t.handler = h
+	t.mu.Unlock()
+}
+
+// answer passes a request to the handler and sends the reply to the window
+// reply, reporting whether there was a handler.
+func (t *Tray) answer(reply uintptr, request string) bool {
+	t.mu.Lock()
+	h := t.handler
+	t.mu.Unlock()
+	if h == nil || reply == 0 {
+		return false
+	}
+	magic := uintptr(replyMagic)
+	text, err := h(request)
+	if err != nil {
+		magic, text = failMagic, err.Error()
+	}
+	sendCopyData(reply, t.hwnd, magic, text)
+	return true
+}
+
+func (t *Tray) setWindowText(text string) {
+	procSetWindowTextW.Call(t.hwnd, uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(text))))
+}
+
+// animate runs the frame timer while the status has more than one frame.
+func (t *Tray) animate() {
+	t.mu.Lock()
+	frames := len(t.icons[t.status])
+	t.mu.Unlock()
+	if frames > 1 {
+		procSetTimer.Call(t.hwnd, animationTimer, uintptr(frameInterval/time.Millisecond), 0)
+	} else {
+		procKillTimer.Call(t.hwnd, animationTimer)
+	}
+}
+
+// nextFrame shows the next frame of the current icon.
+func (t *Tray) nextFrame() {
+	t.mu.Lock()
+	t.frame++
+	t.mu.Unlock()
+	t.notify(nimModify)
+}
+
+// Close removes the icon and waits for its window thread to exit.
+func (t *Tray) Close() {
+	t.once.Do(func() {
+		procPostThreadMessageW.Call(t.threadID, wmQuit, 0, 0)
+		<-t.done
+	})
+}
+
+// showMenu pops up the context menu at the cursor and runs the chosen item.
+func (t *Tray) showMenu() {
+	menu, _, _ := procCreatePopupMenu.Call()
+	if menu == 0 {
+		return
+	}
+	defer procDestroyMenu.Call(menu)
+	for i, it := range t.items {
+		if it.Label == "" {
+			procAppendMenuW.Call(menu, mfSeparator, 0, 0)
+			continue
+		}
+		procAppendMenuW.Call(menu, mfString, uintptr(i+1), uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(it.Label))))
+	}
+	var pt struct{ X, Y int32 }
+	procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
+	// The menu only closes on an outside click while the window is in the
+	// foreground.
+	procSetForegroundWindow.Call(t.hwnd)
+	cmd, _, _ := procTrackPopupMenu.Call(menu, tpmRightButton|tpmNoNotify|tpmReturnCmd,
+		uintptr(pt.X), uintptr(pt.Y), 0, t.hwnd, 0)
+	procPostMessageW.Call(t.hwnd, wmNull, 0, 0)
+	if cmd == 0 || int(cmd) > len(t.items) {
+		return
+	}
+	if fn := t.items[cmd-1].OnClick; fn != nil {
+		go fn()
+	}
+}
+
+// runCommand runs the item whose Command is command and reports whether
+// there was one.
+func (t *Tray) runCommand(command string) bool {
+	for _, it := range t.items {
+		if it.Command != "" && it.Command == command && it.OnClick != nil {
+			go it.OnClick()
+			return true
+		}
+	}
+	return false
+}
+
+// Send runs the menu item with the given Command in the tray of another
+// running instance.
+func Send(command string) error {
+	hwnd, _, _ := procFindWindowW.Call(uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(className))), 0)
+	if hwnd == 0 {
+		return fmt.Errorf("no running instance with a tray icon")
+	}
+	if sendCopyData(hwnd, 0, commandMagic, command) == 0 {
+		return fmt.Errorf("the running instance does not know %q", command)
+	}
+	return nil
+}
+
+// sendCopyData sends text to hwnd in a WM_COPYDATA tagged magic and returns
+// what the receiver returned.
+func sendCopyData(hwnd, from, magic uintptr, text string) uintptr {
+	b := append([]byte(text), 0)
+	cds := copyDataStruct{Data: magic, Size: uint32(len(b)), Bytes: &b[0]}
+	r, _, _ := procSendMessageW.Call(hwnd, wmCopyData, from, uintptr(unsafe.Pointer(&cds)))
+	return r
+}
+
+var (
+	replyOnce      sync.Once
+	replyErr       error
+	repliesMu      sync.Mutex
+	pendingReplies = map[uintptr]*pendingReply{}
+)
+
+type pendingReply struct {
+	text   string
+	failed bool
+	done   bool
+}
+
+// Request sends request to the handler of the tray in another running
+// instance and returns its reply. The reply comes back to a message-only
+// window on this thread while SendMessageW waits, since the tray answers
+// before returning.
+func Request(request string) (string, error) {
+	hwnd, _, _ := procFindWindowW.Call(uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(className))), 0)
+	if hwnd == 0 {
+		return "", fmt.Errorf("no running instance with a tray icon")
+	}
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	instance, _, _ := procGetModuleHandleW.Call(0)
+	replyOnce.Do(func() {
+		wc := wndClassEx{
+			WndProc:   syscall.NewCallback(replyProc),
+			Instance:  instance,
+			ClassName: syscall.StringToUTF16Ptr(replyClassName),
+		}
+		wc.Size = uint32(unsafe.Sizeof(wc))
+		if r, _, err := procRegisterClassExW.Call(uintptr(unsafe.Pointer(&wc))); r == 0 {
+			replyErr = fmt.Errorf("RegisterClassExW failed: %v", err)
+		}
+	})
+	if replyErr != nil {
+		return "", replyErr
+	}
+	reply, _, err := procCreateWindowExW.Call(0, uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(replyClassName))),
+		0, 0, 0, 0, 0, 0, hwndMessage, 0, instance, 0)
+	if reply == 0 {
+		return "", fmt.Errorf("CreateWindowExW failed: %v", err)
+	}
+	defer procDestroyWindow.Call(reply)
+
+	p := &pendingReply{}
+	repliesMu.Lock()
+	pendingReplies[reply] = p
+	repliesMu.Unlock()
+	defer func() {
+		repliesMu.Lock()
+		delete(pendingReplies, reply)
+		repliesMu.Unlock()
+	}()
+
+	if sendCopyData(hwnd, reply, requestMagic, request) == 0 || !p.done {
+		return "", fmt.Errorf("the running instance does not answer requests")
+	}
+	if p.failed {
+		return "", ReplyError(p.text)
+	}
+	return p.text, nil
+}
+
+func replyProc(hwnd, message, wParam, lParam uintptr) uintptr {
+	if message == wmCopyData {
+		repliesMu.Lock()
+		p := pendingReplies[hwnd]
+		repliesMu.Unlock()
+		cds := *(**copyDataStruct)(unsafe.Pointer(&lParam))
+		if p != nil && (cds.Data == replyMagic || cds.Data == failMagic) {
+			p.text = strings.TrimRight(string(unsafe.Slice(cds.Bytes, cds.Size)), "\x00")
+			p.failed = cds.Data == failMagic
+			p.done = true
+			return 1
+		}
+	}
+	r, _, _ := procDefWindowProcW.Call(hwnd, message, wParam, lParam)
+	return r
+}
+
+// Query returns the tooltip of the tray in another running instance.
+func Query() (string, error) {
+	hwnd, _, _ := procFindWindowW.Call(uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(className))), 0)
+	if hwnd == 0 {
+		return "", fmt.Errorf("no running instance with a tray icon")
+	}
+	buf := make([]uint16, 1024)
+	n, _, _ := procSendMessageW.Call(hwnd, wmGetText, uintptr(len(buf)), uintptr(unsafe.Pointer(&buf[0])))
+	return syscall.UTF16ToString(buf[:n]), nil
+}
+
+func wndProc(hwnd, message, wParam, lParam uintptr) uintptr {
+	activeMu.Lock()
+	t := active
+	activeMu.Unlock()
+	if t != nil && hwnd == t.hwnd {
+		switch {
+		case message == wmTrayIcon && (lParam == wmLButtonUp || lParam == wmRButtonUp):
+			t.showMenu()
+			return 0
+		case message == wmAnimate:
+			t.animate()
+			return 0
+		case message == wmTimer && wParam == animationTimer:
+			t.nextFrame()
+			return 0
+		case message == wmCopyData:
+			cds := *(**copyDataStruct)(unsafe.Pointer(&lParam))
+			if (cds.Data != commandMagic && cds.Data != requestMagic) || cds.Size == 0 {
+				return 0
+			}
+			command := strings.TrimRight(string(unsafe.Slice(cds.Bytes, cds.Size)), "\x00")
+			if cds.Data == requestMagic && t.answer(wParam, command) {
+				return 1
+			}
+			if cds.Data == commandMagic && t.runCommand(command) {
+				return 1
+			}
+			return 0
+		case message == wmSettingChange && lParam != 0 &&
+			windows.UTF16PtrToString(*(**uint16)(unsafe.Pointer(&lParam))) == "ImmersiveColorSet":
+			t.themeChanged()
+		case t.taskbarCreated != 0 && message == t.taskbarCreated:
+			t.notify(nimAdd)
+			return 0
+		}
+	}
+	r, _, _ := procDefWindowProcW.Call(hwnd, message, wParam, lParam)
+	return r
+}
+
+// SystemTheme reports whether the taskbar uses the light or dark theme.
+func SystemTheme() Theme {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`, registry.QUERY_VALUE)
+	if err != nil {
+		return ThemeDark
+	}
+	defer key.Close()
+	light, _, err := key.GetIntegerValue("SystemUsesLightTheme")
+	if err == nil && light != 0 {
+		return ThemeLight
+	}
+	return ThemeDark
+}
+
+// OpenFolder opens path in Explorer.
+func OpenFolder(path string) error {
+	return exec.Command("explorer", path).Start()
+}
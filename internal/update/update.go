@@ -0,0 +1,279 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package update replaces stt.exe with the build the release workflow
+// publishes under the rolling "Latest" tag on GitHub. Builds are identified
+// by the commit they were built from, which Go stamps into the binary.
+package update
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+)
+
+// CLIAsset is the release asset holding stt.exe.
+const CLIAsset = "stt-cli-windows-amd64.zip"
+
+// CLIMember is the executable inside CLIAsset.
+const CLIMember = "stt.exe"
+
+// maxAsset limits how much of a release asset is downloaded.
+const maxAsset = 256 << 20
+
+var (
+	// tagURL and downloadURL are variables so tests can point them at a
+	// local server.
+	tagURL      = "https://api.github.com/repos/Joey-Kot/STT-for-Windows/git/ref/tags/Latest"
+	downloadURL = "https://github.com/Joey-Kot/STT-for-Windows/releases/download/Latest/"
+)
+
+// Revision is the commit the release workflow built this binary from, set
+// with -ldflags "-X stt/internal/update.Revision=<commit>". It takes the
+// place of the commit Go stamps, which build output left in the checkout
+// would mark as modified.
+var Revision string
+
+// PublicKey is the base64 Ed25519 public key release assets are signed
+// with, set with -ldflags "-X stt/internal/update.PublicKey=<key>" by the
+// release workflow. Builds without it cannot verify a download and refuse
+// to update.
+var PublicKey string
+
+// ErrChecksum reports a download that does not match its published SHA-256.
+var ErrChecksum = errors.New("checksum mismatch")
+
+// ErrSignature reports a download whose signature does not verify against
+// PublicKey.
+var ErrSignature = errors.New("signature mismatch")
+
+// ErrNoPublicKey reports a build that has no PublicKey to verify downloads.
+var ErrNoPublicKey = errors.New("this build has no update signing key; download the release manually")
+
+// Current returns the commit this binary was built from, or "" for builds
+// without version control information. Unless Revision is set, a build with
+// uncommitted changes gets a "-dirty" suffix, so it never matches a release.
+func Current() string {
+	if Revision != "" {
+		return Revision
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	var revision, modified string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value
+		}
+	}
+	if revision != "" && modified == "true" {
+		revision += "-dirty"
+	}
+	return revision
+}
+
+// Latest returns the commit the published release was built from.
+func Latest(ctx context.Context, client *http.Client) (string, error) {
+	var ref struct {
+		Object struct {
+			SHA  string `json:"sha"`
+			Type string `json:"type"`
+			URL  string `json:"url"`
+		} `json:"object"`
+	}
+	if err := getJSON(ctx, client, tagURL, &ref); err != nil {
+		return "", err
+	}
+	// An annotated tag points at a tag object, which points at the commit.
+	if ref.Object.Type == "tag" {
+		var tag struct {
+			Object struct {
+				SHA string `json:"sha"`
+			} `json:"object"`
+		}
+		if err := getJSON(ctx, client, ref.Object.URL, &tag); err != nil {
+			return "", err
+		}
+		return tag.Object.SHA, nil
+	}
+	if ref.Object.SHA == "" {
+		return "", fmt.Errorf("release tag has no commit")
+	}
+	return ref.Object.SHA, nil
+}
+
+// Download fetches asset with its .sha256 and .sig files into dir and
+// returns the path of the asset once its checksum matches and its signature
+// verifies against PublicKey. The checksum only catches a damaged download;
+// the signature, made with a key the release workflow keeps secret, is what
+// tells a genuine release from a replaced one.
+func Download(ctx context.Context, client *http.Client, asset, dir string) (string, error) {
+	if PublicKey == "" {
+		return "", ErrNoPublicKey
+	}
+	key, err := base64.StdEncoding.DecodeString(PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return "", fmt.Errorf("invalid update signing key in this build")
+	}
+	sum, err := get(ctx, client, downloadURL+asset+".sha256", 4096)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(sum))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty checksum file for %s", asset)
+	}
+	want := strings.ToLower(fields[0])
+
+	data, err := get(ctx, client, downloadURL+asset, maxAsset)
+	if err != nil {
+		return "", err
+	}
+	got := sha256.Sum256(data)
+	if hex.EncodeToString(got[:]) != want {
+		return "", fmt.Errorf("%s: %w", asset, ErrChecksum)
+	}
+	sigText, err := get(ctx, client, downloadURL+asset+".sig", 4096)
+	if err != nil {
+		return "", err
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sigText)))
+	if err != nil || !ed25519.Verify(ed25519.PublicKey(key), data, sig) {
+		return "", fmt.Errorf("%s: %w", asset, ErrSignature)
+	}
+	path := filepath.Join(dir, asset)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Apply replaces exe with member from the zip archive at zipPath. Windows
+// cannot overwrite a running executable but can rename it, so the old one is
+// moved to exe+".old", which RemoveOld deletes on a later start. Files next to
+// exe, such as config.json, are left alone.
+func Apply(zipPath, member, exe string) error {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	var src *zip.File
+	for _, f := range zr.File {
+		if strings.EqualFold(filepath.Base(f.Name), member) {
+			src = f
+			break
+		}
+	}
+	if src == nil {
+		return fmt.Errorf("%s not found in %s", member, filepath.Base(zipPath))
+	}
+
+	newPath := exe + ".new"
+	if err := extract(src, newPath); err != nil {
+		_ = os.Remove(newPath)
+		return err
+	}
+	oldPath := exe + ".old"
+	_ = os.Remove(oldPath)
+	if err := os.Rename(exe, oldPath); err != nil {
+		_ = os.Remove(newPath)
+		return err
+	}
+	if err := os.Rename(newPath, exe); err != nil {
+		// Put the old binary back so the install keeps working.
+		_ = os.Rename(oldPath, exe)
+		_ = os.Remove(newPath)
+		return err
+	}
+	return nil
+}
+
+// RemoveOld deletes the binary a previous Apply moved aside, once it is no
+// longer running.
+func RemoveOld(exe string) {
+	_ = os.Remove(exe + ".old")
+}
+
+// extract writes f to dst. A member larger than maxAsset is refused rather
+// than installed cut short.
+func extract(f *zip.File, dst string) error {
+	if f.UncompressedSize64 > maxAsset {
+		return fmt.Errorf("%s is larger than %d bytes", f.Name, maxAsset)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	// The header can understate the size; reading one byte past the limit
+	// tells a full member from a cut one.
+	n, err := io.Copy(out, io.LimitReader(rc, maxAsset+1))
+	if err == nil && n > maxAsset {
+		err = fmt.Errorf("%s is larger than %d bytes", f.Name, maxAsset)
+	}
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	data, err := get(ctx, client, url, 1<<20)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func get(ctx context.Context, client *http.Client, url string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("GET %s: response larger than %d bytes", url, limit)
+	}
+	return data, nil
+}
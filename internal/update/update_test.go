@@ -0,0 +1,169 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package update
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testRelease serves a release of exe, with sum as its checksum unless it is
+// empty, signed with a key set as PublicKey unless badSig is set.
+func testRelease(t *testing.T, exe []byte, sum string, badSig bool) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create(CLIMember)
+	_, _ = w.Write(exe)
+	_ = zw.Close()
+	archive := buf.Bytes()
+	if sum == "" {
+		h := sha256.Sum256(archive)
+		sum = hex.EncodeToString(h[:])
+	}
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := archive
+	if badSig {
+		signed = []byte("another archive")
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, signed))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ref":
+			_, _ = w.Write([]byte(`{"object":{"sha":"abc123","type":"commit"}}`))
+		case "/dl/" + CLIAsset:
+			_, _ = w.Write(archive)
+		case "/dl/" + CLIAsset + ".sha256":
+			_, _ = w.Write([]byte(sum + "  dist/" + CLIAsset + "\n"))
+		case "/dl/" + CLIAsset + ".sig":
+			_, _ = w.Write([]byte(sig + "\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	oldTag, oldDownload, oldKey := tagURL, downloadURL, PublicKey
+	tagURL, downloadURL = srv.URL+"/ref", srv.URL+"/dl/"
+	PublicKey = base64.StdEncoding.EncodeToString(pub)
+	t.Cleanup(func() { tagURL, downloadURL, PublicKey = oldTag, oldDownload, oldKey })
+}
+
+func TestDownloadAndApply(t *testing.T) {
+	testRelease(t, []byte("new binary"), "", false)
+	ctx := context.Background()
+	if rev, err := Latest(ctx, http.DefaultClient); err != nil || rev != "abc123" {
+		t.Fatalf("Latest = %q, %v", rev, err)
+	}
+
+	dir := t.TempDir()
+	path, err := Download(ctx, http.DefaultClient, CLIAsset, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exe := filepath.Join(dir, "stt.exe")
+	config := filepath.Join(dir, "config.json")
+	for name, data := range map[string]string{exe: "old binary", config: "{}"} {
+		if err := os.WriteFile(name, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Apply(path, CLIMember, exe); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "new binary" {
+		t.Fatalf("exe = %q after Apply", got)
+	}
+	if got, _ := os.ReadFile(exe + ".old"); string(got) != "old binary" {
+		t.Fatalf("old exe = %q after Apply", got)
+	}
+	if got, _ := os.ReadFile(config); string(got) != "{}" {
+		t.Fatalf("config = %q after Apply", got)
+	}
+	RemoveOld(exe)
+	if _, err := os.Stat(exe + ".old"); !os.IsNotExist(err) {
+		t.Fatalf("old exe still there: %v", err)
+	}
+}
+
+func TestDownloadRejectsBadChecksum(t *testing.T) {
+	testRelease(t, []byte("new binary"), "00", false)
+	_, err := Download(context.Background(), http.DefaultClient, CLIAsset, t.TempDir())
+	if !errors.Is(err, ErrChecksum) {
+		t.Fatalf("err = %v, want ErrChecksum", err)
+	}
+}
+
+func TestDownloadRejectsBadSignature(t *testing.T) {
+	testRelease(t, []byte("new binary"), "", true)
+	dir := t.TempDir()
+	_, err := Download(context.Background(), http.DefaultClient, CLIAsset, dir)
+	if !errors.Is(err, ErrSignature) {
+		t.Fatalf("err = %v, want ErrSignature", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, CLIAsset)); !os.IsNotExist(err) {
+		t.Fatalf("unverified asset written: %v", err)
+	}
+
+	PublicKey = ""
+	if _, err := Download(context.Background(), http.DefaultClient, CLIAsset, dir); !errors.Is(err, ErrNoPublicKey) {
+		t.Fatalf("err = %v without a key, want ErrNoPublicKey", err)
+	}
+}
+
+func TestCurrentPrefersRevision(t *testing.T) {
+	defer func(r string) { Revision = r }(Revision)
+	Revision = "abc123"
+	if got := Current(); got != "abc123" {
+		t.Fatalf("Current = %q, want the stamped revision", got)
+	}
+}
+
+func TestApplyRejectsOversizedMember(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, CLIAsset)
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	// A raw member keeps the size its header claims.
+	data := []byte("new binary")
+	w, _ := zw.CreateRaw(&zip.FileHeader{Name: CLIMember, Method: zip.Store, CRC32: crc32.ChecksumIEEE(data), CompressedSize64: uint64(len(data)), UncompressedSize64: maxAsset + 1})
+	_, _ = w.Write(data)
+	_ = zw.Close()
+	if err := os.WriteFile(zipPath, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	exe := filepath.Join(dir, "stt.exe")
+	if err := os.WriteFile(exe, []byte("old binary"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(zipPath, CLIMember, exe); err == nil {
+		t.Fatal("Apply installed an oversized member")
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "old binary" {
+		t.Fatalf("exe = %q after a refused Apply", got)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package urlscheme registers the stt:// URI scheme and parses its links,
+// such as stt://toggle and stt://transcribe?path=D:\Calls\a.mp3.
+package urlscheme
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Scheme is the URI scheme STT handles.
+const Scheme = "stt"
+
+// Actions are the actions a link may name. All but transcribe are sent to
+// the running record-mode instance like `stt ctl`.
+var Actions = []string{"start", "stop", "toggle", "pause", "resume", "cancel", "transcribe"}
+
+// Request is what a link asks for.
+type Request struct {
+	Action string
+	Path   string // the file to transcribe, for "transcribe"
+}
+
+// Parse parses an stt: link. Both stt://toggle and stt:toggle work, and a
+// trailing slash some browsers add is ignored.
+func Parse(link string) (Request, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return Request{}, err
+	}
+	if !strings.EqualFold(u.Scheme, Scheme) {
+		return Request{}, fmt.Errorf("'%s' is not an %s: link", link, Scheme)
+	}
+	action := u.Host
+	if action == "" {
+		action = u.Opaque
+	}
+	action = strings.ToLower(strings.Trim(action, "/"))
+	if strings.Trim(u.Path, "/") != "" {
+		return Request{}, fmt.Errorf("unexpected path in '%s'", link)
+	}
+	if !slices.Contains(Actions, action) {
+		return Request{}, fmt.Errorf("unknown action '%s' (allowed: %s)", action, strings.Join(Actions, ", "))
+	}
+	r := Request{Action: action}
+	if action == "transcribe" {
+		r.Path = u.Query().Get("path")
+		if r.Path == "" {
+			return Request{}, fmt.Errorf("stt://transcribe needs ?path=<file>")
+		}
+		if !localPath(r.Path) {
+			return Request{}, fmt.Errorf("stt://transcribe needs the full path of a local file, not '%s'", r.Path)
+		}
+	}
+	return r, nil
+}
+
+// localPath reports whether path names a file on a local drive by its full
+// path. Any web page can offer a link, so relative paths, which would
+// resolve against the browser's directory, and UNC and device paths (\\host,
+// //host, \\?\), which would send the user's credentials to another host,
+// are refused.
+func localPath(path string) bool {
+	if len(path) >= 3 && path[1] == ':' && (path[2] == '\\' || path[2] == '/') {
+		c := path[0] | 0x20
+		return c >= 'a' && c <= 'z'
+	}
+	// Elsewhere than Windows a full path starts with a single slash.
+	return os.PathSeparator == '/' && len(path) >= 2 && path[0] == '/' && path[1] != '/' && path[1] != '\\'
+}
+
+// Link returns the link for action; path is the file to transcribe for
+// "transcribe" and is ignored otherwise.
+func Link(action, path string) string {
+	u := url.URL{Scheme: Scheme, Host: action}
+	if action == "transcribe" {
+		u.RawQuery = url.Values{"path": {path}}.Encode()
+	}
+	return u.String()
+}
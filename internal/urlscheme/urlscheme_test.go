@@ -0,0 +1,51 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package urlscheme
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := map[string]Request{
+		"stt://toggle": {Action: "toggle"},
+		"stt://Start/": {Action: "start"},
+		"STT:cancel":   {Action: "cancel"},
+		`stt://transcribe?path=D:\Calls\a%20b.mp3`: {Action: "transcribe", Path: `D:\Calls\a b.mp3`},
+	}
+	for link, want := range tests {
+		got, err := Parse(link)
+		if err != nil || got != want {
+			t.Errorf("Parse(%q) = %+v, %v; want %+v", link, got, err, want)
+		}
+	}
+	bads := []string{"http://toggle", "stt://record", "stt://transcribe", "stt://stop/now"}
+	// Only full paths on a local drive may be transcribed.
+	for _, path := range []string{`\\attacker\share\a.mp3`, "//attacker/share/a.mp3", `\\?\C:\a.mp3`, `\\.\pipe\a.mp3`, `/\attacker\a.mp3`, "a.mp3", `..\a.mp3`, `C:a.mp3`, `\a.mp3`} {
+		bads = append(bads, Link("transcribe", path))
+	}
+	for _, bad := range bads {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) = nil error", bad)
+		}
+	}
+}
+
+func TestLinkRoundTrip(t *testing.T) {
+	want := Request{Action: "transcribe", Path: `D:\Calls\a&b #1.mp3`}
+	got, err := Parse(Link(want.Action, want.Path))
+	if err != nil || got != want {
+		t.Errorf("Parse(Link) = %+v, %v; want %+v", got, err, want)
+	}
+	if got := Link("toggle", "ignored"); got != "stt://toggle" {
+		t.Errorf("Link(toggle) = %q", got)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build windows
+
+package urlscheme
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// keyPath is the class of the scheme. HKCU needs no elevation.
+const keyPath = `Software\Classes\` + Scheme
+
+// Install makes Windows open stt: links with command, which gets the link
+// as "%1", showing icon where the link is offered.
+func Install(command, icon string) error {
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, keyPath, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+	if err := key.SetStringValue("", "URL:STT"); err != nil {
+		return err
+	}
+	if err := key.SetStringValue("URL Protocol", ""); err != nil {
+		return err
+	}
+	iconKey, _, err := registry.CreateKey(key, "DefaultIcon", registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	err = iconKey.SetStringValue("", icon)
+	iconKey.Close()
+	if err != nil {
+		return err
+	}
+	cmd, _, err := registry.CreateKey(key, `shell\open\command`, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer cmd.Close()
+	return cmd.SetStringValue("", command)
+}
+
+// Uninstall removes the scheme. Nothing happens when it is not registered.
+func Uninstall() error {
+	for _, path := range []string{`\shell\open\command`, `\shell\open`, `\shell`, `\DefaultIcon`, ""} {
+		err := registry.DeleteKey(registry.CURRENT_USER, keyPath+path)
+		if err != nil && !errors.Is(err, registry.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Installed returns the command registered for the scheme, or "" when
+// there is none.
+func Installed() (string, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, keyPath+`\shell\open\command`, registry.QUERY_VALUE)
+	if errors.Is(err, registry.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer key.Close()
+	command, _, err := key.GetStringValue("")
+	return command, err
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package watchdog keeps a worker process running: a small parent starts
+// it, and when it crashes writes the end of its error output to a crash log
+// and starts it again after a delay that grows with repeated crashes.
+package watchdog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"time"
+
+	"stt/internal/i18n"
+)
+
+// Env is set in the worker's environment, so a worker started with the
+// watchdog's own command line knows not to start another watchdog.
+const Env = "STT_WATCHDOG_WORKER"
+
+// Restarts back off from MinRestartDelay, doubling per crash up to
+// MaxRestartDelay. A worker that ran for StableRun starts the count over.
+const (
+	MinRestartDelay = 5 * time.Second
+	MaxRestartDelay = 5 * time.Minute
+	StableRun       = 10 * time.Minute
+)
+
+// crashTail is how much of the worker's error output a crash log entry
+// keeps; a Go panic trace fits comfortably.
+const crashTail = 64 << 10
+
+// restartDelay is a variable so tests need not wait.
+var restartDelay = RestartDelay
+
+// RestartDelay returns how long to wait before restarting a worker that has
+// crashed crashes times in a row.
+func RestartDelay(crashes int) time.Duration {
+	d := MinRestartDelay
+	for i := 1; i < crashes && d < MaxRestartDelay; i++ {
+		d *= 2
+	}
+	return min(d, MaxRestartDelay)
+}
+
+// IsWorker reports whether this process was started by a watchdog.
+func IsWorker() bool {
+	return os.Getenv(Env) != ""
+}
+
+// Run starts exe with args as the worker, passing its output through, and
+// restarts it whenever it exits with a nonzero code. Each crash is appended
+// to crashLog and reported to onCrash, which may be nil. Run returns the
+// worker's exit code once it exits with 0, or after an interrupt.
+func Run(exe string, args []string, crashLog string, onCrash func(code int, restartIn time.Duration)) int {
+	// The console delivers an interrupt to the worker too; the watchdog
+	// just lets it exit and does not restart it.
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	defer signal.Stop(interrupted)
+
+	crashes := 0
+	for {
+		started := time.Now()
+		tail := &tailBuffer{max: crashTail}
+		cmd := exec.Command(exe, args...)
+		cmd.Env = append(os.Environ(), Env+"=1")
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = io.MultiWriter(os.Stderr, tail)
+		if err := cmd.Start(); err != nil {
+			fmt.Printf("[watchdog] %s\n", i18n.Sprintf("failed to start %s: %v", exe, err))
+			return 1
+		}
+		err := cmd.Wait()
+		code := cmd.ProcessState.ExitCode()
+		select {
+		case <-interrupted:
+			return code
+		default:
+		}
+		if err == nil {
+			return 0
+		}
+
+		ran := time.Since(started)
+		if ran >= StableRun {
+			crashes = 0
+		}
+		crashes++
+		delay := restartDelay(crashes)
+		fmt.Printf("[watchdog] %s\n", i18n.Sprintf("worker exited with code %d after %s; restarting in %s", code, ran.Round(time.Second), delay))
+		if err := appendCrash(crashLog, code, ran, tail.Bytes()); err != nil {
+			fmt.Printf("[watchdog] %s\n", i18n.Sprintf("failed to write crash log: %v", err))
+		}
+		if onCrash != nil {
+			onCrash(code, delay)
+		}
+		select {
+		case <-interrupted:
+			return code
+		case <-time.After(delay):
+		}
+	}
+}
+
+// appendCrash adds an entry for one crash to the log at path.
+func appendCrash(path string, code int, ran time.Duration, output []byte) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "==== %s: exit code %d after %s ====\n%s\n", time.Now().Format(time.RFC3339), code, ran.Round(time.Second), output)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.max {
+		t.buf = append(t.buf[:0], t.buf[len(t.buf)-t.max:]...)
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) Bytes() []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]byte(nil), t.buf...)
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package watchdog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMain doubles as the worker: with WATCHDOG_TEST_DIR set, the test
+// binary crashes on its first run and exits cleanly on the second.
+func TestMain(m *testing.M) {
+	if dir := os.Getenv("WATCHDOG_TEST_DIR"); dir != "" && IsWorker() {
+		marker := filepath.Join(dir, "ran")
+		if _, err := os.Stat(marker); err != nil {
+			_ = os.WriteFile(marker, nil, 0644)
+			fmt.Fprintln(os.Stderr, "panic: boom")
+			os.Exit(2)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestRunRestartsCrashedWorker(t *testing.T) {
+	restartDelay = func(int) time.Duration { return 0 }
+	defer func() { restartDelay = RestartDelay }()
+	dir := t.TempDir()
+	t.Setenv("WATCHDOG_TEST_DIR", dir)
+	crashLog := filepath.Join(dir, "crash.log")
+
+	var crashes []int
+	code := Run(os.Args[0], []string{"-test.run=^$"}, crashLog, func(code int, _ time.Duration) {
+		crashes = append(crashes, code)
+	})
+	if code != 0 || len(crashes) != 1 || crashes[0] != 2 {
+		t.Fatalf("Run = %d, crashes %v; want 0 after one crash with code 2", code, crashes)
+	}
+	log, err := os.ReadFile(crashLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "exit code 2") || !strings.Contains(string(log), "panic: boom") {
+		t.Fatalf("crash log = %q", log)
+	}
+}
+
+func TestRestartDelayBacksOff(t *testing.T) {
+	cases := []struct {
+		crashes int
+		want    time.Duration
+	}{
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{4, 40 * time.Second},
+		{7, 5 * time.Minute},
+		{100, 5 * time.Minute},
+	}
+	for _, c := range cases {
+		if got := RestartDelay(c.crashes); got != c.want {
+			t.Errorf("RestartDelay(%d) = %v, want %v", c.crashes, got, c.want)
+		}
+	}
+}
+
+func TestTailBufferKeepsEnd(t *testing.T) {
+	tail := &tailBuffer{max: 4}
+	_, _ = tail.Write([]byte("abc"))
+	_, _ = tail.Write([]byte("def"))
+	if got := string(tail.Bytes()); got != "cdef" {
+		t.Fatalf("tail = %q, want %q", got, "cdef")
+	}
+}
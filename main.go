@@ -12,121 +12,84 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 
 	"stt/internal/app"
+	"stt/internal/applog"
 	"stt/internal/config"
+	"stt/internal/i18n"
+	"stt/internal/update"
+	"stt/internal/watchdog"
 )
 
-func usage() {
-	programName := filepath.Base(os.Args[0])
-	fmt.Fprintf(os.Stderr, `用法: %s [选项]
-
-该程序用于录音并将音频上传到 ASR 接口，识别结果可自动粘贴到当前光标。
-
-选项:
-[自定义配置文件]
-  -config <string>
-        指定配置文件（JSON），若未提供则默认读取 ./config.json（不存在则生成默认文件并退出）
-  -file <string>
-        指定音频文件，直接上传已有音频获得转录结果。
-  -output <string>
-        -file 模式下输出 txt 的路径（可选，默认当前目录同名 .txt）
-
-[API 端点配置]
-  -api-endpoint <string>
-        ASR 接口 URL (e.g. https://api.example/v1/audio/transcriptions)
-  -token <string>
-        授权 Token（Bearer）
-  -model <string>
-        模型名称
-  -language <string>
-        识别语言 (e.g. zh)
-  -prompt <string>
-        识别提示文本（可选）
-  -text-path <string>
-        JSON 路径，用于从 ASR 返回的 JSON 中抽取文本（点分 + 数组下标语法）
-        默认: "text"
-  -extra-config <string>
-        解析自定义请求字段并合并到向 API 端点发送的请求中，必须填写转义字符串，否则将无法解析。
-
-[ffmpeg 转码配置]
-  -codecs <string>
-        音频编码器类型。默认: OPUS
-  -container <string>
-        音频容器类型。默认: OGG
-  -channels <int>
-        音频通道数（默认 1）
-  -sampling-rate <int>
-        采样率（Hz，默认 16000 Hz）
-  -sampling-rate-depth <int>
-        采样精度（bits，默认 16；允许值：8,16,24,32）
-  -bit-rate <int>
-        目标音频比特率（kbps，默认 128 kbps）
-
-[网络请求配置]
-  -request-timeout <int>
-        请求超时秒数（默认 60）
-  -max-retry <int>
-        上传最大重试次数（默认 3）
-  -retry-base-delay <float>
-        重试基准延迟秒（默认 0.5）
-  -enable-http2 <true|false>
-        是否启用 HTTP/2（默认开启）
-  -verify-ssl <true|false>
-        是否验证 HTTPS 证书（默认开启）
-
-[热键配置]
-  -start-key <string>
-        开始/停止热键（例如 "ctrl+alt+q"）
-  -pause-key <string>
-        暂停/恢复热键（例如 "ctrl+alt+s"）
-  -cancel-key <string>
-        取消录音热键（例如 "alt+esc"）
-  -hotkeyhook <true|false>
-        是否使用低级键盘钩子 (WH_KEYBOARD_LL) 来独占热键（默认开启）。
-
-[缓存配置]
-  -cache-dir <string>
-        设置缓存目录。启用后如不存在路径会尝试自动创建。
-  -keep-cache <true|false>
-        是否启用临时文件保存和转录记录回写（默认关闭）。此选项必须启用 -cache-dir 才会生效。
-
-[系统通知配置]
-  -notification <true|false>
-        是否启用 Windows 通知（默认开启）
-  -request-failed-notification <true|false>
-        仅录音模式下：上传重试耗尽后，粘贴占位符 [request failed]（默认关闭）
-
-[DEBUG 配置]
-  -ffmpeg-debug <true|false>
-        是否启用 FFmpeg 详情（默认关闭）。
-  -record-debug <true|false>
-        是否启用录音子系统的调试输出（默认关闭）。
-  -hotkey-debug <true|false>
-        是否启用热键/消息循环的调试输出（默认开启）。
-  -upload-debug <true|false>
-        是否启用上传过程的调试输出（默认关闭）。
-
-  -h, -help, -?
-        显示帮助信息
-
-说明:
-- 配置优先级：命令行标志 > 配置文件 > 默认值
-- sampling-rate 单位为 Hz； bit-rate 单位为 kbps； sampling-rate-depth 单位为 bits
-- TEXT_PATH 使用点分法并支持方括号索引（例如 data.items[0].value）
-- 程序启动时会清理当前目录下所有以 RecordTemp_ 开头的临时文件
-
-`, programName)
-}
-
 func main() {
+	i18n.Set(uiLangFromArgs(os.Args[1:]))
+	if exe, err := os.Executable(); err == nil {
+		update.RemoveOld(exe)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "history" {
+		os.Exit(runHistoryCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "cache" {
+		os.Exit(runCacheCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "queue" {
+		os.Exit(runQueueCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "devices" {
+		os.Exit(runDevicesCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		os.Exit(runDoctorCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "toggle" {
+		os.Exit(runToggleCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "status" {
+		os.Exit(runStatusCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "ctl" {
+		os.Exit(runCtlCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "service" {
+		os.Exit(runServiceCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "autostart" {
+		os.Exit(runAutostartCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "shell-integration" {
+		os.Exit(runShellCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "protocol" {
+		os.Exit(runProtocolCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "open" {
+		os.Exit(runOpenCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "update" {
+		os.Exit(runUpdateCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "transcribe" {
+		os.Exit(runTranscribeCommand(os.Args[2:]))
+	}
 	flag.Usage = usage
 	flagConfigPath := flag.String("config", "", "path to config JSON")
-	flagFilePath := flag.String("file", "", "path to existing audio file to upload")
+	flagFilePath := flag.String("file", "", "path to existing audio file, folder or glob pattern to upload")
+	flagEnvFile := flag.String("env-file", "", "path to .env file")
+	flagPortable := flag.Bool("portable", false, "keep config and data in the working directory")
+	flagPrintConfig := flag.Bool("print-config", false, "print the effective config with credentials masked and exit")
+	flagSimulate := flag.Bool("simulate", false, "run a synthetic recording through the whole pipeline and exit")
+	flagSimulateMock := flag.Bool("simulate-mock", false, "with -simulate, upload to a built-in mock endpoint")
+	flagQuiet := flag.Bool("quiet", false, "print only errors")
+	flagJSON := flag.Bool("json", false, "with -file or -list-devices, print JSON")
+	flagListDevices := flag.Bool("list-devices", false, "list the capture devices with their index, host API, channels and sample rates and exit")
 
 	fv := config.BindFlags(flag.CommandLine)
 
@@ -139,58 +102,195 @@ func main() {
 		usage()
 		return
 	}
+	if *flagListDevices {
+		os.Exit(listDevices(*flagJSON))
+	}
+	// With -file - stdout carries only the transcript, so the log goes to
+	// stderr. -quiet and -json leave the console log out; errors still go
+	// to stderr and the log file gets everything.
+	transcriptOut := os.Stdout
+	if *flagFilePath == "-" {
+		os.Stdout = os.Stderr
+	}
+	if *flagQuiet || *flagJSON {
+		if null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0); err == nil {
+			os.Stdout = null
+		}
+	}
+	// configError reports an invalid config, environment or flag, also as
+	// JSON with -json, and exits.
+	configError := func(msg string) {
+		fmt.Fprintf(os.Stderr, "[main] %s\n", msg)
+		if *flagJSON {
+			writeReport(transcriptOut, app.FileReport{ExitCode: app.ExitConfig, Error: msg})
+		}
+		os.Exit(app.ExitConfig)
+	}
+
+	if *flagEnvFile != "" {
+		if err := config.LoadDotEnv(*flagEnvFile); err != nil {
+			configError(i18n.Sprintf("failed to load env file '%s': %v", *flagEnvFile, err))
+		}
+	} else if err := config.LoadDotEnv(config.DefaultEnvFile()); err != nil && !os.IsNotExist(err) {
+		configError(i18n.Sprintf("failed to load env file '%s': %v", config.DefaultEnvFile(), err))
+	}
 
 	var cfg config.Config
+	configPath := *flagConfigPath
 	if *flagConfigPath != "" {
 		confFromFile, err := config.Load(*flagConfigPath)
 		if err != nil {
-			fmt.Printf("[main] failed to load config '%s': %v\n", *flagConfigPath, err)
-			os.Exit(1)
+			configError(i18n.Sprintf("failed to load config '%s': %v", *flagConfigPath, err))
 		}
 		cfg = confFromFile
 	} else {
-		if _, err := os.Stat("config.json"); err == nil {
-			confFromFile, err := config.Load("config.json")
+		path := config.SelectDefault(*flagPortable)
+		if _, err := os.Stat(path); err == nil {
+			confFromFile, err := config.Load(path)
 			if err != nil {
-				fmt.Printf("[main] failed to load existing config.json: %v\n", err)
-				os.Exit(1)
+				configError(i18n.Sprintf("failed to load config '%s': %v", path, err))
 			}
 			cfg = confFromFile
+			configPath = path
 		} else if os.IsNotExist(err) {
-			if !fv.AnySet() {
-				if err := config.SaveDefault("config.json"); err != nil {
-					fmt.Printf("[main] failed to write default config: %v\n", err)
+			if !fv.AnySet() && !*flagPrintConfig && !*flagSimulate {
+				if err := config.SaveDefault(path); err != nil {
+					fmt.Printf("[main] %s\n", i18n.Sprintf("failed to write default config: %v", err))
 					os.Exit(1)
 				}
-				fmt.Printf("[main] default config created at %s. Please edit it and re-run.\n", "config.json")
+				if err := config.SaveTemplate(config.TemplatePath(path)); err != nil {
+					fmt.Printf("[main] %s\n", i18n.Sprintf("failed to write config template: %v", err))
+				} else {
+					fmt.Printf("[main] %s\n", i18n.Sprintf("annotated template written to %s", config.TemplatePath(path)))
+				}
+				msg := i18n.Sprintf("default config created at %s. Please edit it and re-run.", path)
+				// Scripts running -file need a failure, not an empty success.
+				if *flagFilePath != "" {
+					configError(msg)
+				}
+				fmt.Printf("[main] %s\n", msg)
 				return
 			}
 			cfg = config.DefaultConfig()
 		} else {
-			fmt.Printf("[main] failed to stat config.json: %v\n", err)
+			fmt.Printf("[main] %s\n", i18n.Sprintf("failed to stat config '%s': %v", path, err))
 			os.Exit(1)
 		}
 	}
 
+	if err := config.ApplyEnv(&cfg); err != nil {
+		configError(i18n.Sprintf("invalid environment override: %v", err))
+	}
 	config.ApplyFlags(&cfg, fv)
+	i18n.Set(cfg.UILang)
+
+	if *flagPrintConfig {
+		data, err := config.RedactedJSON(&cfg)
+		if err != nil {
+			fmt.Printf("[main] %s\n", i18n.Sprintf("failed to print config: %v", err))
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
 
 	if err := config.Validate(&cfg); err != nil {
-		fmt.Printf("[main] invalid config: %v\n", err)
-		os.Exit(1)
+		configError(i18n.Sprintf("invalid config: %v", err))
 	}
 
 	config.InitCacheDir(&cfg)
 
-	if *flagFilePath != "" {
-		if err := app.RunFileMode(cfg, *flagFilePath, fv.OutputPath); err != nil {
-			fmt.Fprintf(os.Stderr, "[main] file mode failed: %v\n", err)
+	// The watchdog only starts the worker; the worker opens the log file and
+	// does everything else with the same command line.
+	if cfg.Watchdog && *flagFilePath == "" && !*flagSimulate && !watchdog.IsWorker() {
+		exe, err := os.Executable()
+		if err != nil {
+			fmt.Printf("[main] %s\n", i18n.Sprintf("failed to start watchdog: %v", err))
 			os.Exit(1)
 		}
+		os.Exit(app.RunWatchdog(cfg, exe, os.Args[1:]))
+	}
+
+	stopLog := func() {}
+	if logPath := config.LogPath(&cfg); logPath != "" {
+		if stop, err := applog.Start(logPath, config.LogRotation(&cfg)); err != nil {
+			fmt.Printf("[main] %s\n", i18n.Sprintf("failed to open log file '%s': %v", logPath, err))
+		} else {
+			stopLog = stop
+		}
+	}
+	defer stopLog()
+
+	if *flagSimulate {
+		if err := app.RunSimulation(cfg, *flagSimulateMock); err != nil {
+			fmt.Fprintf(os.Stderr, "[main] %s\n", i18n.Sprintf("simulation failed: %v", err))
+			stopLog()
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *flagFilePath != "" {
+		run := func() error { return app.RunFileMode(cfg, *flagFilePath, fv.OutputPath) }
+		if *flagFilePath == "-" {
+			run = func() error { return app.RunStdinMode(cfg, os.Stdin, transcriptOut, fv.OutputPath) }
+		}
+		if *flagJSON {
+			var stdin io.Reader
+			if *flagFilePath == "-" {
+				stdin = os.Stdin
+			}
+			report := app.ReportFileMode(cfg, *flagFilePath, fv.OutputPath, stdin)
+			if report.ExitCode != 0 {
+				fmt.Fprintf(os.Stderr, "[main] %s\n", i18n.Sprintf("file mode failed: %v", report.Error))
+			}
+			writeReport(transcriptOut, report)
+			stopLog()
+			os.Exit(report.ExitCode)
+		}
+		if err := run(); err != nil {
+			fmt.Fprintf(os.Stderr, "[main] %s\n", i18n.Sprintf("file mode failed: %v", err))
+			stopLog()
+			os.Exit(app.ExitCode(err))
+		}
 		return
 	}
 
-	if err := app.RunRecordMode(cfg); err != nil {
-		fmt.Fprintf(os.Stderr, "[main] record mode failed: %v\n", err)
+	if cfg.Tray {
+		registerJumpList(configPath)
+	}
+	load := func() (config.Config, error) { return loadRunConfig(configPath, fv) }
+	if err := app.RunRecordMode(cfg, load); err != nil {
+		fmt.Fprintf(os.Stderr, "[main] %s\n", i18n.Sprintf("record mode failed: %v", err))
+		stopLog()
 		os.Exit(1)
 	}
 }
+
+// writeReport prints the outcome of file mode as indented JSON for -json.
+func writeReport(w io.Writer, report app.FileReport) {
+	if report.Files == nil {
+		report.Files = []app.FileResult{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(report)
+}
+
+// loadRunConfig reads the config again for a reload from the tray: the file
+// at path, or the defaults when path is empty, with the same environment and
+// flag overrides as at startup.
+func loadRunConfig(path string, fv *config.FlagValues) (config.Config, error) {
+	cfg := config.DefaultConfig()
+	if path != "" {
+		var err error
+		if cfg, err = config.Load(path); err != nil {
+			return cfg, err
+		}
+	}
+	if err := config.ApplyEnv(&cfg); err != nil {
+		return cfg, err
+	}
+	config.ApplyFlags(&cfg, fv)
+	return cfg, nil
+}
@@ -0,0 +1,323 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package asr
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+	"unicode/utf8"
+
+	"stt/internal/jsonpath"
+	"stt/internal/redact"
+)
+
+// Options configures a Client. Only Endpoint is required.
+type Options struct {
+	// Endpoint is the URL audio is POSTed to as multipart/form-data.
+	Endpoint string
+	// Token, when set, is sent as a Bearer Authorization header.
+	Token    string
+	Model    string
+	Language string
+	Provider string
+	Prompt   string
+	// TextPath is the JSON path of the transcript in the response. An empty
+	// path tries the common "text" layouts.
+	TextPath string
+	// Extra holds additional form fields. A nil value removes a base field
+	// such as "language".
+	Extra map[string]interface{}
+	// Timeout bounds each request when New is given a nil http.Client.
+	Timeout time.Duration
+	// MaxRetry is the most upload attempts made before giving up.
+	MaxRetry int
+	// RetryBaseDelay is the wait before the first retry; it doubles after
+	// each further attempt.
+	RetryBaseDelay time.Duration
+	// MaxConcurrent is the most uploads the client makes at once, counting
+	// the retries of each; the others wait their turn. 0 means no limit.
+	MaxConcurrent int
+	// RequestsPerMinute is the most upload requests the client starts in a
+	// minute, retries included; the others wait their turn. 0 means no
+	// limit.
+	RequestsPerMinute int
+	// RequestsBurst is how many requests may start at once after an idle
+	// spell under RequestsPerMinute; 0 or 1 means none, every request
+	// waits its interval.
+	RequestsBurst int
+	// VerifySSL only describes the http.Client for Probe reports.
+	VerifySSL bool
+	// Debug logs requests and responses to stdout, with the token and the
+	// credentials in Endpoint and Extra masked.
+	Debug bool
+}
+
+// Client performs ASR uploads.
+type Client struct {
+	opts         Options
+	httpClient   *http.Client
+	language     string
+	sendLanguage bool
+	// slots holds a token for each upload in progress when
+	// opts.MaxConcurrent limits them.
+	slots chan struct{}
+	// limit spaces out requests when opts.RequestsPerMinute is set.
+	limit *limiter
+	// secrets are the credentials in opts that output must not show.
+	secrets []string
+}
+
+// RetryExhaustedError indicates upload retries reached the configured limit.
+type RetryExhaustedError struct {
+	MaxRetry     int
+	Attempts     int
+	LastResponse []byte
+}
+
+func (e *RetryExhaustedError) Error() string {
+	return fmt.Sprintf("exceeded max retries (%d), attempts: %d", e.MaxRetry, e.Attempts)
+}
+
+// progressKey is the context key for WithUploadProgress.
+type progressKey struct{}
+
+// WithUploadProgress returns a copy of ctx that makes uploads with it call
+// report with the bytes of the request body sent so far and its total size.
+// Every retry starts over from zero.
+func WithUploadProgress(ctx context.Context, report func(sent, total int64)) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+// progressReader counts the request body as the transport reads it.
+type progressReader struct {
+	r      io.Reader
+	sent   int64
+	total  int64
+	report func(sent, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.sent += int64(n)
+		p.report(p.sent, p.total)
+	}
+	return n, err
+}
+
+// New creates a new ASR client. A nil httpClient uses one limited to
+// opts.Timeout.
+func New(opts Options, httpClient *http.Client) (*Client, error) {
+	c := &Client{opts: opts, httpClient: httpClient}
+	c.secrets = append([]string{opts.Token}, redact.Values(opts.Extra)...)
+	language, send, err := resolveLanguage(opts.Language, opts.Provider, opts.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	c.language, c.sendLanguage = language, send
+	c.limit = newLimiter(opts.RequestsPerMinute, opts.RequestsBurst)
+	if opts.MaxConcurrent > 0 {
+		c.slots = make(chan struct{}, opts.MaxConcurrent)
+	}
+	return c, nil
+}
+
+// Transcribe uploads the audio and returns extracted text and raw JSON.
+func (c *Client) Transcribe(ctx context.Context, filePath string) (string, []byte, error) {
+	text, raw, _, err := c.TranscribeAttempts(ctx, filePath)
+	return text, raw, err
+}
+
+// TranscribeAttempts is Transcribe that also reports how many upload
+// attempts were made.
+func (c *Client) TranscribeAttempts(ctx context.Context, filePath string) (string, []byte, int, error) {
+	if c.opts.Endpoint == "" {
+		return "", nil, 0, fmt.Errorf("API endpoint is empty")
+	}
+	if c.slots != nil {
+		select {
+		case c.slots <- struct{}{}:
+			defer func() { <-c.slots }()
+		case <-ctx.Done():
+			return "", nil, 0, ctx.Err()
+		}
+	}
+
+	try := 0
+	delay := c.opts.RetryBaseDelay
+	var lastResp []byte
+
+	for {
+		try++
+		if c.limit != nil {
+			waited, err := c.limit.wait(ctx)
+			if err != nil {
+				return "", lastResp, try - 1, err
+			}
+			if waited > 0 && c.opts.Debug {
+				fmt.Printf("[upload] rate limit: waited %v\n", waited.Round(time.Millisecond))
+			}
+		}
+		ok, res := c.doUpload(ctx, filePath)
+		lastResp = res
+		if ok {
+			text := jsonpath.ExtractTextFromResponse(res, c.opts.TextPath)
+			return text, res, try, nil
+		}
+
+		if c.opts.Debug {
+			fmt.Printf("[upload] attempt %d failed: %s\n", try, c.redact(formatResponse(res)))
+		}
+		if try >= c.opts.MaxRetry {
+			return "", lastResp, try, &RetryExhaustedError{
+				MaxRetry:     c.opts.MaxRetry,
+				Attempts:     try,
+				LastResponse: lastResp,
+			}
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func (c *Client) doUpload(ctx context.Context, filePath string) (bool, []byte) {
+	if c.opts.Debug {
+		fmt.Printf("[upload] uploading %s -> %s\n", filePath, c.redact(c.opts.Endpoint))
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		return false, []byte(fmt.Sprintf("open file error: %v", err))
+	}
+	defer f.Close()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
+	if err != nil {
+		return false, []byte(fmt.Sprintf("create form file error: %v", err))
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return false, []byte(fmt.Sprintf("copy file error: %v", err))
+	}
+
+	base := make(map[string]interface{})
+	if c.opts.Model != "" {
+		base["model"] = c.opts.Model
+	}
+	if c.sendLanguage {
+		base["language"] = c.language
+	}
+	if c.opts.Prompt != "" {
+		base["prompt"] = c.opts.Prompt
+	}
+	if c.opts.Extra != nil {
+		for k, v := range c.opts.Extra {
+			if v == nil {
+				delete(base, k)
+				continue
+			}
+			base[k] = v
+		}
+	}
+	for k, v := range base {
+		switch val := v.(type) {
+		case string:
+			_ = writer.WriteField(k, val)
+		case bool:
+			_ = writer.WriteField(k, fmt.Sprintf("%v", val))
+		case float64:
+			_ = writer.WriteField(k, fmt.Sprintf("%v", val))
+		case int:
+			_ = writer.WriteField(k, fmt.Sprintf("%v", val))
+		default:
+			if b, err := json.Marshal(val); err == nil {
+				_ = writer.WriteField(k, string(b))
+			} else {
+				_ = writer.WriteField(k, fmt.Sprintf("%v", val))
+			}
+		}
+	}
+	_ = writer.Close()
+
+	client := c.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: c.opts.Timeout}
+	}
+
+	var reqBody io.Reader = body
+	report, _ := ctx.Value(progressKey{}).(func(sent, total int64))
+	if report != nil {
+		reqBody = &progressReader{r: body, total: int64(body.Len()), report: report}
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.opts.Endpoint, reqBody)
+	if err != nil {
+		return false, []byte(c.redact(fmt.Sprintf("new request error: %v", err)))
+	}
+	req.ContentLength = int64(body.Len())
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if c.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.opts.Token)
+	}
+	req.Header.Set("User-Agent", "stt-go-client/1.0")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	elapsed := time.Since(start)
+	if c.opts.Debug {
+		fmt.Printf("[upload] request duration: %v\n", elapsed)
+	}
+
+	if err != nil {
+		return false, []byte(c.redact(fmt.Sprintf("request error: %v", err)))
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return false, respBody
+	}
+	return true, respBody
+}
+
+// redact masks the credentials of c in s.
+func (c *Client) redact(s string) string {
+	return redact.Text(s, c.secrets...)
+}
+
+func formatResponse(b []byte) string {
+	if len(b) == 0 {
+		return "<empty>"
+	}
+	const maxText = 1000
+	const maxBin = 256
+
+	if utf8.Valid(b) {
+		s := string(b)
+		if len(s) > maxText {
+			return fmt.Sprintf("%s... (truncated, total %d bytes)", s[:maxText], len(b))
+		}
+		return s
+	}
+
+	if len(b) > maxBin {
+		return fmt.Sprintf("<binary %d bytes, prefix hex: %s...>", len(b), hex.EncodeToString(b[:maxBin]))
+	}
+	return fmt.Sprintf("<binary %d bytes, hex: %s>", len(b), hex.EncodeToString(b))
+}
@@ -0,0 +1,425 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package asr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTranscribeRetryExhaustedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("fail"))
+	}))
+	defer server.Close()
+
+	opts := Options{}
+	opts.Endpoint = server.URL
+	opts.TextPath = "text"
+	opts.MaxRetry = 2
+	opts.RetryBaseDelay = 0
+	opts.Timeout = 2 * time.Second
+
+	client, err := New(opts, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	audioPath := tempAudioFile(t, "test")
+
+	_, _, err = client.Transcribe(context.Background(), audioPath)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+
+	var re *RetryExhaustedError
+	if !errors.As(err, &re) {
+		t.Fatalf("expected RetryExhaustedError, got %T: %v", err, err)
+	}
+	if re.Attempts != opts.MaxRetry {
+		t.Fatalf("expected attempts %d, got %d", opts.MaxRetry, re.Attempts)
+	}
+	if re.MaxRetry != opts.MaxRetry {
+		t.Fatalf("expected MaxRetry %d, got %d", opts.MaxRetry, re.MaxRetry)
+	}
+}
+
+func TestExtraConfigNullDeletesBaseField(t *testing.T) {
+	requestChecked := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, fmt.Sprintf("parse multipart form: %v", err), http.StatusBadRequest)
+			return
+		}
+		if _, ok := r.MultipartForm.Value["language"]; ok {
+			http.Error(w, "language field should be deleted", http.StatusBadRequest)
+			return
+		}
+		requestChecked = true
+		_, _ = w.Write([]byte(`{"text":"ok"}`))
+	}))
+	defer server.Close()
+
+	opts := Options{}
+	opts.Endpoint = server.URL
+	opts.Language = "zh"
+	opts.Extra = map[string]interface{}{"language": nil}
+	opts.TextPath = "text"
+	opts.MaxRetry = 1
+	opts.Timeout = 2 * time.Second
+
+	client, err := New(opts, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	audioPath := tempAudioFile(t, "test")
+
+	text, _, err := client.Transcribe(context.Background(), audioPath)
+	if err != nil {
+		t.Fatalf("Transcribe failed: %v", err)
+	}
+	if text != "ok" {
+		t.Fatalf("expected text ok, got %q", text)
+	}
+	if !requestChecked {
+		t.Fatalf("server did not check request")
+	}
+}
+
+func TestTranscribeRejectsEmptyEndpointBeforeOpeningFile(t *testing.T) {
+	opts := Options{}
+	opts.Endpoint = ""
+	client, err := New(opts, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	_, _, err = client.Transcribe(context.Background(), "does-not-need-to-exist.wav")
+	if err == nil || err.Error() != "API endpoint is empty" {
+		t.Fatalf("Transcribe error = %v, want API endpoint is empty", err)
+	}
+}
+
+func TestTranscribeSendsMultipartFieldsAuthAndExtractsConfiguredPath(t *testing.T) {
+	requestChecked := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, fmt.Sprintf("method = %s, want POST", r.Method), http.StatusBadRequest)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer token-123" {
+			http.Error(w, fmt.Sprintf("Authorization = %q", got), http.StatusBadRequest)
+			return
+		}
+		if got := r.Header.Get("User-Agent"); got != "stt-go-client/1.0" {
+			http.Error(w, fmt.Sprintf("User-Agent = %q", got), http.StatusBadRequest)
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, fmt.Sprintf("parse multipart form: %v", err), http.StatusBadRequest)
+			return
+		}
+		checks := map[string]string{
+			"model":       "base",
+			"language":    "en",
+			"prompt":      "hello",
+			"temperature": "0.25",
+			"stream":      "false",
+			"metadata":    `{"tier":"test"}`,
+		}
+		for key, want := range checks {
+			if got := r.FormValue(key); got != want {
+				http.Error(w, fmt.Sprintf("%s = %q, want %q", key, got, want), http.StatusBadRequest)
+				return
+			}
+		}
+		files := r.MultipartForm.File["file"]
+		if len(files) != 1 || files[0].Filename == "" {
+			http.Error(w, "missing uploaded file", http.StatusBadRequest)
+			return
+		}
+		requestChecked = true
+		_, _ = w.Write([]byte(`{"data":{"items":[{"text":"configured path"}]}}`))
+	}))
+	defer server.Close()
+
+	opts := Options{}
+	opts.Endpoint = server.URL
+	opts.Token = "token-123"
+	opts.Model = "base"
+	opts.Language = "en"
+	opts.Prompt = "hello"
+	opts.TextPath = "data.items[0].text"
+	opts.Extra = map[string]interface{}{"temperature": 0.25, "stream": false, "metadata": map[string]interface{}{"tier": "test"}}
+	opts.MaxRetry = 1
+	opts.Timeout = 2 * time.Second
+
+	client, err := New(opts, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	text, raw, err := client.Transcribe(context.Background(), tempAudioFile(t, "audio"))
+	if err != nil {
+		t.Fatalf("Transcribe failed: %v", err)
+	}
+	if text != "configured path" {
+		t.Fatalf("text = %q, want configured path", text)
+	}
+	if string(raw) != `{"data":{"items":[{"text":"configured path"}]}}` {
+		t.Fatalf("raw = %s", raw)
+	}
+	if !requestChecked {
+		t.Fatalf("server did not check request")
+	}
+}
+
+func TestTranscribeAttemptsCountsRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"text":"ok"}`))
+	}))
+	defer server.Close()
+
+	opts := Options{}
+	opts.Endpoint = server.URL
+	opts.TextPath = "text"
+	opts.MaxRetry = 3
+	opts.RetryBaseDelay = 0
+	opts.Timeout = 2 * time.Second
+
+	client, err := New(opts, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	text, _, attempts, err := client.TranscribeAttempts(context.Background(), tempAudioFile(t, "audio"))
+	if err != nil {
+		t.Fatalf("TranscribeAttempts failed: %v", err)
+	}
+	if text != "ok" || attempts != 2 {
+		t.Fatalf("TranscribeAttempts = %q, %d attempts; want ok, 2", text, attempts)
+	}
+}
+
+func TestMaxConcurrentLimitsUploads(t *testing.T) {
+	var mu sync.Mutex
+	active, peak := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		peak = max(peak, active)
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		active--
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"text":"ok"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(Options{Endpoint: server.URL, MaxRetry: 1, MaxConcurrent: 2}, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	path := tempAudioFile(t, "audio")
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := client.Transcribe(context.Background(), path); err != nil {
+				t.Errorf("Transcribe failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if peak != 2 {
+		t.Fatalf("peak concurrent uploads = %d, want 2", peak)
+	}
+
+	// A caller waiting for a slot gives up with its context.
+	client.slots <- struct{}{}
+	client.slots <- struct{}{}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := client.Transcribe(ctx, path); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Transcribe while full = %v, want deadline exceeded", err)
+	}
+}
+
+func TestRequestsPerMinuteSpacesUploads(t *testing.T) {
+	l := newLimiter(30, 1)
+	now := time.Now()
+	for i, want := range []time.Duration{0, 2 * time.Second, 4 * time.Second} {
+		if got := l.reserve(now); got != want {
+			t.Fatalf("reserve %d waits %v, want %v", i, got, want)
+		}
+	}
+	// Tokens do not pile up while idle.
+	later := now.Add(time.Hour)
+	if got, next := l.reserve(later), l.reserve(later); got != 0 || next != 2*time.Second {
+		t.Fatalf("after idle: waits %v then %v, want 0 then 2s", got, next)
+	}
+
+	var mu sync.Mutex
+	var starts []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		starts = append(starts, time.Now())
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"text":"ok"}`))
+	}))
+	defer server.Close()
+	client, err := New(Options{Endpoint: server.URL, MaxRetry: 1, RequestsPerMinute: 1200}, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	path := tempAudioFile(t, "audio")
+	for range 3 {
+		if _, _, err := client.Transcribe(context.Background(), path); err != nil {
+			t.Fatalf("Transcribe failed: %v", err)
+		}
+	}
+	if gap := starts[2].Sub(starts[0]); gap < 90*time.Millisecond {
+		t.Fatalf("3 uploads at 1200/min started within %v, want >= 100ms", gap)
+	}
+
+	// A caller waiting for its turn gives up with its context and hands its
+	// token back, so the next caller waits no longer than it would have.
+	client.limit.reserve(time.Now().Add(time.Second))
+	before := client.limit.next
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := client.Transcribe(ctx, path); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Transcribe while limited = %v, want deadline exceeded", err)
+	}
+	if after := client.limit.next; !after.Equal(before) {
+		t.Fatalf("canceled wait kept its token: next moved by %v", after.Sub(before))
+	}
+}
+
+func TestRequestsBurstStartsAtOnceAfterIdle(t *testing.T) {
+	l := newLimiter(30, 3)
+	now := time.Now()
+	for i, want := range []time.Duration{0, 0, 0, 2 * time.Second, 4 * time.Second} {
+		if got := l.reserve(now); got != want {
+			t.Fatalf("reserve %d waits %v, want %v", i, got, want)
+		}
+	}
+	// The bucket refills one token per interval, up to the burst.
+	later := now.Add(time.Hour)
+	for i, want := range []time.Duration{0, 0, 0, 2 * time.Second} {
+		if got := l.reserve(later); got != want {
+			t.Fatalf("after idle: reserve %d waits %v, want %v", i, got, want)
+		}
+	}
+
+	// A canceled reservation returns its token.
+	l = newLimiter(30, 1)
+	l.reserve(now)
+	if got := l.reserve(now); got != 2*time.Second {
+		t.Fatalf("second reserve waits %v, want 2s", got)
+	}
+	l.cancel()
+	if got := l.reserve(now); got != 2*time.Second {
+		t.Fatalf("reserve after cancel waits %v, want 2s", got)
+	}
+}
+
+func TestUploadProgressReachesRequestSize(t *testing.T) {
+	var length int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		length = r.ContentLength
+		_, _ = w.Write([]byte(`{"text":"ok"}`))
+	}))
+	defer server.Close()
+
+	opts := Options{}
+	opts.Endpoint = server.URL
+	opts.TextPath = "text"
+	client, err := New(opts, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	var sent, total int64
+	ctx := WithUploadProgress(context.Background(), func(s, t int64) { sent, total = s, t })
+	if _, _, err := client.Transcribe(ctx, tempAudioFile(t, "audio")); err != nil {
+		t.Fatalf("Transcribe failed: %v", err)
+	}
+	if total == 0 || sent != total || total != length {
+		t.Fatalf("progress = %d/%d, request length %d", sent, total, length)
+	}
+}
+
+func TestRequestErrorsMaskCredentials(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	client, err := New(Options{Endpoint: server.URL + "/v1?api_key=k-secret-1", Token: "sk-secret-2", MaxRetry: 1}, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	_, raw, _, err := client.TranscribeAttempts(context.Background(), tempAudioFile(t, "test"))
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(string(raw), "api_key=***") || strings.Contains(string(raw), "secret") {
+		t.Fatalf("request error = %q, want the api key masked", raw)
+	}
+	if res := client.Probe(context.Background()); res.Err == nil || strings.Contains(res.Summary(), "secret") {
+		t.Fatalf("probe = %q, want the api key masked", res.Summary())
+	}
+}
+
+func TestFormatResponse(t *testing.T) {
+	if got := formatResponse(nil); got != "<empty>" {
+		t.Fatalf("formatResponse(nil) = %q", got)
+	}
+	if got := formatResponse([]byte("hello")); got != "hello" {
+		t.Fatalf("formatResponse(text) = %q", got)
+	}
+	if got := formatResponse([]byte{0xff, 0x00}); got != "<binary 2 bytes, hex: ff00>" {
+		t.Fatalf("formatResponse(binary) = %q", got)
+	}
+}
+
+func tempAudioFile(t *testing.T, contents string) string {
+	t.Helper()
+	tmp, err := os.CreateTemp(t.TempDir(), "asr-test-*.wav")
+	if err != nil {
+		t.Fatalf("CreateTemp failed: %v", err)
+	}
+	if _, err := tmp.Write([]byte(contents)); err != nil {
+		_ = tmp.Close()
+		t.Fatalf("write temp file failed: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		t.Fatalf("close temp file failed: %v", err)
+	}
+	return tmp.Name()
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package asr
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// LanguageAuto is the LANGUAGE value that requests automatic language detection.
+const LanguageAuto = "auto"
+
+// provider describes how a backend expects automatic language detection.
+// autoValue is sent as the language field; an empty autoValue omits the field.
+type provider struct {
+	autoValue    string
+	hostSuffixes []string
+}
+
+var providers = map[string]provider{
+	"openai":      {autoValue: "", hostSuffixes: []string{"api.openai.com"}},
+	"azure":       {autoValue: "", hostSuffixes: []string{".openai.azure.com", ".cognitiveservices.azure.com"}},
+	"groq":        {autoValue: "", hostSuffixes: []string{"api.groq.com"}},
+	"siliconflow": {autoValue: "", hostSuffixes: []string{"api.siliconflow.cn", "api.siliconflow.com"}},
+	"deepinfra":   {autoValue: "", hostSuffixes: []string{"api.deepinfra.com"}},
+	"whispercpp":  {autoValue: "auto"},
+	"sensevoice":  {autoValue: "auto"},
+	"bcp47":       {autoValue: "und"},
+}
+
+// Providers returns the known PROVIDER names in sorted order.
+func Providers() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DetectProvider guesses the provider from the endpoint host. It returns ""
+// when the host is not recognized.
+func DetectProvider(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, name := range Providers() {
+		for _, suffix := range providers[name].hostSuffixes {
+			if host == strings.TrimPrefix(suffix, ".") || strings.HasSuffix(host, suffix) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// resolveLanguage returns the language field to send. LANGUAGE="auto" is
+// mapped through the provider table; unknown providers omit the field, which
+// is the OpenAI-compatible convention for auto-detection.
+func resolveLanguage(language, providerName, endpoint string) (value string, send bool, err error) {
+	if providerName != "" {
+		if _, ok := providers[providerName]; !ok {
+			return "", false, fmt.Errorf("unknown PROVIDER '%s' (known: %s)", providerName, strings.Join(Providers(), ", "))
+		}
+	}
+	if language == "" {
+		return "", false, nil
+	}
+	if !strings.EqualFold(language, LanguageAuto) {
+		return language, true, nil
+	}
+	if providerName == "" {
+		providerName = DetectProvider(endpoint)
+	}
+	autoValue := providers[providerName].autoValue
+	return autoValue, autoValue != "", nil
+}
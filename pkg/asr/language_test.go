@@ -0,0 +1,60 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package asr
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestResolveLanguageMapsAutoPerProvider(t *testing.T) {
+	cases := []struct {
+		language, provider, endpoint string
+		want                         string
+		send                         bool
+	}{
+		{"", "", "https://api.openai.com/v1/audio/transcriptions", "", false},
+		{"zh", "whispercpp", "http://127.0.0.1:8080/inference", "zh", true},
+		{"auto", "", "https://api.openai.com/v1/audio/transcriptions", "", false},
+		{"AUTO", "", "https://my-res.openai.azure.com/openai/deployments/w/audio/transcriptions", "", false},
+		{"auto", "whispercpp", "http://127.0.0.1:8080/inference", "auto", true},
+		{"auto", "bcp47", "https://example.com/asr", "und", true},
+		{"auto", "", "https://unknown.example.com/asr", "", false},
+	}
+	for _, tc := range cases {
+		got, send, err := resolveLanguage(tc.language, tc.provider, tc.endpoint)
+		if err != nil {
+			t.Fatalf("resolveLanguage(%q, %q) failed: %v", tc.language, tc.provider, err)
+		}
+		if got != tc.want || send != tc.send {
+			t.Fatalf("resolveLanguage(%q, %q, %q) = %q, %v; want %q, %v", tc.language, tc.provider, tc.endpoint, got, send, tc.want, tc.send)
+		}
+	}
+}
+
+func TestDetectProviderByHost(t *testing.T) {
+	if got := DetectProvider("https://api.groq.com/openai/v1/audio/transcriptions"); got != "groq" {
+		t.Fatalf("DetectProvider(groq) = %q", got)
+	}
+	if got := DetectProvider("http://localhost:9000/asr"); got != "" {
+		t.Fatalf("DetectProvider(localhost) = %q, want empty", got)
+	}
+}
+
+func TestNewRejectsUnknownProvider(t *testing.T) {
+	opts := Options{}
+	opts.Provider = "nope"
+	if _, err := New(opts, &http.Client{}); err == nil {
+		t.Fatalf("New succeeded with unknown provider, want error")
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package asr
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"stt/internal/redact"
+)
+
+// probeTimeout caps the startup probe so a dead endpoint does not delay startup
+// for the whole REQUEST_TIMEOUT.
+const probeTimeout = 10 * time.Second
+
+// ProbeResult reports what a lightweight request to the ASR endpoint revealed.
+type ProbeResult struct {
+	Reachable  bool
+	TLS        string // "verified", "skipped (VERIFY_SSL=false)", "invalid: ...", or "" for plain HTTP
+	StatusCode int
+	AuthOK     bool
+	Elapsed    time.Duration
+	Err        error
+}
+
+// OK reports whether the endpoint answered and did not reject the credentials.
+func (p ProbeResult) OK() bool {
+	return p.Reachable && p.AuthOK && !strings.HasPrefix(p.TLS, "invalid")
+}
+
+// Summary returns a single-line human readable description of the probe.
+func (p ProbeResult) Summary() string {
+	if !p.Reachable {
+		if strings.HasPrefix(p.TLS, "invalid") {
+			return fmt.Sprintf("endpoint TLS %s", p.TLS)
+		}
+		return fmt.Sprintf("endpoint unreachable: %v", p.Err)
+	}
+	auth := "accepted"
+	if !p.AuthOK {
+		auth = "rejected"
+	}
+	parts := []string{fmt.Sprintf("reachable (HTTP %d, %v)", p.StatusCode, p.Elapsed.Round(time.Millisecond))}
+	if p.TLS != "" {
+		parts = append(parts, "TLS "+p.TLS)
+	}
+	parts = append(parts, "auth "+auth)
+	return strings.Join(parts, ", ")
+}
+
+// Probe sends a GET without audio to the configured endpoint using the same
+// transport, TLS settings, and Authorization header as real uploads. Any HTTP
+// response counts as reachable; 401 and 403 are treated as rejected credentials.
+func (c *Client) Probe(ctx context.Context) ProbeResult {
+	var res ProbeResult
+	if c.opts.Endpoint == "" {
+		res.Err = fmt.Errorf("API endpoint is empty")
+		return res
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.opts.Endpoint, nil)
+	if err != nil {
+		res.Err = redact.Error(fmt.Errorf("new request error: %w", err), c.secrets...)
+		return res
+	}
+	if c.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.opts.Token)
+	}
+	req.Header.Set("User-Agent", "stt-go-client/1.0")
+
+	client := c.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: probeTimeout}
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	res.Elapsed = time.Since(start)
+	if err != nil {
+		res.Err = redact.Error(err, c.secrets...)
+		var certErr *tls.CertificateVerificationError
+		if errors.As(err, &certErr) {
+			res.TLS = "invalid: " + certErr.Err.Error()
+		}
+		return res
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	res.Reachable = true
+	res.StatusCode = resp.StatusCode
+	res.AuthOK = resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden
+	if resp.TLS != nil {
+		if c.opts.VerifySSL {
+			res.TLS = "verified"
+		} else {
+			res.TLS = "skipped (VERIFY_SSL=false)"
+		}
+	}
+	return res
+}
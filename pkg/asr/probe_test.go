@@ -0,0 +1,92 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package asr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbeReportsAuthStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+
+	opts := Options{}
+	opts.Endpoint = server.URL
+
+	opts.Token = "good"
+	client, err := New(opts, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	res := client.Probe(context.Background())
+	if !res.OK() || res.StatusCode != http.StatusMethodNotAllowed || res.TLS != "" {
+		t.Fatalf("probe with valid token = %+v, want reachable and accepted", res)
+	}
+
+	opts.Token = "bad"
+	client, err = New(opts, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	res = client.Probe(context.Background())
+	if res.OK() || !res.Reachable || res.AuthOK {
+		t.Fatalf("probe with bad token = %+v, want reachable but rejected", res)
+	}
+	if !strings.Contains(res.Summary(), "auth rejected") {
+		t.Fatalf("summary = %q, want auth rejected", res.Summary())
+	}
+}
+
+func TestProbeReportsInvalidCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	opts := Options{}
+	opts.Endpoint = server.URL
+	client, err := New(opts, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	res := client.Probe(context.Background())
+	if res.OK() || !strings.HasPrefix(res.TLS, "invalid") {
+		t.Fatalf("probe = %+v, want invalid TLS", res)
+	}
+}
+
+func TestProbeReportsUnreachableEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	opts := Options{}
+	opts.Endpoint = url
+	client, err := New(opts, &http.Client{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	res := client.Probe(context.Background())
+	if res.Reachable || res.Err == nil || !strings.Contains(res.Summary(), "unreachable") {
+		t.Fatalf("probe = %+v, want unreachable", res)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package asr
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter is a token bucket that holds up to burst tokens and refills one
+// every interval. With a burst of 1, upload requests start at least interval
+// apart and never more than the configured number fall in any minute; a
+// larger burst lets that many start at once after an idle spell.
+type limiter struct {
+	interval time.Duration
+	// slack is how far ahead of now next may run while tokens remain in
+	// the bucket: burst-1 intervals.
+	slack time.Duration
+
+	mu sync.Mutex
+	// next is when the bucket would have a token again if it held only one.
+	next time.Time
+}
+
+// newLimiter returns a limiter for perMinute requests a minute, holding up
+// to burst tokens (at least 1), or nil when perMinute is 0.
+func newLimiter(perMinute, burst int) *limiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	interval := time.Minute / time.Duration(perMinute)
+	return &limiter{interval: interval, slack: time.Duration(max(burst, 1)-1) * interval}
+}
+
+// reserve takes the next token and returns how long after now it becomes
+// available.
+func (l *limiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := max(l.next.Sub(now)-l.slack, 0)
+	l.next = l.next.Add(l.interval)
+	return wait
+}
+
+// cancel gives back a token reserve took but the caller did not use, so
+// the requests queued behind it need not wait for it.
+func (l *limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.next = l.next.Add(-l.interval)
+}
+
+// wait blocks until the caller may send a request, or ctx is done, in which
+// case the token goes back to the bucket.
+func (l *limiter) wait(ctx context.Context) (time.Duration, error) {
+	d := l.reserve(time.Now())
+	if d <= 0 {
+		return 0, nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return d, nil
+	case <-ctx.Done():
+		l.cancel()
+		return d, ctx.Err()
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package ffmpeg
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CheckFormat finds out before the first conversion whether audio can be
+// converted with opts into a file with extension ext: that the ffmpeg in use
+// has the encoder of opts.Codec, and that it can write a short silence in
+// that format and container. The test files are written to tempDir.
+func CheckFormat(ctx context.Context, opts Options, ext, tempDir string) error {
+	settings, err := settingsFor(opts, 16000)
+	if err != nil {
+		return err
+	}
+	if err := checkEncoder(ctx, opts, settings.FFCodec); err != nil {
+		return err
+	}
+	in, err := os.CreateTemp(tempDir, "RecordTemp_check_*.wav")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(in.Name())
+	err = writeSilence(in, settings.SampleRate, settings.Channels)
+	if cerr := in.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	out := strings.TrimSuffix(in.Name(), ".wav") + "." + ext
+	defer os.Remove(out)
+	opts.Start, opts.Length, opts.AudioTrack = 0, 0, 0
+	if err := ConvertContext(ctx, opts, in.Name(), out, settings.SampleRate, nil); err != nil {
+		return fmt.Errorf("cannot convert to %s in a .%s file: %w", opts.Codec, ext, err)
+	}
+	if info, err := os.Stat(out); err != nil || info.Size() == 0 {
+		return fmt.Errorf("cannot convert to %s in a .%s file: no output written", opts.Codec, ext)
+	}
+	return nil
+}
+
+// writeSilence writes a tenth of a second of silence to f as a 16-bit PCM
+// WAV file.
+func writeSilence(f *os.File, rate, channels int) error {
+	size := rate / 10 * channels * 2
+	b := make([]byte, 44+size)
+	le := binary.LittleEndian
+	copy(b[0:], "RIFF")
+	le.PutUint32(b[4:], uint32(36+size))
+	copy(b[8:], "WAVEfmt ")
+	le.PutUint32(b[16:], 16)
+	le.PutUint16(b[20:], 1)
+	le.PutUint16(b[22:], uint16(channels))
+	le.PutUint32(b[24:], uint32(rate))
+	le.PutUint32(b[28:], uint32(rate*channels*2))
+	le.PutUint16(b[32:], uint16(channels*2))
+	le.PutUint16(b[34:], 16)
+	copy(b[36:], "data")
+	le.PutUint32(b[40:], uint32(size))
+	_, err := f.Write(b)
+	return err
+}
+
+// hasEncoder reports whether the output of `ffmpeg -encoders` lists an
+// audio encoder called name.
+func hasEncoder(list, name string) bool {
+	for _, line := range strings.Split(list, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && len(fields[0]) == 6 && fields[0][0] == 'A' && fields[1] == name {
+			return true
+		}
+	}
+	return false
+}
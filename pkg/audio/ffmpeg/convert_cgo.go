@@ -290,6 +290,7 @@ static int stt_ffmpeg_convert(
 	int bitrate_kbps,
 	int codec_has_bitrate,
 	const char *sample_fmt_name,
+	int audio_track,
 	int debug,
 	char *errbuf,
 	int errbuf_size
@@ -319,11 +320,26 @@ static int stt_ffmpeg_convert(
 		stt_set_av_error(errbuf, errbuf_size, "could not read input stream info", ret);
 		goto cleanup;
 	}
-	audio_stream = av_find_best_stream(ifmt_ctx, AVMEDIA_TYPE_AUDIO, -1, -1, NULL, 0);
-	if (audio_stream < 0) {
-		ret = audio_stream;
-		stt_set_av_error(errbuf, errbuf_size, "could not find input audio stream", ret);
-		goto cleanup;
+	if (audio_track > 0) {
+		int n = 0;
+		for (unsigned int i = 0; i < ifmt_ctx->nb_streams; i++) {
+			if (ifmt_ctx->streams[i]->codecpar->codec_type == AVMEDIA_TYPE_AUDIO && ++n == audio_track) {
+				audio_stream = (int)i;
+				break;
+			}
+		}
+		if (audio_stream < 0) {
+			ret = AVERROR_STREAM_NOT_FOUND;
+			stt_set_error(errbuf, errbuf_size, "input has no audio track %d", audio_track);
+			goto cleanup;
+		}
+	} else {
+		audio_stream = av_find_best_stream(ifmt_ctx, AVMEDIA_TYPE_AUDIO, -1, -1, NULL, 0);
+		if (audio_stream < 0) {
+			ret = audio_stream;
+			stt_set_av_error(errbuf, errbuf_size, "could not find input audio stream", ret);
+			goto cleanup;
+		}
 	}
 
 	AVStream *in_stream = ifmt_ctx->streams[audio_stream];
@@ -554,23 +570,110 @@ cleanup:
 	}
 	return ret;
 }
+
+static int64_t stt_ffmpeg_duration(const char *in_path, char *errbuf, int errbuf_size) {
+	AVFormatContext *ifmt_ctx = NULL;
+	int64_t duration;
+
+	av_log_set_level(AV_LOG_ERROR);
+
+	int ret = avformat_open_input(&ifmt_ctx, in_path, NULL, NULL);
+	if (ret < 0) {
+		stt_set_av_error(errbuf, errbuf_size, "could not open input audio", ret);
+		return ret;
+	}
+	ret = avformat_find_stream_info(ifmt_ctx, NULL);
+	if (ret < 0) {
+		stt_set_av_error(errbuf, errbuf_size, "could not read input stream info", ret);
+		avformat_close_input(&ifmt_ctx);
+		return ret;
+	}
+	duration = ifmt_ctx->duration;
+	avformat_close_input(&ifmt_ctx);
+	if (duration == AV_NOPTS_VALUE || duration < 0) {
+		stt_set_error(errbuf, errbuf_size, "input has no known duration");
+		return AVERROR(EINVAL);
+	}
+	return av_rescale(duration, 1000000, AV_TIME_BASE); // microseconds
+}
 */
 import "C"
 
 import (
+	"context"
 	"fmt"
+	"time"
 	"unsafe"
-
-	"stt/internal/config"
 )
 
+// CheckAvailable always succeeds because GUI builds link libav statically.
+func CheckAvailable(opts Options) error {
+	return nil
+}
+
+// checkEncoder leaves the encoders to the test conversion of CheckFormat, as
+// the linked libav is built with those of the supported codecs.
+func checkEncoder(ctx context.Context, opts Options, codec string) error {
+	return nil
+}
+
+// Encoder is not available in builds that link libav: StartEncoder always
+// fails, so recordings are written as WAV and converted afterwards.
+type Encoder struct{}
+
+// StartEncoder reports that this build does not stream recordings.
+func StartEncoder(opts Options, outPath string, rate, channels int) (*Encoder, error) {
+	return nil, fmt.Errorf("recordings are not streamed to the linked libav")
+}
+
+// Path returns "".
+func (e *Encoder) Path() string { return "" }
+
+// Write does nothing.
+func (e *Encoder) Write(samples []int16) error { return nil }
+
+// Close does nothing.
+func (e *Encoder) Close() error { return nil }
+
+// Abort does nothing.
+func (e *Encoder) Abort() {}
+
+// Version reports the linked libav.
+func Version(ctx context.Context, opts Options) (string, error) {
+	return "libav (linked)", nil
+}
+
 // Convert converts input audio into the configured codec/container using the
 // statically linked libav* libraries in GUI builds.
-func Convert(cfg config.Config, inPath, outPath string, rate int) error {
-	settings, err := settingsFor(cfg, rate)
+func Convert(opts Options, inPath, outPath string, rate int) error {
+	return ConvertProgress(opts, inPath, outPath, rate, nil)
+}
+
+// ConvertProgress is Convert for callers that track progress. libav converts
+// in one call, so report, when non-nil, only sees the end.
+func ConvertProgress(opts Options, inPath, outPath string, rate int, report func(float64)) error {
+	return ConvertContext(context.Background(), opts, inPath, outPath, rate, report)
+}
+
+// ConvertContext is ConvertProgress that does not start once ctx is done.
+// libav converts in-process, so opts.Timeout does not apply.
+func ConvertContext(ctx context.Context, opts Options, inPath, outPath string, rate int, report func(float64)) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("ffmpeg canceled: %w", err)
+	}
+	settings, err := settingsFor(opts, rate)
 	if err != nil {
 		return err
 	}
+	if opts.Start > 0 || opts.Length > 0 {
+		return fmt.Errorf("libav convert cannot convert part of '%s'", inPath)
+	}
+	if opts.filter() != "" || len(opts.InputArgs) > 0 || len(opts.ExtraArgs) > 0 {
+		fmt.Printf("[ffmpeg] libav convert ignores audio filters and custom ffmpeg arguments\n")
+	}
+	if len(settings.EncoderArgs) > 0 {
+		fmt.Printf("[ffmpeg] libav convert ignores the threads, compression level and VBR options\n")
+	}
 
 	in := C.CString(inPath)
 	out := C.CString(outPath)
@@ -587,7 +690,7 @@ func Convert(cfg config.Config, inPath, outPath string, rate int) error {
 		codecHasBitrate = 1
 	}
 	debug := 0
-	if cfg.FFMPEG_DEBUG {
+	if opts.Debug {
 		debug = 1
 		fmt.Printf("[ffmpeg] libav convert: %s -> %s codec=%s channels=%d rate=%d bitrate=%dk sample_fmt=%s\n",
 			inPath, outPath, settings.FFCodec, settings.Channels, settings.SampleRate, settings.Bitrate, settings.SampleFormat)
@@ -602,6 +705,7 @@ func Convert(cfg config.Config, inPath, outPath string, rate int) error {
 		C.int(settings.Bitrate),
 		C.int(codecHasBitrate),
 		sampleFormat,
+		C.int(opts.AudioTrack),
 		C.int(debug),
 		&errbuf[0],
 		C.int(len(errbuf)),
@@ -613,5 +717,23 @@ func Convert(cfg config.Config, inPath, outPath string, rate int) error {
 		}
 		return fmt.Errorf("ffmpeg failed: %s", msg)
 	}
+	if report != nil {
+		report(1)
+	}
 	return nil
 }
+
+// Duration returns the length of the audio at path, as libav reports it.
+func Duration(ctx context.Context, opts Options, path string) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("ffmpeg canceled: %w", err)
+	}
+	in := C.CString(path)
+	defer C.free(unsafe.Pointer(in))
+	errbuf := make([]C.char, 4096)
+	us := C.stt_ffmpeg_duration(in, &errbuf[0], C.int(len(errbuf)))
+	if us < 0 {
+		return 0, fmt.Errorf("ffmpeg failed: %s", C.GoString(&errbuf[0]))
+	}
+	return time.Duration(us) * time.Microsecond, nil
+}
@@ -0,0 +1,181 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build !gui_ffmpeg_cgo
+
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// CheckAvailable reports whether an ffmpeg executable can be found. Builds
+// that encode Opus themselves need none for Opus output.
+func CheckAvailable(opts Options) error {
+	if nativeOpusFor(opts) {
+		return nil
+	}
+	_, err := Locate(opts)
+	return err
+}
+
+// checkEncoder reports an error when the ffmpeg executable opts selects
+// lacks the encoder codec, which builds without e.g. libopus or libmp3lame
+// do. Recordings encoded to Opus without ffmpeg need no check.
+func checkEncoder(ctx context.Context, opts Options, codec string) error {
+	if nativeOpusFor(opts) {
+		return nil
+	}
+	bin, err := Locate(opts)
+	if err != nil {
+		return err
+	}
+	out, err := query(ctx, opts, bin, "-nostdin", "-hide_banner", "-encoders")
+	if err != nil {
+		return fmt.Errorf("%s cannot list its encoders: %w", bin, err)
+	}
+	if !hasEncoder(string(out), codec) {
+		return fmt.Errorf("%s has no %s encoder for CODECS %s; install an ffmpeg build with it (e.g. the full build from `winget install Gyan.FFmpeg`) or choose another codec", bin, codec, opts.Codec)
+	}
+	return nil
+}
+
+// Version returns the ffmpeg executable opts selects and the first line of
+// its version output.
+func Version(ctx context.Context, opts Options) (string, error) {
+	bin, err := Locate(opts)
+	if err != nil {
+		return "", err
+	}
+	out, err := query(ctx, opts, bin, "-nostdin", "-version")
+	if err != nil {
+		return "", fmt.Errorf("%s does not run: %w", bin, err)
+	}
+	line, _, _ := strings.Cut(string(out), "\n")
+	return bin + ": " + strings.TrimSpace(line), nil
+}
+
+// query runs bin with args for its output, within opts.Timeout.
+func query(ctx context.Context, opts Options, bin string, args ...string) ([]byte, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, bin, args...)
+	startsProcessGroup(cmd)
+	cmd.Cancel = func() error { return killProcessTree(cmd.Process) }
+	cmd.WaitDelay = 5 * time.Second
+	out, err := cmd.Output()
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded) && opts.Timeout > 0:
+		return nil, fmt.Errorf("timed out after %s", opts.Timeout)
+	case ctx.Err() != nil:
+		return nil, fmt.Errorf("canceled: %w", ctx.Err())
+	}
+	return out, err
+}
+
+// Convert converts input audio into the configured codec/container.
+func Convert(opts Options, inPath, outPath string, rate int) error {
+	return ConvertProgress(opts, inPath, outPath, rate, nil)
+}
+
+// ConvertProgress is Convert that also calls report, when non-nil, with the
+// fraction of the input converted so far. WAV recordings bound for Ogg Opus
+// are encoded without ffmpeg in builds with the opus_cgo tag.
+func ConvertProgress(opts Options, inPath, outPath string, rate int, report func(float64)) error {
+	return ConvertContext(context.Background(), opts, inPath, outPath, rate, report)
+}
+
+// ConvertContext is ConvertProgress that kills ffmpeg, with any processes
+// it started, when ctx is done or opts.Timeout passes.
+func ConvertContext(ctx context.Context, opts Options, inPath, outPath string, rate int, report func(float64)) error {
+	settings, err := settingsFor(opts, rate)
+	if err != nil {
+		return err
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	if ok, err := convertNative(ctx, opts, settings, inPath, outPath, report); ok {
+		return err
+	}
+	bin, err := Locate(opts)
+	if err != nil {
+		return err
+	}
+	args := ffmpegArgsFor(settings, inPath, outPath)
+
+	if opts.Debug {
+		fmt.Printf("[ffmpeg] executing: %s %s\n", bin, strings.Join(args, " "))
+	}
+	var stderr bytes.Buffer
+	var progress *progressParser
+	if report != nil {
+		args = append([]string{"-progress", "pipe:1", "-nostats"}, args...)
+		progress = &progressParser{report: report}
+	}
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Stderr = &stderr
+	if progress != nil {
+		cmd.Stdout = progress
+		cmd.Stderr = progress.stderr(&stderr)
+	}
+	startsProcessGroup(cmd)
+	cmd.Cancel = func() error { return killProcessTree(cmd.Process) }
+	// Children that outlive ffmpeg would otherwise keep its pipes open.
+	cmd.WaitDelay = 5 * time.Second
+	if err := cmd.Run(); err != nil {
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded) && opts.Timeout > 0:
+			return fmt.Errorf("ffmpeg timed out after %s converting '%s'", opts.Timeout, inPath)
+		case ctx.Err() != nil:
+			return fmt.Errorf("ffmpeg canceled: %w", ctx.Err())
+		}
+		return fmt.Errorf("ffmpeg failed: %v\n%s", err, stderr.String())
+	}
+	return nil
+}
+
+// Duration returns the length of the audio at path, as ffmpeg reports it.
+func Duration(ctx context.Context, opts Options, path string) (time.Duration, error) {
+	bin, err := Locate(opts)
+	if err != nil {
+		return 0, err
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	// Without an output file ffmpeg prints the input's details and fails.
+	cmd := exec.CommandContext(ctx, bin, "-nostdin", "-hide_banner", "-i", path)
+	startsProcessGroup(cmd)
+	cmd.Cancel = func() error { return killProcessTree(cmd.Process) }
+	out, _ := cmd.CombinedOutput()
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("ffmpeg canceled: %w", err)
+	}
+	d, ok := parseDuration(string(out))
+	if !ok {
+		return 0, fmt.Errorf("ffmpeg reports no duration for '%s':\n%s", path, out)
+	}
+	return d, nil
+}
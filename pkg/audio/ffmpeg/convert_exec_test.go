@@ -0,0 +1,154 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+//go:build !windows && !gui_ffmpeg_cgo
+
+package ffmpeg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hangingFFmpeg writes a stand-in for ffmpeg that, like a shim, starts a
+// child process and waits for it.
+func hangingFFmpeg(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nsleep 30\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConvertContextKillsHangingFFmpeg(t *testing.T) {
+	opts := Options{Codec: "mp3", Path: hangingFFmpeg(t), Timeout: 200 * time.Millisecond}
+	start := time.Now()
+	err := ConvertContext(context.Background(), opts, "in.wav", "out.mp3", 16000, nil)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("ConvertContext = %v, want a timeout error", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("ConvertContext returned after %s", d)
+	}
+}
+
+func TestConvertContextStopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err := ConvertContext(ctx, Options{Codec: "mp3", Path: hangingFFmpeg(t)}, "in.wav", "out.mp3", 16000, nil)
+	if err == nil || !strings.Contains(err.Error(), "canceled") {
+		t.Fatalf("ConvertContext = %v, want a cancellation error", err)
+	}
+}
+
+// encodersFFmpeg writes a stand-in for ffmpeg whose only audio encoders are
+// pcm_s16le and libmp3lame, whose conversions write a placeholder, and
+// which cannot write Ogg files.
+func encodersFFmpeg(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ffmpeg")
+	script := `#!/bin/sh
+if [ "$3" = "-encoders" ]; then
+	printf 'Encoders:\n A....D = Audio\n ------\n V....D libx264              H.264\n A....D pcm_s16le            PCM signed 16-bit\n A....D libmp3lame           MP3\n'
+	exit 0
+fi
+for a; do out=$a; done
+case "$out" in
+*.ogg) echo "Unable to choose an output format" >&2; exit 1 ;;
+esac
+printf data > "$out"
+`
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckFormatChecksTheEncoder(t *testing.T) {
+	bin := encodersFFmpeg(t)
+	dir := t.TempDir()
+	if err := CheckFormat(context.Background(), Options{Codec: "mp3", Path: bin}, "mp3", dir); err != nil {
+		t.Fatalf("CheckFormat(mp3) = %v", err)
+	}
+	err := CheckFormat(context.Background(), Options{Codec: "aac", Path: bin}, "m4a", dir)
+	if err == nil || !strings.Contains(err.Error(), "no aac encoder") {
+		t.Fatalf("CheckFormat(aac) = %v, want a missing encoder", err)
+	}
+	// A listed encoder can still fail in the container.
+	err = CheckFormat(context.Background(), Options{Codec: "mp3", Path: bin}, "ogg", dir)
+	if err == nil || !strings.Contains(err.Error(), "output format") {
+		t.Fatalf("CheckFormat(mp3 in ogg) = %v, want the conversion error", err)
+	}
+	err = CheckFormat(context.Background(), Options{Codec: "mp3", Path: hangingFFmpeg(t), Timeout: 200 * time.Millisecond}, "mp3", dir)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("CheckFormat with a hanging ffmpeg = %v, want a timeout", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("%d test files left behind", len(entries))
+	}
+}
+
+// catFFmpeg writes a stand-in for ffmpeg that writes its arguments and then
+// its input to the output file.
+func catFFmpeg(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ffmpeg")
+	script := `#!/bin/sh
+for a; do out=$a; done
+echo "$*" > "$out"
+cat >> "$out"
+`
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEncoderPipesPCMIntoFFmpeg(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "rec.mp3")
+	enc, err := StartEncoder(Options{Codec: "mp3", Path: catFFmpeg(t)}, out, 16000, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, samples := range [][]int16{{1, -2}, {0x1234}} {
+		if err := enc.Write(samples); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, pcm, _ := strings.Cut(string(data), "\n")
+	if !strings.Contains(args, "-f s16le -ar 16000 -ac 1 -i pipe:0") || strings.Contains(args, "-nostdin") {
+		t.Errorf("ffmpeg args = %q, want raw PCM from stdin", args)
+	}
+	if want := "\x01\x00\xfe\xff\x34\x12"; pcm != want {
+		t.Errorf("ffmpeg input = %q, want %q", pcm, want)
+	}
+
+	enc, err = StartEncoder(Options{Codec: "mp3", Path: hangingFFmpeg(t)}, out, 16000, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc.Abort()
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("output after Abort: %v, want it removed", err)
+	}
+}
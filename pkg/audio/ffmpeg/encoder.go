@@ -0,0 +1,128 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build !gui_ffmpeg_cgo
+
+package ffmpeg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Encoder converts 16-bit PCM written to it while ffmpeg runs, so that a
+// recording needs no intermediate WAV file.
+type Encoder struct {
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	path    string
+	timeout time.Duration
+	buf     []byte
+	stderr  bytes.Buffer
+}
+
+// StartEncoder starts ffmpeg converting the little-endian 16-bit PCM of rate
+// and channels written to the Encoder into outPath. Recordings bound for
+// Ogg Opus in builds that encode it themselves are not streamed, so they
+// keep their WAV and need no ffmpeg.
+func StartEncoder(opts Options, outPath string, rate, channels int) (*Encoder, error) {
+	if nativeOpusFor(opts) {
+		return nil, errors.New("opus is encoded without ffmpeg in this build")
+	}
+	settings, err := settingsFor(opts, rate)
+	if err != nil {
+		return nil, err
+	}
+	bin, err := Locate(opts)
+	if err != nil {
+		return nil, err
+	}
+	settings.InputArgs = append([]string{"-f", "s16le", "-ar", strconv.Itoa(rate), "-ac", strconv.Itoa(channels)}, settings.InputArgs...)
+	args := ffmpegArgsFor(settings, "pipe:0", outPath)
+	// ffmpegArgsFor keeps ffmpeg off stdin, which carries the audio here.
+	args = args[1:]
+	if opts.Debug {
+		fmt.Printf("[ffmpeg] executing: %s %s\n", bin, strings.Join(args, " "))
+	}
+	e := &Encoder{path: outPath, timeout: opts.Timeout}
+	e.cmd = exec.Command(bin, args...)
+	e.cmd.Stderr = &e.stderr
+	startsProcessGroup(e.cmd)
+	// Children that outlive ffmpeg would otherwise keep its pipes open.
+	e.cmd.WaitDelay = 5 * time.Second
+	if e.stdin, err = e.cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+	if err := e.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("ffmpeg failed to start: %w", err)
+	}
+	return e, nil
+}
+
+// Path returns the file the Encoder writes.
+func (e *Encoder) Path() string {
+	return e.path
+}
+
+// Write passes samples to ffmpeg, blocking while it catches up.
+func (e *Encoder) Write(samples []int16) error {
+	n := 2 * len(samples)
+	if cap(e.buf) < n {
+		e.buf = make([]byte, n)
+	}
+	b := e.buf[:n]
+	for i, v := range samples {
+		binary.LittleEndian.PutUint16(b[2*i:], uint16(v))
+	}
+	if _, err := e.stdin.Write(b); err != nil {
+		return fmt.Errorf("ffmpeg stopped taking audio: %w", err)
+	}
+	return nil
+}
+
+// Close ends the audio and waits, at most the timeout of its Options, for
+// ffmpeg to finish the file. The file is removed when ffmpeg fails.
+func (e *Encoder) Close() error {
+	_ = e.stdin.Close()
+	var timedOut atomic.Bool
+	if e.timeout > 0 {
+		timer := time.AfterFunc(e.timeout, func() {
+			timedOut.Store(true)
+			_ = killProcessTree(e.cmd.Process)
+		})
+		defer timer.Stop()
+	}
+	if err := e.cmd.Wait(); err != nil {
+		_ = os.Remove(e.path)
+		if timedOut.Load() {
+			return fmt.Errorf("ffmpeg timed out after %s writing '%s'", e.timeout, e.path)
+		}
+		return fmt.Errorf("ffmpeg failed: %v\n%s", err, e.stderr.String())
+	}
+	return nil
+}
+
+// Abort stops ffmpeg and removes what it wrote.
+func (e *Encoder) Abort() {
+	_ = killProcessTree(e.cmd.Process)
+	_ = e.stdin.Close()
+	_ = e.cmd.Wait()
+	_ = os.Remove(e.path)
+}
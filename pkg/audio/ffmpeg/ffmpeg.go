@@ -0,0 +1,395 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package ffmpeg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options selects the output format of a conversion and the ffmpeg
+// executable used for it. Zero values fall back to mono, the rate passed to
+// Convert, 128 kbps and 16-bit samples.
+type Options struct {
+	// Codec is the output codec, e.g. "mp3", "opus", "aac" or "flac".
+	Codec      string
+	Channels   int
+	SampleRate int
+	// Bitrate is in kbps and only applies to lossy codecs.
+	Bitrate int
+	// Depth picks the sample format, in bits, of non-PCM codecs.
+	Depth int
+	// Path is an explicit ffmpeg executable; empty searches for one.
+	Path string
+	// Debug logs the conversion command to stdout.
+	Debug bool
+	// Timeout bounds a conversion by an ffmpeg executable, which is killed
+	// with its child processes when it runs longer; 0 means no limit.
+	Timeout time.Duration
+	// Highpass and Lowpass, in Hz, cut the audio below and above that
+	// frequency; 0 leaves it. Denoise runs ffmpeg's afftdn filter.
+	Highpass int
+	Lowpass  int
+	Denoise  bool
+	// Speed plays the audio faster by that factor, keeping the pitch, so
+	// services billed by duration charge less; 0 and 1 leave it.
+	Speed float64
+	// AudioTrack picks the input's audio stream, counting from 1, e.g. the
+	// second language of a video; 0 lets ffmpeg pick one.
+	AudioTrack int
+	// Start and Length select the part of the input to convert; a zero
+	// Length converts to the end.
+	Start  time.Duration
+	Length time.Duration
+	// InputArgs are passed to ffmpeg before -i, ExtraArgs right before the
+	// output file, where they override the options STT sets.
+	InputArgs []string
+	ExtraArgs []string
+	// Threads sets the threads of ffmpeg's encoder; 0 lets ffmpeg choose.
+	Threads int
+	// CompressionLevel trades the speed of the Opus and FLAC encoders for
+	// smaller files; DefaultCompression keeps the encoder's default.
+	CompressionLevel int
+	// VBR is the Opus bitrate mode: "on", "off" or "constrained"; empty
+	// keeps the encoder's default.
+	VBR string
+}
+
+// DefaultCompression is the CompressionLevel that keeps the encoder's
+// default.
+const DefaultCompression = -1
+
+type conversionSettings struct {
+	CodecKey        string
+	FFCodec         string
+	CodecHasBitrate bool
+	Channels        int
+	SampleRate      int
+	Bitrate         int
+	Depth           int
+	SampleFormat    string
+	Filter          string
+	AudioTrack      int
+	Start           time.Duration
+	Length          time.Duration
+	InputArgs       []string
+	ExtraArgs       []string
+	EncoderArgs     []string
+}
+
+func settingsFor(opts Options, rate int) (conversionSettings, error) {
+	codecKey := strings.ToLower(opts.Codec)
+	channels := opts.Channels
+	if channels <= 0 {
+		channels = 1
+	}
+	sr := opts.SampleRate
+	if sr <= 0 {
+		sr = rate
+	}
+	bitrate := opts.Bitrate
+	if bitrate <= 0 {
+		bitrate = 128
+	}
+	depth := opts.Depth
+	if depth == 0 {
+		depth = 16
+	}
+
+	ffCodec, codecHasBitrate := ffmpegCodecFor(codecKey)
+	if ffCodec == "" {
+		return conversionSettings{}, fmt.Errorf("unsupported codec: %s", opts.Codec)
+	}
+
+	settings := conversionSettings{
+		CodecKey:        codecKey,
+		FFCodec:         ffCodec,
+		CodecHasBitrate: codecHasBitrate,
+		Channels:        channels,
+		SampleRate:      sr,
+		Bitrate:         bitrate,
+		Depth:           depth,
+		Filter:          opts.filter(),
+		AudioTrack:      opts.AudioTrack,
+		Start:           opts.Start,
+		Length:          opts.Length,
+		InputArgs:       opts.InputArgs,
+		ExtraArgs:       opts.ExtraArgs,
+	}
+	if !strings.HasPrefix(ffCodec, "pcm_") {
+		settings.SampleFormat = sampleFormatForDepth(depth)
+	}
+	settings.EncoderArgs = opts.encoderArgs(ffCodec)
+	return settings, nil
+}
+
+// encoderArgs returns the ffmpeg options of Threads, CompressionLevel and
+// VBR that apply to ffCodec.
+func (opts Options) encoderArgs(ffCodec string) []string {
+	var args []string
+	if opts.Threads > 0 {
+		args = append(args, "-threads", strconv.Itoa(opts.Threads))
+	}
+	if opts.CompressionLevel >= 0 && (ffCodec == "libopus" || ffCodec == "flac") {
+		args = append(args, "-compression_level", strconv.Itoa(opts.CompressionLevel))
+	}
+	if opts.VBR != "" && ffCodec == "libopus" {
+		args = append(args, "-vbr", strings.ToLower(opts.VBR))
+	}
+	return args
+}
+
+// ffmpegArgsFor returns the arguments of a conversion. -nostdin keeps
+// ffmpeg from ever waiting for keyboard input, and -vn and -sn leave out
+// the video and subtitles of video files and the cover art of music.
+func ffmpegArgsFor(settings conversionSettings, inPath, outPath string) []string {
+	args := []string{"-nostdin", "-y"}
+	if settings.Start > 0 {
+		args = append(args, "-ss", seconds(settings.Start))
+	}
+	if settings.Length > 0 {
+		args = append(args, "-t", seconds(settings.Length))
+	}
+	args = append(args, settings.InputArgs...)
+	args = append(args, "-i", inPath)
+	if settings.AudioTrack > 0 {
+		args = append(args, "-map", fmt.Sprintf("0:a:%d", settings.AudioTrack-1))
+	}
+	args = append(args, "-vn", "-sn", "-ac", strconv.Itoa(settings.Channels), "-ar", strconv.Itoa(settings.SampleRate), "-c:a", settings.FFCodec)
+	if !strings.HasPrefix(settings.FFCodec, "pcm_") {
+		if settings.CodecHasBitrate {
+			args = append(args, "-b:a", fmt.Sprintf("%dk", settings.Bitrate))
+		}
+		if settings.SampleFormat != "" {
+			args = append(args, "-sample_fmt", settings.SampleFormat)
+		}
+	}
+	args = append(args, settings.EncoderArgs...)
+	if settings.Filter != "" {
+		args = append(args, "-af", settings.Filter)
+	}
+	args = append(args, settings.ExtraArgs...)
+	return append(args, outPath)
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+// filter returns the ffmpeg audio filter chain of the cleanup and speed
+// options, or "". Denoising comes after the band filters so it works on the
+// band that is kept, and the speed-up last so the filters see the original
+// audio.
+func (opts Options) filter() string {
+	var filters []string
+	if opts.Highpass > 0 {
+		filters = append(filters, fmt.Sprintf("highpass=f=%d", opts.Highpass))
+	}
+	if opts.Lowpass > 0 {
+		filters = append(filters, fmt.Sprintf("lowpass=f=%d", opts.Lowpass))
+	}
+	if opts.Denoise {
+		filters = append(filters, "afftdn")
+	}
+	if opts.Speed > 0 && opts.Speed != 1 {
+		filters = append(filters, "atempo="+strconv.FormatFloat(opts.Speed, 'f', -1, 64))
+	}
+	return strings.Join(filters, ",")
+}
+
+// needsExecutable reports whether opts asks for what only the ffmpeg
+// executable does: filters, a speed-up, another audio track, part of the
+// input or custom arguments. Such conversions are neither skipped nor
+// encoded natively.
+func (opts Options) needsExecutable() bool {
+	return opts.filter() != "" || opts.AudioTrack > 1 || opts.Start > 0 || opts.Length > 0 || len(opts.InputArgs) > 0 || len(opts.ExtraArgs) > 0
+}
+
+// SplitArgs splits s into arguments at whitespace. Double or single quotes
+// group an argument with spaces and are removed; backslashes are literal, so
+// Windows paths need no escaping.
+func SplitArgs(s string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	var quote rune
+	inArg := false
+	for _, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(c)
+			}
+		case c == '"' || c == '\'':
+			quote, inArg = c, true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(c)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in '%s'", quote, s)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+func sampleFormatForDepth(depth int) string {
+	switch depth {
+	case 8:
+		return "u8"
+	case 16:
+		return "s16"
+	case 24:
+		return "s24"
+	case 32:
+		return "s32"
+	default:
+		return ""
+	}
+}
+
+func ffmpegCodecFor(key string) (string, bool) {
+	k := strings.ToLower(key)
+	switch k {
+	case "opus", "libopus":
+		return "libopus", true
+	case "wavpack":
+		return "wavpack", false
+	case "aac":
+		return "aac", true
+	case "ac3":
+		return "ac3", true
+	case "eac3":
+		return "eac3", true
+	case "mp3":
+		return "libmp3lame", true
+	case "mp2":
+		return "mp2", true
+	case "mp1":
+		return "mp1", true
+	case "flac":
+		return "flac", false
+	case "alac":
+		return "alac", false
+	case "pcm":
+		return "pcm_s16le", false
+	case "vorbis", "libvorbis", "vorb":
+		return "libvorbis", true
+	case "adpcm":
+		return "adpcm_ms", false
+	case "amr":
+		return "libopencore_amrnb", true
+	case "pcm_f32be", "pcm_f32le", "pcm_f64be", "pcm_f64le",
+		"pcm_s16be", "pcm_s16le", "pcm_s24be", "pcm_s24le",
+		"pcm_s32be", "pcm_s32le", "pcm_s64be", "pcm_s64le",
+		"pcm_s8":
+		return k, false
+	default:
+		return "", false
+	}
+}
+
+// durationPattern matches the input duration in ffmpeg's banner.
+var durationPattern = regexp.MustCompile(`Duration: (\d+):(\d{2}):(\d{2}(?:\.\d+)?)`)
+
+// progressParser turns the key=value lines ffmpeg writes with -progress into
+// the fraction of the input converted, using the input duration ffmpeg prints
+// on stderr before converting.
+type progressParser struct {
+	report func(float64)
+
+	mu    sync.Mutex
+	total time.Duration
+	head  bytes.Buffer
+	line  []byte
+}
+
+// Write consumes -progress output.
+func (p *progressParser) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.line = append(p.line, b...)
+	for {
+		i := bytes.IndexByte(p.line, '\n')
+		if i < 0 {
+			break
+		}
+		p.parseLine(strings.TrimSpace(string(p.line[:i])))
+		p.line = p.line[i+1:]
+	}
+	return len(b), nil
+}
+
+func (p *progressParser) parseLine(line string) {
+	key, value, _ := strings.Cut(line, "=")
+	switch key {
+	case "out_time_us", "out_time_ms": // both are microseconds
+		us, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || p.total <= 0 || us < 0 {
+			return
+		}
+		p.report(min(1, float64(us)/float64(p.total.Microseconds())))
+	case "progress":
+		if value == "end" {
+			p.report(1)
+		}
+	}
+}
+
+// stderr returns a writer for ffmpeg's stderr that copies it to w and picks
+// up the input duration from the banner.
+func (p *progressParser) stderr(w io.Writer) io.Writer {
+	return writerFunc(func(b []byte) (int, error) {
+		p.mu.Lock()
+		if p.total == 0 && p.head.Len() < 64<<10 {
+			p.head.Write(b)
+			if d, ok := parseDuration(p.head.String()); ok {
+				p.total = d
+				p.head.Reset()
+			}
+		}
+		p.mu.Unlock()
+		return w.Write(b)
+	})
+}
+
+// parseDuration returns the input duration in ffmpeg's banner s.
+func parseDuration(s string) (time.Duration, bool) {
+	m := durationPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	h, _ := strconv.Atoi(m[1])
+	mins, _ := strconv.Atoi(m[2])
+	sec, _ := strconv.ParseFloat(m[3], 64)
+	return time.Duration(h)*time.Hour + time.Duration(mins)*time.Minute + time.Duration(sec*float64(time.Second)), true
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(b []byte) (int, error) { return f(b) }
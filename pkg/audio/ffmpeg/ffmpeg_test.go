@@ -0,0 +1,231 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package ffmpeg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSettingsForAppliesDefaultsAndMapsCodec(t *testing.T) {
+	opts := Options{Codec: "MP3"}
+	settings, err := settingsFor(opts, 44100)
+	if err != nil {
+		t.Fatalf("settingsFor failed: %v", err)
+	}
+
+	if settings.CodecKey != "mp3" || settings.FFCodec != "libmp3lame" || !settings.CodecHasBitrate {
+		t.Fatalf("unexpected codec settings: %#v", settings)
+	}
+	if settings.Channels != 1 || settings.SampleRate != 44100 || settings.Bitrate != 128 || settings.Depth != 16 || settings.SampleFormat != "s16" {
+		t.Fatalf("unexpected defaults: %#v", settings)
+	}
+}
+
+func TestSettingsForRejectsUnsupportedCodec(t *testing.T) {
+	_, err := settingsFor(Options{Codec: "not-real"}, 16000)
+	if err == nil {
+		t.Fatalf("settingsFor succeeded, want unsupported codec error")
+	}
+}
+
+func TestFFmpegArgsForBitrateCodec(t *testing.T) {
+	settings := conversionSettings{
+		FFCodec:         "libopus",
+		CodecHasBitrate: true,
+		Channels:        2,
+		SampleRate:      48000,
+		Bitrate:         64,
+		SampleFormat:    "s16",
+	}
+	got := ffmpegArgsFor(settings, "in.wav", "out.ogg")
+	want := []string{"-nostdin", "-y", "-i", "in.wav", "-vn", "-sn", "-ac", "2", "-ar", "48000", "-c:a", "libopus", "-b:a", "64k", "-sample_fmt", "s16", "out.ogg"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ffmpegArgsFor = %#v, want %#v", got, want)
+	}
+}
+
+func TestFFmpegArgsForPCMCodecOmitsBitrateAndSampleFormat(t *testing.T) {
+	settings := conversionSettings{
+		FFCodec:         "pcm_s16le",
+		CodecHasBitrate: true,
+		Channels:        1,
+		SampleRate:      16000,
+		Bitrate:         64,
+		SampleFormat:    "s16",
+	}
+	got := ffmpegArgsFor(settings, "in.wav", "out.wav")
+	want := []string{"-nostdin", "-y", "-i", "in.wav", "-vn", "-sn", "-ac", "1", "-ar", "16000", "-c:a", "pcm_s16le", "out.wav"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ffmpegArgsFor = %#v, want %#v", got, want)
+	}
+}
+
+func TestSampleFormatForDepth(t *testing.T) {
+	tests := map[int]string{8: "u8", 16: "s16", 24: "s24", 32: "s32", 12: ""}
+	for depth, want := range tests {
+		if got := sampleFormatForDepth(depth); got != want {
+			t.Fatalf("sampleFormatForDepth(%d) = %q, want %q", depth, got, want)
+		}
+	}
+}
+
+func TestFFmpegCodecFor(t *testing.T) {
+	tests := []struct {
+		key     string
+		codec   string
+		bitrate bool
+	}{
+		{key: "opus", codec: "libopus", bitrate: true},
+		{key: "VORB", codec: "libvorbis", bitrate: true},
+		{key: "flac", codec: "flac", bitrate: false},
+		{key: "pcm_s24le", codec: "pcm_s24le", bitrate: false},
+		{key: "unknown", codec: "", bitrate: false},
+	}
+	for _, tt := range tests {
+		codec, bitrate := ffmpegCodecFor(tt.key)
+		if codec != tt.codec || bitrate != tt.bitrate {
+			t.Fatalf("ffmpegCodecFor(%q) = (%q, %v), want (%q, %v)", tt.key, codec, bitrate, tt.codec, tt.bitrate)
+		}
+	}
+}
+
+func TestProgressParserReportsFractionOfInputDuration(t *testing.T) {
+	var got []float64
+	p := &progressParser{report: func(f float64) { got = append(got, f) }}
+	var stderr strings.Builder
+	w := p.stderr(&stderr)
+
+	// Progress before the banner has no duration to compare against.
+	_, _ = p.Write([]byte("out_time_us=1000000\n"))
+	_, _ = w.Write([]byte("Input #0, wav, from 'in.wav':\n  Duration: 00:00:"))
+	_, _ = w.Write([]byte("10.00, bitrate: 256 kb/s\n"))
+	_, _ = p.Write([]byte("out_time_us=2500000\nprogress=continue\nout_time"))
+	_, _ = p.Write([]byte("_us=12000000\nprogress=end\n"))
+
+	if want := []float64{0.25, 1, 1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("reports = %v, want %v", got, want)
+	}
+	if !strings.Contains(stderr.String(), "Duration: 00:00:10.00") {
+		t.Fatalf("stderr not passed through: %q", stderr.String())
+	}
+}
+
+func TestFFmpegArgsForPlacesCustomArgs(t *testing.T) {
+	settings := conversionSettings{
+		FFCodec:    "pcm_s16le",
+		Channels:   1,
+		SampleRate: 16000,
+		InputArgs:  []string{"-ss", "5"},
+		ExtraArgs:  []string{"-af", "highpass=f=200"},
+	}
+	got := ffmpegArgsFor(settings, "in.wav", "out.wav")
+	want := []string{"-nostdin", "-y", "-ss", "5", "-i", "in.wav", "-vn", "-sn", "-ac", "1", "-ar", "16000", "-c:a", "pcm_s16le", "-af", "highpass=f=200", "out.wav"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ffmpegArgsFor = %#v, want %#v", got, want)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := map[string][]string{
+		"":                                   nil,
+		"  -ss 5  ":                          {"-ss", "5"},
+		`-af "highpass=f=200, lowpass=f=3k"`: {"-af", "highpass=f=200, lowpass=f=3k"},
+		`-i 'C:\My Files\x.wav' ""`:          {"-i", `C:\My Files\x.wav`, ""},
+		`a"b c"d`:                            {"ab cd"},
+	}
+	for in, want := range tests {
+		got, err := SplitArgs(in)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("SplitArgs(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := SplitArgs(`-af "highpass`); err == nil {
+		t.Errorf("SplitArgs accepted an unterminated quote")
+	}
+}
+
+func TestSettingsForBuildsCleanupFilter(t *testing.T) {
+	settings, err := settingsFor(Options{Codec: "opus", Highpass: 100, Lowpass: 8000, Denoise: true}, 16000)
+	if err != nil {
+		t.Fatalf("settingsFor failed: %v", err)
+	}
+	if settings.Filter != "highpass=f=100,lowpass=f=8000,afftdn" {
+		t.Fatalf("filter = %q", settings.Filter)
+	}
+	args := ffmpegArgsFor(settings, "in.wav", "out.ogg")
+	if n := len(args); args[n-3] != "-af" || args[n-2] != settings.Filter {
+		t.Fatalf("ffmpegArgsFor = %q, want the filter before the output", args)
+	}
+	if f := (Options{Denoise: true}).filter(); f != "afftdn" {
+		t.Fatalf("denoise-only filter = %q", f)
+	}
+	if f := (Options{Denoise: true, Speed: 1.5}).filter(); f != "afftdn,atempo=1.5" {
+		t.Fatalf("filter with speed = %q, want atempo last", f)
+	}
+	if f := (Options{Speed: 1}).filter(); f != "" {
+		t.Fatalf("filter at normal speed = %q, want none", f)
+	}
+}
+
+func TestSettingsForTunesTheEncoder(t *testing.T) {
+	tests := []struct {
+		opts Options
+		want []string
+	}{
+		{Options{Codec: "flac", Threads: 4, CompressionLevel: 0, VBR: "on"}, []string{"-threads", "4", "-compression_level", "0"}},
+		{Options{Codec: "opus", CompressionLevel: DefaultCompression, VBR: "Constrained"}, []string{"-vbr", "constrained"}},
+		{Options{Codec: "mp3", CompressionLevel: 5}, nil},
+	}
+	for _, tt := range tests {
+		settings, err := settingsFor(tt.opts, 16000)
+		if err != nil {
+			t.Fatalf("settingsFor(%+v) failed: %v", tt.opts, err)
+		}
+		if !reflect.DeepEqual(settings.EncoderArgs, tt.want) {
+			t.Errorf("settingsFor(%+v).EncoderArgs = %q, want %q", tt.opts, settings.EncoderArgs, tt.want)
+		}
+	}
+}
+
+func TestFFmpegArgsForSelectsPartOfTheInput(t *testing.T) {
+	settings, err := settingsFor(Options{Codec: "opus", Start: 595 * time.Second, Length: 90500 * time.Millisecond}, 16000)
+	if err != nil {
+		t.Fatalf("settingsFor failed: %v", err)
+	}
+	args := ffmpegArgsFor(settings, "in.wav", "out.ogg")
+	want := []string{"-nostdin", "-y", "-ss", "595", "-t", "90.5", "-i", "in.wav"}
+	if !reflect.DeepEqual(args[:len(want)], want) {
+		t.Fatalf("ffmpegArgsFor = %q, want prefix %q", args, want)
+	}
+	if d, ok := parseDuration("  Duration: 01:02:03.50, start: 0.000000, bitrate: 64 kb/s"); !ok || d != time.Hour+2*time.Minute+3500*time.Millisecond {
+		t.Fatalf("parseDuration = %s, %v", d, ok)
+	}
+}
+
+func TestFFmpegArgsForMapsAudioTrack(t *testing.T) {
+	settings, err := settingsFor(Options{Codec: "pcm", AudioTrack: 2}, 16000)
+	if err != nil {
+		t.Fatalf("settingsFor failed: %v", err)
+	}
+	args := ffmpegArgsFor(settings, "meeting.mkv", "out.wav")
+	want := []string{"-nostdin", "-y", "-i", "meeting.mkv", "-map", "0:a:1", "-vn", "-sn", "-ac", "1", "-ar", "16000", "-c:a", "pcm_s16le", "out.wav"}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("ffmpegArgsFor = %q, want %q", args, want)
+	}
+	if !(Options{AudioTrack: 2}).needsExecutable() || (Options{AudioTrack: 1}).needsExecutable() {
+		t.Fatalf("only tracks after the first need the ffmpeg executable")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package ffmpeg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// InstallHint is printed when no ffmpeg executable can be found.
+const InstallHint = "ffmpeg not found. Install it (e.g. `winget install Gyan.FFmpeg`, `scoop install ffmpeg`, or download from https://ffmpeg.org/download.html), " +
+	"then add it to PATH, place ffmpeg.exe next to stt.exe, or set FFMPEG_PATH / -ffmpeg-path."
+
+// Locate returns the ffmpeg executable to use. An explicit opts.Path must
+// exist; otherwise the FFMPEG_PATH environment variable, PATH, the
+// executable's directory, and common install locations are searched in that
+// order.
+func Locate(opts Options) (string, error) {
+	if opts.Path != "" {
+		if isFile(opts.Path) {
+			return opts.Path, nil
+		}
+		return "", fmt.Errorf("FFMPEG_PATH '%s' does not exist or is not a file", opts.Path)
+	}
+	if env := os.Getenv("FFMPEG_PATH"); env != "" && isFile(env) {
+		return env, nil
+	}
+	if p, err := exec.LookPath("ffmpeg"); err == nil {
+		return p, nil
+	}
+	for _, dir := range searchDirs() {
+		if dir == "" {
+			continue
+		}
+		p := filepath.Join(dir, binaryName())
+		if isFile(p) {
+			return p, nil
+		}
+	}
+	return "", errors.New(InstallHint)
+}
+
+func searchDirs() []string {
+	var dirs []string
+	if exe, err := os.Executable(); err == nil {
+		exeDir := filepath.Dir(exe)
+		dirs = append(dirs, exeDir, filepath.Join(exeDir, "ffmpeg", "bin"))
+	}
+	if runtime.GOOS == "windows" {
+		programFiles := os.Getenv("ProgramFiles")
+		localAppData := os.Getenv("LOCALAPPDATA")
+		userProfile := os.Getenv("USERPROFILE")
+		programData := os.Getenv("ProgramData")
+		dirs = append(dirs,
+			`C:\ffmpeg\bin`,
+			joinIf(programFiles, "ffmpeg", "bin"),
+			joinIf(localAppData, "Microsoft", "WinGet", "Links"),
+			joinIf(userProfile, "scoop", "shims"),
+			joinIf(programData, "chocolatey", "bin"),
+		)
+		return dirs
+	}
+	return append(dirs, "/usr/local/bin", "/usr/bin", "/opt/homebrew/bin", "/snap/bin")
+}
+
+func joinIf(base string, elem ...string) string {
+	if base == "" {
+		return ""
+	}
+	return filepath.Join(append([]string{base}, elem...)...)
+}
+
+func binaryName() string {
+	if runtime.GOOS == "windows" {
+		return "ffmpeg.exe"
+	}
+	return "ffmpeg"
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
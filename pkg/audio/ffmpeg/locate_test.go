@@ -0,0 +1,57 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package ffmpeg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocatePrefersConfiguredPath(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "ffmpeg-custom")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	got, err := Locate(Options{Path: bin})
+	if err != nil {
+		t.Fatalf("Locate failed: %v", err)
+	}
+	if got != bin {
+		t.Fatalf("Locate = %q, want %q", got, bin)
+	}
+}
+
+func TestLocateRejectsMissingConfiguredPath(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing-ffmpeg")
+	_, err := Locate(Options{Path: missing})
+	if err == nil || !strings.Contains(err.Error(), "FFMPEG_PATH") {
+		t.Fatalf("Locate error = %v, want FFMPEG_PATH error", err)
+	}
+}
+
+func TestLocateUsesFFMPEGPathEnvironment(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "ffmpeg-env")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	t.Setenv("FFMPEG_PATH", bin)
+	got, err := Locate(Options{})
+	if err != nil {
+		t.Fatalf("Locate failed: %v", err)
+	}
+	if got != bin {
+		t.Fatalf("Locate = %q, want %q", got, bin)
+	}
+}
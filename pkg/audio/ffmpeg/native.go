@@ -0,0 +1,206 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package ffmpeg
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+)
+
+// nativeOpusRates are the input rates libopus encodes without resampling.
+var nativeOpusRates = []int{8000, 12000, 16000, 24000, 48000}
+
+// opusFrameMs is the duration of one Opus frame.
+const opusFrameMs = 20
+
+// nativeOpusFor reports whether this build can encode to opts without
+// ffmpeg, given a WAV input at the output rate. Ogg Opus output is what the
+// default CODECS and CONTAINER produce.
+func nativeOpusFor(opts Options) bool {
+	if !nativeOpus || opts.needsExecutable() {
+		return false
+	}
+	codec, _ := ffmpegCodecFor(opts.Codec)
+	return codec == "libopus" && opts.Channels <= 2 &&
+		(opts.SampleRate <= 0 || slices.Contains(nativeOpusRates, opts.SampleRate))
+}
+
+// convertNative encodes a PCM WAV into Ogg Opus with the linked libopus.
+// It reports false, without an error, when the conversion needs ffmpeg:
+// other codecs or containers, filters or custom arguments, inputs that are
+// not 16- to 32-bit PCM WAV, or ones that would have to be resampled.
+func convertNative(ctx context.Context, opts Options, settings conversionSettings, inPath, outPath string, report func(float64)) (bool, error) {
+	if !nativeOpus || opts.needsExecutable() || settings.FFCodec != "libopus" || settings.Channels > 2 {
+		return false, nil
+	}
+	switch strings.ToLower(filepath.Ext(outPath)) {
+	case ".ogg", ".oga", ".opus":
+	default:
+		return false, nil
+	}
+	in, err := os.Open(inPath)
+	if err != nil {
+		return true, err
+	}
+	defer in.Close()
+	dec := wav.NewDecoder(in)
+	if !dec.IsValidFile() || dec.WavAudioFormat != 1 || dec.BitDepth < 16 ||
+		int(dec.SampleRate) != settings.SampleRate || dec.NumChans > 2 ||
+		!slices.Contains(nativeOpusRates, settings.SampleRate) {
+		return false, nil
+	}
+	if opts.Debug {
+		fmt.Printf("[ffmpeg] native opus encode: %s -> %s channels=%d rate=%d bitrate=%dk\n",
+			inPath, outPath, settings.Channels, settings.SampleRate, settings.Bitrate)
+	}
+	if err := encodeOggOpus(ctx, dec, opts, settings, outPath, report); err != nil {
+		_ = os.Remove(outPath)
+		return true, fmt.Errorf("opus encoding failed: %w", err)
+	}
+	return true, nil
+}
+
+// encodeOggOpus encodes the PCM of dec into an Ogg Opus file at outPath.
+// The CompressionLevel and VBR of opts tune libopus as they do ffmpeg's.
+func encodeOggOpus(ctx context.Context, dec *wav.Decoder, opts Options, settings conversionSettings, outPath string, report func(float64)) error {
+	rate, channels := settings.SampleRate, settings.Channels
+	enc, err := newOpusEncoder(rate, channels, settings.Bitrate*1000, opts.CompressionLevel, opusVBRMode(opts.VBR))
+	if err != nil {
+		return err
+	}
+	defer enc.close()
+
+	f, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	bw := bufio.NewWriter(f)
+	ogg := &oggWriter{w: bw, serial: rand.Uint32()}
+
+	// Granule positions count 48 kHz samples from the start of the stream,
+	// including the encoder delay the decoder skips.
+	scale := int64(48000 / rate)
+	preSkip := enc.lookahead() * int(scale)
+	if err := ogg.writePacket(opusHead(channels, preSkip, rate), 0); err != nil {
+		return err
+	}
+	if err := ogg.flush(false); err != nil {
+		return err
+	}
+	if err := ogg.writePacket(opusTags(opusVendor()), 0); err != nil {
+		return err
+	}
+	if err := ogg.flush(false); err != nil {
+		return err
+	}
+
+	frame := rate * opusFrameMs / 1000
+	inChannels := int(dec.NumChans)
+	shift := uint(dec.BitDepth) - 16
+	buf := &audio.IntBuffer{Data: make([]int, 4096*inChannels)}
+	pcm := make([]int16, 0, 2*frame*channels)
+	packet := make([]byte, 4000)
+	var read, total, granule int64
+	encode := func() error {
+		n, err := enc.encode(pcm[:frame*channels], packet)
+		if err != nil {
+			return err
+		}
+		granule += int64(frame) * scale
+		pcm = append(pcm[:0], pcm[frame*channels:]...)
+		return ogg.writePacket(packet[:n], granule)
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := dec.PCMBuffer(buf)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+		if total == 0 {
+			// The size of the PCM chunk is known once reading started.
+			total = dec.PCMLen() / int64(dec.BitDepth/8) / int64(inChannels)
+		}
+		for i := 0; i+inChannels <= n; i += inChannels {
+			l := int16(buf.Data[i] >> shift)
+			r := l
+			if inChannels == 2 {
+				r = int16(buf.Data[i+1] >> shift)
+			}
+			switch {
+			case channels == 2:
+				pcm = append(pcm, l, r)
+			case inChannels == 2:
+				pcm = append(pcm, int16((int32(l)+int32(r))/2))
+			default:
+				pcm = append(pcm, l)
+			}
+			if len(pcm) == frame*channels {
+				if err := encode(); err != nil {
+					return err
+				}
+			}
+		}
+		read += int64(n / inChannels)
+		if report != nil && total > 0 {
+			report(min(1, float64(read)/float64(total)))
+		}
+	}
+	// Pad the last frame and the encoder delay with silence, then trim the
+	// padding through the final granule position.
+	end := int64(preSkip) + read*scale
+	for len(pcm) > 0 || granule < end {
+		pcm = append(pcm, make([]int16, frame*channels-len(pcm))...)
+		if err := encode(); err != nil {
+			return err
+		}
+	}
+	ogg.granule = end
+	if err := ogg.flush(true); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if report != nil {
+		report(1)
+	}
+	return f.Close()
+}
+
+// opusVBRMode maps the VBR option to newOpusEncoder's mode: -1 keeps the
+// default, 0 is constant, 1 variable and 2 constrained variable bitrate.
+func opusVBRMode(vbr string) int {
+	switch strings.ToLower(vbr) {
+	case "off":
+		return 0
+	case "on":
+		return 1
+	case "constrained":
+		return 2
+	}
+	return -1
+}
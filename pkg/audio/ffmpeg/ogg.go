@@ -0,0 +1,131 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package ffmpeg
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// oggCRCTable is the CRC-32 table of Ogg pages: polynomial 0x04c11db7,
+// unreflected, initial value 0.
+var oggCRCTable = func() (t [256]uint32) {
+	for i := range t {
+		r := uint32(i) << 24
+		for range 8 {
+			if r&0x80000000 != 0 {
+				r = r<<1 ^ 0x04c11db7
+			} else {
+				r <<= 1
+			}
+		}
+		t[i] = r
+	}
+	return t
+}()
+
+func oggCRC(b []byte) uint32 {
+	var crc uint32
+	for _, c := range b {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^c]
+	}
+	return crc
+}
+
+// oggMaxPage is the payload size after which oggWriter starts a new page.
+const oggMaxPage = 4096
+
+// oggWriter writes the packets of one logical Ogg stream, several to a
+// page. Header packets are flushed onto pages of their own by the caller.
+type oggWriter struct {
+	w       io.Writer
+	serial  uint32
+	seq     uint32
+	granule int64
+	segs    []byte
+	data    []byte
+}
+
+// writePacket adds a packet that ends at granule, flushing the page first
+// when the packet would not fit on it.
+func (o *oggWriter) writePacket(p []byte, granule int64) error {
+	n := len(p)/255 + 1
+	if n > 255 {
+		return fmt.Errorf("ogg packet of %d bytes is too large", len(p))
+	}
+	if len(o.segs)+n > 255 || len(o.data)+len(p) > oggMaxPage {
+		if err := o.flush(false); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < n-1; i++ {
+		o.segs = append(o.segs, 255)
+	}
+	o.segs = append(o.segs, byte(len(p)%255))
+	o.data = append(o.data, p...)
+	o.granule = granule
+	return nil
+}
+
+// flush writes the pending packets as a page, marking it as the last page
+// of the stream when eos is set. A page without packets is only written
+// for eos.
+func (o *oggWriter) flush(eos bool) error {
+	if len(o.segs) == 0 && !eos {
+		return nil
+	}
+	var flags byte
+	if o.seq == 0 {
+		flags |= 0x02 // beginning of stream
+	}
+	if eos {
+		flags |= 0x04
+	}
+	page := make([]byte, 27, 27+len(o.segs)+len(o.data))
+	copy(page, "OggS")
+	page[5] = flags
+	binary.LittleEndian.PutUint64(page[6:], uint64(o.granule))
+	binary.LittleEndian.PutUint32(page[14:], o.serial)
+	binary.LittleEndian.PutUint32(page[18:], o.seq)
+	page[26] = byte(len(o.segs))
+	page = append(page, o.segs...)
+	page = append(page, o.data...)
+	binary.LittleEndian.PutUint32(page[22:], oggCRC(page))
+	o.seq++
+	o.segs = o.segs[:0]
+	o.data = o.data[:0]
+	_, err := o.w.Write(page)
+	return err
+}
+
+// opusHead returns the identification header of an Ogg Opus stream
+// (RFC 7845, section 5.1) with channel mapping family 0.
+func opusHead(channels, preSkip, rate int) []byte {
+	b := make([]byte, 19)
+	copy(b, "OpusHead")
+	b[8] = 1 // version
+	b[9] = byte(channels)
+	binary.LittleEndian.PutUint16(b[10:], uint16(preSkip))
+	binary.LittleEndian.PutUint32(b[12:], uint32(rate))
+	return b
+}
+
+// opusTags returns the comment header of an Ogg Opus stream without user
+// comments.
+func opusTags(vendor string) []byte {
+	b := make([]byte, 12, 16+len(vendor))
+	copy(b, "OpusTags")
+	binary.LittleEndian.PutUint32(b[8:], uint32(len(vendor)))
+	b = append(b, vendor...)
+	return binary.LittleEndian.AppendUint32(b, 0)
+}
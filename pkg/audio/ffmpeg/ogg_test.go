@@ -0,0 +1,124 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+package ffmpeg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+func TestOggCRCCheckValue(t *testing.T) {
+	if got := oggCRC([]byte("123456789")); got != 0x89a1897f {
+		t.Fatalf("oggCRC = %#x, want 0x89a1897f", got)
+	}
+}
+
+type oggPage struct {
+	flags   byte
+	granule int64
+	seq     uint32
+	packets [][]byte
+}
+
+// readOggPages parses pages written by oggWriter, whose packets never
+// continue onto the next page.
+func readOggPages(t *testing.T, b []byte) []oggPage {
+	t.Helper()
+	var pages []oggPage
+	for len(b) > 0 {
+		if len(b) < 27 || string(b[:4]) != "OggS" {
+			t.Fatalf("bad page header at %d bytes from the end", len(b))
+		}
+		nsegs := int(b[26])
+		segs := b[27 : 27+nsegs]
+		size := 0
+		for _, s := range segs {
+			size += int(s)
+		}
+		page := b[:27+nsegs+size]
+		crc := binary.LittleEndian.Uint32(page[22:])
+		check := bytes.Clone(page)
+		binary.LittleEndian.PutUint32(check[22:], 0)
+		if oggCRC(check) != crc {
+			t.Fatalf("page %d: CRC mismatch", len(pages))
+		}
+		p := oggPage{
+			flags:   page[5],
+			granule: int64(binary.LittleEndian.Uint64(page[6:])),
+			seq:     binary.LittleEndian.Uint32(page[18:]),
+		}
+		data, packet := page[27+nsegs:], []byte{}
+		for _, s := range segs {
+			packet = append(packet, data[:s]...)
+			data = data[s:]
+			if s < 255 {
+				p.packets = append(p.packets, packet)
+				packet = []byte{}
+			}
+		}
+		pages = append(pages, p)
+		b = b[len(page):]
+	}
+	return pages
+}
+
+func TestOggWriterPagesAndLacing(t *testing.T) {
+	var buf bytes.Buffer
+	o := &oggWriter{w: &buf, serial: 7}
+	if err := o.writePacket(opusHead(1, 312, 16000), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.flush(false); err != nil {
+		t.Fatal(err)
+	}
+	// 510 bytes needs a terminating zero-length segment.
+	sizes := []int{100, 510, 3000, 3000, 1}
+	for i, n := range sizes {
+		if err := o.writePacket(bytes.Repeat([]byte{byte(i)}, n), int64(i+1)*960); err != nil {
+			t.Fatal(err)
+		}
+	}
+	o.granule = 4000
+	if err := o.flush(true); err != nil {
+		t.Fatal(err)
+	}
+
+	pages := readOggPages(t, buf.Bytes())
+	if len(pages) != 3 {
+		t.Fatalf("got %d pages, want 3", len(pages))
+	}
+	if pages[0].flags != 0x02 || pages[0].granule != 0 || string(pages[0].packets[0][:8]) != "OpusHead" {
+		t.Fatalf("first page = %+v", pages[0])
+	}
+	var got []int
+	for i, p := range pages {
+		if p.seq != uint32(i) {
+			t.Errorf("page %d has sequence %d", i, p.seq)
+		}
+		if i > 0 {
+			for _, pk := range p.packets {
+				got = append(got, len(pk))
+			}
+		}
+	}
+	if !slices.Equal(got, sizes) {
+		t.Fatalf("packet sizes = %v, want %v", got, sizes)
+	}
+	if len(pages[1].packets) != 3 || pages[1].granule != 3*960 {
+		t.Errorf("second page = %d packets, granule %d", len(pages[1].packets), pages[1].granule)
+	}
+	if last := pages[2]; last.flags != 0x04 || last.granule != 4000 {
+		t.Errorf("last page = flags %#x granule %d", last.flags, last.granule)
+	}
+}
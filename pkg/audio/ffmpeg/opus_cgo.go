@@ -0,0 +1,100 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build opus_cgo
+
+package ffmpeg
+
+/*
+#include <opus.h>
+
+// The encoder controls are variadic macros, which cgo cannot call.
+// Negative complexity and vbr keep the defaults; vbr 2 is constrained VBR.
+static OpusEncoder *stt_opus_create(int rate, int channels, int bitrate, int complexity, int vbr, int *err) {
+	// VOIP tunes the encoder for speech, which is what STT uploads.
+	OpusEncoder *enc = opus_encoder_create(rate, channels, OPUS_APPLICATION_VOIP, err);
+	if (enc == NULL) {
+		return NULL;
+	}
+	if (bitrate > 0) {
+		*err = opus_encoder_ctl(enc, OPUS_SET_BITRATE(bitrate));
+	}
+	if (*err == OPUS_OK && complexity >= 0) {
+		*err = opus_encoder_ctl(enc, OPUS_SET_COMPLEXITY(complexity));
+	}
+	if (*err == OPUS_OK && vbr >= 0) {
+		*err = opus_encoder_ctl(enc, OPUS_SET_VBR(vbr > 0));
+	}
+	if (*err == OPUS_OK && vbr >= 0) {
+		*err = opus_encoder_ctl(enc, OPUS_SET_VBR_CONSTRAINT(vbr == 2));
+	}
+	if (*err != OPUS_OK) {
+		opus_encoder_destroy(enc);
+		return NULL;
+	}
+	return enc;
+}
+
+static int stt_opus_lookahead(OpusEncoder *enc) {
+	opus_int32 n = 0;
+	opus_encoder_ctl(enc, OPUS_GET_LOOKAHEAD(&n));
+	return n;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// nativeOpus reports whether this build links libopus (the opus_cgo tag).
+const nativeOpus = true
+
+// opusEncoder wraps a libopus encoder of interleaved 16-bit samples.
+type opusEncoder struct {
+	enc      *C.OpusEncoder
+	channels int
+}
+
+func newOpusEncoder(rate, channels, bitrate, complexity, vbr int) (*opusEncoder, error) {
+	var cerr C.int
+	enc := C.stt_opus_create(C.int(rate), C.int(channels), C.int(bitrate), C.int(complexity), C.int(vbr), &cerr)
+	if enc == nil {
+		return nil, fmt.Errorf("opus encoder: %s", C.GoString(C.opus_strerror(cerr)))
+	}
+	return &opusEncoder{enc: enc, channels: channels}, nil
+}
+
+// encode encodes one frame of pcm into out and returns the packet size.
+func (e *opusEncoder) encode(pcm []int16, out []byte) (int, error) {
+	n := C.opus_encode(e.enc,
+		(*C.opus_int16)(unsafe.Pointer(&pcm[0])), C.int(len(pcm)/e.channels),
+		(*C.uchar)(unsafe.Pointer(&out[0])), C.opus_int32(len(out)))
+	if n < 0 {
+		return 0, fmt.Errorf("opus encode: %s", C.GoString(C.opus_strerror(n)))
+	}
+	return int(n), nil
+}
+
+// lookahead returns the encoder delay in samples at the input rate.
+func (e *opusEncoder) lookahead() int {
+	return int(C.stt_opus_lookahead(e.enc))
+}
+
+func (e *opusEncoder) close() {
+	C.opus_encoder_destroy(e.enc)
+}
+
+// opusVendor returns the libopus version, for the OpusTags header.
+func opusVendor() string {
+	return C.GoString(C.opus_get_version_string())
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+//go:build !opus_cgo
+
+package ffmpeg
+
+import "errors"
+
+// nativeOpus reports whether this build links libopus (the opus_cgo tag).
+const nativeOpus = false
+
+type opusEncoder struct{}
+
+func newOpusEncoder(rate, channels, bitrate, complexity, vbr int) (*opusEncoder, error) {
+	return nil, errors.New("opus encoding is not built in")
+}
+
+func (e *opusEncoder) encode(pcm []int16, out []byte) (int, error) {
+	return 0, errors.New("opus encoding is not built in")
+}
+
+func (e *opusEncoder) lookahead() int { return 0 }
+
+func (e *opusEncoder) close() {}
+
+func opusVendor() string { return "" }
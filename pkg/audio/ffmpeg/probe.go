@@ -0,0 +1,225 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package ffmpeg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Info describes an audio file as far as Probe can tell from its headers.
+type Info struct {
+	// Container is the file format: "wav", "ogg", "flac" or "mp3".
+	Container string
+	// Codec is the ffmpeg encoder that produces the stream, e.g. "libopus"
+	// or "pcm_s16le", so it compares with the codec of Options.
+	Codec      string
+	SampleRate int
+	Channels   int
+	// Depth is the sample size in bits of PCM and FLAC, or 0.
+	Depth int
+}
+
+// probeSize is how much of a file Probe reads; ID3 tags with cover art
+// before the first MP3 frame can be larger, and are not recognized.
+const probeSize = 256 << 10
+
+var errUnknownFormat = errors.New("unrecognized audio format")
+
+// Probe reads the headers of the audio file at path. It recognizes WAV,
+// FLAC, MP3, and Ogg with Opus, Vorbis or FLAC, and returns an error for
+// anything else.
+func Probe(path string) (Info, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Info{}, err
+	}
+	defer f.Close()
+	b, err := io.ReadAll(io.LimitReader(f, probeSize))
+	if err != nil {
+		return Info{}, err
+	}
+	switch {
+	case len(b) >= 12 && string(b[:4]) == "RIFF" && string(b[8:12]) == "WAVE":
+		return probeWAV(b[12:])
+	case bytes.HasPrefix(b, []byte("OggS")):
+		return probeOgg(b)
+	}
+	b = skipID3(b)
+	if bytes.HasPrefix(b, []byte("fLaC")) && len(b) >= 8+34 && b[4]&0x7f == 0 {
+		info := flacStreamInfo(b[8:])
+		info.Container = "flac"
+		return info, nil
+	}
+	return probeMP3(b)
+}
+
+// Matches reports whether the file info describes is already what Convert
+// would make of it with opts, given the extension of the output file, so it
+// can be uploaded as is. The bitrate of lossy codecs is not compared, and
+// nothing matches when opts has filters or custom ffmpeg arguments.
+func (info Info) Matches(opts Options, ext string) bool {
+	if opts.needsExecutable() {
+		return false
+	}
+	settings, err := settingsFor(opts, info.SampleRate)
+	if err != nil {
+		return false
+	}
+	container := strings.ToLower(strings.TrimPrefix(ext, "."))
+	if container == "oga" || container == "opus" {
+		container = "ogg"
+	}
+	if info.Container != container || info.Codec != settings.FFCodec ||
+		info.SampleRate != settings.SampleRate || info.Channels != settings.Channels {
+		return false
+	}
+	return info.Codec != "flac" || info.Depth == settings.Depth
+}
+
+// probeWAV reads the fmt chunk of the RIFF chunks in b.
+func probeWAV(b []byte) (Info, error) {
+	for len(b) >= 8 {
+		id, size := string(b[:4]), int(binary.LittleEndian.Uint32(b[4:8]))
+		b = b[8:]
+		if id != "fmt " {
+			if size+size%2 > len(b) {
+				break
+			}
+			b = b[size+size%2:]
+			continue
+		}
+		if size < 16 || len(b) < 16 {
+			break
+		}
+		format := binary.LittleEndian.Uint16(b)
+		if format == 0xFFFE && size >= 26 && len(b) >= 26 {
+			// WAVE_FORMAT_EXTENSIBLE: the format is the start of the
+			// subformat GUID.
+			format = binary.LittleEndian.Uint16(b[24:])
+		}
+		info := Info{
+			Container:  "wav",
+			Channels:   int(binary.LittleEndian.Uint16(b[2:])),
+			SampleRate: int(binary.LittleEndian.Uint32(b[4:])),
+			Depth:      int(binary.LittleEndian.Uint16(b[14:])),
+		}
+		switch {
+		case format == 1 && slices.Contains([]int{16, 24, 32, 64}, info.Depth):
+			info.Codec = "pcm_s" + strconv.Itoa(info.Depth) + "le"
+		case format == 3 && (info.Depth == 32 || info.Depth == 64):
+			info.Codec = "pcm_f" + strconv.Itoa(info.Depth) + "le"
+		}
+		return info, nil
+	}
+	return Info{}, errUnknownFormat
+}
+
+// probeOgg reads the first packet of the first stream in b.
+func probeOgg(b []byte) (Info, error) {
+	if len(b) < 27 || len(b) < 27+int(b[26]) {
+		return Info{}, errUnknownFormat
+	}
+	segs := b[27 : 27+int(b[26])]
+	size := 0
+	for _, s := range segs {
+		size += int(s)
+		if s < 255 {
+			break
+		}
+	}
+	p := b[27+len(segs):]
+	if len(p) < size {
+		return Info{}, errUnknownFormat
+	}
+	p = p[:size]
+	info := Info{Container: "ogg"}
+	switch {
+	case bytes.HasPrefix(p, []byte("OpusHead")) && len(p) >= 19:
+		info.Codec = "libopus"
+		info.Channels = int(p[9])
+		info.SampleRate = int(binary.LittleEndian.Uint32(p[12:]))
+	case bytes.HasPrefix(p, []byte("\x01vorbis")) && len(p) >= 16:
+		info.Codec = "libvorbis"
+		info.Channels = int(p[11])
+		info.SampleRate = int(binary.LittleEndian.Uint32(p[12:]))
+	case bytes.HasPrefix(p, []byte("\x7fFLAC")) && len(p) >= 13+4+34:
+		info = flacStreamInfo(p[13+4:])
+		info.Container = "ogg"
+	default:
+		return Info{}, errUnknownFormat
+	}
+	return info, nil
+}
+
+// flacStreamInfo decodes the STREAMINFO metadata block b.
+func flacStreamInfo(b []byte) Info {
+	v := binary.BigEndian.Uint64(b[10:18])
+	return Info{
+		Codec:      "flac",
+		SampleRate: int(v >> 44),
+		Channels:   int(v>>41&0x7) + 1,
+		Depth:      int(v>>36&0x1f) + 1,
+	}
+}
+
+// skipID3 returns b after a leading ID3v2 tag.
+func skipID3(b []byte) []byte {
+	if len(b) < 10 || string(b[:3]) != "ID3" {
+		return b
+	}
+	size := int(b[6]&0x7f)<<21 | int(b[7]&0x7f)<<14 | int(b[8]&0x7f)<<7 | int(b[9]&0x7f)
+	size += 10
+	if b[5]&0x10 != 0 { // footer
+		size += 10
+	}
+	if size > len(b) {
+		return nil
+	}
+	return b[size:]
+}
+
+// mp3Rates are the sample rates of MPEG-1 audio; MPEG-2 halves them and
+// MPEG-2.5 quarters them.
+var mp3Rates = [3]int{44100, 48000, 32000}
+
+// probeMP3 reads the MPEG audio frame header at the start of b.
+func probeMP3(b []byte) (Info, error) {
+	if len(b) < 4 || b[0] != 0xff || b[1]&0xe0 != 0xe0 {
+		return Info{}, errUnknownFormat
+	}
+	version := b[1] >> 3 & 3
+	layer := b[1] >> 1 & 3
+	bitrate := b[2] >> 4
+	rate := b[2] >> 2 & 3
+	if version == 1 || layer == 0 || bitrate == 0 || bitrate == 15 || rate == 3 {
+		return Info{}, errUnknownFormat
+	}
+	info := Info{Container: "mp3", SampleRate: mp3Rates[rate], Channels: 2}
+	switch version {
+	case 2: // MPEG-2
+		info.SampleRate /= 2
+	case 0: // MPEG-2.5
+		info.SampleRate /= 4
+	}
+	if b[3]>>6 == 3 {
+		info.Channels = 1
+	}
+	info.Codec = [4]string{"", "libmp3lame", "mp2", "mp1"}[layer]
+	return info, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+package ffmpeg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTemp(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func wavHeader(format uint16, channels, rate, depth int) []byte {
+	b := []byte("RIFF\x00\x00\x00\x00WAVEfmt \x10\x00\x00\x00")
+	b = binary.LittleEndian.AppendUint16(b, format)
+	b = binary.LittleEndian.AppendUint16(b, uint16(channels))
+	b = binary.LittleEndian.AppendUint32(b, uint32(rate))
+	b = binary.LittleEndian.AppendUint32(b, uint32(rate*channels*depth/8))
+	b = binary.LittleEndian.AppendUint16(b, uint16(channels*depth/8))
+	b = binary.LittleEndian.AppendUint16(b, uint16(depth))
+	return append(b, "data\x00\x00\x00\x00"...)
+}
+
+func flacStreamInfoBlock(rate, channels, depth int) []byte {
+	b := make([]byte, 34)
+	v := uint64(rate)<<44 | uint64(channels-1)<<41 | uint64(depth-1)<<36
+	binary.BigEndian.PutUint64(b[10:], v)
+	return b
+}
+
+func TestProbe(t *testing.T) {
+	var ogg bytes.Buffer
+	o := &oggWriter{w: &ogg}
+	_ = o.writePacket(opusHead(1, 312, 16000), 0)
+	_ = o.flush(false)
+
+	flac := append([]byte("fLaC\x00\x00\x00\x22"), flacStreamInfoBlock(44100, 2, 24)...)
+	id3 := []byte("ID3\x04\x00\x00\x00\x00\x00\x05xxxxx")
+
+	tests := []struct {
+		name string
+		data []byte
+		want Info
+	}{
+		{"a.wav", wavHeader(1, 1, 16000, 16), Info{Container: "wav", Codec: "pcm_s16le", SampleRate: 16000, Channels: 1, Depth: 16}},
+		{"f.wav", wavHeader(3, 2, 48000, 32), Info{Container: "wav", Codec: "pcm_f32le", SampleRate: 48000, Channels: 2, Depth: 32}},
+		{"a.ogg", ogg.Bytes(), Info{Container: "ogg", Codec: "libopus", SampleRate: 16000, Channels: 1}},
+		{"a.flac", flac, Info{Container: "flac", Codec: "flac", SampleRate: 44100, Channels: 2, Depth: 24}},
+		// MPEG-1 Layer III, 128 kbps, 44.1 kHz, mono, after an ID3v2 tag.
+		{"a.mp3", append(id3, 0xff, 0xfb, 0x90, 0xc4), Info{Container: "mp3", Codec: "libmp3lame", SampleRate: 44100, Channels: 1}},
+		// MPEG-2 Layer III, 16 kHz, stereo.
+		{"b.mp3", []byte{0xff, 0xf3, 0x88, 0x00}, Info{Container: "mp3", Codec: "libmp3lame", SampleRate: 16000, Channels: 2}},
+	}
+	for _, tt := range tests {
+		got, err := Probe(writeTemp(t, tt.name, tt.data))
+		if err != nil || got != tt.want {
+			t.Errorf("Probe(%s) = %+v, %v; want %+v", tt.name, got, err, tt.want)
+		}
+	}
+	for _, bad := range [][]byte{[]byte("not audio"), {0xff, 0xfb, 0xf0, 0x00}, nil} {
+		if info, err := Probe(writeTemp(t, "bad", bad)); err == nil {
+			t.Errorf("Probe(%q) = %+v, want an error", bad, info)
+		}
+	}
+}
+
+func TestInfoMatches(t *testing.T) {
+	opus := Info{Container: "ogg", Codec: "libopus", SampleRate: 16000, Channels: 1}
+	opts := Options{Codec: "opus", Channels: 1, SampleRate: 16000}
+	for _, ext := range []string{"ogg", ".oga", "opus"} {
+		if !opus.Matches(opts, ext) {
+			t.Errorf("opus in %s does not match", ext)
+		}
+	}
+	if opus.Matches(opts, "webm") {
+		t.Errorf("ogg matches webm")
+	}
+	if opus.Matches(Options{Codec: "opus", Channels: 1, SampleRate: 48000}, "ogg") {
+		t.Errorf("16 kHz matches 48 kHz")
+	}
+	if opus.Matches(Options{Codec: "mp3", Channels: 1, SampleRate: 16000}, "ogg") {
+		t.Errorf("opus matches mp3")
+	}
+	if !opus.Matches(Options{Codec: "opus"}, "ogg") {
+		t.Errorf("opus does not match default rate and channels")
+	}
+	if opus.Matches(Options{Codec: "opus", ExtraArgs: []string{"-af", "highpass=f=200"}}, "ogg") {
+		t.Errorf("opus matches with custom ffmpeg arguments")
+	}
+
+	wav := Info{Container: "wav", Codec: "pcm_s16le", SampleRate: 16000, Channels: 1, Depth: 16}
+	if !wav.Matches(Options{Codec: "pcm", SampleRate: 16000}, "wav") {
+		t.Errorf("16-bit WAV does not match pcm")
+	}
+	flac := Info{Container: "flac", Codec: "flac", SampleRate: 16000, Channels: 1, Depth: 24}
+	if flac.Matches(Options{Codec: "flac", Depth: 16}, "flac") || !flac.Matches(Options{Codec: "flac", Depth: 24}, "flac") {
+		t.Errorf("FLAC depth is not compared")
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package record
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/gordonklaus/portaudio"
+
+	"stt/pkg/record/source"
+)
+
+// Sources a Recorder records from, see package source.
+const (
+	SourceMicrophone = source.Microphone
+	SourceLoopback   = source.Loopback
+)
+
+// loopbackSuffix ends the names PortAudio gives the WASAPI loopback device of
+// each output device.
+const loopbackSuffix = " [Loopback]"
+
+// loopbackDevices returns the WASAPI loopback devices among devices.
+func loopbackDevices(devices []*portaudio.DeviceInfo) []*portaudio.DeviceInfo {
+	var loopbacks []*portaudio.DeviceInfo
+	for _, d := range devices {
+		if d.HostApi != nil && d.HostApi.Type == portaudio.WASAPI && strings.HasSuffix(d.Name, loopbackSuffix) {
+			loopbacks = append(loopbacks, d)
+		}
+	}
+	return loopbacks
+}
+
+// findLoopback returns the position in loopbacks of the device want selects
+// as findDevice does, or, when want is empty, of the loopback device of the
+// output device called output, falling back to the first.
+func findLoopback(loopbacks []*portaudio.DeviceInfo, want, output string) (int, error) {
+	if len(loopbacks) == 0 {
+		return -1, fmt.Errorf("no WASAPI loopback device found")
+	}
+	if want != "" {
+		return findDevice(loopbacks, want)
+	}
+	for i, d := range loopbacks {
+		if d.Name == output+loopbackSuffix {
+			return i, nil
+		}
+	}
+	return 0, nil
+}
+
+// openLoopback opens the loopback device of the default WASAPI output
+// device, or the one opts.InputDevice selects, in its own format, which
+// WASAPI shares with every other program playing on it. It returns the
+// buffer the stream reads into and the converter to opts' format.
+func (r *Recorder) openLoopback() (*portaudio.Stream, []int16, *converter, error) {
+	devices, err := inputDevices()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	loopbacks := loopbackDevices(devices)
+	output := ""
+	if api, err := portaudio.HostApi(portaudio.WASAPI); err == nil && api.DefaultOutputDevice != nil {
+		output = api.DefaultOutputDevice.Name
+	}
+	i, err := findLoopback(loopbacks, r.opts.InputDevice, output)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	d := loopbacks[i]
+	rate := int(d.DefaultSampleRate)
+	if r.opts.Debug {
+		fmt.Printf("[record] using loopback device %s (%d Hz, %d channels)\n", d.Name, rate, d.MaxInputChannels)
+	}
+	// About 20 ms a buffer, like 1024 samples at 48 kHz stereo.
+	frames := rate / 50
+	in := make([]int16, frames*d.MaxInputChannels)
+	p := portaudio.HighLatencyParameters(d, nil)
+	p.Input.Channels = d.MaxInputChannels
+	p.SampleRate = float64(rate)
+	p.FramesPerBuffer = frames
+	stream, err := portaudio.OpenStream(p, in)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return stream, in, newConverter(d.MaxInputChannels, rate, r.opts.Channels, r.opts.SampleRate), nil
+}
+
+// converter turns interleaved 16-bit audio of one channel count and sample
+// rate into another: channels are averaged down to mono or taken in order,
+// and samples are linearly interpolated, which is plenty for speech.
+type converter struct {
+	inChannels  int
+	outChannels int
+	// step is how many input frames one output frame advances.
+	step float64
+	// pos is where the next output frame falls, in input frames after prev.
+	pos     float64
+	prev    []float64
+	started bool
+	out     []int16
+}
+
+func newConverter(inChannels, inRate, outChannels, outRate int) *converter {
+	return &converter{
+		inChannels:  inChannels,
+		outChannels: outChannels,
+		step:        float64(inRate) / float64(outRate),
+		prev:        make([]float64, outChannels),
+	}
+}
+
+// sample returns output channel ch of input frame i of in.
+func (c *converter) sample(in []int16, i, ch int) float64 {
+	frame := in[i*c.inChannels : (i+1)*c.inChannels]
+	if c.outChannels == 1 {
+		sum := 0.0
+		for _, s := range frame {
+			sum += float64(s)
+		}
+		return sum / float64(len(frame))
+	}
+	return float64(frame[ch%c.inChannels])
+}
+
+// convert returns in in the output format. The result is reused by the next
+// call; frames that fall after the end of in come out of the next one.
+func (c *converter) convert(in []int16) []int16 {
+	n := len(in) / c.inChannels
+	c.out = c.out[:0]
+	if n == 0 {
+		return c.out
+	}
+	if !c.started {
+		for ch := range c.prev {
+			c.prev[ch] = c.sample(in, 0, ch)
+		}
+		// The first output frame is the first frame of in.
+		c.pos = 1
+		c.started = true
+	}
+	// Frame 0 is prev, the last frame of the previous buffer; frame j > 0
+	// is frame j-1 of in.
+	at := func(j, ch int) float64 {
+		if j == 0 {
+			return c.prev[ch]
+		}
+		return c.sample(in, j-1, ch)
+	}
+	for ; c.pos < float64(n); c.pos += c.step {
+		j := int(c.pos)
+		frac := c.pos - float64(j)
+		for ch := 0; ch < c.outChannels; ch++ {
+			a, b := at(j, ch), at(j+1, ch)
+			c.out = append(c.out, int16(math.Round(a+(b-a)*frac)))
+		}
+	}
+	c.pos -= float64(n)
+	for ch := range c.prev {
+		c.prev[ch] = c.sample(in, n-1, ch)
+	}
+	return c.out
+}
@@ -0,0 +1,596 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package record
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gordonklaus/portaudio"
+)
+
+// State represents recorder state.
+type State int
+
+const (
+	StateIdle State = iota
+	StateRecording
+	StatePaused
+	StateStopping
+	StateCanceled
+)
+
+// Result is returned when a recording completes or is canceled.
+type Result struct {
+	WavPath string
+	// EncodedPath names the recording in place of WavPath when an Encoder
+	// wrote it.
+	EncodedPath string
+	Duration    time.Duration
+	Canceled    bool
+	Err         error
+}
+
+// PanicError is the Err of a Result when the recording goroutine panicked.
+// The recording is lost, but the process keeps running.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("recorder panicked: %v", e.Value)
+}
+
+// Encoder takes the place of the WAV file of a recording: it is given the
+// samples as they are recorded and writes the recording in its own format.
+type Encoder interface {
+	Write(samples []int16) error
+	// Close finishes the file once the recording stopped.
+	Close() error
+	// Abort stops encoding and removes what was written.
+	Abort()
+	// Path returns the file written.
+	Path() string
+}
+
+// Level is the loudness of one buffer of recorded audio, as a fraction of
+// full scale.
+type Level struct {
+	Peak float64
+	RMS  float64
+}
+
+// Options configures a Recorder.
+type Options struct {
+	Channels   int
+	SampleRate int
+	// InputDevice names the capture device: its PortAudio device index, an
+	// exact name, or else a case-insensitive substring of one. Empty uses
+	// the system default. With SourceLoopback it selects among the
+	// loopback devices instead, by index or by output device name.
+	InputDevice string
+	// Source is SourceMicrophone, or SourceLoopback to record what the PC
+	// plays; empty means SourceMicrophone.
+	Source string
+	// SegmentLength, when set, also writes the recording in segments of
+	// about this length, each passed to the OnSegment callback as soon as
+	// it ends, so they can be transcribed while recording continues.
+	SegmentLength time.Duration
+	// Encode, when set, is asked for an Encoder at the start of each
+	// recording, given the path its WAV would have minus the extension.
+	// The recording then writes no WAV and Result.EncodedPath names the
+	// encoder's file; when Encode fails the WAV is written as usual.
+	Encode func(base string) (Encoder, error)
+	// Debug logs the device, output file and stream errors to stdout.
+	Debug bool
+}
+
+// Recorder manages PortAudio recording and streaming WAV writing.
+type Recorder struct {
+	mu         sync.Mutex
+	state      State
+	opts       Options
+	tempDir    string
+	wavPath    string
+	stopCtx    context.Context
+	stopCancel context.CancelFunc
+	done       chan Result
+	onLevel    func(Level)
+	onSegment  func(Segment)
+}
+
+// New creates a recorder that writes WAV files into tempDir.
+func New(opts Options, tempDir string) *Recorder {
+	return &Recorder{opts: opts, tempDir: tempDir, state: StateIdle}
+}
+
+// Start begins recording.
+func (r *Recorder) Start(ctx context.Context) error {
+	r.mu.Lock()
+	if r.state != StateIdle {
+		r.mu.Unlock()
+		return fmt.Errorf("recorder not idle")
+	}
+	r.state = StateRecording
+	r.done = make(chan Result, 1)
+	r.stopCtx, r.stopCancel = context.WithCancel(ctx)
+	r.mu.Unlock()
+
+	go r.recordLoop()
+	return nil
+}
+
+// Stop requests a clean stop and waits for completion.
+func (r *Recorder) Stop() (Result, error) {
+	r.mu.Lock()
+	if r.state != StateRecording && r.state != StatePaused {
+		r.mu.Unlock()
+		return Result{}, fmt.Errorf("recorder not running")
+	}
+	r.state = StateStopping
+	cancel := r.stopCancel
+	done := r.done
+	r.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	res := <-done
+	return res, res.Err
+}
+
+// Cancel requests immediate stop and cleanup, waits for completion.
+func (r *Recorder) Cancel() (Result, error) {
+	r.mu.Lock()
+	if r.state != StateRecording && r.state != StatePaused {
+		r.mu.Unlock()
+		return Result{}, fmt.Errorf("recorder not running")
+	}
+	r.state = StateCanceled
+	cancel := r.stopCancel
+	done := r.done
+	r.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	res := <-done
+	return res, res.Err
+}
+
+// TogglePause toggles pause/resume.
+func (r *Recorder) TogglePause() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.state != StateRecording && r.state != StatePaused {
+		return fmt.Errorf("recorder not running")
+	}
+	if r.state == StatePaused {
+		r.state = StateRecording
+	} else {
+		r.state = StatePaused
+	}
+	return nil
+}
+
+// Devices returns the names of the devices that can be recorded from, for
+// choosing INPUT_DEVICE.
+func Devices() ([]string, error) {
+	if err := portaudio.Initialize(); err != nil {
+		return nil, fmt.Errorf("portaudio init failed: %w", err)
+	}
+	defer portaudio.Terminate()
+	devices, err := inputDevices()
+	if err != nil {
+		return nil, err
+	}
+	return deviceNames(devices), nil
+}
+
+// DeviceInfo describes a device that can be recorded from.
+type DeviceInfo struct {
+	// Index is the PortAudio device index, which INPUT_DEVICE accepts.
+	Index   int    `json:"index"`
+	Name    string `json:"name"`
+	HostAPI string `json:"host_api"`
+	// Channels is the most input channels the device offers.
+	Channels          int     `json:"channels"`
+	DefaultSampleRate float64 `json:"default_sample_rate"`
+	// SampleRates are the rates of probeRates the device records 16-bit
+	// mono audio at.
+	SampleRates []int `json:"sample_rates"`
+	// Default is set for the system default input device.
+	Default bool `json:"default"`
+}
+
+// probeRates are the sample rates DeviceDetails checks.
+var probeRates = []int{8000, 11025, 16000, 22050, 24000, 32000, 44100, 48000, 96000}
+
+// DeviceDetails describes the devices that can be recorded from, with the
+// host API and the channels and sample rates each supports.
+func DeviceDetails() ([]DeviceInfo, error) {
+	if err := portaudio.Initialize(); err != nil {
+		return nil, fmt.Errorf("portaudio init failed: %w", err)
+	}
+	defer portaudio.Terminate()
+	devices, err := inputDevices()
+	if err != nil {
+		return nil, err
+	}
+	def, _ := portaudio.DefaultInputDevice()
+	infos := make([]DeviceInfo, len(devices))
+	for i, d := range devices {
+		info := DeviceInfo{
+			Index:             d.Index,
+			Name:              d.Name,
+			Channels:          d.MaxInputChannels,
+			DefaultSampleRate: d.DefaultSampleRate,
+			SampleRates:       []int{},
+			Default:           def != nil && def.Index == d.Index,
+		}
+		if d.HostApi != nil {
+			info.HostAPI = d.HostApi.Name
+		}
+		for _, rate := range probeRates {
+			p := portaudio.HighLatencyParameters(d, nil)
+			p.Input.Channels = 1
+			p.SampleRate = float64(rate)
+			if portaudio.IsFormatSupported(p, make([]int16, 1)) == nil {
+				info.SampleRates = append(info.SampleRates, rate)
+			}
+		}
+		infos[i] = info
+	}
+	return infos, nil
+}
+
+func deviceNames(devices []*portaudio.DeviceInfo) []string {
+	names := make([]string, len(devices))
+	for i, d := range devices {
+		names[i] = d.Name
+	}
+	return names
+}
+
+func inputDevices() ([]*portaudio.DeviceInfo, error) {
+	all, err := portaudio.Devices()
+	if err != nil {
+		return nil, err
+	}
+	var inputs []*portaudio.DeviceInfo
+	for _, d := range all {
+		if d.MaxInputChannels > 0 {
+			inputs = append(inputs, d)
+		}
+	}
+	return inputs, nil
+}
+
+// findDevice returns the position in devices of the device want selects:
+// the one with that PortAudio index when want is a number, otherwise the one
+// matchDevice finds by name. A number is never matched against names, where
+// it would pick any device with a version or a channel count in its name.
+func findDevice(devices []*portaudio.DeviceInfo, want string) (int, error) {
+	if n, err := strconv.Atoi(strings.TrimSpace(want)); err == nil {
+		for i, d := range devices {
+			if d.Index == n {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("input device %d not found", n)
+	}
+	return matchDevice(deviceNames(devices), want)
+}
+
+// matchDevice returns the index of the device called want: an exact name
+// first, otherwise the first name containing want, ignoring case.
+func matchDevice(names []string, want string) (int, error) {
+	for i, name := range names {
+		if name == want {
+			return i, nil
+		}
+	}
+	lower := strings.ToLower(want)
+	for i, name := range names {
+		if strings.Contains(strings.ToLower(name), lower) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("input device %q not found", want)
+}
+
+// openStream opens opts.InputDevice, or the default input device when it is
+// empty, with the same latency settings PortAudio uses for default streams.
+func (r *Recorder) openStream(in []int16) (*portaudio.Stream, error) {
+	if r.opts.InputDevice == "" {
+		return portaudio.OpenDefaultStream(r.opts.Channels, 0, float64(r.opts.SampleRate), len(in), in)
+	}
+	devices, err := inputDevices()
+	if err != nil {
+		return nil, err
+	}
+	names := deviceNames(devices)
+	i, err := findDevice(devices, r.opts.InputDevice)
+	if err != nil {
+		return nil, err
+	}
+	if r.opts.Debug {
+		fmt.Printf("[record] using input device %s\n", names[i])
+	}
+	p := portaudio.HighLatencyParameters(devices[i], nil)
+	p.Input.Channels = r.opts.Channels
+	p.SampleRate = float64(r.opts.SampleRate)
+	p.FramesPerBuffer = len(in)
+	return portaudio.OpenStream(p, in)
+}
+
+// OnLevel registers fn to receive the level of every buffer recorded while
+// not paused, about every 64 ms at 16 kHz. fn runs on the recording
+// goroutine and must not block; nil removes it.
+func (r *Recorder) OnLevel(fn func(Level)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onLevel = fn
+}
+
+// measure returns the level of samples.
+func measure(samples []int16) Level {
+	if len(samples) == 0 {
+		return Level{}
+	}
+	var peak, sum float64
+	for _, v := range samples {
+		f := float64(v) / 32768
+		sum += f * f
+		if f < 0 {
+			f = -f
+		}
+		if f > peak {
+			peak = f
+		}
+	}
+	return Level{Peak: peak, RMS: math.Sqrt(sum / float64(len(samples)))}
+}
+
+// State returns the current recorder state.
+func (r *Recorder) State() State {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+func (r *Recorder) recordLoop() {
+	wavPath := r.generateTempWav()
+	r.wavPath = wavPath
+	// Results are sent last, so a panic always comes before one; the
+	// microphone stream it leaves open is closed by Terminate.
+	defer func() {
+		if v := recover(); v != nil {
+			r.finish(Result{WavPath: wavPath, Err: &PanicError{Value: v, Stack: debug.Stack()}})
+		}
+	}()
+
+	if r.opts.Debug {
+		fmt.Printf("[record] starting, writing to %s\n", wavPath)
+	}
+
+	if err := portaudio.Initialize(); err != nil {
+		r.finish(Result{WavPath: wavPath, Err: fmt.Errorf("portaudio init failed: %w", err)})
+		return
+	}
+	defer portaudio.Terminate()
+
+	in := make([]int16, 1024)
+	var conv *converter
+	var stream *portaudio.Stream
+	var err error
+	if r.opts.Source == SourceLoopback {
+		stream, in, conv, err = r.openLoopback()
+	} else {
+		stream, err = r.openStream(in)
+	}
+	if err != nil {
+		r.finish(Result{WavPath: wavPath, Err: fmt.Errorf("open stream failed: %w", err)})
+		return
+	}
+	if err := stream.Start(); err != nil {
+		_ = stream.Close()
+		r.finish(Result{WavPath: wavPath, Err: fmt.Errorf("start stream failed: %w", err)})
+		return
+	}
+
+	out, encodedPath, err := r.createOutput(wavPath)
+	if err != nil {
+		_ = stream.Stop()
+		_ = stream.Close()
+		r.finish(Result{WavPath: wavPath, Err: fmt.Errorf("create wav failed: %w", err)})
+		return
+	}
+	kind := "wav"
+	if encodedPath != "" {
+		kind = "encoder"
+	}
+	frames := 0
+	var segments *segmentWriter
+	if r.opts.SegmentLength > 0 {
+		segments = newSegmentWriter(wavPath, r.opts, r.emitSegment)
+	}
+
+	for {
+		if r.isCanceled() {
+			break
+		}
+		if r.isPaused() {
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		select {
+		case <-r.stopCtx.Done():
+			goto done
+		default:
+		}
+
+		if err := stream.Read(); err != nil {
+			if r.opts.Debug {
+				fmt.Printf("[record] stream read error: %v\n", err)
+			}
+			continue
+		}
+		samples := in
+		if conv != nil {
+			samples = conv.convert(in)
+		}
+		if err := out.write(samples); err != nil {
+			out.remove()
+			_ = stream.Stop()
+			_ = stream.Close()
+			if segments != nil {
+				segments.abort()
+			}
+			r.finish(Result{WavPath: wavPath, Err: fmt.Errorf("%s write failed: %w", kind, err)})
+			return
+		}
+		frames += len(samples) / r.opts.Channels
+		level := measure(samples)
+		if segments != nil {
+			segments.write(samples, level)
+		}
+		r.mu.Lock()
+		onLevel := r.onLevel
+		r.mu.Unlock()
+		if onLevel != nil {
+			onLevel(level)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+done:
+	_ = stream.Stop()
+	_ = stream.Close()
+
+	if r.isCanceled() {
+		out.remove()
+		if segments != nil {
+			segments.abort()
+		}
+		r.finish(Result{WavPath: "", Canceled: true})
+		return
+	}
+
+	if err := out.close(); err != nil {
+		_ = os.Remove(wavPath)
+		if segments != nil {
+			segments.abort()
+		}
+		r.finish(Result{WavPath: wavPath, Err: fmt.Errorf("%s close failed: %w", kind, err)})
+		return
+	}
+	if segments != nil {
+		segments.end(true)
+	}
+
+	duration := time.Duration(frames) * time.Second / time.Duration(r.opts.SampleRate)
+	if encodedPath != "" {
+		r.finish(Result{EncodedPath: encodedPath, Duration: duration})
+		return
+	}
+	r.finish(Result{WavPath: wavPath, Duration: duration})
+}
+
+// output is what recordLoop writes a recording to: its WAV file, or an
+// Encoder in its place.
+type output interface {
+	write(samples []int16) error
+	close() error
+	remove()
+}
+
+type encoderOutput struct{ Encoder }
+
+func (o encoderOutput) write(samples []int16) error { return o.Write(samples) }
+func (o encoderOutput) close() error                { return o.Close() }
+func (o encoderOutput) remove()                     { o.Abort() }
+
+// createOutput opens the output of the recording at wavPath, an Encoder from
+// opts.Encode when it gives one, and returns the Encoder's file or "".
+func (r *Recorder) createOutput(wavPath string) (output, string, error) {
+	if r.opts.Encode != nil {
+		enc, err := r.opts.Encode(strings.TrimSuffix(wavPath, filepath.Ext(wavPath)))
+		if err == nil {
+			if r.opts.Debug {
+				fmt.Printf("[record] encoding to %s\n", enc.Path())
+			}
+			return encoderOutput{enc}, enc.Path(), nil
+		}
+		if r.opts.Debug {
+			fmt.Printf("[record] cannot encode while recording, writing the wav: %v\n", err)
+		}
+	}
+	w, err := createWav(wavPath, r.opts.SampleRate, r.opts.Channels)
+	return w, "", err
+}
+
+// emitSegment passes seg to the OnSegment callback, or removes its file
+// when there is none.
+func (r *Recorder) emitSegment(seg Segment) {
+	if r.opts.Debug && seg.WavPath != "" {
+		fmt.Printf("[record] segment %d ended at %s\n", seg.Index, (seg.Start + seg.Duration).Round(time.Millisecond))
+	}
+	r.mu.Lock()
+	fn := r.onSegment
+	r.mu.Unlock()
+	if fn != nil {
+		fn(seg)
+	} else if seg.WavPath != "" {
+		_ = os.Remove(seg.WavPath)
+	}
+}
+
+func (r *Recorder) finish(res Result) {
+	r.mu.Lock()
+	r.state = StateIdle
+	r.mu.Unlock()
+	r.done <- res
+}
+
+func (r *Recorder) isPaused() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state == StatePaused
+}
+
+func (r *Recorder) isCanceled() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state == StateCanceled
+}
+
+func (r *Recorder) generateTempWav() string {
+	id := strings.ReplaceAll(uuid.New().String(), "-", "")[:16]
+	base := fmt.Sprintf("RecordTemp_%s.wav", id)
+	dir := r.tempDir
+	if dir == "" {
+		cwd, _ := os.Getwd()
+		dir = cwd
+	}
+	return filepath.Join(dir, base)
+}
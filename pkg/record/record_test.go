@@ -0,0 +1,242 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package record
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-audio/wav"
+	"github.com/gordonklaus/portaudio"
+)
+
+func TestMatchDevice(t *testing.T) {
+	names := []string{
+		"Microphone (Realtek(R) Audio)",
+		"Headset Microphone (USB Audio)",
+		"Microphone",
+	}
+	tests := map[string]int{
+		"Microphone":     2,
+		"usb":            1,
+		"REALTEK":        0,
+		"Headset Microp": 1,
+	}
+	for want, idx := range tests {
+		if got, err := matchDevice(names, want); err != nil || got != idx {
+			t.Fatalf("matchDevice(%q) = %d, %v; want %d", want, got, err, idx)
+		}
+	}
+	if _, err := matchDevice(names, "webcam"); err == nil {
+		t.Fatal("matchDevice(webcam) succeeded, want error")
+	}
+}
+
+func TestFindDeviceByIndex(t *testing.T) {
+	devices := []*portaudio.DeviceInfo{
+		{Index: 1, Name: "Microphone (Realtek(R) Audio)"},
+		{Index: 4, Name: "USB Audio 2.0 Microphone"},
+	}
+	tests := map[string]int{"4": 1, " 1 ": 0, "usb": 1}
+	for want, idx := range tests {
+		if got, err := findDevice(devices, want); err != nil || got != idx {
+			t.Fatalf("findDevice(%q) = %d, %v; want %d", want, got, err, idx)
+		}
+	}
+	// A number is an index, not part of a name.
+	if _, err := findDevice(devices, "2"); err == nil {
+		t.Fatal("findDevice(2) succeeded, want error")
+	}
+}
+
+func TestFindLoopback(t *testing.T) {
+	wasapi := &portaudio.HostApiInfo{Type: portaudio.WASAPI}
+	mme := &portaudio.HostApiInfo{Type: portaudio.MME}
+	loopbacks := loopbackDevices([]*portaudio.DeviceInfo{
+		{Index: 1, Name: "Microphone (Realtek(R) Audio)", HostApi: mme},
+		{Index: 9, Name: "Speakers (Realtek(R) Audio) [Loopback]", HostApi: wasapi},
+		{Index: 10, Name: "Headphones (USB Audio) [Loopback]", HostApi: wasapi},
+		{Index: 11, Name: "Microphone (Realtek(R) Audio)", HostApi: wasapi},
+	})
+	if len(loopbacks) != 2 {
+		t.Fatalf("loopbackDevices found %d, want 2", len(loopbacks))
+	}
+	tests := []struct{ want, output string }{
+		{"", "Headphones (USB Audio)"},
+		{"", "Missing"},
+		{"10", ""},
+		{"speakers", "Headphones (USB Audio)"},
+	}
+	for i, idx := range []int{1, 0, 1, 0} {
+		if got, err := findLoopback(loopbacks, tests[i].want, tests[i].output); err != nil || got != idx {
+			t.Fatalf("findLoopback(%q, %q) = %d, %v; want %d", tests[i].want, tests[i].output, got, err, idx)
+		}
+	}
+	if _, err := findLoopback(nil, "", ""); err == nil {
+		t.Fatal("findLoopback with no devices succeeded, want error")
+	}
+}
+
+func TestConverterDownmixesAndResamples(t *testing.T) {
+	c := newConverter(2, 48000, 1, 16000)
+	// A ramp that climbs 3 a frame, in stereo with the right channel 100
+	// higher, split over buffers that are not a multiple of the step.
+	var got []int16
+	frame := 0
+	for _, n := range []int{5, 7, 12} {
+		in := make([]int16, 0, 2*n)
+		for i := 0; i < n; i++ {
+			in = append(in, int16(3*frame), int16(3*frame+100))
+			frame++
+		}
+		got = append(got, c.convert(in)...)
+	}
+	if len(got) != 8 {
+		t.Fatalf("got %d samples, want 8", len(got))
+	}
+	for i, v := range got {
+		if want := int16(9*i + 50); v != want {
+			t.Fatalf("sample %d = %d, want %d", i, v, want)
+		}
+	}
+
+	up := newConverter(1, 8000, 2, 16000).convert([]int16{0, 100, 200})
+	if want := []int16{0, 0, 50, 50, 100, 100, 150, 150}; !slices.Equal(up, want) {
+		t.Fatalf("upsampled = %v, want %v", up, want)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	if got := measure(nil); got != (Level{}) {
+		t.Fatalf("measure(nil) = %+v, want silence", got)
+	}
+	got := measure([]int16{16384, -16384, 16384, -32768})
+	if got.Peak != 1 {
+		t.Fatalf("Peak = %v, want 1", got.Peak)
+	}
+	if want := math.Sqrt((0.25*3 + 1) / 4); math.Abs(got.RMS-want) > 1e-9 {
+		t.Fatalf("RMS = %v, want %v", got.RMS, want)
+	}
+}
+
+func TestSegmentWriterEndsSegmentsInQuietBuffers(t *testing.T) {
+	var segs []Segment
+	opts := Options{Channels: 1, SampleRate: 1000, SegmentLength: 2 * time.Second}
+	w := newSegmentWriter(filepath.Join(t.TempDir(), "RecordTemp_x.wav"), opts, func(s Segment) { segs = append(segs, s) })
+	buf := make([]int16, 500)
+	loud, quiet := Level{RMS: 0.2}, Level{RMS: 0.001}
+
+	// Quiet before the length is reached, then loud until the grace runs out.
+	for i := 0; i < 14; i++ {
+		level := loud
+		if i < 2 {
+			level = quiet
+		}
+		w.write(buf, level)
+	}
+	// A second segment ending in the first quiet buffer past the length.
+	for i := 0; i < 5; i++ {
+		level := loud
+		if i == 4 {
+			level = quiet
+		}
+		w.write(buf, level)
+	}
+	w.write(buf, loud)
+	w.end(true)
+
+	want := []Segment{
+		{Index: 0, Start: 0, Duration: 7 * time.Second},
+		{Index: 1, Start: 7 * time.Second, Duration: 2500 * time.Millisecond},
+		{Index: 2, Start: 9500 * time.Millisecond, Duration: 500 * time.Millisecond, Last: true},
+	}
+	if len(segs) != len(want) {
+		t.Fatalf("got %d segments, want %d: %+v", len(segs), len(want), segs)
+	}
+	for i, s := range segs {
+		if s.Err != nil {
+			t.Fatalf("segment %d: %v", i, s.Err)
+		}
+		info, err := os.Stat(s.WavPath)
+		if err != nil {
+			t.Fatalf("segment %d: %v", i, err)
+		}
+		if size := int64(s.Duration/time.Millisecond) * 2; info.Size() < size {
+			t.Fatalf("segment %d is %d bytes, want at least %d", i, info.Size(), size)
+		}
+		s.WavPath = ""
+		if s != want[i] {
+			t.Fatalf("segment %d = %+v, want %+v", i, s, want[i])
+		}
+	}
+
+	// A recording stopped right after a segment ended passes an empty one.
+	segs = nil
+	w = newSegmentWriter(filepath.Join(t.TempDir(), "RecordTemp_y.wav"), opts, func(s Segment) { segs = append(segs, s) })
+	for i := 0; i < 4; i++ {
+		w.write(buf, quiet)
+	}
+	w.end(true)
+	if len(segs) != 2 || segs[1].WavPath != "" || !segs[1].Last || segs[1].Start != 2*time.Second {
+		t.Fatalf("segments = %+v, want a 2s one and an empty last one", segs)
+	}
+}
+
+func TestWavWriterWritesPlayableFileWithoutAllocating(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "RecordTemp_x.wav")
+	w, err := createWav(path, 16000, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := []int16{0, 1, -1, 32767, -32768, 1234}
+	writes := 0
+	write := func() {
+		if err := w.write(samples); err != nil {
+			t.Fatal(err)
+		}
+		writes++
+	}
+	write()
+	if allocs := testing.AllocsPerRun(100, write); allocs != 0 {
+		t.Fatalf("write allocates %v times per buffer, want 0", allocs)
+	}
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dec := wav.NewDecoder(f)
+	buf, err := dec.FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("decoding failed: %v", err)
+	}
+	if dec.SampleRate != 16000 || dec.NumChans != 2 || dec.BitDepth != 16 || dec.WavAudioFormat != 1 {
+		t.Fatalf("format = %d Hz, %d channels, %d bits, format %d", dec.SampleRate, dec.NumChans, dec.BitDepth, dec.WavAudioFormat)
+	}
+	if len(buf.Data) != writes*len(samples) {
+		t.Fatalf("decoded %d samples, want %d", len(buf.Data), writes*len(samples))
+	}
+	for i, v := range buf.Data {
+		if want := int(samples[i%len(samples)]); v != want {
+			t.Fatalf("sample %d = %d, want %d", i, v, want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package record
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Segment is a part of a recording written to its own WAV file while the
+// recording continues; see Options.SegmentLength.
+type Segment struct {
+	// Index counts the segments of a recording from 0.
+	Index int
+	// WavPath is the segment's file, which belongs to the OnSegment
+	// callback. It is "" when Err is set.
+	WavPath string
+	// Start is where the segment begins in the recording, pauses left out.
+	Start    time.Duration
+	Duration time.Duration
+	// Last is set on the segment ending with the recording.
+	Last bool
+	// Err is set when the segment could not be written; no further
+	// segments of the recording follow.
+	Err error
+}
+
+const (
+	// quietRMS is the level below which a buffer is quiet enough to end a
+	// segment in, about -40 dBFS.
+	quietRMS = 0.01
+	// segmentGrace is how much longer than Options.SegmentLength a segment
+	// may run while waiting for a quiet buffer to end in.
+	segmentGrace = 5 * time.Second
+)
+
+// OnSegment registers fn to receive the segments of recordings made with
+// Options.SegmentLength set. fn runs on the recording goroutine and must not
+// block; the last segment is passed before Stop returns. Segments of
+// canceled recordings are removed instead. nil removes it.
+func (r *Recorder) OnSegment(fn func(Segment)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onSegment = fn
+}
+
+// segmentWriter writes a recording a second time, in segments of about
+// length each, ending every segment in a quiet buffer where it can.
+type segmentWriter struct {
+	base     string
+	rate     int
+	channels int
+	length   int // frames
+	grace    int // frames
+	emit     func(Segment)
+
+	index  int
+	start  int // frames before the current segment
+	frames int
+	out    *wavWriter
+	failed bool
+}
+
+// newSegmentWriter returns a writer of segments of wavPath, named after it,
+// that passes them to emit.
+func newSegmentWriter(wavPath string, opts Options, emit func(Segment)) *segmentWriter {
+	return &segmentWriter{
+		base:     strings.TrimSuffix(wavPath, ".wav"),
+		rate:     opts.SampleRate,
+		channels: opts.Channels,
+		length:   int(opts.SegmentLength * time.Duration(opts.SampleRate) / time.Second),
+		grace:    int(segmentGrace * time.Duration(opts.SampleRate) / time.Second),
+		emit:     emit,
+	}
+}
+
+// write adds samples, with the level they were measured at, to the
+// current segment, ending the segment after them once it is long enough.
+// After a failure, reported as the last segment, write does nothing.
+func (w *segmentWriter) write(samples []int16, level Level) {
+	if w.failed {
+		return
+	}
+	if w.out == nil {
+		out, err := createWav(fmt.Sprintf("%s_%d.wav", w.base, w.index), w.rate, w.channels)
+		if err != nil {
+			w.fail(fmt.Errorf("create segment failed: %w", err))
+			return
+		}
+		w.out = out
+	}
+	if err := w.out.write(samples); err != nil {
+		w.fail(fmt.Errorf("segment write failed: %w", err))
+		return
+	}
+	w.frames += len(samples) / w.channels
+	if w.frames >= w.length+w.grace || w.frames >= w.length && level.RMS < quietRMS {
+		w.end(false)
+	}
+}
+
+// end closes the current segment and passes it on. last marks the end of
+// the recording, which passes an empty last segment when the previous one
+// just ended.
+func (w *segmentWriter) end(last bool) {
+	if w.failed {
+		return
+	}
+	seg := Segment{
+		Index:    w.index,
+		Start:    w.duration(w.start),
+		Duration: w.duration(w.frames),
+		Last:     last,
+	}
+	if w.out != nil {
+		seg.WavPath = w.out.file.Name()
+		err := w.out.close()
+		w.out = nil
+		if err != nil {
+			_ = os.Remove(seg.WavPath)
+			w.fail(fmt.Errorf("segment close failed: %w", err))
+			return
+		}
+	} else if !last {
+		return
+	}
+	w.index++
+	w.start += w.frames
+	w.frames = 0
+	w.emit(seg)
+}
+
+// abort removes the current segment of a canceled recording.
+func (w *segmentWriter) abort() {
+	if w.out != nil {
+		w.out.remove()
+		w.out = nil
+	}
+}
+
+func (w *segmentWriter) fail(err error) {
+	w.abort()
+	w.failed = true
+	w.emit(Segment{Index: w.index, Start: w.duration(w.start), Last: true, Err: err})
+}
+
+func (w *segmentWriter) duration(frames int) time.Duration {
+	return time.Duration(frames) * time.Second / time.Duration(w.rate)
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+// Package source names the sources package record records from. It has no
+// cgo dependencies, so configuration can validate a source without linking
+// PortAudio.
+package source
+
+const (
+	// Microphone records from an input device.
+	Microphone = "microphone"
+	// Loopback records what the default output device plays, through the
+	// WASAPI loopback device PortAudio lists for it.
+	Loopback = "loopback"
+)
+
+// Valid reports whether value is a source record.Options.Source accepts.
+func Valid(value string) bool {
+	switch value {
+	case "", Microphone, Loopback:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package record
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+)
+
+// wavHeaderSize is the size of the header of a plain 16-bit PCM WAV file.
+const wavHeaderSize = 44
+
+// wavWriter streams 16-bit PCM samples into a WAV file. Unlike wav.Encoder,
+// which writes every sample through binary.Write, it encodes each buffer
+// into one reused byte slice, so recording allocates nothing per buffer.
+type wavWriter struct {
+	file     *os.File
+	rate     int
+	channels int
+	size     int64 // bytes of samples written
+	buf      []byte
+}
+
+// createWav creates the WAV file at path for samples at rate with channels
+// interleaved. Its sizes are filled in by close.
+func createWav(path string, rate, channels int) (*wavWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &wavWriter{file: file, rate: rate, channels: channels}
+	if _, err := file.Write(w.header()); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// header returns the WAV header for the samples written so far.
+func (w *wavWriter) header() []byte {
+	h := make([]byte, wavHeaderSize)
+	le := binary.LittleEndian
+	copy(h[0:], "RIFF")
+	le.PutUint32(h[4:], uint32(wavHeaderSize-8+w.size))
+	copy(h[8:], "WAVEfmt ")
+	le.PutUint32(h[16:], 16)
+	le.PutUint16(h[20:], 1) // PCM
+	le.PutUint16(h[22:], uint16(w.channels))
+	le.PutUint32(h[24:], uint32(w.rate))
+	le.PutUint32(h[28:], uint32(w.rate*w.channels*2))
+	le.PutUint16(h[32:], uint16(w.channels*2))
+	le.PutUint16(h[34:], 16)
+	copy(h[36:], "data")
+	le.PutUint32(h[40:], uint32(w.size))
+	return h
+}
+
+// write appends samples to the file.
+func (w *wavWriter) write(samples []int16) error {
+	if n := len(samples) * 2; cap(w.buf) < n {
+		w.buf = make([]byte, n)
+	}
+	buf := w.buf[:len(samples)*2]
+	for i, v := range samples {
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(v))
+	}
+	n, err := w.file.Write(buf)
+	w.size += int64(n)
+	return err
+}
+
+// close fills in the sizes in the header and closes the file.
+func (w *wavWriter) close() error {
+	_, err := w.file.Seek(0, io.SeekStart)
+	if err == nil {
+		_, err = w.file.Write(w.header())
+	}
+	if cerr := w.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// remove closes and removes the file of a recording that is not kept.
+func (w *wavWriter) remove() {
+	_ = w.file.Close()
+	_ = os.Remove(w.file.Name())
+}
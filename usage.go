@@ -0,0 +1,703 @@
+// Copyright (C) 2026 Joey Kot <joey.kot.x@gmail.com>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed WITHOUT ANY WARRANTY; without even the
+// implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// See <https://www.gnu.org/licenses/> for more details.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"stt/internal/config"
+	"stt/internal/i18n"
+)
+
+func usage() {
+	programName := filepath.Base(os.Args[0])
+	text := usageZH
+	if i18n.Current() == i18n.EN {
+		text = usageEN
+	}
+	fmt.Fprintf(os.Stderr, text, programName, programName, programName, programName, programName, programName, programName, programName, programName, programName, programName, programName, programName, programName, programName, programName, programName)
+}
+
+// uiLangFromArgs returns the -ui-lang value from args or STT_UI_LANG so the
+// help text can be localized before flags and the config file are parsed.
+func uiLangFromArgs(args []string) string {
+	for i, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "ui-lang" {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return os.Getenv(config.EnvPrefix + "UI_LANG")
+}
+
+const usageZH = `用法: %s [选项]
+      %s config <encrypt|decrypt> [-config <路径>]
+      %s history <list|search <关键词>|show <编号>|tui [关键词]> [-since <日期>] [-until <日期>] [-limit <数量>] [-json]
+      %s cache decrypt <文件.enc>... [-out <目录>]
+      %s cache <stats|prune|archive> [-older-than <天数>] [-max-size <MB>] [-dry-run] [-json]
+      %s queue <list|flush>
+      %s devices [-midi] [-json]
+      %s doctor [-config <路径>]
+      %s toggle
+      %s status [-json]
+      %s transcribe [文件] [-config <路径>]
+      %s ctl <start|stop|toggle|pause|resume|cancel|status [-json]|stats [-json]|transcribe <文件>>
+      %s service <install|uninstall> [-config <路径>]
+      %s autostart <enable|disable|status> [-config <路径>]
+      %s shell-integration <install|uninstall|status> [-config <路径>]
+      %s protocol <install|uninstall|status> [-config <路径>]
+      %s update [-check] [-force]
+
+该程序用于录音并将音频上传到 ASR 接口，识别结果可自动粘贴到当前光标。
+
+选项:
+[自定义配置文件]
+  -config <string>
+        指定配置文件（JSON，允许 // 与 /* */ 注释），若未提供则读取 ./config.json，当前目录没有时读取 %%APPDATA%%\stt\config.json（不存在则生成默认文件与带注释的 config.example.jsonc 模板并退出）
+  -env-file <string>
+        指定 .env 文件，未提供时读取程序所在目录下的 .env（存在时）
+  -portable
+        便携模式：配置、缓存与日志都放在当前目录（程序旁有名为 portable 的文件时同样生效）
+  -print-config
+        输出合并配置文件、环境变量与命令行参数后的最终配置（JSON）并退出；TOKEN 等凭据显示为 ***
+  -list-devices
+        列出所有录音设备的编号、名称、主机 API、最大通道数与支持的采样率后退出；* 标记系统默认设备
+  -simulate
+        模拟一次完整流程后退出：生成一段合成测试音频，按配置转码、上传，并输出转写结果（不粘贴），用于检查配置或在没有麦克风的 CI 中测试；API_ENDPOINT 为空时使用内置的模拟端点
+  -simulate-mock
+        与 -simulate 一起使用：上传到内置的模拟端点而非 API_ENDPOINT
+  -file <string>
+        指定音频文件，直接上传已有音频获得转录结果；也可以是文件夹或通配符（如 "recordings\*.m4a"），逐个转写并在最后汇总；为 - 时从标准输入读取音频，并把文字输出到标准输出。
+  -output <string>
+        -file 模式下输出文件的路径（可选，默认当前目录同名 .txt，扩展名随 -output-format）；转写多个文件时为存放 txt 的文件夹（默认各文件旁）
+  -quiet
+        不在控制台输出日志，只在标准错误输出错误（LOG_FILE 照常记录）
+  -json
+        -file 结束时向标准输出写出 JSON 结果（退出码、错误、各文件的输出与文字），不输出日志；与 -list-devices 一起使用时以 JSON 列出设备。
+        -file 的退出码：0 成功，1 其他错误，2 配置错误，3 转码失败，4 上传失败，5 批量转写中部分文件失败
+
+[API 端点配置]
+  -api-endpoint <string>
+        ASR 接口 URL (e.g. https://api.example/v1/audio/transcriptions)
+  -token <string>
+        授权 Token（Bearer）
+  -model <string>
+        模型名称
+  -language <string>
+        识别语言 (e.g. zh)；"auto" 表示自动检测，按服务商约定发送
+  -provider <string>
+        服务商约定（openai, azure, groq, siliconflow, deepinfra, whispercpp, sensevoice, bcp47），留空按端点域名识别
+  -profile <string>
+        配置名称（例如 work、home），在 -cache-name 中以 {profile} 引用；留空为 default
+  -prompt <string>
+        识别提示文本（可选）
+  -text-path <string>
+        JSON 路径，用于从 ASR 返回的 JSON 中抽取文本（点分 + 数组下标语法）
+        默认: "text"
+  -post-command <string>
+        每次转写成功后运行的命令，文本从标准输入传入，STT_* 环境变量提供元数据（默认不运行）
+  -extra-config <string>
+        解析自定义请求字段并合并到向 API 端点发送的请求中，必须填写转义字符串，否则将无法解析。配置文件中的 ExtraConfig 可直接写成 JSON 对象
+
+[ffmpeg 转码配置]
+  -codecs <string>
+        音频编码器类型。默认: OPUS
+  -container <string>
+        音频容器类型。默认: OGG
+  -channels <int>
+        音频通道数（默认 1）
+  -input-device <string>
+        录音设备的编号或名称，名称可只写一部分（不区分大小写）；留空使用系统默认麦克风。可用 devices 子命令列出设备
+  -source <string>
+        录音来源：microphone（默认）为麦克风；loopback 录制电脑正在播放的声音（默认输出设备的 WASAPI 环回）
+  -max-record-seconds <int>
+        单次录音的最长时长（秒），到达后自动停止并上传（默认 0，不限制）
+  -record-segment-seconds <int>
+        录音时每隔约这么多秒在安静处切出一段，边录边转换上传，停止后很快得到全文（默认 0，关闭）
+  -lock-action <none|pause|stop|cancel>
+        锁定工作站时如何处理正在进行的录音：不处理、暂停、停止并转写或取消（默认 pause）
+  -sampling-rate <int>
+        采样率（Hz，默认 16000 Hz）
+  -sampling-rate-depth <int>
+        采样精度（bits，默认 16；允许值：8,16,24,32）
+  -bit-rate <int>
+        目标音频比特率（kbps，默认 128 kbps）
+
+[网络请求配置]
+  -request-timeout <int>
+        请求超时秒数（默认 60）
+  -max-retry <int>
+        上传最大重试次数（默认 3）
+  -retry-base-delay <float>
+        重试基准延迟秒（默认 0.5）
+  -enable-http2 <true|false>
+        是否启用 HTTP/2（默认开启）
+  -verify-ssl <true|false>
+        是否验证 HTTPS 证书（默认开启）
+  -ca-cert-file <path>
+        额外信任的根证书文件（PEM），用于公司代理或自签名证书，无需关闭 -verify-ssl
+  -startup-check <true|false>
+        启动时探测 ASR 端点的可达性、TLS 与鉴权状态（默认关闭）
+  -update-check <true|false>
+        录音模式启动时检查是否有新版本，有则提示运行 update 子命令（默认关闭）
+  -watchdog <true|false>
+        由看护进程运行录音模式，崩溃时自动重启并记录到缓存目录下的 crash.log（默认关闭）
+  -onboarding <true|false>
+        首次启动时显示引导并进行测试录音，测试成功后不再显示（默认开启）
+  -http-api <string>
+        本地 HTTP 控制接口的监听地址，例如 127.0.0.1:8765，只允许回环地址（默认关闭）
+  -http-api-token <string>
+        HTTP 控制接口的访问令牌；为空时每次启动随机生成并写入缓存目录下的 http-api-token
+  -grpc-api <string>
+        本地 gRPC 接口的监听地址，例如 127.0.0.1:8766，只允许回环地址（默认关闭）
+  -grpc-api-token <string>
+        gRPC 接口的访问令牌；为空时每次启动随机生成并写入缓存目录下的 grpc-api-token
+
+[热键配置]
+  -start-key <string>
+        开始/停止热键（例如 "ctrl+alt+q"）
+  -pause-key <string>
+        暂停/恢复热键（例如 "ctrl+alt+s"）
+  -cancel-key <string>
+        取消录音热键（例如 "alt+esc"）
+  -hotkeyhook <true|false>
+        是否使用低级键盘钩子 (WH_KEYBOARD_LL) 来独占热键（默认开启）。
+  -ptt <true|false>
+        按住说话：只在按住开始热键时录音，松开即停止并上传；需开启 -hotkeyhook，macOS 不支持（默认关闭）。
+  -midi-input <string>
+        接收触发的 MIDI 输入设备，名称的一部分即可（不区分大小写）；可用 devices -midi 列出设备（默认关闭）
+  -midi-map <string>
+        MIDI 音符/控制器到动作的映射（默认 "36=toggle,37=pause,38=cancel"，控制器写作 cc64）
+  -schedule <string>
+        定时转写任务，以分号分隔，每项为“分 时 日 月 周 路径”，例如 "0 7 * * * D:\Calls\{yesterday}"；录音模式运行时执行（默认关闭）
+  -clipboard-watch
+        复制音频或视频文件（或其路径）后弹出通知，点击“转写”写出同名 .txt；需先运行 stt protocol install（默认关闭）
+
+[缓存配置]
+  -cache-dir <string>
+        设置缓存目录。启用后如不存在路径会尝试自动创建。
+  -keep-cache <true|false>
+        是否启用临时文件保存和转录记录回写（默认关闭）。此选项必须启用 -cache-dir 才会生效。
+
+  -keep-wav <true|false>
+        -keep-cache 开启时是否保留原始 WAV 录音（默认开启）
+
+  -keep-converted <true|false>
+        -keep-cache 开启时是否保留转码后上传的音频（默认开启）
+
+  -cache-layout <string>
+        保留的缓存文件所在子目录布局（默认 {yyyy}/{mm}/{dd}）。可用 {yyyy} {yy} {mm} {dd} {hh} {ww}，留空则平铺在缓存目录
+
+  -machine-id <string>
+        本机标识，可在 -cache-name 中以 {machine} 引用，并记录到 history.db（默认使用计算机名）；多台电脑同步同一缓存目录时用于区分文件
+
+  -cache-name <string>
+        保留的缓存文件命名模板（默认 audio-{date}-{time}）。可用 {date} {time} {profile} {lang} {model} {provider} {source} {machine} {seq} 及日期占位符；同一秒内重名时 {seq} 递增，模板不含 {seq} 则追加 -2、-3
+
+  -cache-max-age-days <int>
+        自动删除超过指定天数的缓存文件（默认 0，永久保留）
+
+  -cache-max-size-mb <int>
+        缓存总大小上限（MB），超出时从最旧的文件开始删除（默认 0，不限制）
+
+  -cache-purge-interval <int>
+        自动清理缓存的间隔分钟数（默认 60）；启动时也会执行一次
+
+  -cache-archive-days <int>
+        将超过指定天数的缓存按月压缩到缓存目录下的 archive/YYYY-MM.zip（默认 0，不归档）；history.db 中的路径随之更新
+
+  -history <true|false>
+        是否将每次转写记录写入缓存目录下的 history.db（默认开启）。此选项必须启用 -cache-dir 才会生效。
+
+  -retry-queue <true|false>
+        录音上传重试耗尽时，将音频移入缓存目录的 pending/ 队列并在后台重试（默认开启）。此选项必须启用 -cache-dir 才会生效。可用 queue list 查看、queue flush 手动重试
+
+  -queue-retry-interval <int>
+        后台重试 pending/ 队列的间隔秒数（默认 60）
+
+  -offline-first <true|false>
+        离线优先：每段录音先写入 pending/ 队列再转码上传，成功后才移出队列，进程崩溃或断网也不会丢失录音（默认关闭）。此选项必须启用 -cache-dir 才会生效
+
+  -cache-encryption <dpapi|passphrase>
+        加密保留的录音、转码文件、响应 JSON 以及 history.db 中的文本（默认不加密）。dpapi 绑定当前 Windows 用户，passphrase 使用 -cache-passphrase 派生密钥；密钥保存在缓存目录的 cache.key。加密文件可用 cache decrypt 还原
+
+  -cache-passphrase <string>
+        -cache-encryption passphrase 时使用的口令，建议改用环境变量 STT_CACHE_PASSPHRASE
+
+[系统通知配置]
+  -notification <true|false>
+        是否启用 Windows 通知（默认开启）
+  -request-failed-notification <true|false>
+        仅录音模式下：上传重试耗尽后，粘贴占位符 [request failed]（默认关闭）
+  -progress-notification <true|false>
+        转换和上传超过几秒时显示一条原地更新的进度通知（正在转换 40%%…、正在上传 70%%…），需开启 -notification（默认开启）
+  -timing-notification <true|false>
+        在「粘贴成功」通知中附上录音收尾、转换、上传和粘贴各自的耗时，需开启 -notification（默认关闭）；耗时总会写入日志和历史记录
+  -quiet-mode <true|false>
+        全屏应用、演示模式或 Windows 免打扰时段期间不弹出通知、不播放提示音，识别结果照常粘贴（默认开启）
+  -log-file <path>
+        控制台输出同时写入的日志文件，相对路径以缓存目录为基准；失败通知可点击打开错误详情；留空不写日志（默认 stt.log）
+  -log-max-size-mb <int>
+        日志超过此大小（MB）时轮转为 stt.log.1；0 表示不按大小轮转（默认 5）
+  -log-rotate-days <int>
+        每隔多少天开始新日志，1 为每天；0 表示不按时间轮转（默认 0）
+  -log-max-backups <int>
+        保留的旧日志数量（stt.log.1、stt.log.2……）；0 表示轮转时删除（默认 1）
+  -tray <true|false>
+        录音模式下在任务栏通知区域显示状态图标，右键菜单可开始/停止、暂停、取消录音、打开缓存目录、重新加载配置和退出（默认开启）
+  -tray-theme <auto|light|dark>
+        托盘图标配色：auto 跟随 Windows 任务栏的浅色/深色主题，也可固定为 light 或 dark（默认 auto）
+  -vu-meter <true|false>
+        录音时在屏幕右下角显示实时电平与波形小窗口，不抢占焦点，可拖动（默认关闭）
+  -recording-timer <true|false>
+        录音时显示一条每秒更新已录制时长的常驻通知，接近 MAX_RECORD_SECONDS 时提醒（默认关闭）
+  -sound-mute <true|false>
+        关闭全部提示音（默认关闭）
+  -sound-start <string>
+        开始录音时播放的提示音：WAV 文件路径或 Windows 系统声音名（如 SystemAsterisk），留空不播放
+  -sound-stop <string>
+        停止录音时播放的提示音
+  -sound-paste-success <string>
+        识别文本粘贴成功后播放的提示音
+  -sound-upload-failed <string>
+        上传失败时播放的提示音；留空时使用 -sound-error
+  -sound-error <string>
+        其他失败（粘贴失败、转换失败、端点检查失败）时播放的提示音
+
+[ffmpeg 路径]
+  -ffmpeg-path <string>
+        ffmpeg 可执行文件路径。未设置时依次查找 FFMPEG_PATH 环境变量、PATH、程序所在目录和常见安装位置
+  -ffmpeg-timeout <int>
+        ffmpeg 转码的最长秒数，超时会结束 ffmpeg 及其子进程并报错；0 表示不限制（默认 1800）
+  -ffmpeg-input-args <string>
+        放在 -i 之前的额外 ffmpeg 参数，以空格分隔，含空格的参数用引号括起
+  -ffmpeg-extra-args <string>
+        放在输出文件之前的额外 ffmpeg 参数（如 -af "highpass=f=200"），可覆盖 STT 设置的选项；设置后总是调用 ffmpeg 转码
+  -ffmpeg-threads <int>
+        ffmpeg 编码使用的线程数；0 表示由 ffmpeg 决定（默认 0）
+  -compression-level <int>
+        Opus（0-10）或 FLAC（0-12）的压缩等级，越低越快；-1 使用编码器默认值（默认 -1）
+  -opus-vbr <on|off|constrained>
+        Opus 的码率模式；留空使用编码器默认值
+  -highpass <int>
+        转码时滤除低于该频率（Hz）的声音，如 100；0 表示关闭（默认 0）
+  -lowpass <int>
+        转码时滤除高于该频率（Hz）的声音，如 8000；0 表示关闭（默认 0）
+  -denoise
+        转码时用 afftdn 滤镜降低持续的背景噪声（默认关闭）
+  -speed <float>
+        上传前把音频加速到该倍数（1–2，音调不变），按时长计费时可省钱省时；1 表示关闭（默认 1）
+  -chunk-seconds <int>
+        转写文件时把长于该秒数的文件切成多段分别上传再合并；0 表示不切分（默认 0）
+  -chunk-overlap <int>
+        相邻分段重叠的秒数，合并时去掉重复文字（默认 5）
+  -max-upload-mb <int>
+        转码后超过该大小（MB）的文件也切分上传；0 表示不限制（默认 0）
+  -audio-track <int>
+        转写文件时使用的音轨，从 1 开始计数，如多语言视频的第二条音轨；0 表示由 ffmpeg 选择（默认 0）
+  -convert-workers <int>
+        批量转写文件夹时同时转码的文件数（默认 2）
+  -output-format <string>
+        -file 写出的格式：txt、json（服务的完整响应）、srt、vtt 或 tsv（带时间戳的字幕）（默认 txt）
+  -output-template <string>
+        批量转写时每份转写的路径模板，可用 {dir}、{name}、{ext}、{lang}，如 "{dir}\out\{name}.{lang}.{ext}"；留空则写在各文件旁
+  -progress-bar <true|false>
+        批量转写时在终端显示进度条；关闭或输出不是终端时每个文件只输出一行（默认开启）
+  -skip-existing <true|false>
+        批量转写时跳过已有输出文件或历史记录中已成功转写的文件，中断后重新运行即可继续（默认关闭）
+  -recursive <true|false>
+        -file 为文件夹或通配符时也转写子文件夹中的文件（默认关闭）
+  -include-ext <string>
+        批量转写时只转写这些扩展名的文件，逗号分隔，如 ".m4a,.wav"（默认：文件夹取所有音视频文件，通配符取所有匹配的文件）
+  -exclude-ext <string>
+        批量转写时跳过这些扩展名的文件，逗号分隔
+  -min-seconds <int>
+        批量转写时跳过短于该秒数的文件，需要 ffmpeg；0 表示不限制（默认 0）
+  -max-seconds <int>
+        批量转写时跳过长于该秒数的文件，需要 ffmpeg；0 表示不限制（默认 0）
+  -max-concurrent-uploads <int>
+        同时进行的上传数上限，超出的排队；批量转写和分段转写最多同时上传这么多个；0 表示不限制，批量和分段逐个上传（默认 0）
+  -requests-per-minute <int>
+        每分钟最多发出的上传请求数（含重试），请求之间至少间隔 60/该值 秒，避免触发服务的速率限制；0 表示不限制（默认 0）
+  -requests-burst <int>
+        -requests-per-minute 限制下，空闲一段时间后可立即连续发出的请求数；1 表示不突发（默认 1）
+
+[DEBUG 配置]
+  -ffmpeg-debug <true|false>
+        是否启用 FFmpeg 详情（默认关闭）。
+  -record-debug <true|false>
+        是否启用录音子系统的调试输出（默认关闭）。
+  -hotkey-debug <true|false>
+        是否启用热键/消息循环的调试输出（默认开启）。
+  -upload-debug <true|false>
+        是否启用上传过程的调试输出（默认关闭）。
+
+[界面语言]
+  -ui-lang <zh|en>
+        CLI 帮助、日志与通知的语言。未设置时依次读取 STT_UI_LANG、配置文件 UI_LANG 和系统语言
+
+  -h, -help, -?
+        显示帮助信息
+
+说明:
+- 配置优先级：命令行标志 > 环境变量 (STT_<配置键>，例如 STT_TOKEN) > 配置文件 > 默认值
+- .env 中的变量只在进程环境中不存在同名变量时才会生效
+- sampling-rate 单位为 Hz； bit-rate 单位为 kbps； sampling-rate-depth 单位为 bits
+- TEXT_PATH 使用点分法并支持方括号索引（例如 data.items[0].value）
+- 程序启动时会清理缓存目录（未设置时为数据目录）下所有以 RecordTemp_ 开头的临时文件
+- 配置文件中的相对路径（如 CACHE_DIR）相对于配置文件所在目录解析；命令行参数中的相对路径仍相对于当前工作目录
+- config encrypt 使用 Windows DPAPI 加密配置文件中的 TOKEN、API_ENDPOINT 与 CACHE_PASSPHRASE，仅当前 Windows 用户可解密，读取时自动解密；config decrypt 还原为明文
+- ctl 向正在运行且带托盘图标的录音模式实例发送控制命令：start/stop 仅在空闲/录音时生效，pause/resume 分别暂停、继续，status -json 输出 JSON 状态，stats 输出自启动以来的录音、转写、上传、重试次数与上传耗时（-json 输出 JSON）；请求被拒绝或没有运行的实例时退出码为 1
+- service install 以管理员身份把 STT 安装为开机自动启动的 Windows 服务：服务负责重试队列与缓存清理，并在每个登录会话中启动录音模式代理（热键、录音、粘贴），代理崩溃后自动重启；service uninstall 移除服务
+- autostart enable 在“启动”文件夹中创建快捷方式，登录时以 -config 指定的配置（绝对路径）最小化启动录音模式；autostart disable 删除该快捷方式
+- transcribe 指定文件时，若有带托盘图标的录音模式实例在运行，则交给该实例按其配置转写，否则按 -config 以文件模式转写；结果写入同目录下的同名 .txt
+- shell-integration install 为当前用户的音频和视频文件添加资源管理器右键菜单“使用 STT 转写”，运行 transcribe；shell-integration uninstall 移除该菜单
+- protocol install 为当前用户注册 stt:// 链接：stt://start、stop、toggle、pause、resume、cancel 与 ctl 相同，stt://transcribe?path=<文件> 与 transcribe 相同；protocol uninstall 取消注册
+- update 下载 GitHub 上最新发布的 stt.exe，校验 SHA-256 后替换当前程序，配置文件保持不变；-check 只检查是否有更新
+
+`
+
+const usageEN = `Usage: %s [options]
+       %s config <encrypt|decrypt> [-config <path>]
+       %s history <list|search <query>|show <id>|tui [query]> [-since <date>] [-until <date>] [-limit <n>] [-json]
+       %s cache decrypt <file.enc>... [-out <dir>]
+       %s cache <stats|prune|archive> [-older-than <days>] [-max-size <MB>] [-dry-run] [-json]
+       %s queue <list|flush>
+       %s devices [-midi] [-json]
+       %s doctor [-config <path>]
+       %s toggle
+       %s status [-json]
+       %s transcribe [file] [-config <path>]
+       %s ctl <start|stop|toggle|pause|resume|cancel|status [-json]|stats [-json]|transcribe <file>>
+       %s service <install|uninstall> [-config <path>]
+       %s autostart <enable|disable|status> [-config <path>]
+       %s shell-integration <install|uninstall|status> [-config <path>]
+       %s protocol <install|uninstall|status> [-config <path>]
+       %s update [-check] [-force]
+
+Records audio and uploads it to an ASR endpoint; the transcription can be pasted at the current cursor.
+
+Options:
+[Config file]
+  -config <string>
+        Config file (JSON, // and /* */ comments allowed). Defaults to ./config.json, or %%APPDATA%%\stt\config.json when the working directory has none; if missing, a default file and an annotated config.example.jsonc template are written and the program exits
+  -env-file <string>
+        .env file to load. Defaults to .env next to the executable (when present)
+  -portable
+        Portable mode: keep the config, cache and log in the working directory (also on when a file named portable sits next to the executable)
+  -print-config
+        Print the effective config (JSON) after the config file, environment and flags are merged, then exit; TOKEN and other credentials show as ***
+  -list-devices
+        List every capture device with its index, name, host API, most channels and supported sample rates, then exit; * marks the system default
+  -simulate
+        Run one simulated recording through the whole pipeline and exit: a synthetic test clip is converted and uploaded as configured and the transcript printed (not pasted), to check the config or test in CI without a microphone; uses a built-in mock endpoint when API_ENDPOINT is empty
+  -simulate-mock
+        With -simulate, upload to the built-in mock endpoint instead of API_ENDPOINT
+  -file <string>
+        Upload an existing audio file and get its transcription; a folder or glob pattern (e.g. "recordings\*.m4a") transcribes each file and ends with a summary; - reads the audio from stdin and prints the transcript to stdout.
+  -output <string>
+        Output path in -file mode (optional, defaults to <name>.txt in the current directory, or the -output-format extension); with several files, the folder for the transcripts (defaults to next to each file)
+  -quiet
+        Print no log on the console, only errors on stderr (LOG_FILE still gets everything)
+  -json
+        Print the outcome of -file as JSON on stdout (exit code, error, and each file's output and text) instead of the log; with -list-devices, list the devices as JSON.
+        Exit codes of -file: 0 ok, 1 other error, 2 config error, 3 conversion failed, 4 upload failed, 5 some files of a batch failed
+
+[API endpoint]
+  -api-endpoint <string>
+        ASR endpoint URL (e.g. https://api.example/v1/audio/transcriptions)
+  -token <string>
+        Authorization token (Bearer)
+  -model <string>
+        Model name
+  -language <string>
+        Recognition language (e.g. en); "auto" requests auto-detection, sent per provider convention
+  -provider <string>
+        Provider convention (openai, azure, groq, siliconflow, deepinfra, whispercpp, sensevoice, bcp47); empty detects it from the endpoint host
+  -profile <string>
+        Name of this configuration (e.g. work, home), available as {profile} in -cache-name; empty means default
+  -prompt <string>
+        Recognition prompt (optional)
+  -text-path <string>
+        JSON path used to extract text from the ASR response (dot notation + array indexes)
+        Default: "text"
+  -post-command <string>
+        Command run after each transcription with the text on stdin and STT_* metadata in the environment (default none)
+  -extra-config <string>
+        Extra request fields merged into the request sent to the endpoint; must be an escaped JSON string. In the config file ExtraConfig may be a native JSON object
+
+[ffmpeg encoding]
+  -codecs <string>
+        Audio codec. Default: OPUS
+  -container <string>
+        Audio container. Default: OGG
+  -channels <int>
+        Channel count (default 1)
+  -input-device <string>
+        Microphone to record from, by index, name or part of the name (case-insensitive); empty uses the system default. The devices subcommand lists them
+  -source <string>
+        What to record: microphone (default), or loopback for what the PC plays (WASAPI loopback of the default output device)
+  -max-record-seconds <int>
+        Stop and upload a recording automatically after this many seconds (default 0, no limit)
+  -record-segment-seconds <int>
+        Cut a recording into segments of about this many seconds, ending each in a quiet moment, and convert and upload them while recording continues, so the transcript is ready soon after stopping (default 0, off)
+  -lock-action <none|pause|stop|cancel>
+        What to do with an active recording when the workstation locks: nothing, pause, stop and transcribe, or cancel (default pause)
+  -sampling-rate <int>
+        Sample rate (Hz, default 16000 Hz)
+  -sampling-rate-depth <int>
+        Sample depth (bits, default 16; allowed: 8,16,24,32)
+  -bit-rate <int>
+        Target bit rate (kbps, default 128 kbps)
+
+[Network]
+  -request-timeout <int>
+        Request timeout in seconds (default 60)
+  -max-retry <int>
+        Maximum upload attempts (default 3)
+  -retry-base-delay <float>
+        Base retry delay in seconds (default 0.5)
+  -enable-http2 <true|false>
+        Enable HTTP/2 (default on)
+  -verify-ssl <true|false>
+        Verify HTTPS certificates (default on)
+  -ca-cert-file <path>
+        PEM file of extra root certificates to trust, for corporate proxies or self-signed endpoints, instead of turning off -verify-ssl
+  -startup-check <true|false>
+        Probe the ASR endpoint for reachability, TLS, and auth at startup (default off)
+  -update-check <true|false>
+        Check for a newer release when record mode starts and suggest the update subcommand (default off)
+  -watchdog <true|false>
+        Run record mode under a watchdog that restarts it after a crash and logs the crash to crash.log in the cache dir (default off)
+  -onboarding <true|false>
+        Show the first-run guide and test recording until one succeeds (default on)
+  -http-api <string>
+        Address of the local HTTP control API, e.g. 127.0.0.1:8765; loopback only (default off)
+  -http-api-token <string>
+        Token for the HTTP control API; when empty, a new one is written to http-api-token in the cache directory on each start
+  -grpc-api <string>
+        Address of the local gRPC API, e.g. 127.0.0.1:8766; loopback only (default off)
+  -grpc-api-token <string>
+        Token for the gRPC API; when empty, a new one is written to grpc-api-token in the cache directory on each start
+
+[Hotkeys]
+  -start-key <string>
+        Start/stop hotkey (e.g. "ctrl+alt+q")
+  -pause-key <string>
+        Pause/resume hotkey (e.g. "ctrl+alt+s")
+  -cancel-key <string>
+        Cancel hotkey (e.g. "alt+esc")
+  -hotkeyhook <true|false>
+        Use a low-level keyboard hook (WH_KEYBOARD_LL) to claim hotkeys exclusively (default on).
+  -ptt <true|false>
+        Push-to-talk: record only while the start hotkey is held down and stop and upload when it is released; needs -hotkeyhook, not supported on macOS (default off).
+  -midi-input <string>
+        MIDI input device to take triggers from, by part of its name (case-insensitive); devices -midi lists them (default off)
+  -midi-map <string>
+        MIDI notes/controllers mapped to actions (default "36=toggle,37=pause,38=cancel"; controllers are written cc64)
+  -schedule <string>
+        Scheduled transcription jobs separated by ';', each "minute hour day month weekday path", e.g. "0 7 * * * D:\Calls\{yesterday}"; run while record mode is running (default off)
+  -clipboard-watch
+        Offer to transcribe audio/video files (or their paths) copied to the clipboard into a .txt next to them; needs stt protocol install (default off)
+
+[Cache]
+  -cache-dir <string>
+        Cache directory. Created automatically when missing.
+  -keep-cache <true|false>
+        Keep temporary files and write back transcription records (default off). Requires -cache-dir.
+
+  -keep-wav <true|false>
+        Keep the recorded WAV when -keep-cache is on (default on)
+
+  -keep-converted <true|false>
+        Keep the converted upload file when -keep-cache is on (default on)
+
+  -cache-layout <string>
+        Subdirectory layout for kept cache files (default {yyyy}/{mm}/{dd}). Placeholders: {yyyy} {yy} {mm} {dd} {hh} {ww}; empty keeps files flat in the cache dir
+
+  -machine-id <string>
+        Identifier of this computer, available as {machine} in -cache-name and recorded in history.db (default: host name); keeps files apart when several computers sync one cache dir
+
+  -cache-name <string>
+        File name template for kept cache files (default audio-{date}-{time}). Placeholders: {date} {time} {profile} {lang} {model} {provider} {source} {machine} {seq} plus the date placeholders; {seq} counts up when names collide, and templates without it get -2, -3 appended
+
+  -cache-max-age-days <int>
+        Delete kept cache files older than this many days (default 0, keep forever)
+
+  -cache-max-size-mb <int>
+        Delete the oldest kept cache files once the cache exceeds this many MB (default 0, no limit)
+
+  -cache-purge-interval <int>
+        Minutes between automatic cache purges (default 60); a purge also runs at startup
+
+  -cache-archive-days <int>
+        Move kept cache files older than this many days into monthly zips under archive/ in the cache dir (default 0, no archiving); history.db paths follow them
+
+  -history <true|false>
+        Record every transcription in history.db under the cache dir (default on). Requires -cache-dir.
+
+  -retry-queue <true|false>
+        When a recording's upload fails after all retries, move the audio to pending/ in the cache dir and retry it in the background (default on). Requires -cache-dir. Use queue list to inspect and queue flush to retry now
+
+  -queue-retry-interval <int>
+        Seconds between background retries of the pending/ queue (default 60)
+
+  -offline-first <true|false>
+        Write every recording to the pending/ queue before converting and uploading it, and only remove it once the upload succeeds, so a crash or outage never loses a recording (default off). Requires -cache-dir
+
+  -cache-encryption <dpapi|passphrase>
+        Encrypt kept recordings, encoded audio, response JSON, and transcript text in history.db (default off). dpapi binds the key to the current Windows user; passphrase derives it from -cache-passphrase. The key is stored as cache.key in the cache dir. Use cache decrypt to restore files
+
+  -cache-passphrase <string>
+        Passphrase for -cache-encryption passphrase; prefer the STT_CACHE_PASSPHRASE environment variable
+
+[Notifications]
+  -notification <true|false>
+        Enable Windows notifications (default on)
+  -request-failed-notification <true|false>
+        Record mode only: paste the placeholder [request failed] after upload retries are exhausted (default off)
+  -progress-notification <true|false>
+        When converting and uploading takes more than a few seconds, show one progress notification updated in place (converting 40%%…, uploading 70%%…); needs -notification (default on)
+  -timing-notification <true|false>
+        Add how long finishing the recording, converting, uploading and pasting took to the paste notification; needs -notification (default off). The times are always logged and kept in history
+  -quiet-mode <true|false>
+        Stay silent (no notifications or sounds) while a full-screen app, a presentation or Windows quiet hours are active; transcripts are still pasted (default on)
+  -log-file <path>
+        Also write console output to this log file, relative to the cache directory; failure notifications open the error details when clicked; empty disables (default stt.log)
+  -log-max-size-mb <int>
+        Rotate the log to stt.log.1 once it grows past this many MB; 0 = no size limit (default 5)
+  -log-rotate-days <int>
+        Start a new log every this many days, 1 = daily; 0 = no time-based rotation (default 0)
+  -log-max-backups <int>
+        Rotated logs kept (stt.log.1, stt.log.2, ...); 0 deletes them on rotation (default 1)
+  -tray <true|false>
+        Record mode: show a status icon in the notification area whose menu starts/stops, pauses and cancels recording, opens the cache folder, reloads the config and quits (default on)
+  -tray-theme <auto|light|dark>
+        Tray icon palette: auto follows the light or dark Windows taskbar theme; light or dark fixes it (default auto)
+  -vu-meter <true|false>
+        While recording, show a small live level meter and waveform window in the bottom-right corner; it never takes the focus and can be dragged (default off)
+  -recording-timer <true|false>
+        While recording, show a notification with the elapsed time, updated every second, and warn when MAX_RECORD_SECONDS is close (default off)
+  -sound-mute <true|false>
+        Silence all sound cues (default off)
+  -sound-start <string>
+        Sound played when recording starts: a WAV file path or a Windows system sound name such as SystemAsterisk; empty plays nothing
+  -sound-stop <string>
+        Sound played when recording stops
+  -sound-paste-success <string>
+        Sound played after the transcript is pasted
+  -sound-upload-failed <string>
+        Sound played when an upload fails; -sound-error when empty
+  -sound-error <string>
+        Sound played for other failures (paste, conversion, endpoint check)
+
+[ffmpeg path]
+  -ffmpeg-path <string>
+        ffmpeg executable. When unset, the FFMPEG_PATH environment variable, PATH, the executable's directory, and common install locations are searched
+  -ffmpeg-timeout <int>
+        Seconds a conversion may take before ffmpeg and its child processes are killed; 0 = no limit (default 1800)
+  -ffmpeg-input-args <string>
+        Extra ffmpeg arguments placed before -i, separated by spaces; quote arguments that contain spaces
+  -ffmpeg-extra-args <string>
+        Extra ffmpeg arguments placed before the output file (e.g. -af "highpass=f=200"), overriding STT's options; conversions then always run ffmpeg
+  -ffmpeg-threads <int>
+        Threads of ffmpeg's encoder; 0 lets ffmpeg choose (default 0)
+  -compression-level <int>
+        Opus (0-10) or FLAC (0-12) compression level, lower is faster; -1 keeps the encoder default (default -1)
+  -opus-vbr <on|off|constrained>
+        Opus bitrate mode; empty keeps the encoder default
+  -highpass <int>
+        Cut audio below this frequency in Hz during conversion, e.g. 100; 0 = off (default 0)
+  -lowpass <int>
+        Cut audio above this frequency in Hz during conversion, e.g. 8000; 0 = off (default 0)
+  -denoise
+        Reduce steady background noise with the afftdn filter during conversion (default off)
+  -speed <float>
+        Speed the audio up by this factor (1-2, same pitch) before upload, saving cost and time with duration-billed services; 1 = off (default 1)
+  -chunk-seconds <int>
+        Transcribe files longer than this many seconds in chunks and merge the transcripts; 0 = off (default 0)
+  -chunk-overlap <int>
+        Seconds each chunk overlaps the next; the repeated words are dropped when merging (default 5)
+  -max-upload-mb <int>
+        Also chunk files larger than this many MB once converted; 0 = no limit (default 0)
+  -audio-track <int>
+        Audio track of a file to transcribe, counting from 1, e.g. a video's second language; 0 = ffmpeg's pick (default 0)
+  -convert-workers <int>
+        Files converted in parallel when transcribing a folder (default 2)
+  -output-format <string>
+        Format -file writes: txt, json (the service's full response), srt, vtt or tsv (timed subtitles) (default txt)
+  -output-template <string>
+        Path of each transcript when -file transcribes several files, with {dir}, {name}, {ext} and {lang}, e.g. "{dir}\out\{name}.{lang}.{ext}"; empty writes next to each file
+  -progress-bar <true|false>
+        Draw a progress bar on a terminal when transcribing several files; off, or when the output is not a terminal, prints one line per file (default on)
+  -skip-existing <true|false>
+        Skip files of a batch that already have an output file or are transcribed in history, so an interrupted run can be restarted (default off)
+  -recursive <true|false>
+        Also transcribe the files in subfolders when -file is a folder or pattern (default off)
+  -include-ext <string>
+        Only transcribe files of a batch with these extensions, separated by commas, e.g. ".m4a,.wav" (default: a folder's audio and video files, or anything a pattern matches)
+  -exclude-ext <string>
+        Leave files of a batch with these extensions out, separated by commas
+  -min-seconds <int>
+        Leave files shorter than this many seconds out of a batch; needs ffmpeg; 0 = no limit (default 0)
+  -max-seconds <int>
+        Leave files longer than this many seconds out of a batch; needs ffmpeg; 0 = no limit (default 0)
+  -max-concurrent-uploads <int>
+        Most uploads at once, the others wait; batch runs and chunked files upload up to this many in parallel; 0 = no limit, with batches and chunks uploaded one at a time (default 0)
+  -requests-per-minute <int>
+        Most upload requests started per minute, retries included; requests start at least 60/N seconds apart to stay under the service's rate limit; 0 = no limit (default 0)
+  -requests-burst <int>
+        Requests that may start at once after an idle spell under -requests-per-minute; 1 = no burst (default 1)
+
+[Debug]
+  -ffmpeg-debug <true|false>
+        Enable FFmpeg details (default off).
+  -record-debug <true|false>
+        Enable recording subsystem debug output (default off).
+  -hotkey-debug <true|false>
+        Enable hotkey/message loop debug output (default on).
+  -upload-debug <true|false>
+        Enable upload debug output (default off).
+
+[UI language]
+  -ui-lang <zh|en>
+        Language of CLI help, logs, and notifications. When unset, STT_UI_LANG, the config file's UI_LANG, and the system language are used in that order
+
+  -h, -help, -?
+        Show help
+
+Notes:
+- Precedence: command-line flags > environment (STT_<config key>, e.g. STT_TOKEN) > config file > defaults
+- Variables in .env only apply when the process environment does not already define them
+- sampling-rate is in Hz; bit-rate is in kbps; sampling-rate-depth is in bits
+- TEXT_PATH uses dot notation with bracket indexes (e.g. data.items[0].value)
+- Temporary files starting with RecordTemp_ in the cache dir (or, without one, the data directory) are removed at startup
+- Relative paths in the config file (e.g. CACHE_DIR) resolve against the config file's directory; relative paths in flags resolve against the working directory
+- config encrypt protects TOKEN, API_ENDPOINT and CACHE_PASSPHRASE in the config file with Windows DPAPI so only the current Windows user can decrypt them; they are decrypted transparently on load. config decrypt restores plain text
+- ctl sends control commands to the running record-mode instance with a tray icon: start and stop only act when idle or recording, pause and resume only pause or resume, status -json prints the status as JSON, stats prints the recordings, transcriptions, uploads, retries and upload latency since it started (-json prints JSON); the exit code is 1 when the request is refused or no instance is running
+- service install (elevated) installs STT as an automatically started Windows service that owns the retry queue and cache retention and starts the record-mode agent (hotkeys, recording, pasting) in every logged-on session, restarting it when it crashes; service uninstall removes it
+- autostart enable creates a Startup folder shortcut that starts record mode minimized with the -config file (as an absolute path) when you log on; autostart disable removes it
+- transcribe with a file hands it to the running record-mode instance with a tray icon, which uses its own config; without one the file is transcribed in file mode with -config. The transcript goes to a .txt of the same name next to the file
+- shell-integration install adds "Transcribe with STT" to the Explorer context menu of audio and video files for the current user, running transcribe; shell-integration uninstall removes it
+- protocol install registers stt:// links for the current user: stt://start, stop, toggle, pause, resume and cancel work like ctl, stt://transcribe?path=<file> like transcribe; protocol uninstall removes them
+- update downloads the latest stt.exe release from GitHub, checks its SHA-256 and replaces this program, leaving the config alone; -check only reports whether an update is available
+
+`